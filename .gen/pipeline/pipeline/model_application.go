@@ -0,0 +1,29 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type Application struct {
+
+	Name string `json:"name"`
+
+	Namespace string `json:"namespace,omitempty"`
+
+	Values map[string]interface{} `json:"values,omitempty"`
+
+	Releases []ApplicationRelease `json:"releases"`
+
+	Status string `json:"status"`
+
+	StatusMessage string `json:"statusMessage,omitempty"`
+
+	// state of the releases after the latest reconciliation
+	ReleaseStatuses []ApplicationReleaseStatus `json:"releaseStatuses"`
+}
@@ -0,0 +1,29 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type ApplicationRelease struct {
+
+	ReleaseName string `json:"releaseName"`
+
+	Chart string `json:"chart"`
+
+	Version string `json:"version,omitempty"`
+
+	// defaults to the namespace of the application
+	Namespace string `json:"namespace,omitempty"`
+
+	// string values are evaluated as templates, see the values of the application
+	Values map[string]interface{} `json:"values,omitempty"`
+
+	// releases that have to be ready before this release is installed
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
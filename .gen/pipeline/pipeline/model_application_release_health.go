@@ -0,0 +1,21 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type ApplicationReleaseHealth struct {
+
+	ReleaseName string `json:"releaseName"`
+
+	// Helm status of the release, or NOT_INSTALLED
+	Status string `json:"status"`
+
+	Revision int32 `json:"revision,omitempty"`
+}
@@ -0,0 +1,47 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type AuditEvent struct {
+
+	Id int32 `json:"id,omitempty"`
+
+	Time time.Time `json:"time,omitempty"`
+
+	CorrelationId string `json:"correlationId,omitempty"`
+
+	ClientIp string `json:"clientIp,omitempty"`
+
+	UserAgent string `json:"userAgent,omitempty"`
+
+	Path string `json:"path,omitempty"`
+
+	Method string `json:"method,omitempty"`
+
+	UserId int32 `json:"userId,omitempty"`
+
+	StatusCode int32 `json:"statusCode,omitempty"`
+
+	Body map[string]interface{} `json:"body,omitempty"`
+
+	Headers map[string][]string `json:"headers,omitempty"`
+
+	// Response time in milliseconds
+	ResponseTime int32 `json:"responseTime,omitempty"`
+
+	ResponseSize int32 `json:"responseSize,omitempty"`
+
+	Errors []map[string]interface{} `json:"errors,omitempty"`
+}
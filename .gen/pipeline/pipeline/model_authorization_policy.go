@@ -0,0 +1,28 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type AuthorizationPolicy struct {
+
+	Id int32 `json:"id,omitempty"`
+
+	Subject AuthorizationPolicySubject `json:"subject,omitempty"`
+
+	Resources []string `json:"resources,omitempty"`
+
+	Verbs []string `json:"verbs,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
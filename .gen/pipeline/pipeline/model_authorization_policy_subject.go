@@ -0,0 +1,19 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+// AuthorizationPolicySubject - Exactly one of userId and tokenId must be set
+type AuthorizationPolicySubject struct {
+
+	UserId int32 `json:"userId,omitempty"`
+
+	TokenId string `json:"tokenId,omitempty"`
+}
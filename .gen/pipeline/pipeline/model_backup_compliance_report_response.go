@@ -0,0 +1,24 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type BackupComplianceReportResponse struct {
+
+	GeneratedAt time.Time `json:"generatedAt,omitempty"`
+
+	CheckedClusters int32 `json:"checkedClusters,omitempty"`
+
+	NonCompliantClusters []BackupComplianceViolation `json:"nonCompliantClusters,omitempty"`
+}
@@ -0,0 +1,30 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type BackupComplianceViolation struct {
+
+	PolicyId int32 `json:"policyId,omitempty"`
+
+	PolicyName string `json:"policyName,omitempty"`
+
+	ClusterId int32 `json:"clusterId,omitempty"`
+
+	ClusterName string `json:"clusterName,omitempty"`
+
+	LastSuccessfulBackup time.Time `json:"lastSuccessfulBackup,omitempty"`
+
+	Reasons []string `json:"reasons,omitempty"`
+}
@@ -0,0 +1,21 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type BackupMigrationOptions struct {
+
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+
+	NamespaceMapping map[string]string `json:"namespaceMapping,omitempty"`
+
+	// Restore volumes from snapshots, defaults to false across clouds or locations
+	RestorePVs bool `json:"restorePVs,omitempty"`
+}
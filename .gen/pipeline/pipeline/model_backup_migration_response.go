@@ -0,0 +1,44 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type BackupMigrationResponse struct {
+
+	Id int32 `json:"id,omitempty"`
+
+	SourceClusterId int32 `json:"sourceClusterId,omitempty"`
+
+	TargetClusterId int32 `json:"targetClusterId,omitempty"`
+
+	BucketId int32 `json:"bucketId,omitempty"`
+
+	BackupName string `json:"backupName,omitempty"`
+
+	RestoreName string `json:"restoreName,omitempty"`
+
+	Options BackupMigrationOptions `json:"options,omitempty"`
+
+	Status string `json:"status,omitempty"`
+
+	Step string `json:"step,omitempty"`
+
+	StatusMessage string `json:"statusMessage,omitempty"`
+
+	Results BackupMigrationResults `json:"results,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+
+	UpdatedAt time.Time `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,20 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type BackupMigrationResults struct {
+
+	Errors int32 `json:"errors,omitempty"`
+
+	Warnings int32 `json:"warnings,omitempty"`
+
+	Resources []BackupMigrationResourceResult `json:"resources,omitempty"`
+}
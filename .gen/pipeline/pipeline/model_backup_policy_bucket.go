@@ -0,0 +1,25 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+// BackupPolicyBucket - Bucket settings, the bucket name is used as a prefix of the dedicated bucket of every cluster
+type BackupPolicyBucket struct {
+
+	Cloud string `json:"cloud"`
+
+	BucketName string `json:"bucketName"`
+
+	SecretId string `json:"secretId"`
+
+	Location string `json:"location,omitempty"`
+
+	Azure BackupPolicyAzureBucketProperties `json:"azure,omitempty"`
+}
@@ -0,0 +1,21 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+// BackupPolicyClusterSelector - Selects clusters by cloud, distribution and labels, empty lists match every cluster
+type BackupPolicyClusterSelector struct {
+
+	Cloud []string `json:"cloud,omitempty"`
+
+	Distribution []string `json:"distribution,omitempty"`
+
+	Labels map[string]string `json:"labels,omitempty"`
+}
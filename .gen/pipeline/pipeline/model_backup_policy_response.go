@@ -0,0 +1,36 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type BackupPolicyResponse struct {
+
+	Id int32 `json:"id,omitempty"`
+
+	Name string `json:"name,omitempty"`
+
+	Selector BackupPolicyClusterSelector `json:"selector,omitempty"`
+
+	Schedule string `json:"schedule,omitempty"`
+
+	Ttl string `json:"ttl,omitempty"`
+
+	Rpo string `json:"rpo,omitempty"`
+
+	Bucket BackupPolicyBucket `json:"bucket,omitempty"`
+
+	Options BackupOptions `json:"options,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
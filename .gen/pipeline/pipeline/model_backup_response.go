@@ -35,4 +35,6 @@ type BackupResponse struct {
 	ExpireAt string `json:"expireAt,omitempty"`
 
 	ClusterId int32 `json:"clusterId,omitempty"`
+
+	Verification BackupVerification `json:"verification,omitempty"`
 }
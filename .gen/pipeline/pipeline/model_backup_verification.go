@@ -0,0 +1,30 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type BackupVerification struct {
+
+	Status string `json:"status,omitempty"`
+
+	Message string `json:"message,omitempty"`
+
+	Problems []string `json:"problems,omitempty"`
+
+	TargetClusterId int32 `json:"targetClusterId,omitempty"`
+
+	StartedAt time.Time `json:"startedAt,omitempty"`
+
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+}
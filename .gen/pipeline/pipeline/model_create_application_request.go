@@ -0,0 +1,24 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type CreateApplicationRequest struct {
+
+	Name string `json:"name"`
+
+	// default namespace of the releases
+	Namespace string `json:"namespace,omitempty"`
+
+	// values shared between the releases, release values can refer to them as {{ .Values.key }}
+	Values map[string]interface{} `json:"values,omitempty"`
+
+	Releases []ApplicationRelease `json:"releases"`
+}
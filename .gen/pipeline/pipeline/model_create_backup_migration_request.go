@@ -0,0 +1,23 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type CreateBackupMigrationRequest struct {
+
+	SourceClusterId int32 `json:"sourceClusterId"`
+
+	TargetClusterId int32 `json:"targetClusterId"`
+
+	// Existing backup of the source cluster, a new backup is taken if empty
+	BackupName string `json:"backupName,omitempty"`
+
+	Options BackupMigrationOptions `json:"options,omitempty"`
+}
@@ -0,0 +1,29 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type CreateBackupPolicyRequest struct {
+
+	Name string `json:"name"`
+
+	Selector BackupPolicyClusterSelector `json:"selector,omitempty"`
+
+	Schedule string `json:"schedule"`
+
+	Ttl string `json:"ttl"`
+
+	// Maximum age of the last successful backup of a compliant cluster
+	Rpo string `json:"rpo"`
+
+	Bucket BackupPolicyBucket `json:"bucket"`
+
+	Options BackupOptions `json:"options,omitempty"`
+}
@@ -14,5 +14,5 @@ type CreateRestoreRequest struct {
 
 	BackupName string `json:"backupName"`
 
-	Options BackupOptions `json:"options,omitempty"`
+	Options RestoreOptions `json:"options,omitempty"`
 }
@@ -24,6 +24,7 @@ type CreateUpdateDeploymentRequest struct {
 
 	ReleaseName string `json:"releaseName,omitempty"`
 
+	// if set, the deployment is only rendered; upgrades return the added, changed and removed resources
 	DryRun bool `json:"dryRun,omitempty"`
 
 	// if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful
@@ -37,6 +38,6 @@ type CreateUpdateDeploymentRequest struct {
 
 	ReuseValues bool `json:"reuseValues,omitempty"`
 
-	// current values of the deployment
+	// Values of the deployment. They are validated against the values.schema.json of the chart and the schema registered for it in Pipeline. String values can refer to secret values as {{ secret "brn:1:secret:<id>" "<key>" }}, which are resolved at deploy time. The deployment and diff APIs return the references instead of the resolved values, but the resolved values are stored in the release storage of Tiller: in Secrets of its namespace, or in ConfigMaps if Tiller was installed before Secret storage became the default.
 	Values map[string]interface{} `json:"values,omitempty"`
 }
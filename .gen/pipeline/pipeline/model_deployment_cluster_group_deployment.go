@@ -28,8 +28,11 @@ type DeploymentClusterGroupDeployment struct {
 
 	RollingMode bool `json:"rollingMode,omitempty"`
 
+	Rollout DeploymentRolloutStrategy `json:"rollout,omitempty"`
+
 	ValueOverrides map[string]interface{} `json:"valueOverrides,omitempty"`
 
+	// String values can be templates referring to the member cluster, e.g. {{ .Cluster.Location }}, {{ .Cluster.Cloud }}, {{ .Cluster.Labels.env }} or {{ .Cluster.Domain }}. Templates referring to anything else (eg. chart values evaluated with tpl) are passed to the chart untouched.
 	Values map[string]interface{} `json:"values,omitempty"`
 
 	Version string `json:"version,omitempty"`
@@ -0,0 +1,26 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type DeploymentDiff struct {
+
+	ReleaseName string `json:"releaseName"`
+
+	FromVersion int32 `json:"fromVersion"`
+
+	ToVersion int32 `json:"toVersion"`
+
+	// unified diff of the rendered manifests
+	Manifest string `json:"manifest"`
+
+	// unified diff of the user supplied values
+	Values string `json:"values"`
+}
@@ -0,0 +1,34 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type DeploymentRevision struct {
+
+	Version int32 `json:"version"`
+
+	Status string `json:"status"`
+
+	Chart string `json:"chart"`
+
+	ChartName string `json:"chartName"`
+
+	ChartVersion string `json:"chartVersion"`
+
+	AppVersion string `json:"appVersion,omitempty"`
+
+	Description string `json:"description,omitempty"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,23 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type DeploymentRolloutStrategy struct {
+
+	// names of the clusters the deployment is rolled out to first
+	CanaryClusters []string `json:"canaryClusters,omitempty"`
+
+	// time in seconds to wait for the release to become healthy on the clusters of a wave (default 300)
+	HealthTimeout int32 `json:"healthTimeout,omitempty"`
+
+	// number of clusters the deployment is rolled out to at once after the canary clusters, all remaining clusters if not set
+	WaveSize int32 `json:"waveSize,omitempty"`
+}
@@ -12,6 +12,8 @@ package pipeline
 
 type DeploymentTargetClusterStatus struct {
 
+	Canary bool `json:"canary,omitempty"`
+
 	Cloud string `json:"cloud,omitempty"`
 
 	ClusterId int32 `json:"clusterId,omitempty"`
@@ -22,9 +24,16 @@ type DeploymentTargetClusterStatus struct {
 
 	Error string `json:"error,omitempty"`
 
+	RolloutStatus string `json:"rolloutStatus,omitempty"`
+
 	Stale bool `json:"stale,omitempty"`
 
 	Status string `json:"status,omitempty"`
 
+	// the values of the installed release differ from the values rendered for the cluster
+	ValuesDrift bool `json:"valuesDrift,omitempty"`
+
 	Version string `json:"version,omitempty"`
+
+	Wave int32 `json:"wave,omitempty"`
 }
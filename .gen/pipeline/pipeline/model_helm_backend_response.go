@@ -0,0 +1,17 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type HelmBackendResponse struct {
+
+	// tiller manages the releases from the cluster, embedded-tiller manages them with a Tiller release engine running in Pipeline and stores them as secrets in the cluster, helm3 manages them with the Helm 3 client
+	Backend string `json:"backend"`
+}
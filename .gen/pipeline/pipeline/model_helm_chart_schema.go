@@ -0,0 +1,25 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type HelmChartSchema struct {
+
+	Chart string `json:"chart"`
+
+	// JSON schema of the chart values
+	Schema map[string]interface{} `json:"schema"`
+
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -18,9 +18,17 @@ type HelmRepoListItem struct {
 
 	Url string `json:"url,omitempty"`
 
+	Username string `json:"username,omitempty"`
+
+	// redacted
+	Password string `json:"password,omitempty"`
+
 	CertFile string `json:"certFile,omitempty"`
 
 	KeyFile string `json:"keyFile,omitempty"`
 
 	CaFile string `json:"caFile,omitempty"`
+
+	// Secret holding the credentials of the repository
+	SecretId string `json:"secretId,omitempty"`
 }
@@ -14,11 +14,9 @@ type HelmReposAddRequest struct {
 
 	Name string `json:"name"`
 
+	// URL of a chart repository or an OCI registry (oci://registry.example.com/charts)
 	Url string `json:"url"`
 
-	CertFile string `json:"certFile,omitempty"`
-
-	KeyFile string `json:"keyFile,omitempty"`
-
-	CaFile string `json:"caFile,omitempty"`
+	// Password (basic auth), TLS (client certificate) or Docker registry (registry token) secret used to access the repository
+	SecretId string `json:"secretId,omitempty"`
 }
@@ -16,9 +16,6 @@ type HelmReposModifyRequest struct {
 
 	Url string `json:"url,omitempty"`
 
-	CertFile string `json:"certFile,omitempty"`
-
-	KeyFile string `json:"keyFile,omitempty"`
-
-	CaFile string `json:"caFile,omitempty"`
+	// Password, TLS or Docker registry secret used to access the repository; the former credentials are kept if not set
+	SecretId string `json:"secretId,omitempty"`
 }
@@ -17,4 +17,7 @@ type InstallSecretsRequestQuery struct {
 	Ids []string `json:"ids,omitempty"`
 
 	Tags []string `json:"tags,omitempty"`
+
+	// Pins secrets (by ID) to a specific version
+	Versions map[string]int32 `json:"versions,omitempty"`
 }
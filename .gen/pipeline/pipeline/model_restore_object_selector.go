@@ -0,0 +1,23 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+// RestoreObjectSelector - Selects backed up objects, empty fields match everything
+type RestoreObjectSelector struct {
+
+	// Namespace of the object, empty for cluster scoped objects
+	Namespace string `json:"namespace,omitempty"`
+
+	// Resource name with or without the API group
+	Resource string `json:"resource,omitempty"`
+
+	Name string `json:"name,omitempty"`
+}
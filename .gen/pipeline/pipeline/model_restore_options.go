@@ -0,0 +1,33 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type RestoreOptions struct {
+
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+
+	IncludedResources []string `json:"includedResources,omitempty"`
+
+	ExcludedNamespaces []string `json:"excludedNamespaces,omitempty"`
+
+	ExcludedResources []string `json:"excludedResources,omitempty"`
+
+	NamespaceMapping map[string]string `json:"namespaceMapping,omitempty"`
+
+	RestorePVs bool `json:"restorePVs,omitempty"`
+
+	IncludeClusterResources bool `json:"includeClusterResources,omitempty"`
+
+	// Restore only the matching objects, the restore is done by Pipeline in the background instead of ARK if set
+	IncludedObjects []RestoreObjectSelector `json:"includedObjects,omitempty"`
+
+	ExcludedObjects []RestoreObjectSelector `json:"excludedObjects,omitempty"`
+}
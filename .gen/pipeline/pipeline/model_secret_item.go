@@ -28,6 +28,9 @@ type SecretItem struct {
 
 	UpdatedBy string `json:"updatedBy,omitempty"`
 
+	// Earliest expiry time of the certificates stored in the secret (if any)
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+
 	Tags []string `json:"tags,omitempty"`
 
 	Values map[string]interface{} `json:"values,omitempty"`
@@ -0,0 +1,30 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type SecretRotationPolicy struct {
+
+	SecretId string `json:"secretId"`
+
+	Provider string `json:"provider"`
+
+	Interval string `json:"interval"`
+
+	LastRotatedAt time.Time `json:"lastRotatedAt,omitempty"`
+
+	NextRotationAt time.Time `json:"nextRotationAt"`
+
+	LastError string `json:"lastError,omitempty"`
+}
@@ -0,0 +1,30 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+import (
+	"time"
+)
+
+type SecretVersion struct {
+
+	Version int32 `json:"version"`
+
+	CreatedAt time.Time `json:"createdAt"`
+
+	CreatedBy string `json:"createdBy,omitempty"`
+
+	DeletedAt time.Time `json:"deletedAt,omitempty"`
+
+	Destroyed bool `json:"destroyed"`
+
+	Current bool `json:"current"`
+}
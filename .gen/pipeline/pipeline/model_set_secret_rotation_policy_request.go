@@ -0,0 +1,20 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type SetSecretRotationPolicyRequest struct {
+
+	// Key provider used for the rotation (defaults to the secret type)
+	Provider string `json:"provider,omitempty"`
+
+	// Time between two rotations (at least one hour)
+	Interval string `json:"interval"`
+}
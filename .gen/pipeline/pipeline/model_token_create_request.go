@@ -20,5 +20,8 @@ type TokenCreateRequest struct {
 
 	VirtualUser string `json:"virtualUser,omitempty"`
 
-	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Has to be within the maximum token lifetime configured for Pipeline
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	Scope TokenScope `json:"scope,omitempty"`
 }
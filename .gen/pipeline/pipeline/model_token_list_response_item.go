@@ -10,6 +10,10 @@
 
 package pipeline
 
+import (
+	"time"
+)
+
 type TokenListResponseItem struct {
 
 	Id string `json:"id"`
@@ -17,4 +21,10 @@ type TokenListResponseItem struct {
 	CreatedAt string `json:"createdAt"`
 
 	Name string `json:"name"`
+
+	ExpiresAt time.Time `json:"expiresAt,omitempty"`
+
+	LastUsedAt time.Time `json:"lastUsedAt,omitempty"`
+
+	Scope TokenScope `json:"scope,omitempty"`
 }
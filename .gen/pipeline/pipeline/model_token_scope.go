@@ -0,0 +1,24 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+// TokenScope - Restricts the permissions of a token. Scoped tokens cannot be used to manage tokens.
+type TokenScope struct {
+
+	// Restrict the token to a single organization
+	OrganizationId int32 `json:"organizationId,omitempty"`
+
+	// Restrict the token to a set of clusters in the organization
+	ClusterIds []int32 `json:"clusterIds,omitempty"`
+
+	// Restrict the token to read-only requests
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
@@ -0,0 +1,20 @@
+/*
+ * Pipeline API
+ *
+ * Pipeline is a feature rich application platform, built for containers on top of Kubernetes to automate the DevOps experience, continuous application development and the lifecycle of deployments. 
+ *
+ * API version: latest
+ * Contact: info@banzaicloud.com
+ * Generated by: OpenAPI Generator (https://openapi-generator.tech)
+ */
+
+package pipeline
+
+type VerifyBackupRequest struct {
+
+	// Existing short-lived cluster (created with a TTL) to restore the backup into, the verification does not create it. The backup is restored into scratch namespaces of the backed up cluster without ingresses, jobs and cron jobs (services are restored as ClusterIP services) if omitted
+	TargetClusterId int32 `json:"targetClusterId,omitempty"`
+
+	// Time the restored workloads are given to become ready
+	ReadinessTimeout string `json:"readinessTimeout,omitempty"`
+}
@@ -16,6 +16,7 @@ package backups
 
 import (
 	"github.com/gin-gonic/gin"
+	"go.uber.org/cadence/client"
 
 	"github.com/banzaicloud/pipeline/api/ark/common"
 	"github.com/banzaicloud/pipeline/cluster"
@@ -34,8 +35,17 @@ func AddOrgRoutes(group *gin.RouterGroup, clusterManager *cluster.Manager) {
 	group.PUT("/sync", orgBackups.Sync)
 }
 
+type verifications struct {
+	clusterManager *cluster.Manager
+	workflowClient client.Client
+}
+
 // AddRoutes adds ARK backups related API routes
-func AddRoutes(group *gin.RouterGroup) {
+func AddRoutes(group *gin.RouterGroup, clusterManager *cluster.Manager, workflowClient client.Client) {
+	verifications := &verifications{
+		clusterManager: clusterManager,
+		workflowClient: workflowClient,
+	}
 
 	group.Use(common.ARKMiddleware(config.DB(), common.Log))
 	group.GET("", List)
@@ -46,6 +56,8 @@ func AddRoutes(group *gin.RouterGroup) {
 		item.GET("", Get)
 		item.DELETE("", Delete)
 		item.GET("/download", Download)
+		item.GET("/contents", GetContents)
 		item.GET("/logs", GetLogs)
+		item.POST("/verify", verifications.Verify)
 	}
 }
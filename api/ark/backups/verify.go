@@ -0,0 +1,194 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backups
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/api/ark/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	arkAPI "github.com/banzaicloud/pipeline/internal/ark/api"
+	arkClusterManager "github.com/banzaicloud/pipeline/internal/ark/clustermanager"
+	"github.com/banzaicloud/pipeline/internal/ark/verification"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+const verificationTimeout = 3 * time.Hour
+
+// Verify starts verifying a backup by restoring it into scratch namespaces or an existing short-lived cluster
+func (v *verifications) Verify(c *gin.Context) {
+	logger := correlationid.Logger(common.Log, c)
+
+	backupID, ok := ginutils.UintParam(c, IDParamName)
+	if !ok {
+		return
+	}
+
+	logger = logger.WithField("backup", backupID)
+	logger.Info("verifying backup")
+
+	var req arkAPI.VerifyBackupRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		err = emperror.Wrap(err, "could not parse request")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	readinessTimeout, err := req.GetReadinessTimeout()
+	if err != nil {
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	svc := common.GetARKService(c.Request)
+	backups := svc.GetBackupsService()
+
+	backup, err := backups.GetModelByID(backupID)
+	if err != nil {
+		err = emperror.Wrap(err, "could not get backup")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	if req.TargetClusterID == 0 {
+		req.TargetClusterID = backup.ClusterID
+	}
+
+	err = v.validate(c, svc, backup, req.TargetClusterID)
+	if err != nil {
+		err = emperror.Wrap(err, "invalid verification request")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	startedAt := time.Now()
+	backupVerification := &arkAPI.BackupVerification{
+		Status:          arkAPI.BackupVerificationStatusRunning,
+		TargetClusterID: req.TargetClusterID,
+		StartedAt:       &startedAt,
+	}
+
+	err = backups.UpdateVerification(backup.ID, *backupVerification)
+	if err != nil {
+		err = emperror.Wrap(err, "could not record verification")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	input := verification.VerificationWorkflowInput{
+		OrganizationID:   svc.GetOrganization().ID,
+		VerificationID:   verification.ID(backup.ID, startedAt),
+		BackupID:         backup.ID,
+		BackupName:       backup.Name,
+		ClusterID:        backup.ClusterID,
+		TargetClusterID:  req.TargetClusterID,
+		ReadinessTimeout: readinessTimeout,
+		StartedAt:        startedAt,
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           fmt.Sprintf("ark-backup-verification-%d", backup.ID),
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: verificationTimeout,
+		WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+	}
+
+	_, err = v.workflowClient.StartWorkflow(c.Request.Context(), workflowOptions, verification.VerificationWorkflowName, input)
+	if err != nil {
+		finishedAt := time.Now()
+		backupVerification.Status = arkAPI.BackupVerificationStatusFailed
+		backupVerification.Message = err.Error()
+		backupVerification.FinishedAt = &finishedAt
+
+		_ = backups.UpdateVerification(backup.ID, *backupVerification)
+
+		err = emperror.WrapWith(err, "could not start verification workflow", "workflowName", verification.VerificationWorkflowName)
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &arkAPI.VerifyBackupResponse{
+		BackupID:     backup.ID,
+		Verification: backupVerification,
+		Status:       http.StatusOK,
+	})
+}
+
+// validate checks whether the backup can be verified in the target cluster
+func (v *verifications) validate(c *gin.Context, svc *ark.Service, backup *ark.ClusterBackupsModel, targetClusterID uint) error {
+	if backup.ClusterID != svc.GetCluster().GetID() {
+		return errors.New("backup is not taken from this cluster")
+	}
+
+	if backup.Status != "Completed" {
+		return errors.Errorf("backup is %s, only completed backups can be verified", backup.Status)
+	}
+
+	// a verification which has not finished within the workflow timeout is considered to be lost
+	if backup.VerificationStatus == arkAPI.BackupVerificationStatusRunning &&
+		backup.VerificationStartedAt != nil && time.Since(*backup.VerificationStartedAt) < verificationTimeout {
+		return errors.New("backup is already being verified")
+	}
+
+	if targetClusterID == backup.ClusterID {
+		_, err := svc.GetDeploymentsService().GetActiveDeployment()
+		if err != nil {
+			return emperror.Wrap(err, "backup service is not enabled on the cluster")
+		}
+
+		return nil
+	}
+
+	org := auth.GetCurrentOrganization(c.Request)
+
+	target, err := arkClusterManager.New(v.clusterManager).GetClusterByID(c.Request.Context(), org.ID, targetClusterID)
+	if err != nil {
+		return emperror.Wrap(err, "could not get target cluster")
+	}
+
+	status, err := target.GetStatus()
+	if err != nil {
+		return emperror.Wrap(err, "could not get target cluster status")
+	}
+
+	if status.Status != pkgCluster.Running {
+		return errors.New("target cluster is not running")
+	}
+
+	// the target cluster is not provisioned for the verification, but it has to be created with a TTL,
+	// so that it is torn down by the cluster TTL controller after the verification
+	if status.TtlMinutes == 0 {
+		return errors.New("target cluster must be an existing short-lived cluster created with a TTL")
+	}
+
+	return nil
+}
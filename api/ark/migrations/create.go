@@ -0,0 +1,206 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/api/ark/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	arkAPI "github.com/banzaicloud/pipeline/internal/ark/api"
+	arkClusterManager "github.com/banzaicloud/pipeline/internal/ark/clustermanager"
+	"github.com/banzaicloud/pipeline/internal/ark/migration"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// Create starts migrating a backup of a cluster into another cluster
+func (m *migrations) Create(c *gin.Context) {
+	logger := correlationid.Logger(common.Log, c)
+	logger.Info("creating migration")
+
+	var req arkAPI.CreateMigrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = emperror.Wrap(err, "could not parse request")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	org := auth.GetCurrentOrganization(c.Request)
+
+	createBackup, err := m.validate(c, org, req)
+	if errors.Cause(err) == errForbidden {
+		err = emperror.Wrap(err, "could not create migration")
+		common.ErrorHandler.Handle(err)
+		pkgCommon.ErrorResponseWithStatus(c, http.StatusForbidden, err)
+		return
+	}
+	if err != nil {
+		err = emperror.Wrap(err, "invalid migration request")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	svc := ark.MigrationsServiceFactory(org, config.DB(), logger)
+
+	migrationItem, err := svc.Create(req)
+	if err != nil {
+		err = emperror.Wrap(err, "could not create migration")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	if createBackup {
+		migrationItem.BackupName = migration.BackupName(migrationItem.ID, time.Now())
+	}
+
+	input := migration.MigrationWorkflowInput{
+		OrganizationID:  org.ID,
+		MigrationID:     migrationItem.ID,
+		SourceClusterID: req.SourceClusterID,
+		TargetClusterID: req.TargetClusterID,
+		BackupName:      migrationItem.BackupName,
+		CreateBackup:    createBackup,
+		Options:         req.Options,
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		ID:                           fmt.Sprintf("ark-migration-%d", migrationItem.ID),
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: 6 * time.Hour,
+	}
+
+	_, err = m.workflowClient.StartWorkflow(c.Request.Context(), workflowOptions, migration.MigrationWorkflowName, input)
+	if err != nil {
+		_ = svc.UpdateStatus(migrationItem.ID, arkAPI.PersistMigrationStatusRequest{
+			Status:        arkAPI.MigrationStatusFailed,
+			StatusMessage: err.Error(),
+		})
+
+		err = emperror.WrapWith(err, "could not start migration workflow", "workflowName", migration.MigrationWorkflowName)
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &arkAPI.CreateMigrationResponse{
+		Migration: migrationItem,
+		Status:    http.StatusOK,
+	})
+}
+
+// validate checks the clusters and the backup of a migration request, and returns whether a new backup has to be taken
+func (m *migrations) validate(c *gin.Context, org *auth.Organization, req arkAPI.CreateMigrationRequest) (bool, error) {
+	if req.SourceClusterID == req.TargetClusterID {
+		return false, errors.New("source and target clusters must be different")
+	}
+
+	clusters := arkClusterManager.New(m.clusterManager)
+
+	source, err := clusters.GetClusterByID(c.Request.Context(), org.ID, req.SourceClusterID)
+	if err != nil {
+		return false, emperror.Wrap(err, "could not get source cluster")
+	}
+
+	target, err := clusters.GetClusterByID(c.Request.Context(), org.ID, req.TargetClusterID)
+	if err != nil {
+		return false, emperror.Wrap(err, "could not get target cluster")
+	}
+
+	// a backup is taken of the source cluster unless an existing one is restored
+	sourceVerb := role.VerbGet
+	if req.BackupName == "" {
+		sourceVerb = role.VerbUpdate
+	}
+
+	user := auth.GetCurrentUser(c.Request)
+
+	if err := m.authorize(c.Request.Context(), org, user, req.SourceClusterID, sourceVerb); err != nil {
+		return false, emperror.With(err, "clusterRole", "source")
+	}
+
+	if err := m.authorize(c.Request.Context(), org, user, req.TargetClusterID, role.VerbUpdate); err != nil {
+		return false, emperror.With(err, "clusterRole", "target")
+	}
+
+	if req.Options.RestorePVs != nil && *req.Options.RestorePVs && !migration.CanRestoreVolumes(source, target) {
+		return false, errors.New("volumes can only be restored within the same cloud and location")
+	}
+
+	if req.BackupName != "" {
+		backup, err := ark.BackupsServiceFactory(org, config.DB(), common.Log).GetModelByName(req.BackupName)
+		if err != nil {
+			return false, err
+		}
+		if backup.ClusterID != req.SourceClusterID {
+			return false, errors.New("backup is not taken from the source cluster")
+		}
+
+		return false, nil
+	}
+
+	_, err = ark.DeploymentsServiceFactory(org, source, config.DB(), common.Log).GetActiveDeployment()
+	if err != nil {
+		return false, emperror.Wrap(err, "backup service is not enabled on the source cluster")
+	}
+
+	return true, nil
+}
+
+// errForbidden is returned when the caller has no access to a cluster of a migration
+var errForbidden = errors.New("access to cluster is forbidden")
+
+// authorize checks whether the caller (and the token it authenticated with) has access to a cluster of a migration.
+func (m *migrations) authorize(ctx context.Context, org *auth.Organization, user *auth.User, clusterID uint, verb string) error {
+	if user == nil {
+		return errForbidden
+	}
+
+	granted, err := m.tokenScopeEnforcer.EnforceCluster(ctx, user, clusterID)
+	if err != nil {
+		return emperror.WrapWith(err, "could not check token scope", "clusterId", clusterID)
+	}
+	if !granted {
+		return emperror.With(errForbidden, "clusterId", clusterID)
+	}
+
+	resource := brn.New(org.ID, brn.ClusterResourceType, strconv.FormatUint(uint64(clusterID), 10))
+
+	granted, err = m.resourceEnforcer.Enforce(ctx, org.ID, user.ID, user.TokenID, resource, verb)
+	if err != nil {
+		return emperror.WrapWith(err, "could not check cluster permissions", "clusterId", clusterID)
+	}
+	if !granted {
+		return emperror.With(errForbidden, "clusterId", clusterID, "verb", verb)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrations
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+)
+
+type resourceEnforcerStub struct {
+	// granted are the verbs granted by cluster resource ID
+	granted map[string]string
+}
+
+func (e resourceEnforcerStub) Enforce(ctx context.Context, organizationID uint, userID uint, tokenID string, resource brn.ResourceName, verb string) (bool, error) {
+	return resource.ResourceType == brn.ClusterResourceType && e.granted[resource.ResourceID] == verb, nil
+}
+
+type tokenScopeEnforcerStub struct {
+	clusterIDs []uint
+}
+
+func (e tokenScopeEnforcerStub) EnforceCluster(ctx context.Context, user *auth.User, clusterID uint) (bool, error) {
+	for _, id := range e.clusterIDs {
+		if id == clusterID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func TestMigrations_Authorize(t *testing.T) {
+	m := &migrations{
+		resourceEnforcer:   resourceEnforcerStub{granted: map[string]string{"1": role.VerbGet, "2": role.VerbUpdate}},
+		tokenScopeEnforcer: tokenScopeEnforcerStub{clusterIDs: []uint{1, 2, 3}},
+	}
+
+	org := &auth.Organization{ID: 1}
+	user := &auth.User{ID: 1}
+
+	tests := []struct {
+		name      string
+		clusterID uint
+		verb      string
+		forbidden bool
+	}{
+		{name: "read granted", clusterID: 1, verb: role.VerbGet},
+		{name: "update not granted", clusterID: 1, verb: role.VerbUpdate, forbidden: true},
+		{name: "update granted", clusterID: 2, verb: role.VerbUpdate},
+		{name: "no policy", clusterID: 3, verb: role.VerbGet, forbidden: true},
+		{name: "out of token scope", clusterID: 4, verb: role.VerbGet, forbidden: true},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			err := m.authorize(context.Background(), org, user, test.clusterID, test.verb)
+
+			if test.forbidden {
+				assert.Equal(t, errForbidden, errors.Cause(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Equal(t, errForbidden, errors.Cause(m.authorize(context.Background(), org, nil, 1, role.VerbGet)))
+}
@@ -0,0 +1,44 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrations
+
+import (
+	"net/http"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/api/ark/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+)
+
+// List lists the migrations of the organization
+func (m *migrations) List(c *gin.Context) {
+	logger := correlationid.Logger(common.Log, c)
+	logger.Info("getting migrations")
+
+	migrations, err := ark.MigrationsServiceFactory(auth.GetCurrentOrganization(c.Request), config.DB(), logger).List()
+	if err != nil {
+		err = emperror.Wrap(err, "could not get migrations")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, migrations)
+}
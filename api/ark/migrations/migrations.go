@@ -0,0 +1,56 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrations
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/ginauth"
+)
+
+const (
+	IDParamName = "migrationId"
+)
+
+type migrations struct {
+	clusterManager *cluster.Manager
+	workflowClient client.Client
+
+	// the clusters of a migration are referenced in the request body, so they are authorized by the handlers
+	resourceEnforcer   ginauth.ResourceEnforcer
+	tokenScopeEnforcer ginauth.ClusterEnforcer
+}
+
+// AddRoutes adds ARK migrations related API routes
+func AddRoutes(
+	group *gin.RouterGroup,
+	clusterManager *cluster.Manager,
+	workflowClient client.Client,
+	resourceEnforcer ginauth.ResourceEnforcer,
+	tokenScopeEnforcer ginauth.ClusterEnforcer,
+) {
+	m := &migrations{
+		clusterManager:     clusterManager,
+		workflowClient:     workflowClient,
+		resourceEnforcer:   resourceEnforcer,
+		tokenScopeEnforcer: tokenScopeEnforcer,
+	}
+
+	group.GET("", m.List)
+	group.POST("", m.Create)
+	group.GET("/:"+IDParamName, m.Get)
+}
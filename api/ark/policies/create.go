@@ -0,0 +1,73 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policies
+
+import (
+	"net/http"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/api/ark/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	arkAPI "github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+)
+
+// Create creates a backup policy, the matching clusters are reconciled in the background
+func (p *policies) Create(c *gin.Context) {
+	logger := correlationid.Logger(common.Log, c)
+	logger.Info("creating backup policy")
+
+	var req arkAPI.CreateBackupPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		err = emperror.Wrap(err, "could not parse request")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		err = emperror.Wrap(err, "invalid backup policy request")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	org := auth.GetCurrentOrganization(c.Request)
+
+	_, err := ark.GetSecretWithValidation(req.Bucket.SecretID, org.ID, req.Bucket.Cloud)
+	if err != nil {
+		err = emperror.Wrap(err, "invalid bucket secret")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	policy, err := ark.PoliciesServiceFactory(org, config.DB(), logger).Create(req)
+	if err != nil {
+		err = emperror.Wrap(err, "could not create backup policy")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &arkAPI.CreateBackupPolicyResponse{
+		Policy: policy,
+		Status: http.StatusOK,
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policies
+
+import (
+	"net/http"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/api/ark/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	arkAPI "github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+)
+
+// Delete deletes a backup policy, its schedules are removed from the clusters in the background
+func (p *policies) Delete(c *gin.Context) {
+	logger := correlationid.Logger(common.Log, c)
+
+	policyID, ok := ginutils.UintParam(c, IDParamName)
+	if !ok {
+		return
+	}
+
+	logger = logger.WithField("policy", policyID)
+	logger.Info("deleting backup policy")
+
+	err := ark.PoliciesServiceFactory(auth.GetCurrentOrganization(c.Request), config.DB(), logger).DeleteByID(policyID)
+	if err != nil {
+		err = emperror.Wrap(err, "could not delete backup policy")
+		common.ErrorHandler.Handle(err)
+		common.ErrorResponse(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &arkAPI.DeleteBackupPolicyResponse{
+		ID:     policyID,
+		Status: http.StatusOK,
+	})
+}
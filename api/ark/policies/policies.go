@@ -0,0 +1,53 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policies
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/cluster"
+)
+
+const (
+	IDParamName = "policyId"
+)
+
+type policies struct {
+	clusterManager *cluster.Manager
+}
+
+// AddRoutes adds ARK backup policies related API routes
+func AddRoutes(group *gin.RouterGroup, clusterManager *cluster.Manager) {
+	p := &policies{
+		clusterManager: clusterManager,
+	}
+
+	group.GET("", p.List)
+	group.POST("", p.Create)
+	item := group.Group("/:" + IDParamName)
+	{
+		item.GET("", p.Get)
+		item.DELETE("", p.Delete)
+	}
+}
+
+// AddReportRoutes adds ARK backup policy compliance report related API routes
+func AddReportRoutes(group *gin.RouterGroup, clusterManager *cluster.Manager) {
+	p := &policies{
+		clusterManager: clusterManager,
+	}
+
+	group.GET("", p.GetComplianceReport)
+}
@@ -15,18 +15,22 @@
 package restores
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	"emperror.dev/emperror"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/cadence/client"
 
 	"github.com/banzaicloud/pipeline/api/ark/common"
 	arkAPI "github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/internal/ark/restore"
 	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
 )
 
 // Create creates a new ARK restore
-func Create(c *gin.Context) {
+func (r restores) Create(c *gin.Context) {
 	logger := correlationid.Logger(common.Log, c)
 	logger.Info("creating restore")
 
@@ -38,7 +42,9 @@ func Create(c *gin.Context) {
 		return
 	}
 
-	_, err := common.GetARKService(c.Request).GetClusterBackupsService().GetByName(req.BackupName)
+	svc := common.GetARKService(c.Request)
+
+	_, err := svc.GetClusterBackupsService().GetByName(req.BackupName)
 	if err != nil {
 		err = emperror.Wrap(err, "could not find backup")
 		common.ErrorHandler.Handle(err)
@@ -46,7 +52,7 @@ func Create(c *gin.Context) {
 		return
 	}
 
-	restore, err := common.GetARKService(c.Request).GetRestoresService().Create(req)
+	restoreItem, err := svc.GetRestoresService().Create(req)
 	if err != nil {
 		err = emperror.Wrap(err, "could not create restore")
 		common.ErrorHandler.Handle(err)
@@ -54,8 +60,33 @@ func Create(c *gin.Context) {
 		return
 	}
 
+	// object level restores are executed by Pipeline in the background
+	if req.Options.HasObjectSelectors() {
+		input := restore.ObjectRestoreWorkflowInput{
+			OrganizationID: svc.GetOrganization().ID,
+			ClusterID:      svc.GetCluster().GetID(),
+			RestoreID:      restoreItem.ID,
+		}
+
+		workflowOptions := client.StartWorkflowOptions{
+			ID:                           fmt.Sprintf("ark-object-restore-%d", restoreItem.ID),
+			TaskList:                     "pipeline",
+			ExecutionStartToCloseTimeout: 2 * time.Hour,
+		}
+
+		_, err = r.workflowClient.StartWorkflow(c.Request.Context(), workflowOptions, restore.ObjectRestoreWorkflowName, input)
+		if err != nil {
+			_ = svc.GetRestoresService().FailObjectRestore(restoreItem.ID, err)
+
+			err = emperror.WrapWith(err, "could not start object restore workflow", "workflowName", restore.ObjectRestoreWorkflowName)
+			common.ErrorHandler.Handle(err)
+			common.ErrorResponse(c, err)
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, &arkAPI.CreateRestoreResponse{
-		Restore: restore,
+		Restore: restoreItem,
 		Status:  http.StatusOK,
 	})
 }
@@ -16,6 +16,7 @@ package restores
 
 import (
 	"github.com/gin-gonic/gin"
+	"go.uber.org/cadence/client"
 
 	"github.com/banzaicloud/pipeline/api/ark/common"
 	"github.com/banzaicloud/pipeline/config"
@@ -25,12 +26,19 @@ const (
 	IDParamName = "restoreId"
 )
 
+type restores struct {
+	workflowClient client.Client
+}
+
 // AddRoutes adds ARK restores related API routes
-func AddRoutes(group *gin.RouterGroup) {
+func AddRoutes(group *gin.RouterGroup, workflowClient client.Client) {
+	r := restores{
+		workflowClient: workflowClient,
+	}
 
 	group.Use(common.ARKMiddleware(config.DB(), common.Log))
 	group.GET("", List)
-	group.POST("", Create)
+	group.POST("", r.Create)
 	group.PUT("/sync", Sync)
 	item := group.Group("/:" + IDParamName)
 	{
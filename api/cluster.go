@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"emperror.dev/emperror"
@@ -39,6 +40,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/cluster/resourcesummary"
 	intClusterGroup "github.com/banzaicloud/pipeline/internal/clustergroup"
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke/driver"
+	"github.com/banzaicloud/pipeline/pkg/brn"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
@@ -214,9 +216,31 @@ func (a *ClusterAPI) GetClusters(c *gin.Context) {
 		return
 	}
 
+	ids := make([]string, 0, len(clusters))
+	for _, cl := range clusters {
+		ids = append(ids, strconv.FormatUint(uint64(cl.GetID()), 10))
+	}
+
+	allowed, err := auth.FilterResourceIDs(c.Request.Context(), organizationID, brn.ClusterResourceType, ids)
+	if err != nil {
+		logger.Errorf("error filtering clusters: %s", err.Error())
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error filtering clusters",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
 	response := make([]pkgCluster.GetClusterStatusResponse, 0)
 
-	for _, c := range clusters {
+	for i, c := range clusters {
+		if !allowed[ids[i]] {
+			continue
+		}
+
 		logger := logger.WithField("cluster", c.GetName())
 
 		status, err := c.GetStatus()
@@ -452,7 +476,7 @@ type InstallSecretsToClusterRequest struct {
 }
 
 // InstallSecretsToCluster add all secrets from a repo to a cluster's namespace combined into one global secret named as the repo
-func InstallSecretsToCluster(c *gin.Context) {
+func (a *ClusterSecretAPI) InstallSecretsToCluster(c *gin.Context) {
 	commonCluster, ok := getClusterFromRequest(c)
 	if !ok {
 		return
@@ -469,6 +493,27 @@ func InstallSecretsToCluster(c *gin.Context) {
 		return
 	}
 
+	// every secret matching the query is installed, so all of them have to be readable by the user
+	query := request.Query
+	query.Values = false
+
+	secrets, err := secret.Store.List(commonCluster.GetOrganizationId(), &query)
+	if err != nil {
+		log.Errorf("Error listing secrets [%v]: %s", request.Query, err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error listing secrets",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	for _, secretItem := range secrets {
+		if !replyWithSecretAuthorization(c, a.authorizer, secretItem.ID) {
+			return
+		}
+	}
+
 	secretSources, err := cluster.InstallSecrets(commonCluster, &request.Query, request.Namespace)
 
 	if err != nil {
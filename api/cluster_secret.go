@@ -24,6 +24,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/platform/gin/correlationid"
 	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
 )
 
 // Models copied from generated client package.
@@ -45,8 +46,20 @@ type InstallSecretResponse struct {
 	Sourcing string `json:"sourcing"`
 }
 
+// ClusterSecretAPI implements the endpoints installing Pipeline secrets to a cluster.
+type ClusterSecretAPI struct {
+	authorizer SecretAuthorizer
+}
+
+// NewClusterSecretAPI returns a new ClusterSecretAPI instance.
+func NewClusterSecretAPI(authorizer SecretAuthorizer) *ClusterSecretAPI {
+	return &ClusterSecretAPI{
+		authorizer: authorizer,
+	}
+}
+
 // InstallSecretToCluster installs a particular secret to a cluster's namespace.
-func InstallSecretToCluster(c *gin.Context) {
+func (a *ClusterSecretAPI) InstallSecretToCluster(c *gin.Context) {
 	commonCluster, ok := getClusterFromRequest(c)
 	if !ok {
 		return
@@ -96,6 +109,13 @@ func InstallSecretToCluster(c *gin.Context) {
 		secretRequest.SourceSecretName = secretName
 	}
 
+	if secretRequest.SourceSecretName != "" {
+		secretID := secret.GenerateSecretIDFromName(secretRequest.SourceSecretName)
+		if !replyWithSecretAuthorization(c, a.authorizer, secretID) {
+			return
+		}
+	}
+
 	secretSource, err := cluster.InstallSecret(commonCluster, secretName, secretRequest)
 
 	if err == cluster.ErrSecretNotFound {
@@ -139,7 +159,7 @@ func InstallSecretToCluster(c *gin.Context) {
 }
 
 // MergeSecretInCluster installs a particular secret to a cluster's namespace.
-func MergeSecretInCluster(c *gin.Context) {
+func (a *ClusterSecretAPI) MergeSecretInCluster(c *gin.Context) {
 	commonCluster, ok := getClusterFromRequest(c)
 	if !ok {
 		return
@@ -189,6 +209,13 @@ func MergeSecretInCluster(c *gin.Context) {
 		secretRequest.SourceSecretName = secretName
 	}
 
+	if secretRequest.SourceSecretName != "" {
+		secretID := secret.GenerateSecretIDFromName(secretRequest.SourceSecretName)
+		if !replyWithSecretAuthorization(c, a.authorizer, secretID) {
+			return
+		}
+	}
+
 	secretSource, err := cluster.MergeSecret(commonCluster, secretName, secretRequest)
 
 	if err == cluster.ErrSecretNotFound {
@@ -52,7 +52,8 @@ func (e ErrorHandler) errorResponseFrom(err error) *pkgCommon.ErrorResponse {
 	var code int
 	if cgroup.IsClusterGroupNotFoundError(err) || deployment.IsDeploymentNotFoundError(err) || cgroup.IsFeatureRecordNotFoundError(err) {
 		code = http.StatusNotFound
-	} else if cgroup.IsClusterGroupAlreadyExistsError(err) || cgroup.IsUnableToJoinMemberClusterError(err) || cgroup.IsInvalidClusterGroupCreateRequestError(err) || cgroup.IsClusterGroupUpdateRejectedError(err) {
+	} else if cgroup.IsClusterGroupAlreadyExistsError(err) || cgroup.IsUnableToJoinMemberClusterError(err) || cgroup.IsInvalidClusterGroupCreateRequestError(err) || cgroup.IsClusterGroupUpdateRejectedError(err) ||
+		deployment.IsInvalidRolloutStrategyError(err) || deployment.IsRolloutNotHaltedError(err) || deployment.IsInvalidValuesError(err) {
 		code = http.StatusBadRequest
 	}
 
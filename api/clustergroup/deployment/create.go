@@ -28,7 +28,7 @@ import (
 )
 
 // @Summary Create Cluster Group Deployment
-// @Description creates a new cluster group deployment, installs or upgrades deployment on each member cluster accordingly
+// @Description creates a new cluster group deployment, installs or upgrades deployment on each member cluster accordingly; if a rollout strategy is set, the deployment is rolled out progressively in the background
 // @Tags clustergroup deployments
 // @Accept json
 // @Produce json
@@ -61,5 +61,6 @@ func (a *API) AddRoutes(group *gin.RouterGroup) {
 		item.PUT("", a.Upgrade)
 		item.DELETE("", a.Delete)
 		item.PUT("/sync", a.Sync)
+		item.PUT("/rollback", a.Rollback)
 	}
 }
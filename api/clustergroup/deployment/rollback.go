@@ -0,0 +1,70 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/auth"
+	gutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+)
+
+// @Summary Rollback Cluster Group Deployment
+// @Description rolls back a halted progressive rollout of a cluster group deployment, clusters already reached by the rollout are rolled back to their previous release revision, releases installed by the rollout are deleted
+// @Tags clustergroup deployments
+// @Accept json
+// @Produce json
+// @Param orgid path uint true "Organization ID"
+// @Param clusterGroupId path uint true "Cluster Group ID"
+// @Param deploymentName path string true "release name of a cluster group deployment"
+// @Success 202 {object} deployment.TargetClusterStatus "Rollout has been rolled back successfully on all clusters reached by the rollout."
+// @Success 207 {object} common.ErrorResponse "Partial failure, meaning that there was as least one failure on one of the target clusters"
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Router /api/v1/orgs/{orgid}/clustergroups/{clusterGroupId}/deployments/{deploymentName}/rollback [put]
+// @Security bearerAuth
+func (n *API) Rollback(c *gin.Context) {
+	ctx := gutils.Context(context.Background(), c)
+
+	name := c.Param("name")
+	n.logger.Infof("rollback cluster group deployment: [%s]", name)
+
+	clusterGroupID, ok := gutils.UintParam(c, "id")
+	if !ok {
+		return
+	}
+
+	orgID := auth.GetCurrentOrganization(c.Request).ID
+	clusterGroup, err := n.clusterGroupManager.GetClusterGroupByID(ctx, clusterGroupID, orgID)
+	if err != nil {
+		n.errorHandler.Handle(c, err)
+		return
+	}
+
+	response, err := n.deploymentManager.RollbackDeployment(clusterGroup, name)
+	if err != nil {
+		n.errorHandler.Handle(c, err)
+		return
+	}
+
+	if n.returnOperationErrorsIfAny(c, response, name) {
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
@@ -27,7 +27,7 @@ import (
 )
 
 // @Summary Update Cluster Group Deployment
-// @Description updates a cluster group deployment, installs or upgrades deployment on each member cluster accordingly
+// @Description updates a cluster group deployment, installs or upgrades deployment on each member cluster accordingly; if a rollout strategy is set, the deployment is rolled out progressively in the background
 // @Tags clustergroup deployments
 // @Accept json
 // @Produce json
@@ -15,26 +15,29 @@
 package api
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
-	k8sHelm "k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/helm/environment"
-	"k8s.io/helm/pkg/proto/hapi/release"
-	rls "k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/repo"
 
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/cluster"
 	"github.com/banzaicloud/pipeline/helm"
+	internalHelm "github.com/banzaicloud/pipeline/internal/helm"
+	"github.com/banzaicloud/pipeline/pkg/brn"
 	pkgCommmon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
 	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+	"github.com/banzaicloud/pipeline/secret"
 )
 
 // ChartQuery describes a query to get available helm chart's list
@@ -64,8 +67,108 @@ func GetK8sConfig(c *gin.Context) ([]byte, bool) {
 	return kubeConfig, true
 }
 
+// DeploymentSecretGetter returns the secrets referenced in deployment values.
+type DeploymentSecretGetter interface {
+	Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error)
+}
+
+// SecretAuthorizer checks if the current user may read a secret referenced by a request.
+type SecretAuthorizer interface {
+	AuthorizeSecret(r *http.Request, secretID string) (bool, error)
+}
+
+// secretAccessDeniedError is returned when the current user may not read a referenced secret.
+type secretAccessDeniedError struct {
+	secretID string
+}
+
+func (e secretAccessDeniedError) Error() string {
+	return fmt.Sprintf("access to secret %q is denied", e.secretID)
+}
+
+// authorizeSecret returns an error if the current user may not read the secret.
+func authorizeSecret(c *gin.Context, authorizer SecretAuthorizer, secretID string) error {
+	granted, err := authorizer.AuthorizeSecret(c.Request, secretID)
+	if err != nil {
+		return err
+	}
+
+	if !granted {
+		return secretAccessDeniedError{secretID: secretID}
+	}
+
+	return nil
+}
+
+// replyWithSecretAuthorization replies with an error and returns false if the current user may not read the secret.
+func replyWithSecretAuthorization(c *gin.Context, authorizer SecretAuthorizer, secretID string) bool {
+	err := authorizeSecret(c, authorizer, secretID)
+	if _, ok := errors.Cause(err).(secretAccessDeniedError); ok {
+		c.AbortWithStatusJSON(http.StatusForbidden, pkgCommmon.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "Access to the referenced secret is denied",
+			Error:   err.Error(),
+		})
+
+		return false
+	} else if err != nil {
+		log.Errorf("Error during checking secret permissions. %s", err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error checking secret permissions",
+			Error:   err.Error(),
+		})
+
+		return false
+	}
+
+	return true
+}
+
+// DeploymentService manages the deployments of a cluster with the Helm backend of the cluster.
+type DeploymentService interface {
+	ListDeployments(ctx context.Context, clusterID uint, tag string) ([]internalHelm.Release, error)
+	GetDeployment(ctx context.Context, clusterID uint, releaseName string) (*pkgHelm.GetDeploymentResponse, error)
+	GetTaggedDeployment(ctx context.Context, clusterID uint, releaseName string, tag string) (*pkgHelm.GetDeploymentResponse, error)
+	GetDeploymentStatus(ctx context.Context, clusterID uint, releaseName string) (string, error)
+	GetDeploymentResources(ctx context.Context, clusterID uint, releaseName string, resourceTypes []string) ([]pkgHelm.DeploymentResource, error)
+	CreateDeployment(ctx context.Context, clusterID uint, namespace string, chartName string, chartVersion string, releaseName string, values []byte, options internalHelm.ReleaseOptions) (*internalHelm.Release, error)
+	UpgradeDeployment(ctx context.Context, clusterID uint, chartName string, chartVersion string, releaseName string, values []byte, options internalHelm.ReleaseOptions) (*internalHelm.Release, error)
+	PreviewDeploymentUpgrade(ctx context.Context, clusterID uint, chartName string, chartVersion string, releaseName string, values []byte, options internalHelm.ReleaseOptions) (*pkgHelm.DeploymentPreviewResponse, error)
+	GetDeploymentHistory(ctx context.Context, clusterID uint, releaseName string) ([]pkgHelm.DeploymentRevision, error)
+	GetDeploymentDiff(ctx context.Context, clusterID uint, releaseName string, fromVersion int, toVersion int) (*pkgHelm.GetDeploymentDiffResponse, error)
+	RollbackDeployment(ctx context.Context, clusterID uint, releaseName string, version int) (*internalHelm.Release, error)
+	DeleteDeployment(ctx context.Context, clusterID uint, releaseName string) error
+}
+
+// DeploymentAPI implements the Helm deployment endpoints.
+// Deployments are managed by the Helm backend of the cluster (Tiller or Helm 3).
+type DeploymentAPI struct {
+	deployments DeploymentService
+	schemas     internalHelm.ChartSchemaStore
+	secrets     DeploymentSecretGetter
+	authorizer  SecretAuthorizer
+}
+
+// NewDeploymentAPI returns a new DeploymentAPI instance.
+func NewDeploymentAPI(deployments DeploymentService, schemas internalHelm.ChartSchemaStore, secrets DeploymentSecretGetter, authorizer SecretAuthorizer) *DeploymentAPI {
+	return &DeploymentAPI{
+		deployments: deployments,
+		schemas:     schemas,
+		secrets:     secrets,
+		authorizer:  authorizer,
+	}
+}
+
+// isDeploymentNotFound returns true if an error is caused by a missing deployment (or deployment revision).
+func isDeploymentNotFound(err error) bool {
+	_, ok := errors.Cause(err).(*helm.DeploymentNotFoundError)
+
+	return ok
+}
+
 // CreateDeployment creates a Helm deployment
-func CreateDeployment(c *gin.Context) {
+func (a *DeploymentAPI) CreateDeployment(c *gin.Context) {
 	commonCluster, ok := getClusterFromRequest(c)
 	if ok != true {
 		return
@@ -81,25 +184,25 @@ func CreateDeployment(c *gin.Context) {
 		return
 	}
 
-	installOptions := []k8sHelm.InstallOption{
-		k8sHelm.InstallWait(parsedRequest.wait),
-		k8sHelm.ValueOverrides(parsedRequest.values),
-	}
-
-	if parsedRequest.timeout > 0 {
-		installOptions = append(installOptions, k8sHelm.InstallTimeout(parsedRequest.timeout))
+	if !a.prepareDeploymentValues(c, commonCluster.GetOrganizationId(), parsedRequest) {
+		return
 	}
 
-	release, err := helm.CreateDeployment(parsedRequest.deploymentName,
-		parsedRequest.deploymentVersion,
-		parsedRequest.deploymentPackage,
+	release, err := a.deployments.CreateDeployment(
+		c.Request.Context(),
+		commonCluster.GetID(),
 		parsedRequest.namespace,
+		parsedRequest.deploymentName,
+		parsedRequest.deploymentVersion,
 		parsedRequest.deploymentReleaseName,
-		parsedRequest.dryRun,
-		parsedRequest.odPcts,
-		parsedRequest.kubeConfig,
-		helm.GenerateHelmRepoEnv(parsedRequest.organizationName),
-		installOptions...,
+		parsedRequest.values,
+		internalHelm.ReleaseOptions{
+			Wait:                parsedRequest.wait,
+			DryRun:              parsedRequest.dryRun,
+			Timeout:             parsedRequest.timeout,
+			ChartPackage:        parsedRequest.deploymentPackage,
+			OnDemandPercentages: parsedRequest.odPcts,
+		},
 	)
 	if err != nil {
 		// TODO distinguish error codes
@@ -113,11 +216,9 @@ func CreateDeployment(c *gin.Context) {
 	}
 	log.Info("Create deployment succeeded")
 
-	releaseContent := release.GetRelease()
-
-	releaseName := releaseContent.GetName()
-	releaseNotes := base64.StdEncoding.EncodeToString([]byte(releaseContent.GetInfo().GetStatus().GetNotes()))
-	resources, err := helm.ParseReleaseManifest(releaseContent.Manifest, []string{})
+	releaseName := release.Name
+	releaseNotes := base64.StdEncoding.EncodeToString([]byte(release.Notes))
+	resources, err := helm.ParseReleaseManifest(release.Manifest, []string{})
 	if err != nil {
 		log.Errorf("Error during parsing release manifest. %s", err.Error())
 	}
@@ -135,14 +236,14 @@ func CreateDeployment(c *gin.Context) {
 }
 
 // ListDeployments lists a Helm deployment
-func ListDeployments(c *gin.Context) {
-	kubeConfig, ok := GetK8sConfig(c)
+func (a *DeploymentAPI) ListDeployments(c *gin.Context) {
+	commonCluster, ok := getClusterFromRequest(c)
 	if ok != true {
 		return
 	}
 
 	log.Info("Get deployments")
-	response, err := helm.ListDeployments(nil, c.Query("tag"), kubeConfig)
+	response, err := a.deployments.ListDeployments(c.Request.Context(), commonCluster.GetID(), c.Query("tag"))
 	if err != nil {
 		log.Error("Error listing deployments: ", err.Error())
 		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
@@ -176,25 +277,62 @@ func ListDeployments(c *gin.Context) {
 	}
 	releases := ListHelmReleases(c, response, supportedCharts)
 
+	releases, err = filterDeployments(c, releases)
+	if err != nil {
+		log.Error("Error filtering deployments: ", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error filtering deployments",
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, releases)
 	return
 }
 
+// filterDeployments removes the deployments from a list that the current request may not read according to the resource policies.
+func filterDeployments(c *gin.Context, releases []pkgHelm.ListDeploymentResponse) ([]pkgHelm.ListDeploymentResponse, error) {
+	clusterID := c.Param("id")
+	if id, ok := ctxutil.ClusterID(c.Request.Context()); ok {
+		clusterID = strconv.FormatUint(uint64(id), 10)
+	}
+
+	ids := make([]string, 0, len(releases))
+	for _, release := range releases {
+		ids = append(ids, clusterID+"/"+release.Name)
+	}
+
+	allowed, err := auth.FilterResourceIDs(c.Request.Context(), auth.GetCurrentOrganization(c.Request).ID, brn.DeploymentResourceType, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]pkgHelm.ListDeploymentResponse, 0, len(releases))
+	for i, release := range releases {
+		if allowed[ids[i]] {
+			filtered = append(filtered, release)
+		}
+	}
+
+	return filtered, nil
+}
+
 // HelmDeploymentStatus checks the status of a deployment through the helm client API
-func HelmDeploymentStatus(c *gin.Context) {
+func (a *DeploymentAPI) HelmDeploymentStatus(c *gin.Context) {
 
 	name := c.Param("name")
 	log.Infof("getting status for deployment: [%s]", name)
 
-	kubeConfig, ok := GetK8sConfig(c)
+	commonCluster, ok := getClusterFromRequest(c)
 
 	if !ok {
-		log.Debug("could not get the k8s config")
+		log.Debug("could not get the cluster")
 		return
 	}
 
-	status, err := helm.GetDeploymentStatus(name, kubeConfig)
-	// we have the status code in the status, regardless the error!
+	status, err := a.deployments.GetDeploymentStatus(c.Request.Context(), commonCluster.GetID(), name)
 
 	var (
 		statusCode int
@@ -202,19 +340,18 @@ func HelmDeploymentStatus(c *gin.Context) {
 	)
 
 	if err != nil {
-		// the helm client returned with error
-		statusCode = int(status)
-		msg = err.Error()
-	} else {
-		// the helm client returned with the status of the deployment
-		if val, ok := release.Status_Code_name[status]; ok {
-			log.Infof("deployment status: [%s]", val)
-			statusCode = http.StatusOK
-			msg = val
+		statusCode = http.StatusInternalServerError
+		if isDeploymentNotFound(err) {
+			statusCode = http.StatusNotFound
 		}
+		msg = errors.Wrap(err, "couldn't get the release status").Error()
+	} else {
+		log.Infof("deployment status: [%s]", status)
+		statusCode = http.StatusOK
+		msg = status
 	}
 
-	log.Infof("deployment status for [%s] is [%d]", name, status)
+	log.Infof("deployment status for [%s] is [%s]", name, status)
 	c.JSON(statusCode, pkgHelm.DeploymentStatusResponse{
 		Status:  statusCode,
 		Message: msg,
@@ -222,30 +359,24 @@ func HelmDeploymentStatus(c *gin.Context) {
 }
 
 // GetDeployment returns the details of a helm deployment
-func GetDeployment(c *gin.Context) {
+func (a *DeploymentAPI) GetDeployment(c *gin.Context) {
 	name := c.Param("name")
 	tag := c.Query("tag")
 	log.Infof("getting details for deployment: [%s]", name)
 
-	kubeConfig, ok := GetK8sConfig(c)
+	commonCluster, ok := getClusterFromRequest(c)
 
 	if !ok {
-		log.Errorf("could not get the k8s config for querying the details of deployment: [%s]", name)
+		log.Errorf("could not get the cluster for querying the details of deployment: [%s]", name)
 		return
 	}
 
-	deployment, err := helm.GetDeployment(name, kubeConfig)
-	if err == nil && tag != "" && !helm.DeploymentHasTag(deployment, tag) {
-		notFoundError := &helm.DeploymentNotFoundError{HelmError: fmt.Errorf("tag not found")}
-		err = notFoundError
-		for version := deployment.Version - 1; version > 0; version-- {
-			deployment, err = helm.GetDeploymentByVersion(name, kubeConfig, version)
-			if err != nil || helm.DeploymentHasTag(deployment, tag) {
-				break
-			} else {
-				err = notFoundError
-			}
-		}
+	var deployment *pkgHelm.GetDeploymentResponse
+	var err error
+	if tag != "" {
+		deployment, err = a.deployments.GetTaggedDeployment(c.Request.Context(), commonCluster.GetID(), name, tag)
+	} else {
+		deployment, err = a.deployments.GetDeployment(c.Request.Context(), commonCluster.GetID(), name)
 	}
 
 	if err == nil {
@@ -253,7 +384,7 @@ func GetDeployment(c *gin.Context) {
 	} else {
 
 		httpStatusCode := http.StatusInternalServerError
-		if _, ok := err.(*helm.DeploymentNotFoundError); ok {
+		if isDeploymentNotFound(err) {
 			httpStatusCode = http.StatusNotFound
 		} else {
 			log.Error("Error during getting deployment details: ", err.Error())
@@ -268,7 +399,7 @@ func GetDeployment(c *gin.Context) {
 }
 
 // GetDeploymentResources returns the resources of a helm deployment
-func GetDeploymentResources(c *gin.Context) {
+func (a *DeploymentAPI) GetDeploymentResources(c *gin.Context) {
 	name := c.Param("name")
 	log.Infof("getting resources for deployment: [%s]", name)
 
@@ -278,19 +409,19 @@ func GetDeploymentResources(c *gin.Context) {
 		resourceTypes = append(resourceTypes, strings.Split(resourceTypesStr, ",")...)
 	}
 
-	kubeConfig, ok := GetK8sConfig(c)
+	commonCluster, ok := getClusterFromRequest(c)
 
 	if !ok {
-		log.Errorf("could not get the k8s config for querying the resources of deployment: [%s]", name)
+		log.Errorf("could not get the cluster for querying the resources of deployment: [%s]", name)
 		return
 	}
 
-	deploymentResourcesResponse, err := helm.GetDeploymentK8sResources(name, kubeConfig, resourceTypes)
+	deploymentResourcesResponse, err := a.deployments.GetDeploymentResources(c.Request.Context(), commonCluster.GetID(), name, resourceTypes)
 	if err != nil {
 		log.Error("Error during getting deployment resources: ", err.Error())
 
 		httpStatusCode := http.StatusInternalServerError
-		if _, ok := err.(*helm.DeploymentNotFoundError); ok {
+		if isDeploymentNotFound(err) {
 			httpStatusCode = http.StatusNotFound
 		}
 
@@ -308,16 +439,16 @@ func GetDeploymentResources(c *gin.Context) {
 
 }
 
-// GetTillerStatus checks if tiller ready to accept deployments
-func GetTillerStatus(c *gin.Context) {
+// GetTillerStatus checks if tiller (or the helm backend of the cluster) ready to accept deployments
+func (a *DeploymentAPI) GetTillerStatus(c *gin.Context) {
 	name := c.Param("name")
 	log.Infof("Retrieving status for deployment: %s", name)
-	kubeConfig, ok := GetK8sConfig(c)
+	commonCluster, ok := getClusterFromRequest(c)
 	if ok != true {
 		return
 	}
 	// --- [ List deployments ] ---- //
-	_, err := helm.ListDeployments(nil, "", kubeConfig)
+	_, err := a.deployments.ListDeployments(c.Request.Context(), commonCluster.GetID(), "")
 	if err != nil {
 		message := "Error connecting to tiller"
 		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
@@ -335,8 +466,102 @@ func GetTillerStatus(c *gin.Context) {
 	return
 }
 
+// GetHelmBackend returns the helm backend of a cluster
+func GetHelmBackend(c *gin.Context) {
+	kubeConfig, ok := GetK8sConfig(c)
+	if !ok {
+		return
+	}
+
+	backend, err := helm.GetBackend(kubeConfig)
+	if err != nil {
+		log.Errorf("Error during getting helm backend: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error getting helm backend",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pkgHelm.BackendResponse{Backend: backend})
+}
+
+// UpdateHelmBackend switches the helm backend of a cluster, migrating the releases of the current backend
+func UpdateHelmBackend(c *gin.Context) {
+	var request pkgHelm.BackendRequest
+	if err := c.BindJSON(&request); err != nil {
+		log.Errorf("Error during binding helm backend request: %s", err.Error())
+		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error during parsing request!",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := request.Backend.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid helm backend",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	kubeConfig, ok := GetK8sConfig(c)
+	if !ok {
+		return
+	}
+
+	backend, err := helm.GetBackend(kubeConfig)
+	if err != nil {
+		log.Errorf("Error during getting helm backend: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error getting helm backend",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if backend == request.Backend {
+		c.JSON(http.StatusOK, pkgHelm.BackendResponse{Backend: backend})
+		return
+	}
+
+	switch {
+	case backend == pkgHelm.TillerBackend && request.Backend == pkgHelm.EmbeddedTillerBackend:
+		err = helm.MigrateToEmbeddedTiller(log, kubeConfig)
+
+	case backend != pkgHelm.Helm3Backend && request.Backend == pkgHelm.Helm3Backend:
+		err = helm.MigrateToHelm3(log, kubeConfig)
+
+	default:
+		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Only migrating from tiller to the embedded tiller backend and from both to the helm 3 backend is supported",
+			Error:   fmt.Sprintf("cannot migrate from %s to %s", backend, request.Backend),
+		})
+		return
+	}
+
+	if err != nil {
+		log.Errorf("Error during migrating to the %s helm backend: %s", request.Backend, err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: fmt.Sprintf("Error migrating to the %s helm backend", request.Backend),
+			Error:   err.Error(),
+		})
+		return
+	}
+	log.Infof("Migrating to the %s helm backend succeeded", request.Backend)
+
+	c.JSON(http.StatusOK, pkgHelm.BackendResponse{Backend: request.Backend})
+}
+
 // UpgradeDeployment - Upgrades helm deployment, if --reuse-value is specified reuses the last release's value.
-func UpgradeDeployment(c *gin.Context) {
+func (a *DeploymentAPI) UpgradeDeployment(c *gin.Context) {
 	name := c.Param("name")
 	log.Infof("Upgrading deployment: %s", name)
 	commonCluster, ok := getClusterFromRequest(c)
@@ -354,13 +579,36 @@ func UpgradeDeployment(c *gin.Context) {
 		return
 	}
 
-	release, err := helm.UpgradeDeployment(name, parsedRequest.deploymentName,
-		parsedRequest.deploymentVersion, parsedRequest.deploymentPackage, parsedRequest.values,
-		parsedRequest.reuseValues, parsedRequest.kubeConfig, helm.GenerateHelmRepoEnv(parsedRequest.organizationName))
+	if !a.prepareDeploymentValues(c, commonCluster.GetOrganizationId(), parsedRequest) {
+		return
+	}
+
+	if parsedRequest.dryRun {
+		a.previewDeploymentUpgrade(c, commonCluster.GetID(), name, parsedRequest)
+		return
+	}
+
+	release, err := a.deployments.UpgradeDeployment(
+		c.Request.Context(),
+		commonCluster.GetID(),
+		parsedRequest.deploymentName,
+		parsedRequest.deploymentVersion,
+		name,
+		parsedRequest.values,
+		internalHelm.ReleaseOptions{
+			ReuseValues:  parsedRequest.reuseValues,
+			ChartPackage: parsedRequest.deploymentPackage,
+		},
+	)
 	if err != nil {
+		httpStatusCode := http.StatusInternalServerError
+		if isDeploymentNotFound(err) {
+			httpStatusCode = http.StatusNotFound
+		}
+
 		log.Errorf("Error during upgrading deployment. %s", err.Error())
-		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
-			Code:    http.StatusInternalServerError,
+		c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+			Code:    httpStatusCode,
 			Message: "Error upgrading deployment",
 			Error:   err.Error(),
 		})
@@ -368,7 +616,7 @@ func UpgradeDeployment(c *gin.Context) {
 	}
 	log.Info("Upgrade deployment succeeded")
 
-	releaseNotes := base64.StdEncoding.EncodeToString([]byte(release.GetRelease().GetInfo().GetStatus().GetNotes()))
+	releaseNotes := base64.StdEncoding.EncodeToString([]byte(release.Notes))
 
 	log.Debug("Release notes: ", releaseNotes)
 	response := pkgHelm.CreateUpdateDeploymentResponse{
@@ -379,15 +627,328 @@ func UpgradeDeployment(c *gin.Context) {
 	return
 }
 
+// prepareDeploymentValues resolves the secret references in the values of a deployment request
+// and validates the values against the schema of the chart and the schema registered for it.
+// The unresolved references are stored with the release values, so that the deployment APIs
+// return the references instead of the resolved secret values.
+// It replies with an error and returns false if the values are invalid.
+func (a *DeploymentAPI) prepareDeploymentValues(c *gin.Context, organizationID uint, parsedRequest *parsedDeploymentRequest) bool {
+	values, references, err := helm.ResolveSecretReferences(parsedRequest.rawValues, organizationID, func(secretID string) (map[string]string, error) {
+		// the referenced secrets are read with the permissions of the caller
+		if err := authorizeSecret(c, a.authorizer, secretID); err != nil {
+			return nil, err
+		}
+
+		secretItem, err := a.secrets.Get(organizationID, secretID)
+		if err != nil {
+			return nil, err
+		}
+
+		return secretItem.Values, nil
+	})
+	if err != nil {
+		httpStatusCode := http.StatusInternalServerError
+		if _, ok := err.(*helm.SecretReferenceError); ok || errors.Cause(err) == secret.ErrSecretNotExists {
+			httpStatusCode = http.StatusBadRequest
+		} else if _, ok := errors.Cause(err).(secretAccessDeniedError); ok {
+			httpStatusCode = http.StatusForbidden
+		} else {
+			log.Errorf("Error during resolving secret references in deployment values. %s", err.Error())
+		}
+
+		c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+			Code:    httpStatusCode,
+			Message: "Error resolving secret references in values",
+			Error:   err.Error(),
+		})
+		return false
+	}
+
+	if values == nil {
+		return a.validateDeploymentValues(c, organizationID, parsedRequest)
+	}
+
+	parsedRequest.values, err = yaml.Marshal(values)
+	if err == nil {
+		if !a.validateDeploymentValues(c, organizationID, parsedRequest) {
+			return false
+		}
+
+		// the references are added after the validation, as the schemas don't know about them
+		parsedRequest.values, err = yaml.Marshal(helm.StoreSecretReferences(values, references, parsedRequest.reuseValues))
+	}
+	if err != nil {
+		log.Errorf("Error during marshaling deployment values. %s", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error resolving secret references in values",
+			Error:   err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+// validateDeploymentValues validates the values of a deployment request against the schema of the chart
+// and the schema registered for it.
+// It replies with an error and returns false if the values are invalid.
+func (a *DeploymentAPI) validateDeploymentValues(c *gin.Context, organizationID uint, parsedRequest *parsedDeploymentRequest) bool {
+	// the previous values of the release are not known here, so the merged values cannot be validated
+	if parsedRequest.reuseValues {
+		return true
+	}
+
+	var schemas [][]byte
+
+	schema, err := a.schemas.Get(c.Request.Context(), organizationID, parsedRequest.deploymentName)
+	if err == nil {
+		schemas = append(schemas, schema.Schema)
+	} else if _, ok := err.(internalHelm.ChartSchemaNotFoundError); !ok {
+		log.Errorf("Error during getting chart schema. %s", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error getting chart schema",
+			Error:   err.Error(),
+		})
+		return false
+	}
+
+	// the chart is loaded once and passed on as a package, so it is not downloaded again on install
+	if len(parsedRequest.deploymentPackage) == 0 {
+		chartPath, err := helm.DownloadChartFromRepo(parsedRequest.deploymentName, parsedRequest.deploymentVersion, helm.GenerateHelmRepoEnv(parsedRequest.organizationName))
+		if err == nil {
+			parsedRequest.deploymentPackage, err = ioutil.ReadFile(chartPath)
+		}
+		if err != nil {
+			log.Errorf("Error during downloading chart. %s", err.Error())
+			c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Error downloading chart",
+				Error:   err.Error(),
+			})
+			return false
+		}
+	}
+
+	requestedChart, err := helm.GetRequestedChart(parsedRequest.deploymentReleaseName, parsedRequest.deploymentName, parsedRequest.deploymentVersion, parsedRequest.deploymentPackage, helm.GenerateHelmRepoEnv(parsedRequest.organizationName))
+	if err != nil {
+		log.Errorf("Error during loading chart. %s", err.Error())
+		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error loading chart",
+			Error:   err.Error(),
+		})
+		return false
+	}
+
+	err = helm.ValidateValues(requestedChart, parsedRequest.values, schemas...)
+	if err != nil {
+		httpStatusCode := http.StatusInternalServerError
+		if _, ok := err.(*helm.ValuesValidationError); ok {
+			httpStatusCode = http.StatusBadRequest
+		} else {
+			log.Errorf("Error during validating deployment values. %s", err.Error())
+		}
+
+		c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+			Code:    httpStatusCode,
+			Message: "Invalid deployment values",
+			Error:   err.Error(),
+		})
+		return false
+	}
+
+	return true
+}
+
+// previewDeploymentUpgrade renders a deployment upgrade without applying it
+// and replies with the added, changed and removed resources
+func (a *DeploymentAPI) previewDeploymentUpgrade(c *gin.Context, clusterID uint, name string, parsedRequest *parsedDeploymentRequest) {
+	preview, err := a.deployments.PreviewDeploymentUpgrade(
+		c.Request.Context(),
+		clusterID,
+		parsedRequest.deploymentName,
+		parsedRequest.deploymentVersion,
+		name,
+		parsedRequest.values,
+		internalHelm.ReleaseOptions{
+			ReuseValues:  parsedRequest.reuseValues,
+			ChartPackage: parsedRequest.deploymentPackage,
+		},
+	)
+	if err != nil {
+		httpStatusCode := http.StatusInternalServerError
+		if isDeploymentNotFound(err) {
+			httpStatusCode = http.StatusNotFound
+		} else {
+			log.Errorf("Error during previewing deployment upgrade. %s", err.Error())
+		}
+
+		c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+			Code:    httpStatusCode,
+			Message: "Error previewing deployment upgrade",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// GetDeploymentHistory returns the revisions of a helm deployment
+func (a *DeploymentAPI) GetDeploymentHistory(c *gin.Context) {
+	name := c.Param("name")
+	log.Infof("getting history for deployment: [%s]", name)
+
+	commonCluster, ok := getClusterFromRequest(c)
+	if !ok {
+		log.Errorf("could not get the cluster for querying the history of deployment: [%s]", name)
+		return
+	}
+
+	revisions, err := a.deployments.GetDeploymentHistory(c.Request.Context(), commonCluster.GetID(), name)
+	if err != nil {
+		httpStatusCode := http.StatusInternalServerError
+		if isDeploymentNotFound(err) {
+			httpStatusCode = http.StatusNotFound
+		} else {
+			log.Error("Error during getting deployment history: ", err.Error())
+		}
+
+		c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+			Code:    httpStatusCode,
+			Message: "Error getting deployment history",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// GetDeploymentDiff returns the differences between two revisions of a helm deployment
+// The revisions are selected by the from and to query parameters
+// (defaulting to the previous and the current revision).
+func (a *DeploymentAPI) GetDeploymentDiff(c *gin.Context) {
+	name := c.Param("name")
+	log.Infof("getting diff for deployment: [%s]", name)
+
+	commonCluster, ok := getClusterFromRequest(c)
+	if !ok {
+		log.Errorf("could not get the cluster for querying the diff of deployment: [%s]", name)
+		return
+	}
+
+	fromVersion, toVersion, err := parseDeploymentDiffVersions(c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error during parsing request!",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	diff, err := a.deployments.GetDeploymentDiff(c.Request.Context(), commonCluster.GetID(), name, fromVersion, toVersion)
+	if err != nil {
+		replyDeploymentDiffError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+func parseDeploymentDiffVersions(from, to string) (fromVersion, toVersion int, err error) {
+	for _, v := range []struct {
+		value   string
+		version *int
+	}{{from, &fromVersion}, {to, &toVersion}} {
+		if v.value == "" {
+			continue
+		}
+
+		version, err := strconv.ParseInt(v.value, 10, 32)
+		if err != nil || version < 1 {
+			return 0, 0, fmt.Errorf("invalid deployment version: %s", v.value)
+		}
+
+		*v.version = int(version)
+	}
+
+	return fromVersion, toVersion, nil
+}
+
+func replyDeploymentDiffError(c *gin.Context, err error) {
+	httpStatusCode := http.StatusInternalServerError
+	if isDeploymentNotFound(err) {
+		httpStatusCode = http.StatusNotFound
+	} else {
+		log.Error("Error during getting deployment diff: ", err.Error())
+	}
+
+	c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+		Code:    httpStatusCode,
+		Message: "Error getting deployment diff",
+		Error:   err.Error(),
+	})
+}
+
+// RollbackDeployment rolls back a helm deployment to a previous revision
+func (a *DeploymentAPI) RollbackDeployment(c *gin.Context) {
+	name := c.Param("name")
+	log.Infof("Rolling back deployment: %s", name)
+
+	var request pkgHelm.RollbackDeploymentRequest
+	if err := c.BindJSON(&request); err != nil {
+		log.Errorf("Error during binding rollback request: %s", err.Error())
+		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error during parsing request!",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := getClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	release, err := a.deployments.RollbackDeployment(c.Request.Context(), commonCluster.GetID(), name, int(request.Version))
+	if err != nil {
+		httpStatusCode := http.StatusInternalServerError
+		if isDeploymentNotFound(err) {
+			httpStatusCode = http.StatusNotFound
+		}
+
+		log.Errorf("Error during rolling back deployment. %s", err.Error())
+		c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+			Code:    httpStatusCode,
+			Message: "Error rolling back deployment",
+			Error:   err.Error(),
+		})
+		return
+	}
+	log.Info("Rollback deployment succeeded")
+
+	releaseNotes := base64.StdEncoding.EncodeToString([]byte(release.Notes))
+
+	c.JSON(http.StatusOK, pkgHelm.CreateUpdateDeploymentResponse{
+		ReleaseName: name,
+		Notes:       releaseNotes,
+	})
+}
+
 // DeleteDeployment deletes a Helm deployment
-func DeleteDeployment(c *gin.Context) {
+func (a *DeploymentAPI) DeleteDeployment(c *gin.Context) {
 	name := c.Param("name")
 	log.Infof("Delete deployment: %s", name)
-	kubeConfig, ok := GetK8sConfig(c)
+	commonCluster, ok := getClusterFromRequest(c)
 	if ok != true {
 		return
 	}
-	err := helm.DeleteDeployment(name, kubeConfig)
+	err := a.deployments.DeleteDeployment(c.Request.Context(), commonCluster.GetID(), name)
 	if err != nil {
 		// error during delete deployment
 		log.Errorf("Error deleting deployment: %s", err.Error())
@@ -412,6 +973,7 @@ type parsedDeploymentRequest struct {
 	deploymentReleaseName string
 	reuseValues           bool
 	namespace             string
+	rawValues             map[string]interface{}
 	values                []byte
 	kubeConfig            []byte
 	organizationName      string
@@ -451,6 +1013,8 @@ func parseCreateUpdateDeploymentRequest(c *gin.Context, commonCluster cluster.Co
 	pdr.timeout = deployment.Timeout
 	pdr.odPcts = deployment.OdPcts
 
+	pdr.rawValues = deployment.Values
+
 	if deployment.Values != nil {
 		pdr.values, err = yaml.Marshal(deployment.Values)
 		if err != nil {
@@ -470,7 +1034,8 @@ func HelmReposGet(c *gin.Context) {
 
 	log.Info("Get helm repository")
 
-	response, err := helm.ReposGet(helm.GenerateHelmRepoEnv(auth.GetCurrentOrganization(c.Request).Name))
+	helmEnv := helm.GenerateHelmRepoEnv(auth.GetCurrentOrganization(c.Request).Name)
+	response, err := helm.ReposGet(helmEnv)
 	if err != nil {
 		log.Errorf("Error during get helm repo list: %s", err.Error())
 		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
@@ -480,16 +1045,64 @@ func HelmReposGet(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, response)
+	names := make([]string, 0, len(response))
+	for _, entry := range response {
+		names = append(names, entry.Name)
+	}
+
+	allowed, err := auth.FilterResourceIDs(c.Request.Context(), auth.GetCurrentOrganization(c.Request).ID, brn.HelmRepoResourceType, names)
+	if err != nil {
+		log.Errorf("Error during filtering helm repo list: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error listing helm repos",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	entries := make([]pkgHelm.Repository, 0, len(response))
+	for _, entry := range response {
+		if !allowed[entry.Name] {
+			continue
+		}
+
+		repository, err := redactHelmRepoEntry(helmEnv, entry)
+		if err != nil {
+			log.Errorf("Error during get helm repo secret: %s", err.Error())
+			c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Error listing helm repos",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		entries = append(entries, repository)
+	}
+
+	c.JSON(http.StatusOK, entries)
 	return
 }
 
+// HelmRepoAPI implements the Helm repository endpoints referencing secrets.
+type HelmRepoAPI struct {
+	authorizer SecretAuthorizer
+}
+
+// NewHelmRepoAPI returns a new HelmRepoAPI instance.
+func NewHelmRepoAPI(authorizer SecretAuthorizer) *HelmRepoAPI {
+	return &HelmRepoAPI{
+		authorizer: authorizer,
+	}
+}
+
 // HelmReposAdd add a new helm repository
-func HelmReposAdd(c *gin.Context) {
+func (a *HelmRepoAPI) HelmReposAdd(c *gin.Context) {
 	log.Info("Add helm repository")
 
-	var r *repo.Entry
-	err := c.BindJSON(&r)
+	var request pkgHelm.RepositoryRequest
+	err := c.BindJSON(&request)
 	if err != nil {
 		log.Errorf("Error parsing request: %s", err.Error())
 		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
@@ -500,8 +1113,31 @@ func HelmReposAdd(c *gin.Context) {
 		return
 	}
 
-	helmEnv := helm.GenerateHelmRepoEnv(auth.GetCurrentOrganization(c.Request).Name)
-	_, err = helm.ReposAdd(helmEnv, r)
+	organization := auth.GetCurrentOrganization(c.Request)
+	helmEnv := helm.GenerateHelmRepoEnv(organization.Name)
+
+	r := &repo.Entry{
+		Name: request.Name,
+		URL:  request.URL,
+	}
+
+	if request.SecretID != "" {
+		if !replyWithSecretAuthorization(c, a.authorizer, request.SecretID) {
+			return
+		}
+
+		if err := setHelmRepoCredentials(helmEnv, organization.ID, r, request.SecretID); err != nil {
+			log.Errorf("Error setting helm repo credentials: %s", err.Error())
+			c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Error setting helm repo credentials",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	added, err := helm.ReposAdd(helmEnv, r)
 	if err != nil {
 		log.Errorf("Error adding helm repo: %s", err.Error())
 		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
@@ -512,6 +1148,18 @@ func HelmReposAdd(c *gin.Context) {
 		return
 	}
 
+	if added && request.SecretID != "" {
+		if err := helm.SetRepositorySecret(helmEnv, r.Name, organization.ID, request.SecretID); err != nil {
+			log.Errorf("Error storing helm repo secret: %s", err.Error())
+			c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Error storing helm repo secret",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
 	sendResponseWithRepo(c, helmEnv, r.Name)
 
 	return
@@ -551,14 +1199,14 @@ func HelmReposDelete(c *gin.Context) {
 }
 
 // HelmReposModify modify the helm repository
-func HelmReposModify(c *gin.Context) {
+func (a *HelmRepoAPI) HelmReposModify(c *gin.Context) {
 	log.Info("modify helm repository")
 
 	repoName := c.Param("name")
 	log.Debugf("repoName: %s", repoName)
 
-	var newRepo *repo.Entry
-	err := c.BindJSON(&newRepo)
+	var request pkgHelm.RepositoryRequest
+	err := c.BindJSON(&request)
 	if err != nil {
 		log.Errorf("Error parsing request: %s", err.Error())
 		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
@@ -568,7 +1216,35 @@ func HelmReposModify(c *gin.Context) {
 		})
 		return
 	}
-	helmEnv := helm.GenerateHelmRepoEnv(auth.GetCurrentOrganization(c.Request).Name)
+
+	organization := auth.GetCurrentOrganization(c.Request)
+	helmEnv := helm.GenerateHelmRepoEnv(organization.Name)
+
+	newRepo := &repo.Entry{
+		Name: request.Name,
+		URL:  request.URL,
+	}
+
+	if request.SecretID != "" {
+		if !replyWithSecretAuthorization(c, a.authorizer, request.SecretID) {
+			return
+		}
+
+		if newRepo.Name == "" {
+			newRepo.Name = repoName
+		}
+
+		if err := setHelmRepoCredentials(helmEnv, organization.ID, newRepo, request.SecretID); err != nil {
+			log.Errorf("Error setting helm repo credentials: %s", err.Error())
+			c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "error setting helm repo credentials",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
 	errModify := helm.ReposModify(helmEnv, repoName, newRepo)
 	if errModify != nil {
 		if errModify == helm.ErrRepoNotFound {
@@ -589,6 +1265,18 @@ func HelmReposModify(c *gin.Context) {
 		return
 	}
 
+	if request.SecretID != "" {
+		if err := helm.SetRepositorySecret(helmEnv, newRepo.Name, organization.ID, request.SecretID); err != nil {
+			log.Errorf("Error storing helm repo secret: %s", err.Error())
+			c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "error storing helm repo secret",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
 	sendResponseWithRepo(c, helmEnv, newRepo.Name)
 
 	return
@@ -645,7 +1333,31 @@ func HelmCharts(c *gin.Context) {
 		})
 		return
 	}
-	c.JSON(http.StatusOK, response)
+
+	repoNames := make([]string, 0, len(response))
+	for _, chartList := range response {
+		repoNames = append(repoNames, chartList.Name)
+	}
+
+	allowed, err := auth.FilterResourceIDs(c.Request.Context(), auth.GetCurrentOrganization(c.Request).ID, brn.HelmRepoResourceType, repoNames)
+	if err != nil {
+		log.Error("Error during filtering helm repo chart list.", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error listing helm repo charts",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	charts := make([]helm.ChartList, 0, len(response))
+	for _, chartList := range response {
+		if allowed[chartList.Name] {
+			charts = append(charts, chartList)
+		}
+	}
+
+	c.JSON(http.StatusOK, charts)
 	return
 }
 
@@ -702,7 +1414,18 @@ func sendResponseWithRepo(c *gin.Context, helmEnv environment.EnvSettings, repoN
 
 	for _, entry := range entries {
 		if entry.Name == repoName {
-			c.JSON(http.StatusOK, entry)
+			repository, err := redactHelmRepoEntry(helmEnv, entry)
+			if err != nil {
+				log.Errorf("Error during getting helm repo secret: %s", err.Error())
+				c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+					Code:    http.StatusInternalServerError,
+					Message: "Error during getting helm repo",
+					Error:   err.Error(),
+				})
+				return
+			}
+
+			c.JSON(http.StatusOK, repository)
 			return
 		}
 	}
@@ -713,8 +1436,44 @@ func sendResponseWithRepo(c *gin.Context, helmEnv environment.EnvSettings, repoN
 	})
 }
 
+// setHelmRepoCredentials sets the credentials of a helm repository from a secret
+// to check that the secret can be used to access the repository.
+// Only the reference of the secret is stored, the credentials are read from it when the repository is accessed.
+func setHelmRepoCredentials(helmEnv environment.EnvSettings, organizationID uint, entry *repo.Entry, secretID string) error {
+	repoSecret, err := secret.Store.Get(organizationID, secretID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get repository secret")
+	}
+
+	credentials, err := helm.RepositoryCredentialsFromSecret(repoSecret.Type, repoSecret.Values)
+	if err != nil {
+		return err
+	}
+
+	return helm.SetRepositoryCredentials(helmEnv, entry, credentials)
+}
+
+// redactHelmRepoEntry removes the password from a helm repository before it's returned to the client
+// and adds the ID of the secret holding its credentials
+func redactHelmRepoEntry(helmEnv environment.EnvSettings, entry *repo.Entry) (pkgHelm.Repository, error) {
+	redacted := *entry
+	if redacted.Password != "" {
+		redacted.Password = "<redacted>"
+	}
+
+	secretID, err := helm.GetRepositorySecretID(helmEnv, entry.Name)
+	if err != nil {
+		return pkgHelm.Repository{}, err
+	}
+
+	return pkgHelm.Repository{
+		Entry:    &redacted,
+		SecretID: secretID,
+	}, nil
+}
+
 // ListHelmReleases list helm releases
-func ListHelmReleases(c *gin.Context, response *rls.ListReleasesResponse, optparam interface{}) []pkgHelm.ListDeploymentResponse {
+func ListHelmReleases(c *gin.Context, response []internalHelm.Release, optparam interface{}) []pkgHelm.ListDeploymentResponse {
 
 	// Get WhiteList set
 	releaseWhitelist, ok := GetWhitelistSet(c)
@@ -727,28 +1486,24 @@ func ListHelmReleases(c *gin.Context, response *rls.ListReleasesResponse, optpar
 	}
 
 	releases := make([]pkgHelm.ListDeploymentResponse, 0)
-	if response != nil && len(response.Releases) > 0 {
-		for _, r := range response.Releases {
-
-			createdAt := time.Unix(r.Info.FirstDeployed.Seconds, 0)
-			updated := time.Unix(r.Info.LastDeployed.Seconds, 0)
-			chartName := r.GetChart().GetMetadata().GetName()
+	if len(response) > 0 {
+		for _, r := range response {
 
 			body := pkgHelm.ListDeploymentResponse{
 				Name:         r.Name,
-				Chart:        helm.GetVersionedChartName(r.Chart.Metadata.Name, r.Chart.Metadata.Version),
-				ChartName:    chartName,
-				ChartVersion: r.GetChart().GetMetadata().GetVersion(),
-				Version:      r.Version,
-				UpdatedAt:    updated,
-				Status:       r.Info.Status.Code.String(),
+				Chart:        helm.GetVersionedChartName(r.ChartName, r.ChartVersion),
+				ChartName:    r.ChartName,
+				ChartVersion: r.ChartVersion,
+				Version:      int32(r.Version),
+				UpdatedAt:    r.LastDeployed,
+				Status:       r.Status,
 				Namespace:    r.Namespace,
-				CreatedAt:    createdAt,
+				CreatedAt:    r.FirstDeployed,
 			}
 			optparamType := fmt.Sprintf("%T", optparam)
 			if optparamType == "map[string]repo.ChartVersions" {
 				supportedCharts := optparam.(map[string]repo.ChartVersions)
-				body.Supported = supportedCharts[chartName] != nil
+				body.Supported = supportedCharts[r.ChartName] != nil
 			}
 			// Add WhiteListed flag if present
 			if _, ok := releaseWhitelist[r.Name]; ok {
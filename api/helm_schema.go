@@ -0,0 +1,166 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/helm"
+	internalHelm "github.com/banzaicloud/pipeline/internal/helm"
+	pkgCommmon "github.com/banzaicloud/pipeline/pkg/common"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+// ChartSchemaAPI implements the endpoints of the values schemas registered for charts.
+type ChartSchemaAPI struct {
+	schemas internalHelm.ChartSchemaStore
+}
+
+// NewChartSchemaAPI returns a new ChartSchemaAPI instance.
+func NewChartSchemaAPI(schemas internalHelm.ChartSchemaStore) *ChartSchemaAPI {
+	return &ChartSchemaAPI{
+		schemas: schemas,
+	}
+}
+
+// ListChartSchemas lists the values schemas registered by an organization
+func (a *ChartSchemaAPI) ListChartSchemas(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	schemas, err := a.schemas.List(c.Request.Context(), organizationID)
+	if err != nil {
+		log.Errorf("Error during listing chart schemas: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error listing chart schemas",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response := make([]pkgHelm.ChartSchemaResponse, 0, len(schemas))
+	for _, schema := range schemas {
+		response = append(response, chartSchemaResponse(schema))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetChartSchema returns the values schema registered for a chart
+func (a *ChartSchemaAPI) GetChartSchema(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	schema, err := a.schemas.Get(c.Request.Context(), organizationID, chartSchemaChart(c))
+	if err != nil {
+		httpStatusCode := http.StatusInternalServerError
+		if _, ok := err.(internalHelm.ChartSchemaNotFoundError); ok {
+			httpStatusCode = http.StatusNotFound
+		} else {
+			log.Errorf("Error during getting chart schema: %s", err.Error())
+		}
+
+		c.JSON(httpStatusCode, pkgCommmon.ErrorResponse{
+			Code:    httpStatusCode,
+			Message: "Error getting chart schema",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, chartSchemaResponse(schema))
+}
+
+// SetChartSchema registers the values schema of a chart
+// Deployment values of the chart are validated against the schema in addition to the schema shipped with the chart.
+func (a *ChartSchemaAPI) SetChartSchema(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	var request pkgHelm.ChartSchemaRequest
+	if err := c.BindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := helm.ValidateValuesSchema(request.Schema); err != nil {
+		c.JSON(http.StatusBadRequest, pkgCommmon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid chart schema",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	chart := chartSchemaChart(c)
+
+	if err := a.schemas.Set(c.Request.Context(), organizationID, chart, request.Schema); err != nil {
+		log.Errorf("Error during registering chart schema: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error registering chart schema",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	schema, err := a.schemas.Get(c.Request.Context(), organizationID, chart)
+	if err != nil {
+		log.Errorf("Error during getting chart schema: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error getting chart schema",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, chartSchemaResponse(schema))
+}
+
+// DeleteChartSchema removes the values schema registered for a chart
+func (a *ChartSchemaAPI) DeleteChartSchema(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	if err := a.schemas.Delete(c.Request.Context(), organizationID, chartSchemaChart(c)); err != nil {
+		log.Errorf("Error during deleting chart schema: %s", err.Error())
+		c.JSON(http.StatusInternalServerError, pkgCommmon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error deleting chart schema",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// chartSchemaChart returns the chart reference (repository/name) of a request
+func chartSchemaChart(c *gin.Context) string {
+	return c.Param("reponame") + "/" + c.Param("name")
+}
+
+func chartSchemaResponse(schema internalHelm.ChartSchema) pkgHelm.ChartSchemaResponse {
+	return pkgHelm.ChartSchemaResponse{
+		Chart:     schema.Chart,
+		Schema:    schema.Schema,
+		UpdatedAt: schema.UpdatedAt,
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/.gen/pipeline/pipeline"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// ListSecretVersions returns the versions of a secret
+func ListSecretVersions(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	secretID := getSecretID(c)
+
+	versions, err := secret.RestrictedStore.ListVersions(organizationID, secretID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == secret.ErrSecretNotExists {
+			statusCode = http.StatusNotFound
+		}
+		log.Errorf("Error during listing secret versions: %s", err.Error())
+		c.AbortWithStatusJSON(statusCode, common.ErrorResponse{
+			Code:    statusCode,
+			Message: "Error during listing secret versions",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetSecretVersion returns a specific version of a secret
+func GetSecretVersion(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	secretID := getSecretID(c)
+
+	version, ok := getSecretVersion(c)
+	if !ok {
+		return
+	}
+
+	s, err := secret.RestrictedStore.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if err == secret.ErrSecretVersionNotExists {
+			statusCode = http.StatusNotFound
+		}
+		log.Errorf("Error during getting secret version: %s", err.Error())
+		c.AbortWithStatusJSON(statusCode, common.ErrorResponse{
+			Code:    statusCode,
+			Message: "Error during getting secret version",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s)
+}
+
+// RollbackSecret restores a previous version of a secret as its latest version
+func RollbackSecret(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+
+	secretID := getSecretID(c)
+
+	version, ok := getSecretVersion(c)
+	if !ok {
+		return
+	}
+
+	err := secret.RestrictedStore.Rollback(organizationID, secretID, version, auth.GetCurrentUser(c.Request).Login)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch err.(type) {
+		case secret.ReadOnlyError, secret.ForbiddenError:
+			statusCode = http.StatusBadRequest
+		}
+		if err == secret.ErrSecretNotExists || err == secret.ErrSecretVersionNotExists {
+			statusCode = http.StatusNotFound
+		} else if secret.IsCASError(err) {
+			statusCode = http.StatusBadRequest
+		}
+		log.Errorf("Error during secret rollback: %s", err.Error())
+		c.AbortWithStatusJSON(statusCode, common.ErrorResponse{
+			Code:    statusCode,
+			Message: "Error during secret rollback",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	s, err := secret.RestrictedStore.Get(organizationID, secretID)
+	if err != nil {
+		log.Errorf("error during getting secret: %s", err.Error())
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error during getting secret",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pipeline.CreateSecretResponse{
+		Name:      s.Name,
+		Type:      s.Type,
+		Id:        secretID,
+		UpdatedAt: s.UpdatedAt,
+		UpdatedBy: s.UpdatedBy,
+		Version:   int32(s.Version),
+		Tags:      s.Tags,
+	})
+}
+
+func getSecretVersion(c *gin.Context) (int, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid secret version",
+			Error:   "version must be a positive integer",
+		})
+		return 0, false
+	}
+
+	return version, true
+}
@@ -28,8 +28,10 @@ import (
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/cluster"
 	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	"github.com/banzaicloud/pipeline/pkg/brn"
 	"github.com/banzaicloud/pipeline/pkg/common"
 	"github.com/banzaicloud/pipeline/pkg/providers"
 	"github.com/banzaicloud/pipeline/secret"
@@ -327,12 +329,41 @@ func ListSecrets(c *gin.Context) {
 				Message: "Error during listing secrets",
 				Error:   err.Error(),
 			})
+		} else if secrets, err = filterSecrets(c.Request.Context(), organizationID, secrets); err != nil {
+			log.Errorf("Error during filtering secrets: %s", err.Error())
+			c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Error during filtering secrets",
+				Error:   err.Error(),
+			})
 		} else {
 			c.JSON(http.StatusOK, secrets)
 		}
 	}
 }
 
+// filterSecrets removes the secrets from a list that the current request may not read according to the resource policies.
+func filterSecrets(ctx context.Context, organizationID uint, secrets []*secret.SecretItemResponse) ([]*secret.SecretItemResponse, error) {
+	ids := make([]string, 0, len(secrets))
+	for _, s := range secrets {
+		ids = append(ids, s.ID)
+	}
+
+	allowed, err := auth.FilterResourceIDs(ctx, organizationID, brn.SecretResourceType, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*secret.SecretItemResponse, 0, len(secrets))
+	for _, s := range secrets {
+		if allowed[s.ID] {
+			filtered = append(filtered, s)
+		}
+	}
+
+	return filtered, nil
+}
+
 // GetSecret returns a secret by ID
 func GetSecret(c *gin.Context) {
 
@@ -352,8 +383,27 @@ func GetSecret(c *gin.Context) {
 	}
 }
 
+// SecretUsageLister lists the objects referencing a secret.
+type SecretUsageLister interface {
+	// ListUsages returns every object referencing a secret.
+	ListUsages(ctx context.Context, secretID string) ([]secretusage.Usage, error)
+}
+
+// SecretAPI implements the secret API actions that depend on other services.
+type SecretAPI struct {
+	usages SecretUsageLister
+}
+
+// NewSecretAPI returns a new SecretAPI instance.
+func NewSecretAPI(usages SecretUsageLister) *SecretAPI {
+	return &SecretAPI{
+		usages: usages,
+	}
+}
+
 // DeleteSecrets delete a secret with the given secret id
-func DeleteSecrets(c *gin.Context) {
+// Secrets still referenced by other objects are only deleted when the force query parameter is set.
+func (a *SecretAPI) DeleteSecrets(c *gin.Context) {
 	log.Info("Start deleting secrets")
 
 	log.Info("Get organization id from params")
@@ -362,6 +412,8 @@ func DeleteSecrets(c *gin.Context) {
 
 	secretID := getSecretID(c)
 
+	force, _ := strconv.ParseBool(c.Query("force"))
+
 	log.Infof("Check clusters before delete secret[%s]", secretID)
 	if err := checkClustersBeforeDelete(organizationID, secretID); err != nil {
 		log.Errorf("Cluster found with this secret[%s]: %s", secretID, err.Error())
@@ -370,7 +422,33 @@ func DeleteSecrets(c *gin.Context) {
 			Message: fmt.Sprintf("Cluster found with this secret[%s]", secretID),
 			Error:   err.Error(),
 		})
-	} else if err := secret.RestrictedStore.Delete(organizationID, secretID); err != nil {
+		return
+	}
+
+	if !force {
+		usages, err := a.usages.ListUsages(c.Request.Context(), secretID)
+		if err != nil {
+			log.Errorf("Error during listing secret usages: %s", err.Error())
+			c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+				Code:    http.StatusInternalServerError,
+				Message: "Error during listing secret usages",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if len(usages) > 0 {
+			log.Infof("Secret[%s] is still in use by %d object(s)", secretID, len(usages))
+			c.AbortWithStatusJSON(http.StatusConflict, common.ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: fmt.Sprintf("Secret[%s] is still in use, delete it with force=true to ignore usages", secretID),
+				Error:   formatSecretUsages(usages),
+			})
+			return
+		}
+	}
+
+	if err := secret.RestrictedStore.Delete(organizationID, secretID); err != nil {
 		log.Errorf("Error during deleting secrets: %s", err.Error())
 		code := http.StatusInternalServerError
 		resp := common.ErrorResponse{
@@ -385,6 +463,15 @@ func DeleteSecrets(c *gin.Context) {
 	}
 }
 
+func formatSecretUsages(usages []secretusage.Usage) string {
+	items := make([]string, 0, len(usages))
+	for _, usage := range usages {
+		items = append(items, fmt.Sprintf("%s %s[%s]", usage.Kind, usage.Name, usage.ID))
+	}
+
+	return "secret is used by: " + strings.Join(items, ", ")
+}
+
 // GetSecretTags returns tags of a secret by ID
 func GetSecretTags(c *gin.Context) {
 	organizationID := auth.GetCurrentOrganization(c.Request).ID
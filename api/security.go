@@ -29,7 +29,6 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 
 	apiCommon "github.com/banzaicloud/pipeline/api/common"
-	"github.com/banzaicloud/pipeline/helm"
 	internalCommon "github.com/banzaicloud/pipeline/internal/common"
 	anchore "github.com/banzaicloud/pipeline/internal/security"
 	"github.com/banzaicloud/pipeline/pkg/common"
@@ -72,7 +71,7 @@ func getSecurityClient(c *gin.Context) *clientV1alpha1.SecurityV1Alpha1Client {
 }
 
 // GetImageDeployments list deployments by image
-func GetImageDeployments(c *gin.Context) {
+func (a *DeploymentAPI) GetImageDeployments(c *gin.Context) {
 	imageDigest := c.Param("imageDigest")
 	releaseMap := make(map[string]bool)
 
@@ -88,14 +87,25 @@ func GetImageDeployments(c *gin.Context) {
 		return
 	}
 
-	kubeConfig, ok := GetK8sConfig(c)
+	commonCluster, ok := getClusterFromRequest(c)
 	if !ok {
 		return
 	}
 
+	kubeConfig, err := commonCluster.GetK8sConfig()
+	if err != nil {
+		log.Errorf("Error getting K8s config: %s", err.Error())
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error getting kubeconfig",
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	// Get active helm deployments
 	log.Info("Get deployments")
-	activeReleases, err := helm.ListDeployments(nil, c.Query("tag"), kubeConfig)
+	activeReleases, err := a.deployments.ListDeployments(c.Request.Context(), commonCluster.GetID(), c.Query("tag"))
 	if err != nil {
 		log.Error("Error listing deployments: ", err.Error())
 		c.JSON(http.StatusBadRequest, common.ErrorResponse{
@@ -198,6 +198,7 @@ func Init(db *gorm.DB, signingKey string, tokenStore bauth.TokenStore, tokenMana
 				ID:      uint(userID),
 				Login:   claims.Text, // This is needed for CICD virtual user tokens
 				Virtual: claims.Type == ginauth.TokenType(CICDHookTokenType),
+				TokenID: claims.Id,
 			}
 		},
 		func(ctx context.Context, value interface{}) context.Context {
@@ -23,12 +23,15 @@ import (
 
 	"emperror.dev/errors"
 	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
 )
 
 // RbacEnforcer makes authorization decisions based on user roles.
 type RbacEnforcer struct {
-	roleSource RoleSource
-	logger     Logger
+	roleSource       RoleSource
+	permissionSource PermissionSource
+	logger           Logger
 }
 
 // RoleSource returns the user's role in a given organization.
@@ -38,10 +41,17 @@ type RoleSource interface {
 	FindUserRole(ctx context.Context, organizationID uint, userID uint) (string, bool, error)
 }
 
+// PermissionSource returns the permissions granted to a user by custom roles.
+type PermissionSource interface {
+	// FindUserPermissions returns the permissions granted to a user by custom roles in a given organization.
+	FindUserPermissions(ctx context.Context, organizationID uint, userID uint) ([]role.Permission, error)
+}
+
 // NewRbacEnforcer returns a new RbacEnforcer.
-func NewRbacEnforcer(roleSource RoleSource, logger Logger) RbacEnforcer {
+func NewRbacEnforcer(roleSource RoleSource, permissionSource PermissionSource, logger Logger) RbacEnforcer {
 	return RbacEnforcer{
-		roleSource: roleSource,
+		roleSource:       roleSource,
+		permissionSource: permissionSource,
 
 		logger: logger,
 	}
@@ -87,7 +97,7 @@ func (e RbacEnforcer) Enforce(org *Organization, user *User, path, method string
 		return org.Name == orgName, nil
 	}
 
-	role, member, err := e.roleSource.FindUserRole(context.Background(), org.ID, user.ID)
+	userRole, member, err := e.roleSource.FindUserRole(context.Background(), org.ID, user.ID)
 	if err != nil {
 		return false, errors.WrapIfWithDetails(
 			err, "failed to check user organization membership",
@@ -105,31 +115,120 @@ func (e RbacEnforcer) Enforce(org *Organization, user *User, path, method string
 		return false, nil
 	}
 
-	switch role {
+	switch userRole {
 	case RoleAdmin:
 		return true, nil
 	case RoleMember:
-		// Members can only read organization resources
-		if ok, err := regexp.MatchString(`^/api/v1/orgs(?:/.*)?$`, path); err != nil || (ok && method != http.MethodGet && method != http.MethodHead) {
-			return false, nil
-		}
-
-		// Members cannot access secrets at all
-		if ok, err := regexp.MatchString(`^/api/v1/orgs/.+/secrets(?:/.*)?$`, path); err != nil || ok {
-			return false, errors.WithStackIf(err)
+		granted, err := enforceMember(path, method)
+		if err != nil || granted {
+			return granted, err
 		}
 
-		return true, nil
+		// Custom roles can grant further permissions to members
+		return e.enforceCustomRoles(org, user, path, method)
 	default:
 		return false, errors.NewWithDetails(
 			"unknown membership role",
 			"userId", user.ID,
 			"organizationId", org.ID,
-			"role", role,
+			"role", userRole,
+			"method", method,
+			"path", path,
+		)
+	}
+}
+
+func enforceMember(path, method string) (bool, error) {
+	// Members can only read organization resources
+	if ok, err := regexp.MatchString(`^/api/v1/orgs(?:/.*)?$`, path); err != nil || (ok && method != http.MethodGet && method != http.MethodHead) {
+		return false, nil
+	}
+
+	// Members cannot access secrets at all
+	if ok, err := regexp.MatchString(`^/api/v1/orgs/.+/secrets(?:/.*)?$`, path); err != nil || ok {
+		return false, errors.WithStackIf(err)
+	}
+
+	// Members cannot access the audit log
+	if ok, err := regexp.MatchString(`^/api/v1/orgs/[^/]+/audit(?:/.*)?$`, path); err != nil || ok {
+		return false, errors.WithStackIf(err)
+	}
+
+	return true, nil
+}
+
+func (e RbacEnforcer) enforceCustomRoles(org *Organization, user *User, path, method string) (bool, error) {
+	if e.permissionSource == nil {
+		return false, nil
+	}
+
+	resource := resourceFromPath(path)
+	if resource == "" {
+		return false, nil
+	}
+
+	verb := VerbFromMethod(method)
+	if verb == "" {
+		return false, nil
+	}
+
+	permissions, err := e.permissionSource.FindUserPermissions(context.Background(), org.ID, user.ID)
+	if err != nil {
+		return false, errors.WrapIfWithDetails(
+			err, "failed to find custom role permissions",
 			"method", method,
 			"path", path,
 		)
 	}
+
+	return role.Allows(permissions, resource, verb), nil
+}
+
+// nolint: gochecknoglobals
+var orgResourcePathRegexp = regexp.MustCompile(`^/api/v1/orgs/[^/]+/([^/]+)(?:/[^/]+/([^/]+))?`)
+
+// resourceFromPath returns the custom role resource type an organization path belongs to.
+// It returns an empty string for paths that cannot be granted by custom roles.
+func resourceFromPath(path string) string {
+	matches := orgResourcePathRegexp.FindStringSubmatch(path)
+	if matches == nil {
+		return ""
+	}
+
+	switch matches[1] {
+	case "secrets":
+		return role.ResourceSecrets
+	case "backups", "backupbuckets":
+		return role.ResourceBackups
+	case "clusters":
+		switch matches[2] {
+		case "deployments":
+			return role.ResourceDeployments
+		case "backups", "backupservice", "restores", "schedules":
+			return role.ResourceBackups
+		default:
+			return role.ResourceClusters
+		}
+	default:
+		return ""
+	}
+}
+
+// VerbFromMethod returns the authorization verb corresponding to an HTTP method.
+// It returns an empty string for methods that cannot be mapped to a verb.
+func VerbFromMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return role.VerbGet
+	case http.MethodPost:
+		return role.VerbCreate
+	case http.MethodPut, http.MethodPatch:
+		return role.VerbUpdate
+	case http.MethodDelete:
+		return role.VerbDelete
+	default:
+		return ""
+	}
 }
 
 // Authorizer checks if a context has permission to execute an action.
@@ -148,7 +247,8 @@ func NewAuthorizer(db *gorm.DB, roleSource RoleSource) Authorizer {
 
 // Authorize authorizes a context to execute an action on an object.
 func (a Authorizer) Authorize(ctx context.Context, action string, object interface{}) (bool, error) {
-	if action == "virtualUser.create" {
+	switch action {
+	case "virtualUser.create":
 		orgName, ok := object.(string)
 		if !ok {
 			return false, errors.NewWithDetails("invalid object for action", "action", action, "object", object)
@@ -167,15 +267,33 @@ func (a Authorizer) Authorize(ctx context.Context, action string, object interfa
 			return false, errors.New("user not found in the context")
 		}
 
-		role, member, err := a.roleSource.FindUserRole(ctx, organization.ID, userID)
+		userRole, member, err := a.roleSource.FindUserRole(ctx, organization.ID, userID)
 		if err != nil {
 			return false, errors.WithMessage(err, "failed to query organization membership for virtual user")
 		}
 
 		// TODO: implement better authorization here
-		if !member || role != RoleAdmin {
+		if !member || userRole != RoleAdmin {
 			return false, nil
 		}
+
+	case "token.scope":
+		orgID, ok := object.(uint)
+		if !ok {
+			return false, errors.NewWithDetails("invalid object for action", "action", action, "object", object)
+		}
+
+		userID, ok := UserExtractor{}.GetUserID(ctx)
+		if !ok {
+			return false, errors.New("user not found in the context")
+		}
+
+		_, member, err := a.roleSource.FindUserRole(ctx, orgID, userID)
+		if err != nil {
+			return false, errors.WithMessage(err, "failed to query organization membership for token scope")
+		}
+
+		return member, nil
 	}
 
 	return true, nil
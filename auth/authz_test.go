@@ -21,13 +21,15 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
 	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
 )
 
 //go:generate mockery -name RoleSource -inpkg -testonly
+//go:generate mockery -name PermissionSource -inpkg -testonly
 
 func TestRbacEnforcer_Enforce_NoOrgIsAllowed(t *testing.T) {
-	enforcer := NewRbacEnforcer(nil, commonadapter.NewNoopLogger())
+	enforcer := NewRbacEnforcer(nil, nil, commonadapter.NewNoopLogger())
 
 	ok, err := enforcer.Enforce(nil, &User{}, "/", "GET")
 	require.NoError(t, err)
@@ -36,7 +38,7 @@ func TestRbacEnforcer_Enforce_NoOrgIsAllowed(t *testing.T) {
 }
 
 func TestRbacEnforcer_Enforce_NoUserIsNotAllowed(t *testing.T) {
-	enforcer := NewRbacEnforcer(nil, commonadapter.NewNoopLogger())
+	enforcer := NewRbacEnforcer(nil, nil, commonadapter.NewNoopLogger())
 
 	ok, err := enforcer.Enforce(&Organization{}, nil, "/", "GET")
 	require.NoError(t, err)
@@ -77,7 +79,7 @@ func TestRbacEnforcer_Enforce_VirtualUser(t *testing.T) {
 		test := test
 
 		t.Run("", func(t *testing.T) {
-			enforcer := NewRbacEnforcer(nil, commonadapter.NewNoopLogger())
+			enforcer := NewRbacEnforcer(nil, nil, commonadapter.NewNoopLogger())
 
 			ok, err := enforcer.Enforce(&test.organization, &test.user, "/", "GET")
 			require.NoError(t, err)
@@ -120,7 +122,7 @@ func TestRbacEnforcer_Enforce_VirtualUser_Invalid(t *testing.T) {
 		test := test
 
 		t.Run("", func(t *testing.T) {
-			enforcer := NewRbacEnforcer(nil, commonadapter.NewNoopLogger())
+			enforcer := NewRbacEnforcer(nil, nil, commonadapter.NewNoopLogger())
 
 			ok, err := enforcer.Enforce(&test.organization, &test.user, "/", "GET")
 			if test.error {
@@ -146,7 +148,7 @@ func TestRbacEnforcer_Enforce_NotAMember(t *testing.T) {
 	roleSource := &MockRoleSource{}
 	roleSource.On("FindUserRole", mock.Anything, org.ID, user.ID).Return("", false, nil)
 
-	enforcer := NewRbacEnforcer(roleSource, commonadapter.NewNoopLogger())
+	enforcer := NewRbacEnforcer(roleSource, nil, commonadapter.NewNoopLogger())
 
 	ok, err := enforcer.Enforce(&org, &user, "/", "GET")
 	require.NoError(t, err)
@@ -231,6 +233,18 @@ func TestRbacEnforcer_Enforce(t *testing.T) {
 			method:   "POST",
 			expected: false,
 		},
+		{
+			role:     RoleAdmin,
+			path:     "/api/v1/orgs/1/audit",
+			method:   "GET",
+			expected: true,
+		},
+		{
+			role:     RoleMember,
+			path:     "/api/v1/orgs/1/audit/export",
+			method:   "GET",
+			expected: false,
+		},
 	}
 
 	for _, test := range tests {
@@ -240,7 +254,105 @@ func TestRbacEnforcer_Enforce(t *testing.T) {
 			roleSource := &MockRoleSource{}
 			roleSource.On("FindUserRole", mock.Anything, org.ID, user.ID).Return(test.role, true, nil)
 
-			enforcer := NewRbacEnforcer(roleSource, commonadapter.NewNoopLogger())
+			enforcer := NewRbacEnforcer(roleSource, nil, commonadapter.NewNoopLogger())
+
+			ok, err := enforcer.Enforce(&org, &user, test.path, test.method)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, ok)
+		})
+	}
+}
+
+func TestRbacEnforcer_Enforce_CustomRoles(t *testing.T) {
+	org := Organization{
+		ID:   1,
+		Name: "example",
+	}
+
+	user := User{
+		ID:    1,
+		Login: "john.doe",
+	}
+
+	permissions := []role.Permission{
+		{
+			Resource: role.ResourceClusters,
+			Verbs:    []string{role.VerbCreate, role.VerbUpdate},
+		},
+		{
+			Resource: role.ResourceSecrets,
+			Verbs:    []string{role.VerbGet},
+		},
+		{
+			Resource: role.ResourceDeployments,
+			Verbs:    []string{role.VerbAll},
+		},
+	}
+
+	tests := []struct {
+		path     string
+		method   string
+		expected bool
+	}{
+		{
+			path:     "/api/v1/orgs/1/clusters",
+			method:   "GET",
+			expected: true,
+		},
+		{
+			path:     "/api/v1/orgs/1/clusters",
+			method:   "POST",
+			expected: true,
+		},
+		{
+			path:     "/api/v1/orgs/1/clusters/1",
+			method:   "DELETE",
+			expected: false,
+		},
+		{
+			path:     "/api/v1/orgs/1/secrets/secretID",
+			method:   "GET",
+			expected: true,
+		},
+		{
+			path:     "/api/v1/orgs/1/secrets/secretID",
+			method:   "DELETE",
+			expected: false,
+		},
+		{
+			path:     "/api/v1/orgs/1/clusters/1/deployments/release",
+			method:   "DELETE",
+			expected: true,
+		},
+		{
+			path:     "/api/v1/orgs/1/clusters/1/backups",
+			method:   "POST",
+			expected: false,
+		},
+		{
+			path:     "/api/v1/orgs/1/buckets",
+			method:   "POST",
+			expected: false,
+		},
+		{
+			path:     "/api/v1/orgs/1/roles",
+			method:   "POST",
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			roleSource := &MockRoleSource{}
+			roleSource.On("FindUserRole", mock.Anything, org.ID, user.ID).Return(RoleMember, true, nil)
+
+			permissionSource := &MockPermissionSource{}
+			permissionSource.On("FindUserPermissions", mock.Anything, org.ID, user.ID).Return(permissions, nil)
+
+			enforcer := NewRbacEnforcer(roleSource, permissionSource, commonadapter.NewNoopLogger())
 
 			ok, err := enforcer.Enforce(&org, &user, test.path, test.method)
 			require.NoError(t, err)
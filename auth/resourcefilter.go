@@ -0,0 +1,64 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/qor/qor/utils"
+
+	"github.com/banzaicloud/pipeline/pkg/brn"
+)
+
+const resourceFilterKey utils.ContextKey = "resourceFilter"
+
+// ResourceFilter returns the resources the current user (and the token it authenticated with) may read.
+type ResourceFilter func(ctx context.Context, resources []brn.ResourceName) ([]brn.ResourceName, error)
+
+// SetResourceFilter returns a context with the resource filter of the current request set.
+func SetResourceFilter(ctx context.Context, filter ResourceFilter) context.Context {
+	return context.WithValue(ctx, resourceFilterKey, filter)
+}
+
+// FilterResourceIDs returns the set of resource IDs of a type the current request may read.
+// Every ID is returned when the request has no resource filter.
+func FilterResourceIDs(ctx context.Context, organizationID uint, resourceType string, ids []string) (map[string]bool, error) {
+	allowed := make(map[string]bool, len(ids))
+
+	filter, ok := ctx.Value(resourceFilterKey).(ResourceFilter)
+	if !ok {
+		for _, id := range ids {
+			allowed[id] = true
+		}
+
+		return allowed, nil
+	}
+
+	resources := make([]brn.ResourceName, 0, len(ids))
+	for _, id := range ids {
+		resources = append(resources, brn.New(organizationID, resourceType, id))
+	}
+
+	resources, err := filter(ctx, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, resource := range resources {
+		allowed[resource.ResourceID] = true
+	}
+
+	return allowed, nil
+}
@@ -54,6 +54,8 @@ func NewClusterTokenGenerator(tokenManager TokenManager, tokenStore bauth.TokenS
 }
 
 // GenerateClusterToken looks up or generates and stores a token for a cluster.
+// Unlike the tokens created through the API, cluster tokens do not expire:
+// nodes joining the cluster use them during the whole lifetime of the cluster.
 func (g ClusterTokenGenerator) GenerateClusterToken(orgID uint, clusterID uint) (string, string, error) {
 	userID := fmt.Sprintf("clusters/%d/%d", orgID, clusterID)
 
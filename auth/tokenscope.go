@@ -0,0 +1,192 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/token"
+)
+
+// TokenScopeSource provides access to the scopes of access tokens.
+type TokenScopeSource interface {
+	// FindTokenScope returns the scope of a token or nil if the token is not scoped.
+	FindTokenScope(ctx context.Context, tokenID string) (*token.Scope, error)
+
+	// MarkTokenUsed records the last time a token was used.
+	MarkTokenUsed(ctx context.Context, tokenID string, usedAt time.Time) error
+}
+
+// TokenScopeEnforcer restricts requests authenticated with scoped access tokens.
+type TokenScopeEnforcer struct {
+	scopeSource TokenScopeSource
+	usage       *tokenUsage
+	logger      Logger
+}
+
+// NewTokenScopeEnforcer returns a new TokenScopeEnforcer.
+func NewTokenScopeEnforcer(scopeSource TokenScopeSource, logger Logger) TokenScopeEnforcer {
+	return TokenScopeEnforcer{
+		scopeSource: scopeSource,
+		usage: &tokenUsage{
+			markedAt: make(map[string]time.Time),
+		},
+		logger: logger,
+	}
+}
+
+// tokenUsageInterval is the minimum time between recording two usages of the same token.
+const tokenUsageInterval = time.Minute
+
+// tokenUsage keeps track of when the usage of tokens was last recorded
+// to avoid writing the database on every request.
+type tokenUsage struct {
+	mu       sync.Mutex
+	markedAt map[string]time.Time
+}
+
+// shouldMark checks whether the usage of a token should be recorded and reserves the recording if it should.
+func (u *tokenUsage) shouldMark(tokenID string, now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if markedAt, ok := u.markedAt[tokenID]; ok && now.Sub(markedAt) < tokenUsageInterval {
+		return false
+	}
+
+	for id, markedAt := range u.markedAt {
+		if now.Sub(markedAt) >= tokenUsageInterval {
+			delete(u.markedAt, id)
+		}
+	}
+
+	u.markedAt[tokenID] = now
+
+	return true
+}
+
+// nolint: gochecknoglobals
+var scopedPathRegexp = regexp.MustCompile(`^/(?:api/v1|dashboard)/orgs/([0-9]+)(/clusters(?:/([^/]+))?)?`)
+
+// Enforce checks whether the token used for the request has access to the path with method.
+// It also records the usage of the token (at most once in a minute).
+//
+// Clusters referenced by name in the path cannot be checked here:
+// they have to be checked with EnforceCluster once the cluster is resolved.
+func (e TokenScopeEnforcer) Enforce(_ *Organization, user *User, path, method string) (bool, error) {
+	if user == nil || user.TokenID == "" {
+		return true, nil
+	}
+
+	ctx := context.Background()
+
+	if now := time.Now(); e.usage.shouldMark(user.TokenID, now) {
+		err := e.scopeSource.MarkTokenUsed(ctx, user.TokenID, now)
+		if err != nil {
+			e.logger.Warn("failed to mark token as used", map[string]interface{}{
+				"tokenId": user.TokenID,
+				"error":   err.Error(),
+			})
+		}
+	}
+
+	scope, err := e.scopeSource.FindTokenScope(ctx, user.TokenID)
+	if err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to find token scope", "tokenId", user.TokenID)
+	}
+
+	if scope == nil {
+		return true, nil
+	}
+
+	readOnly := VerbFromMethod(method) == role.VerbGet
+
+	if scope.ReadOnly && !readOnly {
+		return false, nil
+	}
+
+	// Scoped tokens cannot be used to manage access tokens
+	if (path == "/api/v1/tokens" || strings.HasPrefix(path, "/api/v1/tokens/")) && !readOnly {
+		return false, nil
+	}
+
+	if scope.OrganizationID == 0 {
+		return true, nil
+	}
+
+	matches := scopedPathRegexp.FindStringSubmatch(path)
+	if matches == nil {
+		// Resources outside of organizations can only be read
+		return readOnly, nil
+	}
+
+	orgID, err := strconv.ParseUint(matches[1], 10, 32)
+	if err != nil || uint(orgID) != scope.OrganizationID {
+		return false, nil
+	}
+
+	if len(scope.ClusterIDs) == 0 {
+		return true, nil
+	}
+
+	if matches[3] == "" {
+		// Cluster scoped tokens can only read the organization and list its clusters
+		return readOnly && len(matches[0]) == len(path), nil
+	}
+
+	clusterID, err := strconv.ParseUint(matches[3], 10, 32)
+	if err != nil {
+		// The cluster is referenced by name, it is checked by EnforceCluster
+		return true, nil
+	}
+
+	return scopeAllowsCluster(scope, uint(clusterID)), nil
+}
+
+// EnforceCluster checks whether the token used for the request has access to the cluster the request was resolved to.
+func (e TokenScopeEnforcer) EnforceCluster(ctx context.Context, user *User, clusterID uint) (bool, error) {
+	if user == nil || user.TokenID == "" {
+		return true, nil
+	}
+
+	scope, err := e.scopeSource.FindTokenScope(ctx, user.TokenID)
+	if err != nil {
+		return false, errors.WrapIfWithDetails(err, "failed to find token scope", "tokenId", user.TokenID)
+	}
+
+	if scope == nil || len(scope.ClusterIDs) == 0 {
+		return true, nil
+	}
+
+	return scopeAllowsCluster(scope, clusterID), nil
+}
+
+func scopeAllowsCluster(scope *token.Scope, clusterID uint) bool {
+	for _, id := range scope.ClusterIDs {
+		if id == clusterID {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,143 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/token"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+//go:generate mockery -name TokenScopeSource -inpkg -testonly
+
+func TestTokenScopeEnforcer_Enforce(t *testing.T) {
+	scopes := map[string]*token.Scope{
+		"unscoped": nil,
+		"readonly": {ReadOnly: true},
+		"org":      {OrganizationID: 1},
+		"clusters": {OrganizationID: 1, ClusterIDs: []uint{2, 3}},
+	}
+
+	tests := []struct {
+		tokenID  string
+		method   string
+		path     string
+		expected bool
+	}{
+		{"", http.MethodDelete, "/api/v1/orgs/1/clusters/5", true},
+		{"unscoped", http.MethodDelete, "/api/v1/orgs/1/clusters/5", true},
+		{"readonly", http.MethodGet, "/api/v1/orgs/2/clusters/5", true},
+		{"readonly", http.MethodDelete, "/api/v1/orgs/2/clusters/5", false},
+		{"org", http.MethodDelete, "/api/v1/orgs/1/clusters/5", true},
+		{"org", http.MethodGet, "/api/v1/orgs/2/clusters/5", false},
+		{"org", http.MethodGet, "/api/v1/orgs", true},
+		{"org", http.MethodPost, "/api/v1/orgs", false},
+		{"org", http.MethodPost, "/api/v1/tokens", false},
+		{"clusters", http.MethodPut, "/api/v1/orgs/1/clusters/2", true},
+		{"clusters", http.MethodGet, "/api/v1/orgs/1/clusters/3/deployments", true},
+		{"clusters", http.MethodGet, "/api/v1/orgs/1/clusters/32", false},
+		{"clusters", http.MethodGet, "/api/v1/orgs/1/clusters/my-cluster", true},
+		{"clusters", http.MethodPost, "/api/v1/orgs/1/backupmigrations", false},
+		{"clusters", http.MethodGet, "/api/v1/orgs/1/clusters", true},
+		{"clusters", http.MethodPost, "/api/v1/orgs/1/clusters", false},
+		{"clusters", http.MethodGet, "/api/v1/orgs/1/secrets", false},
+		{"clusters", http.MethodGet, "/api/v1/orgs/1/clustergroups", false},
+		{"clusters", http.MethodGet, "/dashboard/orgs/1/clusters", true},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.tokenID+" "+test.method+" "+test.path, func(t *testing.T) {
+			scopeSource := new(MockTokenScopeSource)
+			if test.tokenID != "" {
+				scopeSource.On("MarkTokenUsed", mock.Anything, test.tokenID, mock.Anything).Return(nil)
+				scopeSource.On("FindTokenScope", mock.Anything, test.tokenID).Return(scopes[test.tokenID], nil)
+			}
+
+			enforcer := NewTokenScopeEnforcer(scopeSource, commonadapter.NewNoopLogger())
+
+			granted, err := enforcer.Enforce(nil, &User{ID: 1, TokenID: test.tokenID}, test.path, test.method)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, granted)
+
+			scopeSource.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTokenScopeEnforcer_Enforce_MarksTokenUsedOnce(t *testing.T) {
+	scopeSource := new(MockTokenScopeSource)
+	scopeSource.On("MarkTokenUsed", mock.Anything, "token", mock.Anything).Return(nil).Once()
+	scopeSource.On("FindTokenScope", mock.Anything, "token").Return(nil, nil)
+
+	enforcer := NewTokenScopeEnforcer(scopeSource, commonadapter.NewNoopLogger())
+
+	for i := 0; i < 3; i++ {
+		granted, err := enforcer.Enforce(nil, &User{ID: 1, TokenID: "token"}, "/api/v1/orgs/1/clusters", http.MethodGet)
+		require.NoError(t, err)
+		assert.True(t, granted)
+	}
+
+	scopeSource.AssertExpectations(t)
+}
+
+func TestTokenScopeEnforcer_EnforceCluster(t *testing.T) {
+	scopes := map[string]*token.Scope{
+		"unscoped": nil,
+		"org":      {OrganizationID: 1},
+		"clusters": {OrganizationID: 1, ClusterIDs: []uint{2, 3}},
+	}
+
+	tests := []struct {
+		tokenID   string
+		clusterID uint
+		expected  bool
+	}{
+		{"", 5, true},
+		{"unscoped", 5, true},
+		{"org", 5, true},
+		{"clusters", 2, true},
+		{"clusters", 5, false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.tokenID, func(t *testing.T) {
+			scopeSource := new(MockTokenScopeSource)
+			if test.tokenID != "" {
+				scopeSource.On("FindTokenScope", mock.Anything, test.tokenID).Return(scopes[test.tokenID], nil)
+			}
+
+			enforcer := NewTokenScopeEnforcer(scopeSource, commonadapter.NewNoopLogger())
+
+			granted, err := enforcer.EnforceCluster(context.Background(), &User{ID: 1, TokenID: test.tokenID}, test.clusterID)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, granted)
+
+			scopeSource.AssertExpectations(t)
+		})
+	}
+}
@@ -76,6 +76,7 @@ type User struct {
 	Organizations []Organization `gorm:"many2many:user_organizations" json:"organizations,omitempty"`
 	Virtual       bool           `json:"-" gorm:"-"` // Used only internally
 	APIToken      string         `json:"-" gorm:"-"` // Used only internally
+	TokenID       string         `json:"-" gorm:"-"` // Used only internally
 }
 
 // CICDUser struct
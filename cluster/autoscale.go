@@ -30,6 +30,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/providers/azure/pke"
 	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 )
 
@@ -305,14 +306,14 @@ func DeployClusterAutoscaler(cluster CommonCluster) error {
 		return err
 	}
 
-	if isAutoscalerDeployedAlready(releaseName, kubeConfig) {
+	if isReleaseDeployed(releaseName, kubeConfig) {
 		// no need to upgrade in case of EKS since we're using nodepool autodiscovery
 		if _, isEks := cluster.(*EKSCluster); isEks {
 			return nil
 		}
 		if len(nodeGroups) == 0 {
 			// delete
-			err := helm.DeleteDeployment(releaseName, kubeConfig)
+			err := deleteAutoscalerChart(kubeConfig)
 			if err != nil {
 				log.Errorf("DeleteDeployment '%s' failed due to: %s", autoScalerChart, err.Error())
 				return err
@@ -334,10 +335,26 @@ func DeployClusterAutoscaler(cluster CommonCluster) error {
 	return nil
 }
 
-func isAutoscalerDeployedAlready(releaseName string, kubeConfig []byte) bool {
+func isReleaseDeployed(releaseName string, kubeConfig []byte) bool {
+	backend, err := helm.GetBackend(kubeConfig)
+	if err != nil {
+		log.Errorf("getting helm backend failed due to: %s", err.Error())
+		return false
+	}
+
+	if backend == pkgHelm.Helm3Backend {
+		rls, err := helm.Helm3GetDeployment(releaseName, kubeConfig)
+		if err != nil {
+			log.Errorf("getting release '%s' failed due to: %s", releaseName, err.Error())
+			return false
+		}
+
+		return rls != nil
+	}
+
 	deployments, err := helm.ListDeployments(&releaseName, "", kubeConfig)
 	if err != nil {
-		log.Errorf("ListDeployments for '%s' failed due to: %s", autoScalerChart, err.Error())
+		log.Errorf("ListDeployments for '%s' failed due to: %s", releaseName, err.Error())
 		return false
 	}
 	for _, release := range deployments.GetReleases() {
@@ -348,6 +365,19 @@ func isAutoscalerDeployedAlready(releaseName string, kubeConfig []byte) bool {
 	return false
 }
 
+func deleteAutoscalerChart(kubeConfig []byte) error {
+	backend, err := helm.GetBackend(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	if backend == pkgHelm.Helm3Backend {
+		return helm.Helm3DeleteDeployment(releaseName, kubeConfig)
+	}
+
+	return helm.DeleteDeployment(releaseName, kubeConfig)
+}
+
 func deployAutoscalerChart(cluster CommonCluster, nodeGroups []nodeGroup, kubeConfig []byte, action deploymentAction) error {
 	var values *autoscalingInfo
 	switch cluster.GetDistribution() {
@@ -383,10 +413,19 @@ func deployAutoscalerChart(cluster CommonCluster, nodeGroups []nodeGroup, kubeCo
 
 	chartVersion := viper.GetString(config.AutoscaleClusterAutoscalerChartVersion)
 
-	switch action {
-	case install:
+	backend, err := helm.GetBackend(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case backend == pkgHelm.Helm3Backend && action == install:
+		_, err = helm.Helm3InstallDeployment(autoScalerChart, chartVersion, nil, helm.SystemNamespace, releaseName, yamlValues, helm.Helm3Options{}, kubeConfig, helm.GenerateHelmRepoEnv(org.Name))
+	case backend == pkgHelm.Helm3Backend && action == upgrade:
+		_, err = helm.Helm3UpgradeDeployment(releaseName, autoScalerChart, chartVersion, nil, yamlValues, helm.Helm3Options{}, kubeConfig, helm.GenerateHelmRepoEnv(org.Name))
+	case action == install:
 		_, err = helm.CreateDeployment(autoScalerChart, chartVersion, nil, helm.SystemNamespace, releaseName, false, nil, kubeConfig, helm.GenerateHelmRepoEnv(org.Name), k8sHelm.ValueOverrides(yamlValues))
-	case upgrade:
+	case action == upgrade:
 		_, err = helm.UpgradeDeployment(releaseName, autoScalerChart, chartVersion, nil, yamlValues, false, kubeConfig, helm.GenerateHelmRepoEnv(org.Name))
 	default:
 		return err
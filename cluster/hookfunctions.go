@@ -33,7 +33,7 @@ var HookMap = map[string]PostFunctioner{
 		f:            CreatePipelineNamespacePostHook,
 		ErrorHandler: ErrorHandler{},
 	},
-	pkgCluster.InstallHelmPostHook: &BasePostFunction{
+	pkgCluster.InstallHelmPostHook: &PostFunctionWithParam{
 		f:            InstallHelmPostHook,
 		ErrorHandler: ErrorHandler{},
 	},
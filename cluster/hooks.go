@@ -136,6 +136,16 @@ func installDeployment(cluster CommonCluster, namespace string, deploymentName s
 		return err
 	}
 
+	backend, err := helm.GetBackend(kubeConfig)
+	if err != nil {
+		log.Errorf("Unable to get helm backend: %s", err.Error())
+		return err
+	}
+
+	if backend == pkgHelm.Helm3Backend {
+		return installHelm3Deployment(kubeConfig, org.Name, namespace, deploymentName, releaseName, values, chartVersion, wait)
+	}
+
 	deployments, err := helm.ListDeployments(&releaseName, "", kubeConfig)
 	if err != nil {
 		log.Errorln("Unable to fetch deployments from helm:", err)
@@ -180,6 +190,37 @@ func installDeployment(cluster CommonCluster, namespace string, deploymentName s
 	return nil
 }
 
+// installHelm3Deployment is the installDeployment of clusters using the helm 3 backend.
+func installHelm3Deployment(kubeConfig []byte, orgName string, namespace string, deploymentName string, releaseName string, values []byte, chartVersion string, wait bool) error {
+	foundRelease, err := helm.Helm3GetDeployment(releaseName, kubeConfig)
+	if err != nil {
+		log.Errorf("Unable to fetch release '%s': %s", releaseName, err.Error())
+		return err
+	}
+
+	if foundRelease != nil && foundRelease.Info != nil {
+		switch pkgHelm.Helm2Status(foundRelease.Info.Status) {
+		case pkgHelmRelease.Status_DEPLOYED.String():
+			log.Infof("'%s' is already installed", deploymentName)
+			return nil
+		case pkgHelmRelease.Status_FAILED.String():
+			err = helm.Helm3DeleteDeployment(releaseName, kubeConfig)
+			if err != nil {
+				log.Errorf("Failed to deleted failed deployment '%s' due to: %s", deploymentName, err.Error())
+				return err
+			}
+		}
+	}
+
+	_, err = helm.Helm3InstallDeployment(deploymentName, chartVersion, nil, namespace, releaseName, values, helm.Helm3Options{Wait: wait}, kubeConfig, helm.GenerateHelmRepoEnv(orgName))
+	if err != nil {
+		log.Errorf("Deploying '%s' failed due to: %s", deploymentName, err.Error())
+		return err
+	}
+	log.Infof("'%s' installed", deploymentName)
+	return nil
+}
+
 func InstallKubernetesDashboardPostHook(cluster CommonCluster) error {
 
 	k8sDashboardNameSpace := viper.GetString(pipConfig.PipelineSystemNamespace)
@@ -555,8 +596,41 @@ func CreatePipelineNamespacePostHook(cluster CommonCluster) error {
 }
 
 // InstallHelmPostHook this posthook installs the helm related things
-func InstallHelmPostHook(cluster CommonCluster) error {
+func InstallHelmPostHook(cluster CommonCluster, param pkgCluster.PostHookParam) error {
 	log := log.WithFields(logrus.Fields{"cluster": cluster.GetName(), "clusterID": cluster.GetID()})
+
+	var helmParam pkgCluster.HelmParam
+	err := castToPostHookParam(&param, &helmParam)
+	if err != nil {
+		return emperror.Wrap(err, "posthook param failed")
+	}
+
+	backend := pkgHelm.Backend(helmParam.Backend)
+	if backend == "" {
+		backend = pkgHelm.Backend(viper.GetString(pipConfig.HelmBackendKey))
+	}
+	if err := backend.Validate(); err != nil {
+		return err
+	}
+
+	switch backend {
+	case pkgHelm.EmbeddedTillerBackend:
+		kubeconfig, err := cluster.GetK8sConfig()
+		if err != nil {
+			return err
+		}
+
+		return helm.InitEmbeddedTiller(log, kubeconfig)
+
+	case pkgHelm.Helm3Backend:
+		kubeconfig, err := cluster.GetK8sConfig()
+		if err != nil {
+			return err
+		}
+
+		return helm.InitHelm3(log, kubeconfig)
+	}
+
 	helmInstall := &pkgHelm.Install{
 		Namespace:      "kube-system",
 		ServiceAccount: "tiller",
@@ -32,12 +32,26 @@ import (
 	"github.com/banzaicloud/pipeline/secret"
 )
 
+// loggingFeatureReleaseName is the release of the logging resources installed by the logging cluster feature
+const loggingFeatureReleaseName = "logging-operator-logging"
+
 // InstallLogging to install logging deployment
+// The logging cluster feature supersedes this hook: it supports multiple outputs and can be reconfigured after cluster creation.
+// The feature installs its operator under the same release name and removes the releases of this hook when activated.
 func InstallLogging(cluster CommonCluster, param pkgCluster.PostHookParam) error {
 	var releaseTag = fmt.Sprintf("release:%s", pipConfig.LoggingReleaseName)
 
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return emperror.Wrap(err, "failed to get kubeconfig")
+	}
+
+	if isReleaseDeployed(loggingFeatureReleaseName, kubeConfig) {
+		return errors.New("logging is managed by the logging cluster feature")
+	}
+
 	var loggingParam pkgCluster.LoggingParam
-	err := castToPostHookParam(&param, &loggingParam)
+	err = castToPostHookParam(&param, &loggingParam)
 	if err != nil {
 		return emperror.Wrap(err, "posthook param failed")
 	}
@@ -41,6 +41,8 @@ type clusterRepository interface {
 	FindOneByID(organizationID uint, clusterID uint) (*model.ClusterModel, error)
 	FindOneByName(organizationID uint, clusterName string) (*model.ClusterModel, error)
 	FindBySecret(organizationID uint, secretID string) ([]*model.ClusterModel, error)
+	GetLabels(clusterID uint) (map[string]string, error)
+	SetLabels(clusterID uint, labels map[string]string) error
 }
 
 type secretValidator interface {
@@ -0,0 +1,51 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// GetClusterLabels returns the user-defined labels of a cluster.
+func (m *Manager) GetClusterLabels(ctx context.Context, clusterID uint) (map[string]string, error) {
+	labels, err := m.clusters.GetLabels(clusterID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "could not get cluster labels")
+	}
+
+	return labels, nil
+}
+
+// UpdateClusterLabels replaces the user-defined labels of a cluster.
+// Cluster labels can be used by cluster group selectors, so an update event is emitted.
+func (m *Manager) UpdateClusterLabels(ctx context.Context, clusterID uint, labels map[string]string) error {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"cluster": clusterID,
+	})
+
+	logger.Info("updating cluster labels")
+
+	if err := m.clusters.SetLabels(clusterID, labels); err != nil {
+		return errors.WrapIf(err, "could not update cluster labels")
+	}
+	m.events.ClusterUpdated(clusterID)
+
+	logger.Info("cluster labels updated successfully")
+
+	return nil
+}
@@ -99,9 +99,10 @@ func InstallSecretsByK8SConfig(kubeConfig []byte, orgID uint, query *secret.List
 		}
 
 		kubeSecretRequest := intSecret.KubeSecretRequest{
-			Name:   s.Name,
-			Type:   s.Type,
-			Values: s.Values,
+			Name:     s.Name,
+			Type:     s.Type,
+			Values:   s.Values,
+			SourceID: s.ID,
 		}
 
 		newK8sSecret, err := intSecret.CreateKubeSecret(kubeSecretRequest)
@@ -116,6 +117,7 @@ func InstallSecretsByK8SConfig(kubeConfig []byte, orgID uint, query *secret.List
 		} else {
 			k8sSecret.Data = nil // Clear data so that it is created from string data again
 			k8sSecret.StringData = newK8sSecret.StringData
+			intSecret.MarkKubeSecretSource(&k8sSecret, s.ID)
 
 			_, err = clusterClient.CoreV1().Secrets(namespace).Update(&k8sSecret)
 		}
@@ -190,6 +192,7 @@ func InstallSecretByK8SConfig(kubeConfig []byte, orgID uint, secretName string,
 
 		kubeSecretRequest.Type = secretItem.Type
 		kubeSecretRequest.Values = secretItem.Values
+		kubeSecretRequest.SourceID = secretItem.ID
 
 		sourceMeta = secretItem.K8SSourceMeta()
 	}
@@ -17,6 +17,7 @@ package main
 import (
 	"net/url"
 	"os"
+	"time"
 
 	"emperror.dev/errors"
 	"github.com/spf13/pflag"
@@ -25,6 +26,7 @@ import (
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/internal/anchore"
 	"github.com/banzaicloud/pipeline/internal/app/frontend"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/api/middleware/audit"
 	"github.com/banzaicloud/pipeline/internal/platform/errorhandler"
 	"github.com/banzaicloud/pipeline/internal/platform/log"
 	"github.com/banzaicloud/pipeline/pkg/viperx"
@@ -47,6 +49,9 @@ type configuration struct {
 
 	// Cluster configuration
 	Cluster clusterConfig
+
+	// Audit configuration
+	Audit auditConfig
 }
 
 // Validate validates the configuration.
@@ -63,6 +68,10 @@ func (c configuration) Validate() error {
 		return err
 	}
 
+	if err := c.Audit.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -105,6 +114,9 @@ type authTokenConfig struct {
 	SigningKey string
 	Issuer     string
 	Audience   string
+
+	// MaxLifetime is the maximum lifetime of the access tokens created through the API
+	MaxLifetime time.Duration
 }
 
 // Validate validates the configuration.
@@ -117,13 +129,28 @@ func (c authTokenConfig) Validate() error {
 		return errors.New("auth token signing key must be at least 32 characters")
 	}
 
+	if c.MaxLifetime <= 0 {
+		return errors.New("auth token max lifetime must be positive")
+	}
+
 	return nil
 }
 
+// auditConfig contains audit configuration.
+type auditConfig struct {
+	Sinks audit.SinkConfig
+}
+
+// Validate validates the configuration.
+func (c auditConfig) Validate() error {
+	return c.Sinks.Validate()
+}
+
 // clusterConfig contains cluster configuration.
 type clusterConfig struct {
 	Vault        clusterVaultConfig
 	Monitoring   clusterMonitorConfig
+	Logging      clusterLoggingConfig
 	SecurityScan clusterSecurityScanConfig
 }
 
@@ -154,6 +181,11 @@ type clusterMonitorConfig struct {
 	Enabled bool
 }
 
+// clusterLoggingConfig contains cluster logging configuration.
+type clusterLoggingConfig struct {
+	Enabled bool
+}
+
 // clusterSecurityScanConfig contains cluster security scan configuration.
 type clusterSecurityScanConfig struct {
 	Enabled bool
@@ -219,6 +251,7 @@ func configure(v *viper.Viper, _ *pflag.FlagSet) {
 	// Auth configuration
 	v.SetDefault("auth.token.issuer", "https://banzaicloud.com/")
 	v.SetDefault("auth.token.audience", "https://pipeline.banzaicloud.com")
+	v.SetDefault("auth.token.maxLifetime", 365*24*time.Hour)
 
 	v.SetDefault("auth.role.default", auth.RoleAdmin)
 	v.SetDefault("auth.role.binding", map[string]string{
@@ -237,11 +270,28 @@ func configure(v *viper.Viper, _ *pflag.FlagSet) {
 	v.SetDefault("cluster.vault.enabled", true)
 	v.SetDefault("cluster.vault.managed.enabled", false)
 	v.SetDefault("cluster.monitoring.enabled", true)
+	v.SetDefault("cluster.logging.enabled", true)
 	v.SetDefault("cluster.securityScan.enabled", true)
 	v.SetDefault("cluster.securityScan.anchore.enabled", false)
 	v.SetDefault("cluster.securityScan.anchore.endpoint", "")
 	v.SetDefault("cluster.securityScan.anchore.user", "")
 	v.SetDefault("cluster.securityScan.anchore.password", "")
+
+	v.SetDefault("audit.sinks.database.enabled", true)
+	v.SetDefault("audit.sinks.file.enabled", false)
+	v.SetDefault("audit.sinks.file.path", "audit/audit.log")
+	v.SetDefault("audit.sinks.file.maxSize", 100)
+	v.SetDefault("audit.sinks.file.maxBackups", 5)
+	v.SetDefault("audit.sinks.webhook.enabled", false)
+	v.SetDefault("audit.sinks.webhook.url", "")
+	v.SetDefault("audit.sinks.webhook.headers", map[string]string{})
+	v.SetDefault("audit.sinks.webhook.batchSize", 100)
+	v.SetDefault("audit.sinks.webhook.flushInterval", "5s")
+	v.SetDefault("audit.sinks.webhook.maxRetries", 3)
+	v.SetDefault("audit.sinks.webhook.retryInterval", "1s")
+	v.SetDefault("audit.sinks.webhook.timeout", "10s")
+	v.SetDefault("audit.sinks.watermill.enabled", false)
+	v.SetDefault("audit.sinks.watermill.topic", "audit")
 }
 
 func registerAliases(v *viper.Viper) {
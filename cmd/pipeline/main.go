@@ -37,7 +37,6 @@ import (
 	"github.com/go-kit/kit/endpoint"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/gorilla/mux"
-	"github.com/jinzhu/gorm"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sagikazarmark/kitx/correlation"
 	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
@@ -52,6 +51,8 @@ import (
 	"github.com/banzaicloud/pipeline/api/ark/backups"
 	"github.com/banzaicloud/pipeline/api/ark/backupservice"
 	"github.com/banzaicloud/pipeline/api/ark/buckets"
+	"github.com/banzaicloud/pipeline/api/ark/migrations"
+	"github.com/banzaicloud/pipeline/api/ark/policies"
 	"github.com/banzaicloud/pipeline/api/ark/restores"
 	"github.com/banzaicloud/pipeline/api/ark/schedules"
 	"github.com/banzaicloud/pipeline/api/cluster/namespace"
@@ -65,17 +66,41 @@ import (
 	"github.com/banzaicloud/pipeline/cluster"
 	"github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/dns"
+	legacyhelm "github.com/banzaicloud/pipeline/helm"
 	anchore2 "github.com/banzaicloud/pipeline/internal/anchore"
 	"github.com/banzaicloud/pipeline/internal/app/frontend"
+	"github.com/banzaicloud/pipeline/internal/app/frontend/notification"
+	"github.com/banzaicloud/pipeline/internal/app/frontend/notification/notificationadapter"
+	"github.com/banzaicloud/pipeline/internal/app/frontend/notification/notificationdriver"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/api/middleware/audit"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application/applicationadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application/applicationdriver"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auditlog"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auditlog/auditlogadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auditlog/auditlogdriver"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/policy"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/policy/policyadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/policy/policydriver"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role/roleadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role/roledriver"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/token"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/token/tokenadapter"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/token/tokendriver"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/cap/capdriver"
 	googleproject "github.com/banzaicloud/pipeline/internal/app/pipeline/cloud/google/project"
 	googleprojectdriver "github.com/banzaicloud/pipeline/internal/app/pipeline/cloud/google/project/projectdriver"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretexpiry"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretexpiry/secretexpiryadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation/secretrotationadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation/secretrotationdriver"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/secrettype"
 	"github.com/banzaicloud/pipeline/internal/app/pipeline/secrettype/secrettypedriver"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage/secretusageadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage/secretusagedriver"
 	arkClusterManager "github.com/banzaicloud/pipeline/internal/ark/clustermanager"
 	arkEvents "github.com/banzaicloud/pipeline/internal/ark/events"
 	arkSync "github.com/banzaicloud/pipeline/internal/ark/sync"
@@ -90,6 +115,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeaturedriver"
 	featureDns "github.com/banzaicloud/pipeline/internal/clusterfeature/features/dns"
+	featureLogging "github.com/banzaicloud/pipeline/internal/clusterfeature/features/logging"
 	featureMonitoring "github.com/banzaicloud/pipeline/internal/clusterfeature/features/monitoring"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/features/securityscan"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/features/securityscan/securityscanadapter"
@@ -122,6 +148,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/providers/google/googleadapter"
 	anchore "github.com/banzaicloud/pipeline/internal/security"
 	pkgAuth "github.com/banzaicloud/pipeline/pkg/auth"
+	"github.com/banzaicloud/pipeline/pkg/brn"
 	"github.com/banzaicloud/pipeline/pkg/ctxutil"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 	"github.com/banzaicloud/pipeline/pkg/problems"
@@ -365,6 +392,45 @@ func main() {
 		}
 	}
 
+	if viper.GetBool("audit.retention.enabled") {
+		go auditlog.NewRetentionJob(
+			auditlogadapter.NewGormStore(db),
+			viper.GetDuration("audit.retention.period"),
+			commonLogger.WithFields(map[string]interface{}{"subsystem": "audit-retention"}),
+			emperror.MakeContextAware(errorHandler),
+		).Run(context.Background(), viper.GetDuration("audit.retention.interval"))
+	}
+
+	if viper.GetBool("secretRotation.scheduler.enabled") {
+		go secretrotation.NewScheduler(
+			secretrotationadapter.NewGormPolicyStore(db),
+			secretrotationadapter.NewCadenceRotationStarter(workflowClient),
+			commonLogger.WithFields(map[string]interface{}{"subsystem": "secret-rotation"}),
+			emperror.MakeContextAware(errorHandler),
+		).Run(context.Background(), viper.GetDuration("secretRotation.scheduler.interval"))
+	}
+
+	if viper.GetBool("secretExpiry.enabled") {
+		metrics := secretexpiryadapter.NewPrometheusMetrics()
+		prometheus.MustRegister(metrics)
+
+		secretExpiryConfig := secretexpiry.Config{
+			NotifyBefore: viper.GetDuration("secretExpiry.notifyBefore"),
+			RenewBefore:  viper.GetDuration("secretExpiry.renewBefore"),
+		}
+		emperror.Panic(errors.WrapIf(secretExpiryConfig.Validate(), "invalid secret expiry configuration"))
+
+		go secretexpiry.NewController(
+			secretExpiryConfig,
+			secretexpiryadapter.NewCertificateStore(db, secret.Store),
+			secretexpiryadapter.NewGormNotifier(db, notificationadapter.NewGormStore(db)),
+			secretexpiryadapter.NewRotationRenewer(secretrotationadapter.NewCadenceRotationStarter(workflowClient)),
+			metrics,
+			commonLogger.WithFields(map[string]interface{}{"subsystem": "secret-expiry"}),
+			emperror.MakeContextAware(errorHandler),
+		).Run(context.Background(), viper.GetDuration("secretExpiry.interval"))
+	}
+
 	if viper.GetBool(config.SpotMetricsEnabled) {
 		go monitor.NewSpotMetricsExporter(context.Background(), clusterManager, logrusLogger.WithField("subsystem", "spot-metrics-exporter")).Run(viper.GetDuration(config.SpotMetricsCollectionInterval))
 	}
@@ -407,11 +473,19 @@ func main() {
 	clusterGroupManager := clustergroup.NewManager(cgroupAdapter, clustergroup.NewClusterGroupRepository(db, logrusLogger), logrusLogger, errorHandler)
 	infraNamespace := viper.GetString(config.PipelineSystemNamespace)
 	federationHandler := federation.NewFederationHandler(cgroupAdapter, infraNamespace, logrusLogger, errorHandler)
-	deploymentManager := deployment.NewCGDeploymentManager(db, cgroupAdapter, logrusLogger, errorHandler)
+	featureClusterGetter := clusterfeatureadapter.MakeClusterGetter(clusterManager)
+	clusterDomainGetter := featureDns.NewClusterDomainGetter(
+		clusterfeatureadapter.NewGormFeatureRepository(db, commonLogger),
+		featureDns.MakeFeatureManager(featureClusterGetter, commonLogger, featureDns.NewOrgDomainService(featureClusterGetter, dnsSvc, commonLogger)),
+	)
+	helmBackends := helmadapter.NewBackends()
+	helmService := helm.NewHelmService(helmadapter.NewClusterService(clusterManager), helmBackends, commonLogger)
+	deploymentManager := deployment.NewCGDeploymentManager(db, cgroupAdapter, clusterDomainGetter, helmBackends, workflowClient, logrusLogger, errorHandler)
 	serviceMeshFeatureHandler := cgFeatureIstio.NewServiceMeshFeatureHandler(cgroupAdapter, logrusLogger, errorHandler)
 	clusterGroupManager.RegisterFeatureHandler(federation.FeatureName, federationHandler)
 	clusterGroupManager.RegisterFeatureHandler(deployment.FeatureName, deploymentManager)
 	clusterGroupManager.RegisterFeatureHandler(cgFeatureIstio.FeatureName, serviceMeshFeatureHandler)
+	clusterGroupManager.RegisterClusterEvents(clustergroup.NewClusterEvents(clusterEventBus))
 	clusterUpdaters := api.ClusterUpdaters{
 		PKEOnAzure: azurePKEDriver.MakeAzurePKEClusterUpdater(
 			logrusLogger,
@@ -435,6 +509,14 @@ func main() {
 
 	// These two paths can contain sensitive information, so it is advised not to log them out.
 	skipPaths := viper.GetStringSlice("audit.skippaths")
+
+	var auditSink audit.Sink
+	if viper.GetBool("audit.enabled") {
+		auditSink, err = audit.NewSink(conf.Audit.Sinks, db, publisher, logrusLogger)
+		emperror.Panic(errors.WrapIf(err, "failed to create audit sink"))
+		defer auditSink.Close()
+	}
+
 	engine.Use(correlationid.Middleware())
 	engine.Use(ginlog.Middleware(logrusLogger, skipPaths...))
 
@@ -456,7 +538,7 @@ func main() {
 	engine.Use(cors.New(config.GetCORS()))
 	if viper.GetBool("audit.enabled") {
 		logger.Info("Audit enabled, installing Gin audit middleware")
-		engine.Use(audit.LogWriter(skipPaths, viper.GetStringSlice("audit.headers"), db, logrusLogger))
+		engine.Use(audit.LogWriter(skipPaths, viper.GetStringSlice("audit.headers"), auditSink, logrusLogger))
 	}
 	engine.Use(func(c *gin.Context) { // TODO: move to middleware
 		c.Request = c.Request.WithContext(ctxutil.WithParams(c.Request.Context(), ginutils.ParamsToMap(c.Params)))
@@ -476,6 +558,7 @@ func main() {
 			db,
 			buildInfo,
 			auth.UserExtractor{},
+			commonadapter.OrgIDContextExtractorFunc(auth.GetCurrentOrganizationID),
 			commonLogger,
 			errorHandler,
 		)
@@ -495,12 +578,54 @@ func main() {
 	auth.Install(engine)
 	auth.StartTokenStoreGC(tokenStore)
 
-	enforcer := auth.NewRbacEnforcer(organizationStore, commonLogger)
+	roleStore := roleadapter.NewGormStore(db)
+	enforcer := auth.NewRbacEnforcer(organizationStore, roleStore, commonLogger)
 	authorizationMiddleware := ginauth.NewMiddleware(enforcer, basePath, errorHandler)
 
+	tokenMetadataStore := tokenadapter.NewGormMetadataStore(db)
+	tokenScopeEnforcer := auth.NewTokenScopeEnforcer(tokenMetadataStore, commonLogger)
+	tokenScopeMiddleware := ginauth.NewMiddleware(tokenScopeEnforcer, basePath, errorHandler)
+	clusterTokenScopeMiddleware := ginauth.NewClusterMiddleware(tokenScopeEnforcer, errorHandler)
+
+	policyStore := policyadapter.NewGormStore(db)
+	resourceEnforcer := policy.NewEnforcer(policyStore)
+	clusterAuthorizationMiddleware := ginauth.NewResourceMiddleware(
+		resourceEnforcer,
+		ginauth.ClusterResolver("id"),
+		errorHandler,
+	)
+	clusterProxyAuthorizationMiddleware := ginauth.NewResourceMiddleware(
+		resourceEnforcer,
+		ginauth.VerbResolver(ginauth.ClusterResolver("id"), role.VerbUpdate),
+		errorHandler,
+	)
+	deploymentAuthorizationMiddleware := ginauth.NewResourceMiddleware(
+		resourceEnforcer,
+		ginauth.ClusterChildResolver(brn.DeploymentResourceType, "id", "name"),
+		errorHandler,
+	)
+	secretAuthorizationMiddleware := ginauth.NewResourceMiddleware(
+		resourceEnforcer,
+		ginauth.ParamResolver(brn.SecretResourceType, "id"),
+		errorHandler,
+	)
+	helmRepoAuthorizationMiddleware := ginauth.NewResourceMiddleware(
+		resourceEnforcer,
+		ginauth.ParamResolver(brn.HelmRepoResourceType, "name"),
+		errorHandler,
+	)
+	helmChartAuthorizationMiddleware := ginauth.NewResourceMiddleware(
+		resourceEnforcer,
+		ginauth.ParamResolver(brn.HelmRepoResourceType, "reponame"),
+		errorHandler,
+	)
+	resourceFilterMiddleware := ginauth.NewResourceFilterMiddleware(resourceEnforcer)
+	secretAuthorizer := ginauth.NewSecretAuthorizer(resourceEnforcer, enforcer, tokenScopeEnforcer)
+
 	dashboardAPI := dashboard.NewDashboardAPI(clusterManager, clusterGroupManager, logrusLogger, errorHandler)
 	dgroup := base.Group(path.Join("dashboard", "orgs"))
 	dgroup.Use(auth.Handler)
+	dgroup.Use(tokenScopeMiddleware)
 	dgroup.Use(api.OrganizationMiddleware)
 	dgroup.Use(authorizationMiddleware)
 	dgroup.GET("/:orgid/clusters", dashboardAPI.GetDashboard)
@@ -509,6 +634,7 @@ func main() {
 		// Cluster details dashboard
 		dcGroup := dgroup.Group("/:orgid/clusters/:id")
 		dcGroup.Use(cluster.NewClusterCheckMiddleware(clusterManager, errorHandler))
+		dcGroup.Use(clusterTokenScopeMiddleware)
 		dcGroup.GET("", dashboardAPI.GetClusterDashboard)
 	}
 
@@ -519,6 +645,32 @@ func main() {
 	userAPI := api.NewUserAPI(db, scmTokenStore, logrusLogger, errorHandler)
 	networkAPI := api.NewNetworkAPI(logrusLogger)
 
+	secretUsageService := secretusage.NewService(
+		commonadapter.OrgIDContextExtractorFunc(auth.GetCurrentOrganizationID),
+		secretusageadapter.NewGormClusterFinder(db),
+		secretusageadapter.NewGormBucketFinder(db),
+		secretusageadapter.NewGormBackupFinder(db),
+		secretusageadapter.NewGormFeatureFinder(db),
+		secretusageadapter.NewSpotguideFinder(secret.Store),
+		secretusageadapter.NewHelmRepositoryFinder(db),
+	)
+	secretAPI := api.NewSecretAPI(secretUsageService)
+
+	chartSchemaStore := helmadapter.NewGormChartSchemaStore(db)
+	deploymentAPI := api.NewDeploymentAPI(helmService, chartSchemaStore, secret.Store, secretAuthorizer)
+	clusterSecretAPI := api.NewClusterSecretAPI(secretAuthorizer)
+	helmRepoAPI := api.NewHelmRepoAPI(secretAuthorizer)
+
+	// the credentials of private chart repositories are read from their secrets when the repositories are accessed
+	legacyhelm.SetRepositorySecretGetter(func(organizationID uint, secretID string) (string, map[string]string, error) {
+		secretItem, err := secret.Store.Get(organizationID, secretID)
+		if err != nil {
+			return "", nil, err
+		}
+
+		return secretItem.Type, secretItem.Values, nil
+	})
+
 	switch viper.GetString(config.DNSBaseDomain) {
 	case "", "example.com", "example.org":
 		global.AutoDNSEnabled = false
@@ -576,6 +728,7 @@ func main() {
 		apiRouter.MethodNotAllowedHandler = problems.StatusProblemHandler(problems.NewStatusProblem(http.StatusMethodNotAllowed))
 
 		v1.Use(auth.Handler)
+		v1.Use(tokenScopeMiddleware)
 		capdriver.RegisterHTTPHandler(mapCapabilities(conf), emperror.MakeContextAware(errorHandler), v1)
 		v1.GET("/securityscan", api.SecurityScanEnabled)
 		v1.GET("/me", userAPI.GetCurrentUser)
@@ -596,6 +749,7 @@ func main() {
 		{
 			orgs.Use(api.OrganizationMiddleware)
 			orgs.Use(authorizationMiddleware)
+			orgs.Use(resourceFilterMiddleware)
 
 			if viper.GetBool("cicd.enabled") {
 				spotguides := orgs.Group("/:orgid/spotguides")
@@ -614,17 +768,21 @@ func main() {
 				logger := commonadapter.NewLogger(logger) // TODO: make this a context aware logger
 
 				cRouter.Use(cluster.NewClusterCheckMiddleware(clusterManager, errorHandler))
+				cRouter.Use(clusterTokenScopeMiddleware)
+				cRouter.Use(clusterAuthorizationMiddleware)
 
 				cRouter.GET("", clusterAPI.GetCluster)
 				cRouter.GET("/pods", api.GetPodDetails)
 				cRouter.GET("/bootstrap", clusterAPI.GetBootstrapInfo)
 				cRouter.PUT("", clusterAPI.UpdateCluster)
+				cRouter.GET("/labels", clusterAPI.GetClusterLabels)
+				cRouter.PUT("/labels", clusterAPI.UpdateClusterLabels)
 
 				cRouter.PUT("/posthooks", clusterAPI.ReRunPostHooks)
-				cRouter.POST("/secrets", api.InstallSecretsToCluster)
-				cRouter.POST("/secrets/:secretName", api.InstallSecretToCluster)
-				cRouter.PATCH("/secrets/:secretName", api.MergeSecretInCluster)
-				cRouter.Any("/proxy/*path", clusterAPI.ProxyToCluster)
+				cRouter.POST("/secrets", clusterSecretAPI.InstallSecretsToCluster)
+				cRouter.POST("/secrets/:secretName", clusterSecretAPI.InstallSecretToCluster)
+				cRouter.PATCH("/secrets/:secretName", clusterSecretAPI.MergeSecretInCluster)
+				cRouter.Any("/proxy/*path", clusterProxyAuthorizationMiddleware, clusterAPI.ProxyToCluster)
 				cRouter.DELETE("", clusterAPI.DeleteCluster)
 				cRouter.HEAD("", clusterAPI.ClusterCheck)
 				cRouter.GET("/config", api.GetClusterConfig)
@@ -632,21 +790,26 @@ func main() {
 				cRouter.GET("/nodes", api.GetClusterNodes)
 				cRouter.GET("/endpoints", api.MakeEndpointLister(logger).ListEndpoints)
 				cRouter.GET("/secrets", api.ListClusterSecrets)
-				cRouter.GET("/deployments", api.ListDeployments)
-				cRouter.POST("/deployments", api.CreateDeployment)
-				cRouter.GET("/deployments/:name", api.GetDeployment)
-				cRouter.GET("/deployments/:name/resources", api.GetDeploymentResources)
+				cRouter.GET("/deployments", deploymentAPI.ListDeployments)
+				cRouter.POST("/deployments", deploymentAPI.CreateDeployment)
+				cRouter.GET("/deployments/:name", deploymentAuthorizationMiddleware, deploymentAPI.GetDeployment)
+				cRouter.GET("/deployments/:name/resources", deploymentAuthorizationMiddleware, deploymentAPI.GetDeploymentResources)
 				cRouter.GET("/hpa", api.GetHpaResource)
 				cRouter.PUT("/hpa", api.PutHpaResource)
 				cRouter.DELETE("/hpa", api.DeleteHpaResource)
-				cRouter.HEAD("/deployments", api.GetTillerStatus)
-				cRouter.DELETE("/deployments/:name", api.DeleteDeployment)
-				cRouter.PUT("/deployments/:name", api.UpgradeDeployment)
-				cRouter.HEAD("/deployments/:name", api.HelmDeploymentStatus)
+				cRouter.HEAD("/deployments", deploymentAPI.GetTillerStatus)
+				cRouter.GET("/helm/backend", api.GetHelmBackend)
+				cRouter.PUT("/helm/backend", api.UpdateHelmBackend)
+				cRouter.DELETE("/deployments/:name", deploymentAuthorizationMiddleware, deploymentAPI.DeleteDeployment)
+				cRouter.PUT("/deployments/:name", deploymentAuthorizationMiddleware, deploymentAPI.UpgradeDeployment)
+				cRouter.GET("/deployments/:name/history", deploymentAuthorizationMiddleware, deploymentAPI.GetDeploymentHistory)
+				cRouter.GET("/deployments/:name/diff", deploymentAuthorizationMiddleware, deploymentAPI.GetDeploymentDiff)
+				cRouter.POST("/deployments/:name/rollback", deploymentAuthorizationMiddleware, deploymentAPI.RollbackDeployment)
+				cRouter.HEAD("/deployments/:name", deploymentAuthorizationMiddleware, deploymentAPI.HelmDeploymentStatus)
 
 				cRouter.GET("/images", api.ListImages)
-				cRouter.GET("/images/:imageDigest/deployments", api.GetImageDeployments)
-				cRouter.GET("/deployments/:name/images", api.GetDeploymentImages)
+				cRouter.GET("/images/:imageDigest/deployments", deploymentAPI.GetImageDeployments)
+				cRouter.GET("/deployments/:name/images", deploymentAuthorizationMiddleware, api.GetDeploymentImages)
 			}
 
 			clusterSecretStore := clustersecret.NewStore(
@@ -672,11 +835,15 @@ func main() {
 
 				if conf.Cluster.Monitoring.Enabled {
 					endpointManager := endpoints.NewEndpointManager(logger)
-					helmService := helm.NewHelmService(helmadapter.NewClusterService(clusterManager), logger)
 					monitoringConfig := featureMonitoring.NewFeatureConfiguration()
 					featureManagers = append(featureManagers, featureMonitoring.MakeFeatureManager(clusterGetter, secretStore, endpointManager, helmService, monitoringConfig, logger))
 				}
 
+				if conf.Cluster.Logging.Enabled {
+					loggingConfig := featureLogging.NewFeatureConfiguration()
+					featureManagers = append(featureManagers, featureLogging.MakeFeatureManager(helmService, loggingConfig, logger))
+				}
+
 				if conf.Cluster.SecurityScan.Enabled {
 					customAnchoreConfigProvider := securityscan.NewCustomAnchoreConfigProvider(
 						featureRepository,
@@ -749,6 +916,32 @@ func main() {
 				cRouter.Any("/features/:featureName", gin.WrapH(router))
 			}
 
+			{
+				logger := commonLogger.WithFields(map[string]interface{}{"module": "application"})
+				errorHandler := emperror.MakeContextAware(emperror.WithDetails(errorHandler, "module", "application"))
+
+				service := application.NewService(
+					applicationadapter.NewGormStore(db),
+					applicationadapter.NewCadenceReconciler(workflowClient),
+					applicationadapter.NewHelmReleases(helmadapter.NewClusterService(clusterManager)),
+				)
+				endpoints := applicationdriver.TraceEndpoints(applicationdriver.MakeEndpoints(
+					service,
+					kitxendpoint.Chain(endpointMiddleware...),
+					appkit.EndpointLogger(logger),
+				))
+
+				applicationdriver.RegisterHTTPHandlers(
+					endpoints,
+					clusterRouter.PathPrefix("/applications").Subrouter(),
+					kitxhttp.ServerOptions(httpServerOptions),
+					kithttp.ServerErrorHandler(errorHandler),
+				)
+
+				cRouter.Any("/applications", gin.WrapH(router))
+				cRouter.Any("/applications/*path", gin.WrapH(router))
+			}
+
 			// ClusterGroupAPI
 			cgroupsAPI := cgroupAPI.NewAPI(clusterGroupManager, deploymentManager, logrusLogger, errorHandler)
 			cgroupsAPI.AddRoutes(orgs.Group("/:orgid/clustergroups"))
@@ -795,21 +988,30 @@ func main() {
 			clusterAuthAPI.RegisterRoutes(cRouter, engine)
 
 			orgs.GET("/:orgid/helm/repos", api.HelmReposGet)
-			orgs.POST("/:orgid/helm/repos", api.HelmReposAdd)
-			orgs.PUT("/:orgid/helm/repos/:name", api.HelmReposModify)
-			orgs.PUT("/:orgid/helm/repos/:name/update", api.HelmReposUpdate)
-			orgs.DELETE("/:orgid/helm/repos/:name", api.HelmReposDelete)
+			orgs.POST("/:orgid/helm/repos", helmRepoAPI.HelmReposAdd)
+			orgs.PUT("/:orgid/helm/repos/:name", helmRepoAuthorizationMiddleware, helmRepoAPI.HelmReposModify)
+			orgs.PUT("/:orgid/helm/repos/:name/update", helmRepoAuthorizationMiddleware, api.HelmReposUpdate)
+			orgs.DELETE("/:orgid/helm/repos/:name", helmRepoAuthorizationMiddleware, api.HelmReposDelete)
 			orgs.GET("/:orgid/helm/charts", api.HelmCharts)
-			orgs.GET("/:orgid/helm/chart/:reponame/:name", api.HelmChart)
+			orgs.GET("/:orgid/helm/chart/:reponame/:name", helmChartAuthorizationMiddleware, api.HelmChart)
+
+			chartSchemaAPI := api.NewChartSchemaAPI(chartSchemaStore)
+			orgs.GET("/:orgid/helm/schemas", chartSchemaAPI.ListChartSchemas)
+			orgs.GET("/:orgid/helm/schemas/:reponame/:name", helmChartAuthorizationMiddleware, chartSchemaAPI.GetChartSchema)
+			orgs.PUT("/:orgid/helm/schemas/:reponame/:name", helmChartAuthorizationMiddleware, chartSchemaAPI.SetChartSchema)
+			orgs.DELETE("/:orgid/helm/schemas/:reponame/:name", helmChartAuthorizationMiddleware, chartSchemaAPI.DeleteChartSchema)
 			orgs.GET("/:orgid/secrets", api.ListSecrets)
-			orgs.GET("/:orgid/secrets/:id", api.GetSecret)
+			orgs.GET("/:orgid/secrets/:id", secretAuthorizationMiddleware, api.GetSecret)
 			orgs.POST("/:orgid/secrets", api.AddSecrets)
-			orgs.PUT("/:orgid/secrets/:id", api.UpdateSecrets)
-			orgs.DELETE("/:orgid/secrets/:id", api.DeleteSecrets)
-			orgs.GET("/:orgid/secrets/:id/validate", api.ValidateSecret)
-			orgs.GET("/:orgid/secrets/:id/tags", api.GetSecretTags)
-			orgs.PUT("/:orgid/secrets/:id/tags/*tag", api.AddSecretTag)
-			orgs.DELETE("/:orgid/secrets/:id/tags/*tag", api.DeleteSecretTag)
+			orgs.PUT("/:orgid/secrets/:id", secretAuthorizationMiddleware, api.UpdateSecrets)
+			orgs.DELETE("/:orgid/secrets/:id", secretAuthorizationMiddleware, secretAPI.DeleteSecrets)
+			orgs.GET("/:orgid/secrets/:id/versions", secretAuthorizationMiddleware, api.ListSecretVersions)
+			orgs.GET("/:orgid/secrets/:id/versions/:version", secretAuthorizationMiddleware, api.GetSecretVersion)
+			orgs.POST("/:orgid/secrets/:id/versions/:version/rollback", secretAuthorizationMiddleware, api.RollbackSecret)
+			orgs.GET("/:orgid/secrets/:id/validate", secretAuthorizationMiddleware, api.ValidateSecret)
+			orgs.GET("/:orgid/secrets/:id/tags", secretAuthorizationMiddleware, api.GetSecretTags)
+			orgs.PUT("/:orgid/secrets/:id/tags/*tag", secretAuthorizationMiddleware, api.AddSecretTag)
+			orgs.DELETE("/:orgid/secrets/:id/tags/*tag", secretAuthorizationMiddleware, api.DeleteSecretTag)
 			orgs.GET("/:orgid/users", userAPI.GetUsers)
 			orgs.GET("/:orgid/users/:id", userAPI.GetUsers)
 
@@ -856,6 +1058,153 @@ func main() {
 				orgs.Any("/:orgid/google/projects", gin.WrapH(router))
 			}
 
+			{
+				logger := commonLogger.WithFields(map[string]interface{}{"module": "role"})
+				errorHandler := emperror.MakeContextAware(emperror.WithDetails(errorHandler, "module", "role"))
+
+				service := role.NewService(
+					commonadapter.OrgIDContextExtractorFunc(auth.GetCurrentOrganizationID),
+					roleStore,
+				)
+				endpoints := roledriver.TraceEndpoints(roledriver.MakeEndpoints(
+					service,
+					kitxendpoint.Chain(endpointMiddleware...),
+					appkit.EndpointLogger(logger),
+				))
+
+				roledriver.RegisterHTTPHandlers(
+					endpoints,
+					orgRouter.PathPrefix("/roles").Subrouter(),
+					kitxhttp.ServerOptions(httpServerOptions),
+					kithttp.ServerErrorHandler(errorHandler),
+				)
+
+				orgs.Any("/:orgid/roles", gin.WrapH(router))
+				orgs.Any("/:orgid/roles/*path", gin.WrapH(router))
+			}
+
+			{
+				logger := commonLogger.WithFields(map[string]interface{}{"module": "policy"})
+				errorHandler := emperror.MakeContextAware(emperror.WithDetails(errorHandler, "module", "policy"))
+
+				service := policy.NewService(
+					commonadapter.OrgIDContextExtractorFunc(auth.GetCurrentOrganizationID),
+					policyStore,
+				)
+				endpoints := policydriver.TraceEndpoints(policydriver.MakeEndpoints(
+					service,
+					kitxendpoint.Chain(endpointMiddleware...),
+					appkit.EndpointLogger(logger),
+				))
+
+				policydriver.RegisterHTTPHandlers(
+					endpoints,
+					orgRouter.PathPrefix("/policies").Subrouter(),
+					kitxhttp.ServerOptions(httpServerOptions),
+					kithttp.ServerErrorHandler(errorHandler),
+				)
+
+				orgs.Any("/:orgid/policies", gin.WrapH(router))
+				orgs.Any("/:orgid/policies/*path", gin.WrapH(router))
+			}
+
+			{
+				logger := commonLogger.WithFields(map[string]interface{}{"module": "auditlog"})
+				errorHandler := emperror.MakeContextAware(emperror.WithDetails(errorHandler, "module", "auditlog"))
+
+				service := auditlog.NewService(
+					commonadapter.OrgIDContextExtractorFunc(auth.GetCurrentOrganizationID),
+					auditlogadapter.NewGormStore(db),
+				)
+				endpoints := auditlogdriver.TraceEndpoints(auditlogdriver.MakeEndpoints(
+					service,
+					kitxendpoint.Chain(endpointMiddleware...),
+					appkit.EndpointLogger(logger),
+				))
+
+				auditlogdriver.RegisterHTTPHandlers(
+					endpoints,
+					orgRouter.PathPrefix("/audit").Subrouter(),
+					kitxhttp.ServerOptions(httpServerOptions),
+					kithttp.ServerErrorHandler(errorHandler),
+				)
+
+				orgs.Any("/:orgid/audit", gin.WrapH(router))
+				orgs.Any("/:orgid/audit/*path", gin.WrapH(router))
+			}
+
+			{
+				logger := commonLogger.WithFields(map[string]interface{}{"module": "secretrotation"})
+				errorHandler := emperror.MakeContextAware(emperror.WithDetails(errorHandler, "module", "secretrotation"))
+
+				service := secretrotation.NewService(
+					commonadapter.OrgIDContextExtractorFunc(auth.GetCurrentOrganizationID),
+					secretrotationadapter.NewGormPolicyStore(db),
+					secretrotationadapter.NewSecretStore(secret.Store),
+					secretrotationadapter.NewCadenceRotationStarter(workflowClient),
+					secretrotation.CloudProviders,
+				)
+				endpoints := secretrotationdriver.TraceEndpoints(secretrotationdriver.MakeEndpoints(
+					service,
+					kitxendpoint.Chain(endpointMiddleware...),
+					appkit.EndpointLogger(logger),
+				))
+
+				secretrotationdriver.RegisterHTTPHandlers(
+					endpoints,
+					orgRouter.PathPrefix("/secrets/{secretId}/rotation").Subrouter(),
+					kitxhttp.ServerOptions(httpServerOptions),
+					kithttp.ServerErrorHandler(errorHandler),
+				)
+
+				orgs.Any("/:orgid/secrets/:id/rotation", secretAuthorizationMiddleware, gin.WrapH(router))
+				orgs.Any("/:orgid/secrets/:id/rotation/*path", secretAuthorizationMiddleware, gin.WrapH(router))
+			}
+
+			{
+				logger := commonLogger.WithFields(map[string]interface{}{"module": "secretusage"})
+				errorHandler := emperror.MakeContextAware(emperror.WithDetails(errorHandler, "module", "secretusage"))
+
+				endpoints := secretusagedriver.TraceEndpoints(secretusagedriver.MakeEndpoints(
+					secretUsageService,
+					kitxendpoint.Chain(endpointMiddleware...),
+					appkit.EndpointLogger(logger),
+				))
+
+				secretusagedriver.RegisterHTTPHandlers(
+					endpoints,
+					orgRouter.PathPrefix("/secrets/{secretId}/usages").Subrouter(),
+					kitxhttp.ServerOptions(httpServerOptions),
+					kithttp.ServerErrorHandler(errorHandler),
+				)
+
+				orgs.GET("/:orgid/secrets/:id/usages", secretAuthorizationMiddleware, gin.WrapH(router))
+			}
+
+			{
+				logger := commonLogger.WithFields(map[string]interface{}{"module": "notification"})
+				errorHandler := emperror.MakeContextAware(emperror.WithDetails(errorHandler, "module", "notification"))
+
+				service := notification.NewService(
+					notificationadapter.NewGormStore(db),
+					commonadapter.OrgIDContextExtractorFunc(auth.GetCurrentOrganizationID),
+				)
+				endpoints := notificationdriver.TraceEndpoints(notificationdriver.MakeEndpoints(
+					service,
+					kitxendpoint.Chain(endpointMiddleware...),
+					appkit.EndpointLogger(logger),
+				))
+
+				notificationdriver.RegisterHTTPHandlers(
+					endpoints,
+					orgRouter.PathPrefix("/notifications").Subrouter(),
+					kitxhttp.ServerOptions(httpServerOptions),
+					kithttp.ServerErrorHandler(errorHandler),
+				)
+
+				orgs.GET("/:orgid/notifications", gin.WrapH(router))
+			}
+
 			orgs.GET("/:orgid", organizationAPI.GetOrganizations)
 			orgs.DELETE("/:orgid", organizationAPI.DeleteOrganization)
 		}
@@ -869,7 +1218,9 @@ func main() {
 			service := token.NewService(
 				auth.UserExtractor{},
 				tokenadapter.NewBankVaultsStore(tokenStore),
+				tokenMetadataStore,
 				tokenGenerator,
+				conf.Auth.Token.MaxLifetime,
 			)
 			service = tokendriver.AuthorizationMiddleware(auth.NewAuthorizer(db, organizationStore))(service)
 
@@ -925,12 +1276,15 @@ func main() {
 			}
 		}
 
-		backups.AddRoutes(orgs.Group("/:orgid/clusters/:id/backups"))
-		backupservice.AddRoutes(orgs.Group("/:orgid/clusters/:id/backupservice"))
-		restores.AddRoutes(orgs.Group("/:orgid/clusters/:id/restores"))
-		schedules.AddRoutes(orgs.Group("/:orgid/clusters/:id/schedules"))
+		backups.AddRoutes(orgs.Group("/:orgid/clusters/:id/backups", clusterAuthorizationMiddleware), clusterManager, workflowClient)
+		backupservice.AddRoutes(orgs.Group("/:orgid/clusters/:id/backupservice", clusterAuthorizationMiddleware))
+		restores.AddRoutes(orgs.Group("/:orgid/clusters/:id/restores", clusterAuthorizationMiddleware), workflowClient)
+		schedules.AddRoutes(orgs.Group("/:orgid/clusters/:id/schedules", clusterAuthorizationMiddleware))
 		buckets.AddRoutes(orgs.Group("/:orgid/backupbuckets"))
 		backups.AddOrgRoutes(orgs.Group("/:orgid/backups"), clusterManager)
+		migrations.AddRoutes(orgs.Group("/:orgid/backupmigrations"), clusterManager, workflowClient, resourceEnforcer, tokenScopeEnforcer)
+		policies.AddRoutes(orgs.Group("/:orgid/backuppolicies"), clusterManager)
+		policies.AddReportRoutes(orgs.Group("/:orgid/backupcompliance"), clusterManager)
 	}
 
 	arkEvents.NewClusterEventHandler(arkEvents.NewClusterEvents(clusterEventBus), config.DB(), logrusLogger)
@@ -947,6 +1301,7 @@ func main() {
 			viper.GetDuration(config.ARKBucketSyncInterval),
 			viper.GetDuration(config.ARKRestoreSyncInterval),
 			viper.GetDuration(config.ARKBackupSyncInterval),
+			viper.GetDuration(config.ARKPolicySyncInterval),
 		)
 	}
 
@@ -955,7 +1310,7 @@ func main() {
 	internalBindAddr := viper.GetString("pipeline.internalBindAddr")
 	logger.Info("Pipeline internal API listening", map[string]interface{}{"address": "http://" + internalBindAddr})
 
-	go createInternalAPIRouter(skipPaths, db, basePath, clusterAPI, logger, logrusLogger).Run(internalBindAddr) // nolint: errcheck
+	go createInternalAPIRouter(skipPaths, auditSink, basePath, clusterAPI, logger, logrusLogger).Run(internalBindAddr) // nolint: errcheck
 
 	bindAddr := viper.GetString("pipeline.bindaddr")
 	if port := viper.GetInt("pipeline.listenport"); port != 0 { // TODO: remove deprecated option
@@ -977,7 +1332,7 @@ func main() {
 	}
 }
 
-func createInternalAPIRouter(skipPaths []string, db *gorm.DB, basePath string, clusterAPI *api.ClusterAPI, logger logur.Logger, logrusLogger logrus.FieldLogger) *gin.Engine {
+func createInternalAPIRouter(skipPaths []string, auditSink audit.Sink, basePath string, clusterAPI *api.ClusterAPI, logger logur.Logger, logrusLogger logrus.FieldLogger) *gin.Engine {
 	// Initialise Gin router for Internal API
 	internalRouter := gin.New()
 	internalRouter.Use(correlationid.Middleware())
@@ -985,7 +1340,7 @@ func createInternalAPIRouter(skipPaths []string, db *gorm.DB, basePath string, c
 	internalRouter.Use(gin.Recovery())
 	if viper.GetBool("audit.enabled") {
 		logger.Info("Audit enabled, installing Gin audit middleware to internal router")
-		internalRouter.Use(audit.LogWriter(skipPaths, viper.GetStringSlice("audit.headers"), db, logrusLogger))
+		internalRouter.Use(audit.LogWriter(skipPaths, viper.GetStringSlice("audit.headers"), auditSink, logrusLogger))
 	}
 	internalGroup := internalRouter.Group(path.Join(basePath, "api", "v1/", "orgs"))
 	internalGroup.Use(auth.InternalUserHandler)
@@ -19,9 +19,16 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/banzaicloud/pipeline/internal/app/frontend/notification/notificationadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application/applicationadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/policy/policyadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role/roleadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/token/tokenadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretexpiry/secretexpiryadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation/secretrotationadapter"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
 	"github.com/banzaicloud/pipeline/internal/clustergroup/deployment"
 	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/internal/helm/helmadapter"
 
 	"github.com/banzaicloud/pipeline/auth"
 	route53model "github.com/banzaicloud/pipeline/dns/route53/model"
@@ -84,5 +91,33 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger, commonLogger common.Logger)
 		return err
 	}
 
+	if err := roleadapter.Migrate(db, commonLogger); err != nil {
+		return err
+	}
+
+	if err := policyadapter.Migrate(db, commonLogger); err != nil {
+		return err
+	}
+
+	if err := tokenadapter.Migrate(db, commonLogger); err != nil {
+		return err
+	}
+
+	if err := secretrotationadapter.Migrate(db, commonLogger); err != nil {
+		return err
+	}
+
+	if err := secretexpiryadapter.Migrate(db, commonLogger); err != nil {
+		return err
+	}
+
+	if err := applicationadapter.Migrate(db, commonLogger); err != nil {
+		return err
+	}
+
+	if err := helmadapter.Migrate(db, commonLogger); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,57 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application"
+)
+
+func registerApplicationWorkflows(store application.Store, releases application.Releases) {
+	workflow.RegisterWithOptions(application.ReconcileWorkflow, workflow.RegisterOptions{Name: application.ReconcileWorkflowName})
+	workflow.RegisterWithOptions(application.DeleteWorkflow, workflow.RegisterOptions{Name: application.DeleteWorkflowName})
+
+	{
+		a := application.NewPlanActivity(store, releases)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: application.PlanActivityName})
+	}
+
+	{
+		a := application.NewApplyReleaseActivity(releases)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: application.ApplyReleaseActivityName})
+	}
+
+	{
+		a := application.NewRollbackReleaseActivity(releases)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: application.RollbackReleaseActivityName})
+	}
+
+	{
+		a := application.NewDeleteReleaseActivity(releases)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: application.DeleteReleaseActivityName})
+	}
+
+	{
+		a := application.NewRecordStatusActivity(store)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: application.RecordStatusActivityName})
+	}
+
+	{
+		a := application.NewRemoveApplicationActivity(store)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: application.RemoveApplicationActivityName})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/ark/migration"
+	"github.com/banzaicloud/pipeline/internal/ark/restore"
+	"github.com/banzaicloud/pipeline/internal/ark/verification"
+)
+
+func registerArkWorkflows(clusters migration.Clusters, db *gorm.DB, logger logrus.FieldLogger) {
+	workflow.RegisterWithOptions(migration.MigrationWorkflow, workflow.RegisterOptions{Name: migration.MigrationWorkflowName})
+
+	{
+		a := migration.NewCreateBackupActivity(clusters, db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: migration.CreateBackupActivityName})
+	}
+
+	{
+		a := migration.NewPrepareTargetActivity(clusters, db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: migration.PrepareTargetActivityName})
+	}
+
+	{
+		a := migration.NewRestoreActivity(clusters, db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: migration.RestoreActivityName})
+	}
+
+	{
+		a := migration.NewRemoveDeploymentActivity(clusters, db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: migration.RemoveDeploymentActivityName})
+	}
+
+	{
+		a := migration.NewRecordStatusActivity(db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: migration.RecordStatusActivityName})
+	}
+
+	workflow.RegisterWithOptions(verification.VerificationWorkflow, workflow.RegisterOptions{Name: verification.VerificationWorkflowName})
+
+	{
+		a := verification.NewPlanActivity(db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: verification.PlanActivityName})
+	}
+
+	{
+		a := verification.NewRestoreServicesActivity(clusters, db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: verification.RestoreServicesActivityName})
+	}
+
+	{
+		a := verification.NewCheckReadinessActivity(clusters, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: verification.CheckReadinessActivityName})
+	}
+
+	{
+		a := verification.NewCleanupActivity(clusters, db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: verification.CleanupActivityName})
+	}
+
+	{
+		a := verification.NewRecordResultActivity(db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: verification.RecordResultActivityName})
+	}
+
+	workflow.RegisterWithOptions(restore.ObjectRestoreWorkflow, workflow.RegisterOptions{Name: restore.ObjectRestoreWorkflowName})
+
+	{
+		a := restore.NewRestoreObjectsActivity(clusters, db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: restore.RestoreObjectsActivityName})
+	}
+
+	{
+		a := restore.NewFailRestoreActivity(clusters, db, logger)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: restore.FailRestoreActivityName})
+	}
+}
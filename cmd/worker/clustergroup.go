@@ -0,0 +1,41 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/clustergroup/deployment"
+)
+
+func registerClusterGroupWorkflows(deploymentManager *deployment.CGDeploymentManager) {
+	workflow.RegisterWithOptions(deployment.RolloutWorkflow, workflow.RegisterOptions{Name: deployment.RolloutWorkflowName})
+
+	{
+		a := deployment.NewStartRolloutWaveActivity(deploymentManager)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: deployment.StartRolloutWaveActivityName})
+	}
+
+	{
+		a := deployment.NewDeployRolloutTargetActivity(deploymentManager)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: deployment.DeployRolloutTargetActivityName})
+	}
+
+	{
+		a := deployment.NewFinishRolloutWaveActivity(deploymentManager)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: deployment.FinishRolloutWaveActivityName})
+	}
+}
@@ -37,7 +37,11 @@ import (
 	"github.com/banzaicloud/pipeline/cluster"
 	conf "github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/dns"
+	legacyhelm "github.com/banzaicloud/pipeline/helm"
 	anchore2 "github.com/banzaicloud/pipeline/internal/anchore"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application/applicationadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation/secretrotationadapter"
+	arkClusterManager "github.com/banzaicloud/pipeline/internal/ark/clustermanager"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	intClusterAuth "github.com/banzaicloud/pipeline/internal/cluster/auth"
 	"github.com/banzaicloud/pipeline/internal/cluster/clusteradapter"
@@ -50,10 +54,13 @@ import (
 	"github.com/banzaicloud/pipeline/internal/clusterfeature"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
 	featureDns "github.com/banzaicloud/pipeline/internal/clusterfeature/features/dns"
+	featureLogging "github.com/banzaicloud/pipeline/internal/clusterfeature/features/logging"
 	featureMonitoring "github.com/banzaicloud/pipeline/internal/clusterfeature/features/monitoring"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/features/securityscan"
 	"github.com/banzaicloud/pipeline/internal/clusterfeature/features/securityscan/securityscanadapter"
 	featureVault "github.com/banzaicloud/pipeline/internal/clusterfeature/features/vault"
+	cgroupAdapter "github.com/banzaicloud/pipeline/internal/clustergroup/adapter"
+	"github.com/banzaicloud/pipeline/internal/clustergroup/deployment"
 	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
 	"github.com/banzaicloud/pipeline/internal/global"
 	"github.com/banzaicloud/pipeline/internal/helm"
@@ -69,6 +76,7 @@ import (
 	"github.com/banzaicloud/pipeline/internal/providers/pke/pkeworkflow"
 	"github.com/banzaicloud/pipeline/internal/providers/pke/pkeworkflow/pkeworkflowadapter"
 	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
 	anchore "github.com/banzaicloud/pipeline/internal/security"
 	pkgAuth "github.com/banzaicloud/pipeline/pkg/auth"
 	"github.com/banzaicloud/pipeline/secret"
@@ -195,6 +203,16 @@ func main() {
 		clusters := pkeworkflowadapter.NewClusterManagerAdapter(clusterManager)
 		secretStore := pkeworkflowadapter.NewSecretStore(secret.Store)
 
+		// the credentials of private chart repositories are read from their secrets when the repositories are accessed
+		legacyhelm.SetRepositorySecretGetter(func(organizationID uint, secretID string) (string, map[string]string, error) {
+			secretItem, err := secret.Store.Get(organizationID, secretID)
+			if err != nil {
+				return "", nil, err
+			}
+
+			return secretItem.Type, secretItem.Values, nil
+		})
+
 		clusterSecretStore := clustersecret.NewStore(
 			clustersecretadapter.NewClusterManagerAdapter(clusterManager),
 			clustersecretadapter.NewSecretStore(secret.Store),
@@ -330,7 +348,7 @@ func main() {
 
 			logger := commonadapter.NewLogger(logger) // TODO: make this a context aware logger
 			featureRepository := clusterfeatureadapter.NewGormFeatureRepository(db, logger)
-			helmService := helm.NewHelmService(helmadapter.NewClusterService(clusterManager), logger)
+			helmService := helm.NewHelmService(helmadapter.NewClusterService(clusterManager), helmadapter.NewBackends(), logger)
 			kubernetesService := kubernetes.NewKubernetesService(helmadapter.NewClusterService(clusterManager), logger)
 
 			clusterGetter := clusterfeatureadapter.MakeClusterGetter(clusterManager)
@@ -360,6 +378,7 @@ func main() {
 			featureWhitelistService := securityscan.NewFeatureWhitelistService(clusterGetter, anchore.NewSecurityResourceService(logger), logger)
 
 			monitorConfiguration := featureMonitoring.NewFeatureConfiguration()
+			loggingConfiguration := featureLogging.NewFeatureConfiguration()
 			featureOperatorRegistry := clusterfeature.MakeFeatureOperatorRegistry([]clusterfeature.FeatureOperator{
 				featureDns.MakeFeatureOperator(clusterGetter, clusterService, helmService, logger, orgDomainService, commonSecretStore),
 				securityscan.MakeFeatureOperator(
@@ -375,11 +394,48 @@ func main() {
 				),
 				featureVault.MakeFeatureOperator(clusterGetter, clusterService, helmService, kubernetesService, commonSecretStore, logger),
 				featureMonitoring.MakeFeatureOperator(clusterGetter, clusterService, helmService, monitorConfiguration, logger, commonSecretStore),
+				featureLogging.MakeFeatureOperator(clusterGetter, clusterService, helmService, kubernetesService, loggingConfiguration, logger, commonSecretStore),
 			})
 
 			registerClusterFeatureWorkflows(featureOperatorRegistry, featureRepository)
+
+			// Cluster group deployments are rolled out wave by wave using a workflow
+			clusterDomainGetter := featureDns.NewClusterDomainGetter(
+				featureRepository,
+				featureDns.MakeFeatureManager(clusterGetter, logger, orgDomainService),
+			)
+			registerClusterGroupWorkflows(deployment.NewCGDeploymentManager(
+				db,
+				cgroupAdapter.NewClusterGetter(clusterManager),
+				clusterDomainGetter,
+				helmadapter.NewBackends(),
+				nil,
+				conf.Logger().WithField("subsystem", "clustergroup"),
+				errorHandler,
+			))
+		}
+
+		{
+			keyProviders := secretrotationadapter.NewCloudKeyProviders()
+
+			// Generated TLS certificates are renewed using the rotation workflow
+			keyProviders[secrettype.TLSSecretType] = secretrotationadapter.NewTLSKeyProvider(viper.GetString("tls.validity"))
+
+			registerSecretRotationWorkflows(
+				secretrotationadapter.NewSecretStore(secret.Store),
+				secretrotationadapter.NewGormPolicyStore(db),
+				keyProviders,
+				secretrotationadapter.NewClusterSecretSyncer(clusterManager, secret.Store),
+			)
 		}
 
+		registerApplicationWorkflows(
+			applicationadapter.NewGormStore(db),
+			applicationadapter.NewHelmReleases(helmadapter.NewClusterService(clusterManager)),
+		)
+
+		registerArkWorkflows(arkClusterManager.New(clusterManager), db, conf.Logger().WithField("subsystem", "ark"))
+
 		var closeCh = make(chan struct{})
 
 		group.Add(
@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+)
+
+func registerSecretRotationWorkflows(
+	secrets secretrotation.SecretStore,
+	policies secretrotation.PolicyStore,
+	providers secretrotation.KeyProviders,
+	syncer secretrotation.ClusterSecretSyncer,
+) {
+	workflow.RegisterWithOptions(secretrotation.Workflow, workflow.RegisterOptions{Name: secretrotation.WorkflowName})
+
+	{
+		a := secretrotation.NewCreateKeyActivity(secrets, providers)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: secretrotation.CreateKeyActivityName})
+	}
+
+	{
+		a := secretrotation.NewVerifyKeyActivity(secrets, providers)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: secretrotation.VerifyKeyActivityName})
+	}
+
+	{
+		a := secretrotation.NewRollbackSecretActivity(secrets)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: secretrotation.RollbackSecretActivityName})
+	}
+
+	{
+		a := secretrotation.NewSyncClustersActivity(syncer)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: secretrotation.SyncClustersActivityName})
+	}
+
+	{
+		a := secretrotation.NewRevokeKeyActivity(secrets, providers)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: secretrotation.RevokeKeyActivityName})
+	}
+
+	{
+		a := secretrotation.NewRecordRotationActivity(policies)
+		activity.RegisterWithOptions(a.Execute, activity.RegisterOptions{Name: secretrotation.RecordRotationActivityName})
+	}
+}
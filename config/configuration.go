@@ -92,6 +92,7 @@ const (
 	ARKBucketSyncInterval  = "ark.bucketSyncInterval"
 	ARKRestoreSyncInterval = "ark.restoreSyncInterval"
 	ARKBackupSyncInterval  = "ark.backupSyncInterval"
+	ARKPolicySyncInterval  = "ark.policySyncInterval"
 	ARKRestoreWaitTimeout  = "ark.restoreWaitTimeout"
 
 	AutoscaleClusterAutoscalerChartVersion = "autoscale.clusterAutoscalerChartVersion"
@@ -131,6 +132,10 @@ const (
 	LoggingOperatorChartVersion = "loggingOperator.chartVersion"
 	LoggingOperatorImageTag     = "loggingOperator.imageTag"
 
+	LoggingOperatorChartKey               = "loggingOperator.chart"
+	LoggingOperatorLoggingChartKey        = "loggingOperator.loggingChart"
+	LoggingOperatorLoggingChartVersionKey = "loggingOperator.loggingChartVersion"
+
 	// Spotguides constants
 	SpotguideAllowPrereleases                = "spotguide.allowPrereleases"
 	SpotguideAllowPrivateRepos               = "spotguide.allowPrivateRepos"
@@ -193,6 +198,8 @@ const (
 	HelmStableRepositoryKey = "helm.stableRepositoryURL"
 	HelmBanzaiRepositoryKey = "helm.banzaiRepositoryURL"
 	HelmLokiRepositoryKey   = "helm.lokiRepositoryURL"
+	HelmBackendKey          = "helm.backend"
+	HelmHelm3BinaryKey      = "helm.helm3Binary"
 )
 
 // Init initializes the configurations
@@ -216,6 +223,8 @@ func init() {
 	viper.SetDefault("cicd.insecure", false)
 	viper.SetDefault("cicd.scm", "github")
 	viper.SetDefault("helm.tillerVersion", "v2.14.2")
+	viper.SetDefault(HelmBackendKey, "tiller")
+	viper.SetDefault(HelmHelm3BinaryKey, "helm3")
 	viper.SetDefault(HelmStableRepositoryKey, "https://kubernetes-charts.storage.googleapis.com")
 	viper.SetDefault(HelmBanzaiRepositoryKey, "https://kubernetes-charts.banzaicloud.com")
 	viper.SetDefault(HelmLokiRepositoryKey, "https://grafana.github.io/loki/charts")
@@ -261,6 +270,16 @@ func init() {
 	viper.SetDefault("audit.enabled", true)
 	viper.SetDefault("audit.headers", []string{"secretId"})
 	viper.SetDefault("audit.skippaths", []string{"/auth/dex/callback", "/pipeline/api"})
+	viper.SetDefault("audit.retention.enabled", false)
+	viper.SetDefault("audit.retention.period", "2160h") // 90 days
+	viper.SetDefault("audit.retention.interval", "1h")
+	viper.SetDefault("secretRotation.scheduler.enabled", false)
+	viper.SetDefault("secretRotation.scheduler.interval", "10m")
+	viper.SetDefault("secretExpiry.enabled", false)
+	viper.SetDefault("secretExpiry.interval", "1h")
+	viper.SetDefault("secretExpiry.notifyBefore", "720h") // 30 days
+	viper.SetDefault("secretExpiry.renewBefore", "336h")  // 14 days
+
 	viper.SetDefault("tls.validity", "8760h") // 1 year
 	viper.SetDefault(DNSBaseDomain, "example.org")
 	viper.SetDefault(DNSGcIntervalMinute, 1)
@@ -281,6 +300,7 @@ func init() {
 	viper.SetDefault(ARKBucketSyncInterval, "10m")
 	viper.SetDefault(ARKRestoreSyncInterval, "20s")
 	viper.SetDefault(ARKBackupSyncInterval, "20s")
+	viper.SetDefault(ARKPolicySyncInterval, "5m")
 	viper.SetDefault(ARKRestoreWaitTimeout, "5m")
 
 	viper.SetDefault(AutoscaleClusterAutoscalerChartVersion, "0.12.3")
@@ -299,6 +319,9 @@ func init() {
 	// empty string means the latest version of the chart will be installed
 	viper.SetDefault(LoggingOperatorChartVersion, "")
 	viper.SetDefault(LoggingOperatorImageTag, "0.0.5")
+	viper.SetDefault(LoggingOperatorChartKey, "banzaicloud-stable/logging-operator")
+	viper.SetDefault(LoggingOperatorLoggingChartKey, "banzaicloud-stable/logging-operator-logging")
+	viper.SetDefault(LoggingOperatorLoggingChartVersionKey, "")
 
 	_ = viper.BindEnv(ControlPlaneNamespace, "KUBERNETES_NAMESPACE")
 	viper.SetDefault(ControlPlaneNamespace, "default")
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS `organization_role_bindings`;
+DROP TABLE IF EXISTS `organization_roles`;
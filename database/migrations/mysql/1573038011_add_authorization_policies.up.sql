@@ -0,0 +1,13 @@
+CREATE TABLE `authorization_policies` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `organization_id` int(10) unsigned NOT NULL,
+  `user_id` int(10) unsigned DEFAULT NULL,
+  `token_id` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `resources` text COLLATE utf8mb4_unicode_ci,
+  `verbs` text COLLATE utf8mb4_unicode_ci,
+  PRIMARY KEY (`id`),
+  KEY `idx_authorization_policies_org_id` (`organization_id`),
+  KEY `idx_authorization_policies_user_id` (`user_id`),
+  KEY `idx_authorization_policies_token_id` (`token_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
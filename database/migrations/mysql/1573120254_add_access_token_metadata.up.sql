@@ -0,0 +1,13 @@
+CREATE TABLE `access_token_metadata` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `token_id` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `scoped` tinyint(1) DEFAULT NULL,
+  `organization_id` int(10) unsigned DEFAULT NULL,
+  `cluster_ids` text COLLATE utf8mb4_unicode_ci,
+  `read_only` tinyint(1) DEFAULT NULL,
+  `last_used_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_access_token_metadata_token_id` (`token_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+CREATE TABLE `secret_rotation_policies` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `organization_id` int(10) unsigned NOT NULL,
+  `secret_id` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `provider` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `interval` bigint(20) DEFAULT NULL,
+  `last_rotated_at` timestamp NULL DEFAULT NULL,
+  `next_rotation_at` timestamp NULL DEFAULT NULL,
+  `last_error` text COLLATE utf8mb4_unicode_ci,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_secret_rotation_policies_org_secret` (`organization_id`,`secret_id`),
+  KEY `idx_secret_rotation_policies_next_rotation_at` (`next_rotation_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
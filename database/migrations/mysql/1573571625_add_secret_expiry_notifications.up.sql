@@ -0,0 +1,12 @@
+ALTER TABLE `notifications` ADD COLUMN `organization_id` int(10) unsigned NOT NULL DEFAULT 0;
+CREATE INDEX `idx_notifications_organization_id` ON `notifications` (`organization_id`);
+
+CREATE TABLE `secret_expiry_notifications` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `organization_id` int(10) unsigned NOT NULL,
+  `secret_id` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `expires_at` timestamp NOT NULL DEFAULT '1970-01-01 00:00:01',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_secret_expiry_notifications_org_secret_expiry` (`organization_id`,`secret_id`,`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
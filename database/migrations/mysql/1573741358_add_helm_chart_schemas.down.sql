@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `helm_chart_schemas`;
@@ -0,0 +1,10 @@
+CREATE TABLE `helm_chart_schemas` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `organization_id` int(10) unsigned NOT NULL,
+  `chart` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `schema` text COLLATE utf8mb4_unicode_ci,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_helm_chart_schemas_org_chart` (`organization_id`,`chart`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
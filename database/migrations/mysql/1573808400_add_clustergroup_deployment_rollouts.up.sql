@@ -0,0 +1,14 @@
+ALTER TABLE `clustergroup_deployments`
+  ADD COLUMN `rollout_strategy` text COLLATE utf8mb4_unicode_ci,
+  ADD COLUMN `rollout_status` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  ADD COLUMN `rollout_wave` int(11) DEFAULT NULL,
+  ADD COLUMN `rollout_waves` int(11) DEFAULT NULL,
+  ADD COLUMN `rollout_error` text COLLATE utf8mb4_unicode_ci,
+  ADD COLUMN `rollout_generation` int(10) unsigned DEFAULT NULL;
+
+ALTER TABLE `clustergroup_deployment_target_clusters`
+  ADD COLUMN `wave` int(11) DEFAULT NULL,
+  ADD COLUMN `canary` tinyint(1) DEFAULT NULL,
+  ADD COLUMN `rollout_status` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  ADD COLUMN `rollout_error` text COLLATE utf8mb4_unicode_ci,
+  ADD COLUMN `previous_revision` int(11) DEFAULT NULL;
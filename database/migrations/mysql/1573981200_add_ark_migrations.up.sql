@@ -0,0 +1,20 @@
+CREATE TABLE `ark_migrations` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `source_cluster_id` int(10) unsigned NOT NULL,
+  `target_cluster_id` int(10) unsigned NOT NULL,
+  `bucket_id` int(10) unsigned DEFAULT NULL,
+  `backup_name` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `restore_name` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `options` json DEFAULT NULL,
+  `results` json DEFAULT NULL,
+  `status` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `step` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `status_message` text COLLATE utf8mb4_unicode_ci,
+  `organization_id` int(10) unsigned NOT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_ark_migrations_source_cluster_id` (`source_cluster_id`),
+  KEY `idx_ark_migrations_target_cluster_id` (`target_cluster_id`),
+  KEY `idx_ark_migrations_organization_id` (`organization_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,15 @@
+CREATE TABLE `ark_backup_policies` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `schedule` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `ttl` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `rpo` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `selector` json DEFAULT NULL,
+  `bucket` json DEFAULT NULL,
+  `options` json DEFAULT NULL,
+  `organization_id` int(10) unsigned NOT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_ark_backup_policies_org_name` (`name`,`organization_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,7 @@
+ALTER TABLE `ark_backups`
+  ADD COLUMN `verification_status` varchar(255),
+  ADD COLUMN `verification_message` text,
+  ADD COLUMN `verification_problems` json,
+  ADD COLUMN `verification_target_cluster_id` int(10) unsigned,
+  ADD COLUMN `verification_started_at` timestamp NULL DEFAULT NULL,
+  ADD COLUMN `verification_finished_at` timestamp NULL DEFAULT NULL;
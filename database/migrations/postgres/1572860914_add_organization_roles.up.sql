@@ -0,0 +1,21 @@
+CREATE TABLE "organization_roles" (
+  "id" serial,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "organization_id" integer NOT NULL,
+  "name" text NOT NULL,
+  "description" text,
+  "permissions" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_organization_roles_org_id_name ON "organization_roles"(organization_id, "name");
+
+CREATE TABLE "organization_role_bindings" (
+  "id" serial,
+  "role_id" integer NOT NULL,
+  "user_id" integer NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_organization_role_bindings_role_id_user_id ON "organization_role_bindings"(role_id, user_id);
@@ -0,0 +1,14 @@
+CREATE TABLE "authorization_policies" (
+  "id" serial,
+  "created_at" timestamp with time zone,
+  "organization_id" integer NOT NULL,
+  "user_id" integer,
+  "token_id" text,
+  "resources" text,
+  "verbs" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_authorization_policies_org_id ON "authorization_policies"(organization_id);
+CREATE INDEX idx_authorization_policies_user_id ON "authorization_policies"(user_id);
+CREATE INDEX idx_authorization_policies_token_id ON "authorization_policies"(token_id);
@@ -0,0 +1,14 @@
+CREATE TABLE "access_token_metadata" (
+  "id" serial,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "token_id" text NOT NULL,
+  "scoped" boolean,
+  "organization_id" integer,
+  "cluster_ids" text,
+  "read_only" boolean,
+  "last_used_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_access_token_metadata_token_id ON "access_token_metadata"(token_id);
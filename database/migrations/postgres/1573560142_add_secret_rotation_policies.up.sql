@@ -0,0 +1,16 @@
+CREATE TABLE "secret_rotation_policies" (
+  "id" serial,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "organization_id" integer NOT NULL,
+  "secret_id" text NOT NULL,
+  "provider" text,
+  "interval" bigint,
+  "last_rotated_at" timestamp with time zone,
+  "next_rotation_at" timestamp with time zone,
+  "last_error" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_secret_rotation_policies_org_secret ON "secret_rotation_policies"(organization_id, secret_id);
+CREATE INDEX idx_secret_rotation_policies_next_rotation_at ON "secret_rotation_policies"(next_rotation_at);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "secret_expiry_notifications";
+
+DROP INDEX idx_notifications_organization_id;
+ALTER TABLE "notifications" DROP COLUMN "organization_id";
@@ -0,0 +1,13 @@
+ALTER TABLE "notifications" ADD COLUMN "organization_id" integer NOT NULL DEFAULT 0;
+CREATE INDEX idx_notifications_organization_id ON "notifications"(organization_id);
+
+CREATE TABLE "secret_expiry_notifications" (
+  "id" serial,
+  "created_at" timestamp with time zone,
+  "organization_id" integer NOT NULL,
+  "secret_id" text NOT NULL,
+  "expires_at" timestamp with time zone NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_secret_expiry_notifications_org_secret_expiry ON "secret_expiry_notifications"(organization_id, secret_id, expires_at);
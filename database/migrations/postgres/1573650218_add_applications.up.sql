@@ -0,0 +1,14 @@
+CREATE TABLE "applications" (
+  "id" serial,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "cluster_id" integer NOT NULL,
+  "name" text NOT NULL,
+  "spec" text,
+  "status" text,
+  "status_message" text,
+  "releases" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_applications_cluster_name ON "applications"(cluster_id, name);
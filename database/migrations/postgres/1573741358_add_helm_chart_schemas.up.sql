@@ -0,0 +1,11 @@
+CREATE TABLE "helm_chart_schemas" (
+  "id" serial,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "organization_id" integer NOT NULL,
+  "chart" text NOT NULL,
+  "schema" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_helm_chart_schemas_org_chart ON "helm_chart_schemas"(organization_id, chart);
@@ -0,0 +1,14 @@
+ALTER TABLE "clustergroup_deployments"
+  DROP COLUMN "rollout_strategy",
+  DROP COLUMN "rollout_status",
+  DROP COLUMN "rollout_wave",
+  DROP COLUMN "rollout_waves",
+  DROP COLUMN "rollout_error",
+  DROP COLUMN "rollout_generation";
+
+ALTER TABLE "clustergroup_deployment_target_clusters"
+  DROP COLUMN "wave",
+  DROP COLUMN "canary",
+  DROP COLUMN "rollout_status",
+  DROP COLUMN "rollout_error",
+  DROP COLUMN "previous_revision";
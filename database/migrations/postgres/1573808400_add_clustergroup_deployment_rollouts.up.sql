@@ -0,0 +1,14 @@
+ALTER TABLE "clustergroup_deployments"
+  ADD COLUMN "rollout_strategy" text,
+  ADD COLUMN "rollout_status" text,
+  ADD COLUMN "rollout_wave" integer,
+  ADD COLUMN "rollout_waves" integer,
+  ADD COLUMN "rollout_error" text,
+  ADD COLUMN "rollout_generation" integer;
+
+ALTER TABLE "clustergroup_deployment_target_clusters"
+  ADD COLUMN "wave" integer,
+  ADD COLUMN "canary" boolean,
+  ADD COLUMN "rollout_status" text,
+  ADD COLUMN "rollout_error" text,
+  ADD COLUMN "previous_revision" integer;
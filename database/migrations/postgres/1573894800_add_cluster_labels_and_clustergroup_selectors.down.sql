@@ -0,0 +1,3 @@
+ALTER TABLE "clustergroups" DROP COLUMN "selector";
+
+DROP TABLE IF EXISTS "cluster_labels";
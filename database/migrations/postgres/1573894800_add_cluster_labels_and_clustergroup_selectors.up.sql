@@ -0,0 +1,13 @@
+CREATE TABLE "cluster_labels" (
+  "id" serial,
+  "cluster_id" integer,
+  "name" text,
+  "value" text,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_labels_id_name ON "cluster_labels"(cluster_id, name);
+
+ALTER TABLE "clustergroups" ADD COLUMN "selector" text;
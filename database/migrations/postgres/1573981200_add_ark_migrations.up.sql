@@ -0,0 +1,23 @@
+CREATE TABLE "ark_migrations" (
+  "id" serial,
+  "source_cluster_id" integer NOT NULL,
+  "target_cluster_id" integer NOT NULL,
+  "bucket_id" integer,
+  "backup_name" text,
+  "restore_name" text,
+  "options" json,
+  "results" json,
+  "status" text,
+  "step" text,
+  "status_message" text,
+  "organization_id" integer NOT NULL,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_ark_migrations_source_cluster_id ON "ark_migrations"(source_cluster_id);
+
+CREATE INDEX idx_ark_migrations_target_cluster_id ON "ark_migrations"(target_cluster_id);
+
+CREATE INDEX idx_ark_migrations_organization_id ON "ark_migrations"(organization_id);
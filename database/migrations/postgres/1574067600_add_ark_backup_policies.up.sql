@@ -0,0 +1,16 @@
+CREATE TABLE "ark_backup_policies" (
+  "id" serial,
+  "name" text,
+  "schedule" text,
+  "ttl" text,
+  "rpo" text,
+  "selector" json,
+  "bucket" json,
+  "options" json,
+  "organization_id" integer NOT NULL,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_ark_backup_policies_org_name ON "ark_backup_policies"(name, organization_id);
@@ -0,0 +1,7 @@
+ALTER TABLE "ark_backups"
+  DROP COLUMN "verification_status",
+  DROP COLUMN "verification_message",
+  DROP COLUMN "verification_problems",
+  DROP COLUMN "verification_target_cluster_id",
+  DROP COLUMN "verification_started_at",
+  DROP COLUMN "verification_finished_at";
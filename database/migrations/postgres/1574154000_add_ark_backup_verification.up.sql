@@ -0,0 +1,7 @@
+ALTER TABLE "ark_backups"
+  ADD COLUMN "verification_status" text,
+  ADD COLUMN "verification_message" text,
+  ADD COLUMN "verification_problems" json,
+  ADD COLUMN "verification_target_cluster_id" integer,
+  ADD COLUMN "verification_started_at" timestamp with time zone,
+  ADD COLUMN "verification_finished_at" timestamp with time zone;
@@ -0,0 +1,184 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/helm/cmd/helm/installer"
+
+	phelm "github.com/banzaicloud/pipeline/pkg/helm"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+// tillerServiceAccount is the name of the service account (and RBAC resources) created for Tiller by PreInstall.
+const tillerServiceAccount = "tiller"
+
+// GetBackend returns the Helm backend of a cluster.
+func GetBackend(kubeConfig []byte) (phelm.Backend, error) {
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	return phelm.GetBackend(client)
+}
+
+// InitEmbeddedTiller sets up a cluster to use the embedded tiller Helm backend.
+func InitEmbeddedTiller(log logrus.FieldLogger, kubeConfig []byte) error {
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	log.Info("setting up embedded tiller helm backend")
+
+	return phelm.SetBackend(client, phelm.EmbeddedTillerBackend)
+}
+
+// MigrateToEmbeddedTiller moves a cluster from the Tiller backend to the embedded tiller one.
+//
+// Tiller is stopped first, so that no release changes while its releases are copied to the embedded tiller storage.
+// Helm operations fail until the migration finishes; a failed migration can be retried.
+// The releases stored by Tiller are kept as a backup.
+func MigrateToEmbeddedTiller(log logrus.FieldLogger, kubeConfig []byte) error {
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	backend, err := phelm.GetBackend(client)
+	if err != nil {
+		return err
+	}
+
+	if backend == phelm.EmbeddedTillerBackend {
+		log.Info("cluster already uses the embedded tiller helm backend")
+
+		return nil
+	}
+
+	log.Info("removing tiller")
+	if err := installer.Uninstall(client, &installer.Options{Namespace: phelm.ReleaseNamespace}); err != nil {
+		return errors.WrapIf(err, "failed to remove tiller")
+	}
+
+	migrated, err := phelm.MigrateTillerReleases(client)
+	if err != nil {
+		return err
+	}
+	log.WithField("revisions", migrated).Info("migrated tiller releases")
+
+	if err := phelm.SetBackend(client, phelm.EmbeddedTillerBackend); err != nil {
+		return err
+	}
+
+	if err := deleteTillerRBAC(client); err != nil {
+		return err
+	}
+
+	log.Info("migrated cluster to the embedded tiller helm backend")
+
+	return nil
+}
+
+// InitHelm3 sets up a cluster to use the Helm 3 backend.
+func InitHelm3(log logrus.FieldLogger, kubeConfig []byte) error {
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	log.Info("setting up helm 3 backend")
+
+	return phelm.SetBackend(client, phelm.Helm3Backend)
+}
+
+// MigrateToHelm3 moves a cluster from the Tiller or the embedded tiller backend to the Helm 3 one.
+//
+// Tiller is stopped first (if the cluster runs it), so that no release changes while its releases are converted.
+// Every revision of the Helm 2 releases is stored as a Helm 3 release secret in the namespace of the release.
+// Helm operations fail until the migration finishes; a failed migration can be retried.
+// The Helm 2 releases are kept as a backup.
+func MigrateToHelm3(log logrus.FieldLogger, kubeConfig []byte) error {
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	backend, err := phelm.GetBackend(client)
+	if err != nil {
+		return err
+	}
+
+	if backend == phelm.Helm3Backend {
+		log.Info("cluster already uses the helm 3 backend")
+
+		return nil
+	}
+
+	if backend == phelm.TillerBackend {
+		log.Info("removing tiller")
+		if err := installer.Uninstall(client, &installer.Options{Namespace: phelm.ReleaseNamespace}); err != nil {
+			return errors.WrapIf(err, "failed to remove tiller")
+		}
+	}
+
+	releases, err := phelm.GetTillerReleases(client, backend)
+	if err != nil {
+		return err
+	}
+
+	migrated, err := phelm.MigrateToHelm3Releases(client, releases)
+	if err != nil {
+		return err
+	}
+	log.WithField("revisions", migrated).Info("migrated helm 2 releases")
+
+	if err := phelm.SetBackend(client, phelm.Helm3Backend); err != nil {
+		return err
+	}
+
+	if err := deleteTillerRBAC(client); err != nil {
+		return err
+	}
+
+	log.Info("migrated cluster to the helm 3 backend")
+
+	return nil
+}
+
+// deleteTillerRBAC removes the privileges granted to Tiller.
+func deleteTillerRBAC(client kubernetes.Interface) error {
+	err := client.RbacV1().ClusterRoleBindings().Delete(tillerServiceAccount, &metav1.DeleteOptions{})
+	if err != nil && !k8sapierrors.IsNotFound(err) {
+		return errors.WrapIf(err, "failed to delete tiller cluster role binding")
+	}
+
+	err = client.RbacV1().ClusterRoles().Delete(tillerServiceAccount, &metav1.DeleteOptions{})
+	if err != nil && !k8sapierrors.IsNotFound(err) {
+		return errors.WrapIf(err, "failed to delete tiller cluster role")
+	}
+
+	err = client.CoreV1().ServiceAccounts(phelm.ReleaseNamespace).Delete(tillerServiceAccount, &metav1.DeleteOptions{})
+	if err != nil && !k8sapierrors.IsNotFound(err) {
+		return errors.WrapIf(err, "failed to delete tiller service account")
+	}
+
+	return nil
+}
@@ -114,15 +114,47 @@ func DownloadFile(url string) ([]byte, error) {
 	}
 	defer resp.Body.Close()
 
-	compressedContent := new(bytes.Buffer)
-
 	if resp.ContentLength > maxCompressedDataSize {
 		return nil, errors.WithStack(&chartDataIsTooBigError{resp.ContentLength})
 	}
 
-	_, copyErr := io.CopyN(compressedContent, resp.Body, maxCompressedDataSize)
+	return readChartArchive(resp.Body)
+}
+
+// downloadRepositoryFile downloads a chart archive using the credentials of the repository and unzips it in memory
+func downloadRepositoryFile(url string, repository *repo.Entry, env helm_env.EnvSettings) ([]byte, error) {
+	if repository.Username == "" && repository.Password == "" && repository.CertFile == "" && repository.CAFile == "" {
+		return DownloadFile(url)
+	}
+
+	r, err := repo.NewChartRepository(repository, getter.All(env))
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot create a new ChartRepo")
+	}
+
+	if g, ok := r.Client.(*getter.HttpGetter); ok {
+		g.SetCredentials(repository.Username, repository.Password)
+	}
+
+	content, err := r.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if content.Len() > maxCompressedDataSize {
+		return nil, errors.WithStack(&chartDataIsTooBigError{int64(content.Len())})
+	}
+
+	return readChartArchive(content)
+}
+
+// readChartArchive unzips a chart archive in memory
+func readChartArchive(reader io.Reader) ([]byte, error) {
+	compressedContent := new(bytes.Buffer)
+
+	_, copyErr := io.CopyN(compressedContent, reader, maxCompressedDataSize)
 	if copyErr != nil && copyErr != io.EOF {
-		return nil, errors.Wrap(err, "failed to read from chart response")
+		return nil, errors.Wrap(copyErr, "failed to read from chart response")
 	}
 
 	gzf, err := gzip.NewReader(compressedContent)
@@ -305,7 +337,12 @@ func ListDeployments(filter *string, tagFilter string, kubeConfig []byte) (*rls.
 }
 
 func DeploymentHasTag(deployment *pkgHelm.GetDeploymentResponse, tagFilter string) bool {
-	if banzaicloudRaw, ok := deployment.Values["banzaicloud"]; ok {
+	return ValuesHaveTag(deployment.Values, tagFilter)
+}
+
+// ValuesHaveTag returns true if release values are tagged with a tag (in the banzaicloud.tags value)
+func ValuesHaveTag(values map[string]interface{}, tagFilter string) bool {
+	if banzaicloudRaw, ok := values["banzaicloud"]; ok {
 		banzaicloudValues, err := cast.ToStringMapE(banzaicloudRaw)
 		if err != nil {
 			return false
@@ -357,7 +394,7 @@ func GetRequestedChart(releaseName, chartName, chartVersion string, chartPackage
 }
 
 // UpgradeDeployment upgrades a Helm deployment
-func UpgradeDeployment(releaseName, chartName, chartVersion string, chartPackage []byte, values []byte, reuseValues bool, kubeConfig []byte, env helm_env.EnvSettings) (*rls.UpdateReleaseResponse, error) {
+func UpgradeDeployment(releaseName, chartName, chartVersion string, chartPackage []byte, values []byte, reuseValues bool, kubeConfig []byte, env helm_env.EnvSettings, overrideOpts ...helm.UpdateOption) (*rls.UpdateReleaseResponse, error) {
 
 	chartRequested, err := GetRequestedChart(releaseName, chartName, chartVersion, chartPackage, env)
 	if err != nil {
@@ -371,13 +408,18 @@ func UpgradeDeployment(releaseName, chartName, chartVersion string, chartPackage
 	}
 	defer hClient.Close()
 
-	upgradeRes, err := hClient.UpdateReleaseFromChart(
-		releaseName,
-		chartRequested,
+	upgradeOptions := []helm.UpdateOption{
 		helm.UpdateValueOverrides(values),
 		helm.UpgradeDryRun(false),
 		// helm.ResetValues(u.resetValues),
 		helm.ReuseValues(reuseValues),
+	}
+	upgradeOptions = append(upgradeOptions, overrideOpts...)
+
+	upgradeRes, err := hClient.UpdateReleaseFromChart(
+		releaseName,
+		chartRequested,
+		upgradeOptions...,
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "upgrade failed")
@@ -527,27 +569,7 @@ func DeleteDeployment(releaseName string, kubeConfig []byte) error {
 	return nil
 }
 
-// GetDeploymentK8sResources returns K8s resources of a helm deployment
-func GetDeploymentK8sResources(releaseName string, kubeConfig []byte, resourceTypes []string) ([]pkgHelm.DeploymentResource, error) {
-	hClient, err := pkgHelm.NewClient(kubeConfig, log)
-	if err != nil {
-		log.Errorf("Getting Helm client failed: %s", err.Error())
-		return nil, err
-	}
-	defer hClient.Close()
-
-	releaseContent, err := hClient.ReleaseContent(releaseName)
-
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return nil, &DeploymentNotFoundError{HelmError: err}
-		}
-		return nil, err
-	}
-
-	return ParseReleaseManifest(releaseContent.Release.Manifest, resourceTypes)
-}
-
+// ParseReleaseManifest returns the resources of a release manifest (all of them if no resource types are given)
 func ParseReleaseManifest(manifest string, resourceTypes []string) ([]pkgHelm.DeploymentResource, error) {
 
 	objects := strings.Split(manifest, "---")
@@ -621,7 +643,8 @@ func GetDeploymentByVersion(releaseName string, kubeConfig []byte, version int32
 		return nil, err
 	}
 
-	values := cfg.AsMap()
+	// the resolved secret values are replaced with the references they were resolved from
+	values := MaskSecretReferences(cfg.AsMap())
 
 	return &pkgHelm.GetDeploymentResponse{
 		ReleaseName:  releaseContent.GetRelease().GetName(),
@@ -769,21 +792,38 @@ func ReposAdd(env helm_env.EnvSettings, Hrepo *repo.Entry) (bool, error) {
 		}
 	}
 
+	// the username and password are only used to access the repository, they are not written to the repositories file
 	c := repo.Entry{
-		Name:  Hrepo.Name,
-		URL:   Hrepo.URL,
-		Cache: env.Home.CacheIndex(Hrepo.Name),
-	}
-	r, err := repo.NewChartRepository(&c, getter.All(env))
-	if err != nil {
-		return false, errors.Wrap(err, "Cannot create a new ChartRepo")
+		Name:     Hrepo.Name,
+		URL:      Hrepo.URL,
+		Cache:    env.Home.CacheIndex(Hrepo.Name),
+		CertFile: Hrepo.CertFile,
+		KeyFile:  Hrepo.KeyFile,
+		CAFile:   Hrepo.CAFile,
+	}
+
+	access := c
+	access.Username = Hrepo.Username
+	access.Password = Hrepo.Password
+
+	// OCI registries have no index, only the access is checked
+	if IsOCIRepository(c.URL) {
+		if err := pingOCIRegistry(&access); err != nil {
+			return false, errors.Wrap(err, "OCI registry is not accessible")
+		}
+	} else {
+		r, err := repo.NewChartRepository(&access, getter.All(env))
+		if err != nil {
+			return false, errors.Wrap(err, "Cannot create a new ChartRepo")
+		}
+
+		errIdx := r.DownloadIndexFile("")
+		if errIdx != nil {
+			return false, errors.Wrap(errIdx, "Repo index download failed")
+		}
 	}
 	log.Debugf("New repo added: %s", Hrepo.Name)
 
-	errIdx := r.DownloadIndexFile("")
-	if errIdx != nil {
-		return false, errors.Wrap(errIdx, "Repo index download failed")
-	}
 	f.Add(&c)
 	if errW := f.WriteFile(repoFile, 0644); errW != nil {
 		return false, errors.Wrap(errW, "Cannot write helm repo profile file")
@@ -814,7 +854,14 @@ func ReposDelete(env helm_env.EnvSettings, repoName string) error {
 			return err
 		}
 	}
-	return nil
+
+	if filepath.Base(repoName) == repoName && repoName != ".." {
+		if err := os.RemoveAll(repositoryCertDir(env, repoName)); err != nil {
+			return err
+		}
+	}
+
+	return SetRepositorySecret(env, repoName, 0, "")
 
 }
 
@@ -858,6 +905,19 @@ func ReposModify(env helm_env.EnvSettings, repoName string, newRepo *repo.Entry)
 			newRepo.Cache = formerRepo.Cache
 			log.Infof("new repo cache field is empty, replaced with: %s", formerRepo.Cache)
 		}
+
+		if newRepo.Username == "" && newRepo.Password == "" && newRepo.CertFile == "" && newRepo.KeyFile == "" && newRepo.CAFile == "" {
+			newRepo.Username = formerRepo.Username
+			newRepo.Password = formerRepo.Password
+			newRepo.CertFile = formerRepo.CertFile
+			newRepo.KeyFile = formerRepo.KeyFile
+			newRepo.CAFile = formerRepo.CAFile
+			log.Info("new repo has no credentials, former credentials are kept")
+		} else {
+			// new credentials are read from the secret of the repository when it's accessed
+			newRepo.Username = ""
+			newRepo.Password = ""
+		}
 	}
 
 	f.Update(newRepo)
@@ -882,6 +942,15 @@ func ReposUpdate(env helm_env.EnvSettings, repoName string) error {
 
 	for _, cfg := range f.Repositories {
 		if cfg.Name == repoName {
+			cfg, err := withRepositoryCredentials(env, cfg)
+			if err != nil {
+				return errors.WithMessage(err, "failed to get repository credentials")
+			}
+
+			if IsOCIRepository(cfg.URL) {
+				return errors.Wrap(pingOCIRegistry(cfg), "OCI registry is not accessible")
+			}
+
 			c, err := repo.NewChartRepository(cfg, getter.All(env))
 			if err != nil {
 				return errors.Wrap(err, "Cannot get ChartRepo")
@@ -919,6 +988,11 @@ func ChartsGet(env helm_env.EnvSettings, queryName, queryRepo, queryVersion, que
 	for _, r := range f.Repositories {
 
 		log.Debugf("Repository: %s", r.Name)
+		if IsOCIRepository(r.URL) {
+			// OCI registries have no index to list charts from
+			continue
+		}
+
 		i, errIndx := repo.LoadIndexFile(r.Cache)
 		if errIndx != nil {
 			return nil, errIndx
@@ -987,6 +1061,9 @@ func ChartGet(env helm_env.EnvSettings, chartRepo, chartName, chartVersion strin
 	for _, repository := range f.Repositories {
 
 		log.Debugf("Repository: %s", repository.Name)
+		if IsOCIRepository(repository.URL) {
+			continue
+		}
 
 		var i *repo.IndexFile
 		i, err = repo.LoadIndexFile(repository.Cache)
@@ -1009,7 +1086,7 @@ func ChartGet(env helm_env.EnvSettings, chartRepo, chartName, chartVersion strin
 						if v.Version == chartVersion || chartVersion == "" {
 
 							var ver *ChartVersion
-							ver, err = getChartVersion(v, repository.URL)
+							ver, err = getChartVersion(v, repository, env)
 							if err != nil {
 								return
 							}
@@ -1018,7 +1095,7 @@ func ChartGet(env helm_env.EnvSettings, chartRepo, chartName, chartVersion strin
 							return
 						} else if chartVersion == versionAll {
 							var ver *ChartVersion
-							ver, err = getChartVersion(v, repository.URL)
+							ver, err = getChartVersion(v, repository, env)
 							if err != nil {
 								log.Warnf("error during getting chart[%s - %s]: %s", v.Name, v.Version, err.Error())
 							} else {
@@ -1038,17 +1115,22 @@ func ChartGet(env helm_env.EnvSettings, chartRepo, chartName, chartVersion strin
 	return
 }
 
-func getChartVersion(v *repo.ChartVersion, repoUrl string) (*ChartVersion, error) {
+func getChartVersion(v *repo.ChartVersion, repository *repo.Entry, env helm_env.EnvSettings) (*ChartVersion, error) {
 	log.Infof("get chart[%s - %s]", v.Name, v.Version)
 
 	chartSource := v.URLs[0]
 	if !strings.HasPrefix(chartSource, "http") {
 		// append with repo url to avoid unsupported protocol scheme errors
-		chartSource = fmt.Sprintf("%s/%s", repoUrl, chartSource)
+		chartSource = fmt.Sprintf("%s/%s", repository.URL, chartSource)
 	}
 
 	log.Debugf("chartSource: %s", chartSource)
-	reader, err := DownloadFile(chartSource)
+	repository, err := withRepositoryCredentials(env, repository)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get repository credentials")
+	}
+
+	reader, err := downloadRepositoryFile(chartSource, repository, env)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,362 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+	"k8s.io/helm/pkg/chartutil"
+	helm_env "k8s.io/helm/pkg/helm/environment"
+
+	"github.com/banzaicloud/pipeline/config"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	"github.com/banzaicloud/pipeline/pkg/k8sutil"
+)
+
+// The helm3 backend manages releases with the Helm 3 client, run as a separate process.
+//
+// The Helm 3 library can't be linked into Pipeline yet: it requires the Kubernetes 1.16 client libraries,
+// while Pipeline is pinned to the 1.13 ones by the kubefed dependency. Running the client binary keeps
+// the two dependency trees apart until that upgrade, and it's acceptable for the following reasons:
+//   - the binary is shipped in the Pipeline images at a pinned version (helm.helm3Binary)
+//   - every run gets the kubeconfig of the cluster and empty cache, config and data directories,
+//     so runs don't share state with each other or with the user running Pipeline
+//   - charts are resolved by Pipeline the same way as for the other backends and passed to the client as archives,
+//     so the client never reaches the chart repositories
+//   - releases are read directly from the Helm 3 release storage (see pkg/helm.GetHelm3Release),
+//     the client is only run for the operations changing releases
+
+// helm3Timeout is the time the Helm 3 client waits for Kubernetes operations (same as DefaultInstallOptions).
+const helm3Timeout = "5m0s"
+
+// Helm3Options are the options of installing or upgrading a release with the Helm 3 client.
+type Helm3Options struct {
+	// Wait waits until the resources of the release are ready
+	Wait bool
+
+	// DryRun renders the release without applying it
+	DryRun bool
+
+	// Timeout is the time to wait for Kubernetes operations in seconds (helm3Timeout if not set)
+	Timeout int64
+
+	// ReuseValues merges the values with the values of the latest revision (upgrade only)
+	ReuseValues bool
+
+	// OnDemandPercentages are the on-demand percentages of the resources of the release on spot clusters (install only)
+	OnDemandPercentages map[string]int
+}
+
+func (o Helm3Options) timeout() string {
+	if o.Timeout > 0 {
+		return fmt.Sprintf("%ds", o.Timeout)
+	}
+
+	return helm3Timeout
+}
+
+// Helm3InstallDeployment installs a release with the Helm 3 client.
+// The namespace of the release is created if it does not exist (as Tiller would do).
+func Helm3InstallDeployment(
+	chartName string,
+	chartVersion string,
+	chartPackage []byte,
+	namespace string,
+	releaseName string,
+	values []byte,
+	options Helm3Options,
+	kubeConfig []byte,
+	env helm_env.EnvSettings,
+) (*pkgHelm.Helm3Release, error) {
+	if namespace == "" {
+		log.Warn("Deployment namespace was not set failing back to default")
+		namespace = DefaultNamespace
+	}
+
+	// unlike Tiller, the Helm 3 client does not generate missing release names
+	if len(strings.TrimSpace(releaseName)) == 0 {
+		releaseName = pkgHelm.GenerateReleaseName()
+	}
+
+	if !options.DryRun {
+		client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to create kubernetes client")
+		}
+
+		if err := k8sutil.EnsureNamespace(client, namespace); err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to create release namespace", "namespace", namespace)
+		}
+	}
+
+	var cmUpdated bool
+	if !options.DryRun && options.OnDemandPercentages != nil {
+		if err := updateSpotConfigMap(kubeConfig, options.OnDemandPercentages, releaseName); err != nil {
+			return nil, errors.WrapIf(err, "failed to update spot ConfigMap")
+		}
+		cmUpdated = true
+	}
+
+	args := []string{"install", releaseName, "--namespace", namespace, "--replace", "--timeout", options.timeout()}
+	if options.Wait {
+		args = append(args, "--wait")
+	}
+
+	rls, err := runHelm3Release(releaseName, chartName, chartVersion, chartPackage, values, options.DryRun, kubeConfig, env, args)
+	if err != nil && cmUpdated {
+		if err := cleanupSpotConfigMap(kubeConfig, options.OnDemandPercentages, releaseName); err != nil {
+			log.Warn("failed to clean up spot config map")
+		}
+	}
+
+	return rls, err
+}
+
+// Helm3UpgradeDeployment upgrades a release with the Helm 3 client.
+func Helm3UpgradeDeployment(
+	releaseName string,
+	chartName string,
+	chartVersion string,
+	chartPackage []byte,
+	values []byte,
+	options Helm3Options,
+	kubeConfig []byte,
+	env helm_env.EnvSettings,
+) (*pkgHelm.Helm3Release, error) {
+	rls, err := Helm3GetDeployment(releaseName, kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if rls == nil {
+		return nil, &DeploymentNotFoundError{HelmError: errors.Errorf("release %s not found", releaseName)}
+	}
+
+	args := []string{"upgrade", releaseName, "--namespace", rls.Namespace, "--timeout", options.timeout()}
+	if options.Wait {
+		args = append(args, "--wait")
+	}
+	if options.ReuseValues {
+		args = append(args, "--reuse-values")
+	}
+
+	return runHelm3Release(releaseName, chartName, chartVersion, chartPackage, values, options.DryRun, kubeConfig, env, args)
+}
+
+// Helm3RollbackDeployment rolls back a release to a previous revision with the Helm 3 client.
+func Helm3RollbackDeployment(releaseName string, version int, kubeConfig []byte) (*pkgHelm.Helm3Release, error) {
+	if version < 1 {
+		return nil, errors.Errorf("invalid deployment version: %d", version)
+	}
+
+	rls, err := Helm3GetDeployment(releaseName, kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	if rls == nil {
+		return nil, &DeploymentNotFoundError{HelmError: errors.Errorf("release %s not found", releaseName)}
+	}
+
+	_, err = runHelm3(kubeConfig, "rollback", releaseName, strconv.Itoa(version), "--namespace", rls.Namespace, "--timeout", helm3Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// the rollback command doesn't print the new revision
+	return Helm3GetDeployment(releaseName, kubeConfig)
+}
+
+// Helm3DeleteDeployment uninstalls a release with the Helm 3 client.
+// Deleting a missing release is not an error.
+func Helm3DeleteDeployment(releaseName string, kubeConfig []byte) error {
+	rls, err := Helm3GetDeployment(releaseName, kubeConfig)
+	if err != nil {
+		return err
+	}
+	if rls == nil {
+		return nil
+	}
+
+	_, err = runHelm3(kubeConfig, "uninstall", releaseName, "--namespace", rls.Namespace, "--timeout", helm3Timeout)
+
+	return err
+}
+
+// Helm3GetDeployment returns the latest revision of a release managed by the Helm 3 client.
+// It returns nil if the release is not found.
+func Helm3GetDeployment(releaseName string, kubeConfig []byte) (*pkgHelm.Helm3Release, error) {
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	return pkgHelm.GetHelm3Release(client, releaseName)
+}
+
+// Helm3GetDeploymentRevision returns a revision of a release managed by the Helm 3 client.
+// It returns nil if the revision is not found.
+func Helm3GetDeploymentRevision(releaseName string, version int, kubeConfig []byte) (*pkgHelm.Helm3Release, error) {
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	return pkgHelm.GetHelm3ReleaseRevision(client, releaseName, version)
+}
+
+// Helm3GetDeploymentHistory returns the revisions of a release managed by the Helm 3 client (latest first).
+func Helm3GetDeploymentHistory(releaseName string, kubeConfig []byte) ([]*pkgHelm.Helm3Release, error) {
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	return pkgHelm.GetHelm3ReleaseHistory(client, releaseName)
+}
+
+// Helm3ListDeployments returns the latest revision of the releases managed by the Helm 3 client,
+// the last deployed first. Uninstalled and superseded releases are left out, like in ListDeployments.
+func Helm3ListDeployments(kubeConfig []byte) ([]*pkgHelm.Helm3Release, error) {
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	releases, err := pkgHelm.ListHelm3Releases(client)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make([]*pkgHelm.Helm3Release, 0, len(releases))
+	for _, rls := range releases {
+		if rls.Info == nil {
+			continue
+		}
+
+		switch rls.Info.Status {
+		case "deployed", "failed", "uninstalling", "pending-install", "pending-upgrade", "pending-rollback":
+			listed = append(listed, rls)
+		}
+	}
+
+	sort.SliceStable(listed, func(i, j int) bool {
+		return listed[i].Info.LastDeployed.After(listed[j].Info.LastDeployed)
+	})
+
+	return listed, nil
+}
+
+// runHelm3Release installs or upgrades a release, and returns the release printed by the Helm 3 client.
+func runHelm3Release(
+	releaseName string,
+	chartName string,
+	chartVersion string,
+	chartPackage []byte,
+	values []byte,
+	dryRun bool,
+	kubeConfig []byte,
+	env helm_env.EnvSettings,
+	args []string,
+) (*pkgHelm.Helm3Release, error) {
+	requestedChart, err := GetRequestedChart(releaseName, chartName, chartVersion, chartPackage, env)
+	if err != nil {
+		return nil, errors.WrapIf(err, "error loading chart")
+	}
+
+	dir, err := ioutil.TempDir("", "helm3-")
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+
+	chartArchive, err := chartutil.Save(requestedChart, dir)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to save chart archive")
+	}
+
+	valuesFile := filepath.Join(dir, "values.yaml")
+	if err := ioutil.WriteFile(valuesFile, values, 0600); err != nil {
+		return nil, errors.WrapIf(err, "failed to write values")
+	}
+
+	args = append(args, chartArchive, "--values", valuesFile, "--output", "json")
+	if dryRun {
+		args = append(args, "--dry-run")
+	}
+
+	output, err := runHelm3(kubeConfig, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var rls pkgHelm.Helm3Release
+	if err := json.Unmarshal(output, &rls); err != nil {
+		return nil, errors.WrapIf(err, "failed to parse helm 3 release")
+	}
+
+	return &rls, nil
+}
+
+// runHelm3 runs the Helm 3 client on a cluster and returns its standard output.
+func runHelm3(kubeConfig []byte, args ...string) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "helm3-")
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create temporary directory")
+	}
+	defer os.RemoveAll(dir)
+
+	kubeConfigFile := filepath.Join(dir, "kubeconfig")
+	if err := ioutil.WriteFile(kubeConfigFile, kubeConfig, 0600); err != nil {
+		return nil, errors.WrapIf(err, "failed to write kubeconfig")
+	}
+
+	binary := viper.GetString(config.HelmHelm3BinaryKey)
+
+	cmd := exec.Command(binary, append(args, "--kubeconfig", kubeConfigFile)...) // nolint: gosec
+
+	// the client must not use the repositories, plugins or caches of the user running Pipeline
+	cmd.Env = append(
+		os.Environ(),
+		"XDG_CACHE_HOME="+filepath.Join(dir, "cache"),
+		"XDG_CONFIG_HOME="+filepath.Join(dir, "config"),
+		"XDG_DATA_HOME="+filepath.Join(dir, "data"),
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		message := strings.TrimPrefix(strings.TrimSpace(stderr.String()), "Error: ")
+		if message == "" {
+			message = err.Error()
+		}
+
+		return nil, errors.WithDetails(errors.Errorf("helm 3 %s failed: %s", args[0], message), "binary", binary)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,68 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/config"
+)
+
+// setupFakeHelm3 replaces the Helm 3 client with a shell script, and returns a function restoring it.
+func setupFakeHelm3(t *testing.T, script string) func() {
+	dir, err := ioutil.TempDir("", "helm3-test-")
+	require.NoError(t, err)
+
+	binary := filepath.Join(dir, "helm3")
+	require.NoError(t, ioutil.WriteFile(binary, []byte("#!/bin/sh\n"+script), 0700))
+
+	previous := viper.GetString(config.HelmHelm3BinaryKey)
+	viper.Set(config.HelmHelm3BinaryKey, binary)
+
+	return func() {
+		viper.Set(config.HelmHelm3BinaryKey, previous)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestRunHelm3(t *testing.T) {
+	defer setupFakeHelm3(t, `echo "$@"; cat "$4"; echo; echo "$XDG_CONFIG_HOME"`)()
+
+	output, err := runHelm3([]byte("my-kubeconfig"), "uninstall", "my-release")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "uninstall my-release --kubeconfig "))
+	assert.Equal(t, "my-kubeconfig", lines[1])
+	assert.NotEqual(t, os.Getenv("XDG_CONFIG_HOME"), lines[2])
+}
+
+func TestRunHelm3_Error(t *testing.T) {
+	defer setupFakeHelm3(t, `echo "Error: release not found" >&2; exit 1`)()
+
+	_, err := runHelm3([]byte("my-kubeconfig"), "uninstall", "my-release")
+	require.Error(t, err)
+
+	assert.Equal(t, "helm 3 uninstall failed: release not found", err.Error())
+}
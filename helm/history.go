@@ -0,0 +1,189 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+// maxHistory is the maximum number of revisions returned for a deployment.
+const maxHistory = 256
+
+// GetReleaseHistory returns the revisions of a release managed by Tiller (latest first)
+func GetReleaseHistory(releaseName string, kubeConfig []byte) ([]*release.Release, error) {
+	helmClient, err := pkgHelm.NewClient(kubeConfig, log)
+	if err != nil {
+		log.Errorf("Getting Helm client failed: %s", err.Error())
+		return nil, err
+	}
+	defer helmClient.Close()
+
+	history, err := helmClient.ReleaseHistory(releaseName, helm.WithMaxHistory(maxHistory))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &DeploymentNotFoundError{HelmError: err}
+		}
+		return nil, err
+	}
+
+	releases := history.GetReleases()
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].GetVersion() > releases[j].GetVersion()
+	})
+
+	return releases, nil
+}
+
+// GetReleaseRevision returns a revision of a release managed by Tiller
+func GetReleaseRevision(releaseName string, kubeConfig []byte, version int32) (*release.Release, error) {
+	helmClient, err := pkgHelm.NewClient(kubeConfig, log)
+	if err != nil {
+		log.Errorf("Getting Helm client failed: %s", err.Error())
+		return nil, err
+	}
+	defer helmClient.Close()
+
+	releaseContent, err := helmClient.ReleaseContent(releaseName, helm.ContentReleaseVersion(version))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &DeploymentNotFoundError{HelmError: err}
+		}
+		return nil, err
+	}
+
+	return releaseContent.GetRelease(), nil
+}
+
+// DeploymentRevisionContent is the content of a deployment revision compared by DiffDeploymentRevisions.
+type DeploymentRevisionContent struct {
+	Version  int32
+	Manifest string
+
+	// Values are the values supplied for the revision
+	Values map[string]interface{}
+}
+
+// DiffDeploymentRevisions returns the manifest and values differences between two revisions of a helm deployment
+// The values diff shows secret references instead of the resolved secret values,
+// the manifests are diffed as rendered by the chart with the data of Secret objects
+// and the resolved secret values redacted.
+func DiffDeploymentRevisions(releaseName string, from, to DeploymentRevisionContent) (*pkgHelm.GetDeploymentDiffResponse, error) {
+	fromName := fmt.Sprintf("%s (version %d)", releaseName, from.Version)
+	toName := fmt.Sprintf("%s (version %d)", releaseName, to.Version)
+
+	sensitiveValues := append(ResolvedSecretValues(from.Values), ResolvedSecretValues(to.Values)...)
+
+	fromManifest, toManifest, err := redactManifests(from.Manifest, to.Manifest, sensitiveValues)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestDiff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromManifest),
+		B:        difflib.SplitLines(toManifest),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to diff release manifests")
+	}
+
+	fromValues, err := normalizeValues(from.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	toValues, err := normalizeValues(to.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	valuesDiff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromValues),
+		B:        difflib.SplitLines(toValues),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to diff release values")
+	}
+
+	return &pkgHelm.GetDeploymentDiffResponse{
+		ReleaseName: releaseName,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Manifest:    manifestDiff,
+		Values:      valuesDiff,
+	}, nil
+}
+
+// normalizeValues re-encodes values so that formatting and key order differences don't show up in diffs.
+// Resolved secret values are replaced with the references they were resolved from.
+func normalizeValues(values map[string]interface{}) (string, error) {
+	values = MaskSecretReferences(values)
+
+	if len(values) == 0 {
+		return "", nil
+	}
+
+	normalized, err := yaml.Marshal(values)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode release values")
+	}
+
+	return string(normalized), nil
+}
+
+// RollbackDeployment rolls back a helm deployment to a previous revision
+func RollbackDeployment(releaseName string, kubeConfig []byte, version int32) (*release.Release, error) {
+	if version < 1 {
+		return nil, errors.Errorf("invalid deployment version: %d", version)
+	}
+
+	helmClient, err := pkgHelm.NewClient(kubeConfig, log)
+	if err != nil {
+		log.Errorf("Getting Helm client failed: %s", err.Error())
+		return nil, err
+	}
+	defer helmClient.Close()
+
+	rollbackRes, err := helmClient.RollbackRelease(
+		releaseName,
+		helm.RollbackVersion(version),
+		helm.RollbackDescription(fmt.Sprintf("Rollback to %d", version)),
+		helm.RollbackTimeout(300),
+		helm.RollbackWait(false),
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			return nil, &DeploymentNotFoundError{HelmError: err}
+		}
+		return nil, errors.Wrap(err, "rollback failed")
+	}
+
+	return rollbackRes.GetRelease(), nil
+}
@@ -0,0 +1,147 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseTestValues(t *testing.T, raw string) map[string]interface{} {
+	var values map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(raw), &values))
+
+	return values
+}
+
+func TestDiffDeploymentRevisions(t *testing.T) {
+	from := DeploymentRevisionContent{
+		Version:  1,
+		Manifest: "kind: Deployment\nspec:\n  replicas: 1\n",
+		Values:   parseTestValues(t, "replicaCount: 1\nimage:\n  tag: \"1.0\"\n"),
+	}
+
+	to := DeploymentRevisionContent{
+		Version:  2,
+		Manifest: "kind: Deployment\nspec:\n  replicas: 2\n",
+		Values:   parseTestValues(t, "image: {tag: \"1.0\"}\nreplicaCount: 2\n"),
+	}
+
+	diff, err := DiffDeploymentRevisions("my-release", from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-release", diff.ReleaseName)
+	assert.Equal(t, int32(1), diff.FromVersion)
+	assert.Equal(t, int32(2), diff.ToVersion)
+
+	assert.Equal(
+		t,
+		"--- my-release (version 1)\n+++ my-release (version 2)\n@@ -1,4 +1,4 @@\n kind: Deployment\n spec:\n-  replicas: 1\n+  replicas: 2\n \n",
+		diff.Manifest,
+	)
+
+	// Only the changed value shows up, formatting and key order differences are ignored
+	assert.Equal(
+		t,
+		"--- my-release (version 1)\n+++ my-release (version 2)\n@@ -1,4 +1,4 @@\n image:\n   tag: \"1.0\"\n-replicaCount: 1\n+replicaCount: 2\n \n",
+		diff.Values,
+	)
+}
+
+func TestDiffDeploymentRevisions_SecretReferences(t *testing.T) {
+	from := DeploymentRevisionContent{
+		Version: 1,
+		Values:  parseTestValues(t, "password: s3cr3t\npipelineSecretReferences:\n  password: '{{ secret \"brn:1:secret:db\" \"password\" }}'\n"),
+	}
+
+	to := DeploymentRevisionContent{
+		Version: 2,
+		Values:  parseTestValues(t, "password: n3ws3cr3t\npipelineSecretReferences:\n  password: '{{ secret \"brn:1:secret:newdb\" \"password\" }}'\n"),
+	}
+
+	diff, err := DiffDeploymentRevisions("my-release", from, to)
+	require.NoError(t, err)
+
+	assert.NotContains(t, diff.Values, "s3cr3t\n")
+	assert.NotContains(t, diff.Values, "pipelineSecretReferences")
+	assert.Equal(
+		t,
+		"--- my-release (version 1)\n+++ my-release (version 2)\n@@ -1,2 +1,2 @@\n-password: '{{ secret \"brn:1:secret:db\" \"password\" }}'\n+password: '{{ secret \"brn:1:secret:newdb\" \"password\" }}'\n \n",
+		diff.Values,
+	)
+}
+
+func TestDiffDeploymentRevisions_Secrets(t *testing.T) {
+	const manifest = `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: db
+data:
+  password: %s
+  username: YWRtaW4=
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: db
+data:
+  url: postgres://admin:%s@db:5432
+`
+
+	from := DeploymentRevisionContent{
+		Version:  1,
+		Manifest: fmt.Sprintf(manifest, "czNjcjN0", "s3cr3t"),
+		Values:   parseTestValues(t, "password: s3cr3t\npipelineSecretReferences:\n  password: '{{ secret \"brn:1:secret:db\" \"password\" }}'\n"),
+	}
+
+	to := DeploymentRevisionContent{
+		Version:  2,
+		Manifest: fmt.Sprintf(manifest, "bjN3czNjcjN0", "n3ws3cr3t"),
+		Values:   parseTestValues(t, "password: n3ws3cr3t\npipelineSecretReferences:\n  password: '{{ secret \"brn:1:secret:newdb\" \"password\" }}'\n"),
+	}
+
+	diff, err := DiffDeploymentRevisions("my-release", from, to)
+	require.NoError(t, err)
+
+	for _, value := range []string{"czNjcjN0", "bjN3czNjcjN0", "YWRtaW4=", "s3cr3t", "n3ws3cr3t"} {
+		assert.NotContains(t, diff.Manifest, value)
+	}
+
+	// The changed keys of secrets still show up
+	assert.Contains(t, diff.Manifest, "-  password: <redacted>\n+  password: <redacted, changed>\n")
+	assert.Contains(t, diff.Manifest, "   username: <redacted>\n")
+
+	// The resolved secret values are masked in the other objects too, so the config map is unchanged
+	assert.NotContains(t, diff.Manifest, "url:")
+}
+
+func TestDiffDeploymentRevisions_Unchanged(t *testing.T) {
+	rel := DeploymentRevisionContent{
+		Version:  1,
+		Manifest: "kind: Deployment\n",
+	}
+
+	diff, err := DiffDeploymentRevisions("my-release", rel, rel)
+	require.NoError(t, err)
+
+	assert.Empty(t, diff.Manifest)
+	assert.Empty(t, diff.Values)
+}
@@ -29,6 +29,7 @@ import (
 	v1 "k8s.io/api/rbac/v1"
 	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/helm/cmd/helm/installer"
 	"k8s.io/helm/pkg/downloader"
 	"k8s.io/helm/pkg/getter"
@@ -43,6 +44,24 @@ import (
 	"github.com/banzaicloud/pipeline/pkg/k8sutil"
 )
 
+// tillerUsesSecretStorage tells whether the installed Tiller stores releases in Secrets.
+func tillerUsesSecretStorage(client kubernetes.Interface, namespace string) (bool, error) {
+	deployment, err := client.ExtensionsV1beta1().Deployments(namespace).Get("tiller-deploy", metav1.GetOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		for _, arg := range append(container.Command, container.Args...) {
+			if arg == tillerSecretStorageFlag {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
 // PreInstall create's serviceAccount and AccountRoleBinding
 func PreInstall(log logrus.FieldLogger, helmInstall *phelm.Install, kubeConfig []byte) error {
 	log.Info("start pre-install")
@@ -213,6 +232,30 @@ func DownloadChartFromRepo(name, version string, env helmEnv.EnvSettings) (strin
 		os.MkdirAll(env.Home.Archive(), 0744) // nolint: errcheck
 	}
 
+	ociRef, ociRepo, err := findOCIChart(name, env)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to look up chart repository")
+	}
+
+	if ociRef != "" {
+		ociRepo, err = withRepositoryCredentials(env, ociRepo)
+		if err != nil {
+			return "", errors.WithMessage(err, "failed to get repository credentials")
+		}
+
+		log.Infof("Pulling helm chart %q, version %q to %q", ociRef, version, env.Home.Archive())
+		filename, err := downloadOCIChart(ociRef, version, ociRepo, env.Home.Archive())
+		if err != nil {
+			return "", errors.Wrapf(err, "Failed to pull chart %q, version %q", name, version)
+		}
+		log.Debugf("Pulled helm chart %q, version %q to %q", ociRef, version, filename)
+		return filename, nil
+	}
+
+	if err := setDownloaderCredentials(&dl, name, env); err != nil {
+		return "", err
+	}
+
 	log.Infof("Downloading helm chart %q, version %q to %q", name, version, env.Home.Archive())
 	filename, _, err := dl.DownloadTo(name, version, env.Home.Archive())
 	if err == nil {
@@ -227,6 +270,37 @@ func DownloadChartFromRepo(name, version string, env helmEnv.EnvSettings) (strin
 	return filename, errors.Wrapf(err, "Failed to download chart %q, version %q", name, version)
 }
 
+// setDownloaderCredentials sets the credentials of the repository of a chart (eg. myrepo/mychart) on a chart downloader.
+func setDownloaderCredentials(dl *downloader.ChartDownloader, name string, env helmEnv.EnvSettings) error {
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) != 2 {
+		return nil
+	}
+
+	f, err := repo.LoadRepositoriesFile(env.Home.RepositoryFile())
+	if err != nil {
+		return nil
+	}
+
+	for _, entry := range f.Repositories {
+		if entry.Name != parts[0] {
+			continue
+		}
+
+		entry, err := withRepositoryCredentials(env, entry)
+		if err != nil {
+			return errors.WithMessage(err, "failed to get repository credentials")
+		}
+
+		dl.Username = entry.Username
+		dl.Password = entry.Password
+
+		return nil
+	}
+
+	return nil
+}
+
 // InstallHelmClient Installs helm client on a given path
 func InstallHelmClient(env helmEnv.EnvSettings) error {
 	if err := EnsureDirectories(env); err != nil {
@@ -315,7 +389,19 @@ func InstallLocalHelm(env helmEnv.EnvSettings) error {
 	return nil
 }
 
+// tillerSecretStorageFlag makes Tiller store releases in Secrets instead of ConfigMaps.
+// Release values can hold resolved secret references (see ResolveSecretReferences),
+// which should not be readable by everyone who can read ConfigMaps in the Tiller namespace.
+const tillerSecretStorageFlag = "--storage=secret"
+
+// tillerSecretStorageOverride sets the storage of Tiller in its deployment the same way "helm init --override" does.
+const tillerSecretStorageOverride = "spec.template.spec.containers[0].command={/tiller," + tillerSecretStorageFlag + "}"
+
 // Install uses Kubernetes client to install Tiller.
+//
+// New Tiller installations store releases in Secrets.
+// Tiller installations storing releases in ConfigMaps keep doing so when they are upgraded,
+// otherwise they would lose track of their existing releases.
 func Install(log logrus.FieldLogger, helmInstall *phelm.Install, kubeConfig []byte) error {
 
 	err := PreInstall(log, helmInstall, kubeConfig)
@@ -377,7 +463,10 @@ func Install(log logrus.FieldLogger, helmInstall *phelm.Install, kubeConfig []by
 	if err != nil {
 		return err
 	}
-	if err := installer.Install(kubeClient, &opts); err != nil {
+	secretStorageOpts := opts
+	secretStorageOpts.Values = append([]string{tillerSecretStorageOverride}, opts.Values...)
+
+	if err := installer.Install(kubeClient, &secretStorageOpts); err != nil {
 		if !k8sapierrors.IsAlreadyExists(err) {
 			// TODO shouldn'T we just skipp?
 			return err
@@ -385,7 +474,18 @@ func Install(log logrus.FieldLogger, helmInstall *phelm.Install, kubeConfig []by
 		log.Info("Tiller already installed")
 		if helmInstall.Upgrade {
 			log.Info("upgrading Tiller")
-			if err := installer.Upgrade(kubeClient, &opts); err != nil {
+
+			secretStorage, err := tillerUsesSecretStorage(kubeClient, helmInstall.Namespace)
+			if err != nil {
+				return errors.Wrap(err, "failed to check the release storage of Tiller")
+			}
+			upgradeOpts := secretStorageOpts
+			if !secretStorage {
+				log.Warn("Tiller stores releases in ConfigMaps, keeping the storage to preserve existing releases")
+				upgradeOpts = opts
+			}
+
+			if err := installer.Upgrade(kubeClient, &upgradeOpts); err != nil {
 				return errors.Wrap(err, "error when upgrading")
 			}
 			log.Info("Tiller (the Helm server-side component) has been upgraded to the current version.")
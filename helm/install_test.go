@@ -0,0 +1,51 @@
+// Copyright © 2018 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/helm/cmd/helm/installer"
+)
+
+func TestTillerSecretStorage(t *testing.T) {
+	opts := installer.Options{
+		Namespace: "kube-system",
+		Values:    []string{tillerSecretStorageOverride},
+	}
+
+	deployment, err := installer.Deployment(&opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"/tiller", tillerSecretStorageFlag}, deployment.Spec.Template.Spec.Containers[0].Command)
+
+	client := fake.NewSimpleClientset()
+	require.NoError(t, installer.Install(client, &opts))
+
+	secretStorage, err := tillerUsesSecretStorage(client, "kube-system")
+	require.NoError(t, err)
+	assert.True(t, secretStorage)
+
+	// Tiller installed with the default ConfigMap storage
+	client = fake.NewSimpleClientset()
+	require.NoError(t, installer.Install(client, &installer.Options{Namespace: "kube-system"}))
+
+	secretStorage, err = tillerUsesSecretStorage(client, "kube-system")
+	require.NoError(t, err)
+	assert.False(t, secretStorage)
+}
@@ -0,0 +1,248 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/helm/pkg/releaseutil"
+)
+
+const (
+	// redactedValue replaces secret values in manifest diffs.
+	redactedValue = "<redacted>"
+
+	// redactedChangedValue replaces the secret values changed compared to the other manifest of a diff,
+	// so that the diff still shows which keys of a secret are changed.
+	redactedChangedValue = "<redacted, changed>"
+)
+
+// ResolvedSecretValues returns the resolved secret values of release values stored with their references
+// (see StoreSecretReferences), so that they can be redacted from the manifests of the release.
+func ResolvedSecretValues(values map[string]interface{}) []string {
+	references, _ := values[secretReferencesKey].(map[string]interface{})
+	if len(references) == 0 {
+		return nil
+	}
+
+	var resolved []string
+	_, _ = walkStringValues(values, "", func(path string, value string) (string, error) {
+		if reference, ok := references[path].(string); ok && value != "" && value != reference {
+			resolved = append(resolved, value)
+		}
+
+		return value, nil
+	})
+
+	return resolved
+}
+
+// redactManifests masks the data of Secret objects and the given sensitive values
+// (eg. resolved secret references) in two versions of a release manifest.
+// Only the redacted objects are encoded again, the rest of the manifests is left as rendered.
+func redactManifests(from string, to string, sensitiveValues []string) (string, string, error) {
+	fromObjects, err := splitManifest(from)
+	if err != nil {
+		return "", "", err
+	}
+
+	toObjects, err := splitManifest(to)
+	if err != nil {
+		return "", "", err
+	}
+
+	// longer values are masked first, so that values containing others are masked entirely
+	sensitiveValues = append([]string(nil), sensitiveValues...)
+	sort.Slice(sensitiveValues, func(i, j int) bool {
+		return len(sensitiveValues[i]) > len(sensitiveValues[j])
+	})
+
+	previous := make(map[string]map[string]interface{}, len(fromObjects))
+	for _, object := range fromObjects {
+		if object.object != nil {
+			previous[object.key()] = object.object
+		}
+	}
+
+	// the objects are redacted in place, so the new versions are compared with the previous ones first
+	toRedacted, err := redactManifestDocuments(toObjects, previous, sensitiveValues)
+	if err != nil {
+		return "", "", err
+	}
+
+	fromRedacted, err := redactManifestDocuments(fromObjects, nil, sensitiveValues)
+	if err != nil {
+		return "", "", err
+	}
+
+	if fromRedacted {
+		from = joinManifest(fromObjects)
+	}
+
+	if toRedacted {
+		to = joinManifest(toObjects)
+	}
+
+	return from, to, nil
+}
+
+// redactManifestDocuments redacts the objects of a manifest and returns whether any of them is redacted.
+func redactManifestDocuments(documents []*manifestDocument, previous map[string]map[string]interface{}, sensitiveValues []string) (bool, error) {
+	redacted := false
+
+	for _, document := range documents {
+		if document.object == nil {
+			continue
+		}
+
+		ok, err := document.redact(previous[document.key()], sensitiveValues)
+		if err != nil {
+			return false, err
+		}
+
+		redacted = redacted || ok
+	}
+
+	return redacted, nil
+}
+
+// manifestDocument is a document of a release manifest.
+type manifestDocument struct {
+	content string
+
+	// object is the decoded content, nil for documents without objects (eg. templates rendered to comments only)
+	object map[string]interface{}
+}
+
+// splitManifest splits a release manifest into its documents in the order of the manifest.
+// The manifest is split on YAML document separators the same way Helm does,
+// so that separator-like content in values (eg. PEM encoded certificates) doesn't break objects apart.
+func splitManifest(manifest string) ([]*manifestDocument, error) {
+	split := releaseutil.SplitManifests(manifest)
+
+	// the documents are keyed by their index in the manifest
+	documents := make([]*manifestDocument, len(split))
+	for key, content := range split {
+		index, err := strconv.Atoi(strings.TrimPrefix(key, "manifest-"))
+		if err != nil || index < 0 || index >= len(documents) {
+			return nil, errors.Errorf("unexpected manifest document key: %s", key)
+		}
+
+		var object map[string]interface{}
+		if err := yaml.Unmarshal([]byte(content), &object); err != nil {
+			return nil, errors.Wrap(err, "failed to parse manifest object")
+		}
+
+		if len(object) == 0 {
+			object = nil
+		}
+
+		documents[index] = &manifestDocument{
+			content: content,
+			object:  object,
+		}
+	}
+
+	return documents, nil
+}
+
+func joinManifest(documents []*manifestDocument) string {
+	var manifest strings.Builder
+
+	for _, document := range documents {
+		manifest.WriteString("---\n")
+		manifest.WriteString(strings.TrimSuffix(document.content, "\n"))
+		manifest.WriteString("\n")
+	}
+
+	return manifest.String()
+}
+
+// key identifies the object of a document by namespace, kind and name.
+func (d *manifestDocument) key() string {
+	object := unstructured.Unstructured{Object: d.object}
+
+	return strings.Join([]string{object.GetNamespace(), object.GetKind(), object.GetName()}, "/")
+}
+
+// redact masks the secret values of the object of a document and returns whether anything is masked.
+// Secret data differing from the previous version of the object (if any) is masked as changed.
+func (d *manifestDocument) redact(previous map[string]interface{}, sensitiveValues []string) (bool, error) {
+	redacted := false
+	if isSecretObject(d.object) {
+		redactSecretData(d.object, previous)
+		redacted = true
+	}
+
+	masked, _ := walkStringValues(d.object, "", func(_ string, value string) (string, error) {
+		for _, sensitiveValue := range sensitiveValues {
+			if sensitiveValue != "" && strings.Contains(value, sensitiveValue) {
+				value = strings.Replace(value, sensitiveValue, redactedValue, -1)
+				redacted = true
+			}
+		}
+
+		return value, nil
+	})
+
+	if !redacted {
+		return false, nil
+	}
+
+	d.object = masked.(map[string]interface{})
+
+	content, err := yaml.Marshal(d.object)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to encode manifest object")
+	}
+
+	d.content = string(content)
+
+	return true, nil
+}
+
+func isSecretObject(object map[string]interface{}) bool {
+	return object["apiVersion"] == "v1" && object["kind"] == "Secret"
+}
+
+// redactSecretData masks the data and stringData values of a Secret object.
+// Values differing from the previous version of the object (if any) are masked as changed.
+func redactSecretData(object map[string]interface{}, previous map[string]interface{}) {
+	for _, field := range []string{"data", "stringData"} {
+		data, ok := object[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		previousData, _ := previous[field].(map[string]interface{})
+
+		redacted := make(map[string]interface{}, len(data))
+		for key, value := range data {
+			if previousValue, ok := previousData[key]; previous != nil && (!ok || !reflect.DeepEqual(previousValue, value)) {
+				redacted[key] = redactedChangedValue
+			} else {
+				redacted[key] = redactedValue
+			}
+		}
+
+		object[field] = redacted
+	}
+}
@@ -0,0 +1,522 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/pkg/errors"
+	helm_env "k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/repo"
+	"k8s.io/helm/pkg/tlsutil"
+)
+
+// ociScheme is the URL scheme of charts and repositories stored in OCI registries.
+const ociScheme = "oci://"
+
+// ociManifestMediaType is the media type of OCI image manifests.
+const ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+
+// ociChartLayerMediaTypes are the layer media types of Helm charts stored in OCI registries.
+// nolint: gochecknoglobals
+var ociChartLayerMediaTypes = map[string]bool{
+	"application/vnd.cncf.helm.chart.content.v1.tar+gzip": true,
+	"application/tar+gzip":                                true,
+}
+
+// maxOCIChartSize is the maximum size of a chart archive pulled from an OCI registry.
+const maxOCIChartSize = maxCompressedDataSize
+
+// IsOCIRepository returns true if the repository (or chart) URL points to an OCI registry
+func IsOCIRepository(repoURL string) bool {
+	return strings.HasPrefix(repoURL, ociScheme)
+}
+
+// ociReference is a chart reference in an OCI registry (eg. oci://registry.example.com/charts/mychart).
+type ociReference struct {
+	host       string
+	repository string
+	tag        string
+}
+
+// chartName returns the name of the referenced chart.
+func (r ociReference) chartName() string {
+	return r.repository[strings.LastIndex(r.repository, "/")+1:]
+}
+
+// parseOCIReference parses an OCI chart reference with an optional tag.
+func parseOCIReference(ref string) (ociReference, error) {
+	if !IsOCIRepository(ref) {
+		return ociReference{}, errors.Errorf("invalid OCI reference: %s", ref)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(ref, ociScheme), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || strings.Trim(parts[1], "/") == "" {
+		return ociReference{}, errors.Errorf("invalid OCI reference: %s", ref)
+	}
+
+	reference := ociReference{
+		host:       parts[0],
+		repository: strings.Trim(parts[1], "/"),
+	}
+
+	if i := strings.LastIndex(reference.repository, ":"); i > strings.LastIndex(reference.repository, "/") {
+		reference.tag = reference.repository[i+1:]
+		reference.repository = reference.repository[:i]
+	}
+
+	return reference, nil
+}
+
+// findOCIChart resolves a chart name to an OCI reference and returns the repository entry holding its credentials.
+// Chart names are either OCI references or point to a chart in an OCI repository (eg. myrepo/mychart).
+// An empty reference is returned for charts in index based repositories.
+func findOCIChart(name string, env helm_env.EnvSettings) (string, *repo.Entry, error) {
+	var repositories []*repo.Entry
+	if f, err := repo.LoadRepositoriesFile(env.Home.RepositoryFile()); err == nil {
+		repositories = f.Repositories
+	} else if !IsOCIRepository(name) {
+		return "", nil, nil
+	}
+
+	if IsOCIRepository(name) {
+		var found *repo.Entry
+		for _, entry := range repositories {
+			prefix := strings.TrimSuffix(entry.URL, "/") + "/"
+			if IsOCIRepository(entry.URL) && strings.HasPrefix(name, prefix) && (found == nil || len(entry.URL) > len(found.URL)) {
+				found = entry
+			}
+		}
+
+		return name, found, nil
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) != 2 {
+		return "", nil, nil
+	}
+
+	for _, entry := range repositories {
+		if entry.Name == parts[0] && IsOCIRepository(entry.URL) {
+			return strings.TrimSuffix(entry.URL, "/") + "/" + parts[1], entry, nil
+		}
+	}
+
+	return "", nil, nil
+}
+
+// downloadOCIChart pulls a chart from an OCI registry into the destination directory.
+// The version is either an exact version or a semver constraint that is matched against the tags of the chart.
+func downloadOCIChart(ref, version string, entry *repo.Entry, dest string) (string, error) {
+	reference, err := parseOCIReference(ref)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := newOCIRegistryClient("https://"+reference.host, entry)
+	if err != nil {
+		return "", err
+	}
+
+	if version == "" {
+		version = reference.tag
+	}
+
+	tag, err := client.resolveTag(reference.repository, version)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := client.pullChart(reference.repository, tag)
+	if err != nil {
+		return "", err
+	}
+
+	filename := filepath.Join(dest, GetVersionedChartName(reference.chartName(), strings.Replace(tag, "_", "+", -1))+".tgz")
+	if err := ioutil.WriteFile(filename, content, 0644); err != nil {
+		return "", errors.Wrap(err, "failed to write chart archive")
+	}
+
+	return filepath.Abs(filename)
+}
+
+// pingOCIRegistry checks that an OCI registry is accessible with the credentials of a repository entry.
+func pingOCIRegistry(entry *repo.Entry) error {
+	host := strings.SplitN(strings.TrimPrefix(entry.URL, ociScheme), "/", 2)[0]
+	if host == "" {
+		return errors.Errorf("invalid OCI repository URL: %s", entry.URL)
+	}
+
+	client, err := newOCIRegistryClient("https://"+host, entry)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.get("/v2/", "", "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected OCI registry response: %s", resp.Status)
+	}
+
+	return nil
+}
+
+// ociRegistryClient is a minimal client of the OCI distribution API supporting basic and token authentication.
+type ociRegistryClient struct {
+	endpoint string
+	username string
+	password string
+	token    string
+	client   *http.Client
+}
+
+// newOCIRegistryClient creates an OCI registry client using the credentials of a repository entry (if any).
+func newOCIRegistryClient(endpoint string, entry *repo.Entry) (*ociRegistryClient, error) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+	}
+
+	client := &ociRegistryClient{
+		endpoint: endpoint,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   time.Minute,
+		},
+	}
+
+	if entry != nil {
+		client.username = entry.Username
+		client.password = entry.Password
+
+		if (entry.CertFile != "" && entry.KeyFile != "") || entry.CAFile != "" {
+			tlsConfig, err := tlsutil.NewTLSConfig(endpoint, entry.CertFile, entry.KeyFile, entry.CAFile)
+			if err != nil {
+				return nil, errors.Wrap(err, "can't create TLS config")
+			}
+			transport.TLSClientConfig = tlsConfig
+		}
+	}
+
+	return client, nil
+}
+
+// resolveTag returns the tag of the highest chart version matching a version constraint.
+// Exact versions are returned as tags without listing the repository.
+func (c *ociRegistryClient) resolveTag(repository, version string) (string, error) {
+	if _, err := semver.NewVersion(version); err == nil {
+		// OCI tags cannot contain "+"
+		return strings.Replace(version, "+", "_", -1), nil
+	}
+
+	if version == "" || version == "latest" {
+		version = "*"
+	}
+
+	constraint, err := semver.NewConstraint(version)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid chart version: %s", version)
+	}
+
+	resp, err := c.get("/v2/"+repository+"/tags/list", "", "repository:"+repository+":pull")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("failed to list tags of %s: %s", repository, resp.Status)
+	}
+
+	var tagList struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tagList); err != nil {
+		return "", errors.Wrap(err, "failed to decode tag list")
+	}
+
+	versions := make(map[*semver.Version]string)
+	candidates := make([]*semver.Version, 0, len(tagList.Tags))
+	for _, tag := range tagList.Tags {
+		v, err := semver.NewVersion(strings.Replace(tag, "_", "+", -1))
+		if err != nil || !constraint.Check(v) {
+			continue
+		}
+
+		versions[v] = tag
+		candidates = append(candidates, v)
+	}
+
+	if len(candidates) == 0 {
+		return "", errors.Errorf("no chart version found in %s matching %s", repository, version)
+	}
+
+	sort.Sort(semver.Collection(candidates))
+
+	return versions[candidates[len(candidates)-1]], nil
+}
+
+// pullChart downloads the chart layer of a tagged chart.
+func (c *ociRegistryClient) pullChart(repository, tag string) ([]byte, error) {
+	scope := "repository:" + repository + ":pull"
+
+	resp, err := c.get("/v2/"+repository+"/manifests/"+tag, ociManifestMediaType, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get manifest of %s:%s: %s", repository, tag, resp.Status)
+	}
+
+	var manifest struct {
+		Layers []struct {
+			MediaType string `json:"mediaType"`
+			Digest    string `json:"digest"`
+			Size      int64  `json:"size"`
+		} `json:"layers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to decode manifest")
+	}
+
+	for _, layer := range manifest.Layers {
+		if !ociChartLayerMediaTypes[layer.MediaType] {
+			continue
+		}
+
+		if layer.Size > maxOCIChartSize {
+			return nil, errors.WithStack(&chartDataIsTooBigError{layer.Size})
+		}
+
+		return c.pullBlob(repository, layer.Digest)
+	}
+
+	return nil, errors.Errorf("%s:%s is not a helm chart", repository, tag)
+}
+
+// pullBlob downloads a blob and verifies its digest.
+func (c *ociRegistryClient) pullBlob(repository, digest string) ([]byte, error) {
+	if !strings.HasPrefix(digest, "sha256:") {
+		return nil, errors.Errorf("unsupported digest: %s", digest)
+	}
+
+	resp, err := c.get("/v2/"+repository+"/blobs/"+digest, "", "repository:"+repository+":pull")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to get blob %s: %s", digest, resp.Status)
+	}
+
+	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxOCIChartSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read blob")
+	}
+
+	if len(content) > maxOCIChartSize {
+		return nil, errors.WithStack(&chartDataIsTooBigError{int64(len(content))})
+	}
+
+	sum := sha256.Sum256(content)
+	if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+		return nil, errors.Errorf("digest mismatch of blob %s", digest)
+	}
+
+	return content, nil
+}
+
+// get sends a GET request to the registry API.
+// When the registry requires token authentication a token is requested for the given scope.
+func (c *ociRegistryClient) get(path string, accept string, scope string) (*http.Response, error) {
+	resp, err := c.do(path, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, errors.New("unauthorized to access OCI registry")
+	}
+
+	if err := c.requestToken(challenge, scope); err != nil {
+		return nil, err
+	}
+
+	resp, err = c.do(path, accept)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+
+		return nil, errors.New("unauthorized to access OCI registry")
+	}
+
+	return resp, nil
+}
+
+func (c *ociRegistryClient) do(path string, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create registry request")
+	}
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	} else if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reach OCI registry")
+	}
+
+	return resp, nil
+}
+
+// requestToken requests a token from the authorization server of the registry.
+func (c *ociRegistryClient) requestToken(challenge string, scope string) error {
+	params := parseOCIAuthChallenge(challenge)
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return errors.Errorf("invalid registry authentication challenge: %s", challenge)
+	}
+
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if challengeScope := params["scope"]; challengeScope != "" {
+		scope = challengeScope
+	}
+	if scope != "" {
+		query.Set("scope", scope)
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create token request")
+	}
+
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to reach registry authorization server")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("unauthorized to access OCI registry")
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return errors.Wrap(err, "failed to decode registry token")
+	}
+
+	c.token = token.Token
+	if c.token == "" {
+		c.token = token.AccessToken
+	}
+
+	if c.token == "" {
+		return errors.New("no token received from registry authorization server")
+	}
+
+	return nil
+}
+
+// parseOCIAuthChallenge parses the parameters of a WWW-Authenticate header.
+// eg. Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:foo/bar:pull"
+func parseOCIAuthChallenge(challenge string) map[string]string {
+	params := make(map[string]string)
+
+	parts := strings.SplitN(challenge, " ", 2)
+	if len(parts) < 2 {
+		return params
+	}
+
+	for _, param := range splitChallengeParams(parts[1]) {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		params[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+	}
+
+	return params
+}
+
+// splitChallengeParams splits challenge parameters at commas outside of quoted values
+// (scopes may contain commas, eg. repository:foo/bar:pull,push).
+func splitChallengeParams(params string) []string {
+	var (
+		result []string
+		quoted bool
+		start  int
+	)
+
+	for i, r := range params {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				result = append(result, params[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(result, params[start:])
+}
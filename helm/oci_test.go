@@ -0,0 +1,204 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/helm/pkg/repo"
+)
+
+func TestParseOCIReference(t *testing.T) {
+	tests := map[string]ociReference{
+		"oci://registry.example.com/charts/mychart":       {host: "registry.example.com", repository: "charts/mychart"},
+		"oci://localhost:5000/mychart:1.0.0":              {host: "localhost:5000", repository: "mychart", tag: "1.0.0"},
+		"oci://registry.example.com/org/charts/mychart/":  {host: "registry.example.com", repository: "org/charts/mychart"},
+		"oci://registry.example.com/mychart:1.0.0_build1": {host: "registry.example.com", repository: "mychart", tag: "1.0.0_build1"},
+	}
+
+	for ref, expected := range tests {
+		ref, expected := ref, expected
+
+		t.Run(ref, func(t *testing.T) {
+			reference, err := parseOCIReference(ref)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, reference)
+		})
+	}
+
+	for _, ref := range []string{"stable/mychart", "oci://registry.example.com", "oci:///mychart"} {
+		_, err := parseOCIReference(ref)
+		assert.Error(t, err, ref)
+	}
+}
+
+func TestFindOCIChart(t *testing.T) {
+	home, err := ioutil.TempDir("", "helm")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	env := CreateEnvSettings(home)
+	require.NoError(t, EnsureDirectories(env))
+
+	private := &repo.Entry{Name: "private", URL: "oci://registry.example.com/charts", Username: "user"}
+	f := repo.NewRepoFile()
+	f.Add(&repo.Entry{Name: "stable", URL: "https://kubernetes-charts.storage.googleapis.com"}, private)
+	require.NoError(t, f.WriteFile(env.Home.RepositoryFile(), 0644))
+
+	ref, entry, err := findOCIChart("private/mychart", env)
+	require.NoError(t, err)
+	assert.Equal(t, "oci://registry.example.com/charts/mychart", ref)
+	assert.Equal(t, private, entry)
+
+	ref, entry, err = findOCIChart("oci://registry.example.com/charts/mychart", env)
+	require.NoError(t, err)
+	assert.Equal(t, "oci://registry.example.com/charts/mychart", ref)
+	assert.Equal(t, private, entry)
+
+	ref, entry, err = findOCIChart("oci://other.example.com/mychart", env)
+	require.NoError(t, err)
+	assert.Equal(t, "oci://other.example.com/mychart", ref)
+	assert.Nil(t, entry)
+
+	ref, _, err = findOCIChart("stable/mychart", env)
+	require.NoError(t, err)
+	assert.Empty(t, ref)
+}
+
+func TestOCIRegistryClient(t *testing.T) {
+	chart := []byte("chart archive")
+	sum := sha256.Sum256(chart)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	auth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		assert.Equal(t, "registry.example.com", r.URL.Query().Get("service"))
+		assert.Equal(t, "repository:charts/mychart:pull", r.URL.Query().Get("scope"))
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "bearer-token"})
+	}))
+	defer auth.Close()
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer bearer-token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+auth.URL+`/token",service="registry.example.com"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/charts/mychart/tags/list":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"name": "charts/mychart",
+				"tags": []string{"0.1.0", "0.2.0", "0.10.0_build1", "1.0.0-rc1", "latest"},
+			})
+
+		case "/v2/charts/mychart/manifests/0.10.0_build1":
+			assert.Equal(t, ociManifestMediaType, r.Header.Get("Accept"))
+
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"schemaVersion": 2,
+				"layers": []map[string]interface{}{
+					{"mediaType": "application/vnd.cncf.helm.chart.content.v1.tar+gzip", "digest": digest, "size": len(chart)},
+				},
+			})
+
+		case "/v2/charts/mychart/blobs/" + digest:
+			_, _ = w.Write(chart)
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer registry.Close()
+
+	client, err := newOCIRegistryClient(registry.URL, &repo.Entry{Username: "user", Password: "token"})
+	require.NoError(t, err)
+
+	tag, err := client.resolveTag("charts/mychart", "")
+	require.NoError(t, err)
+	assert.Equal(t, "0.10.0_build1", tag)
+
+	tag, err = client.resolveTag("charts/mychart", "~0.1")
+	require.NoError(t, err)
+	assert.Equal(t, "0.1.0", tag)
+
+	tag, err = client.resolveTag("charts/mychart", "0.10.0+build1")
+	require.NoError(t, err)
+	assert.Equal(t, "0.10.0_build1", tag)
+
+	_, err = client.resolveTag("charts/mychart", ">=2.0.0")
+	assert.Error(t, err)
+
+	content, err := client.pullChart("charts/mychart", "0.10.0_build1")
+	require.NoError(t, err)
+	assert.Equal(t, chart, content)
+
+	_, err = client.pullChart("charts/mychart", "0.3.0")
+	assert.Error(t, err)
+
+	unauthorized, err := newOCIRegistryClient(registry.URL, &repo.Entry{Username: "user", Password: "wrong"})
+	require.NoError(t, err)
+
+	_, err = unauthorized.resolveTag("charts/mychart", "")
+	assert.Error(t, err)
+}
+
+func TestOCIRegistryClient_DigestMismatch(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/manifests/") {
+			_, _ = w.Write([]byte(`{"layers": [{"mediaType": "application/tar+gzip", "digest": "sha256:0000", "size": 5}]}`))
+			return
+		}
+
+		_, _ = w.Write([]byte("chart"))
+	}))
+	defer registry.Close()
+
+	client, err := newOCIRegistryClient(registry.URL, nil)
+	require.NoError(t, err)
+
+	_, err = client.pullChart("mychart", "1.0.0")
+	assert.Error(t, err)
+}
+
+func TestParseOCIAuthChallenge(t *testing.T) {
+	params := parseOCIAuthChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:foo/bar:pull,push"`)
+
+	assert.Equal(
+		t,
+		map[string]string{
+			"realm":   "https://auth.example.com/token",
+			"service": "registry.example.com",
+			"scope":   "repository:foo/bar:pull,push",
+		},
+		params,
+	)
+}
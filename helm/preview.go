@@ -0,0 +1,183 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/helm/pkg/releaseutil"
+
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+// DiffDeploymentManifests compares a live and a rendered release manifest object by object
+// and returns the added, changed and removed resources.
+// The data of Secret objects and the sensitive values (eg. resolved secret references) are redacted.
+func DiffDeploymentManifests(releaseName, liveManifest, renderedManifest string, sensitiveValues []string) (*pkgHelm.DeploymentPreviewResponse, error) {
+	liveManifest, renderedManifest, err := redactManifests(liveManifest, renderedManifest, sensitiveValues)
+	if err != nil {
+		return nil, err
+	}
+
+	liveObjects, err := parseManifestObjects(liveManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	renderedObjects, err := parseManifestObjects(renderedManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &pkgHelm.DeploymentPreviewResponse{
+		ReleaseName: releaseName,
+		Added:       []pkgHelm.DeploymentResourceChange{},
+		Changed:     []pkgHelm.DeploymentResourceChange{},
+		Removed:     []pkgHelm.DeploymentResourceChange{},
+	}
+
+	for key, rendered := range renderedObjects {
+		live, ok := liveObjects[key]
+		if ok && live.content == rendered.content {
+			continue
+		}
+
+		change, err := diffManifestObjects(rendered.resource, live.content, rendered.content)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			preview.Changed = append(preview.Changed, change)
+		} else {
+			preview.Added = append(preview.Added, change)
+		}
+	}
+
+	for key, live := range liveObjects {
+		if _, ok := renderedObjects[key]; ok {
+			continue
+		}
+
+		change, err := diffManifestObjects(live.resource, live.content, "")
+		if err != nil {
+			return nil, err
+		}
+
+		preview.Removed = append(preview.Removed, change)
+	}
+
+	for _, changes := range [][]pkgHelm.DeploymentResourceChange{preview.Added, preview.Changed, preview.Removed} {
+		sortResourceChanges(changes)
+	}
+
+	return preview, nil
+}
+
+// manifestObject is a single K8s resource of a release manifest.
+type manifestObject struct {
+	resource pkgHelm.DeploymentResource
+	content  string
+}
+
+// parseManifestObjects splits a release manifest into its objects keyed by namespace, kind and name.
+// The manifest is split on YAML document separators the same way Helm does,
+// so that separator-like content in values (eg. PEM encoded certificates) doesn't break objects apart.
+// Objects are decoded as unstructured objects, so custom resources are compared as well.
+func parseManifestObjects(manifest string) (map[string]manifestObject, error) {
+	objects := make(map[string]manifestObject)
+
+	for _, document := range releaseutil.SplitManifests(manifest) {
+		object, content, err := decodeManifestObject(document)
+		if err != nil {
+			return nil, err
+		}
+
+		if object == nil || object.GetKind() == "" || object.GetName() == "" {
+			continue
+		}
+
+		key := strings.Join([]string{object.GetNamespace(), object.GetKind(), object.GetName()}, "/")
+		objects[key] = manifestObject{
+			resource: pkgHelm.DeploymentResource{
+				Name: object.GetName(),
+				Kind: object.GetKind(),
+			},
+			content: content,
+		}
+	}
+
+	return objects, nil
+}
+
+// decodeManifestObject decodes a manifest object and re-encodes it,
+// so that comments, formatting and key order differences don't show up in diffs.
+// It returns nil for empty documents (eg. templates rendered to comments only).
+func decodeManifestObject(document string) (*unstructured.Unstructured, string, error) {
+	var content map[string]interface{}
+
+	if err := yaml.Unmarshal([]byte(document), &content); err != nil {
+		return nil, "", errors.Wrap(err, "failed to parse manifest object")
+	}
+
+	if len(content) == 0 {
+		return nil, "", nil
+	}
+
+	normalized, err := yaml.Marshal(content)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to encode manifest object")
+	}
+
+	return &unstructured.Unstructured{Object: content}, string(normalized), nil
+}
+
+// diffManifestObjects creates a unified diff of the live and the rendered version of a resource.
+func diffManifestObjects(resource pkgHelm.DeploymentResource, live string, rendered string) (pkgHelm.DeploymentResourceChange, error) {
+	name := fmt.Sprintf("%s/%s", resource.Kind, resource.Name)
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(live),
+		B:        difflib.SplitLines(rendered),
+		FromFile: name + " (live)",
+		ToFile:   name + " (rendered)",
+		Context:  3,
+	})
+	if err != nil {
+		return pkgHelm.DeploymentResourceChange{}, errors.Wrap(err, "failed to diff manifest objects")
+	}
+
+	return pkgHelm.DeploymentResourceChange{
+		Name: resource.Name,
+		Kind: resource.Kind,
+		Diff: diff,
+	}, nil
+}
+
+func sortResourceChanges(changes []pkgHelm.DeploymentResourceChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+
+		return changes[i].Name < changes[j].Name
+	})
+}
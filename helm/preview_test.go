@@ -0,0 +1,290 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+const previewLiveManifest = `
+---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  replicas: "1"
+---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+---
+# Source: app/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-legacy
+`
+
+const previewRenderedManifest = `
+---
+# Source: app/templates/service.yaml
+apiVersion: v1
+metadata:
+  name: app
+kind: Service
+spec:
+  ports:
+    - port: 80
+---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  replicas: "2"
+---
+# Source: app/templates/serviceaccount.yaml
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: app
+`
+
+func TestDiffDeploymentManifests(t *testing.T) {
+	preview, err := DiffDeploymentManifests("my-release", previewLiveManifest, previewRenderedManifest, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-release", preview.ReleaseName)
+
+	assert.Equal(
+		t,
+		[]pkgHelm.DeploymentResourceChange{
+			{
+				Name: "app",
+				Kind: "ServiceAccount",
+				Diff: "--- ServiceAccount/app (live)\n+++ ServiceAccount/app (rendered)\n@@ -1 +1,5 @@\n+apiVersion: v1\n+kind: ServiceAccount\n+metadata:\n+  name: app\n \n",
+			},
+		},
+		preview.Added,
+	)
+
+	// Formatting, comment and key order differences are ignored
+	assert.Equal(
+		t,
+		[]pkgHelm.DeploymentResourceChange{
+			{
+				Name: "app-config",
+				Kind: "ConfigMap",
+				Diff: "--- ConfigMap/app-config (live)\n+++ ConfigMap/app-config (rendered)\n@@ -1,6 +1,6 @@\n apiVersion: v1\n data:\n-  replicas: \"1\"\n+  replicas: \"2\"\n kind: ConfigMap\n metadata:\n   name: app-config\n",
+			},
+		},
+		preview.Changed,
+	)
+
+	require.Len(t, preview.Removed, 1)
+	assert.Equal(t, "app-legacy", preview.Removed[0].Name)
+	assert.Equal(t, "Secret", preview.Removed[0].Kind)
+}
+
+func TestDiffDeploymentManifests_Install(t *testing.T) {
+	preview, err := DiffDeploymentManifests("my-release", "", previewRenderedManifest, nil)
+	require.NoError(t, err)
+
+	var added []pkgHelm.DeploymentResource
+	for _, change := range preview.Added {
+		added = append(added, pkgHelm.DeploymentResource{Name: change.Name, Kind: change.Kind})
+	}
+
+	assert.Equal(
+		t,
+		[]pkgHelm.DeploymentResource{
+			{Name: "app-config", Kind: "ConfigMap"},
+			{Name: "app", Kind: "Service"},
+			{Name: "app", Kind: "ServiceAccount"},
+		},
+		added,
+	)
+	assert.Empty(t, preview.Changed)
+	assert.Empty(t, preview.Removed)
+}
+
+func TestDiffDeploymentManifests_ObjectSeparators(t *testing.T) {
+	const live = `
+---
+# Source: app/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-tls
+  namespace: default
+stringData:
+  tls.crt: |
+    -----BEGIN CERTIFICATE-----
+    MIIB
+    -----END CERTIFICATE-----
+---
+# Source: app/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-tls
+  namespace: monitoring
+stringData:
+  tls.crt: |
+    -----BEGIN CERTIFICATE-----
+    MIIB
+    -----END CERTIFICATE-----
+---
+# Source: app/templates/certificate.yaml
+apiVersion: certmanager.k8s.io/v1alpha1
+kind: Certificate
+metadata:
+  name: app
+spec:
+  secretName: app-tls
+`
+
+	const rendered = `
+---
+# Source: app/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-tls
+  namespace: default
+stringData:
+  tls.crt: |
+    -----BEGIN CERTIFICATE-----
+    MIIB
+    -----END CERTIFICATE-----
+---
+# Source: app/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-tls
+  namespace: monitoring
+stringData:
+  tls.crt: |
+    -----BEGIN CERTIFICATE-----
+    MIIC
+    -----END CERTIFICATE-----
+---
+# Source: app/templates/certificate.yaml
+apiVersion: certmanager.k8s.io/v1alpha1
+kind: Certificate
+metadata:
+  name: app
+spec:
+  secretName: app-tls-v2
+`
+
+	preview, err := DiffDeploymentManifests("my-release", live, rendered, nil)
+	require.NoError(t, err)
+
+	var changed []pkgHelm.DeploymentResource
+	for _, change := range preview.Changed {
+		changed = append(changed, pkgHelm.DeploymentResource{Name: change.Name, Kind: change.Kind})
+	}
+
+	// Only the secret of the monitoring namespace and the custom resource are changed
+	assert.Equal(
+		t,
+		[]pkgHelm.DeploymentResource{
+			{Name: "app", Kind: "Certificate"},
+			{Name: "app-tls", Kind: "Secret"},
+		},
+		changed,
+	)
+	assert.Contains(t, preview.Changed[1].Diff, "+  tls.crt: <redacted, changed>")
+	assert.Empty(t, preview.Added)
+	assert.Empty(t, preview.Removed)
+}
+
+func TestDiffDeploymentManifests_Secrets(t *testing.T) {
+	const live = `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+data:
+  password: czNjcjN0
+  username: YWRtaW4=
+`
+
+	const rendered = `
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+data:
+  password: bjN3czNjcjN0
+  username: YWRtaW4=
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-token
+stringData:
+  token: t0k3n
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  url: postgres://admin:n3ws3cr3t@db:5432
+`
+
+	preview, err := DiffDeploymentManifests("my-release", live, rendered, []string{"n3ws3cr3t"})
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]pkgHelm.DeploymentResourceChange{
+			{
+				Name: "app",
+				Kind: "Secret",
+				Diff: "--- Secret/app (live)\n+++ Secret/app (rendered)\n@@ -1,6 +1,6 @@\n apiVersion: v1\n data:\n-  password: <redacted>\n+  password: <redacted, changed>\n   username: <redacted>\n kind: Secret\n metadata:\n",
+			},
+		},
+		preview.Changed,
+	)
+
+	require.Len(t, preview.Added, 2)
+	assert.Contains(t, preview.Added[0].Diff, "+  url: postgres://admin:<redacted>@db:5432\n")
+	assert.Contains(t, preview.Added[1].Diff, "+  token: <redacted>\n")
+
+	for _, change := range append(preview.Changed, preview.Added...) {
+		for _, value := range []string{"czNjcjN0", "bjN3czNjcjN0", "YWRtaW4=", "t0k3n", "n3ws3cr3t"} {
+			assert.NotContains(t, change.Diff, value)
+		}
+	}
+}
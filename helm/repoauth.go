@@ -0,0 +1,296 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	helm_env "k8s.io/helm/pkg/helm/environment"
+	"k8s.io/helm/pkg/repo"
+
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	phelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+// RepositoryCredentials holds the credentials used to access an authenticated chart repository
+type RepositoryCredentials struct {
+	Username string
+	Password string
+
+	// PEM encoded TLS client certificate, key and CA certificate
+	ClientCert string
+	ClientKey  string
+	CACert     string
+}
+
+// RepositorySecretGetter returns the type and the values of a secret of an organization.
+type RepositorySecretGetter func(organizationID uint, secretID string) (secretType string, values map[string]string, err error)
+
+// repositorySecretGetter resolves the credentials of private chart repositories at fetch time.
+// nolint: gochecknoglobals
+var repositorySecretGetter RepositorySecretGetter
+
+// SetRepositorySecretGetter sets the function used to resolve the credentials of private chart repositories.
+func SetRepositorySecretGetter(getter RepositorySecretGetter) {
+	repositorySecretGetter = getter
+}
+
+// RepositorySecret references the secret holding the credentials of a chart repository.
+// The references are stored next to the repositories file, so that the credentials themselves
+// are never written to disk in plain text and are always read from the secret store.
+type RepositorySecret struct {
+	OrganizationID uint   `json:"organizationId"`
+	SecretID       string `json:"secretId"`
+}
+
+// RepositoryCredentialsFromSecret creates chart repository credentials from the values of a Pipeline secret.
+// Password (basic auth), TLS (client certificate) and Docker registry (registry token) secrets are supported.
+func RepositoryCredentialsFromSecret(secretType string, values map[string]string) (*RepositoryCredentials, error) {
+	switch secretType {
+	case secrettype.PasswordSecretType, secrettype.DockerRegistrySecretType:
+		return &RepositoryCredentials{
+			Username: values[secrettype.Username],
+			Password: values[secrettype.Password],
+		}, nil
+
+	case secrettype.TLSSecretType:
+		if values[secrettype.ClientCert] == "" || values[secrettype.ClientKey] == "" {
+			return nil, errors.New("TLS secret has no client certificate")
+		}
+
+		return &RepositoryCredentials{
+			ClientCert: values[secrettype.ClientCert],
+			ClientKey:  values[secrettype.ClientKey],
+			CACert:     values[secrettype.CACert],
+		}, nil
+
+	default:
+		return nil, errors.Errorf("secret type %q cannot be used for helm repositories", secretType)
+	}
+}
+
+// SetRepositoryCredentials sets the credentials of a repository entry.
+// Username and password are only kept in memory, they are never written to the repositories file.
+// TLS certificates are written to the helm home of the organization, because Helm only accepts certificate files.
+//
+// Certificates are written once per content into their own directory, which is populated in a temporary directory
+// and renamed into place, so that concurrent fetches never see partially written or removed certificate files.
+func SetRepositoryCredentials(env helm_env.EnvSettings, entry *repo.Entry, credentials *RepositoryCredentials) error {
+	if entry.Name == "" || entry.Name == ".." || filepath.Base(entry.Name) != entry.Name {
+		return errors.Errorf("invalid repository name: %q", entry.Name)
+	}
+
+	entry.Username = credentials.Username
+	entry.Password = credentials.Password
+	entry.CertFile = ""
+	entry.KeyFile = ""
+	entry.CAFile = ""
+
+	if credentials.ClientCert == "" && credentials.CACert == "" {
+		return nil
+	}
+
+	files := []struct {
+		path    *string
+		name    string
+		content string
+	}{
+		{path: &entry.CertFile, name: "cert.pem", content: credentials.ClientCert},
+		{path: &entry.KeyFile, name: "key.pem", content: credentials.ClientKey},
+		{path: &entry.CAFile, name: "ca.pem", content: credentials.CACert},
+	}
+
+	hash := sha256.New()
+	for _, file := range files {
+		_, _ = fmt.Fprintf(hash, "%s:%d:%s\n", file.name, len(file.content), file.content)
+	}
+
+	repoCertDir := repositoryCertDir(env, entry.Name)
+	certDir := filepath.Join(repoCertDir, hex.EncodeToString(hash.Sum(nil))[:16])
+
+	if _, err := os.Stat(certDir); os.IsNotExist(err) {
+		if err := os.MkdirAll(repoCertDir, 0700); err != nil {
+			return errors.Wrap(err, "failed to create repository certificate directory")
+		}
+
+		tmpDir, err := ioutil.TempDir(repoCertDir, ".tmp-")
+		if err != nil {
+			return errors.Wrap(err, "failed to create temporary repository certificate directory")
+		}
+		defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+		for _, file := range files {
+			if file.content == "" {
+				continue
+			}
+
+			if err := ioutil.WriteFile(filepath.Join(tmpDir, file.name), []byte(file.content), 0600); err != nil {
+				return errors.Wrapf(err, "failed to write repository certificate %s", file.name)
+			}
+		}
+
+		// another fetch may have written the same certificates in the meantime
+		if err := os.Rename(tmpDir, certDir); err != nil {
+			if _, statErr := os.Stat(certDir); statErr != nil {
+				return errors.Wrap(err, "failed to move repository certificates into place")
+			}
+		}
+	} else if err != nil {
+		return errors.Wrap(err, "failed to check repository certificate directory")
+	}
+
+	for _, file := range files {
+		if file.content == "" {
+			continue
+		}
+
+		*file.path = filepath.Join(certDir, file.name)
+	}
+
+	return nil
+}
+
+// GetRepositorySecretID returns the ID of the secret holding the credentials of a repository.
+// An empty ID is returned for public repositories.
+func GetRepositorySecretID(env helm_env.EnvSettings, repoName string) (string, error) {
+	secrets, err := loadRepositorySecrets(env)
+	if err != nil {
+		return "", err
+	}
+
+	return secrets[repoName].SecretID, nil
+}
+
+// ListRepositorySecretIDs returns the IDs of the secrets referenced by the repositories of an organization
+// keyed by repository name. Unlike GenerateHelmRepoEnv, it does not initialize the Helm home of the organization.
+func ListRepositorySecretIDs(orgName string) (map[string]string, error) {
+	env := CreateEnvSettings(fmt.Sprintf("%s/%s", config.GetHelmPath(orgName), phelm.HelmPostFix))
+
+	secrets, err := loadRepositorySecrets(env)
+	if err != nil {
+		return nil, err
+	}
+
+	secretIDs := make(map[string]string, len(secrets))
+	for repoName, repositorySecret := range secrets {
+		secretIDs[repoName] = repositorySecret.SecretID
+	}
+
+	return secretIDs, nil
+}
+
+// SetRepositorySecret stores the reference of the secret holding the credentials of a repository.
+// The reference is removed if the secret ID is empty.
+func SetRepositorySecret(env helm_env.EnvSettings, repoName string, organizationID uint, secretID string) error {
+	secrets, err := loadRepositorySecrets(env)
+	if err != nil {
+		return err
+	}
+
+	if secretID == "" {
+		if _, ok := secrets[repoName]; !ok {
+			return nil
+		}
+
+		delete(secrets, repoName)
+	} else {
+		secrets[repoName] = RepositorySecret{
+			OrganizationID: organizationID,
+			SecretID:       secretID,
+		}
+	}
+
+	content, err := yaml.Marshal(secrets)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode repository secrets")
+	}
+
+	return errors.Wrap(ioutil.WriteFile(repositorySecretsFile(env), content, 0600), "failed to write repository secrets")
+}
+
+// withRepositoryCredentials returns a copy of a repository entry with the credentials read from its secret.
+// Entries without a secret are returned as they are.
+func withRepositoryCredentials(env helm_env.EnvSettings, entry *repo.Entry) (*repo.Entry, error) {
+	if entry == nil {
+		return nil, nil
+	}
+
+	secrets, err := loadRepositorySecrets(env)
+	if err != nil {
+		return nil, err
+	}
+
+	repositorySecret, ok := secrets[entry.Name]
+	if !ok {
+		return entry, nil
+	}
+
+	if repositorySecretGetter == nil {
+		return nil, errors.New("repository secrets cannot be resolved: no secret getter is set")
+	}
+
+	secretType, values, err := repositorySecretGetter(repositorySecret.OrganizationID, repositorySecret.SecretID)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get the secret of repository %s", entry.Name)
+	}
+
+	credentials, err := RepositoryCredentialsFromSecret(secretType, values)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *entry
+	if err := SetRepositoryCredentials(env, &resolved, credentials); err != nil {
+		return nil, err
+	}
+
+	return &resolved, nil
+}
+
+// loadRepositorySecrets reads the secret references of the repositories keyed by repository name.
+func loadRepositorySecrets(env helm_env.EnvSettings) (map[string]RepositorySecret, error) {
+	secrets := make(map[string]RepositorySecret)
+
+	content, err := ioutil.ReadFile(repositorySecretsFile(env))
+	if os.IsNotExist(err) {
+		return secrets, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to read repository secrets")
+	}
+
+	if err := yaml.Unmarshal(content, &secrets); err != nil {
+		return nil, errors.Wrap(err, "failed to parse repository secrets")
+	}
+
+	return secrets, nil
+}
+
+// repositorySecretsFile returns the path of the file holding the secret references of the repositories.
+func repositorySecretsFile(env helm_env.EnvSettings) string {
+	return env.Home.Path("repository", "secrets.yaml")
+}
+
+// repositoryCertDir returns the directory the TLS certificates of a repository are stored in.
+func repositoryCertDir(env helm_env.EnvSettings, repoName string) string {
+	return env.Home.Path("repository", "certs", repoName)
+}
@@ -0,0 +1,266 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/helm/pkg/repo"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+func TestRepositoryCredentialsFromSecret(t *testing.T) {
+	credentials, err := RepositoryCredentialsFromSecret(secrettype.PasswordSecretType, map[string]string{
+		secrettype.Username: "user",
+		secrettype.Password: "pass",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &RepositoryCredentials{Username: "user", Password: "pass"}, credentials)
+
+	credentials, err = RepositoryCredentialsFromSecret(secrettype.DockerRegistrySecretType, map[string]string{
+		secrettype.DockerRegistryServer: "registry.example.com",
+		secrettype.Username:             "oauth2accesstoken",
+		secrettype.Password:             "token",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &RepositoryCredentials{Username: "oauth2accesstoken", Password: "token"}, credentials)
+
+	credentials, err = RepositoryCredentialsFromSecret(secrettype.TLSSecretType, map[string]string{
+		secrettype.CACert:     "ca",
+		secrettype.ClientCert: "cert",
+		secrettype.ClientKey:  "key",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &RepositoryCredentials{ClientCert: "cert", ClientKey: "key", CACert: "ca"}, credentials)
+
+	_, err = RepositoryCredentialsFromSecret(secrettype.TLSSecretType, map[string]string{secrettype.CACert: "ca"})
+	assert.Error(t, err)
+
+	_, err = RepositoryCredentialsFromSecret(secrettype.Amazon, map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestSetRepositoryCredentials(t *testing.T) {
+	home, err := ioutil.TempDir("", "helm")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	env := CreateEnvSettings(home)
+
+	entry := &repo.Entry{Name: "private", URL: "https://charts.example.com"}
+	err = SetRepositoryCredentials(env, entry, &RepositoryCredentials{
+		ClientCert: "cert",
+		ClientKey:  "key",
+	})
+	require.NoError(t, err)
+
+	certDir := filepath.Dir(entry.CertFile)
+	assert.Equal(t, filepath.Join(home, "repository", "certs", "private"), filepath.Dir(certDir))
+	assert.Equal(t, filepath.Join(certDir, "cert.pem"), entry.CertFile)
+	assert.Equal(t, filepath.Join(certDir, "key.pem"), entry.KeyFile)
+	assert.Empty(t, entry.CAFile)
+
+	key, err := ioutil.ReadFile(entry.KeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, "key", string(key))
+
+	// The same certificates are reused, changed certificates are written next to the previous ones
+	same := &repo.Entry{Name: "private"}
+	err = SetRepositoryCredentials(env, same, &RepositoryCredentials{ClientCert: "cert", ClientKey: "key"})
+	require.NoError(t, err)
+	assert.Equal(t, entry.CertFile, same.CertFile)
+
+	changed := &repo.Entry{Name: "private"}
+	err = SetRepositoryCredentials(env, changed, &RepositoryCredentials{ClientCert: "cert", ClientKey: "new-key"})
+	require.NoError(t, err)
+	assert.NotEqual(t, entry.KeyFile, changed.KeyFile)
+
+	key, err = ioutil.ReadFile(entry.KeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, "key", string(key), "certificates in use must not be modified")
+
+	err = SetRepositoryCredentials(env, entry, &RepositoryCredentials{Username: "user", Password: "pass"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "user", entry.Username)
+	assert.Equal(t, "pass", entry.Password)
+	assert.Empty(t, entry.CertFile)
+
+	err = SetRepositoryCredentials(env, &repo.Entry{Name: "../private"}, &RepositoryCredentials{})
+	assert.Error(t, err)
+}
+
+func TestSetRepositoryCredentials_Concurrent(t *testing.T) {
+	home, err := ioutil.TempDir("", "helm")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	env := CreateEnvSettings(home)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			entry := &repo.Entry{Name: "private"}
+			if err := SetRepositoryCredentials(env, entry, &RepositoryCredentials{ClientCert: "cert", ClientKey: "key"}); err != nil {
+				errs <- err
+
+				return
+			}
+
+			if _, err := ioutil.ReadFile(entry.KeyFile); err != nil {
+				errs <- err
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	dirs, err := ioutil.ReadDir(filepath.Join(home, "repository", "certs", "private"))
+	require.NoError(t, err)
+	assert.Len(t, dirs, 1, "temporary directories should be cleaned up")
+}
+
+func TestRepositorySecret(t *testing.T) {
+	home, err := ioutil.TempDir("", "helm")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	env := CreateEnvSettings(home)
+	require.NoError(t, EnsureDirectories(env))
+
+	secretID, err := GetRepositorySecretID(env, "private")
+	require.NoError(t, err)
+	assert.Empty(t, secretID)
+
+	require.NoError(t, SetRepositorySecret(env, "private", 1, "secret"))
+
+	secretID, err = GetRepositorySecretID(env, "private")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", secretID)
+
+	require.NoError(t, SetRepositorySecret(env, "private", 1, ""))
+
+	secretID, err = GetRepositorySecretID(env, "private")
+	require.NoError(t, err)
+	assert.Empty(t, secretID)
+}
+
+func TestWithRepositoryCredentials(t *testing.T) {
+	home, err := ioutil.TempDir("", "helm")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	env := CreateEnvSettings(home)
+	require.NoError(t, EnsureDirectories(env))
+
+	defer SetRepositorySecretGetter(repositorySecretGetter)
+	SetRepositorySecretGetter(func(organizationID uint, secretID string) (string, map[string]string, error) {
+		if organizationID != 1 || secretID != "secret" {
+			return "", nil, errors.New("secret not found")
+		}
+
+		return secrettype.PasswordSecretType, map[string]string{
+			secrettype.Username: "user",
+			secrettype.Password: "pass",
+		}, nil
+	})
+
+	public := &repo.Entry{Name: "public", URL: "https://charts.example.com"}
+
+	entry, err := withRepositoryCredentials(env, public)
+	require.NoError(t, err)
+	assert.Equal(t, public, entry)
+
+	private := &repo.Entry{Name: "private", URL: "https://private.example.com"}
+	require.NoError(t, SetRepositorySecret(env, "private", 1, "secret"))
+
+	entry, err = withRepositoryCredentials(env, private)
+	require.NoError(t, err)
+	assert.Equal(t, "user", entry.Username)
+	assert.Equal(t, "pass", entry.Password)
+	assert.Empty(t, private.Username)
+
+	require.NoError(t, SetRepositorySecret(env, "private", 1, "missing"))
+
+	_, err = withRepositoryCredentials(env, private)
+	assert.Error(t, err)
+}
+
+func TestReposAdd_SecretCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte("apiVersion: v1\nentries: {}\n"))
+	}))
+	defer ts.Close()
+
+	home, err := ioutil.TempDir("", "helm")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+
+	env := CreateEnvSettings(home)
+	require.NoError(t, EnsureDirectories(env))
+
+	defer SetRepositorySecretGetter(repositorySecretGetter)
+	SetRepositorySecretGetter(func(organizationID uint, secretID string) (string, map[string]string, error) {
+		return secrettype.PasswordSecretType, map[string]string{
+			secrettype.Username: "user",
+			secrettype.Password: "s3cr3t",
+		}, nil
+	})
+
+	added, err := ReposAdd(env, &repo.Entry{Name: "private", URL: ts.URL, Username: "user", Password: "s3cr3t"})
+	require.NoError(t, err)
+	require.True(t, added)
+	require.NoError(t, SetRepositorySecret(env, "private", 1, "secret"))
+
+	// the credentials are not written to the repositories file
+	repositories, err := ioutil.ReadFile(env.Home.RepositoryFile())
+	require.NoError(t, err)
+	assert.NotContains(t, string(repositories), "s3cr3t")
+
+	// the credentials are read from the secret on update
+	require.NoError(t, ReposUpdate(env, "private"))
+
+	require.NoError(t, ReposDelete(env, "private"))
+
+	secretID, err := GetRepositorySecretID(env, "private")
+	require.NoError(t, err)
+	assert.Empty(t, secretID)
+}
@@ -0,0 +1,91 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// ValuesSchemaFile is the file of a chart describing the JSON schema of its values.
+const ValuesSchemaFile = "values.schema.json"
+
+// ValuesValidationError is returned when the values of a deployment do not match a values schema.
+type ValuesValidationError struct {
+	Errors []string
+}
+
+func (e *ValuesValidationError) Error() string {
+	return "values don't meet the specifications of the schema: " + strings.Join(e.Errors, "; ")
+}
+
+// GetValuesSchema returns the values schema of a chart (nil if the chart has none).
+func GetValuesSchema(ch *chart.Chart) []byte {
+	for _, file := range ch.GetFiles() {
+		if file.GetTypeUrl() == ValuesSchemaFile {
+			return file.GetValue()
+		}
+	}
+
+	return nil
+}
+
+// ValidateValuesSchema checks whether a values schema is a valid JSON schema.
+func ValidateValuesSchema(schema []byte) error {
+	_, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema))
+
+	return errors.Wrap(err, "invalid values schema")
+}
+
+// ValidateValues validates the values of a deployment against the values schema of the chart (if it has one)
+// and the additional schemas (eg. the one registered in Pipeline for the chart).
+// Values are validated merged with the default values of the chart.
+func ValidateValues(ch *chart.Chart, values []byte, schemas ...[]byte) error {
+	if chartSchema := GetValuesSchema(ch); chartSchema != nil {
+		schemas = append([][]byte{chartSchema}, schemas...)
+	}
+
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	merged, err := chartutil.CoalesceValues(ch, &chart.Config{Raw: string(values)})
+	if err != nil {
+		return errors.Wrap(err, "failed to merge values with the chart defaults")
+	}
+
+	var validationErrors []string
+
+	for _, schema := range schemas {
+		result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewGoLoader(map[string]interface{}(merged)))
+		if err != nil {
+			return errors.Wrap(err, "failed to validate values")
+		}
+
+		for _, resultError := range result.Errors() {
+			validationErrors = append(validationErrors, resultError.String())
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return &ValuesValidationError{Errors: validationErrors}
+	}
+
+	return nil
+}
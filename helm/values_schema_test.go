@@ -0,0 +1,117 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+const testValuesSchema = `{
+  "type": "object",
+  "required": ["image"],
+  "properties": {
+    "image": {
+      "type": "object",
+      "required": ["repository"],
+      "properties": {
+        "repository": {"type": "string"},
+        "tag": {"type": "string"}
+      }
+    },
+    "replicaCount": {"type": "integer", "minimum": 1}
+  }
+}`
+
+func newTestSchemaChart(schema string) *chart.Chart {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "app", Version: "0.1.0"},
+		Values:   &chart.Config{Raw: "replicaCount: 1\nimage:\n  tag: latest\n"},
+	}
+
+	if schema != "" {
+		ch.Files = []*any.Any{{TypeUrl: ValuesSchemaFile, Value: []byte(schema)}}
+	}
+
+	return ch
+}
+
+func TestValidateValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		values  string
+		extra   []string
+		invalid bool
+	}{
+		{
+			name:   "no schema",
+			values: "replicaCount: 0",
+		},
+		{
+			name:   "valid values",
+			schema: testValuesSchema,
+			values: "image:\n  repository: nginx\n",
+		},
+		{
+			name:    "missing required value",
+			schema:  testValuesSchema,
+			values:  "replicaCount: 2",
+			invalid: true,
+		},
+		{
+			name:    "invalid default overridden",
+			schema:  testValuesSchema,
+			values:  "image:\n  repository: nginx\nreplicaCount: 0\n",
+			invalid: true,
+		},
+		{
+			name:    "registered schema",
+			values:  "image:\n  repository: nginx\n",
+			extra:   []string{`{"properties": {"image": {"properties": {"tag": {"pattern": "^v"}}}}}`},
+			invalid: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			var schemas [][]byte
+			for _, schema := range test.extra {
+				schemas = append(schemas, []byte(schema))
+			}
+
+			err := ValidateValues(newTestSchemaChart(test.schema), []byte(test.values), schemas...)
+
+			if test.invalid {
+				require.Error(t, err)
+				assert.IsType(t, &ValuesValidationError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateValuesSchema(t *testing.T) {
+	assert.NoError(t, ValidateValuesSchema([]byte(testValuesSchema)))
+	assert.Error(t, ValidateValuesSchema([]byte(`{"type": "unknown"}`)))
+	assert.Error(t, ValidateValuesSchema([]byte(`{`)))
+}
@@ -0,0 +1,225 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"github.com/banzaicloud/pipeline/pkg/brn"
+)
+
+// secretReferencePattern matches secret references in values: {{ secret "brn:1:secret:secretID" "key" }}
+// nolint: gochecknoglobals
+var secretReferencePattern = regexp.MustCompile(`\{\{\s*secret\s+"([^"]*)"\s+"([^"]*)"\s*\}\}`)
+
+// secretReferencesKey is the values key under which the unresolved secret references
+// of a deployment are stored with the release, keyed by the path of the referencing value.
+const secretReferencesKey = "pipelineSecretReferences"
+
+// SecretValuesGetter returns the values of a secret of the organization of a deployment.
+type SecretValuesGetter func(secretID string) (map[string]string, error)
+
+// SecretReferenceError is returned when a secret reference in the values of a deployment cannot be resolved.
+type SecretReferenceError struct {
+	Path    string
+	Message string
+}
+
+func (e *SecretReferenceError) Error() string {
+	return fmt.Sprintf("invalid secret reference at %s: %s", e.Path, e.Message)
+}
+
+// ResolveSecretReferences replaces the secret references in the string values of a deployment
+// with the referenced secret values: {{ secret "brn:<organizationID>:secret:<secretID>" "<key>" }}
+// Only the secrets of the organization of the deployment can be referenced.
+// Other templates in the values (eg. the ones evaluated by the chart with tpl) are left untouched.
+// The unresolved values containing references are returned keyed by their path.
+func ResolveSecretReferences(values map[string]interface{}, organizationID uint, getSecret SecretValuesGetter) (map[string]interface{}, map[string]string, error) {
+	secrets := make(map[string]map[string]string)
+	references := make(map[string]string)
+
+	resolved, err := walkStringValues(values, "", func(path string, value string) (string, error) {
+		var resolveErr error
+
+		result := secretReferencePattern.ReplaceAllStringFunc(value, func(reference string) string {
+			if resolveErr != nil {
+				return reference
+			}
+
+			match := secretReferencePattern.FindStringSubmatch(reference)
+
+			rn, err := brn.ParseAs(match[1], brn.SecretResourceType)
+			if err != nil {
+				resolveErr = &SecretReferenceError{Path: path, Message: fmt.Sprintf("%q is not a secret BRN", match[1])}
+
+				return reference
+			}
+
+			if rn.OrganizationID != 0 && rn.OrganizationID != organizationID {
+				resolveErr = &SecretReferenceError{Path: path, Message: "secrets of other organizations cannot be referenced"}
+
+				return reference
+			}
+
+			secretValues, ok := secrets[rn.ResourceID]
+			if !ok {
+				secretValues, err = getSecret(rn.ResourceID)
+				if err != nil {
+					resolveErr = errors.WithMessagef(err, "failed to get secret referenced at %s", path)
+
+					return reference
+				}
+
+				secrets[rn.ResourceID] = secretValues
+			}
+
+			secretValue, ok := secretValues[match[2]]
+			if !ok {
+				resolveErr = &SecretReferenceError{Path: path, Message: fmt.Sprintf("secret has no %q key", match[2])}
+
+				return reference
+			}
+
+			return secretValue
+		})
+
+		if result != value {
+			references[path] = value
+		}
+
+		return result, resolveErr
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resolved == nil {
+		return nil, nil, nil
+	}
+
+	return resolved.(map[string]interface{}), references, nil
+}
+
+// StoreSecretReferences returns the values of a deployment with its unresolved secret references added,
+// so that they can be returned instead of the resolved secret values when the release is read.
+// When the values are merged with the previous values of the release (reuseValues),
+// the references of the other string values are cleared, so the stale ones of the previous revision are dropped.
+//
+// Note that the chart needs the resolved values, so they are still stored in the release storage of Tiller.
+// Tillers installed by Pipeline store releases in Secrets of their namespace,
+// but Tillers installed earlier keep storing them in ConfigMaps (see Install).
+func StoreSecretReferences(values map[string]interface{}, references map[string]string, reuseValues bool) map[string]interface{} {
+	if values == nil || (len(references) == 0 && !reuseValues) {
+		return values
+	}
+
+	stored := make(map[string]interface{}, len(references))
+	if reuseValues {
+		_, _ = walkStringValues(values, "", func(path string, value string) (string, error) {
+			stored[path] = nil
+
+			return value, nil
+		})
+	}
+
+	for path, reference := range references {
+		stored[path] = reference
+	}
+
+	result := make(map[string]interface{}, len(values)+1)
+	for key, value := range values {
+		result[key] = value
+	}
+	result[secretReferencesKey] = stored
+
+	return result
+}
+
+// MaskSecretReferences replaces the resolved secret values of a release with the secret references
+// stored by StoreSecretReferences and removes the stored references from the values.
+func MaskSecretReferences(values map[string]interface{}) map[string]interface{} {
+	stored, ok := values[secretReferencesKey]
+	if !ok {
+		return values
+	}
+
+	withoutReferences := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if key != secretReferencesKey {
+			withoutReferences[key] = value
+		}
+	}
+
+	references, _ := stored.(map[string]interface{})
+
+	masked, _ := walkStringValues(withoutReferences, "", func(path string, value string) (string, error) {
+		if reference, ok := references[path].(string); ok {
+			return reference, nil
+		}
+
+		return value, nil
+	})
+
+	return masked.(map[string]interface{})
+}
+
+// walkStringValues replaces every string in a values tree with the result of fn.
+func walkStringValues(value interface{}, path string, fn func(path string, value string) (string, error)) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return nil, nil
+		}
+
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			itemPath := key
+			if path != "" {
+				itemPath = path + "." + key
+			}
+
+			r, err := walkStringValues(item, itemPath, fn)
+			if err != nil {
+				return nil, err
+			}
+
+			result[key] = r
+		}
+
+		return result, nil
+
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := walkStringValues(item, fmt.Sprintf("%s[%d]", path, i), fn)
+			if err != nil {
+				return nil, err
+			}
+
+			result[i] = r
+		}
+
+		return result, nil
+
+	case string:
+		return fn(path, v)
+
+	default:
+		return v, nil
+	}
+}
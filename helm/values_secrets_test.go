@@ -0,0 +1,189 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecretReferences(t *testing.T) {
+	secrets := map[string]map[string]string{
+		"db": {"password": "s3cr3t", "user": "admin"},
+	}
+
+	var calls int
+	getSecret := func(secretID string) (map[string]string, error) {
+		calls++
+
+		secret, ok := secrets[secretID]
+		if !ok {
+			return nil, errors.New("secret not found")
+		}
+
+		return secret, nil
+	}
+
+	values := map[string]interface{}{
+		"replicaCount": 1,
+		"db": map[string]interface{}{
+			"url":      `postgres://{{ secret "brn:1:secret:db" "user" }}:{{secret "brn:1:secret:db" "password"}}@db`,
+			"password": `{{ secret "brn:1:secret:db" "password" }}`,
+		},
+		"hosts": []interface{}{"example.com", `{{ secret "brn::secret:db" "user" }}`},
+	}
+
+	resolved, references, err := ResolveSecretReferences(values, 1, getSecret)
+	require.NoError(t, err)
+
+	expected := map[string]interface{}{
+		"replicaCount": 1,
+		"db": map[string]interface{}{
+			"url":      "postgres://admin:s3cr3t@db",
+			"password": "s3cr3t",
+		},
+		"hosts": []interface{}{"example.com", "admin"},
+	}
+
+	assert.Equal(t, expected, resolved)
+	assert.Equal(t, 1, calls)
+	assert.Equal(
+		t,
+		map[string]string{
+			"db.url":      `postgres://{{ secret "brn:1:secret:db" "user" }}:{{secret "brn:1:secret:db" "password"}}@db`,
+			"db.password": `{{ secret "brn:1:secret:db" "password" }}`,
+			"hosts[1]":    `{{ secret "brn::secret:db" "user" }}`,
+		},
+		references,
+	)
+	assert.Equal(t, `{{ secret "brn:1:secret:db" "password" }}`, values["db"].(map[string]interface{})["password"])
+}
+
+func TestResolveSecretReferences_Invalid(t *testing.T) {
+	getSecret := func(secretID string) (map[string]string, error) {
+		if secretID != "db" {
+			return nil, errors.New("secret not found")
+		}
+
+		return map[string]string{"password": "s3cr3t"}, nil
+	}
+
+	tests := map[string]string{
+		"not a secret":      `{{ secret "brn:1:cluster:db" "password" }}`,
+		"other org":         `{{ secret "brn:2:secret:db" "password" }}`,
+		"missing key":       `{{ secret "brn:1:secret:db" "user" }}`,
+		"unknown reference": `{{ secret "db" "password" }}`,
+	}
+
+	for name, value := range tests {
+		value := value
+
+		t.Run(name, func(t *testing.T) {
+			_, _, err := ResolveSecretReferences(map[string]interface{}{"db": map[string]interface{}{"password": value}}, 1, getSecret)
+			require.Error(t, err)
+
+			if assert.IsType(t, &SecretReferenceError{}, err) {
+				assert.Equal(t, "db.password", err.(*SecretReferenceError).Path)
+			}
+		})
+	}
+
+	t.Run("secret not found", func(t *testing.T) {
+		_, _, err := ResolveSecretReferences(map[string]interface{}{"password": `{{ secret "brn:1:secret:other" "password" }}`}, 1, getSecret)
+		require.Error(t, err)
+
+		_, ok := err.(*SecretReferenceError)
+		assert.False(t, ok)
+	})
+}
+
+func TestMaskSecretReferences(t *testing.T) {
+	values := map[string]interface{}{
+		"replicaCount": 1,
+		"db": map[string]interface{}{
+			"user":     "admin",
+			"password": "s3cr3t",
+		},
+		"hosts": []interface{}{"example.com", "admin"},
+	}
+	references := map[string]string{
+		"db.password": `{{ secret "brn:1:secret:db" "password" }}`,
+		"hosts[1]":    `{{ secret "brn:1:secret:db" "user" }}`,
+	}
+
+	stored := StoreSecretReferences(values, references, false)
+	assert.NotContains(t, values, secretReferencesKey)
+
+	// the values are stored with the release as YAML
+	raw, err := yaml.Marshal(stored)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "password: s3cr3t")
+	assert.Contains(t, string(raw), secretReferencesKey)
+
+	var released map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(raw, &released))
+
+	expected := map[string]interface{}{
+		"replicaCount": float64(1),
+		"db": map[string]interface{}{
+			"user":     "admin",
+			"password": `{{ secret "brn:1:secret:db" "password" }}`,
+		},
+		"hosts": []interface{}{"example.com", `{{ secret "brn:1:secret:db" "user" }}`},
+	}
+
+	assert.Equal(t, expected, MaskSecretReferences(released))
+}
+
+func TestMaskSecretReferences_ReuseValues(t *testing.T) {
+	previous := StoreSecretReferences(
+		map[string]interface{}{"password": "s3cr3t", "token": "t0k3n"},
+		map[string]string{
+			"password": `{{ secret "brn:1:secret:db" "password" }}`,
+			"token":    `{{ secret "brn:1:secret:api" "token" }}`,
+		},
+		false,
+	)
+
+	// the password is overridden with a plain value, the token is reused
+	current := StoreSecretReferences(map[string]interface{}{"password": "plain"}, map[string]string{}, true)
+
+	merged := map[string]interface{}{
+		"password":          "plain",
+		"token":             "t0k3n",
+		secretReferencesKey: previous[secretReferencesKey],
+	}
+	for path, reference := range current[secretReferencesKey].(map[string]interface{}) {
+		merged[secretReferencesKey].(map[string]interface{})[path] = reference
+	}
+
+	expected := map[string]interface{}{
+		"password": "plain",
+		"token":    `{{ secret "brn:1:secret:api" "token" }}`,
+	}
+
+	assert.Equal(t, expected, MaskSecretReferences(merged))
+}
+
+func TestMaskSecretReferences_NoReferences(t *testing.T) {
+	values := map[string]interface{}{"password": "plain"}
+
+	assert.Equal(t, values, StoreSecretReferences(values, map[string]string{}, false))
+	assert.Equal(t, values, MaskSecretReferences(values))
+}
@@ -47,6 +47,7 @@ func RegisterApp(
 	db *gorm.DB,
 	buildInfo buildinfo.BuildInfo,
 	userExtractor issue.UserExtractor,
+	orgIDExtractor notification.OrgIDContextExtractor,
 	logger Logger,
 	errorHandler emperror.Handler,
 ) error {
@@ -65,7 +66,7 @@ func RegisterApp(
 		errorHandler := emperror.MakeContextAware(emperror.WithDetails(errorHandler, "module", "notification"))
 
 		store := notificationadapter.NewGormStore(db)
-		service := notification.NewService(store)
+		service := notification.NewService(store, orgIDExtractor)
 		endpoints := notificationdriver.TraceEndpoints(notificationdriver.MakeEndpoints(
 			service,
 			kitxendpoint.Chain(endpointMiddleware...),
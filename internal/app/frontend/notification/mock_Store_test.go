@@ -10,13 +10,13 @@ type MockStore struct {
 	mock.Mock
 }
 
-// GetActiveNotifications provides a mock function with given fields: ctx
-func (_m *MockStore) GetActiveNotifications(ctx context.Context) ([]Notification, error) {
-	ret := _m.Called(ctx)
+// GetActiveNotifications provides a mock function with given fields: ctx, organizationID
+func (_m *MockStore) GetActiveNotifications(ctx context.Context, organizationID uint) ([]Notification, error) {
+	ret := _m.Called(ctx, organizationID)
 
 	var r0 []Notification
-	if rf, ok := ret.Get(0).(func(context.Context) []Notification); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []Notification); ok {
+		r0 = rf(ctx, organizationID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]Notification)
@@ -24,8 +24,8 @@ func (_m *MockStore) GetActiveNotifications(ctx context.Context) ([]Notification
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, organizationID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -16,6 +16,7 @@ package notification
 
 import (
 	"context"
+	"time"
 )
 
 // Notifications is the list of notifications active.
@@ -30,6 +31,19 @@ type Notification struct {
 	Priority int8   `json:"priority"`
 }
 
+// NewNotification contains the details of a notification raised by the application.
+type NewNotification struct {
+	Message  string
+	Priority int8
+
+	// OrganizationID restricts the notification to the members of an organization (if set).
+	OrganizationID uint
+
+	// The notification is active between InitialTime and EndTime.
+	InitialTime time.Time
+	EndTime     time.Time
+}
+
 // Service provides an interface to notifications.
 //go:generate mga gen kit endpoint --outdir notificationdriver --with-oc Service
 //go:generate mockery -name Service -inpkg
@@ -39,25 +53,37 @@ type Service interface {
 }
 
 type service struct {
-	store Store
+	store          Store
+	orgIDExtractor OrgIDContextExtractor
 }
 
 // NewService returns a new Service.
-func NewService(store Store) Service {
+func NewService(store Store, orgIDExtractor OrgIDContextExtractor) Service {
 	return &service{
-		store: store,
+		store:          store,
+		orgIDExtractor: orgIDExtractor,
 	}
 }
 
+// OrgIDContextExtractor extracts an organization ID from a context (if there is any).
+type OrgIDContextExtractor interface {
+	// GetOrganizationID extracts an organization ID from a context (if there is any).
+	GetOrganizationID(ctx context.Context) (uint, bool)
+}
+
 // Store is a data persistence layer for notifications.
 type Store interface {
 	// GetActiveNotifications returns the list of active notifications.
-	GetActiveNotifications(ctx context.Context) ([]Notification, error)
+	// Notifications of an organization are only returned when its ID is not zero.
+	GetActiveNotifications(ctx context.Context, organizationID uint) ([]Notification, error)
 }
 
 // GetActiveNotifications returns the list of active notifications.
+// Notifications of an organization are only returned when the organization is present in the context.
 func (s *service) GetNotifications(ctx context.Context) (Notifications, error) {
-	notifications, err := s.store.GetActiveNotifications(ctx)
+	organizationID, _ := s.orgIDExtractor.GetOrganizationID(ctx)
+
+	notifications, err := s.store.GetActiveNotifications(ctx, organizationID)
 	if err != nil {
 		return Notifications{}, err
 	}
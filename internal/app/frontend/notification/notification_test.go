@@ -37,9 +37,9 @@ func TestService_GetNotifications(t *testing.T) {
 		},
 	}
 
-	store.On("GetActiveNotifications", ctx).Return(notifications, nil)
+	store.On("GetActiveNotifications", ctx, uint(0)).Return(notifications, nil)
 
-	service := NewService(store)
+	service := NewService(store, noOrgIDContextExtractor{})
 
 	activeNotifications, err := service.GetNotifications(ctx)
 
@@ -54,3 +54,48 @@ func TestService_GetNotifications(t *testing.T) {
 
 	store.AssertExpectations(t)
 }
+
+func TestService_GetNotifications_Organization(t *testing.T) {
+	store := &MockStore{}
+
+	ctx := context.Background()
+
+	notifications := []Notification{
+		{
+			ID:       2,
+			Message:  "organization message",
+			Priority: 100,
+		},
+	}
+
+	store.On("GetActiveNotifications", ctx, uint(1)).Return(notifications, nil)
+
+	service := NewService(store, orgIDContextExtractor{orgID: 1})
+
+	activeNotifications, err := service.GetNotifications(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		Notifications{
+			Messages: notifications,
+		},
+		activeNotifications,
+	)
+
+	store.AssertExpectations(t)
+}
+
+type noOrgIDContextExtractor struct{}
+
+func (noOrgIDContextExtractor) GetOrganizationID(_ context.Context) (uint, bool) {
+	return 0, false
+}
+
+type orgIDContextExtractor struct {
+	orgID uint
+}
+
+func (e orgIDContextExtractor) GetOrganizationID(_ context.Context) (uint, bool) {
+	return e.orgID, true
+}
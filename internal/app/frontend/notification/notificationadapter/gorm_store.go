@@ -35,6 +35,9 @@ type notificationModel struct {
 	InitialTime time.Time `gorm:"index:idx_initial_time_end_time;default:current_timestamp;not null"`
 	EndTime     time.Time `gorm:"index:idx_initial_time_end_time;default:'1970-01-01 00:00:01';not null"`
 	Priority    int8      `gorm:"not null"`
+
+	// OrganizationID is zero for notifications shown to everyone.
+	OrganizationID uint `gorm:"index;not null;default:0"`
 }
 
 // TableName changes the default table name.
@@ -55,10 +58,13 @@ func NewGormStore(db *gorm.DB) *GormStore {
 }
 
 // GetActiveNotifications returns the list of active notifications.
-func (s *GormStore) GetActiveNotifications(ctx context.Context) ([]notification.Notification, error) {
+func (s *GormStore) GetActiveNotifications(ctx context.Context, organizationID uint) ([]notification.Notification, error) {
 	var notifications []notificationModel
 
-	err := s.db.Where("? BETWEEN initial_time AND end_time", time.Now()).Find(&notifications).Error
+	err := s.db.
+		Where("? BETWEEN initial_time AND end_time", time.Now()).
+		Where("organization_id IN (?)", []uint{0, organizationID}).
+		Find(&notifications).Error
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to find notifications")
 	}
@@ -75,3 +81,21 @@ func (s *GormStore) GetActiveNotifications(ctx context.Context) ([]notification.
 
 	return result, nil
 }
+
+// AddNotification stores a new notification.
+func (s *GormStore) AddNotification(ctx context.Context, n notification.NewNotification) error {
+	model := notificationModel{
+		Message:        n.Message,
+		InitialTime:    n.InitialTime,
+		EndTime:        n.EndTime,
+		Priority:       n.Priority,
+		OrganizationID: n.OrganizationID,
+	}
+
+	err := s.db.Create(&model).Error
+	if err != nil {
+		return errors.Wrap(err, "failed to create notification")
+	}
+
+	return nil
+}
@@ -58,9 +58,20 @@ func testGormStoreGetActiveNotifications(t *testing.T) {
 	err = db.Save(inactiveModel).Error
 	require.NoError(t, err)
 
+	organizationModel := &notificationModel{
+		Message:        message,
+		InitialTime:    time.Now().Add(-time.Hour),
+		EndTime:        time.Now().Add(time.Hour),
+		Priority:       priority,
+		OrganizationID: 1,
+	}
+
+	err = db.Save(organizationModel).Error
+	require.NoError(t, err)
+
 	store := NewGormStore(db)
 
-	notifications, err := store.GetActiveNotifications(context.Background())
+	notifications, err := store.GetActiveNotifications(context.Background(), 0)
 	require.NoError(t, err)
 
 	assert.Equal(
@@ -75,3 +86,49 @@ func testGormStoreGetActiveNotifications(t *testing.T) {
 		notifications,
 	)
 }
+
+func testGormStoreGetActiveNotificationsOfOrganization(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = Migrate(db, commonadapter.NewNoopLogger())
+	require.NoError(t, err)
+
+	store := NewGormStore(db)
+
+	err = store.AddNotification(context.Background(), notification.NewNotification{
+		Message:     "global",
+		Priority:    1,
+		InitialTime: time.Now().Add(-time.Hour),
+		EndTime:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	err = store.AddNotification(context.Background(), notification.NewNotification{
+		Message:        "organization",
+		Priority:       1,
+		OrganizationID: 1,
+		InitialTime:    time.Now().Add(-time.Hour),
+		EndTime:        time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	err = store.AddNotification(context.Background(), notification.NewNotification{
+		Message:        "other organization",
+		Priority:       1,
+		OrganizationID: 2,
+		InitialTime:    time.Now().Add(-time.Hour),
+		EndTime:        time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	notifications, err := store.GetActiveNotifications(context.Background(), 1)
+	require.NoError(t, err)
+
+	var messages []string
+	for _, n := range notifications {
+		messages = append(messages, n.Message)
+	}
+
+	assert.ElementsMatch(t, []string{"global", "organization"}, messages)
+}
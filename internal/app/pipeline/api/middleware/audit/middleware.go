@@ -17,6 +17,7 @@ package audit
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -25,7 +26,6 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jinzhu/gorm"
 	"github.com/sirupsen/logrus"
 
 	"github.com/banzaicloud/pipeline/auth"
@@ -35,11 +35,11 @@ import (
 	"github.com/banzaicloud/pipeline/spotguide"
 )
 
-// LogWriter instance is a Gin Middleware which logs all request data into MySQL audit_events table.
+// LogWriter instance is a Gin Middleware which records all request data into an audit sink.
 func LogWriter(
 	skipPaths []string,
 	whitelistedHeaders []string,
-	db *gorm.DB,
+	sink Sink,
 	logger logrus.FieldLogger,
 ) gin.HandlerFunc {
 	skip := map[string]struct{}{}
@@ -174,38 +174,58 @@ func LogWriter(
 			Headers:       string(headers),
 		}
 
-		if err := db.Save(&event).Error; err != nil {
-			_ = c.AbortWithError(http.StatusInternalServerError, err)
-			logger.Errorf("audit: failed to write request to db: %v", err)
+		// Sinks recording requests (eg. the database) must succeed before the request is processed,
+		// so that no mutating request goes unrecorded
+		if recorder, ok := sink.(RequestRecorder); ok {
+			if err := recorder.RecordRequest(&event); err != nil {
+				_ = c.AbortWithError(http.StatusInternalServerError, err)
+				logger.Errorf("audit: failed to write request: %v", err)
 
-			return
+				return
+			}
 		}
 
-		c.Next() // process request
+		// Response details are recorded in a deferred call, so that panicking handlers are captured as well
+		defer func() {
+			r := recover()
 
-		user = auth.GetCurrentUser(c.Request)
-		if user != nil {
-			userID = user.ID
-		}
+			user := auth.GetCurrentUser(c.Request)
+			if user != nil {
+				event.UserID = user.ID
+			}
 
-		responseEvent := AuditEvent{
-			UserID:       userID,
-			StatusCode:   c.Writer.Status(),
-			ResponseSize: c.Writer.Size(),
-			ResponseTime: int(time.Since(start).Nanoseconds() / 1000 / 1000), // ms
-		}
+			if org := auth.GetCurrentOrganization(c.Request); org != nil {
+				event.OrganizationID = org.ID
+			}
 
-		if c.IsAborted() {
-			if marshalled, err := json.Marshal(c.Errors); err != nil {
-				logger.Errorf("audit: failed to marshal c.Errors: %v", err)
-			} else {
-				errors := string(marshalled)
-				responseEvent.Errors = &errors
+			event.StatusCode = c.Writer.Status()
+			event.ResponseSize = c.Writer.Size()
+			event.ResponseTime = int(time.Since(start).Nanoseconds() / 1000 / 1000) // ms
+
+			if r != nil {
+				// the recovery middleware responds with this status
+				event.StatusCode = http.StatusInternalServerError
+				_ = c.Error(fmt.Errorf("panic: %v", r))
 			}
-		}
 
-		if err := db.Model(&event).Updates(responseEvent).Error; err != nil {
-			logger.Errorf("audit: failed to write response details: %v", err)
-		}
+			if c.IsAborted() || r != nil {
+				if marshalled, err := json.Marshal(c.Errors); err != nil {
+					logger.Errorf("audit: failed to marshal c.Errors: %v", err)
+				} else {
+					errors := string(marshalled)
+					event.Errors = &errors
+				}
+			}
+
+			if err := sink.Record(event); err != nil {
+				logger.Errorf("audit: failed to record event: %v", err)
+			}
+
+			if r != nil {
+				panic(r)
+			}
+		}()
+
+		c.Next() // process request
 	}
 }
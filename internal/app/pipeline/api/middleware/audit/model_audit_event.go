@@ -25,20 +25,21 @@ const (
 
 // AuditEvent holds all information related to a user interaction.
 type AuditEvent struct {
-	ID            uint      `gorm:"primary_key"`
-	Time          time.Time `gorm:"index"`
-	CorrelationID string    `gorm:"size:36"`
-	ClientIP      string    `gorm:"size:45"`
-	UserAgent     string
-	Path          string `gorm:"size:8000"`
-	Method        string `gorm:"size:7"`
-	UserID        uint
-	StatusCode    int
-	Body          *string `gorm:"type:json"`
-	Headers       string  `gorm:"type:json"`
-	ResponseTime  int
-	ResponseSize  int
-	Errors        *string `gorm:"type:json"`
+	ID             uint      `gorm:"primary_key"`
+	Time           time.Time `gorm:"index"`
+	CorrelationID  string    `gorm:"size:36"`
+	ClientIP       string    `gorm:"size:45"`
+	UserAgent      string
+	Path           string `gorm:"size:8000"`
+	Method         string `gorm:"size:7"`
+	UserID         uint
+	OrganizationID uint `gorm:"index"`
+	StatusCode     int
+	Body           *string `gorm:"type:json"`
+	Headers        string  `gorm:"type:json"`
+	ResponseTime   int
+	ResponseSize   int
+	Errors         *string `gorm:"type:json"`
 }
 
 // TableName specifies a database table name for the model.
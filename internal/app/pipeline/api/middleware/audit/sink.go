@@ -0,0 +1,273 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+// Sink records audit events.
+type Sink interface {
+	// Record records a completed audit event.
+	Record(event AuditEvent) error
+
+	// Close flushes any buffered events and releases the resources held by the sink.
+	Close() error
+}
+
+// RequestRecorder is implemented by sinks that record requests before they are processed.
+//
+// Requests that can't be recorded by such a sink are rejected,
+// and the completed event passed to Record later carries the ID set by RecordRequest.
+type RequestRecorder interface {
+	// RecordRequest records the request details of an event before the request is processed.
+	RecordRequest(event *AuditEvent) error
+}
+
+// SinkConfig contains the configuration of the audit sinks.
+// Any number of sinks can be enabled at the same time.
+type SinkConfig struct {
+	Database  DatabaseSinkConfig
+	File      FileSinkConfig
+	Webhook   WebhookSinkConfig
+	Watermill WatermillSinkConfig
+}
+
+// Validate validates the configuration.
+func (c SinkConfig) Validate() error {
+	if c.File.Enabled {
+		if c.File.Path == "" {
+			return errors.New("audit file sink path is required")
+		}
+
+		if c.File.MaxSize <= 0 {
+			return errors.New("audit file sink max size must be positive")
+		}
+	}
+
+	if c.Webhook.Enabled {
+		if u, err := url.Parse(c.Webhook.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.New("audit webhook sink URL must be a valid absolute URL")
+		}
+
+		if c.Webhook.BatchSize <= 0 {
+			return errors.New("audit webhook sink batch size must be positive")
+		}
+
+		if c.Webhook.FlushInterval <= 0 {
+			return errors.New("audit webhook sink flush interval must be positive")
+		}
+	}
+
+	if c.Watermill.Enabled && c.Watermill.Topic == "" {
+		return errors.New("audit watermill sink topic is required")
+	}
+
+	return nil
+}
+
+// DatabaseSinkConfig contains the configuration of the database sink.
+type DatabaseSinkConfig struct {
+	Enabled bool
+}
+
+// NewSink returns a sink recording events into every sink enabled in the configuration.
+func NewSink(config SinkConfig, db *gorm.DB, publisher message.Publisher, logger logrus.FieldLogger) (Sink, error) {
+	var sinks []Sink
+
+	if config.Database.Enabled {
+		sinks = append(sinks, NewDatabaseSink(db))
+	}
+
+	if config.File.Enabled {
+		sink, err := NewFileSink(config.File)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if config.Webhook.Enabled {
+		sinks = append(sinks, NewWebhookSink(config.Webhook, logger.WithField("sink", "webhook")))
+	}
+
+	if config.Watermill.Enabled {
+		sinks = append(sinks, NewWatermillSink(publisher, config.Watermill.Topic))
+	}
+
+	return NewMultiSink(sinks...), nil
+}
+
+// MultiSink records events into multiple sinks.
+type MultiSink struct {
+	sinks []Sink
+}
+
+// NewMultiSink returns a new MultiSink.
+func NewMultiSink(sinks ...Sink) MultiSink {
+	return MultiSink{
+		sinks: sinks,
+	}
+}
+
+// Record records an event into every sink.
+func (s MultiSink) Record(event AuditEvent) error {
+	var errs []error
+
+	for _, sink := range s.sinks {
+		errs = append(errs, sink.Record(event))
+	}
+
+	return errors.Combine(errs...)
+}
+
+// RecordRequest records the request details of an event into every sink recording requests.
+func (s MultiSink) RecordRequest(event *AuditEvent) error {
+	for _, sink := range s.sinks {
+		if recorder, ok := sink.(RequestRecorder); ok {
+			if err := recorder.RecordRequest(event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close closes every sink.
+func (s MultiSink) Close() error {
+	var errs []error
+
+	for _, sink := range s.sinks {
+		errs = append(errs, sink.Close())
+	}
+
+	return errors.Combine(errs...)
+}
+
+// DatabaseSink records events into the audit_events table.
+type DatabaseSink struct {
+	db *gorm.DB
+}
+
+// NewDatabaseSink returns a new DatabaseSink.
+func NewDatabaseSink(db *gorm.DB) DatabaseSink {
+	return DatabaseSink{
+		db: db,
+	}
+}
+
+// RecordRequest saves the request details of the event in the database.
+func (s DatabaseSink) RecordRequest(event *AuditEvent) error {
+	if err := s.db.Create(event).Error; err != nil {
+		return errors.WrapIf(err, "failed to write audit event to db")
+	}
+
+	return nil
+}
+
+// Record saves the event in the database.
+// If the request details are already saved, only the response details are updated.
+func (s DatabaseSink) Record(event AuditEvent) error {
+	if event.ID == 0 {
+		if err := s.db.Create(&event).Error; err != nil {
+			return errors.WrapIf(err, "failed to write audit event to db")
+		}
+
+		return nil
+	}
+
+	responseEvent := AuditEvent{
+		UserID:         event.UserID,
+		OrganizationID: event.OrganizationID,
+		StatusCode:     event.StatusCode,
+		ResponseSize:   event.ResponseSize,
+		ResponseTime:   event.ResponseTime,
+		Errors:         event.Errors,
+	}
+
+	if err := s.db.Model(&AuditEvent{ID: event.ID}).Updates(responseEvent).Error; err != nil {
+		return errors.WrapIf(err, "failed to write response details to db")
+	}
+
+	return nil
+}
+
+// Close implements the Sink interface.
+func (DatabaseSink) Close() error {
+	return nil
+}
+
+// eventPayload is the external representation of audit events.
+type eventPayload struct {
+	Time           time.Time       `json:"time"`
+	CorrelationID  string          `json:"correlationId,omitempty"`
+	ClientIP       string          `json:"clientIp"`
+	UserAgent      string          `json:"userAgent"`
+	Path           string          `json:"path"`
+	Method         string          `json:"method"`
+	UserID         uint            `json:"userId"`
+	OrganizationID uint            `json:"organizationId,omitempty"`
+	StatusCode     int             `json:"statusCode"`
+	Body           json.RawMessage `json:"body,omitempty"`
+	Headers        json.RawMessage `json:"headers,omitempty"`
+	ResponseTime   int             `json:"responseTime"`
+	ResponseSize   int             `json:"responseSize"`
+	Errors         json.RawMessage `json:"errors,omitempty"`
+}
+
+// marshalEvent encodes an event as JSON for external sinks.
+func marshalEvent(event AuditEvent) ([]byte, error) {
+	payload := eventPayload{
+		Time:           event.Time,
+		CorrelationID:  event.CorrelationID,
+		ClientIP:       event.ClientIP,
+		UserAgent:      event.UserAgent,
+		Path:           event.Path,
+		Method:         event.Method,
+		UserID:         event.UserID,
+		OrganizationID: event.OrganizationID,
+		StatusCode:     event.StatusCode,
+		ResponseTime:   event.ResponseTime,
+		ResponseSize:   event.ResponseSize,
+	}
+
+	if event.Body != nil && *event.Body != "" {
+		payload.Body = json.RawMessage(*event.Body)
+	}
+
+	if event.Headers != "" {
+		payload.Headers = json.RawMessage(event.Headers)
+	}
+
+	if event.Errors != nil && *event.Errors != "" {
+		payload.Errors = json.RawMessage(*event.Errors)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to marshal audit event")
+	}
+
+	return data, nil
+}
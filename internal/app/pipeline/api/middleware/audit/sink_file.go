@@ -0,0 +1,161 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"emperror.dev/errors"
+)
+
+// FileSinkConfig contains the configuration of the file sink.
+type FileSinkConfig struct {
+	Enabled bool
+
+	// Path of the log file
+	Path string
+
+	// MaxSize is the size of the log file in megabytes that triggers a rotation
+	MaxSize int
+
+	// MaxBackups is the number of rotated log files to keep
+	MaxBackups int
+}
+
+// FileSink writes events into a local file as JSON lines and rotates the file when it grows too large.
+type FileSink struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewFileSink returns a new FileSink.
+func NewFileSink(config FileSinkConfig) (*FileSink, error) {
+	sink := &FileSink{
+		path:       config.Path,
+		maxSize:    int64(config.MaxSize) * 1024 * 1024,
+		maxBackups: config.MaxBackups,
+	}
+
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0755); err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to create audit log directory", "path", config.Path)
+	}
+
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to open audit log file", "path", s.path)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return errors.WrapIfWithDetails(err, "failed to stat audit log file", "path", s.path)
+	}
+
+	s.file = file
+	s.size = info.Size()
+
+	return nil
+}
+
+func (s *FileSink) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", s.path, n)
+}
+
+// rotate moves the current log file to the first backup and opens a new one.
+func (s *FileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to close audit log file", "path", s.path)
+	}
+
+	if s.maxBackups > 0 {
+		for n := s.maxBackups - 1; n > 0; n-- {
+			err := os.Rename(s.backupPath(n), s.backupPath(n+1))
+			if err != nil && !os.IsNotExist(err) {
+				return errors.WrapIfWithDetails(err, "failed to rotate audit log file", "path", s.path)
+			}
+		}
+
+		if err := os.Rename(s.path, s.backupPath(1)); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to rotate audit log file", "path", s.path)
+		}
+	} else if err := os.Remove(s.path); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to remove audit log file", "path", s.path)
+	}
+
+	return s.open()
+}
+
+// Record appends the event to the log file.
+func (s *FileSink) Record(event AuditEvent) error {
+	data, err := marshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	data = append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return errors.NewWithDetails("audit log file is closed", "path", s.path)
+	}
+
+	if s.size > 0 && s.size+int64(len(data)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(data)
+	s.size += int64(n)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to write audit log file", "path", s.path)
+	}
+
+	return nil
+}
+
+// Close closes the log file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return errors.WrapIfWithDetails(err, "failed to close audit log file", "path", s.path)
+}
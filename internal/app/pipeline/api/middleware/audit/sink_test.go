@@ -0,0 +1,306 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(path string) AuditEvent {
+	body := `{"name":"cluster"}`
+
+	return AuditEvent{
+		Time:           time.Date(2019, time.November, 8, 10, 0, 0, 0, time.UTC),
+		CorrelationID:  "abc",
+		Path:           path,
+		Method:         http.MethodPost,
+		UserID:         1,
+		OrganizationID: 2,
+		StatusCode:     http.StatusAccepted,
+		Body:           &body,
+		Headers:        "{}",
+	}
+}
+
+type sinkStub struct {
+	events []AuditEvent
+	closed bool
+}
+
+func (s *sinkStub) Record(event AuditEvent) error {
+	s.events = append(s.events, event)
+
+	return nil
+}
+
+func (s *sinkStub) Close() error {
+	s.closed = true
+
+	return nil
+}
+
+func TestMultiSink(t *testing.T) {
+	sink1 := &sinkStub{}
+	sink2 := &sinkStub{}
+
+	sink := NewMultiSink(sink1, sink2)
+
+	event := testEvent("/api/v1/orgs/2/clusters")
+
+	err := sink.Record(event)
+	require.NoError(t, err)
+
+	err = sink.Close()
+	require.NoError(t, err)
+
+	assert.Equal(t, []AuditEvent{event}, sink1.events)
+	assert.Equal(t, []AuditEvent{event}, sink2.events)
+	assert.True(t, sink1.closed)
+	assert.True(t, sink2.closed)
+}
+
+type requestRecorderStub struct {
+	sinkStub
+	requests []AuditEvent
+	err      error
+}
+
+func (s *requestRecorderStub) RecordRequest(event *AuditEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	event.ID = uint(len(s.requests) + 1)
+	s.requests = append(s.requests, *event)
+
+	return nil
+}
+
+func TestMultiSink_RecordRequest(t *testing.T) {
+	recorder := &requestRecorderStub{}
+	sink := NewMultiSink(&sinkStub{}, recorder)
+
+	event := testEvent("/api/v1/orgs/2/clusters")
+
+	err := sink.RecordRequest(&event)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint(1), event.ID)
+	assert.Equal(t, []AuditEvent{event}, recorder.requests)
+}
+
+func TestMultiSink_RecordRequest_Error(t *testing.T) {
+	sink := NewMultiSink(&sinkStub{}, &requestRecorderStub{err: errors.New("db is down")})
+
+	event := testEvent("/api/v1/orgs/2/clusters")
+
+	err := sink.RecordRequest(&event)
+	require.Error(t, err)
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "audit.log")
+
+	sink, err := NewFileSink(FileSinkConfig{Path: path, MaxSize: 1, MaxBackups: 2})
+	require.NoError(t, err)
+
+	// Force a rotation after every event
+	sink.maxSize = 1
+
+	for _, p := range []string{"/first", "/second", "/third", "/fourth"} {
+		err := sink.Record(testEvent(p))
+		require.NoError(t, err)
+	}
+
+	err = sink.Close()
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		path:        "/fourth",
+		path + ".1": "/third",
+		path + ".2": "/second",
+	}
+
+	for file, eventPath := range expected {
+		f, err := os.Open(file)
+		require.NoError(t, err)
+
+		scanner := bufio.NewScanner(f)
+		require.True(t, scanner.Scan())
+
+		var payload eventPayload
+
+		err = json.Unmarshal(scanner.Bytes(), &payload)
+		require.NoError(t, err)
+
+		assert.Equal(t, eventPath, payload.Path)
+		assert.JSONEq(t, `{"name":"cluster"}`, string(payload.Body))
+		assert.False(t, scanner.Scan())
+
+		_ = f.Close()
+	}
+
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestWebhookSink(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]eventPayload
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		requests++
+
+		// Fail the first request to test retries
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		var batch []eventPayload
+
+		err := json.NewDecoder(r.Body).Decode(&batch)
+		require.NoError(t, err)
+
+		batches = append(batches, batch)
+	}))
+	defer server.Close()
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	sink := NewWebhookSink(
+		WebhookSinkConfig{
+			URL:           server.URL,
+			Headers:       map[string]string{"Authorization": "Bearer token"},
+			BatchSize:     2,
+			FlushInterval: time.Hour,
+			MaxRetries:    1,
+			RetryInterval: time.Millisecond,
+			Timeout:       time.Second,
+		},
+		logger,
+	)
+
+	for _, p := range []string{"/first", "/second", "/third"} {
+		err := sink.Record(testEvent(p))
+		require.NoError(t, err)
+	}
+
+	err := sink.Close()
+	require.NoError(t, err)
+
+	err = sink.Record(testEvent("/closed"))
+	assert.Error(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Equal(t, 3, requests)
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0], 2)
+	require.Len(t, batches[1], 1)
+	assert.Equal(t, "/first", batches[0][0].Path)
+	assert.Equal(t, "/third", batches[1][0].Path)
+}
+
+type publisherStub struct {
+	topic    string
+	messages []*message.Message
+}
+
+func (p *publisherStub) Publish(topic string, messages ...*message.Message) error {
+	p.topic = topic
+	p.messages = append(p.messages, messages...)
+
+	return nil
+}
+
+func (p *publisherStub) Close() error {
+	return nil
+}
+
+func TestWatermillSink(t *testing.T) {
+	publisher := &publisherStub{}
+
+	sink := NewWatermillSink(publisher, "audit")
+
+	err := sink.Record(testEvent("/api/v1/orgs/2/clusters"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "audit", publisher.topic)
+	require.Len(t, publisher.messages, 1)
+	assert.Equal(t, "abc", middleware.MessageCorrelationID(publisher.messages[0]))
+
+	var payload eventPayload
+
+	err = json.Unmarshal(publisher.messages[0].Payload, &payload)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/api/v1/orgs/2/clusters", payload.Path)
+	assert.Equal(t, uint(2), payload.OrganizationID)
+}
+
+func TestSinkConfig_Validate(t *testing.T) {
+	tests := map[string]SinkConfig{
+		"file without path": {
+			File: FileSinkConfig{Enabled: true, MaxSize: 100},
+		},
+		"webhook without URL": {
+			Webhook: WebhookSinkConfig{Enabled: true, BatchSize: 1, FlushInterval: time.Second},
+		},
+		"webhook without batch size": {
+			Webhook: WebhookSinkConfig{Enabled: true, URL: "https://example.com", FlushInterval: time.Second},
+		},
+		"watermill without topic": {
+			Watermill: WatermillSinkConfig{Enabled: true},
+		},
+	}
+
+	for name, config := range tests {
+		name, config := name, config
+
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, config.Validate())
+		})
+	}
+}
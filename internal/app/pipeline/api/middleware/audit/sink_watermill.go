@@ -0,0 +1,69 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"emperror.dev/errors"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
+)
+
+// WatermillSinkConfig contains the configuration of the watermill sink.
+type WatermillSinkConfig struct {
+	Enabled bool
+	Topic   string
+}
+
+// WatermillSink publishes events as JSON messages to a watermill topic.
+// Events can be streamed to any message broker supported by watermill (eg. Kafka).
+type WatermillSink struct {
+	publisher message.Publisher
+	topic     string
+}
+
+// NewWatermillSink returns a new WatermillSink.
+func NewWatermillSink(publisher message.Publisher, topic string) WatermillSink {
+	return WatermillSink{
+		publisher: publisher,
+		topic:     topic,
+	}
+}
+
+// Record publishes the event.
+func (s WatermillSink) Record(event AuditEvent) error {
+	payload, err := marshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+
+	if event.CorrelationID != "" {
+		middleware.SetCorrelationID(event.CorrelationID, msg)
+	}
+
+	if err := s.publisher.Publish(s.topic, msg); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to publish audit event", "topic", s.topic)
+	}
+
+	return nil
+}
+
+// Close implements the Sink interface.
+// The publisher is not closed as it is owned by the caller.
+func (WatermillSink) Close() error {
+	return nil
+}
@@ -0,0 +1,210 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookSinkConfig contains the configuration of the webhook sink.
+type WebhookSinkConfig struct {
+	Enabled bool
+
+	// URL events are posted to
+	URL string
+
+	// Headers added to every request (eg. Authorization)
+	Headers map[string]string
+
+	// BatchSize is the maximum number of events sent in a single request
+	BatchSize int
+
+	// FlushInterval is the maximum time events are buffered before sending them
+	FlushInterval time.Duration
+
+	// MaxRetries is the number of times a failed request is retried
+	MaxRetries int
+
+	// RetryInterval is the initial wait time between retries (doubled after every attempt)
+	RetryInterval time.Duration
+
+	// Timeout of a single request
+	Timeout time.Duration
+}
+
+// webhookBufferBatches is the number of batches the webhook sink buffers before dropping events.
+const webhookBufferBatches = 10
+
+// WebhookSink posts events in batches (as a JSON array) to an HTTP endpoint.
+// Failed requests are retried with exponential backoff.
+type WebhookSink struct {
+	config WebhookSinkConfig
+	client *http.Client
+	logger logrus.FieldLogger
+
+	mu     sync.RWMutex
+	closed bool
+	events chan json.RawMessage
+	done   chan struct{}
+}
+
+// NewWebhookSink returns a new WebhookSink and starts sending events in the background.
+func NewWebhookSink(config WebhookSinkConfig, logger logrus.FieldLogger) *WebhookSink {
+	sink := &WebhookSink{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		logger: logger,
+		events: make(chan json.RawMessage, config.BatchSize*webhookBufferBatches),
+		done:   make(chan struct{}),
+	}
+
+	go sink.run()
+
+	return sink
+}
+
+// Record queues the event for sending.
+// The event is dropped if the buffer is full (eg. the endpoint has been unavailable for a while).
+func (s *WebhookSink) Record(event AuditEvent) error {
+	payload, err := marshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return errors.New("audit webhook sink is closed")
+	}
+
+	select {
+	case s.events <- payload:
+		return nil
+	default:
+		return errors.NewWithDetails("audit webhook buffer is full, dropping event", "url", s.config.URL)
+	}
+}
+
+// Close sends the buffered events and stops the sink.
+func (s *WebhookSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.events)
+	}
+	s.mu.Unlock()
+
+	<-s.done
+
+	return nil
+}
+
+func (s *WebhookSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]json.RawMessage, 0, s.config.BatchSize)
+
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				s.flush(batch)
+
+				return
+			}
+
+			batch = append(batch, event)
+
+			if len(batch) >= s.config.BatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+
+		case <-ticker.C:
+			s.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (s *WebhookSink) flush(batch []json.RawMessage) {
+	if len(batch) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(batch)
+	if err != nil {
+		s.logger.Errorf("audit: failed to marshal event batch: %v", err)
+
+		return
+	}
+
+	wait := s.config.RetryInterval
+
+	for attempt := 0; ; attempt++ {
+		err = s.send(body)
+		if err == nil {
+			return
+		}
+
+		if attempt >= s.config.MaxRetries {
+			break
+		}
+
+		time.Sleep(wait)
+		wait *= 2
+	}
+
+	s.logger.WithFields(logrus.Fields{
+		"url":    s.config.URL,
+		"events": len(batch),
+	}).Errorf("audit: failed to send events to webhook: %v", err)
+}
+
+func (s *WebhookSink) send(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.WrapIf(err, "failed to create webhook request")
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	for name, value := range s.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.WrapIf(err, "failed to send webhook request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return errors.NewWithDetails("unexpected webhook response", "statusCode", resp.StatusCode)
+	}
+
+	return nil
+}
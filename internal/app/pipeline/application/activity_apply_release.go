@@ -0,0 +1,57 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+)
+
+const ApplyReleaseActivityName = "application-apply-release"
+
+type ApplyReleaseActivity struct {
+	releases Releases
+}
+
+// NewApplyReleaseActivity returns a new ApplyReleaseActivity.
+func NewApplyReleaseActivity(releases Releases) ApplyReleaseActivity {
+	return ApplyReleaseActivity{
+		releases: releases,
+	}
+}
+
+type ApplyReleaseActivityInput struct {
+	ClusterID uint
+	Release   PlannedRelease
+}
+
+type ApplyReleaseActivityOutput struct {
+	// Revision is the deployed revision of the release.
+	Revision int32
+}
+
+// Execute installs or upgrades a release and waits until it becomes ready.
+func (a ApplyReleaseActivity) Execute(ctx context.Context, input ApplyReleaseActivityInput) (ApplyReleaseActivityOutput, error) {
+	activity.GetLogger(ctx).Sugar().With("clusterId", input.ClusterID, "release", input.Release.Name).Info("applying release")
+
+	revision, err := a.releases.Apply(ctx, input.ClusterID, input.Release, ReleaseTimeout)
+	if err != nil {
+		return ApplyReleaseActivityOutput{}, errors.WrapIfWithDetails(err, "failed to apply release", "release", input.Release.Name)
+	}
+
+	return ApplyReleaseActivityOutput{Revision: revision}, nil
+}
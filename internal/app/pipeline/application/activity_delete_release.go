@@ -0,0 +1,51 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+)
+
+const DeleteReleaseActivityName = "application-delete-release"
+
+type DeleteReleaseActivity struct {
+	releases Releases
+}
+
+// NewDeleteReleaseActivity returns a new DeleteReleaseActivity.
+func NewDeleteReleaseActivity(releases Releases) DeleteReleaseActivity {
+	return DeleteReleaseActivity{
+		releases: releases,
+	}
+}
+
+type DeleteReleaseActivityInput struct {
+	ClusterID   uint
+	ReleaseName string
+}
+
+// Execute deletes a release.
+func (a DeleteReleaseActivity) Execute(ctx context.Context, input DeleteReleaseActivityInput) error {
+	activity.GetLogger(ctx).Sugar().With("clusterId", input.ClusterID, "release", input.ReleaseName).Info("deleting release")
+
+	if err := a.releases.Delete(ctx, input.ClusterID, input.ReleaseName); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete release", "release", input.ReleaseName)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const PlanActivityName = "application-plan"
+
+// PlannedRelease is a release of an application with its values rendered.
+type PlannedRelease struct {
+	Name      string
+	Chart     string
+	Version   string
+	Namespace string
+	Values    []byte
+
+	// Revision is the deployed revision before the reconciliation (0 if the release is not deployed).
+	// The release is rolled back to this revision if the reconciliation fails.
+	Revision int32
+}
+
+type PlanActivity struct {
+	store    Store
+	releases Releases
+}
+
+// NewPlanActivity returns a new PlanActivity.
+func NewPlanActivity(store Store, releases Releases) PlanActivity {
+	return PlanActivity{
+		store:    store,
+		releases: releases,
+	}
+}
+
+type PlanActivityInput struct {
+	ClusterID uint
+	Name      string
+
+	// Deletion skips rendering the values and checking the live state of the releases.
+	Deletion bool
+}
+
+type PlanActivityOutput struct {
+	// Releases are the declared releases in installation order.
+	Releases []PlannedRelease
+
+	// Removed are the releases installed by a previous reconciliation which are not declared any more.
+	Removed []ReleaseStatus
+}
+
+// Execute computes the steps of reconciling or deleting an application.
+func (a PlanActivity) Execute(ctx context.Context, input PlanActivityInput) (PlanActivityOutput, error) {
+	app, err := a.store.Get(ctx, input.ClusterID, input.Name)
+	if err != nil {
+		return PlanActivityOutput{}, err
+	}
+
+	releases, err := SortReleases(app.Spec.Releases)
+	if err != nil {
+		return PlanActivityOutput{}, err
+	}
+
+	var output PlanActivityOutput
+
+	declared := make(map[string]bool, len(releases))
+
+	for _, release := range releases {
+		declared[release.Name] = true
+
+		planned := PlannedRelease{
+			Name:      release.Name,
+			Chart:     release.Chart,
+			Version:   release.Version,
+			Namespace: ReleaseNamespace(app.Spec, release),
+		}
+
+		if !input.Deletion {
+			values, err := RenderValues(app.Name, app.Spec, release)
+			if err != nil {
+				return PlanActivityOutput{}, err
+			}
+
+			planned.Values, err = yaml.Marshal(values)
+			if err != nil {
+				return PlanActivityOutput{}, errors.WrapIfWithDetails(err, "failed to marshal values", "release", release.Name)
+			}
+
+			info, err := a.releases.Get(ctx, input.ClusterID, release.Name)
+			if err != nil {
+				return PlanActivityOutput{}, err
+			}
+
+			if info.Installed && info.Status == ReleaseStatusDeployed {
+				planned.Revision = info.Revision
+			}
+		}
+
+		output.Releases = append(output.Releases, planned)
+	}
+
+	for _, status := range app.Releases {
+		if !declared[status.Name] {
+			output.Removed = append(output.Removed, status)
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,62 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+)
+
+const RecordStatusActivityName = "application-record-status"
+
+type RecordStatusActivity struct {
+	store Store
+}
+
+// NewRecordStatusActivity returns a new RecordStatusActivity.
+func NewRecordStatusActivity(store Store) RecordStatusActivity {
+	return RecordStatusActivity{
+		store: store,
+	}
+}
+
+type RecordStatusActivityInput struct {
+	ClusterID uint
+	Name      string
+	Status    string
+	Message   string
+
+	// Releases keeps the state of the releases recorded previously when nil.
+	Releases []ReleaseStatus
+}
+
+// Execute records the result of a reconciliation.
+func (a RecordStatusActivity) Execute(ctx context.Context, input RecordStatusActivityInput) error {
+	releases := input.Releases
+
+	if releases == nil {
+		app, err := a.store.Get(ctx, input.ClusterID, input.Name)
+		if errors.As(err, &NotFoundError{}) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		releases = app.Releases
+	}
+
+	return a.store.UpdateStatus(ctx, input.ClusterID, input.Name, input.Status, input.Message, releases)
+}
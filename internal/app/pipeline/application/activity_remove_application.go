@@ -0,0 +1,42 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+)
+
+const RemoveApplicationActivityName = "application-remove-application"
+
+type RemoveApplicationActivity struct {
+	store Store
+}
+
+// NewRemoveApplicationActivity returns a new RemoveApplicationActivity.
+func NewRemoveApplicationActivity(store Store) RemoveApplicationActivity {
+	return RemoveApplicationActivity{
+		store: store,
+	}
+}
+
+type RemoveApplicationActivityInput struct {
+	ClusterID uint
+	Name      string
+}
+
+// Execute removes an application after its releases have been deleted.
+func (a RemoveApplicationActivity) Execute(ctx context.Context, input RemoveApplicationActivityInput) error {
+	return a.store.Delete(ctx, input.ClusterID, input.Name)
+}
@@ -0,0 +1,62 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+)
+
+const RollbackReleaseActivityName = "application-rollback-release"
+
+type RollbackReleaseActivity struct {
+	releases Releases
+}
+
+// NewRollbackReleaseActivity returns a new RollbackReleaseActivity.
+func NewRollbackReleaseActivity(releases Releases) RollbackReleaseActivity {
+	return RollbackReleaseActivity{
+		releases: releases,
+	}
+}
+
+type RollbackReleaseActivityInput struct {
+	ClusterID   uint
+	ReleaseName string
+
+	// Revision is the revision to restore: releases without a deployed revision are deleted.
+	Revision int32
+}
+
+// Execute restores the state of a release before the reconciliation.
+func (a RollbackReleaseActivity) Execute(ctx context.Context, input RollbackReleaseActivityInput) error {
+	activity.GetLogger(ctx).Sugar().With("clusterId", input.ClusterID, "release", input.ReleaseName, "revision", input.Revision).Info("rolling back release")
+
+	if input.Revision == 0 {
+		if err := a.releases.Delete(ctx, input.ClusterID, input.ReleaseName); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to delete release", "release", input.ReleaseName)
+		}
+
+		return nil
+	}
+
+	if err := a.releases.Rollback(ctx, input.ClusterID, input.ReleaseName, input.Revision); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to roll back release", "release", input.ReleaseName, "revision", input.Revision)
+	}
+
+	return nil
+}
@@ -0,0 +1,569 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Application statuses
+const (
+	StatusReconciling = "RECONCILING"
+	StatusReady       = "READY"
+	StatusFailed      = "FAILED"
+	StatusDeleting    = "DELETING"
+)
+
+// Release statuses reported by the latest reconciliation
+const (
+	ReleaseStatusPending    = "PENDING"
+	ReleaseStatusDeployed   = "DEPLOYED"
+	ReleaseStatusFailed     = "FAILED"
+	ReleaseStatusRolledBack = "ROLLED_BACK"
+	ReleaseStatusSkipped    = "SKIPPED"
+)
+
+// Aggregate health values
+const (
+	HealthHealthy  = "HEALTHY"
+	HealthDegraded = "DEGRADED"
+)
+
+// ReleaseTimeout is the time a release has to become ready before its dependents are installed.
+const ReleaseTimeout = 5 * time.Minute
+
+// Application is a set of related Helm releases managed together on a cluster.
+type Application struct {
+	ClusterID uint
+	Name      string
+	Spec      Spec
+
+	Status        string
+	StatusMessage string
+
+	// Releases holds the state of the releases after the latest reconciliation.
+	Releases []ReleaseStatus
+}
+
+// Spec is the declared state of an application.
+type Spec struct {
+	// Namespace is the default namespace of the releases.
+	Namespace string
+
+	// Values are shared between the releases: release values can refer to them as {{ .Values.key }}.
+	Values map[string]interface{}
+
+	Releases []Release
+}
+
+// Release is a member of an application.
+type Release struct {
+	Name      string
+	Chart     string
+	Version   string
+	Namespace string
+	Values    map[string]interface{}
+
+	// DependsOn lists the releases that have to be ready before this release is installed.
+	DependsOn []string
+}
+
+// ReleaseStatus is the state of a release after a reconciliation.
+type ReleaseStatus struct {
+	Name     string
+	Status   string
+	Revision int32
+	Message  string
+}
+
+// Health is the aggregate health of the releases of an application.
+type Health struct {
+	Status   string
+	Releases []ReleaseHealth
+}
+
+// ReleaseHealth is the live state of a release.
+type ReleaseHealth struct {
+	Name     string
+	Status   string
+	Revision int32
+}
+
+// ReleaseInfo is the live state of a release returned by Releases.
+type ReleaseInfo struct {
+	Installed bool
+	Revision  int32
+	Status    string
+}
+
+// nolint: gochecknoglobals
+var namePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+//go:generate mga gen kit endpoint --outdir applicationdriver --with-oc Service
+//go:generate mockery -name Service -inpkg
+type Service interface {
+	// ListApplications returns the applications of a cluster.
+	ListApplications(ctx context.Context, clusterID uint) ([]Application, error)
+
+	// GetApplication returns an application.
+	GetApplication(ctx context.Context, clusterID uint, name string) (Application, error)
+
+	// CreateApplication creates an application and starts installing its releases.
+	CreateApplication(ctx context.Context, clusterID uint, name string, spec Spec) (Application, error)
+
+	// UpdateApplication updates the declared state of an application and starts reconciling its releases.
+	UpdateApplication(ctx context.Context, clusterID uint, name string, spec Spec) (Application, error)
+
+	// DeleteApplication starts deleting the releases of an application.
+	DeleteApplication(ctx context.Context, clusterID uint, name string) error
+
+	// ReconcileApplication starts reconciling the releases of an application with its declared state.
+	ReconcileApplication(ctx context.Context, clusterID uint, name string) error
+
+	// GetApplicationHealth returns the aggregate health of the releases of an application.
+	GetApplicationHealth(ctx context.Context, clusterID uint, name string) (Health, error)
+}
+
+// NewService returns a new Service.
+func NewService(store Store, reconciler Reconciler, releases Releases) Service {
+	return service{
+		store:      store,
+		reconciler: reconciler,
+		releases:   releases,
+	}
+}
+
+type service struct {
+	store      Store
+	reconciler Reconciler
+	releases   Releases
+}
+
+// Store persists applications.
+type Store interface {
+	// List returns the applications of a cluster.
+	List(ctx context.Context, clusterID uint) ([]Application, error)
+
+	// Get returns an application.
+	// It returns a NotFoundError if the application cannot be found.
+	Get(ctx context.Context, clusterID uint, name string) (Application, error)
+
+	// Create stores a new application.
+	// It returns an AlreadyExistsError if the application exists already.
+	Create(ctx context.Context, app Application) error
+
+	// Update updates the declared state and the status of an application.
+	Update(ctx context.Context, app Application) error
+
+	// UpdateStatus records the result of a reconciliation.
+	UpdateStatus(ctx context.Context, clusterID uint, name string, status string, message string, releases []ReleaseStatus) error
+
+	// Delete deletes an application.
+	Delete(ctx context.Context, clusterID uint, name string) error
+}
+
+// Reconciler reconciles the releases of applications in the background.
+type Reconciler interface {
+	// Reconcile starts reconciling the releases of an application with its declared state.
+	// It returns a BusyError if the application is being reconciled or deleted already.
+	Reconcile(ctx context.Context, clusterID uint, name string) error
+
+	// Delete starts deleting the releases of an application.
+	// It returns a BusyError if the application is being reconciled or deleted already.
+	Delete(ctx context.Context, clusterID uint, name string) error
+}
+
+// Releases manages Helm releases on clusters.
+type Releases interface {
+	// Get returns the live state of a release.
+	Get(ctx context.Context, clusterID uint, name string) (ReleaseInfo, error)
+
+	// Apply installs or upgrades a release and waits until it becomes ready.
+	// It returns the deployed revision.
+	Apply(ctx context.Context, clusterID uint, release PlannedRelease, timeout time.Duration) (int32, error)
+
+	// Rollback rolls back a release to a previous revision.
+	Rollback(ctx context.Context, clusterID uint, name string, revision int32) error
+
+	// Delete deletes a release. Deleting a missing release is not an error.
+	Delete(ctx context.Context, clusterID uint, name string) error
+}
+
+func (s service) ListApplications(ctx context.Context, clusterID uint) ([]Application, error) {
+	return s.store.List(ctx, clusterID)
+}
+
+func (s service) GetApplication(ctx context.Context, clusterID uint, name string) (Application, error) {
+	return s.store.Get(ctx, clusterID, name)
+}
+
+func (s service) CreateApplication(ctx context.Context, clusterID uint, name string, spec Spec) (Application, error) {
+	if !namePattern.MatchString(name) {
+		return Application{}, ValidationError{Message: fmt.Sprintf("invalid application name: %q", name)}
+	}
+
+	if err := s.validate(ctx, clusterID, name, spec); err != nil {
+		return Application{}, err
+	}
+
+	app := Application{
+		ClusterID: clusterID,
+		Name:      name,
+		Spec:      spec,
+		Status:    StatusReconciling,
+	}
+
+	if err := s.store.Create(ctx, app); err != nil {
+		return Application{}, err
+	}
+
+	if err := s.reconcile(ctx, app); err != nil {
+		return Application{}, err
+	}
+
+	return app, nil
+}
+
+func (s service) UpdateApplication(ctx context.Context, clusterID uint, name string, spec Spec) (Application, error) {
+	app, err := s.store.Get(ctx, clusterID, name)
+	if err != nil {
+		return Application{}, err
+	}
+
+	if err := checkIdle(app); err != nil {
+		return Application{}, err
+	}
+
+	if err := s.validate(ctx, clusterID, name, spec); err != nil {
+		return Application{}, err
+	}
+
+	app.Spec = spec
+	app.Status = StatusReconciling
+	app.StatusMessage = ""
+
+	if err := s.store.Update(ctx, app); err != nil {
+		return Application{}, err
+	}
+
+	if err := s.reconcile(ctx, app); err != nil {
+		return Application{}, err
+	}
+
+	return app, nil
+}
+
+func (s service) DeleteApplication(ctx context.Context, clusterID uint, name string) error {
+	app, err := s.store.Get(ctx, clusterID, name)
+	if err != nil {
+		return err
+	}
+
+	if app.Status == StatusReconciling {
+		return BusyError{ClusterID: clusterID, Name: name}
+	}
+
+	if err := s.store.UpdateStatus(ctx, clusterID, name, StatusDeleting, "", app.Releases); err != nil {
+		return err
+	}
+
+	err = s.reconciler.Delete(ctx, clusterID, name)
+	if err != nil && !errors.As(err, &BusyError{}) {
+		statusErr := s.store.UpdateStatus(ctx, clusterID, name, StatusFailed, "failed to start deletion", app.Releases)
+
+		return errors.Combine(err, statusErr)
+	}
+
+	return err
+}
+
+func (s service) ReconcileApplication(ctx context.Context, clusterID uint, name string) error {
+	app, err := s.store.Get(ctx, clusterID, name)
+	if err != nil {
+		return err
+	}
+
+	// A running reconciliation is detected by the reconciler,
+	// so that a stale status never prevents reconciling an application.
+	if app.Status == StatusDeleting {
+		return BusyError{ClusterID: clusterID, Name: name}
+	}
+
+	if err := s.store.UpdateStatus(ctx, clusterID, name, StatusReconciling, "", app.Releases); err != nil {
+		return err
+	}
+
+	app.Status = StatusReconciling
+
+	return s.reconcile(ctx, app)
+}
+
+func (s service) GetApplicationHealth(ctx context.Context, clusterID uint, name string) (Health, error) {
+	app, err := s.store.Get(ctx, clusterID, name)
+	if err != nil {
+		return Health{}, err
+	}
+
+	health := Health{
+		Status:   HealthHealthy,
+		Releases: make([]ReleaseHealth, 0, len(app.Spec.Releases)),
+	}
+
+	for _, release := range app.Spec.Releases {
+		info, err := s.releases.Get(ctx, clusterID, release.Name)
+		if err != nil {
+			return Health{}, errors.WrapIfWithDetails(err, "failed to get release", "release", release.Name)
+		}
+
+		releaseHealth := ReleaseHealth{
+			Name:     release.Name,
+			Status:   info.Status,
+			Revision: info.Revision,
+		}
+
+		if !info.Installed {
+			releaseHealth.Status = "NOT_INSTALLED"
+		}
+
+		if releaseHealth.Status != ReleaseStatusDeployed {
+			health.Status = HealthDegraded
+		}
+
+		health.Releases = append(health.Releases, releaseHealth)
+	}
+
+	return health, nil
+}
+
+func (s service) reconcile(ctx context.Context, app Application) error {
+	err := s.reconciler.Reconcile(ctx, app.ClusterID, app.Name)
+	if err != nil && !errors.As(err, &BusyError{}) {
+		// The application would be stuck in the reconciling status otherwise
+		statusErr := s.store.UpdateStatus(ctx, app.ClusterID, app.Name, StatusFailed, "failed to start reconciliation", app.Releases)
+
+		return errors.Combine(err, statusErr)
+	}
+
+	return err
+}
+
+// validate checks the declared state of an application:
+// release names must be unique on the cluster, dependencies must exist and be acyclic,
+// and the value templates must render.
+func (s service) validate(ctx context.Context, clusterID uint, name string, spec Spec) error {
+	if len(spec.Releases) == 0 {
+		return ValidationError{Message: "an application must have at least one release"}
+	}
+
+	if _, err := SortReleases(spec.Releases); err != nil {
+		return err
+	}
+
+	for _, release := range spec.Releases {
+		if _, err := RenderValues(name, spec, release); err != nil {
+			return err
+		}
+	}
+
+	apps, err := s.store.List(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	for _, app := range apps {
+		if app.Name == name {
+			continue
+		}
+
+		for _, other := range app.Spec.Releases {
+			for _, release := range spec.Releases {
+				if release.Name == other.Name {
+					return ValidationError{Message: fmt.Sprintf("release %q is managed by application %q", release.Name, app.Name)}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkIdle(app Application) error {
+	switch app.Status {
+	case StatusReconciling, StatusDeleting:
+		return BusyError{ClusterID: app.ClusterID, Name: app.Name}
+	}
+
+	return nil
+}
+
+// SortReleases returns the releases of an application in installation order:
+// every release comes after its dependencies, otherwise the declaration order is kept.
+func SortReleases(releases []Release) ([]Release, error) {
+	index := make(map[string]int, len(releases))
+
+	for i, release := range releases {
+		if !namePattern.MatchString(release.Name) {
+			return nil, ValidationError{Message: fmt.Sprintf("invalid release name: %q", release.Name)}
+		}
+
+		if release.Chart == "" {
+			return nil, ValidationError{Message: fmt.Sprintf("release %q has no chart", release.Name)}
+		}
+
+		if _, ok := index[release.Name]; ok {
+			return nil, ValidationError{Message: fmt.Sprintf("duplicate release: %q", release.Name)}
+		}
+
+		index[release.Name] = i
+	}
+
+	for _, release := range releases {
+		for _, dependency := range release.DependsOn {
+			if _, ok := index[dependency]; !ok {
+				return nil, ValidationError{Message: fmt.Sprintf("release %q depends on unknown release %q", release.Name, dependency)}
+			}
+
+			if dependency == release.Name {
+				return nil, ValidationError{Message: fmt.Sprintf("release %q depends on itself", release.Name)}
+			}
+		}
+	}
+
+	sorted := make([]Release, 0, len(releases))
+	done := make(map[string]bool, len(releases))
+
+	for len(sorted) < len(releases) {
+		progress := false
+
+		for _, release := range releases {
+			if done[release.Name] {
+				continue
+			}
+
+			ready := true
+			for _, dependency := range release.DependsOn {
+				if !done[dependency] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				sorted = append(sorted, release)
+				done[release.Name] = true
+				progress = true
+
+				// restart from the beginning to keep the declaration order as much as possible
+				break
+			}
+		}
+
+		if !progress {
+			var cycle []string
+			for _, release := range releases {
+				if !done[release.Name] {
+					cycle = append(cycle, release.Name)
+				}
+			}
+
+			return nil, ValidationError{Message: fmt.Sprintf("dependency cycle between releases: %v", cycle)}
+		}
+	}
+
+	return sorted, nil
+}
+
+// NotFoundError is returned if an application cannot be found.
+type NotFoundError struct {
+	ClusterID uint
+	Name      string
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "application not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"clusterId", e.ClusterID, "application", e.Name}
+}
+
+// IsBusinessError tells the transport layer to return this error to the client.
+func (NotFoundError) IsBusinessError() bool {
+	return true
+}
+
+// AlreadyExistsError is returned if an application exists already.
+type AlreadyExistsError struct {
+	ClusterID uint
+	Name      string
+}
+
+// Error implements the error interface.
+func (AlreadyExistsError) Error() string {
+	return "application already exists"
+}
+
+// Details returns error details.
+func (e AlreadyExistsError) Details() []interface{} {
+	return []interface{}{"clusterId", e.ClusterID, "application", e.Name}
+}
+
+// IsBusinessError tells the transport layer to return this error to the client.
+func (AlreadyExistsError) IsBusinessError() bool {
+	return true
+}
+
+// BusyError is returned if an application is being reconciled or deleted.
+type BusyError struct {
+	ClusterID uint
+	Name      string
+}
+
+// Error implements the error interface.
+func (BusyError) Error() string {
+	return "application is being reconciled or deleted"
+}
+
+// Details returns error details.
+func (e BusyError) Details() []interface{} {
+	return []interface{}{"clusterId", e.ClusterID, "application", e.Name}
+}
+
+// IsBusinessError tells the transport layer to return this error to the client.
+func (BusyError) IsBusinessError() bool {
+	return true
+}
+
+// ValidationError is returned if the declared state of an application is invalid.
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// IsBusinessError tells the transport layer to return this error to the client.
+func (ValidationError) IsBusinessError() bool {
+	return true
+}
@@ -0,0 +1,231 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockery -name Store -inpkg -testonly
+//go:generate mockery -name Reconciler -inpkg -testonly
+//go:generate mockery -name Releases -inpkg -testonly
+
+func testSpec() Spec {
+	return Spec{
+		Namespace: "shop",
+		Values: map[string]interface{}{
+			"domain": "example.com",
+		},
+		Releases: []Release{
+			{
+				Name:      "frontend",
+				Chart:     "stable/nginx",
+				Values:    map[string]interface{}{"host": "shop.{{ .Values.domain }}"},
+				DependsOn: []string{"backend"},
+			},
+			{
+				Name:      "backend",
+				Chart:     "stable/backend",
+				DependsOn: []string{"database"},
+			},
+			{
+				Name:  "database",
+				Chart: "stable/mysql",
+			},
+		},
+	}
+}
+
+func TestSortReleases(t *testing.T) {
+	releases, err := SortReleases(testSpec().Releases)
+	require.NoError(t, err)
+
+	var names []string
+	for _, release := range releases {
+		names = append(names, release.Name)
+	}
+
+	assert.Equal(t, []string{"database", "backend", "frontend"}, names)
+}
+
+func TestSortReleases_KeepsDeclarationOrder(t *testing.T) {
+	releases, err := SortReleases([]Release{
+		{Name: "a", Chart: "stable/a"},
+		{Name: "b", Chart: "stable/b", DependsOn: []string{"c"}},
+		{Name: "c", Chart: "stable/c"},
+		{Name: "d", Chart: "stable/d"},
+	})
+	require.NoError(t, err)
+
+	var names []string
+	for _, release := range releases {
+		names = append(names, release.Name)
+	}
+
+	assert.Equal(t, []string{"a", "c", "b", "d"}, names)
+}
+
+func TestSortReleases_Invalid(t *testing.T) {
+	tests := map[string][]Release{
+		"cycle": {
+			{Name: "a", Chart: "stable/a", DependsOn: []string{"b"}},
+			{Name: "b", Chart: "stable/b", DependsOn: []string{"a"}},
+		},
+		"unknown dependency": {
+			{Name: "a", Chart: "stable/a", DependsOn: []string{"b"}},
+		},
+		"self dependency": {
+			{Name: "a", Chart: "stable/a", DependsOn: []string{"a"}},
+		},
+		"duplicate": {
+			{Name: "a", Chart: "stable/a"},
+			{Name: "a", Chart: "stable/b"},
+		},
+		"missing chart": {
+			{Name: "a"},
+		},
+		"invalid name": {
+			{Name: "A_B", Chart: "stable/a"},
+		},
+	}
+
+	for name, releases := range tests {
+		releases := releases
+
+		t.Run(name, func(t *testing.T) {
+			_, err := SortReleases(releases)
+
+			assert.True(t, errors.As(err, &ValidationError{}))
+		})
+	}
+}
+
+func TestService_CreateApplication(t *testing.T) {
+	ctx := context.Background()
+
+	store := new(MockStore)
+	store.On("List", ctx, uint(1)).Return([]Application{}, nil)
+	store.On("Create", ctx, mock.MatchedBy(func(app Application) bool {
+		return app.ClusterID == 1 && app.Name == "shop" && app.Status == StatusReconciling
+	})).Return(nil)
+
+	reconciler := new(MockReconciler)
+	reconciler.On("Reconcile", ctx, uint(1), "shop").Return(nil)
+
+	service := NewService(store, reconciler, nil)
+
+	app, err := service.CreateApplication(ctx, 1, "shop", testSpec())
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusReconciling, app.Status)
+
+	store.AssertExpectations(t)
+	reconciler.AssertExpectations(t)
+}
+
+func TestService_CreateApplication_ReleaseConflict(t *testing.T) {
+	ctx := context.Background()
+
+	store := new(MockStore)
+	store.On("List", ctx, uint(1)).Return([]Application{
+		{
+			ClusterID: 1,
+			Name:      "other",
+			Spec:      Spec{Releases: []Release{{Name: "database", Chart: "stable/mysql"}}},
+		},
+	}, nil)
+
+	service := NewService(store, nil, nil)
+
+	_, err := service.CreateApplication(ctx, 1, "shop", testSpec())
+
+	assert.True(t, errors.As(err, &ValidationError{}))
+
+	store.AssertExpectations(t)
+}
+
+func TestService_CreateApplication_ReconcileFailed(t *testing.T) {
+	ctx := context.Background()
+
+	store := new(MockStore)
+	store.On("List", ctx, uint(1)).Return([]Application{}, nil)
+	store.On("Create", ctx, mock.Anything).Return(nil)
+	store.On("UpdateStatus", ctx, uint(1), "shop", StatusFailed, mock.Anything, []ReleaseStatus(nil)).Return(nil)
+
+	reconciler := new(MockReconciler)
+	reconciler.On("Reconcile", ctx, uint(1), "shop").Return(errors.New("cadence is down"))
+
+	service := NewService(store, reconciler, nil)
+
+	_, err := service.CreateApplication(ctx, 1, "shop", testSpec())
+	require.Error(t, err)
+
+	store.AssertExpectations(t)
+	reconciler.AssertExpectations(t)
+}
+
+func TestService_UpdateApplication_Busy(t *testing.T) {
+	ctx := context.Background()
+
+	store := new(MockStore)
+	store.On("Get", ctx, uint(1), "shop").Return(Application{ClusterID: 1, Name: "shop", Status: StatusReconciling}, nil)
+
+	service := NewService(store, nil, nil)
+
+	_, err := service.UpdateApplication(ctx, 1, "shop", testSpec())
+
+	assert.True(t, errors.As(err, &BusyError{}))
+
+	store.AssertExpectations(t)
+}
+
+func TestService_GetApplicationHealth(t *testing.T) {
+	ctx := context.Background()
+
+	store := new(MockStore)
+	store.On("Get", ctx, uint(1), "shop").Return(Application{ClusterID: 1, Name: "shop", Spec: testSpec()}, nil)
+
+	releases := new(MockReleases)
+	releases.On("Get", ctx, uint(1), "frontend").Return(ReleaseInfo{Installed: true, Revision: 2, Status: "DEPLOYED"}, nil)
+	releases.On("Get", ctx, uint(1), "backend").Return(ReleaseInfo{Installed: true, Revision: 3, Status: "FAILED"}, nil)
+	releases.On("Get", ctx, uint(1), "database").Return(ReleaseInfo{}, nil)
+
+	service := NewService(store, nil, releases)
+
+	health, err := service.GetApplicationHealth(ctx, 1, "shop")
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		Health{
+			Status: HealthDegraded,
+			Releases: []ReleaseHealth{
+				{Name: "frontend", Status: "DEPLOYED", Revision: 2},
+				{Name: "backend", Status: "FAILED", Revision: 3},
+				{Name: "database", Status: "NOT_INSTALLED"},
+			},
+		},
+		health,
+	)
+
+	store.AssertExpectations(t)
+	releases.AssertExpectations(t)
+}
@@ -0,0 +1,75 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applicationadapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application"
+)
+
+// CadenceReconciler reconciles applications using Cadence workflows.
+type CadenceReconciler struct {
+	cadenceClient client.Client
+}
+
+// NewCadenceReconciler returns a new CadenceReconciler.
+func NewCadenceReconciler(cadenceClient client.Client) CadenceReconciler {
+	return CadenceReconciler{
+		cadenceClient: cadenceClient,
+	}
+}
+
+// Reconcile starts reconciling the releases of an application with its declared state.
+func (r CadenceReconciler) Reconcile(ctx context.Context, clusterID uint, name string) error {
+	return r.start(ctx, application.ReconcileWorkflowName, clusterID, name)
+}
+
+// Delete starts deleting the releases of an application.
+func (r CadenceReconciler) Delete(ctx context.Context, clusterID uint, name string) error {
+	return r.start(ctx, application.DeleteWorkflowName, clusterID, name)
+}
+
+func (r CadenceReconciler) start(ctx context.Context, workflowName string, clusterID uint, name string) error {
+	// The workflow ID makes sure that an application is never reconciled and deleted concurrently
+	workflowID := fmt.Sprintf("application-%d-%s", clusterID, name)
+
+	options := client.StartWorkflowOptions{
+		ID:                           workflowID,
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: 3 * time.Hour,
+		WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+	}
+
+	input := application.WorkflowInput{
+		ClusterID: clusterID,
+		Name:      name,
+	}
+
+	_, err := r.cadenceClient.StartWorkflow(ctx, options, workflowName, input)
+	if _, ok := err.(*shared.WorkflowExecutionAlreadyStartedError); ok {
+		return application.BusyError{ClusterID: clusterID, Name: name}
+	} else if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to start workflow", "workflowName", workflowName, "workflowId", workflowID)
+	}
+
+	return nil
+}
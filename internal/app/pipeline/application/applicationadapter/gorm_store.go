@@ -0,0 +1,304 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applicationadapter
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application"
+)
+
+// TableName constants
+const (
+	applicationTableName = "applications"
+)
+
+type applicationModel struct {
+	ID            uint `gorm:"primary_key"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	ClusterID     uint   `gorm:"unique_index:idx_applications_cluster_name;not null"`
+	Name          string `gorm:"unique_index:idx_applications_cluster_name;not null"`
+	Spec          string `sql:"type:text"`
+	Status        string
+	StatusMessage string `sql:"type:text"`
+	Releases      string `sql:"type:text"`
+}
+
+// TableName changes the default table name.
+func (applicationModel) TableName() string {
+	return applicationTableName
+}
+
+// specModel is the JSON representation of the declared state of an application.
+type specModel struct {
+	Namespace string                 `json:"namespace,omitempty"`
+	Values    map[string]interface{} `json:"values,omitempty"`
+	Releases  []releaseModel         `json:"releases"`
+}
+
+type releaseModel struct {
+	Name      string                 `json:"name"`
+	Chart     string                 `json:"chart"`
+	Version   string                 `json:"version,omitempty"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Values    map[string]interface{} `json:"values,omitempty"`
+	DependsOn []string               `json:"dependsOn,omitempty"`
+}
+
+type releaseStatusModel struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Revision int32  `json:"revision,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// GormStore stores applications using Gorm for data persistence.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) GormStore {
+	return GormStore{
+		db: db,
+	}
+}
+
+// List returns the applications of a cluster.
+func (s GormStore) List(ctx context.Context, clusterID uint) ([]application.Application, error) {
+	var models []applicationModel
+
+	err := s.db.Where(applicationModel{ClusterID: clusterID}).Order("name").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list applications", "clusterId", clusterID)
+	}
+
+	apps := make([]application.Application, 0, len(models))
+	for _, model := range models {
+		app, err := toApplication(model)
+		if err != nil {
+			return nil, err
+		}
+
+		apps = append(apps, app)
+	}
+
+	return apps, nil
+}
+
+// Get returns an application.
+func (s GormStore) Get(ctx context.Context, clusterID uint, name string) (application.Application, error) {
+	model, err := s.find(clusterID, name)
+	if err != nil {
+		return application.Application{}, err
+	}
+
+	return toApplication(model)
+}
+
+// Create stores a new application.
+func (s GormStore) Create(ctx context.Context, app application.Application) error {
+	_, err := s.find(app.ClusterID, app.Name)
+	if err == nil {
+		return application.AlreadyExistsError{ClusterID: app.ClusterID, Name: app.Name}
+	} else if !errors.As(err, &application.NotFoundError{}) {
+		return err
+	}
+
+	model := applicationModel{
+		ClusterID: app.ClusterID,
+		Name:      app.Name,
+	}
+
+	if err := fromApplication(app, &model); err != nil {
+		return err
+	}
+
+	err = s.db.Create(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to create application", "clusterId", app.ClusterID, "application", app.Name)
+	}
+
+	return nil
+}
+
+// Update updates the declared state and the status of an application.
+func (s GormStore) Update(ctx context.Context, app application.Application) error {
+	model, err := s.find(app.ClusterID, app.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := fromApplication(app, &model); err != nil {
+		return err
+	}
+
+	err = s.db.Save(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to update application", "clusterId", app.ClusterID, "application", app.Name)
+	}
+
+	return nil
+}
+
+// UpdateStatus records the result of a reconciliation.
+func (s GormStore) UpdateStatus(
+	ctx context.Context,
+	clusterID uint,
+	name string,
+	status string,
+	message string,
+	releases []application.ReleaseStatus,
+) error {
+	model, err := s.find(clusterID, name)
+	if err != nil {
+		return err
+	}
+
+	model.Releases, err = marshalReleaseStatuses(releases)
+	if err != nil {
+		return err
+	}
+
+	model.Status = status
+	model.StatusMessage = message
+
+	err = s.db.Save(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to update application status", "clusterId", clusterID, "application", name)
+	}
+
+	return nil
+}
+
+// Delete deletes an application.
+func (s GormStore) Delete(ctx context.Context, clusterID uint, name string) error {
+	err := s.db.Where(applicationModel{ClusterID: clusterID, Name: name}).Delete(applicationModel{}).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete application", "clusterId", clusterID, "application", name)
+	}
+
+	return nil
+}
+
+func (s GormStore) find(clusterID uint, name string) (applicationModel, error) {
+	var model applicationModel
+
+	err := s.db.Where(applicationModel{ClusterID: clusterID, Name: name}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return applicationModel{}, application.NotFoundError{ClusterID: clusterID, Name: name}
+	}
+	if err != nil {
+		return applicationModel{}, errors.WrapIfWithDetails(err, "failed to find application", "clusterId", clusterID, "application", name)
+	}
+
+	return model, nil
+}
+
+func fromApplication(app application.Application, model *applicationModel) error {
+	spec := specModel{
+		Namespace: app.Spec.Namespace,
+		Values:    app.Spec.Values,
+		Releases:  make([]releaseModel, 0, len(app.Spec.Releases)),
+	}
+
+	for _, release := range app.Spec.Releases {
+		spec.Releases = append(spec.Releases, releaseModel{
+			Name:      release.Name,
+			Chart:     release.Chart,
+			Version:   release.Version,
+			Namespace: release.Namespace,
+			Values:    release.Values,
+			DependsOn: release.DependsOn,
+		})
+	}
+
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to marshal application spec", "application", app.Name)
+	}
+
+	model.Spec = string(specJSON)
+	model.Status = app.Status
+	model.StatusMessage = app.StatusMessage
+
+	model.Releases, err = marshalReleaseStatuses(app.Releases)
+
+	return err
+}
+
+func marshalReleaseStatuses(releases []application.ReleaseStatus) (string, error) {
+	models := make([]releaseStatusModel, 0, len(releases))
+	for _, release := range releases {
+		models = append(models, releaseStatusModel(release))
+	}
+
+	releasesJSON, err := json.Marshal(models)
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to marshal release statuses")
+	}
+
+	return string(releasesJSON), nil
+}
+
+func toApplication(model applicationModel) (application.Application, error) {
+	var spec specModel
+
+	if err := json.Unmarshal([]byte(model.Spec), &spec); err != nil {
+		return application.Application{}, errors.WrapIfWithDetails(err, "failed to unmarshal application spec", "application", model.Name)
+	}
+
+	app := application.Application{
+		ClusterID: model.ClusterID,
+		Name:      model.Name,
+		Spec: application.Spec{
+			Namespace: spec.Namespace,
+			Values:    spec.Values,
+		},
+		Status:        model.Status,
+		StatusMessage: model.StatusMessage,
+	}
+
+	for _, release := range spec.Releases {
+		app.Spec.Releases = append(app.Spec.Releases, application.Release{
+			Name:      release.Name,
+			Chart:     release.Chart,
+			Version:   release.Version,
+			Namespace: release.Namespace,
+			Values:    release.Values,
+			DependsOn: release.DependsOn,
+		})
+	}
+
+	if model.Releases != "" {
+		var releases []releaseStatusModel
+
+		if err := json.Unmarshal([]byte(model.Releases), &releases); err != nil {
+			return application.Application{}, errors.WrapIfWithDetails(err, "failed to unmarshal release statuses", "application", model.Name)
+		}
+
+		for _, release := range releases {
+			app.Releases = append(app.Releases, application.ReleaseStatus(release))
+		}
+	}
+
+	return app, nil
+}
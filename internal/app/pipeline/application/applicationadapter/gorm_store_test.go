@@ -0,0 +1,88 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applicationadapter
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+func testGormStore(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = Migrate(db, commonadapter.NewNoopLogger())
+	require.NoError(t, err)
+
+	store := NewGormStore(db)
+
+	app := application.Application{
+		ClusterID: 1,
+		Name:      "shop",
+		Spec: application.Spec{
+			Namespace: "shop",
+			Values:    map[string]interface{}{"domain": "example.com"},
+			Releases: []application.Release{
+				{Name: "database", Chart: "stable/mysql"},
+				{Name: "frontend", Chart: "stable/nginx", DependsOn: []string{"database"}},
+			},
+		},
+		Status: application.StatusReconciling,
+	}
+
+	err = store.Create(ctx, app)
+	require.NoError(t, err)
+
+	err = store.Create(ctx, app)
+	assert.True(t, errors.As(err, &application.AlreadyExistsError{}))
+
+	foundApp, err := store.Get(ctx, 1, "shop")
+	require.NoError(t, err)
+
+	assert.Equal(t, app, foundApp)
+
+	releases := []application.ReleaseStatus{
+		{Name: "database", Status: application.ReleaseStatusDeployed, Revision: 1},
+		{Name: "frontend", Status: application.ReleaseStatusFailed, Message: "timed out"},
+	}
+
+	err = store.UpdateStatus(ctx, 1, "shop", application.StatusFailed, "release failed", releases)
+	require.NoError(t, err)
+
+	apps, err := store.List(ctx, 1)
+	require.NoError(t, err)
+
+	require.Len(t, apps, 1)
+	assert.Equal(t, application.StatusFailed, apps[0].Status)
+	assert.Equal(t, "release failed", apps[0].StatusMessage)
+	assert.Equal(t, releases, apps[0].Releases)
+
+	err = store.Delete(ctx, 1, "shop")
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, 1, "shop")
+	assert.True(t, errors.As(err, &application.NotFoundError{}))
+}
@@ -0,0 +1,165 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applicationadapter
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	k8sHelm "k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+
+	"github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application"
+	internalHelm "github.com/banzaicloud/pipeline/internal/helm"
+)
+
+// HelmReleases manages the releases of applications using Helm.
+type HelmReleases struct {
+	clusters internalHelm.ClusterService
+}
+
+// NewHelmReleases returns a new HelmReleases.
+func NewHelmReleases(clusters internalHelm.ClusterService) HelmReleases {
+	return HelmReleases{
+		clusters: clusters,
+	}
+}
+
+// Get returns the live state of a release.
+func (r HelmReleases) Get(ctx context.Context, clusterID uint, name string) (application.ReleaseInfo, error) {
+	cluster, err := r.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return application.ReleaseInfo{}, err
+	}
+
+	foundRelease, err := findRelease(name, cluster.KubeConfig)
+	if err != nil || foundRelease == nil {
+		return application.ReleaseInfo{}, err
+	}
+
+	return application.ReleaseInfo{
+		Installed: true,
+		Revision:  foundRelease.GetVersion(),
+		Status:    foundRelease.GetInfo().GetStatus().GetCode().String(),
+	}, nil
+}
+
+// Apply installs or upgrades a release and waits until it becomes ready.
+// A failed release is deleted and installed again.
+func (r HelmReleases) Apply(ctx context.Context, clusterID uint, rel application.PlannedRelease, timeout time.Duration) (int32, error) {
+	cluster, err := r.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return 0, err
+	}
+
+	env := helm.GenerateHelmRepoEnv(cluster.OrganizationName)
+
+	foundRelease, err := findRelease(rel.Name, cluster.KubeConfig)
+	if err != nil {
+		return 0, err
+	}
+
+	if foundRelease != nil && foundRelease.GetInfo().GetStatus().GetCode() == release.Status_DEPLOYED {
+		res, err := helm.UpgradeDeployment(
+			rel.Name,
+			rel.Chart,
+			rel.Version,
+			nil,
+			rel.Values,
+			false,
+			cluster.KubeConfig,
+			env,
+			k8sHelm.UpgradeWait(true),
+			k8sHelm.UpgradeTimeout(int64(timeout.Seconds())),
+		)
+		if err != nil {
+			return 0, errors.WrapIfWithDetails(err, "failed to upgrade release", "chart", rel.Chart, "release", rel.Name)
+		}
+
+		return res.GetRelease().GetVersion(), nil
+	}
+
+	if foundRelease != nil {
+		if err := helm.DeleteDeployment(rel.Name, cluster.KubeConfig); err != nil {
+			return 0, errors.WrapIfWithDetails(err, "failed to delete failed release", "chart", rel.Chart, "release", rel.Name)
+		}
+	}
+
+	res, err := helm.CreateDeployment(
+		rel.Chart,
+		rel.Version,
+		nil,
+		rel.Namespace,
+		rel.Name,
+		false,
+		nil,
+		cluster.KubeConfig,
+		env,
+		k8sHelm.ValueOverrides(rel.Values),
+		k8sHelm.InstallWait(true),
+		k8sHelm.InstallTimeout(int64(timeout.Seconds())),
+	)
+	if err != nil {
+		return 0, errors.WrapIfWithDetails(err, "failed to install release", "chart", rel.Chart, "release", rel.Name)
+	}
+
+	return res.GetRelease().GetVersion(), nil
+}
+
+// Rollback rolls back a release to a previous revision.
+func (r HelmReleases) Rollback(ctx context.Context, clusterID uint, name string, revision int32) error {
+	cluster, err := r.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	_, err = helm.RollbackDeployment(name, cluster.KubeConfig, revision)
+
+	return err
+}
+
+// Delete deletes a release.
+func (r HelmReleases) Delete(ctx context.Context, clusterID uint, name string) error {
+	cluster, err := r.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	foundRelease, err := findRelease(name, cluster.KubeConfig)
+	if err != nil || foundRelease == nil {
+		return err
+	}
+
+	return helm.DeleteDeployment(name, cluster.KubeConfig)
+}
+
+func findRelease(releaseName string, kubeConfig []byte) (*release.Release, error) {
+	deployments, err := helm.ListDeployments(&releaseName, "", kubeConfig)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to fetch deployments", "release", releaseName)
+	}
+
+	if deployments != nil {
+		for _, rel := range deployments.Releases {
+			if rel.Name == releaseName {
+				return rel, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
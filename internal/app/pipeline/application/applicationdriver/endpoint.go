@@ -0,0 +1,116 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applicationdriver
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application"
+)
+
+type listApplicationsRequest struct {
+	ClusterID uint
+}
+
+func MakeListApplicationsEndpoint(service application.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(listApplicationsRequest)
+
+		return service.ListApplications(ctx, r.ClusterID)
+	})
+}
+
+type getApplicationRequest struct {
+	ClusterID uint
+	Name      string
+}
+
+func MakeGetApplicationEndpoint(service application.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(getApplicationRequest)
+
+		return service.GetApplication(ctx, r.ClusterID, r.Name)
+	})
+}
+
+type createApplicationRequest struct {
+	ClusterID uint
+	Name      string
+	Spec      application.Spec
+}
+
+func MakeCreateApplicationEndpoint(service application.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(createApplicationRequest)
+
+		return service.CreateApplication(ctx, r.ClusterID, r.Name, r.Spec)
+	})
+}
+
+type updateApplicationRequest struct {
+	ClusterID uint
+	Name      string
+	Spec      application.Spec
+}
+
+func MakeUpdateApplicationEndpoint(service application.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(updateApplicationRequest)
+
+		return service.UpdateApplication(ctx, r.ClusterID, r.Name, r.Spec)
+	})
+}
+
+type deleteApplicationRequest struct {
+	ClusterID uint
+	Name      string
+}
+
+func MakeDeleteApplicationEndpoint(service application.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(deleteApplicationRequest)
+
+		return nil, service.DeleteApplication(ctx, r.ClusterID, r.Name)
+	})
+}
+
+type reconcileApplicationRequest struct {
+	ClusterID uint
+	Name      string
+}
+
+func MakeReconcileApplicationEndpoint(service application.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(reconcileApplicationRequest)
+
+		return nil, service.ReconcileApplication(ctx, r.ClusterID, r.Name)
+	})
+}
+
+type getApplicationHealthRequest struct {
+	ClusterID uint
+	Name      string
+}
+
+func MakeGetApplicationHealthEndpoint(service application.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(getApplicationHealthRequest)
+
+		return service.GetApplicationHealth(ctx, r.ClusterID, r.Name)
+	})
+}
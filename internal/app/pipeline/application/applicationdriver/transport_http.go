@@ -0,0 +1,374 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applicationdriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"emperror.dev/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+	"github.com/banzaicloud/pipeline/pkg/problems"
+)
+
+// RegisterHTTPHandlers mounts all of the service endpoints into a router.
+func RegisterHTTPHandlers(endpoints Endpoints, router *mux.Router, options ...kithttp.ServerOption) {
+	options = append(options, kithttp.ServerErrorEncoder(encodeHTTPError))
+
+	router.Methods(http.MethodGet).Path("").Handler(kithttp.NewServer(
+		endpoints.ListApplications,
+		decodeListApplicationsHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeListApplicationsHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPost).Path("").Handler(kithttp.NewServer(
+		endpoints.CreateApplication,
+		decodeCreateApplicationHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeAcceptedApplicationHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("/{name}").Handler(kithttp.NewServer(
+		endpoints.GetApplication,
+		decodeGetApplicationHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeApplicationHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPut).Path("/{name}").Handler(kithttp.NewServer(
+		endpoints.UpdateApplication,
+		decodeUpdateApplicationHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeAcceptedApplicationHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodDelete).Path("/{name}").Handler(kithttp.NewServer(
+		endpoints.DeleteApplication,
+		decodeDeleteApplicationHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusAccepted), errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPost).Path("/{name}/reconcile").Handler(kithttp.NewServer(
+		endpoints.ReconcileApplication,
+		decodeReconcileApplicationHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusAccepted), errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("/{name}/health").Handler(kithttp.NewServer(
+		endpoints.GetApplicationHealth,
+		decodeGetApplicationHealthHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeApplicationHealthHTTPResponse, errorEncoder),
+		options...,
+	))
+}
+
+// applicationRequest is the JSON representation of an application create or update request.
+type applicationRequest struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Values    map[string]interface{} `json:"values,omitempty"`
+	Releases  []releaseItem          `json:"releases"`
+}
+
+type releaseItem struct {
+	ReleaseName string                 `json:"releaseName"`
+	Chart       string                 `json:"chart"`
+	Version     string                 `json:"version,omitempty"`
+	Namespace   string                 `json:"namespace,omitempty"`
+	Values      map[string]interface{} `json:"values,omitempty"`
+	DependsOn   []string               `json:"dependsOn,omitempty"`
+}
+
+// applicationResponse is the JSON representation of an application.
+type applicationResponse struct {
+	Name            string                 `json:"name"`
+	Namespace       string                 `json:"namespace,omitempty"`
+	Values          map[string]interface{} `json:"values,omitempty"`
+	Releases        []releaseItem          `json:"releases"`
+	Status          string                 `json:"status"`
+	StatusMessage   string                 `json:"statusMessage,omitempty"`
+	ReleaseStatuses []releaseStatusItem    `json:"releaseStatuses"`
+}
+
+type releaseStatusItem struct {
+	ReleaseName string `json:"releaseName"`
+	Status      string `json:"status"`
+	Revision    int32  `json:"revision,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// healthResponse is the JSON representation of the aggregate health of an application.
+type healthResponse struct {
+	Status   string              `json:"status"`
+	Releases []releaseHealthItem `json:"releases"`
+}
+
+type releaseHealthItem struct {
+	ReleaseName string `json:"releaseName"`
+	Status      string `json:"status"`
+	Revision    int32  `json:"revision,omitempty"`
+}
+
+func decodeListApplicationsHTTPRequest(ctx context.Context, _ *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return listApplicationsRequest{ClusterID: clusterID}, nil
+}
+
+func decodeCreateApplicationHTTPRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	clusterID, err := getClusterID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := decodeApplicationRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return createApplicationRequest{
+		ClusterID: clusterID,
+		Name:      req.Name,
+		Spec:      toSpec(req),
+	}, nil
+}
+
+func decodeGetApplicationHTTPRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	clusterID, name, err := getApplication(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return getApplicationRequest{ClusterID: clusterID, Name: name}, nil
+}
+
+func decodeUpdateApplicationHTTPRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	clusterID, name, err := getApplication(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := decodeApplicationRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateApplicationRequest{
+		ClusterID: clusterID,
+		Name:      name,
+		Spec:      toSpec(req),
+	}, nil
+}
+
+func decodeDeleteApplicationHTTPRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	clusterID, name, err := getApplication(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return deleteApplicationRequest{ClusterID: clusterID, Name: name}, nil
+}
+
+func decodeReconcileApplicationHTTPRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	clusterID, name, err := getApplication(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return reconcileApplicationRequest{ClusterID: clusterID, Name: name}, nil
+}
+
+func decodeGetApplicationHealthHTTPRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	clusterID, name, err := getApplication(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return getApplicationHealthRequest{ClusterID: clusterID, Name: name}, nil
+}
+
+func decodeApplicationRequest(r *http.Request) (applicationRequest, error) {
+	var req applicationRequest
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return req, application.ValidationError{Message: "invalid request body: " + err.Error()}
+	}
+
+	return req, nil
+}
+
+func toSpec(req applicationRequest) application.Spec {
+	spec := application.Spec{
+		Namespace: req.Namespace,
+		Values:    req.Values,
+	}
+
+	for _, release := range req.Releases {
+		spec.Releases = append(spec.Releases, application.Release{
+			Name:      release.ReleaseName,
+			Chart:     release.Chart,
+			Version:   release.Version,
+			Namespace: release.Namespace,
+			Values:    release.Values,
+			DependsOn: release.DependsOn,
+		})
+	}
+
+	return spec
+}
+
+func encodeListApplicationsHTTPResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	apps := resp.([]application.Application)
+
+	items := make([]applicationResponse, 0, len(apps))
+	for _, app := range apps {
+		items = append(items, toApplicationResponse(app))
+	}
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, items)
+}
+
+func encodeApplicationHTTPResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	return kitxhttp.JSONResponseEncoder(ctx, w, toApplicationResponse(resp.(application.Application)))
+}
+
+func encodeAcceptedApplicationHTTPResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	// The releases are reconciled in the background
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusAccepted)
+
+	return json.NewEncoder(w).Encode(toApplicationResponse(resp.(application.Application)))
+}
+
+func encodeApplicationHealthHTTPResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	health := resp.(application.Health)
+
+	response := healthResponse{
+		Status:   health.Status,
+		Releases: make([]releaseHealthItem, 0, len(health.Releases)),
+	}
+
+	for _, release := range health.Releases {
+		response.Releases = append(response.Releases, releaseHealthItem{
+			ReleaseName: release.Name,
+			Status:      release.Status,
+			Revision:    release.Revision,
+		})
+	}
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, response)
+}
+
+func toApplicationResponse(app application.Application) applicationResponse {
+	response := applicationResponse{
+		Name:            app.Name,
+		Namespace:       app.Spec.Namespace,
+		Values:          app.Spec.Values,
+		Releases:        make([]releaseItem, 0, len(app.Spec.Releases)),
+		Status:          app.Status,
+		StatusMessage:   app.StatusMessage,
+		ReleaseStatuses: make([]releaseStatusItem, 0, len(app.Releases)),
+	}
+
+	for _, release := range app.Spec.Releases {
+		response.Releases = append(response.Releases, releaseItem{
+			ReleaseName: release.Name,
+			Chart:       release.Chart,
+			Version:     release.Version,
+			Namespace:   release.Namespace,
+			Values:      release.Values,
+			DependsOn:   release.DependsOn,
+		})
+	}
+
+	for _, release := range app.Releases {
+		response.ReleaseStatuses = append(response.ReleaseStatuses, releaseStatusItem{
+			ReleaseName: release.Name,
+			Status:      release.Status,
+			Revision:    release.Revision,
+			Message:     release.Message,
+		})
+	}
+
+	return response
+}
+
+func getClusterID(ctx context.Context) (uint, error) {
+	clusterID, ok := ctxutil.ClusterID(ctx)
+	if !ok {
+		return 0, errors.New("cluster ID not found in the context")
+	}
+
+	return clusterID, nil
+}
+
+func getApplication(ctx context.Context, r *http.Request) (uint, string, error) {
+	clusterID, err := getClusterID(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	name, ok := mux.Vars(r)["name"]
+	if !ok || name == "" {
+		return 0, "", errors.NewWithDetails("missing parameter from the URL", "param", "name")
+	}
+
+	return clusterID, name, nil
+}
+
+func encodeHTTPError(ctx context.Context, err error, w http.ResponseWriter) {
+	_ = errorEncoder(ctx, w, err)
+}
+
+func errorEncoder(_ context.Context, w http.ResponseWriter, e error) error {
+	var problem problems.StatusProblem
+
+	switch {
+	case errors.As(e, &application.NotFoundError{}):
+		problem = problems.NewDetailedProblem(http.StatusNotFound, e.Error())
+
+	case errors.As(e, &application.AlreadyExistsError{}), errors.As(e, &application.BusyError{}):
+		problem = problems.NewDetailedProblem(http.StatusConflict, e.Error())
+
+	case errors.As(e, &application.ValidationError{}):
+		problem = problems.NewDetailedProblem(http.StatusUnprocessableEntity, e.Error())
+
+	default:
+		problem = problems.NewDetailedProblem(http.StatusInternalServerError, "something went wrong")
+	}
+
+	w.Header().Set("Content-Type", problems.ProblemMediaType)
+	w.WriteHeader(problem.ProblemStatus())
+
+	err := json.NewEncoder(w).Encode(problem)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode error response")
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package applicationdriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/application"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+)
+
+func newTestServer(endpoints Endpoints) *httptest.Server {
+	router := mux.NewRouter()
+	RegisterHTTPHandlers(endpoints, router.PathPrefix("/applications").Subrouter())
+
+	// The cluster ID is set by the cluster middleware in production
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r.WithContext(ctxutil.WithClusterID(r.Context(), 1)))
+	}))
+}
+
+func TestRegisterHTTPHandlers_CreateApplication(t *testing.T) {
+	ts := newTestServer(Endpoints{
+		CreateApplication: func(ctx context.Context, request interface{}) (interface{}, error) {
+			spec := application.Spec{
+				Namespace: "shop",
+				Values:    map[string]interface{}{"domain": "example.com"},
+				Releases: []application.Release{
+					{Name: "database", Chart: "stable/mysql"},
+					{
+						Name:      "frontend",
+						Chart:     "stable/nginx",
+						Version:   "1.2.3",
+						Values:    map[string]interface{}{"host": "{{ .Values.domain }}"},
+						DependsOn: []string{"database"},
+					},
+				},
+			}
+
+			assert.Equal(t, createApplicationRequest{ClusterID: 1, Name: "shop", Spec: spec}, request)
+
+			return application.Application{
+				ClusterID: 1,
+				Name:      "shop",
+				Spec:      spec,
+				Status:    application.StatusReconciling,
+			}, nil
+		},
+	})
+	defer ts.Close()
+
+	req, err := http.NewRequest(
+		http.MethodPost,
+		ts.URL+"/applications",
+		strings.NewReader(`{
+			"name": "shop",
+			"namespace": "shop",
+			"values": {"domain": "example.com"},
+			"releases": [
+				{"releaseName": "database", "chart": "stable/mysql"},
+				{"releaseName": "frontend", "chart": "stable/nginx", "version": "1.2.3", "values": {"host": "{{ .Values.domain }}"}, "dependsOn": ["database"]}
+			]
+		}`),
+	)
+	require.NoError(t, err)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	var app applicationResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&app)
+	require.NoError(t, err)
+
+	assert.Equal(t, "shop", app.Name)
+	assert.Equal(t, application.StatusReconciling, app.Status)
+	assert.Len(t, app.Releases, 2)
+	assert.Empty(t, app.ReleaseStatuses)
+}
+
+func TestRegisterHTTPHandlers_GetApplication_NotFound(t *testing.T) {
+	ts := newTestServer(Endpoints{
+		GetApplication: func(ctx context.Context, request interface{}) (interface{}, error) {
+			assert.Equal(t, getApplicationRequest{ClusterID: 1, Name: "shop"}, request)
+
+			return nil, application.NotFoundError{ClusterID: 1, Name: "shop"}
+		},
+	})
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/applications/shop")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRegisterHTTPHandlers_ReconcileApplication_Busy(t *testing.T) {
+	ts := newTestServer(Endpoints{
+		ReconcileApplication: func(ctx context.Context, request interface{}) (interface{}, error) {
+			assert.Equal(t, reconcileApplicationRequest{ClusterID: 1, Name: "shop"}, request)
+
+			return nil, application.BusyError{ClusterID: 1, Name: "shop"}
+		},
+	})
+	defer ts.Close()
+
+	resp, err := ts.Client().Post(ts.URL+"/applications/shop/reconcile", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
+
+func TestRegisterHTTPHandlers_GetApplicationHealth(t *testing.T) {
+	ts := newTestServer(Endpoints{
+		GetApplicationHealth: func(ctx context.Context, request interface{}) (interface{}, error) {
+			return application.Health{
+				Status: application.HealthHealthy,
+				Releases: []application.ReleaseHealth{
+					{Name: "database", Status: "DEPLOYED", Revision: 3},
+				},
+			}, nil
+		},
+	})
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/applications/shop/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var health healthResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&health)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		healthResponse{
+			Status:   application.HealthHealthy,
+			Releases: []releaseHealthItem{{ReleaseName: "database", Status: "DEPLOYED", Revision: 3}},
+		},
+		health,
+	)
+}
@@ -0,0 +1,65 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"fmt"
+
+	"github.com/banzaicloud/pipeline/internal/helm/valuetemplate"
+)
+
+// ReleaseNamespace returns the namespace of a release, falling back to the namespace of the application.
+func ReleaseNamespace(spec Spec, release Release) string {
+	if release.Namespace != "" {
+		return release.Namespace
+	}
+
+	if spec.Namespace != "" {
+		return spec.Namespace
+	}
+
+	return "default"
+}
+
+// RenderValues evaluates the templates in the string values of a release.
+//
+// Templates can refer to the shared values of the application ({{ .Values.key }}),
+// the application ({{ .Application.Name }}, {{ .Application.Namespace }})
+// and the release itself ({{ .Release.Name }}, {{ .Release.Namespace }}).
+// Other templates are left untouched, so that the chart can evaluate them (eg. with tpl).
+func RenderValues(appName string, spec Spec, release Release) (map[string]interface{}, error) {
+	data := map[string]interface{}{
+		"Values": spec.Values,
+		"Application": map[string]interface{}{
+			"Name":      appName,
+			"Namespace": spec.Namespace,
+		},
+		"Release": map[string]interface{}{
+			"Name":      release.Name,
+			"Namespace": ReleaseNamespace(spec, release),
+		},
+	}
+
+	if data["Values"] == nil {
+		data["Values"] = map[string]interface{}{}
+	}
+
+	values, err := valuetemplate.RenderKnown(release.Values, data)
+	if err != nil {
+		return nil, ValidationError{Message: fmt.Sprintf("invalid values of release %q: %s", release.Name, err.Error())}
+	}
+
+	return values, nil
+}
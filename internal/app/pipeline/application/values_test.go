@@ -0,0 +1,111 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderValues(t *testing.T) {
+	spec := Spec{
+		Namespace: "shop",
+		Values: map[string]interface{}{
+			"domain": "example.com",
+		},
+	}
+
+	release := Release{
+		Name: "frontend",
+		Values: map[string]interface{}{
+			"replicaCount": 2,
+			"ingress": map[string]interface{}{
+				"hosts": []interface{}{"{{ .Release.Name }}.{{ .Values.domain }}"},
+			},
+			"backend": "http://backend.{{ .Release.Namespace }}.svc",
+			"title":   "{{ .Application.Name | upper }}",
+		},
+	}
+
+	values, err := RenderValues("shop", spec, release)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"replicaCount": 2,
+			"ingress": map[string]interface{}{
+				"hosts": []interface{}{"frontend.example.com"},
+			},
+			"backend": "http://backend.shop.svc",
+			"title":   "SHOP",
+		},
+		values,
+	)
+}
+
+func TestRenderValues_MissingValue(t *testing.T) {
+	release := Release{
+		Name: "frontend",
+		Values: map[string]interface{}{
+			"host": "{{ .Values.domain }}",
+		},
+	}
+
+	_, err := RenderValues("shop", Spec{}, release)
+
+	assert.True(t, errors.As(err, &ValidationError{}))
+}
+
+func TestRenderValues_Environment(t *testing.T) {
+	for _, value := range []string{`{{ .Release.Name }}: {{ env "HOME" }}`, `{{ .Release.Name }}: {{ expandenv "$HOME" }}`} {
+		release := Release{
+			Name: "frontend",
+			Values: map[string]interface{}{
+				"home": value,
+			},
+		}
+
+		_, err := RenderValues("shop", Spec{}, release)
+
+		assert.True(t, errors.As(err, &ValidationError{}), value)
+	}
+}
+
+func TestRenderValues_ChartTemplates(t *testing.T) {
+	release := Release{
+		Name: "frontend",
+		Values: map[string]interface{}{
+			"fullname": `{{ include "frontend.fullname" . }}`,
+			"chart":    "{{ .Chart.Name }}",
+			"home":     `{{ env "HOME" }}`,
+		},
+	}
+
+	values, err := RenderValues("shop", Spec{}, release)
+	require.NoError(t, err)
+
+	assert.Equal(t, release.Values, values)
+}
+
+func TestRenderValues_Empty(t *testing.T) {
+	values, err := RenderValues("shop", Spec{}, Release{Name: "frontend"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{}, values)
+}
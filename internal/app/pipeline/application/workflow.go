@@ -0,0 +1,244 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+const ReconcileWorkflowName = "application-reconcile"
+
+const DeleteWorkflowName = "application-delete"
+
+type WorkflowInput struct {
+	ClusterID uint
+	Name      string
+}
+
+// ReconcileWorkflow installs or upgrades the releases of an application in dependency order.
+// If a release fails, every release touched by the reconciliation is restored to its previous state.
+func ReconcileWorkflow(ctx workflow.Context, input WorkflowInput) error {
+	activityOptions := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+		WaitForCancellation:    true,
+	}
+
+	// Applying a release is never retried: a failure rolls back the whole application
+	applyOptions := activityOptions
+	applyOptions.StartToCloseTimeout = ReleaseTimeout + 5*time.Minute
+	applyCtx := workflow.WithActivityOptions(ctx, applyOptions)
+
+	// Default timeouts and retries
+	activityOptions.RetryPolicy = &cadence.RetryPolicy{
+		InitialInterval:    5 * time.Second,
+		BackoffCoefficient: 1.5,
+		MaximumInterval:    time.Minute,
+		MaximumAttempts:    10,
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	var plan PlanActivityOutput
+	{
+		activityInput := PlanActivityInput{
+			ClusterID: input.ClusterID,
+			Name:      input.Name,
+		}
+
+		err := workflow.ExecuteActivity(ctx, PlanActivityName, activityInput).Get(ctx, &plan)
+		if err != nil {
+			return recordStatus(ctx, input, StatusFailed, err.Error(), nil, err)
+		}
+	}
+
+	statuses := make([]ReleaseStatus, 0, len(plan.Releases)+len(plan.Removed))
+	for _, release := range plan.Releases {
+		statuses = append(statuses, ReleaseStatus{
+			Name:     release.Name,
+			Status:   ReleaseStatusPending,
+			Revision: release.Revision,
+		})
+	}
+
+	for i, release := range plan.Releases {
+		activityInput := ApplyReleaseActivityInput{
+			ClusterID: input.ClusterID,
+			Release:   release,
+		}
+
+		var output ApplyReleaseActivityOutput
+
+		err := workflow.ExecuteActivity(applyCtx, ApplyReleaseActivityName, activityInput).Get(ctx, &output)
+		if err != nil {
+			statuses[i].Status = ReleaseStatusFailed
+			statuses[i].Message = err.Error()
+
+			for j := i + 1; j < len(plan.Releases); j++ {
+				statuses[j].Status = ReleaseStatusSkipped
+			}
+
+			message := fmt.Sprintf("release %q failed: %s", release.Name, err.Error())
+
+			// The failed release is restored as well, its previous state is unknown otherwise
+			if rollbackErr := rollback(ctx, input, plan.Releases[:i+1], statuses); rollbackErr != nil {
+				message += "; rollback failed: " + rollbackErr.Error()
+			} else {
+				message += "; application rolled back"
+			}
+
+			// Removed releases are deleted only after a successful reconciliation
+			statuses = append(statuses, plan.Removed...)
+
+			return recordStatus(ctx, input, StatusFailed, message, statuses, err)
+		}
+
+		statuses[i].Status = ReleaseStatusDeployed
+		statuses[i].Revision = output.Revision
+	}
+
+	for i, removed := range plan.Removed {
+		activityInput := DeleteReleaseActivityInput{
+			ClusterID:   input.ClusterID,
+			ReleaseName: removed.Name,
+		}
+
+		err := workflow.ExecuteActivity(ctx, DeleteReleaseActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			// Releases not deleted yet are kept, so that the next reconciliation deletes them
+			statuses = append(statuses, plan.Removed[i:]...)
+
+			message := fmt.Sprintf("failed to delete removed release %q: %s", removed.Name, err.Error())
+
+			return recordStatus(ctx, input, StatusFailed, message, statuses, err)
+		}
+	}
+
+	return recordStatus(ctx, input, StatusReady, "", statuses, nil)
+}
+
+// rollback restores the previous state of the releases in reverse installation order.
+func rollback(ctx workflow.Context, input WorkflowInput, releases []PlannedRelease, statuses []ReleaseStatus) error {
+	var errs []error
+
+	for i := len(releases) - 1; i >= 0; i-- {
+		release := releases[i]
+
+		activityInput := RollbackReleaseActivityInput{
+			ClusterID:   input.ClusterID,
+			ReleaseName: release.Name,
+			Revision:    release.Revision,
+		}
+
+		err := workflow.ExecuteActivity(ctx, RollbackReleaseActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			errs = append(errs, err)
+
+			statuses[i].Status = ReleaseStatusFailed
+			statuses[i].Message = "rollback failed: " + err.Error()
+
+			continue
+		}
+
+		// The failed release keeps its error
+		if statuses[i].Status != ReleaseStatusFailed {
+			statuses[i].Status = ReleaseStatusRolledBack
+		}
+		statuses[i].Revision = release.Revision
+	}
+
+	return errors.Combine(errs...)
+}
+
+// DeleteWorkflow deletes the releases of an application in reverse dependency order, then the application itself.
+func DeleteWorkflow(ctx workflow.Context, input WorkflowInput) error {
+	activityOptions := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+		WaitForCancellation:    true,
+		RetryPolicy: &cadence.RetryPolicy{
+			InitialInterval:    5 * time.Second,
+			BackoffCoefficient: 1.5,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    10,
+		},
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	var plan PlanActivityOutput
+	{
+		activityInput := PlanActivityInput{
+			ClusterID: input.ClusterID,
+			Name:      input.Name,
+			Deletion:  true,
+		}
+
+		err := workflow.ExecuteActivity(ctx, PlanActivityName, activityInput).Get(ctx, &plan)
+		if err != nil {
+			return recordStatus(ctx, input, StatusFailed, err.Error(), nil, err)
+		}
+	}
+
+	var releaseNames []string
+	for i := len(plan.Releases) - 1; i >= 0; i-- {
+		releaseNames = append(releaseNames, plan.Releases[i].Name)
+	}
+	for _, removed := range plan.Removed {
+		releaseNames = append(releaseNames, removed.Name)
+	}
+
+	for _, releaseName := range releaseNames {
+		activityInput := DeleteReleaseActivityInput{
+			ClusterID:   input.ClusterID,
+			ReleaseName: releaseName,
+		}
+
+		err := workflow.ExecuteActivity(ctx, DeleteReleaseActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			message := fmt.Sprintf("failed to delete release %q: %s", releaseName, err.Error())
+
+			return recordStatus(ctx, input, StatusFailed, message, nil, err)
+		}
+	}
+
+	activityInput := RemoveApplicationActivityInput{
+		ClusterID: input.ClusterID,
+		Name:      input.Name,
+	}
+
+	return workflow.ExecuteActivity(ctx, RemoveApplicationActivityName, activityInput).Get(ctx, nil)
+}
+
+func recordStatus(ctx workflow.Context, input WorkflowInput, status string, message string, releases []ReleaseStatus, workflowErr error) error {
+	activityInput := RecordStatusActivityInput{
+		ClusterID: input.ClusterID,
+		Name:      input.Name,
+		Status:    status,
+		Message:   message,
+		Releases:  releases,
+	}
+
+	err := workflow.ExecuteActivity(ctx, RecordStatusActivityName, activityInput).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("failed to record application status", zap.Error(err))
+	}
+
+	return workflowErr
+}
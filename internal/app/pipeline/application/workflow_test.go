@@ -0,0 +1,179 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/testsuite"
+	"go.uber.org/cadence/workflow"
+)
+
+// nolint: gochecknoglobals
+var testWorkflowInput = WorkflowInput{
+	ClusterID: 1,
+	Name:      "shop",
+}
+
+// nolint: gochecknoglobals
+var testPlan = PlanActivityOutput{
+	Releases: []PlannedRelease{
+		{Name: "database", Chart: "stable/mysql", Namespace: "shop", Revision: 4},
+		{Name: "backend", Chart: "stable/backend", Namespace: "shop"},
+		{Name: "frontend", Chart: "stable/nginx", Namespace: "shop", Revision: 2},
+	},
+	Removed: []ReleaseStatus{
+		{Name: "cache", Status: ReleaseStatusDeployed, Revision: 1},
+	},
+}
+
+type WorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env *testsuite.TestWorkflowEnvironment
+}
+
+func TestWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowTestSuite))
+}
+
+func (s *WorkflowTestSuite) SetupSuite() {
+	workflow.RegisterWithOptions(ReconcileWorkflow, workflow.RegisterOptions{Name: ReconcileWorkflowName})
+	workflow.RegisterWithOptions(DeleteWorkflow, workflow.RegisterOptions{Name: DeleteWorkflowName})
+
+	activity.RegisterWithOptions(PlanActivity{}.Execute, activity.RegisterOptions{Name: PlanActivityName})
+	activity.RegisterWithOptions(ApplyReleaseActivity{}.Execute, activity.RegisterOptions{Name: ApplyReleaseActivityName})
+	activity.RegisterWithOptions(RollbackReleaseActivity{}.Execute, activity.RegisterOptions{Name: RollbackReleaseActivityName})
+	activity.RegisterWithOptions(DeleteReleaseActivity{}.Execute, activity.RegisterOptions{Name: DeleteReleaseActivityName})
+	activity.RegisterWithOptions(RecordStatusActivity{}.Execute, activity.RegisterOptions{Name: RecordStatusActivityName})
+	activity.RegisterWithOptions(RemoveApplicationActivity{}.Execute, activity.RegisterOptions{Name: RemoveApplicationActivityName})
+}
+
+func (s *WorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+}
+
+func (s *WorkflowTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *WorkflowTestSuite) onPlan(deletion bool) {
+	s.env.OnActivity(
+		PlanActivityName,
+		mock.Anything,
+		PlanActivityInput{ClusterID: 1, Name: "shop", Deletion: deletion},
+	).Return(testPlan, nil)
+}
+
+func (s *WorkflowTestSuite) onApply(index int, revision int32, err error) {
+	s.env.OnActivity(
+		ApplyReleaseActivityName,
+		mock.Anything,
+		ApplyReleaseActivityInput{ClusterID: 1, Release: testPlan.Releases[index]},
+	).Return(ApplyReleaseActivityOutput{Revision: revision}, err).Once()
+}
+
+func (s *WorkflowTestSuite) onRollback(releaseName string, revision int32) {
+	s.env.OnActivity(
+		RollbackReleaseActivityName,
+		mock.Anything,
+		RollbackReleaseActivityInput{ClusterID: 1, ReleaseName: releaseName, Revision: revision},
+	).Return(nil).Once()
+}
+
+func (s *WorkflowTestSuite) onDelete(releaseName string) {
+	s.env.OnActivity(
+		DeleteReleaseActivityName,
+		mock.Anything,
+		DeleteReleaseActivityInput{ClusterID: 1, ReleaseName: releaseName},
+	).Return(nil).Once()
+}
+
+func (s *WorkflowTestSuite) onRecordStatus(status string, releases []ReleaseStatus) {
+	s.env.OnActivity(
+		RecordStatusActivityName,
+		mock.Anything,
+		mock.MatchedBy(func(input RecordStatusActivityInput) bool {
+			s.Equal(releases, input.Releases)
+
+			return input.ClusterID == 1 && input.Name == "shop" && input.Status == status
+		}),
+	).Return(nil)
+}
+
+func (s *WorkflowTestSuite) Test_Reconcile_Success() {
+	s.onPlan(false)
+	s.onApply(0, 5, nil)
+	s.onApply(1, 1, nil)
+	s.onApply(2, 3, nil)
+	s.onDelete("cache")
+
+	s.onRecordStatus(StatusReady, []ReleaseStatus{
+		{Name: "database", Status: ReleaseStatusDeployed, Revision: 5},
+		{Name: "backend", Status: ReleaseStatusDeployed, Revision: 1},
+		{Name: "frontend", Status: ReleaseStatusDeployed, Revision: 3},
+	})
+
+	s.env.ExecuteWorkflow(ReconcileWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *WorkflowTestSuite) Test_Reconcile_RollsBack() {
+	s.onPlan(false)
+	s.onApply(0, 5, nil)
+	s.onApply(1, 0, errors.New("timed out waiting for the condition"))
+
+	// Releases are restored in reverse order: new releases are deleted
+	s.onRollback("backend", 0)
+	s.onRollback("database", 4)
+
+	s.onRecordStatus(StatusFailed, []ReleaseStatus{
+		{Name: "database", Status: ReleaseStatusRolledBack, Revision: 4},
+		{Name: "backend", Status: ReleaseStatusFailed, Message: "timed out waiting for the condition"},
+		{Name: "frontend", Status: ReleaseStatusSkipped, Revision: 2},
+		{Name: "cache", Status: ReleaseStatusDeployed, Revision: 1},
+	})
+
+	s.env.ExecuteWorkflow(ReconcileWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.EqualError(s.env.GetWorkflowError(), "timed out waiting for the condition")
+}
+
+func (s *WorkflowTestSuite) Test_Delete() {
+	s.onPlan(true)
+	s.onDelete("frontend")
+	s.onDelete("backend")
+	s.onDelete("database")
+	s.onDelete("cache")
+
+	s.env.OnActivity(
+		RemoveApplicationActivityName,
+		mock.Anything,
+		RemoveApplicationActivityInput{ClusterID: 1, Name: "shop"},
+	).Return(nil)
+
+	s.env.ExecuteWorkflow(DeleteWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
@@ -0,0 +1,264 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Pagination defaults.
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Event is a recorded user interaction with the API.
+type Event struct {
+	ID            uint                `json:"id"`
+	Time          time.Time           `json:"time"`
+	CorrelationID string              `json:"correlationId,omitempty"`
+	ClientIP      string              `json:"clientIp"`
+	UserAgent     string              `json:"userAgent"`
+	Path          string              `json:"path"`
+	Method        string              `json:"method"`
+	UserID        uint                `json:"userId"`
+	StatusCode    int                 `json:"statusCode"`
+	Body          json.RawMessage     `json:"body,omitempty"`
+	Headers       map[string][]string `json:"headers,omitempty"`
+	ResponseTime  int                 `json:"responseTime"`
+	ResponseSize  int                 `json:"responseSize"`
+	Errors        json.RawMessage     `json:"errors,omitempty"`
+}
+
+// Filter narrows down the list of events.
+// Zero values are ignored.
+type Filter struct {
+	UserID        uint
+	Since         time.Time
+	Until         time.Time
+	Method        string
+	PathPrefix    string
+	StatusCode    int
+	CorrelationID string
+}
+
+// ListEventsRequest contains the filter and the page of events to list.
+type ListEventsRequest struct {
+	Filter Filter
+	Limit  int
+	Offset int
+}
+
+// EventPage is a page of events.
+type EventPage struct {
+	Events []Event `json:"events"`
+	Total  int     `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
+
+// ExportEventsRequest contains the filter and the format of an export.
+type ExportEventsRequest struct {
+	Filter Filter
+	Format string
+}
+
+//go:generate mga gen kit endpoint --outdir auditlogdriver --with-oc Service
+//go:generate mockery -name Service -inpkg
+type Service interface {
+	// ListEvents lists the audit events of the current organization.
+	ListEvents(ctx context.Context, req ListEventsRequest) (EventPage, error)
+
+	// ExportEvents exports every audit event of the current organization matching a filter.
+	// An export covers at most MaxExportPeriod.
+	ExportEvents(ctx context.Context, req ExportEventsRequest) (Export, error)
+}
+
+// NewService returns a new Service.
+func NewService(orgIDExtractor OrgIDContextExtractor, store Store) Service {
+	return service{
+		orgIDExtractor: orgIDExtractor,
+		store:          store,
+	}
+}
+
+type service struct {
+	orgIDExtractor OrgIDContextExtractor
+	store          Store
+}
+
+// OrgIDContextExtractor extracts an organization ID from a context (if there is any).
+type OrgIDContextExtractor interface {
+	// GetOrganizationID extracts an organization ID from a context (if there is any).
+	GetOrganizationID(ctx context.Context) (uint, bool)
+}
+
+// Store provides access to recorded audit events.
+type Store interface {
+	// Find returns the events of an organization matching a filter ordered by time (newest first).
+	// A non-positive limit returns every matching event.
+	Find(ctx context.Context, organizationID uint, filter Filter, limit int, offset int) ([]Event, error)
+
+	// Stream calls fn for each event of an organization matching a filter ordered by time (newest first)
+	// without loading every event into memory.
+	Stream(ctx context.Context, organizationID uint, filter Filter, fn func(event Event) error) error
+
+	// Count returns the number of events of an organization matching a filter.
+	Count(ctx context.Context, organizationID uint, filter Filter) (int, error)
+
+	// DeleteBefore deletes every event recorded before a given time and returns the number of deleted events.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// ValidationError is returned when a request is invalid.
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// IsBusinessError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (ValidationError) IsBusinessError() bool {
+	return true
+}
+
+func validateFilter(filter Filter) error {
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && filter.Until.Before(filter.Since) {
+		return ValidationError{Message: "the end of the time range must not be before its start"}
+	}
+
+	if filter.Method != "" {
+		switch strings.ToUpper(filter.Method) {
+		case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+			http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		default:
+			return ValidationError{Message: "unknown HTTP method: " + filter.Method}
+		}
+	}
+
+	if filter.StatusCode != 0 && (filter.StatusCode < 100 || filter.StatusCode > 599) {
+		return ValidationError{Message: "invalid HTTP status code"}
+	}
+
+	return nil
+}
+
+func normalizeFilter(filter Filter) Filter {
+	filter.Method = strings.ToUpper(filter.Method)
+
+	return filter
+}
+
+func (s service) organizationID(ctx context.Context) (uint, error) {
+	orgID, ok := s.orgIDExtractor.GetOrganizationID(ctx)
+	if !ok {
+		return 0, errors.New("organization not found in the context")
+	}
+
+	return orgID, nil
+}
+
+func (s service) ListEvents(ctx context.Context, req ListEventsRequest) (EventPage, error) {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	if err := validateFilter(req.Filter); err != nil {
+		return EventPage{}, err
+	}
+
+	if req.Limit < 0 || req.Limit > MaxLimit {
+		return EventPage{}, ValidationError{Message: "limit must be between 1 and 1000"}
+	}
+
+	if req.Offset < 0 {
+		return EventPage{}, ValidationError{Message: "offset must not be negative"}
+	}
+
+	if req.Limit == 0 {
+		req.Limit = DefaultLimit
+	}
+
+	filter := normalizeFilter(req.Filter)
+
+	total, err := s.store.Count(ctx, orgID, filter)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	events, err := s.store.Find(ctx, orgID, filter, req.Limit, req.Offset)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	return EventPage{
+		Events: events,
+		Total:  total,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}, nil
+}
+
+func (s service) ExportEvents(ctx context.Context, req ExportEventsRequest) (Export, error) {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return Export{}, err
+	}
+
+	if err := validateFilter(req.Filter); err != nil {
+		return Export{}, err
+	}
+
+	format := req.Format
+	if format == "" {
+		format = FormatJSONLines
+	}
+
+	if !formats[format] {
+		return Export{}, ValidationError{Message: "unknown export format: " + req.Format}
+	}
+
+	filter := normalizeFilter(req.Filter)
+
+	if filter.Until.IsZero() {
+		filter.Until = time.Now()
+	}
+
+	if filter.Since.IsZero() {
+		filter.Since = filter.Until.Add(-MaxExportPeriod)
+	}
+
+	if filter.Until.Sub(filter.Since) > MaxExportPeriod {
+		return Export{}, ValidationError{Message: "the time range of an export must not be longer than 31 days"}
+	}
+
+	return Export{
+		Format: format,
+		Events: func(fn func(event Event) error) error {
+			return s.store.Stream(ctx, orgID, filter, fn)
+		},
+	}, nil
+}
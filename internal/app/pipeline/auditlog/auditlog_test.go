@@ -0,0 +1,220 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+//go:generate mockery -name Store -inpkg -testonly
+
+type orgIDExtractorStub struct {
+	orgID uint
+}
+
+func (e orgIDExtractorStub) GetOrganizationID(ctx context.Context) (uint, bool) {
+	return e.orgID, e.orgID != 0
+}
+
+func TestService_ListEvents(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(12)
+
+	filter := Filter{
+		UserID:     2,
+		Method:     "post",
+		PathPrefix: "/api/v1/orgs/12/clusters",
+	}
+
+	normalizedFilter := filter
+	normalizedFilter.Method = "POST"
+
+	events := []Event{
+		{ID: 1, Path: "/api/v1/orgs/12/clusters", Method: "POST", UserID: 2},
+	}
+
+	store := new(MockStore)
+	store.On("Count", ctx, orgID, normalizedFilter).Return(11, nil)
+	store.On("Find", ctx, orgID, normalizedFilter, DefaultLimit, 10).Return(events, nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, store)
+
+	page, err := service.ListEvents(ctx, ListEventsRequest{Filter: filter, Offset: 10})
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		EventPage{
+			Events: events,
+			Total:  11,
+			Limit:  DefaultLimit,
+			Offset: 10,
+		},
+		page,
+	)
+
+	store.AssertExpectations(t)
+}
+
+func TestService_ListEvents_Invalid(t *testing.T) {
+	now := time.Now()
+
+	tests := map[string]ListEventsRequest{
+		"invalid time range": {
+			Filter: Filter{Since: now, Until: now.Add(-time.Hour)},
+		},
+		"unknown method": {
+			Filter: Filter{Method: "FETCH"},
+		},
+		"invalid status code": {
+			Filter: Filter{StatusCode: 1000},
+		},
+		"limit too large": {
+			Limit: MaxLimit + 1,
+		},
+		"negative offset": {
+			Offset: -1,
+		},
+	}
+
+	for name, req := range tests {
+		name, req := name, req
+
+		t.Run(name, func(t *testing.T) {
+			store := new(MockStore)
+
+			service := NewService(orgIDExtractorStub{12}, store)
+
+			_, err := service.ListEvents(context.Background(), req)
+			require.Error(t, err)
+
+			assert.True(t, errors.As(err, &ValidationError{}))
+
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_ExportEvents(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(12)
+
+	since := time.Date(2019, time.November, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2019, time.November, 8, 0, 0, 0, 0, time.UTC)
+	filter := Filter{CorrelationID: "abc", Since: since, Until: until}
+
+	events := []Event{
+		{ID: 1, CorrelationID: "abc"},
+		{ID: 2, CorrelationID: "abc"},
+	}
+
+	store := new(MockStore)
+	store.On("Stream", ctx, orgID, filter, mock.Anything).Return(func(_ context.Context, _ uint, _ Filter, fn func(Event) error) error {
+		return EventSlice(events)(fn)
+	})
+
+	service := NewService(orgIDExtractorStub{orgID}, store)
+
+	export, err := service.ExportEvents(ctx, ExportEventsRequest{Filter: filter})
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatJSONLines, export.Format)
+
+	var exported []Event
+
+	err = export.Events(func(event Event) error {
+		exported = append(exported, event)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, events, exported)
+
+	store.AssertExpectations(t)
+}
+
+func TestService_ExportEvents_DefaultTimeRange(t *testing.T) {
+	until := time.Date(2019, time.November, 8, 0, 0, 0, 0, time.UTC)
+
+	store := new(MockStore)
+
+	service := NewService(orgIDExtractorStub{12}, store)
+
+	export, err := service.ExportEvents(context.Background(), ExportEventsRequest{Filter: Filter{Until: until}})
+	require.NoError(t, err)
+
+	store.On("Stream", mock.Anything, uint(12), Filter{Since: until.Add(-MaxExportPeriod), Until: until}, mock.Anything).Return(nil)
+
+	err = export.Events(func(Event) error { return nil })
+	require.NoError(t, err)
+
+	store.AssertExpectations(t)
+}
+
+func TestService_ExportEvents_TimeRangeTooLong(t *testing.T) {
+	until := time.Date(2019, time.November, 8, 0, 0, 0, 0, time.UTC)
+
+	store := new(MockStore)
+
+	service := NewService(orgIDExtractorStub{12}, store)
+
+	_, err := service.ExportEvents(context.Background(), ExportEventsRequest{
+		Filter: Filter{Since: until.Add(-MaxExportPeriod - time.Hour), Until: until},
+	})
+	require.Error(t, err)
+
+	assert.True(t, errors.As(err, &ValidationError{}))
+
+	store.AssertExpectations(t)
+}
+
+func TestService_ExportEvents_UnknownFormat(t *testing.T) {
+	store := new(MockStore)
+
+	service := NewService(orgIDExtractorStub{12}, store)
+
+	_, err := service.ExportEvents(context.Background(), ExportEventsRequest{Format: "xml"})
+	require.Error(t, err)
+
+	assert.True(t, errors.As(err, &ValidationError{}))
+
+	store.AssertExpectations(t)
+}
+
+func TestRetentionJob_Prune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2019, time.November, 8, 10, 0, 0, 0, time.UTC)
+
+	store := new(MockStore)
+	store.On("DeleteBefore", ctx, now.Add(-24*time.Hour)).Return(int64(5), nil)
+
+	job := NewRetentionJob(store, 24*time.Hour, commonadapter.NewNoopLogger(), common.NewNoopErrorHandler())
+
+	err := job.Prune(ctx, now)
+	require.NoError(t, err)
+
+	store.AssertExpectations(t)
+}
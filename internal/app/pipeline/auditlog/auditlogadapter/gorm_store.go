@@ -0,0 +1,225 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlogadapter
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auditlog"
+)
+
+// TableName constants
+const (
+	eventTableName = "audit_events"
+)
+
+// eventModel is a read model of the events recorded by the audit middleware.
+type eventModel struct {
+	ID             uint `gorm:"primary_key"`
+	Time           time.Time
+	CorrelationID  string
+	ClientIP       string
+	UserAgent      string
+	Path           string
+	Method         string
+	UserID         uint
+	OrganizationID uint
+	StatusCode     int
+	Body           *string
+	Headers        string
+	ResponseTime   int
+	ResponseSize   int
+	Errors         *string
+}
+
+// TableName changes the default table name.
+func (eventModel) TableName() string {
+	return eventTableName
+}
+
+// nolint: gochecknoglobals
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// GormStore reads audit events using Gorm.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) GormStore {
+	return GormStore{
+		db: db,
+	}
+}
+
+func (s GormStore) query(organizationID uint, filter auditlog.Filter) *gorm.DB {
+	query := s.db.Model(&eventModel{}).Where("organization_id = ?", organizationID)
+
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if !filter.Since.IsZero() {
+		query = query.Where("time >= ?", filter.Since)
+	}
+
+	if !filter.Until.IsZero() {
+		query = query.Where("time <= ?", filter.Until)
+	}
+
+	if filter.Method != "" {
+		query = query.Where("method = ?", filter.Method)
+	}
+
+	if filter.PathPrefix != "" {
+		query = query.Where("path LIKE ? ESCAPE '!'", likeEscaper.Replace(filter.PathPrefix)+"%")
+	}
+
+	if filter.StatusCode != 0 {
+		query = query.Where("status_code = ?", filter.StatusCode)
+	}
+
+	if filter.CorrelationID != "" {
+		query = query.Where("correlation_id = ?", filter.CorrelationID)
+	}
+
+	return query
+}
+
+// Find returns the events of an organization matching a filter ordered by time (newest first).
+func (s GormStore) Find(
+	ctx context.Context,
+	organizationID uint,
+	filter auditlog.Filter,
+	limit int,
+	offset int,
+) ([]auditlog.Event, error) {
+	query := s.query(organizationID, filter).Order("time DESC").Order("id DESC")
+
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	var models []eventModel
+
+	err := query.Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to find audit events", "organizationId", organizationID)
+	}
+
+	events := make([]auditlog.Event, 0, len(models))
+
+	for _, model := range models {
+		event, err := toEvent(model)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// Stream calls fn for each event of an organization matching a filter ordered by time (newest first).
+// Events are read row by row, so they are never loaded into memory at once.
+func (s GormStore) Stream(ctx context.Context, organizationID uint, filter auditlog.Filter, fn func(event auditlog.Event) error) error {
+	rows, err := s.query(organizationID, filter).Order("time DESC").Order("id DESC").Rows()
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to find audit events", "organizationId", organizationID)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model eventModel
+
+		err := s.db.ScanRows(rows, &model)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to read audit event", "organizationId", organizationID)
+		}
+
+		event, err := toEvent(model)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	return errors.WrapIfWithDetails(rows.Err(), "failed to read audit events", "organizationId", organizationID)
+}
+
+// Count returns the number of events of an organization matching a filter.
+func (s GormStore) Count(ctx context.Context, organizationID uint, filter auditlog.Filter) (int, error) {
+	var count int
+
+	err := s.query(organizationID, filter).Count(&count).Error
+	if err != nil {
+		return 0, errors.WrapIfWithDetails(err, "failed to count audit events", "organizationId", organizationID)
+	}
+
+	return count, nil
+}
+
+// DeleteBefore deletes every event recorded before a given time and returns the number of deleted events.
+func (s GormStore) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.Where("time < ?", before).Delete(&eventModel{})
+	if result.Error != nil {
+		return 0, errors.WrapIfWithDetails(result.Error, "failed to delete audit events", "before", before)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func toEvent(model eventModel) (auditlog.Event, error) {
+	event := auditlog.Event{
+		ID:            model.ID,
+		Time:          model.Time,
+		CorrelationID: model.CorrelationID,
+		ClientIP:      model.ClientIP,
+		UserAgent:     model.UserAgent,
+		Path:          model.Path,
+		Method:        model.Method,
+		UserID:        model.UserID,
+		StatusCode:    model.StatusCode,
+		ResponseTime:  model.ResponseTime,
+		ResponseSize:  model.ResponseSize,
+	}
+
+	if model.Body != nil && *model.Body != "" {
+		event.Body = json.RawMessage(*model.Body)
+	}
+
+	if model.Errors != nil && *model.Errors != "" {
+		event.Errors = json.RawMessage(*model.Errors)
+	}
+
+	if model.Headers != "" {
+		err := json.Unmarshal([]byte(model.Headers), &event.Headers)
+		if err != nil {
+			return event, errors.WrapIfWithDetails(err, "failed to decode audit event headers", "eventId", model.ID)
+		}
+	}
+
+	return event, nil
+}
@@ -0,0 +1,149 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlogadapter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auditlog"
+)
+
+func testGormStore(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(12)
+	now := time.Date(2019, time.November, 8, 10, 0, 0, 0, time.UTC)
+
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&eventModel{}).Error
+	require.NoError(t, err)
+
+	body := `{"name":"100%_secret"}`
+
+	events := []eventModel{
+		{
+			Time:           now.Add(-48 * time.Hour),
+			Path:           "/api/v1/orgs/12/clusters",
+			Method:         http.MethodGet,
+			UserID:         1,
+			OrganizationID: orgID,
+			StatusCode:     http.StatusOK,
+			Headers:        "{}",
+		},
+		{
+			Time:           now.Add(-time.Hour),
+			CorrelationID:  "abc",
+			Path:           "/api/v1/orgs/12/secrets",
+			Method:         http.MethodPost,
+			UserID:         2,
+			OrganizationID: orgID,
+			StatusCode:     http.StatusCreated,
+			Body:           &body,
+			Headers:        `{"secretId":["123"]}`,
+		},
+		{
+			Time:           now,
+			Path:           "/api/v1/orgs/12/clusters/1",
+			Method:         http.MethodDelete,
+			UserID:         2,
+			OrganizationID: orgID,
+			StatusCode:     http.StatusAccepted,
+			Headers:        "{}",
+		},
+		{
+			Time:           now,
+			Path:           "/api/v1/orgs/13/clusters",
+			Method:         http.MethodGet,
+			UserID:         2,
+			OrganizationID: 13,
+			StatusCode:     http.StatusOK,
+			Headers:        "{}",
+		},
+	}
+
+	for i := range events {
+		err := db.Save(&events[i]).Error
+		require.NoError(t, err)
+	}
+
+	store := NewGormStore(db)
+
+	count, err := store.Count(ctx, orgID, auditlog.Filter{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, count)
+
+	found, err := store.Find(ctx, orgID, auditlog.Filter{}, 2, 0)
+	require.NoError(t, err)
+
+	require.Len(t, found, 2)
+	assert.Equal(t, events[2].ID, found[0].ID)
+	assert.Equal(t, events[1].ID, found[1].ID)
+	assert.Equal(t, body, string(found[1].Body))
+	assert.Equal(t, map[string][]string{"secretId": {"123"}}, found[1].Headers)
+
+	found, err = store.Find(ctx, orgID, auditlog.Filter{UserID: 2, PathPrefix: "/api/v1/orgs/12/clusters"}, 0, 0)
+	require.NoError(t, err)
+
+	require.Len(t, found, 1)
+	assert.Equal(t, events[2].ID, found[0].ID)
+
+	found, err = store.Find(ctx, orgID, auditlog.Filter{Since: now.Add(-2 * time.Hour), Until: now.Add(-time.Minute)}, 0, 0)
+	require.NoError(t, err)
+
+	require.Len(t, found, 1)
+	assert.Equal(t, events[1].ID, found[0].ID)
+
+	found, err = store.Find(ctx, orgID, auditlog.Filter{Method: http.MethodPost, StatusCode: http.StatusCreated, CorrelationID: "abc"}, 0, 0)
+	require.NoError(t, err)
+
+	require.Len(t, found, 1)
+	assert.Equal(t, events[1].ID, found[0].ID)
+
+	found, err = store.Find(ctx, orgID, auditlog.Filter{PathPrefix: "/api/v1/orgs/12/%"}, 0, 0)
+	require.NoError(t, err)
+
+	assert.Empty(t, found)
+
+	var streamed []uint
+
+	err = store.Stream(ctx, orgID, auditlog.Filter{UserID: 2}, func(event auditlog.Event) error {
+		streamed = append(streamed, event.ID)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint{events[2].ID, events[1].ID}, streamed)
+
+	deleted, err := store.DeleteBefore(ctx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), deleted)
+
+	count, err = store.Count(ctx, orgID, auditlog.Filter{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, count)
+}
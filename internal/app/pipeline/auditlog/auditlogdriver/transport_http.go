@@ -0,0 +1,190 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlogdriver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auditlog"
+	"github.com/banzaicloud/pipeline/pkg/problems"
+)
+
+// RegisterHTTPHandlers mounts all of the service endpoints into a router.
+func RegisterHTTPHandlers(endpoints Endpoints, router *mux.Router, options ...kithttp.ServerOption) {
+	// Query parameters are validated while decoding requests
+	options = append(options, kithttp.ServerErrorEncoder(encodeHTTPError))
+
+	router.Methods(http.MethodGet).Path("").Handler(kithttp.NewServer(
+		endpoints.ListEvents,
+		decodeListEventsHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("/export").Handler(kithttp.NewServer(
+		endpoints.ExportEvents,
+		decodeExportEventsHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeExportEventsHTTPResponse, errorEncoder),
+		options...,
+	))
+}
+
+func decodeListEventsHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+
+	filter, err := decodeFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := intParam(query, "limit")
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := intParam(query, "offset")
+	if err != nil {
+		return nil, err
+	}
+
+	return auditlog.ListEventsRequest{
+		Filter: filter,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+func decodeExportEventsHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	query := r.URL.Query()
+
+	filter, err := decodeFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return auditlog.ExportEventsRequest{
+		Filter: filter,
+		Format: query.Get("format"),
+	}, nil
+}
+
+func encodeExportEventsHTTPResponse(_ context.Context, w http.ResponseWriter, resp interface{}) error {
+	export := resp.(auditlog.Export)
+
+	w.Header().Set("Content-Type", export.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.FileName()))
+	w.WriteHeader(http.StatusOK)
+
+	return export.Write(w)
+}
+
+func decodeFilter(query url.Values) (auditlog.Filter, error) {
+	userID, err := intParam(query, "userId")
+	if err != nil {
+		return auditlog.Filter{}, err
+	}
+
+	statusCode, err := intParam(query, "statusCode")
+	if err != nil {
+		return auditlog.Filter{}, err
+	}
+
+	since, err := timeParam(query, "since")
+	if err != nil {
+		return auditlog.Filter{}, err
+	}
+
+	until, err := timeParam(query, "until")
+	if err != nil {
+		return auditlog.Filter{}, err
+	}
+
+	return auditlog.Filter{
+		UserID:        uint(userID),
+		Since:         since,
+		Until:         until,
+		Method:        query.Get("method"),
+		PathPrefix:    query.Get("pathPrefix"),
+		StatusCode:    statusCode,
+		CorrelationID: query.Get("correlationId"),
+	}, nil
+}
+
+func intParam(query url.Values, name string) (int, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseUint(raw, 10, 31)
+	if err != nil {
+		return 0, auditlog.ValidationError{Message: fmt.Sprintf("invalid %s query parameter: %q", name, raw)}
+	}
+
+	return int(value), nil
+}
+
+func timeParam(query url.Values, name string) (time.Time, error) {
+	raw := query.Get(name)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, auditlog.ValidationError{
+			Message: fmt.Sprintf("invalid %s query parameter (expected RFC3339 time): %q", name, raw),
+		}
+	}
+
+	return value, nil
+}
+
+func encodeHTTPError(ctx context.Context, err error, w http.ResponseWriter) {
+	_ = errorEncoder(ctx, w, err)
+}
+
+func errorEncoder(_ context.Context, w http.ResponseWriter, e error) error {
+	var problem problems.StatusProblem
+
+	switch {
+	case errors.As(e, &auditlog.ValidationError{}):
+		problem = problems.NewDetailedProblem(http.StatusUnprocessableEntity, e.Error())
+
+	default:
+		problem = problems.NewDetailedProblem(http.StatusInternalServerError, "something went wrong")
+	}
+
+	w.Header().Set("Content-Type", problems.ProblemMediaType)
+	w.WriteHeader(problem.ProblemStatus())
+
+	err := json.NewEncoder(w).Encode(problem)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode error response")
+	}
+
+	return nil
+}
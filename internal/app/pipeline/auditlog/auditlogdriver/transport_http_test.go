@@ -0,0 +1,156 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlogdriver
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auditlog"
+)
+
+func TestRegisterHTTPHandlers_ListEvents(t *testing.T) {
+	expectedPage := auditlog.EventPage{
+		Events: []auditlog.Event{
+			{
+				ID:         1,
+				Time:       time.Date(2019, time.November, 8, 10, 0, 0, 0, time.UTC),
+				Path:       "/api/v1/orgs/1/clusters",
+				Method:     http.MethodPost,
+				UserID:     2,
+				StatusCode: http.StatusAccepted,
+			},
+		},
+		Total:  1,
+		Limit:  10,
+		Offset: 20,
+	}
+
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			ListEvents: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				assert.Equal(
+					t,
+					auditlog.ListEventsRequest{
+						Filter: auditlog.Filter{
+							UserID:     2,
+							Since:      time.Date(2019, time.November, 1, 0, 0, 0, 0, time.UTC),
+							Method:     "post",
+							PathPrefix: "/api/v1/orgs/1/clusters",
+							StatusCode: http.StatusAccepted,
+						},
+						Limit:  10,
+						Offset: 20,
+					},
+					request,
+				)
+
+				return expectedPage, nil
+			},
+		},
+		handler.PathPrefix("/audit").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(
+		ts.URL + "/audit?userId=2&since=2019-11-01T00:00:00Z&method=post&pathPrefix=/api/v1/orgs/1/clusters&statusCode=202&limit=10&offset=20",
+	)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var page auditlog.EventPage
+
+	err = json.NewDecoder(resp.Body).Decode(&page)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedPage, page)
+}
+
+func TestRegisterHTTPHandlers_ListEvents_InvalidQuery(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			ListEvents: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				t.Fatal("the endpoint should not be called")
+
+				return nil, nil
+			},
+		},
+		handler.PathPrefix("/audit").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/audit?since=yesterday")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestRegisterHTTPHandlers_ExportEvents(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			ExportEvents: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				assert.Equal(
+					t,
+					auditlog.ExportEventsRequest{
+						Filter: auditlog.Filter{CorrelationID: "abc"},
+						Format: auditlog.FormatCSV,
+					},
+					request,
+				)
+
+				return auditlog.Export{
+					Format: auditlog.FormatCSV,
+					Events: auditlog.EventSlice([]auditlog.Event{{ID: 1}}),
+				}, nil
+			},
+		},
+		handler.PathPrefix("/audit").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/audit/export?correlationId=abc&format=csv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="audit-events.csv"`, resp.Header.Get("Content-Disposition"))
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), "id,time,correlationId")
+}
@@ -0,0 +1,163 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Export formats.
+const (
+	FormatJSONLines = "jsonl"
+	FormatCSV       = "csv"
+)
+
+// nolint: gochecknoglobals
+var formats = map[string]bool{
+	FormatJSONLines: true,
+	FormatCSV:       true,
+}
+
+// nolint: gochecknoglobals
+var csvHeader = []string{
+	"id",
+	"time",
+	"correlationId",
+	"clientIp",
+	"userAgent",
+	"path",
+	"method",
+	"userId",
+	"statusCode",
+	"body",
+	"headers",
+	"responseTime",
+	"responseSize",
+	"errors",
+}
+
+// MaxExportPeriod is the longest time range a single export can cover.
+const MaxExportPeriod = 31 * 24 * time.Hour
+
+// EventIterator calls fn for each event of an export in order.
+// Iteration stops at the first error returned by fn.
+type EventIterator func(fn func(event Event) error) error
+
+// EventSlice returns an EventIterator iterating over a list of events.
+func EventSlice(events []Event) EventIterator {
+	return func(fn func(event Event) error) error {
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// Export is a set of events in an export format.
+// Events are read while the export is written, so they are never loaded into memory at once.
+type Export struct {
+	Format string
+	Events EventIterator
+}
+
+// ContentType returns the media type of the export.
+func (e Export) ContentType() string {
+	switch e.Format {
+	case FormatCSV:
+		return "text/csv"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+// FileName returns a file name for the export.
+func (e Export) FileName() string {
+	return "audit-events." + e.Format
+}
+
+// Write writes the events to w in the export format.
+func (e Export) Write(w io.Writer) error {
+	switch e.Format {
+	case FormatJSONLines:
+		return writeJSONLines(w, e.Events)
+	case FormatCSV:
+		return writeCSV(w, e.Events)
+	default:
+		return errors.NewWithDetails("unknown export format", "format", e.Format)
+	}
+}
+
+func writeJSONLines(w io.Writer, events EventIterator) error {
+	encoder := json.NewEncoder(w)
+
+	return events(func(event Event) error {
+		return errors.WrapIfWithDetails(encoder.Encode(event), "failed to encode event", "eventId", event.ID)
+	})
+}
+
+func writeCSV(w io.Writer, events EventIterator) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(csvHeader); err != nil {
+		return errors.WrapIf(err, "failed to write CSV header")
+	}
+
+	err := events(func(event Event) error {
+		var headers string
+		if len(event.Headers) > 0 {
+			rawHeaders, err := json.Marshal(event.Headers)
+			if err != nil {
+				return errors.WrapIfWithDetails(err, "failed to encode event headers", "eventId", event.ID)
+			}
+
+			headers = string(rawHeaders)
+		}
+
+		record := []string{
+			strconv.FormatUint(uint64(event.ID), 10),
+			event.Time.UTC().Format(time.RFC3339Nano),
+			event.CorrelationID,
+			event.ClientIP,
+			event.UserAgent,
+			event.Path,
+			event.Method,
+			strconv.FormatUint(uint64(event.UserID), 10),
+			strconv.Itoa(event.StatusCode),
+			string(event.Body),
+			headers,
+			strconv.Itoa(event.ResponseTime),
+			strconv.Itoa(event.ResponseSize),
+			string(event.Errors),
+		}
+
+		return errors.WrapIfWithDetails(writer.Write(record), "failed to write event", "eventId", event.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+
+	return errors.WrapIf(writer.Error(), "failed to write CSV")
+}
@@ -0,0 +1,82 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvents() []Event {
+	return []Event{
+		{
+			ID:            1,
+			Time:          time.Date(2019, time.November, 8, 10, 0, 0, 0, time.UTC),
+			CorrelationID: "abc",
+			ClientIP:      "127.0.0.1",
+			UserAgent:     "curl",
+			Path:          "/api/v1/orgs/1/secrets",
+			Method:        "POST",
+			UserID:        2,
+			StatusCode:    201,
+			Body:          json.RawMessage(`{"name":"secret"}`),
+			Headers:       map[string][]string{"secretId": {"123"}},
+			ResponseTime:  10,
+			ResponseSize:  100,
+		},
+		{
+			ID:     2,
+			Time:   time.Date(2019, time.November, 8, 11, 0, 0, 0, time.UTC),
+			Path:   "/api/v1/orgs/1/clusters",
+			Method: "GET",
+			UserID: 2,
+		},
+	}
+}
+
+func TestExport_Write_JSONLines(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Export{Format: FormatJSONLines, Events: EventSlice(testEvents())}.Write(&buf)
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var event Event
+
+	err = json.Unmarshal(lines[0], &event)
+	require.NoError(t, err)
+
+	assert.Equal(t, testEvents()[0], event)
+}
+
+func TestExport_Write_CSV(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := Export{Format: FormatCSV, Events: EventSlice(testEvents())}.Write(&buf)
+	require.NoError(t, err)
+
+	expected := "id,time,correlationId,clientIp,userAgent,path,method,userId,statusCode,body,headers,responseTime,responseSize,errors\n" +
+		`1,2019-11-08T10:00:00Z,abc,127.0.0.1,curl,/api/v1/orgs/1/secrets,POST,2,201,"{""name"":""secret""}","{""secretId"":[""123""]}",10,100,` + "\n" +
+		"2,2019-11-08T11:00:00Z,,,,/api/v1/orgs/1/clusters,GET,2,0,,,0,0,\n"
+
+	assert.Equal(t, expected, buf.String())
+}
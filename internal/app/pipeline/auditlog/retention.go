@@ -0,0 +1,85 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// EventPruner deletes old audit events.
+type EventPruner interface {
+	// DeleteBefore deletes every event recorded before a given time and returns the number of deleted events.
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// RetentionJob periodically deletes audit events older than the retention period.
+type RetentionJob struct {
+	pruner       EventPruner
+	period       time.Duration
+	logger       Logger
+	errorHandler ErrorHandler
+}
+
+// NewRetentionJob returns a new RetentionJob.
+func NewRetentionJob(pruner EventPruner, period time.Duration, logger Logger, errorHandler ErrorHandler) RetentionJob {
+	return RetentionJob{
+		pruner:       pruner,
+		period:       period,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// Prune deletes the events older than the retention period.
+func (j RetentionJob) Prune(ctx context.Context, now time.Time) error {
+	before := now.Add(-j.period)
+
+	deleted, err := j.pruner.DeleteBefore(ctx, before)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to prune audit events", "before", before)
+	}
+
+	j.logger.Info("pruned audit events", map[string]interface{}{
+		"before":  before,
+		"deleted": deleted,
+	})
+
+	return nil
+}
+
+// Run prunes events with the given interval until the context is cancelled.
+func (j RetentionJob) Run(ctx context.Context, interval time.Duration) {
+	if err := j.Prune(ctx, time.Now()); err != nil {
+		j.errorHandler.Handle(ctx, err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := j.Prune(ctx, now); err != nil {
+				j.errorHandler.Handle(ctx, err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
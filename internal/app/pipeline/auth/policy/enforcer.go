@@ -0,0 +1,135 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+)
+
+// Enforcer checks resource access against the policies of a subject.
+type Enforcer struct {
+	store Store
+}
+
+// NewEnforcer returns a new Enforcer.
+func NewEnforcer(store Store) Enforcer {
+	return Enforcer{
+		store: store,
+	}
+}
+
+// Enforce checks if a user (and the token it authenticated with) may access a resource with verb.
+//
+// User and token policies are evaluated independently and both have to allow access.
+// A subject without policies for the resource type is not restricted.
+func (e Enforcer) Enforce(ctx context.Context, organizationID uint, userID uint, tokenID string, resource brn.ResourceName, verb string) (bool, error) {
+	for _, subject := range subjects(userID, tokenID) {
+		policies, err := e.store.FindSubjectPolicies(ctx, organizationID, subject)
+		if err != nil {
+			return false, errors.WrapIfWithDetails(err, "failed to find policies", "resource", resource.String())
+		}
+
+		if !Allows(policies, resource, verb) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Filter returns the resources a user (and the token it authenticated with) may access with verb.
+//
+// Policies are loaded once for all resources, so listing endpoints should prefer it over calling Enforce for each item.
+func (e Enforcer) Filter(ctx context.Context, organizationID uint, userID uint, tokenID string, resources []brn.ResourceName, verb string) ([]brn.ResourceName, error) {
+	var subjectPolicies [][]Policy
+
+	for _, subject := range subjects(userID, tokenID) {
+		policies, err := e.store.FindSubjectPolicies(ctx, organizationID, subject)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to find policies")
+		}
+
+		subjectPolicies = append(subjectPolicies, policies)
+	}
+
+	allowed := make([]brn.ResourceName, 0, len(resources))
+
+resources:
+	for _, resource := range resources {
+		for _, policies := range subjectPolicies {
+			if !Allows(policies, resource, verb) {
+				continue resources
+			}
+		}
+
+		allowed = append(allowed, resource)
+	}
+
+	return allowed, nil
+}
+
+func subjects(userID uint, tokenID string) []Subject {
+	var subjects []Subject
+
+	if userID != 0 {
+		subjects = append(subjects, Subject{UserID: userID})
+	}
+
+	if tokenID != "" {
+		subjects = append(subjects, Subject{TokenID: tokenID})
+	}
+
+	return subjects
+}
+
+// Allows checks whether a set of policies allows verb on a resource.
+// Policies that do not cover the type of the resource are ignored.
+// Policies only restrict access (the role and token scope checks still apply),
+// so a resource not covered by any of the policies is allowed.
+func Allows(policies []Policy, resource brn.ResourceName, verb string) bool {
+	scoped := false
+
+	for _, policy := range policies {
+		for _, pattern := range policy.Resources {
+			resourceType := brn.PatternResourceType(pattern)
+			if resourceType != brn.Wildcard && resourceType != resource.ResourceType {
+				continue
+			}
+
+			scoped = true
+
+			if brn.Match(pattern, resource) && allowsVerb(policy.Verbs, verb) {
+				return true
+			}
+		}
+	}
+
+	return !scoped
+}
+
+func allowsVerb(verbs []string, verb string) bool {
+	for _, v := range verbs {
+		if v == role.VerbAll || v == verb {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+)
+
+func TestAllows(t *testing.T) {
+	policies := []Policy{
+		{
+			Resources: []string{"brn:12:cluster:5", "brn:12:cluster:6"},
+			Verbs:     []string{role.VerbGet},
+		},
+		{
+			Resources: []string{"brn:12:deployment:5/*"},
+			Verbs:     []string{role.VerbAll},
+		},
+	}
+
+	tests := []struct {
+		resource brn.ResourceName
+		verb     string
+		expected bool
+	}{
+		{brn.New(12, brn.ClusterResourceType, "5"), role.VerbGet, true},
+		{brn.New(12, brn.ClusterResourceType, "6"), role.VerbGet, true},
+		{brn.New(12, brn.ClusterResourceType, "5"), role.VerbDelete, false},
+		{brn.New(12, brn.ClusterResourceType, "7"), role.VerbGet, false},
+		{brn.New(12, brn.DeploymentResourceType, "5/my-release"), role.VerbDelete, true},
+		{brn.New(12, brn.DeploymentResourceType, "7/my-release"), role.VerbGet, false},
+		{brn.New(12, brn.SecretResourceType, "abc"), role.VerbDelete, true},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.resource.String()+"/"+test.verb, func(t *testing.T) {
+			assert.Equal(t, test.expected, Allows(policies, test.resource, test.verb))
+		})
+	}
+}
+
+func TestEnforcer_Enforce(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(12)
+	cluster := brn.New(orgID, brn.ClusterResourceType, "5")
+
+	store := new(MockStore)
+	store.On("FindSubjectPolicies", ctx, orgID, Subject{UserID: 1}).Return(nil, nil)
+	store.On("FindSubjectPolicies", ctx, orgID, Subject{TokenID: "token"}).Return(
+		[]Policy{{Resources: []string{"brn:12:cluster:5"}, Verbs: []string{role.VerbGet}}},
+		nil,
+	)
+
+	enforcer := NewEnforcer(store)
+
+	granted, err := enforcer.Enforce(ctx, orgID, 1, "", cluster, role.VerbDelete)
+	require.NoError(t, err)
+	assert.True(t, granted, "user without policies should not be restricted")
+
+	granted, err = enforcer.Enforce(ctx, orgID, 1, "token", cluster, role.VerbGet)
+	require.NoError(t, err)
+	assert.True(t, granted)
+
+	granted, err = enforcer.Enforce(ctx, orgID, 1, "token", cluster, role.VerbDelete)
+	require.NoError(t, err)
+	assert.False(t, granted, "token policy should restrict the verbs")
+
+	store.AssertExpectations(t)
+}
+
+func TestEnforcer_Filter(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(12)
+
+	store := new(MockStore)
+	store.On("FindSubjectPolicies", ctx, orgID, Subject{UserID: 1}).Return(
+		[]Policy{{Resources: []string{"brn:12:cluster:5", "brn:12:cluster:6"}, Verbs: []string{role.VerbGet}}},
+		nil,
+	)
+	store.On("FindSubjectPolicies", ctx, orgID, Subject{TokenID: "token"}).Return(
+		[]Policy{{Resources: []string{"brn:12:cluster:6", "brn:12:cluster:7"}, Verbs: []string{role.VerbAll}}},
+		nil,
+	)
+
+	enforcer := NewEnforcer(store)
+
+	resources := []brn.ResourceName{
+		brn.New(orgID, brn.ClusterResourceType, "5"),
+		brn.New(orgID, brn.ClusterResourceType, "6"),
+		brn.New(orgID, brn.ClusterResourceType, "7"),
+		brn.New(orgID, brn.SecretResourceType, "abc"),
+	}
+
+	allowed, err := enforcer.Filter(ctx, orgID, 1, "token", resources, role.VerbGet)
+	require.NoError(t, err)
+	assert.Equal(t, []brn.ResourceName{resources[1], resources[3]}, allowed)
+
+	store.AssertExpectations(t)
+}
@@ -0,0 +1,243 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+)
+
+// nolint: gochecknoglobals
+var verbs = map[string]bool{
+	role.VerbGet:    true,
+	role.VerbCreate: true,
+	role.VerbUpdate: true,
+	role.VerbDelete: true,
+	role.VerbAll:    true,
+}
+
+// Policy grants a subject access to a set of resources identified by BRN patterns.
+//
+// Policies scope access: once a subject has a policy covering a resource type,
+// it can only access resources of that type matched by one of its policies.
+// Policies never grant more than the roles of the subject allow.
+type Policy struct {
+	ID        uint      `json:"id"`
+	Subject   Subject   `json:"subject"`
+	Resources []string  `json:"resources"`
+	Verbs     []string  `json:"verbs"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
+
+// Subject is a user or a token a policy applies to.
+// Exactly one of the fields is set.
+type Subject struct {
+	UserID  uint   `json:"userId,omitempty"`
+	TokenID string `json:"tokenId,omitempty"`
+}
+
+// NewPolicyRequest contains the details of a new policy.
+type NewPolicyRequest struct {
+	Subject   Subject  `json:"subject"`
+	Resources []string `json:"resources"`
+	Verbs     []string `json:"verbs"`
+}
+
+//go:generate mga gen kit endpoint --outdir policydriver --with-oc Service
+//go:generate mockery -name Service -inpkg
+type Service interface {
+	// CreatePolicy creates a new policy in the current organization.
+	CreatePolicy(ctx context.Context, policyRequest NewPolicyRequest) (Policy, error)
+
+	// ListPolicies lists the policies of the current organization.
+	ListPolicies(ctx context.Context) ([]Policy, error)
+
+	// GetPolicy returns a single policy of the current organization.
+	GetPolicy(ctx context.Context, id uint) (Policy, error)
+
+	// DeletePolicy deletes a policy of the current organization.
+	DeletePolicy(ctx context.Context, id uint) error
+}
+
+// NewService returns a new Service.
+func NewService(orgIDExtractor OrgIDContextExtractor, store Store) Service {
+	return service{
+		orgIDExtractor: orgIDExtractor,
+		store:          store,
+	}
+}
+
+type service struct {
+	orgIDExtractor OrgIDContextExtractor
+	store          Store
+}
+
+// OrgIDContextExtractor extracts an organization ID from a context (if there is any).
+type OrgIDContextExtractor interface {
+	// GetOrganizationID extracts an organization ID from a context (if there is any).
+	GetOrganizationID(ctx context.Context) (uint, bool)
+}
+
+// Store persists policies.
+type Store interface {
+	// Create creates a new policy.
+	Create(ctx context.Context, organizationID uint, policy Policy) (Policy, error)
+
+	// List lists the policies of an organization.
+	List(ctx context.Context, organizationID uint) ([]Policy, error)
+
+	// Get returns a policy of an organization.
+	// It returns a NotFoundError if the policy cannot be found.
+	Get(ctx context.Context, organizationID uint, id uint) (Policy, error)
+
+	// Delete deletes a policy.
+	// It returns a NotFoundError if the policy cannot be found.
+	Delete(ctx context.Context, organizationID uint, id uint) error
+
+	// FindSubjectPolicies returns the policies of a subject in an organization.
+	FindSubjectPolicies(ctx context.Context, organizationID uint, subject Subject) ([]Policy, error)
+}
+
+// NotFoundError is returned when a policy cannot be found.
+type NotFoundError struct {
+	ID uint
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "policy not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"policyId", e.ID}
+}
+
+// IsBusinessError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (NotFoundError) IsBusinessError() bool {
+	return true
+}
+
+// ValidationError is returned when a policy request is invalid.
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// IsBusinessError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (ValidationError) IsBusinessError() bool {
+	return true
+}
+
+func validatePolicyRequest(organizationID uint, policyRequest NewPolicyRequest) error {
+	if (policyRequest.Subject.UserID == 0) == (policyRequest.Subject.TokenID == "") {
+		return ValidationError{Message: "exactly one of user and token must be set as subject"}
+	}
+
+	if len(policyRequest.Resources) == 0 {
+		return ValidationError{Message: "at least one resource must be specified"}
+	}
+
+	orgID := strconv.FormatUint(uint64(organizationID), 10)
+
+	for _, resource := range policyRequest.Resources {
+		if err := brn.ValidatePattern(resource); err != nil {
+			return ValidationError{Message: fmt.Sprintf("invalid resource name pattern: %q", resource)}
+		}
+
+		if !strings.HasPrefix(resource, brn.SchemePrefix+orgID+":") {
+			return ValidationError{Message: fmt.Sprintf("resource %q does not belong to the organization", resource)}
+		}
+	}
+
+	if len(policyRequest.Verbs) == 0 {
+		return ValidationError{Message: "at least one verb must be specified"}
+	}
+
+	for _, verb := range policyRequest.Verbs {
+		if !verbs[verb] {
+			return ValidationError{Message: fmt.Sprintf("unknown verb: %q", verb)}
+		}
+	}
+
+	return nil
+}
+
+func (s service) organizationID(ctx context.Context) (uint, error) {
+	orgID, ok := s.orgIDExtractor.GetOrganizationID(ctx)
+	if !ok {
+		return 0, errors.New("organization not found in the context")
+	}
+
+	return orgID, nil
+}
+
+func (s service) CreatePolicy(ctx context.Context, policyRequest NewPolicyRequest) (Policy, error) {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	if err := validatePolicyRequest(orgID, policyRequest); err != nil {
+		return Policy{}, err
+	}
+
+	return s.store.Create(ctx, orgID, Policy{
+		Subject:   policyRequest.Subject,
+		Resources: policyRequest.Resources,
+		Verbs:     policyRequest.Verbs,
+	})
+}
+
+func (s service) ListPolicies(ctx context.Context) ([]Policy, error) {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.store.List(ctx, orgID)
+}
+
+func (s service) GetPolicy(ctx context.Context, id uint) (Policy, error) {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return s.store.Get(ctx, orgID, id)
+}
+
+func (s service) DeletePolicy(ctx context.Context, id uint) error {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Delete(ctx, orgID, id)
+}
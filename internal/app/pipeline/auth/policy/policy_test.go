@@ -0,0 +1,126 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+)
+
+//go:generate mockery -name Store -inpkg -testonly
+
+type orgIDExtractorStub struct {
+	orgID uint
+}
+
+func (e orgIDExtractorStub) GetOrganizationID(ctx context.Context) (uint, bool) {
+	return e.orgID, e.orgID != 0
+}
+
+func TestService_CreatePolicy(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(12)
+
+	policyRequest := NewPolicyRequest{
+		Subject:   Subject{TokenID: "b1b6b2a4-3c76-4a1e-9b5c-2a0a7c4c5f1e"},
+		Resources: []string{"brn:12:cluster:*"},
+		Verbs:     []string{role.VerbGet},
+	}
+
+	newPolicy := Policy{
+		Subject:   policyRequest.Subject,
+		Resources: policyRequest.Resources,
+		Verbs:     policyRequest.Verbs,
+	}
+
+	store := new(MockStore)
+	store.On("Create", ctx, orgID, newPolicy).Return(newPolicy, nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, store)
+
+	policy, err := service.CreatePolicy(ctx, policyRequest)
+	require.NoError(t, err)
+
+	assert.Equal(t, newPolicy, policy)
+
+	store.AssertExpectations(t)
+}
+
+func TestService_CreatePolicy_Invalid(t *testing.T) {
+	tests := map[string]NewPolicyRequest{
+		"no subject": {
+			Resources: []string{"brn:12:cluster:*"},
+			Verbs:     []string{role.VerbGet},
+		},
+		"multiple subjects": {
+			Subject:   Subject{UserID: 1, TokenID: "token"},
+			Resources: []string{"brn:12:cluster:*"},
+			Verbs:     []string{role.VerbGet},
+		},
+		"no resources": {
+			Subject: Subject{UserID: 1},
+			Verbs:   []string{role.VerbGet},
+		},
+		"invalid pattern": {
+			Subject:   Subject{UserID: 1},
+			Resources: []string{"cluster:*"},
+			Verbs:     []string{role.VerbGet},
+		},
+		"other organization": {
+			Subject:   Subject{UserID: 1},
+			Resources: []string{"brn:13:cluster:*"},
+			Verbs:     []string{role.VerbGet},
+		},
+		"unknown verb": {
+			Subject:   Subject{UserID: 1},
+			Resources: []string{"brn:12:cluster:*"},
+			Verbs:     []string{"list"},
+		},
+	}
+
+	for name, policyRequest := range tests {
+		name, policyRequest := name, policyRequest
+
+		t.Run(name, func(t *testing.T) {
+			service := NewService(orgIDExtractorStub{12}, new(MockStore))
+
+			_, err := service.CreatePolicy(context.Background(), policyRequest)
+			require.Error(t, err)
+
+			assert.True(t, errors.As(err, &ValidationError{}))
+		})
+	}
+}
+
+func TestService_DeletePolicy(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(12)
+
+	store := new(MockStore)
+	store.On("Delete", ctx, orgID, uint(3)).Return(nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, store)
+
+	err := service.DeletePolicy(ctx, 3)
+	require.NoError(t, err)
+
+	store.AssertExpectations(t)
+}
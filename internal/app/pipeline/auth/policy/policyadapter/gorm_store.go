@@ -0,0 +1,184 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policyadapter
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/policy"
+)
+
+// TableName constants
+const (
+	policyTableName = "authorization_policies"
+)
+
+type policyModel struct {
+	ID             uint `gorm:"primary_key"`
+	CreatedAt      time.Time
+	OrganizationID uint       `gorm:"index:idx_authorization_policies_org_id;not null"`
+	UserID         uint       `gorm:"index:idx_authorization_policies_user_id"`
+	TokenID        string     `gorm:"index:idx_authorization_policies_token_id"`
+	Resources      stringList `sql:"type:text"`
+	Verbs          stringList `sql:"type:text"`
+}
+
+// TableName changes the default table name.
+func (policyModel) TableName() string {
+	return policyTableName
+}
+
+// stringList is a list of strings stored as JSON in SQL databases.
+type stringList []string
+
+// Value implements the driver.Valuer interface.
+func (l stringList) Value() (driver.Value, error) {
+	v, err := json.Marshal(l)
+
+	return string(v), err
+}
+
+// Scan implements the sql.Scanner interface.
+func (l *stringList) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case nil:
+		*l = nil
+
+		return nil
+	default:
+		return errors.NewWithDetails("cannot scan string list", "type", v)
+	}
+}
+
+// GormStore is a policy store using Gorm for data persistence.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) GormStore {
+	return GormStore{
+		db: db,
+	}
+}
+
+func (s GormStore) Create(ctx context.Context, organizationID uint, p policy.Policy) (policy.Policy, error) {
+	model := policyModel{
+		OrganizationID: organizationID,
+		UserID:         p.Subject.UserID,
+		TokenID:        p.Subject.TokenID,
+		Resources:      p.Resources,
+		Verbs:          p.Verbs,
+	}
+
+	err := s.db.Create(&model).Error
+	if err != nil {
+		return policy.Policy{}, errors.WrapIfWithDetails(err, "failed to create policy", "organizationId", organizationID)
+	}
+
+	return toPolicy(model), nil
+}
+
+func (s GormStore) List(ctx context.Context, organizationID uint) ([]policy.Policy, error) {
+	return s.findAll(policyModel{OrganizationID: organizationID})
+}
+
+func (s GormStore) Get(ctx context.Context, organizationID uint, id uint) (policy.Policy, error) {
+	model, err := s.find(organizationID, id)
+	if err != nil {
+		return policy.Policy{}, err
+	}
+
+	return toPolicy(model), nil
+}
+
+func (s GormStore) Delete(ctx context.Context, organizationID uint, id uint) error {
+	model, err := s.find(organizationID, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Delete(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete policy", "policyId", id)
+	}
+
+	return nil
+}
+
+func (s GormStore) FindSubjectPolicies(ctx context.Context, organizationID uint, subject policy.Subject) ([]policy.Policy, error) {
+	if subject.UserID == 0 && subject.TokenID == "" {
+		return nil, nil
+	}
+
+	return s.findAll(policyModel{
+		OrganizationID: organizationID,
+		UserID:         subject.UserID,
+		TokenID:        subject.TokenID,
+	})
+}
+
+func (s GormStore) findAll(query policyModel) ([]policy.Policy, error) {
+	var models []policyModel
+
+	err := s.db.Where(query).Order("id").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list policies", "organizationId", query.OrganizationID)
+	}
+
+	policies := make([]policy.Policy, 0, len(models))
+	for _, model := range models {
+		policies = append(policies, toPolicy(model))
+	}
+
+	return policies, nil
+}
+
+func (s GormStore) find(organizationID uint, id uint) (policyModel, error) {
+	var model policyModel
+
+	err := s.db.Where(policyModel{ID: id, OrganizationID: organizationID}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return model, errors.WithStack(policy.NotFoundError{ID: id})
+	}
+	if err != nil {
+		return model, errors.WrapIfWithDetails(err, "failed to find policy", "policyId", id)
+	}
+
+	return model, nil
+}
+
+func toPolicy(model policyModel) policy.Policy {
+	return policy.Policy{
+		ID: model.ID,
+		Subject: policy.Subject{
+			UserID:  model.UserID,
+			TokenID: model.TokenID,
+		},
+		Resources: model.Resources,
+		Verbs:     model.Verbs,
+		CreatedAt: model.CreatedAt,
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policyadapter
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/policy"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+func testGormStore(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(12)
+
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = Migrate(db, commonadapter.NewNoopLogger())
+	require.NoError(t, err)
+
+	store := NewGormStore(db)
+
+	userPolicy := policy.Policy{
+		Subject:   policy.Subject{UserID: 2},
+		Resources: []string{"brn:12:cluster:*"},
+		Verbs:     []string{"get"},
+	}
+
+	tokenPolicy := policy.Policy{
+		Subject:   policy.Subject{TokenID: "token"},
+		Resources: []string{"brn:12:secret:abc"},
+		Verbs:     []string{"*"},
+	}
+
+	created, err := store.Create(ctx, orgID, userPolicy)
+	require.NoError(t, err)
+
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, userPolicy.Resources, created.Resources)
+
+	_, err = store.Create(ctx, orgID, tokenPolicy)
+	require.NoError(t, err)
+
+	policies, err := store.List(ctx, orgID)
+	require.NoError(t, err)
+
+	assert.Len(t, policies, 2)
+
+	policies, err = store.FindSubjectPolicies(ctx, orgID, policy.Subject{TokenID: "token"})
+	require.NoError(t, err)
+
+	require.Len(t, policies, 1)
+	assert.Equal(t, tokenPolicy.Resources, policies[0].Resources)
+
+	policies, err = store.FindSubjectPolicies(ctx, 13, policy.Subject{UserID: 2})
+	require.NoError(t, err)
+
+	assert.Empty(t, policies)
+
+	err = store.Delete(ctx, orgID, created.ID)
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, orgID, created.ID)
+	assert.True(t, errors.As(err, &policy.NotFoundError{}))
+}
@@ -0,0 +1,133 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policydriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"emperror.dev/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/policy"
+	"github.com/banzaicloud/pipeline/pkg/problems"
+)
+
+// RegisterHTTPHandlers mounts all of the service endpoints into a router.
+func RegisterHTTPHandlers(endpoints Endpoints, router *mux.Router, options ...kithttp.ServerOption) {
+	router.Methods(http.MethodPost).Path("").Handler(kithttp.NewServer(
+		endpoints.CreatePolicy,
+		decodeCreatePolicyHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeCreatePolicyHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("").Handler(kithttp.NewServer(
+		endpoints.ListPolicies,
+		kithttp.NopRequestDecoder,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("/{policyId}").Handler(kithttp.NewServer(
+		endpoints.GetPolicy,
+		decodeGetPolicyHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodDelete).Path("/{policyId}").Handler(kithttp.NewServer(
+		endpoints.DeletePolicy,
+		decodeDeletePolicyHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusNoContent), errorEncoder),
+		options...,
+	))
+}
+
+func decodeCreatePolicyHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var policyRequest policy.NewPolicyRequest
+
+	err := json.NewDecoder(r.Body).Decode(&policyRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	return policyRequest, nil
+}
+
+func encodeCreatePolicyHTTPResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	return kitxhttp.JSONResponseEncoder(ctx, w, kitxhttp.WithStatusCode(resp, http.StatusCreated))
+}
+
+func decodeGetPolicyHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := getPolicyID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return getPolicyRequest{ID: id}, nil
+}
+
+func decodeDeletePolicyHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := getPolicyID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return deletePolicyRequest{ID: id}, nil
+}
+
+func getPolicyID(r *http.Request) (uint, error) {
+	rawID, ok := mux.Vars(r)["policyId"]
+	if !ok || rawID == "" {
+		return 0, errors.NewWithDetails("missing parameter from the URL", "param", "policyId")
+	}
+
+	id, err := strconv.ParseUint(rawID, 10, 32)
+	if err != nil {
+		return 0, errors.WrapIfWithDetails(err, "invalid parameter in the URL", "param", "policyId")
+	}
+
+	return uint(id), nil
+}
+
+func errorEncoder(_ context.Context, w http.ResponseWriter, e error) error {
+	var problem problems.StatusProblem
+
+	switch {
+	case errors.As(e, &policy.NotFoundError{}):
+		problem = problems.NewDetailedProblem(http.StatusNotFound, e.Error())
+
+	case errors.As(e, &policy.ValidationError{}):
+		problem = problems.NewDetailedProblem(http.StatusUnprocessableEntity, e.Error())
+
+	default:
+		problem = problems.NewDetailedProblem(http.StatusInternalServerError, "something went wrong")
+	}
+
+	w.Header().Set("Content-Type", problems.ProblemMediaType)
+	w.WriteHeader(problem.ProblemStatus())
+
+	err := json.NewEncoder(w).Encode(problem)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode error response")
+	}
+
+	return nil
+}
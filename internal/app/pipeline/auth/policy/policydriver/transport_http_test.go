@@ -0,0 +1,98 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policydriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/policy"
+)
+
+func TestRegisterHTTPHandlers_CreatePolicy(t *testing.T) {
+	expectedPolicy := policy.Policy{
+		ID:        1,
+		Subject:   policy.Subject{UserID: 2},
+		Resources: []string{"brn:12:cluster:*"},
+		Verbs:     []string{"get"},
+	}
+
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			CreatePolicy: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return expectedPolicy, nil
+			},
+		},
+		handler.PathPrefix("/policies").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	body, err := json.Marshal(policy.NewPolicyRequest{
+		Subject:   expectedPolicy.Subject,
+		Resources: expectedPolicy.Resources,
+		Verbs:     expectedPolicy.Verbs,
+	})
+	require.NoError(t, err)
+
+	resp, err := ts.Client().Post(ts.URL+"/policies", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var policyResp policy.Policy
+
+	err = json.NewDecoder(resp.Body).Decode(&policyResp)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedPolicy, policyResp)
+}
+
+func TestRegisterHTTPHandlers_DeletePolicy(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			DeletePolicy: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				assert.Equal(t, deletePolicyRequest{ID: 3}, request)
+
+				return nil, nil
+			},
+		},
+		handler.PathPrefix("/policies").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodDelete, ts.URL+"/policies/3", nil)
+	require.NoError(t, err)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
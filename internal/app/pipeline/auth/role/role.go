@@ -0,0 +1,363 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package role
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Resource types that can be granted by custom roles.
+const (
+	ResourceClusters    = "clusters"
+	ResourceSecrets     = "secrets"
+	ResourceDeployments = "deployments"
+	ResourceBackups     = "backups"
+)
+
+// Verbs that can be granted by custom roles.
+const (
+	VerbGet    = "get"
+	VerbCreate = "create"
+	VerbUpdate = "update"
+	VerbDelete = "delete"
+
+	// VerbAll grants every verb on a resource type.
+	VerbAll = "*"
+)
+
+// Built-in role names that cannot be used for custom roles.
+const (
+	builtinRoleAdmin  = "admin"
+	builtinRoleMember = "member"
+)
+
+// nolint: gochecknoglobals
+var resources = map[string]bool{
+	ResourceClusters:    true,
+	ResourceSecrets:     true,
+	ResourceDeployments: true,
+	ResourceBackups:     true,
+}
+
+// nolint: gochecknoglobals
+var verbs = map[string]bool{
+	VerbGet:    true,
+	VerbCreate: true,
+	VerbUpdate: true,
+	VerbDelete: true,
+	VerbAll:    true,
+}
+
+// nolint: gochecknoglobals
+var roleNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// Role is a custom, organization scoped role.
+type Role struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+	Users       []uint       `json:"users"`
+	CreatedAt   time.Time    `json:"createdAt,omitempty"`
+	UpdatedAt   time.Time    `json:"updatedAt,omitempty"`
+}
+
+// Permission grants a set of verbs on a resource type.
+type Permission struct {
+	Resource string   `json:"resource"`
+	Verbs    []string `json:"verbs"`
+}
+
+// Allows checks whether any of the permissions grants verb on a resource type.
+func Allows(permissions []Permission, resource string, verb string) bool {
+	for _, permission := range permissions {
+		if permission.Resource != resource {
+			continue
+		}
+
+		for _, v := range permission.Verbs {
+			if v == VerbAll || v == verb {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// NewRoleRequest contains the details of a new custom role.
+type NewRoleRequest struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+}
+
+// UpdateRoleRequest contains the updatable details of a custom role.
+type UpdateRoleRequest struct {
+	Description string       `json:"description,omitempty"`
+	Permissions []Permission `json:"permissions"`
+}
+
+//go:generate mga gen kit endpoint --outdir roledriver --with-oc Service
+//go:generate mockery -name Service -inpkg
+type Service interface {
+	// CreateRole creates a new custom role in the current organization.
+	CreateRole(ctx context.Context, roleRequest NewRoleRequest) (Role, error)
+
+	// ListRoles lists the custom roles of the current organization.
+	ListRoles(ctx context.Context) ([]Role, error)
+
+	// GetRole returns a single custom role of the current organization.
+	GetRole(ctx context.Context, name string) (Role, error)
+
+	// UpdateRole updates the description and the permissions of a custom role.
+	UpdateRole(ctx context.Context, name string, roleRequest UpdateRoleRequest) (Role, error)
+
+	// DeleteRole deletes a custom role (including its bindings).
+	DeleteRole(ctx context.Context, name string) error
+
+	// BindUser grants a custom role to a member of the current organization.
+	BindUser(ctx context.Context, name string, userID uint) error
+
+	// UnbindUser revokes a custom role from a member of the current organization.
+	UnbindUser(ctx context.Context, name string, userID uint) error
+}
+
+// NewService returns a new Service.
+func NewService(orgIDExtractor OrgIDContextExtractor, store Store) Service {
+	return service{
+		orgIDExtractor: orgIDExtractor,
+		store:          store,
+	}
+}
+
+type service struct {
+	orgIDExtractor OrgIDContextExtractor
+	store          Store
+}
+
+// OrgIDContextExtractor extracts an organization ID from a context (if there is any).
+type OrgIDContextExtractor interface {
+	// GetOrganizationID extracts an organization ID from a context (if there is any).
+	GetOrganizationID(ctx context.Context) (uint, bool)
+}
+
+// Store persists custom roles and their bindings.
+type Store interface {
+	// Create creates a new role.
+	// It returns an AlreadyExistsError if a role with the same name exists in the organization.
+	Create(ctx context.Context, organizationID uint, role Role) (Role, error)
+
+	// List lists the roles of an organization.
+	List(ctx context.Context, organizationID uint) ([]Role, error)
+
+	// Get returns a role of an organization.
+	// It returns a NotFoundError if the role cannot be found.
+	Get(ctx context.Context, organizationID uint, name string) (Role, error)
+
+	// Update updates the description and the permissions of a role.
+	// It returns a NotFoundError if the role cannot be found.
+	Update(ctx context.Context, organizationID uint, role Role) (Role, error)
+
+	// Delete deletes a role and all of its bindings.
+	// It returns a NotFoundError if the role cannot be found.
+	Delete(ctx context.Context, organizationID uint, name string) error
+
+	// BindUser binds a role to a user.
+	// It returns a NotFoundError if the role cannot be found.
+	BindUser(ctx context.Context, organizationID uint, name string, userID uint) error
+
+	// UnbindUser removes a role binding from a user.
+	// It returns a NotFoundError if the role cannot be found.
+	UnbindUser(ctx context.Context, organizationID uint, name string, userID uint) error
+}
+
+// NotFoundError is returned when a role cannot be found.
+type NotFoundError struct {
+	Name string
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "role not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"role", e.Name}
+}
+
+// IsBusinessError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (NotFoundError) IsBusinessError() bool {
+	return true
+}
+
+// AlreadyExistsError is returned when a role already exists.
+type AlreadyExistsError struct {
+	Name string
+}
+
+// Error implements the error interface.
+func (AlreadyExistsError) Error() string {
+	return "role already exists"
+}
+
+// Details returns error details.
+func (e AlreadyExistsError) Details() []interface{} {
+	return []interface{}{"role", e.Name}
+}
+
+// IsBusinessError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (AlreadyExistsError) IsBusinessError() bool {
+	return true
+}
+
+// ValidationError is returned when a role request is invalid.
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// IsBusinessError tells the transport layer whether this error should be translated into the transport format
+// or an internal error should be returned instead.
+func (ValidationError) IsBusinessError() bool {
+	return true
+}
+
+func validatePermissions(permissions []Permission) error {
+	for _, permission := range permissions {
+		if !resources[permission.Resource] {
+			return ValidationError{Message: fmt.Sprintf("unknown resource type: %q", permission.Resource)}
+		}
+
+		if len(permission.Verbs) == 0 {
+			return ValidationError{Message: fmt.Sprintf("no verbs granted for resource type: %q", permission.Resource)}
+		}
+
+		for _, verb := range permission.Verbs {
+			if !verbs[verb] {
+				return ValidationError{Message: fmt.Sprintf("unknown verb: %q", verb)}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s service) organizationID(ctx context.Context) (uint, error) {
+	orgID, ok := s.orgIDExtractor.GetOrganizationID(ctx)
+	if !ok {
+		return 0, errors.New("organization not found in the context")
+	}
+
+	return orgID, nil
+}
+
+func (s service) CreateRole(ctx context.Context, roleRequest NewRoleRequest) (Role, error) {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return Role{}, err
+	}
+
+	if !roleNameRegexp.MatchString(roleRequest.Name) {
+		return Role{}, ValidationError{Message: "role name must consist of lower case alphanumeric characters or '-'"}
+	}
+
+	if roleRequest.Name == builtinRoleAdmin || roleRequest.Name == builtinRoleMember {
+		return Role{}, ValidationError{Message: fmt.Sprintf("%q is a built-in role", roleRequest.Name)}
+	}
+
+	if err := validatePermissions(roleRequest.Permissions); err != nil {
+		return Role{}, err
+	}
+
+	return s.store.Create(ctx, orgID, Role{
+		Name:        roleRequest.Name,
+		Description: roleRequest.Description,
+		Permissions: roleRequest.Permissions,
+	})
+}
+
+func (s service) ListRoles(ctx context.Context) ([]Role, error) {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.store.List(ctx, orgID)
+}
+
+func (s service) GetRole(ctx context.Context, name string) (Role, error) {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return Role{}, err
+	}
+
+	return s.store.Get(ctx, orgID, name)
+}
+
+func (s service) UpdateRole(ctx context.Context, name string, roleRequest UpdateRoleRequest) (Role, error) {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return Role{}, err
+	}
+
+	if err := validatePermissions(roleRequest.Permissions); err != nil {
+		return Role{}, err
+	}
+
+	return s.store.Update(ctx, orgID, Role{
+		Name:        name,
+		Description: roleRequest.Description,
+		Permissions: roleRequest.Permissions,
+	})
+}
+
+func (s service) DeleteRole(ctx context.Context, name string) error {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Delete(ctx, orgID, name)
+}
+
+func (s service) BindUser(ctx context.Context, name string, userID uint) error {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.store.BindUser(ctx, orgID, name, userID)
+}
+
+func (s service) UnbindUser(ctx context.Context, name string, userID uint) error {
+	orgID, err := s.organizationID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.store.UnbindUser(ctx, orgID, name, userID)
+}
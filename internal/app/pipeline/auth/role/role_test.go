@@ -0,0 +1,191 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package role
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockery -name Store -inpkg -testonly
+
+type orgIDExtractorStub struct {
+	orgID uint
+}
+
+func (e orgIDExtractorStub) GetOrganizationID(ctx context.Context) (uint, bool) {
+	return e.orgID, e.orgID != 0
+}
+
+func TestService_CreateRole(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+
+	roleRequest := NewRoleRequest{
+		Name:        "cluster-operator",
+		Description: "Manages clusters",
+		Permissions: []Permission{
+			{
+				Resource: ResourceClusters,
+				Verbs:    []string{VerbAll},
+			},
+		},
+	}
+
+	newRole := Role{
+		Name:        roleRequest.Name,
+		Description: roleRequest.Description,
+		Permissions: roleRequest.Permissions,
+	}
+
+	store := new(MockStore)
+	store.On("Create", ctx, orgID, newRole).Return(newRole, nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, store)
+
+	role, err := service.CreateRole(ctx, roleRequest)
+	require.NoError(t, err)
+
+	assert.Equal(t, newRole, role)
+
+	store.AssertExpectations(t)
+}
+
+func TestService_CreateRole_Invalid(t *testing.T) {
+	tests := map[string]NewRoleRequest{
+		"invalid name": {
+			Name: "Cluster Operator",
+		},
+		"builtin role": {
+			Name: "admin",
+		},
+		"unknown resource": {
+			Name: "operator",
+			Permissions: []Permission{
+				{
+					Resource: "nodes",
+					Verbs:    []string{VerbGet},
+				},
+			},
+		},
+		"unknown verb": {
+			Name: "operator",
+			Permissions: []Permission{
+				{
+					Resource: ResourceClusters,
+					Verbs:    []string{"list"},
+				},
+			},
+		},
+		"no verbs": {
+			Name: "operator",
+			Permissions: []Permission{
+				{
+					Resource: ResourceClusters,
+				},
+			},
+		},
+	}
+
+	for name, roleRequest := range tests {
+		name, roleRequest := name, roleRequest
+
+		t.Run(name, func(t *testing.T) {
+			service := NewService(orgIDExtractorStub{1}, new(MockStore))
+
+			_, err := service.CreateRole(context.Background(), roleRequest)
+			require.Error(t, err)
+
+			assert.True(t, errors.As(err, &ValidationError{}))
+		})
+	}
+}
+
+func TestService_CreateRole_NoOrganization(t *testing.T) {
+	service := NewService(orgIDExtractorStub{}, new(MockStore))
+
+	_, err := service.CreateRole(context.Background(), NewRoleRequest{Name: "operator"})
+	require.Error(t, err)
+}
+
+func TestService_UpdateRole(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+
+	roleRequest := UpdateRoleRequest{
+		Permissions: []Permission{
+			{
+				Resource: ResourceSecrets,
+				Verbs:    []string{VerbGet},
+			},
+		},
+	}
+
+	updatedRole := Role{
+		Name:        "secret-reader",
+		Permissions: roleRequest.Permissions,
+	}
+
+	store := new(MockStore)
+	store.On("Update", ctx, orgID, updatedRole).Return(updatedRole, nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, store)
+
+	role, err := service.UpdateRole(ctx, "secret-reader", roleRequest)
+	require.NoError(t, err)
+
+	assert.Equal(t, updatedRole, role)
+
+	store.AssertExpectations(t)
+}
+
+func TestService_BindUser(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+
+	store := new(MockStore)
+	store.On("BindUser", ctx, orgID, "secret-reader", uint(2)).Return(nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, store)
+
+	err := service.BindUser(ctx, "secret-reader", 2)
+	require.NoError(t, err)
+
+	store.AssertExpectations(t)
+}
+
+func TestAllows(t *testing.T) {
+	permissions := []Permission{
+		{
+			Resource: ResourceClusters,
+			Verbs:    []string{VerbGet, VerbUpdate},
+		},
+		{
+			Resource: ResourceDeployments,
+			Verbs:    []string{VerbAll},
+		},
+	}
+
+	assert.True(t, Allows(permissions, ResourceClusters, VerbGet))
+	assert.True(t, Allows(permissions, ResourceClusters, VerbUpdate))
+	assert.False(t, Allows(permissions, ResourceClusters, VerbDelete))
+	assert.True(t, Allows(permissions, ResourceDeployments, VerbDelete))
+	assert.False(t, Allows(permissions, ResourceSecrets, VerbGet))
+	assert.False(t, Allows(nil, ResourceSecrets, VerbGet))
+}
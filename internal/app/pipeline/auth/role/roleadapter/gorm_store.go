@@ -0,0 +1,310 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roleadapter
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+)
+
+// TableName constants
+const (
+	roleTableName        = "organization_roles"
+	roleBindingTableName = "organization_role_bindings"
+
+	membershipTableName = "user_organizations"
+)
+
+type roleModel struct {
+	ID             uint `gorm:"primary_key"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrganizationID uint               `gorm:"unique_index:idx_organization_roles_org_id_name;not null"`
+	Name           string             `gorm:"unique_index:idx_organization_roles_org_id_name;not null"`
+	Description    string             `sql:"type:text"`
+	Permissions    permissions        `sql:"type:text"`
+	Bindings       []roleBindingModel `gorm:"foreignkey:RoleID"`
+}
+
+// TableName changes the default table name.
+func (roleModel) TableName() string {
+	return roleTableName
+}
+
+type roleBindingModel struct {
+	ID     uint `gorm:"primary_key"`
+	RoleID uint `gorm:"unique_index:idx_organization_role_bindings_role_id_user_id;not null"`
+	UserID uint `gorm:"unique_index:idx_organization_role_bindings_role_id_user_id;not null"`
+}
+
+// TableName changes the default table name.
+func (roleBindingModel) TableName() string {
+	return roleBindingTableName
+}
+
+// permissions is a list of permissions stored as JSON in SQL databases.
+type permissions []role.Permission
+
+// Value implements the driver.Valuer interface.
+func (p permissions) Value() (driver.Value, error) {
+	v, err := json.Marshal(p)
+
+	return string(v), err
+}
+
+// Scan implements the sql.Scanner interface.
+func (p *permissions) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	case nil:
+		*p = nil
+
+		return nil
+	default:
+		return errors.NewWithDetails("cannot scan permissions", "type", v)
+	}
+}
+
+// GormStore is a role store using Gorm for data persistence.
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a new GormStore.
+func NewGormStore(db *gorm.DB) GormStore {
+	return GormStore{
+		db: db,
+	}
+}
+
+func (s GormStore) Create(ctx context.Context, organizationID uint, r role.Role) (role.Role, error) {
+	var count int
+
+	err := s.db.
+		Model(&roleModel{}).
+		Where(roleModel{OrganizationID: organizationID, Name: r.Name}).
+		Count(&count).
+		Error
+	if err != nil {
+		return role.Role{}, errors.WrapIfWithDetails(err, "failed to check role", "role", r.Name)
+	}
+
+	if count > 0 {
+		return role.Role{}, errors.WithStack(role.AlreadyExistsError{Name: r.Name})
+	}
+
+	model := roleModel{
+		OrganizationID: organizationID,
+		Name:           r.Name,
+		Description:    r.Description,
+		Permissions:    r.Permissions,
+	}
+
+	err = s.db.Create(&model).Error
+	if err != nil {
+		return role.Role{}, errors.WrapIfWithDetails(err, "failed to create role", "role", r.Name)
+	}
+
+	return toRole(model), nil
+}
+
+func (s GormStore) List(ctx context.Context, organizationID uint) ([]role.Role, error) {
+	var models []roleModel
+
+	err := s.db.
+		Preload("Bindings").
+		Where(roleModel{OrganizationID: organizationID}).
+		Order("name").
+		Find(&models).
+		Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to list roles", "organizationId", organizationID)
+	}
+
+	roles := make([]role.Role, 0, len(models))
+	for _, model := range models {
+		roles = append(roles, toRole(model))
+	}
+
+	return roles, nil
+}
+
+func (s GormStore) Get(ctx context.Context, organizationID uint, name string) (role.Role, error) {
+	model, err := s.find(organizationID, name)
+	if err != nil {
+		return role.Role{}, err
+	}
+
+	return toRole(model), nil
+}
+
+func (s GormStore) Update(ctx context.Context, organizationID uint, r role.Role) (role.Role, error) {
+	model, err := s.find(organizationID, r.Name)
+	if err != nil {
+		return role.Role{}, err
+	}
+
+	model.Description = r.Description
+	model.Permissions = r.Permissions
+
+	err = s.db.Save(&model).Error
+	if err != nil {
+		return role.Role{}, errors.WrapIfWithDetails(err, "failed to update role", "role", r.Name)
+	}
+
+	return toRole(model), nil
+}
+
+func (s GormStore) Delete(ctx context.Context, organizationID uint, name string) error {
+	model, err := s.find(organizationID, name)
+	if err != nil {
+		return err
+	}
+
+	tx := s.db.Begin()
+	if err := tx.Error; err != nil {
+		return errors.WrapIf(err, "failed to begin transaction")
+	}
+
+	err = tx.Where(roleBindingModel{RoleID: model.ID}).Delete(roleBindingModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete role bindings", "role", name)
+	}
+
+	err = tx.Delete(&model).Error
+	if err != nil {
+		tx.Rollback()
+
+		return errors.WrapIfWithDetails(err, "failed to delete role", "role", name)
+	}
+
+	return errors.WrapIf(tx.Commit().Error, "failed to commit transaction")
+}
+
+func (s GormStore) BindUser(ctx context.Context, organizationID uint, name string, userID uint) error {
+	model, err := s.find(organizationID, name)
+	if err != nil {
+		return err
+	}
+
+	var count int
+
+	err = s.db.
+		Table(membershipTableName).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Count(&count).
+		Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to check organization membership", "userId", userID)
+	}
+
+	if count == 0 {
+		return errors.WithStack(role.ValidationError{Message: "user is not a member of the organization"})
+	}
+
+	binding := roleBindingModel{RoleID: model.ID, UserID: userID}
+
+	err = s.db.Where(binding).FirstOrCreate(&binding).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to bind role", "role", name, "userId", userID)
+	}
+
+	return nil
+}
+
+func (s GormStore) UnbindUser(ctx context.Context, organizationID uint, name string, userID uint) error {
+	model, err := s.find(organizationID, name)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Where(roleBindingModel{RoleID: model.ID, UserID: userID}).Delete(roleBindingModel{}).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to unbind role", "role", name, "userId", userID)
+	}
+
+	return nil
+}
+
+// FindUserPermissions returns the permissions granted to a user by custom roles in an organization.
+func (s GormStore) FindUserPermissions(ctx context.Context, organizationID uint, userID uint) ([]role.Permission, error) {
+	var models []roleModel
+
+	err := s.db.
+		Joins("JOIN "+roleBindingTableName+" ON "+roleBindingTableName+".role_id = "+roleTableName+".id").
+		Where(roleTableName+".organization_id = ? AND "+roleBindingTableName+".user_id = ?", organizationID, userID).
+		Find(&models).
+		Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(
+			err, "failed to find custom roles of user",
+			"organizationId", organizationID,
+			"userId", userID,
+		)
+	}
+
+	var result []role.Permission
+	for _, model := range models {
+		result = append(result, model.Permissions...)
+	}
+
+	return result, nil
+}
+
+func (s GormStore) find(organizationID uint, name string) (roleModel, error) {
+	var model roleModel
+
+	err := s.db.
+		Preload("Bindings").
+		Where(roleModel{OrganizationID: organizationID, Name: name}).
+		First(&model).
+		Error
+	if gorm.IsRecordNotFoundError(err) {
+		return model, errors.WithStack(role.NotFoundError{Name: name})
+	}
+	if err != nil {
+		return model, errors.WrapIfWithDetails(err, "failed to find role", "role", name)
+	}
+
+	return model, nil
+}
+
+func toRole(model roleModel) role.Role {
+	users := make([]uint, 0, len(model.Bindings))
+	for _, binding := range model.Bindings {
+		users = append(users, binding.UserID)
+	}
+
+	return role.Role{
+		Name:        model.Name,
+		Description: model.Description,
+		Permissions: model.Permissions,
+		Users:       users,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+}
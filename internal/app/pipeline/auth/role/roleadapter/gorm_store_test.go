@@ -0,0 +1,106 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roleadapter
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+type membershipModel struct {
+	UserID         uint
+	OrganizationID uint
+}
+
+func (membershipModel) TableName() string {
+	return membershipTableName
+}
+
+func testGormStore(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+	userID := uint(2)
+
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = Migrate(db, commonadapter.NewNoopLogger())
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&membershipModel{}).Error
+	require.NoError(t, err)
+
+	err = db.Save(&membershipModel{UserID: userID, OrganizationID: orgID}).Error
+	require.NoError(t, err)
+
+	store := NewGormStore(db)
+
+	permissions := []role.Permission{
+		{
+			Resource: role.ResourceSecrets,
+			Verbs:    []string{role.VerbGet},
+		},
+	}
+
+	created, err := store.Create(ctx, orgID, role.Role{Name: "secret-reader", Permissions: permissions})
+	require.NoError(t, err)
+
+	assert.Equal(t, "secret-reader", created.Name)
+	assert.Equal(t, permissions, created.Permissions)
+
+	_, err = store.Create(ctx, orgID, role.Role{Name: "secret-reader"})
+	assert.True(t, errors.As(err, &role.AlreadyExistsError{}))
+
+	err = store.BindUser(ctx, orgID, "secret-reader", userID)
+	require.NoError(t, err)
+
+	err = store.BindUser(ctx, orgID, "secret-reader", 3)
+	assert.True(t, errors.As(err, &role.ValidationError{}))
+
+	r, err := store.Get(ctx, orgID, "secret-reader")
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint{userID}, r.Users)
+
+	userPermissions, err := store.FindUserPermissions(ctx, orgID, userID)
+	require.NoError(t, err)
+
+	assert.Equal(t, permissions, userPermissions)
+
+	userPermissions, err = store.FindUserPermissions(ctx, 3, userID)
+	require.NoError(t, err)
+
+	assert.Empty(t, userPermissions)
+
+	err = store.Delete(ctx, orgID, "secret-reader")
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, orgID, "secret-reader")
+	assert.True(t, errors.As(err, &role.NotFoundError{}))
+
+	userPermissions, err = store.FindUserPermissions(ctx, orgID, userID)
+	require.NoError(t, err)
+
+	assert.Empty(t, userPermissions)
+}
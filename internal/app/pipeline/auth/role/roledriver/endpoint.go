@@ -0,0 +1,99 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roledriver
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+)
+
+func MakeCreateRoleEndpoint(service role.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		return service.CreateRole(ctx, req.(role.NewRoleRequest))
+	})
+}
+
+func MakeListRolesEndpoint(service role.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, _ interface{}) (interface{}, error) {
+		return service.ListRoles(ctx)
+	})
+}
+
+type getRoleRequest struct {
+	Name string
+}
+
+func MakeGetRoleEndpoint(service role.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(getRoleRequest)
+
+		return service.GetRole(ctx, r.Name)
+	})
+}
+
+type updateRoleRequest struct {
+	Name        string
+	RoleRequest role.UpdateRoleRequest
+}
+
+func MakeUpdateRoleEndpoint(service role.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(updateRoleRequest)
+
+		return service.UpdateRole(ctx, r.Name, r.RoleRequest)
+	})
+}
+
+type deleteRoleRequest struct {
+	Name string
+}
+
+func MakeDeleteRoleEndpoint(service role.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(deleteRoleRequest)
+
+		return nil, service.DeleteRole(ctx, r.Name)
+	})
+}
+
+type bindUserRequest struct {
+	Name   string
+	UserID uint
+}
+
+func MakeBindUserEndpoint(service role.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(bindUserRequest)
+
+		return nil, service.BindUser(ctx, r.Name, r.UserID)
+	})
+}
+
+type unbindUserRequest struct {
+	Name   string
+	UserID uint
+}
+
+func MakeUnbindUserEndpoint(service role.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(unbindUserRequest)
+
+		return nil, service.UnbindUser(ctx, r.Name, r.UserID)
+	})
+}
@@ -0,0 +1,210 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roledriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"emperror.dev/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/pkg/problems"
+)
+
+// RegisterHTTPHandlers mounts all of the service endpoints into a router.
+func RegisterHTTPHandlers(endpoints Endpoints, router *mux.Router, options ...kithttp.ServerOption) {
+	router.Methods(http.MethodPost).Path("").Handler(kithttp.NewServer(
+		endpoints.CreateRole,
+		decodeCreateRoleHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeCreateRoleHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("").Handler(kithttp.NewServer(
+		endpoints.ListRoles,
+		kithttp.NopRequestDecoder,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodGet).Path("/{name}").Handler(kithttp.NewServer(
+		endpoints.GetRole,
+		decodeGetRoleHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPut).Path("/{name}").Handler(kithttp.NewServer(
+		endpoints.UpdateRole,
+		decodeUpdateRoleHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.JSONResponseEncoder, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodDelete).Path("/{name}").Handler(kithttp.NewServer(
+		endpoints.DeleteRole,
+		decodeDeleteRoleHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusNoContent), errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPut).Path("/{name}/users/{userId}").Handler(kithttp.NewServer(
+		endpoints.BindUser,
+		decodeBindUserHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusNoContent), errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodDelete).Path("/{name}/users/{userId}").Handler(kithttp.NewServer(
+		endpoints.UnbindUser,
+		decodeUnbindUserHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusNoContent), errorEncoder),
+		options...,
+	))
+}
+
+func decodeCreateRoleHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var roleRequest role.NewRoleRequest
+
+	err := json.NewDecoder(r.Body).Decode(&roleRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	return roleRequest, nil
+}
+
+func encodeCreateRoleHTTPResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	return kitxhttp.JSONResponseEncoder(ctx, w, kitxhttp.WithStatusCode(resp, http.StatusCreated))
+}
+
+func decodeGetRoleHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	name, err := getName(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return getRoleRequest{Name: name}, nil
+}
+
+func decodeUpdateRoleHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	name, err := getName(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var roleRequest role.UpdateRoleRequest
+
+	err = json.NewDecoder(r.Body).Decode(&roleRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	return updateRoleRequest{Name: name, RoleRequest: roleRequest}, nil
+}
+
+func decodeDeleteRoleHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	name, err := getName(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return deleteRoleRequest{Name: name}, nil
+}
+
+func decodeBindUserHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	name, err := getName(r)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := getUserID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return bindUserRequest{Name: name, UserID: userID}, nil
+}
+
+func decodeUnbindUserHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	name, err := getName(r)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := getUserID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return unbindUserRequest{Name: name, UserID: userID}, nil
+}
+
+func getName(r *http.Request) (string, error) {
+	name, ok := mux.Vars(r)["name"]
+	if !ok || name == "" {
+		return "", errors.NewWithDetails("missing parameter from the URL", "param", "name")
+	}
+
+	return name, nil
+}
+
+func getUserID(r *http.Request) (uint, error) {
+	rawUserID, ok := mux.Vars(r)["userId"]
+	if !ok || rawUserID == "" {
+		return 0, errors.NewWithDetails("missing parameter from the URL", "param", "userId")
+	}
+
+	userID, err := strconv.ParseUint(rawUserID, 10, 32)
+	if err != nil {
+		return 0, errors.WrapIfWithDetails(err, "invalid parameter in the URL", "param", "userId")
+	}
+
+	return uint(userID), nil
+}
+
+func errorEncoder(_ context.Context, w http.ResponseWriter, e error) error {
+	var problem problems.StatusProblem
+
+	switch {
+	case errors.As(e, &role.NotFoundError{}):
+		problem = problems.NewDetailedProblem(http.StatusNotFound, e.Error())
+
+	case errors.As(e, &role.AlreadyExistsError{}):
+		problem = problems.NewDetailedProblem(http.StatusConflict, e.Error())
+
+	case errors.As(e, &role.ValidationError{}):
+		problem = problems.NewDetailedProblem(http.StatusUnprocessableEntity, e.Error())
+
+	default:
+		problem = problems.NewDetailedProblem(http.StatusInternalServerError, "something went wrong")
+	}
+
+	w.Header().Set("Content-Type", problems.ProblemMediaType)
+	w.WriteHeader(problem.ProblemStatus())
+
+	err := json.NewEncoder(w).Encode(problem)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode error response")
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package roledriver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/sagikazarmark/kitx/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+)
+
+func TestRegisterHTTPHandlers_CreateRole(t *testing.T) {
+	expectedRole := role.Role{
+		Name: "cluster-operator",
+		Permissions: []role.Permission{
+			{
+				Resource: role.ResourceClusters,
+				Verbs:    []string{role.VerbAll},
+			},
+		},
+		Users: []uint{},
+	}
+
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			CreateRole: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return expectedRole, nil
+			},
+		},
+		handler.PathPrefix("/roles").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	body, err := json.Marshal(role.NewRoleRequest{
+		Name:        expectedRole.Name,
+		Permissions: expectedRole.Permissions,
+	})
+	require.NoError(t, err)
+
+	resp, err := ts.Client().Post(ts.URL+"/roles", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var roleResp role.Role
+
+	err = json.NewDecoder(resp.Body).Decode(&roleResp)
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedRole, roleResp)
+}
+
+func TestRegisterHTTPHandlers_GetRole_NotFound(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			GetRole: endpoint.BusinessErrorMiddleware(func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return nil, role.NotFoundError{Name: request.(getRoleRequest).Name}
+			}),
+		},
+		handler.PathPrefix("/roles").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/roles/cluster-operator")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRegisterHTTPHandlers_BindUser(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			BindUser: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				assert.Equal(t, bindUserRequest{Name: "cluster-operator", UserID: 2}, request)
+
+				return nil, nil
+			},
+		},
+		handler.PathPrefix("/roles").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodPut, ts.URL+"/roles/cluster-operator/users/2", nil)
+	require.NoError(t, err)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
@@ -24,10 +24,31 @@ import (
 
 // Token represents an access token.
 type Token struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
-	CreatedAt time.Time  `json:"createdAt,omitempty"`
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	Scope      *Scope     `json:"scope,omitempty"`
+}
+
+// Scope restricts the permissions of an access token.
+// A token without a scope carries all permissions of its owner.
+type Scope struct {
+	// OrganizationID restricts the token to a single organization.
+	OrganizationID uint `json:"organizationId,omitempty"`
+
+	// ClusterIDs restricts the token to a set of clusters within the organization.
+	ClusterIDs []uint `json:"clusterIds,omitempty"`
+
+	// ReadOnly restricts the token to read-only requests.
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// Metadata contains additional information about an access token.
+type Metadata struct {
+	Scope      *Scope
+	LastUsedAt *time.Time
 }
 
 // Service provides access to personal access tokens.
@@ -52,6 +73,7 @@ type NewTokenRequest struct {
 	Name        string     `json:"name,omitempty"`
 	VirtualUser string     `json:"virtualUser,omitempty"`
 	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	Scope       *Scope     `json:"scope,omitempty"`
 }
 
 // NewToken contains a generated token.
@@ -61,22 +83,29 @@ type NewToken struct {
 }
 
 // NewService returns a new Service.
+// Every new token has to expire within maxLifetime.
 func NewService(
 	userExtractor UserExtractor,
 	store Store,
+	metadataStore MetadataStore,
 	generator Generator,
+	maxLifetime time.Duration,
 ) Service {
 	return service{
 		userExtractor: userExtractor,
 		store:         store,
+		metadataStore: metadataStore,
 		generator:     generator,
+		maxLifetime:   maxLifetime,
 	}
 }
 
 type service struct {
 	userExtractor UserExtractor
 	store         Store
+	metadataStore MetadataStore
 	generator     Generator
+	maxLifetime   time.Duration
 }
 
 // UserExtractor extracts user information from the context.
@@ -105,6 +134,19 @@ type Store interface {
 	Revoke(ctx context.Context, userID string, tokenID string) error
 }
 
+// MetadataStore persists the scope and usage information of access tokens.
+type MetadataStore interface {
+	// SaveScope saves the scope of a token.
+	SaveScope(ctx context.Context, tokenID string, scope Scope) error
+
+	// FindMetadata returns the metadata of a set of tokens.
+	// Tokens without metadata are omitted from the result.
+	FindMetadata(ctx context.Context, tokenIDs []string) (map[string]Metadata, error)
+
+	// DeleteMetadata deletes the metadata of a token.
+	DeleteMetadata(ctx context.Context, tokenID string) error
+}
+
 // NotFoundError is returned if a token cannot be found.
 type NotFoundError struct {
 	ID string
@@ -125,6 +167,21 @@ func (e NotFoundError) IsBusinessError() bool {
 	return true
 }
 
+// ValidationError is returned when a token request is invalid.
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// IsBusinessError tells the transport layer to return this error to the client.
+func (ValidationError) IsBusinessError() bool {
+	return true
+}
+
 // Generator generates a token.
 type Generator interface {
 	// GenerateToken generates a token.
@@ -154,6 +211,16 @@ func (s service) CreateToken(ctx context.Context, tokenRequest NewTokenRequest)
 		tokenRequest.Name = "generated"
 	}
 
+	if err := validateExpiry(tokenRequest.ExpiresAt, s.maxLifetime); err != nil {
+		return NewToken{}, err
+	}
+
+	if tokenRequest.Scope != nil {
+		if err := validateScope(*tokenRequest.Scope); err != nil {
+			return NewToken{}, err
+		}
+	}
+
 	sub := fmt.Sprint(userID)
 	tokenType := CICDUserTokenType
 
@@ -163,12 +230,7 @@ func (s service) CreateToken(ctx context.Context, tokenRequest NewTokenRequest)
 		tokenType = CICDHookTokenType
 	}
 
-	expiresAt := int64(0)
-	if tokenRequest.ExpiresAt != nil {
-		expiresAt = tokenRequest.ExpiresAt.Unix()
-	}
-
-	tokenID, signedToken, err := s.generator.GenerateToken(sub, expiresAt, tokenType, userLogin)
+	tokenID, signedToken, err := s.generator.GenerateToken(sub, tokenRequest.ExpiresAt.Unix(), tokenType, userLogin)
 	if err != nil {
 		return NewToken{}, err
 	}
@@ -178,6 +240,13 @@ func (s service) CreateToken(ctx context.Context, tokenRequest NewTokenRequest)
 		return NewToken{}, err
 	}
 
+	if tokenRequest.Scope != nil {
+		err = s.metadataStore.SaveScope(ctx, tokenID, *tokenRequest.Scope)
+		if err != nil {
+			return NewToken{}, err
+		}
+	}
+
 	return NewToken{
 		ID:    tokenID,
 		Token: signedToken,
@@ -190,7 +259,26 @@ func (s service) ListTokens(ctx context.Context) ([]Token, error) {
 		return nil, errors.New("user not found in the context")
 	}
 
-	return s.store.List(ctx, fmt.Sprint(userID))
+	tokens, err := s.store.List(ctx, fmt.Sprint(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	tokenIDs := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		tokenIDs = append(tokenIDs, t.ID)
+	}
+
+	metadata, err := s.metadataStore.FindMetadata(ctx, tokenIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, t := range tokens {
+		tokens[i] = withMetadata(t, metadata)
+	}
+
+	return tokens, nil
 }
 
 func (s service) GetToken(ctx context.Context, id string) (Token, error) {
@@ -199,7 +287,17 @@ func (s service) GetToken(ctx context.Context, id string) (Token, error) {
 		return Token{}, errors.New("user not found in the context")
 	}
 
-	return s.store.Lookup(ctx, fmt.Sprint(userID), id)
+	t, err := s.store.Lookup(ctx, fmt.Sprint(userID), id)
+	if err != nil {
+		return Token{}, err
+	}
+
+	metadata, err := s.metadataStore.FindMetadata(ctx, []string{t.ID})
+	if err != nil {
+		return Token{}, err
+	}
+
+	return withMetadata(t, metadata), nil
 }
 
 func (s service) DeleteToken(ctx context.Context, id string) error {
@@ -208,5 +306,45 @@ func (s service) DeleteToken(ctx context.Context, id string) error {
 		return errors.New("user not found in the context")
 	}
 
-	return s.store.Revoke(ctx, fmt.Sprint(userID), id)
+	err := s.store.Revoke(ctx, fmt.Sprint(userID), id)
+	if err != nil {
+		return err
+	}
+
+	return s.metadataStore.DeleteMetadata(ctx, id)
+}
+
+func validateExpiry(expiresAt *time.Time, maxLifetime time.Duration) error {
+	if expiresAt == nil {
+		return ValidationError{Message: "tokens must have an expiration time"}
+	}
+
+	now := time.Now()
+
+	if !expiresAt.After(now) {
+		return ValidationError{Message: "expiration time must be in the future"}
+	}
+
+	if maxLifetime > 0 && expiresAt.After(now.Add(maxLifetime)) {
+		return ValidationError{Message: fmt.Sprintf("expiration time must be within %s", maxLifetime)}
+	}
+
+	return nil
+}
+
+func validateScope(scope Scope) error {
+	if len(scope.ClusterIDs) > 0 && scope.OrganizationID == 0 {
+		return ValidationError{Message: "cluster scoped tokens must be scoped to an organization"}
+	}
+
+	return nil
+}
+
+func withMetadata(t Token, metadata map[string]Metadata) Token {
+	if m, ok := metadata[t.ID]; ok {
+		t.Scope = m.Scope
+		t.LastUsedAt = m.LastUsedAt
+	}
+
+	return t
 }
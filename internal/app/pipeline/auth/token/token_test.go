@@ -27,8 +27,11 @@ import (
 
 //go:generate mockery -name UserExtractor -inpkg -testonly
 //go:generate mockery -name Store -inpkg -testonly
+//go:generate mockery -name MetadataStore -inpkg -testonly
 //go:generate mockery -name Generator -inpkg -testonly
 
+const maxLifetime = 24 * time.Hour
+
 func TestService_CreateToken(t *testing.T) {
 	ctx := context.Background()
 	userID := uint(1)
@@ -36,10 +39,11 @@ func TestService_CreateToken(t *testing.T) {
 	userLogin := "john.doe"
 	tokenID := "id"
 	tokenValue := "token"
+	expiresAt := time.Now().Add(time.Hour)
 
 	tokenRequest := NewTokenRequest{
 		Name:      "tokenName",
-		ExpiresAt: nil,
+		ExpiresAt: &expiresAt,
 	}
 
 	userExtractor := new(MockUserExtractor)
@@ -54,10 +58,12 @@ func TestService_CreateToken(t *testing.T) {
 	store := new(MockStore)
 	store.On("Store", ctx, userIDString, tokenID, tokenRequest.Name, tokenRequest.ExpiresAt).Return(nil)
 
+	metadataStore := new(MockMetadataStore)
+
 	generator := new(MockGenerator)
-	generator.On("GenerateToken", userIDString, int64(0), CICDUserTokenType, userLogin).Return(tokenID, tokenValue, nil)
+	generator.On("GenerateToken", userIDString, expiresAt.Unix(), CICDUserTokenType, userLogin).Return(tokenID, tokenValue, nil)
 
-	service := NewService(userExtractor, store, generator)
+	service := NewService(userExtractor, store, metadataStore, generator, maxLifetime)
 
 	newToken, err := service.CreateToken(ctx, tokenRequest)
 	require.NoError(t, err)
@@ -66,6 +72,7 @@ func TestService_CreateToken(t *testing.T) {
 
 	userExtractor.AssertExpectations(t)
 	store.AssertExpectations(t)
+	metadataStore.AssertExpectations(t)
 	generator.AssertExpectations(t)
 }
 
@@ -76,10 +83,11 @@ func TestService_CreateToken_DefaultName(t *testing.T) {
 	userLogin := "john.doe"
 	tokenID := "id"
 	tokenValue := "token"
+	expiresAt := time.Now().Add(time.Hour)
 
 	tokenRequest := NewTokenRequest{
 		Name:      "",
-		ExpiresAt: nil,
+		ExpiresAt: &expiresAt,
 	}
 
 	userExtractor := new(MockUserExtractor)
@@ -94,10 +102,12 @@ func TestService_CreateToken_DefaultName(t *testing.T) {
 	store := new(MockStore)
 	store.On("Store", ctx, userIDString, tokenID, "generated", tokenRequest.ExpiresAt).Return(nil)
 
+	metadataStore := new(MockMetadataStore)
+
 	generator := new(MockGenerator)
-	generator.On("GenerateToken", userIDString, int64(0), CICDUserTokenType, userLogin).Return(tokenID, tokenValue, nil)
+	generator.On("GenerateToken", userIDString, expiresAt.Unix(), CICDUserTokenType, userLogin).Return(tokenID, tokenValue, nil)
 
-	service := NewService(userExtractor, store, generator)
+	service := NewService(userExtractor, store, metadataStore, generator, maxLifetime)
 
 	newToken, err := service.CreateToken(ctx, tokenRequest)
 	require.NoError(t, err)
@@ -106,20 +116,115 @@ func TestService_CreateToken_DefaultName(t *testing.T) {
 
 	userExtractor.AssertExpectations(t)
 	store.AssertExpectations(t)
+	metadataStore.AssertExpectations(t)
+	generator.AssertExpectations(t)
+}
+
+func TestService_CreateToken_Scoped(t *testing.T) {
+	ctx := context.Background()
+	userID := uint(1)
+	userIDString := fmt.Sprint(userID)
+	userLogin := "john.doe"
+	tokenID := "id"
+	tokenValue := "token"
+	expiresAt := time.Now().Add(time.Hour)
+
+	tokenRequest := NewTokenRequest{
+		Name:      "ci",
+		ExpiresAt: &expiresAt,
+		Scope: &Scope{
+			OrganizationID: 1,
+			ClusterIDs:     []uint{2, 3},
+			ReadOnly:       true,
+		},
+	}
+
+	userExtractor := new(MockUserExtractor)
+	userExtractor.On("GetUserID", ctx).Return(userID, true)
+	userExtractor.On("GetUserLogin", ctx).Return(userLogin, true)
+
+	store := new(MockStore)
+	store.On("Store", ctx, userIDString, tokenID, tokenRequest.Name, tokenRequest.ExpiresAt).Return(nil)
+
+	metadataStore := new(MockMetadataStore)
+	metadataStore.On("SaveScope", ctx, tokenID, *tokenRequest.Scope).Return(nil)
+
+	generator := new(MockGenerator)
+	generator.On("GenerateToken", userIDString, expiresAt.Unix(), CICDUserTokenType, userLogin).Return(tokenID, tokenValue, nil)
+
+	service := NewService(userExtractor, store, metadataStore, generator, maxLifetime)
+
+	newToken, err := service.CreateToken(ctx, tokenRequest)
+	require.NoError(t, err)
+
+	assert.Equal(t, NewToken{ID: tokenID, Token: tokenValue}, newToken)
+
+	userExtractor.AssertExpectations(t)
+	store.AssertExpectations(t)
+	metadataStore.AssertExpectations(t)
 	generator.AssertExpectations(t)
 }
 
+func TestService_CreateToken_Invalid(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	expiredAt := time.Now().Add(-time.Hour)
+	tooLateAt := time.Now().Add(maxLifetime + time.Hour)
+
+	tests := map[string]NewTokenRequest{
+		"no expiration": {
+			Name: "tokenName",
+		},
+		"scoped without expiration": {
+			Scope: &Scope{ReadOnly: true},
+		},
+		"virtual user without expiration": {
+			VirtualUser: "virtualUser",
+		},
+		"expiration beyond max lifetime": {
+			ExpiresAt: &tooLateAt,
+		},
+		"expired": {
+			ExpiresAt: &expiredAt,
+			Scope:     &Scope{ReadOnly: true},
+		},
+		"clusters without organization": {
+			ExpiresAt: &expiresAt,
+			Scope:     &Scope{ClusterIDs: []uint{1}},
+		},
+	}
+
+	for name, tokenRequest := range tests {
+		name, tokenRequest := name, tokenRequest
+
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			userExtractor := new(MockUserExtractor)
+			userExtractor.On("GetUserID", ctx).Return(uint(1), true)
+			userExtractor.On("GetUserLogin", ctx).Return("john.doe", true)
+
+			service := NewService(userExtractor, new(MockStore), new(MockMetadataStore), new(MockGenerator), maxLifetime)
+
+			_, err := service.CreateToken(ctx, tokenRequest)
+			require.Error(t, err)
+
+			assert.True(t, errors.As(err, &ValidationError{}))
+		})
+	}
+}
+
 func TestService_VirtualUser(t *testing.T) {
 	ctx := context.Background()
 	userID := "virtualUser"
 	userLogin := "john.doe"
 	tokenID := "id"
 	tokenValue := "token"
+	expiresAt := time.Now().Add(time.Hour)
 
 	tokenRequest := NewTokenRequest{
 		Name:        "tokenName",
 		VirtualUser: "virtualUser",
-		ExpiresAt:   nil,
+		ExpiresAt:   &expiresAt,
 	}
 
 	userExtractor := new(MockUserExtractor)
@@ -134,10 +239,12 @@ func TestService_VirtualUser(t *testing.T) {
 	store := new(MockStore)
 	store.On("Store", ctx, userID, tokenID, tokenRequest.Name, tokenRequest.ExpiresAt).Return(nil)
 
+	metadataStore := new(MockMetadataStore)
+
 	generator := new(MockGenerator)
-	generator.On("GenerateToken", "virtualUser", int64(0), CICDHookTokenType, "virtualUser").Return(tokenID, tokenValue, nil)
+	generator.On("GenerateToken", "virtualUser", expiresAt.Unix(), CICDHookTokenType, "virtualUser").Return(tokenID, tokenValue, nil)
 
-	service := NewService(userExtractor, store, generator)
+	service := NewService(userExtractor, store, metadataStore, generator, maxLifetime)
 
 	newToken, err := service.CreateToken(ctx, tokenRequest)
 	require.NoError(t, err)
@@ -146,6 +253,7 @@ func TestService_VirtualUser(t *testing.T) {
 
 	userExtractor.AssertExpectations(t)
 	store.AssertExpectations(t)
+	metadataStore.AssertExpectations(t)
 	generator.AssertExpectations(t)
 }
 
@@ -169,9 +277,12 @@ func TestService_ListTokens(t *testing.T) {
 	store := new(MockStore)
 	store.On("List", ctx, userIDString).Return(expectedTokens, nil)
 
+	metadataStore := new(MockMetadataStore)
+	metadataStore.On("FindMetadata", ctx, []string{"tokenid"}).Return(map[string]Metadata{}, nil)
+
 	generator := new(MockGenerator)
 
-	service := NewService(userExtractor, store, generator)
+	service := NewService(userExtractor, store, metadataStore, generator, maxLifetime)
 
 	tokens, err := service.ListTokens(ctx)
 	require.NoError(t, err)
@@ -180,6 +291,7 @@ func TestService_ListTokens(t *testing.T) {
 
 	userExtractor.AssertExpectations(t)
 	store.AssertExpectations(t)
+	metadataStore.AssertExpectations(t)
 	generator.AssertExpectations(t)
 }
 
@@ -202,9 +314,12 @@ func TestService_GetToken(t *testing.T) {
 	store := new(MockStore)
 	store.On("Lookup", ctx, userIDString, tokenID).Return(expectedToken, nil)
 
+	metadataStore := new(MockMetadataStore)
+	metadataStore.On("FindMetadata", ctx, []string{tokenID}).Return(map[string]Metadata{}, nil)
+
 	generator := new(MockGenerator)
 
-	service := NewService(userExtractor, store, generator)
+	service := NewService(userExtractor, store, metadataStore, generator, maxLifetime)
 
 	token, err := service.GetToken(ctx, tokenID)
 	require.NoError(t, err)
@@ -213,6 +328,7 @@ func TestService_GetToken(t *testing.T) {
 
 	userExtractor.AssertExpectations(t)
 	store.AssertExpectations(t)
+	metadataStore.AssertExpectations(t)
 	generator.AssertExpectations(t)
 }
 
@@ -230,9 +346,11 @@ func TestService_GetToken_NotFound(t *testing.T) {
 	store := new(MockStore)
 	store.On("Lookup", ctx, userIDString, tokenID).Return(Token{}, notFoundError)
 
+	metadataStore := new(MockMetadataStore)
+
 	generator := new(MockGenerator)
 
-	service := NewService(userExtractor, store, generator)
+	service := NewService(userExtractor, store, metadataStore, generator, maxLifetime)
 
 	_, err := service.GetToken(ctx, tokenID)
 	require.Error(t, err)
@@ -241,6 +359,7 @@ func TestService_GetToken_NotFound(t *testing.T) {
 
 	userExtractor.AssertExpectations(t)
 	store.AssertExpectations(t)
+	metadataStore.AssertExpectations(t)
 	generator.AssertExpectations(t)
 }
 
@@ -256,14 +375,18 @@ func TestService_DeleteToken(t *testing.T) {
 	store := new(MockStore)
 	store.On("Revoke", ctx, userIDString, tokenID).Return(nil)
 
+	metadataStore := new(MockMetadataStore)
+	metadataStore.On("DeleteMetadata", ctx, tokenID).Return(nil)
+
 	generator := new(MockGenerator)
 
-	service := NewService(userExtractor, store, generator)
+	service := NewService(userExtractor, store, metadataStore, generator, maxLifetime)
 
 	err := service.DeleteToken(ctx, tokenID)
 	require.NoError(t, err)
 
 	userExtractor.AssertExpectations(t)
 	store.AssertExpectations(t)
+	metadataStore.AssertExpectations(t)
 	generator.AssertExpectations(t)
 }
@@ -0,0 +1,183 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenadapter
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/token"
+)
+
+// TableName constants
+const (
+	metadataTableName = "access_token_metadata"
+)
+
+type metadataModel struct {
+	ID             uint `gorm:"primary_key"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	TokenID        string `gorm:"unique_index:idx_access_token_metadata_token_id;not null"`
+	Scoped         bool
+	OrganizationID uint
+	ClusterIDs     clusterIDs `sql:"type:text"`
+	ReadOnly       bool
+	LastUsedAt     *time.Time
+}
+
+// TableName changes the default table name.
+func (metadataModel) TableName() string {
+	return metadataTableName
+}
+
+// clusterIDs is a list of cluster IDs stored as JSON in SQL databases.
+type clusterIDs []uint
+
+// Value implements the driver.Valuer interface.
+func (c clusterIDs) Value() (driver.Value, error) {
+	v, err := json.Marshal(c)
+
+	return string(v), err
+}
+
+// Scan implements the sql.Scanner interface.
+func (c *clusterIDs) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case nil:
+		*c = nil
+
+		return nil
+	default:
+		return errors.NewWithDetails("cannot scan cluster IDs", "type", v)
+	}
+}
+
+// GormMetadataStore stores token metadata using Gorm for data persistence.
+type GormMetadataStore struct {
+	db *gorm.DB
+}
+
+// NewGormMetadataStore returns a new GormMetadataStore.
+func NewGormMetadataStore(db *gorm.DB) GormMetadataStore {
+	return GormMetadataStore{
+		db: db,
+	}
+}
+
+// SaveScope saves the scope of a token.
+func (s GormMetadataStore) SaveScope(ctx context.Context, tokenID string, scope token.Scope) error {
+	model := metadataModel{TokenID: tokenID}
+
+	err := s.db.Where(model).FirstOrInit(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to find token metadata", "tokenId", tokenID)
+	}
+
+	model.Scoped = true
+	model.OrganizationID = scope.OrganizationID
+	model.ClusterIDs = scope.ClusterIDs
+	model.ReadOnly = scope.ReadOnly
+
+	err = s.db.Save(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to save token scope", "tokenId", tokenID)
+	}
+
+	return nil
+}
+
+// FindMetadata returns the metadata of a set of tokens.
+func (s GormMetadataStore) FindMetadata(ctx context.Context, tokenIDs []string) (map[string]token.Metadata, error) {
+	metadata := make(map[string]token.Metadata, len(tokenIDs))
+
+	if len(tokenIDs) == 0 {
+		return metadata, nil
+	}
+
+	var models []metadataModel
+
+	err := s.db.Where("token_id IN (?)", tokenIDs).Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to find token metadata")
+	}
+
+	for _, model := range models {
+		metadata[model.TokenID] = token.Metadata{
+			Scope:      toScope(model),
+			LastUsedAt: model.LastUsedAt,
+		}
+	}
+
+	return metadata, nil
+}
+
+// DeleteMetadata deletes the metadata of a token.
+func (s GormMetadataStore) DeleteMetadata(ctx context.Context, tokenID string) error {
+	err := s.db.Where(metadataModel{TokenID: tokenID}).Delete(metadataModel{}).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete token metadata", "tokenId", tokenID)
+	}
+
+	return nil
+}
+
+// FindTokenScope returns the scope of a token or nil if the token is not scoped.
+func (s GormMetadataStore) FindTokenScope(ctx context.Context, tokenID string) (*token.Scope, error) {
+	var model metadataModel
+
+	err := s.db.Where(metadataModel{TokenID: tokenID}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to find token scope", "tokenId", tokenID)
+	}
+
+	return toScope(model), nil
+}
+
+// MarkTokenUsed records the last time a token was used.
+func (s GormMetadataStore) MarkTokenUsed(ctx context.Context, tokenID string, usedAt time.Time) error {
+	model := metadataModel{TokenID: tokenID}
+
+	err := s.db.Where(model).Assign(metadataModel{LastUsedAt: &usedAt}).FirstOrCreate(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to mark token as used", "tokenId", tokenID)
+	}
+
+	return nil
+}
+
+func toScope(model metadataModel) *token.Scope {
+	if !model.Scoped {
+		return nil
+	}
+
+	return &token.Scope{
+		OrganizationID: model.OrganizationID,
+		ClusterIDs:     model.ClusterIDs,
+		ReadOnly:       model.ReadOnly,
+	}
+}
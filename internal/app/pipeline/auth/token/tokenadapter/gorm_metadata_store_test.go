@@ -0,0 +1,84 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenadapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/token"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+func testGormMetadataStore(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = Migrate(db, commonadapter.NewNoopLogger())
+	require.NoError(t, err)
+
+	store := NewGormMetadataStore(db)
+
+	scope := token.Scope{
+		OrganizationID: 1,
+		ClusterIDs:     []uint{2, 3},
+		ReadOnly:       true,
+	}
+
+	err = store.SaveScope(ctx, "scoped", scope)
+	require.NoError(t, err)
+
+	foundScope, err := store.FindTokenScope(ctx, "scoped")
+	require.NoError(t, err)
+
+	assert.Equal(t, &scope, foundScope)
+
+	usedAt := time.Date(2019, time.November, 6, 10, 0, 0, 0, time.UTC)
+
+	err = store.MarkTokenUsed(ctx, "scoped", usedAt)
+	require.NoError(t, err)
+
+	err = store.MarkTokenUsed(ctx, "unscoped", usedAt)
+	require.NoError(t, err)
+
+	foundScope, err = store.FindTokenScope(ctx, "unscoped")
+	require.NoError(t, err)
+
+	assert.Nil(t, foundScope)
+
+	metadata, err := store.FindMetadata(ctx, []string{"scoped", "unscoped", "unknown"})
+	require.NoError(t, err)
+
+	require.Len(t, metadata, 2)
+	assert.Equal(t, &scope, metadata["scoped"].Scope)
+	assert.True(t, usedAt.Equal(*metadata["scoped"].LastUsedAt))
+	assert.Nil(t, metadata["unscoped"].Scope)
+
+	err = store.DeleteMetadata(ctx, "scoped")
+	require.NoError(t, err)
+
+	foundScope, err = store.FindTokenScope(ctx, "scoped")
+	require.NoError(t, err)
+
+	assert.Nil(t, foundScope)
+}
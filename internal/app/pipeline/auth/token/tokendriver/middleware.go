@@ -60,6 +60,9 @@ func (e sentinel) IsBusinessError() bool {
 // CannotCreateVirtualUser is returned when a user does not have the right to create a virtual user token.
 const CannotCreateVirtualUser = sentinel("cannot create virtual user")
 
+// CannotScopeToOrganization is returned when a user cannot create a token for an organization.
+const CannotScopeToOrganization = sentinel("cannot scope token to organization")
+
 func (m authorizationMiddleware) CreateToken(ctx context.Context, tokenRequest token.NewTokenRequest) (token.NewToken, error) {
 	if tokenRequest.VirtualUser != "" { // authorize creating a virtual user
 		orgName := strings.Split(tokenRequest.VirtualUser, "/")[0]
@@ -74,6 +77,17 @@ func (m authorizationMiddleware) CreateToken(ctx context.Context, tokenRequest t
 		}
 	}
 
+	if tokenRequest.Scope != nil && tokenRequest.Scope.OrganizationID != 0 { // authorize access to the organization
+		ok, err := m.authorizer.Authorize(ctx, "token.scope", tokenRequest.Scope.OrganizationID)
+		if err != nil {
+			return token.NewToken{}, err
+		}
+
+		if !ok {
+			return token.NewToken{}, CannotScopeToOrganization
+		}
+	}
+
 	return m.next.CreateToken(ctx, tokenRequest)
 }
 
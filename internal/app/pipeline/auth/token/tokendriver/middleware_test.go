@@ -111,3 +111,29 @@ func TestAuthorizationMiddleware_CreateToken_VirtualUserDenied(t *testing.T) {
 	service.AssertExpectations(t)
 	authorizer.AssertExpectations(t)
 }
+
+func TestAuthorizationMiddleware_CreateToken_ScopeDenied(t *testing.T) {
+	ctx := context.Background()
+
+	tokenRequest := token.NewTokenRequest{
+		Name: "token",
+		Scope: &token.Scope{
+			OrganizationID: 2,
+		},
+	}
+
+	service := new(token.MockService)
+
+	authorizer := new(MockAuthorizer)
+	authorizer.On("Authorize", ctx, "token.scope", uint(2)).Return(false, nil)
+
+	middleware := AuthorizationMiddleware(authorizer)(service)
+
+	_, err := middleware.CreateToken(ctx, tokenRequest)
+	require.Error(t, err)
+
+	assert.Equal(t, CannotScopeToOrganization, err)
+
+	service.AssertExpectations(t)
+	authorizer.AssertExpectations(t)
+}
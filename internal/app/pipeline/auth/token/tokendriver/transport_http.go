@@ -96,9 +96,12 @@ func errorEncoder(_ context.Context, w http.ResponseWriter, e error) error {
 	var problem problems.StatusProblem
 
 	switch {
-	case errors.Is(e, CannotCreateVirtualUser):
+	case errors.Is(e, CannotCreateVirtualUser), errors.Is(e, CannotScopeToOrganization):
 		problem = problems.NewDetailedProblem(http.StatusForbidden, e.Error())
 
+	case errors.As(e, &token.ValidationError{}):
+		problem = problems.NewDetailedProblem(http.StatusUnprocessableEntity, e.Error())
+
 	case errors.As(e, &token.NotFoundError{}):
 		problem = problems.NewDetailedProblem(http.StatusNotFound, e.Error())
 
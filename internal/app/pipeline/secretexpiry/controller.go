@@ -0,0 +1,114 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretexpiry
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Controller tracks the expiry of certificates stored in secrets.
+type Controller struct {
+	config       Config
+	certificates CertificateStore
+	notifier     Notifier
+	renewer      Renewer
+	metrics      Metrics
+	logger       Logger
+	errorHandler ErrorHandler
+}
+
+// NewController returns a new Controller.
+func NewController(
+	config Config,
+	certificates CertificateStore,
+	notifier Notifier,
+	renewer Renewer,
+	metrics Metrics,
+	logger Logger,
+	errorHandler ErrorHandler,
+) Controller {
+	return Controller{
+		config:       config,
+		certificates: certificates,
+		notifier:     notifier,
+		renewer:      renewer,
+		metrics:      metrics,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// Check records the expiry of every certificate at a given time.
+// Certificates about to expire raise a notification, and renewable ones are renewed.
+func (c Controller) Check(ctx context.Context, now time.Time) error {
+	certs, err := c.certificates.ListCertificates(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.metrics.RecordExpiries(certs)
+
+	var errs []error
+
+	for _, cert := range certs {
+		remaining := cert.ExpiresAt.Sub(now)
+
+		if cert.Renewable && remaining <= c.config.RenewBefore {
+			err := c.renewer.RenewCertificate(ctx, cert.OrganizationID, cert.SecretID)
+			if err != nil {
+				errs = append(errs, errors.WithDetails(err, "organizationId", cert.OrganizationID, "secretId", cert.SecretID))
+			} else {
+				c.logger.Info("certificate renewal started", map[string]interface{}{
+					"organizationId": cert.OrganizationID,
+					"secretId":       cert.SecretID,
+				})
+			}
+		}
+
+		if remaining <= c.config.NotifyBefore {
+			err := c.notifier.NotifyExpiry(ctx, cert)
+			if err != nil {
+				errs = append(errs, errors.WithDetails(err, "organizationId", cert.OrganizationID, "secretId", cert.SecretID))
+			}
+		}
+	}
+
+	return errors.Combine(errs...)
+}
+
+// Run checks certificates with the given interval until the context is cancelled.
+func (c Controller) Run(ctx context.Context, interval time.Duration) {
+	if err := c.Check(ctx, time.Now()); err != nil {
+		c.errorHandler.Handle(ctx, err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := c.Check(ctx, now); err != nil {
+				c.errorHandler.Handle(ctx, err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretexpiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+//go:generate mockery -name CertificateStore -inpkg -testonly
+//go:generate mockery -name Notifier -inpkg -testonly
+//go:generate mockery -name Renewer -inpkg -testonly
+//go:generate mockery -name Metrics -inpkg -testonly
+
+func TestController_Check(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2019, time.November, 10, 10, 0, 0, 0, time.UTC)
+
+	certs := []Certificate{
+		{
+			OrganizationID: 1,
+			SecretID:       "valid",
+			SecretType:     "tls",
+			ExpiresAt:      now.Add(90 * 24 * time.Hour),
+			Renewable:      true,
+		},
+		{
+			OrganizationID: 1,
+			SecretID:       "renewable",
+			SecretType:     "tls",
+			ExpiresAt:      now.Add(10 * 24 * time.Hour),
+			Renewable:      true,
+		},
+		{
+			OrganizationID: 1,
+			SecretID:       "failing",
+			SecretType:     "tls",
+			ExpiresAt:      now.Add(7 * 24 * time.Hour),
+			Renewable:      true,
+		},
+		{
+			OrganizationID: 2,
+			SecretID:       "pke",
+			SecretType:     "pkecert",
+			ExpiresAt:      now.Add(7 * 24 * time.Hour),
+		},
+		{
+			OrganizationID: 2,
+			SecretID:       "custom",
+			SecretType:     "tls",
+			ExpiresAt:      now.Add(20 * 24 * time.Hour),
+		},
+	}
+
+	certificates := new(MockCertificateStore)
+	certificates.On("ListCertificates", ctx).Return(certs, nil)
+
+	metrics := new(MockMetrics)
+	metrics.On("RecordExpiries", certs).Return()
+
+	renewer := new(MockRenewer)
+	renewer.On("RenewCertificate", ctx, uint(1), "renewable").Return(nil)
+	renewer.On("RenewCertificate", ctx, uint(1), "failing").Return(errors.New("cadence is down"))
+
+	notifier := new(MockNotifier)
+	notifier.On("NotifyExpiry", ctx, certs[1]).Return(nil)
+	notifier.On("NotifyExpiry", ctx, certs[2]).Return(nil)
+	notifier.On("NotifyExpiry", ctx, certs[3]).Return(nil)
+	notifier.On("NotifyExpiry", ctx, certs[4]).Return(nil)
+
+	controller := NewController(
+		Config{
+			NotifyBefore: 30 * 24 * time.Hour,
+			RenewBefore:  14 * 24 * time.Hour,
+		},
+		certificates,
+		notifier,
+		renewer,
+		metrics,
+		commonadapter.NewNoopLogger(),
+		common.NewNoopErrorHandler(),
+	)
+
+	err := controller.Check(ctx, now)
+	assert.EqualError(t, err, "cadence is down")
+
+	certificates.AssertExpectations(t)
+	metrics.AssertExpectations(t)
+	renewer.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	err := Config{NotifyBefore: 30 * 24 * time.Hour, RenewBefore: 14 * 24 * time.Hour}.Validate()
+	assert.NoError(t, err)
+
+	err = Config{NotifyBefore: 14 * 24 * time.Hour, RenewBefore: 30 * 24 * time.Hour}.Validate()
+	assert.Error(t, err)
+
+	err = Config{NotifyBefore: 14 * 24 * time.Hour}.Validate()
+	assert.Error(t, err)
+}
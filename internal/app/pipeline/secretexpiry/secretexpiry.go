@@ -0,0 +1,84 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretexpiry
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Certificate describes the certificates stored in a secret.
+type Certificate struct {
+	OrganizationID uint
+	SecretID       string
+	SecretName     string
+	SecretType     string
+
+	// ExpiresAt is the earliest expiry time of the certificates in the secret.
+	ExpiresAt time.Time
+
+	// Renewable tells whether the certificates were generated by Pipeline (and can be generated again).
+	Renewable bool
+}
+
+// Config contains the thresholds of the expiry controller.
+type Config struct {
+	// NotifyBefore is the time before expiry when a notification is raised.
+	NotifyBefore time.Duration
+
+	// RenewBefore is the time before expiry when renewable certificates are renewed.
+	RenewBefore time.Duration
+}
+
+// Validate validates the configuration.
+// Members are notified before (or when) the renewal starts, so NotifyBefore cannot be shorter than RenewBefore.
+func (c Config) Validate() error {
+	if c.RenewBefore <= 0 {
+		return errors.New("secret expiry renew threshold must be positive")
+	}
+
+	if c.NotifyBefore < c.RenewBefore {
+		return errors.New("secret expiry notify threshold cannot be shorter than the renew threshold")
+	}
+
+	return nil
+}
+
+// CertificateStore lists the certificates stored in secrets.
+type CertificateStore interface {
+	// ListCertificates returns the certificates of every organization.
+	ListCertificates(ctx context.Context) ([]Certificate, error)
+}
+
+// Notifier notifies the members of an organization about expiring certificates.
+type Notifier interface {
+	// NotifyExpiry raises a notification about an expiring certificate.
+	// A notification is raised only once for every expiry time of a certificate.
+	NotifyExpiry(ctx context.Context, cert Certificate) error
+}
+
+// Renewer renews certificates.
+type Renewer interface {
+	// RenewCertificate starts renewing the certificates of a secret and installing them into clusters.
+	RenewCertificate(ctx context.Context, organizationID uint, secretID string) error
+}
+
+// Metrics exposes the expiry times of certificates.
+type Metrics interface {
+	// RecordExpiries replaces every previously recorded expiry time.
+	RecordExpiries(certs []Certificate)
+}
@@ -0,0 +1,95 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretexpiryadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretexpiry"
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// nolint: gochecknoglobals
+var certificateSecretTypes = []string{
+	secrettype.TLSSecretType,
+	secrettype.PKESecretType,
+}
+
+// InternalSecretStore lists secrets.
+type InternalSecretStore interface {
+	// List lists secrets of an organization.
+	List(organizationID uint, query *secret.ListSecretsQuery) ([]*secret.SecretItemResponse, error)
+}
+
+// CertificateStore lists the certificates stored in secrets.
+type CertificateStore struct {
+	db      *gorm.DB
+	secrets InternalSecretStore
+}
+
+// NewCertificateStore returns a new CertificateStore.
+func NewCertificateStore(db *gorm.DB, secrets InternalSecretStore) CertificateStore {
+	return CertificateStore{
+		db:      db,
+		secrets: secrets,
+	}
+}
+
+// ListCertificates returns the certificates of every organization.
+func (s CertificateStore) ListCertificates(ctx context.Context) ([]secretexpiry.Certificate, error) {
+	var organizationIDs []uint
+
+	err := s.db.Table("organizations").Pluck("id", &organizationIDs).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list organizations")
+	}
+
+	var certs []secretexpiry.Certificate
+
+	for _, organizationID := range organizationIDs {
+		for _, secretType := range certificateSecretTypes {
+			items, err := s.secrets.List(organizationID, &secret.ListSecretsQuery{Type: secretType, Values: true})
+			if err != nil {
+				return nil, errors.WrapIfWithDetails(
+					err, "failed to list secrets",
+					"organizationId", organizationID,
+					"type", secretType,
+				)
+			}
+
+			for _, item := range items {
+				// Secrets without (valid) certificates
+				if item.ExpiresAt == nil {
+					continue
+				}
+
+				certs = append(certs, secretexpiry.Certificate{
+					OrganizationID: organizationID,
+					SecretID:       item.ID,
+					SecretName:     item.Name,
+					SecretType:     item.Type,
+					ExpiresAt:      *item.ExpiresAt,
+					Renewable:      secret.IsGeneratedCertificate(item.Type, item.Values),
+				})
+			}
+		}
+	}
+
+	return certs, nil
+}
@@ -0,0 +1,123 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretexpiryadapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/frontend/notification"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretexpiry"
+)
+
+const (
+	// notificationPriority is the priority of expiry notifications.
+	notificationPriority int8 = 50
+
+	// expiredNotificationPeriod is how long a notification is shown after the certificates expired.
+	expiredNotificationPeriod = 7 * 24 * time.Hour
+)
+
+// TableName constants
+const (
+	notificationRecordTableName = "secret_expiry_notifications"
+)
+
+// notificationRecordModel records the expiry times a notification has been raised for.
+type notificationRecordModel struct {
+	ID             uint `gorm:"primary_key"`
+	CreatedAt      time.Time
+	OrganizationID uint      `gorm:"unique_index:idx_secret_expiry_notifications_org_secret_expiry;not null"`
+	SecretID       string    `gorm:"unique_index:idx_secret_expiry_notifications_org_secret_expiry;not null"`
+	ExpiresAt      time.Time `gorm:"unique_index:idx_secret_expiry_notifications_org_secret_expiry;not null"`
+}
+
+// TableName changes the default table name.
+func (notificationRecordModel) TableName() string {
+	return notificationRecordTableName
+}
+
+// NotificationStore stores frontend notifications.
+type NotificationStore interface {
+	// AddNotification stores a new notification.
+	AddNotification(ctx context.Context, n notification.NewNotification) error
+}
+
+// GormNotifier raises frontend notifications about expiring certificates.
+// Raised notifications are recorded using Gorm.
+type GormNotifier struct {
+	db            *gorm.DB
+	notifications NotificationStore
+}
+
+// NewGormNotifier returns a new GormNotifier.
+func NewGormNotifier(db *gorm.DB, notifications NotificationStore) GormNotifier {
+	return GormNotifier{
+		db:            db,
+		notifications: notifications,
+	}
+}
+
+// NotifyExpiry raises a notification about an expiring certificate.
+// A notification is raised only once for every expiry time of a certificate.
+func (n GormNotifier) NotifyExpiry(ctx context.Context, cert secretexpiry.Certificate) error {
+	record := notificationRecordModel{
+		OrganizationID: cert.OrganizationID,
+		SecretID:       cert.SecretID,
+		ExpiresAt:      cert.ExpiresAt.UTC(),
+	}
+
+	var count int
+
+	err := n.db.Model(&notificationRecordModel{}).Where(&record).Count(&count).Error
+	if err != nil {
+		return errors.WrapIf(err, "failed to find expiry notification")
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf(
+		"The certificates in secret %q expire at %s.",
+		cert.SecretName,
+		cert.ExpiresAt.UTC().Format(time.RFC1123),
+	)
+	if cert.Renewable {
+		message += " They could not be renewed automatically."
+	}
+
+	err = n.notifications.AddNotification(ctx, notification.NewNotification{
+		Message:        message,
+		Priority:       notificationPriority,
+		OrganizationID: cert.OrganizationID,
+		InitialTime:    time.Now(),
+		EndTime:        cert.ExpiresAt.Add(expiredNotificationPeriod),
+	})
+	if err != nil {
+		return errors.WrapIf(err, "failed to add expiry notification")
+	}
+
+	err = n.db.Create(&record).Error
+	if err != nil {
+		return errors.WrapIf(err, "failed to record expiry notification")
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretexpiryadapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/frontend/notification/notificationadapter"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretexpiry"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+func testGormNotifier(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = Migrate(db, commonadapter.NewNoopLogger())
+	require.NoError(t, err)
+
+	err = notificationadapter.Migrate(db, commonadapter.NewNoopLogger())
+	require.NoError(t, err)
+
+	notifications := notificationadapter.NewGormStore(db)
+	notifier := NewGormNotifier(db, notifications)
+
+	cert := secretexpiry.Certificate{
+		OrganizationID: 1,
+		SecretID:       "secret",
+		SecretName:     "my-tls",
+		SecretType:     "tls",
+		ExpiresAt:      time.Now().Add(24 * time.Hour).Truncate(time.Second),
+	}
+
+	ctx := context.Background()
+
+	err = notifier.NotifyExpiry(ctx, cert)
+	require.NoError(t, err)
+
+	// The same expiry should not be notified again
+	err = notifier.NotifyExpiry(ctx, cert)
+	require.NoError(t, err)
+
+	active, err := notifications.GetActiveNotifications(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Contains(t, active[0].Message, `"my-tls"`)
+
+	active, err = notifications.GetActiveNotifications(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, active, "notifications of an organization should not be public")
+
+	// Renewed certificates are notified again
+	cert.ExpiresAt = cert.ExpiresAt.Add(time.Hour)
+
+	err = notifier.NotifyExpiry(ctx, cert)
+	require.NoError(t, err)
+
+	active, err = notifications.GetActiveNotifications(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, active, 2)
+}
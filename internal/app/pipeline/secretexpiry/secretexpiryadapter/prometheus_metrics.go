@@ -0,0 +1,57 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretexpiryadapter
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretexpiry"
+)
+
+// PrometheusMetrics exposes the expiry times of certificates as Prometheus metrics.
+type PrometheusMetrics struct {
+	*prometheus.GaugeVec
+}
+
+// NewPrometheusMetrics returns a new PrometheusMetrics.
+func NewPrometheusMetrics() PrometheusMetrics {
+	return PrometheusMetrics{
+		prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: "pipeline",
+				Name:      "secret_certificate_expiry_timestamp_seconds",
+				Help:      "Earliest expiry time of the certificates stored in a secret",
+			},
+			[]string{"orgId", "secretId", "secretName", "secretType"},
+		),
+	}
+}
+
+// RecordExpiries replaces every previously recorded expiry time.
+func (m PrometheusMetrics) RecordExpiries(certs []secretexpiry.Certificate) {
+	// Deleted secrets should disappear
+	m.Reset()
+
+	for _, cert := range certs {
+		m.WithLabelValues(
+			strconv.FormatUint(uint64(cert.OrganizationID), 10),
+			cert.SecretID,
+			cert.SecretName,
+			cert.SecretType,
+		).Set(float64(cert.ExpiresAt.Unix()))
+	}
+}
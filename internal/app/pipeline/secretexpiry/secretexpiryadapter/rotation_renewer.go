@@ -0,0 +1,48 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretexpiryadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+// RotationRenewer renews certificates using the secret rotation workflow.
+// The workflow verifies the new certificates and installs them into the clusters using the secret.
+type RotationRenewer struct {
+	rotations secretrotation.RotationStarter
+}
+
+// NewRotationRenewer returns a new RotationRenewer.
+func NewRotationRenewer(rotations secretrotation.RotationStarter) RotationRenewer {
+	return RotationRenewer{
+		rotations: rotations,
+	}
+}
+
+// RenewCertificate starts renewing the certificates of a secret.
+// Secrets which are being renewed already are skipped.
+func (r RotationRenewer) RenewCertificate(ctx context.Context, organizationID uint, secretID string) error {
+	err := r.rotations.StartRotation(ctx, organizationID, secretID, secrettype.TLSSecretType)
+	if errors.As(err, &secretrotation.AlreadyRotatingError{}) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,88 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+)
+
+const CreateKeyActivityName = "secret-rotation-create-key"
+
+type CreateKeyActivity struct {
+	secrets   SecretStore
+	providers KeyProviders
+}
+
+// NewCreateKeyActivity returns a new CreateKeyActivity.
+func NewCreateKeyActivity(secrets SecretStore, providers KeyProviders) CreateKeyActivity {
+	return CreateKeyActivity{
+		secrets:   secrets,
+		providers: providers,
+	}
+}
+
+type CreateKeyActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+	Provider       string
+}
+
+type CreateKeyActivityOutput struct {
+	// Version of the secret holding the previous key.
+	PreviousVersion int
+
+	// Version of the secret holding the new key.
+	Version int
+}
+
+// Execute creates a new key and stores it as a new version of the secret.
+func (a CreateKeyActivity) Execute(ctx context.Context, input CreateKeyActivityInput) (CreateKeyActivityOutput, error) {
+	activity.GetLogger(ctx).Sugar().With("secretId", input.SecretID).Info("creating new key")
+
+	provider, err := a.providers.Get(input.Provider)
+	if err != nil {
+		return CreateKeyActivityOutput{}, err
+	}
+
+	current, err := a.secrets.Get(ctx, input.OrganizationID, input.SecretID)
+	if err != nil {
+		return CreateKeyActivityOutput{}, errors.WrapIfWithDetails(err, "failed to get secret", "secretId", input.SecretID)
+	}
+
+	values, err := provider.CreateKey(ctx, current.Values)
+	if err != nil {
+		return CreateKeyActivityOutput{}, errors.WrapIfWithDetails(err, "failed to create key", "secretId", input.SecretID)
+	}
+
+	rotated := current
+	rotated.Values = values
+
+	if err := a.secrets.Update(ctx, input.OrganizationID, rotated); err != nil {
+		// Do not leave an unused key behind
+		if err := provider.RevokeKey(ctx, current.Values, values); err != nil {
+			activity.GetLogger(ctx).Sugar().With("secretId", input.SecretID).Error("failed to revoke unused key")
+		}
+
+		return CreateKeyActivityOutput{}, errors.WrapIfWithDetails(err, "failed to store new key", "secretId", input.SecretID)
+	}
+
+	return CreateKeyActivityOutput{
+		PreviousVersion: current.Version,
+		Version:         current.Version + 1,
+	}, nil
+}
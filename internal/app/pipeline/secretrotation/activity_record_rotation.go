@@ -0,0 +1,73 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+const RecordRotationActivityName = "secret-rotation-record-rotation"
+
+// RetryInterval is the maximum time before a failed rotation is attempted again.
+const RetryInterval = time.Hour
+
+type RecordRotationActivity struct {
+	policies PolicyStore
+}
+
+// NewRecordRotationActivity returns a new RecordRotationActivity.
+func NewRecordRotationActivity(policies PolicyStore) RecordRotationActivity {
+	return RecordRotationActivity{
+		policies: policies,
+	}
+}
+
+type RecordRotationActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+	RotatedAt      time.Time
+
+	// Error is empty when the rotation succeeded.
+	Error string
+}
+
+// Execute records the outcome of a rotation and schedules the next one.
+func (a RecordRotationActivity) Execute(ctx context.Context, input RecordRotationActivityInput) error {
+	policy, err := a.policies.Get(ctx, input.OrganizationID, input.SecretID)
+	if errors.As(err, &NotFoundError{}) { // the secret was rotated manually
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if input.Error == "" {
+		policy.LastRotatedAt = &input.RotatedAt
+		policy.NextRotationAt = input.RotatedAt.Add(policy.Interval)
+	} else {
+		retryInterval := policy.Interval
+		if retryInterval > RetryInterval {
+			retryInterval = RetryInterval
+		}
+
+		policy.NextRotationAt = input.RotatedAt.Add(retryInterval)
+	}
+
+	policy.LastError = input.Error
+
+	return a.policies.Save(ctx, policy)
+}
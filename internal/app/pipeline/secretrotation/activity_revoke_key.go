@@ -0,0 +1,75 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+)
+
+const RevokeKeyActivityName = "secret-rotation-revoke-key"
+
+type RevokeKeyActivity struct {
+	secrets   SecretStore
+	providers KeyProviders
+}
+
+// NewRevokeKeyActivity returns a new RevokeKeyActivity.
+func NewRevokeKeyActivity(secrets SecretStore, providers KeyProviders) RevokeKeyActivity {
+	return RevokeKeyActivity{
+		secrets:   secrets,
+		providers: providers,
+	}
+}
+
+type RevokeKeyActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+	Provider       string
+
+	// Version of the secret holding the key used for the revocation.
+	ActiveVersion int
+
+	// Version of the secret holding the revoked key.
+	RevokedVersion int
+}
+
+// Execute revokes the key stored in a version of the secret.
+func (a RevokeKeyActivity) Execute(ctx context.Context, input RevokeKeyActivityInput) error {
+	activity.GetLogger(ctx).Sugar().With("secretId", input.SecretID, "version", input.RevokedVersion).Info("revoking key")
+
+	provider, err := a.providers.Get(input.Provider)
+	if err != nil {
+		return err
+	}
+
+	active, err := a.secrets.GetVersion(ctx, input.OrganizationID, input.SecretID, input.ActiveVersion)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get secret version", "secretId", input.SecretID, "version", input.ActiveVersion)
+	}
+
+	revoked, err := a.secrets.GetVersion(ctx, input.OrganizationID, input.SecretID, input.RevokedVersion)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get secret version", "secretId", input.SecretID, "version", input.RevokedVersion)
+	}
+
+	if err := provider.RevokeKey(ctx, active.Values, revoked.Values); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to revoke key", "secretId", input.SecretID, "version", input.RevokedVersion)
+	}
+
+	return nil
+}
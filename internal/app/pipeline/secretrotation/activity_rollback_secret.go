@@ -0,0 +1,52 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+)
+
+const RollbackSecretActivityName = "secret-rotation-rollback-secret"
+
+type RollbackSecretActivity struct {
+	secrets SecretStore
+}
+
+// NewRollbackSecretActivity returns a new RollbackSecretActivity.
+func NewRollbackSecretActivity(secrets SecretStore) RollbackSecretActivity {
+	return RollbackSecretActivity{
+		secrets: secrets,
+	}
+}
+
+type RollbackSecretActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+	Version        int
+}
+
+// Execute restores a previous version of the secret.
+func (a RollbackSecretActivity) Execute(ctx context.Context, input RollbackSecretActivityInput) error {
+	activity.GetLogger(ctx).Sugar().With("secretId", input.SecretID, "version", input.Version).Info("restoring previous key")
+
+	if err := a.secrets.Rollback(ctx, input.OrganizationID, input.SecretID, input.Version); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to roll back secret", "secretId", input.SecretID, "version", input.Version)
+	}
+
+	return nil
+}
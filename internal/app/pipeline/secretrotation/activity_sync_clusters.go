@@ -0,0 +1,57 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+)
+
+const SyncClustersActivityName = "secret-rotation-sync-clusters"
+
+// ClusterSecretSyncer updates the copies of a secret installed to clusters.
+type ClusterSecretSyncer interface {
+	// SyncSecret reinstalls a secret to every cluster of an organization it was installed to.
+	SyncSecret(ctx context.Context, organizationID uint, secretID string) error
+}
+
+type SyncClustersActivity struct {
+	syncer ClusterSecretSyncer
+}
+
+// NewSyncClustersActivity returns a new SyncClustersActivity.
+func NewSyncClustersActivity(syncer ClusterSecretSyncer) SyncClustersActivity {
+	return SyncClustersActivity{
+		syncer: syncer,
+	}
+}
+
+type SyncClustersActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+}
+
+// Execute updates the clusters the secret is installed to.
+func (a SyncClustersActivity) Execute(ctx context.Context, input SyncClustersActivityInput) error {
+	activity.GetLogger(ctx).Sugar().With("secretId", input.SecretID).Info("updating secret in clusters")
+
+	if err := a.syncer.SyncSecret(ctx, input.OrganizationID, input.SecretID); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to update secret in clusters", "secretId", input.SecretID)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/testsuite"
+)
+
+// inmemorySecretStore keeps every version of a single secret in memory.
+type inmemorySecretStore struct {
+	versions []Secret
+}
+
+func (s *inmemorySecretStore) Get(ctx context.Context, organizationID uint, secretID string) (Secret, error) {
+	return s.versions[len(s.versions)-1], nil
+}
+
+func (s *inmemorySecretStore) GetVersion(ctx context.Context, organizationID uint, secretID string, version int) (Secret, error) {
+	if version < 1 || version > len(s.versions) {
+		return Secret{}, errors.New("version not found")
+	}
+
+	return s.versions[version-1], nil
+}
+
+func (s *inmemorySecretStore) Update(ctx context.Context, organizationID uint, secret Secret) error {
+	if secret.Version != len(s.versions) {
+		return errors.New("check-and-set parameter did not match the current version")
+	}
+
+	secret.Version++
+	s.versions = append(s.versions, secret)
+
+	return nil
+}
+
+func (s *inmemorySecretStore) Rollback(ctx context.Context, organizationID uint, secretID string, version int) error {
+	secret, err := s.GetVersion(ctx, organizationID, secretID, version)
+	if err != nil {
+		return err
+	}
+
+	secret.Version = len(s.versions)
+
+	return s.Update(ctx, organizationID, secret)
+}
+
+// Activities are registered globally and the workflow tests already use the real names,
+// so the activities under test are registered with a prefix.
+const testActivityPrefix = "test-"
+
+func newTestActivityEnvironment(secrets *inmemorySecretStore, provider KeyProvider) *testsuite.TestActivityEnvironment {
+	providers := KeyProviders{"fake": provider}
+
+	activity.RegisterWithOptions(
+		NewCreateKeyActivity(secrets, providers).Execute,
+		activity.RegisterOptions{Name: testActivityPrefix + CreateKeyActivityName},
+	)
+	activity.RegisterWithOptions(
+		NewVerifyKeyActivity(secrets, providers).Execute,
+		activity.RegisterOptions{Name: testActivityPrefix + VerifyKeyActivityName},
+	)
+	activity.RegisterWithOptions(
+		NewRevokeKeyActivity(secrets, providers).Execute,
+		activity.RegisterOptions{Name: testActivityPrefix + RevokeKeyActivityName},
+	)
+	activity.RegisterWithOptions(
+		NewRollbackSecretActivity(secrets).Execute,
+		activity.RegisterOptions{Name: testActivityPrefix + RollbackSecretActivityName},
+	)
+
+	return new(testsuite.WorkflowTestSuite).NewTestActivityEnvironment()
+}
+
+func TestActivities(t *testing.T) {
+	provider := NewFakeKeyProvider("id", "secret")
+	provider.AddKey("initial", "initial-secret")
+
+	secrets := &inmemorySecretStore{
+		versions: []Secret{
+			{
+				ID:      "secret",
+				Name:    "my-secret",
+				Type:    "fake",
+				Values:  map[string]string{"id": "initial", "secret": "initial-secret", "region": "eu"},
+				Version: 1,
+			},
+		},
+	}
+
+	env := newTestActivityEnvironment(secrets, provider)
+
+	t.Run("Rotate", func(t *testing.T) {
+		result, err := env.ExecuteActivity(
+			testActivityPrefix+CreateKeyActivityName,
+			CreateKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "fake"},
+		)
+		require.NoError(t, err)
+
+		var output CreateKeyActivityOutput
+		require.NoError(t, result.Get(&output))
+
+		assert.Equal(t, CreateKeyActivityOutput{PreviousVersion: 1, Version: 2}, output)
+
+		current := secrets.versions[1]
+		assert.NotEqual(t, "initial", current.Values["id"])
+		assert.Equal(t, "eu", current.Values["region"])
+		assert.True(t, provider.HasKey(current.Values["id"]))
+
+		_, err = env.ExecuteActivity(
+			testActivityPrefix+VerifyKeyActivityName,
+			VerifyKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "fake", Version: 2},
+		)
+		require.NoError(t, err)
+
+		_, err = env.ExecuteActivity(
+			testActivityPrefix+RevokeKeyActivityName,
+			RevokeKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "fake", ActiveVersion: 2, RevokedVersion: 1},
+		)
+		require.NoError(t, err)
+
+		assert.False(t, provider.HasKey("initial"))
+		assert.True(t, provider.HasKey(current.Values["id"]))
+
+		_, err = env.ExecuteActivity(
+			testActivityPrefix+VerifyKeyActivityName,
+			VerifyKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "fake", Version: 1},
+		)
+		require.Error(t, err)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		_, err := env.ExecuteActivity(
+			testActivityPrefix+RollbackSecretActivityName,
+			RollbackSecretActivityInput{OrganizationID: 1, SecretID: "secret", Version: 1},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, secrets.versions, 3)
+		assert.Equal(t, "initial", secrets.versions[2].Values["id"])
+		assert.Equal(t, 3, secrets.versions[2].Version)
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/activity"
+)
+
+const VerifyKeyActivityName = "secret-rotation-verify-key"
+
+type VerifyKeyActivity struct {
+	secrets   SecretStore
+	providers KeyProviders
+}
+
+// NewVerifyKeyActivity returns a new VerifyKeyActivity.
+func NewVerifyKeyActivity(secrets SecretStore, providers KeyProviders) VerifyKeyActivity {
+	return VerifyKeyActivity{
+		secrets:   secrets,
+		providers: providers,
+	}
+}
+
+type VerifyKeyActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+	Provider       string
+	Version        int
+}
+
+// Execute verifies the key stored in a version of the secret.
+func (a VerifyKeyActivity) Execute(ctx context.Context, input VerifyKeyActivityInput) error {
+	activity.GetLogger(ctx).Sugar().With("secretId", input.SecretID, "version", input.Version).Info("verifying new key")
+
+	provider, err := a.providers.Get(input.Provider)
+	if err != nil {
+		return err
+	}
+
+	item, err := a.secrets.GetVersion(ctx, input.OrganizationID, input.SecretID, input.Version)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get secret version", "secretId", input.SecretID, "version", input.Version)
+	}
+
+	if err := provider.VerifyKey(ctx, item.Values); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to verify key", "secretId", input.SecretID, "version", input.Version)
+	}
+
+	return nil
+}
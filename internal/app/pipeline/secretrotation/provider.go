@@ -0,0 +1,149 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"emperror.dev/errors"
+)
+
+// KeyProvider manages the keys of a cloud provider account.
+type KeyProvider interface {
+	// CreateKey creates a new key for the account the credentials belong to
+	// and returns the credentials with the new key.
+	CreateKey(ctx context.Context, values map[string]string) (map[string]string, error)
+
+	// VerifyKey checks whether the credentials are valid.
+	VerifyKey(ctx context.Context, values map[string]string) error
+
+	// RevokeKey revokes the key of a set of credentials using the active credentials.
+	RevokeKey(ctx context.Context, active map[string]string, revoked map[string]string) error
+}
+
+// KeyProviders is a set of key providers indexed by their names.
+type KeyProviders map[string]KeyProvider
+
+// Get returns a key provider by its name.
+func (p KeyProviders) Get(name string) (KeyProvider, error) {
+	provider, ok := p[name]
+	if !ok {
+		return nil, errors.NewWithDetails("unknown key provider", "provider", name)
+	}
+
+	return provider, nil
+}
+
+// FakeKeyProvider is an in-memory key provider for local testing.
+type FakeKeyProvider struct {
+	idField     string
+	secretField string
+
+	mu   sync.Mutex
+	keys map[string]string
+	seq  int
+}
+
+// NewFakeKeyProvider returns a new FakeKeyProvider storing key IDs and key secrets in the given fields of the
+// credentials.
+func NewFakeKeyProvider(idField string, secretField string) *FakeKeyProvider {
+	return &FakeKeyProvider{
+		idField:     idField,
+		secretField: secretField,
+		keys:        make(map[string]string),
+	}
+}
+
+// AddKey registers an existing key.
+func (p *FakeKeyProvider) AddKey(id string, secret string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.keys[id] = secret
+}
+
+// HasKey tells whether a key exists (and is not revoked).
+func (p *FakeKeyProvider) HasKey(id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.keys[id]
+
+	return ok
+}
+
+// CreateKey implements the KeyProvider interface.
+func (p *FakeKeyProvider) CreateKey(ctx context.Context, values map[string]string) (map[string]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.verify(values); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, errors.WrapIf(err, "failed to generate key")
+	}
+
+	p.seq++
+	id := fmt.Sprintf("fake-%d", p.seq)
+	p.keys[id] = hex.EncodeToString(secret)
+
+	newValues := make(map[string]string, len(values))
+	for k, v := range values {
+		newValues[k] = v
+	}
+
+	newValues[p.idField] = id
+	newValues[p.secretField] = p.keys[id]
+
+	return newValues, nil
+}
+
+// VerifyKey implements the KeyProvider interface.
+func (p *FakeKeyProvider) VerifyKey(ctx context.Context, values map[string]string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.verify(values)
+}
+
+// RevokeKey implements the KeyProvider interface.
+func (p *FakeKeyProvider) RevokeKey(ctx context.Context, active map[string]string, revoked map[string]string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.verify(active); err != nil {
+		return err
+	}
+
+	delete(p.keys, revoked[p.idField])
+
+	return nil
+}
+
+func (p *FakeKeyProvider) verify(values map[string]string) error {
+	secret, ok := p.keys[values[p.idField]]
+	if !ok || secret != values[p.secretField] {
+		return errors.NewWithDetails("invalid key", "keyId", values[p.idField])
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Scheduler starts the rotation of secrets which are due according to their policies.
+type Scheduler struct {
+	policies     PolicyStore
+	rotations    RotationStarter
+	logger       Logger
+	errorHandler ErrorHandler
+}
+
+// NewScheduler returns a new Scheduler.
+func NewScheduler(policies PolicyStore, rotations RotationStarter, logger Logger, errorHandler ErrorHandler) Scheduler {
+	return Scheduler{
+		policies:     policies,
+		rotations:    rotations,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// Schedule starts the rotation of every secret due at a given time.
+// Secrets which are being rotated already are skipped.
+func (s Scheduler) Schedule(ctx context.Context, now time.Time) error {
+	policies, err := s.policies.FindDue(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, policy := range policies {
+		err := s.rotations.StartRotation(ctx, policy.OrganizationID, policy.SecretID, policy.Provider)
+		if errors.As(err, &AlreadyRotatingError{}) {
+			continue
+		} else if err != nil {
+			errs = append(errs, errors.WithDetails(err, "organizationId", policy.OrganizationID, "secretId", policy.SecretID))
+
+			continue
+		}
+
+		s.logger.Info("secret rotation started", map[string]interface{}{
+			"organizationId": policy.OrganizationID,
+			"secretId":       policy.SecretID,
+		})
+	}
+
+	return errors.Combine(errs...)
+}
+
+// Run schedules rotations with the given interval until the context is cancelled.
+func (s Scheduler) Run(ctx context.Context, interval time.Duration) {
+	if err := s.Schedule(ctx, time.Now()); err != nil {
+		s.errorHandler.Handle(ctx, err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			if err := s.Schedule(ctx, now); err != nil {
+				s.errorHandler.Handle(ctx, err)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+func TestScheduler_Schedule(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2019, time.November, 10, 10, 0, 0, 0, time.UTC)
+
+	policies := new(MockPolicyStore)
+	policies.On("FindDue", ctx, now).Return(
+		[]Policy{
+			{OrganizationID: 1, SecretID: "rotating", Provider: "amazon"},
+			{OrganizationID: 1, SecretID: "failing", Provider: "amazon"},
+			{OrganizationID: 2, SecretID: "due", Provider: "google"},
+		},
+		nil,
+	)
+
+	rotations := new(MockRotationStarter)
+	rotations.On("StartRotation", ctx, uint(1), "rotating", "amazon").Return(AlreadyRotatingError{SecretID: "rotating"})
+	rotations.On("StartRotation", ctx, uint(1), "failing", "amazon").Return(errors.New("cadence is down"))
+	rotations.On("StartRotation", ctx, uint(2), "due", "google").Return(nil)
+
+	scheduler := NewScheduler(policies, rotations, commonadapter.NewNoopLogger(), common.NewNoopErrorHandler())
+
+	err := scheduler.Schedule(ctx, now)
+	assert.EqualError(t, err, "cadence is down")
+
+	policies.AssertExpectations(t)
+	rotations.AssertExpectations(t)
+}
@@ -0,0 +1,337 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+// MinInterval is the shortest rotation interval a policy can have.
+const MinInterval = time.Hour
+
+// UpdatedBy is recorded as the author of secret versions created by a rotation.
+const UpdatedBy = "secret-rotation"
+
+// CloudProviders lists the key providers of the cloud secret types.
+// nolint: gochecknoglobals
+var CloudProviders = []string{
+	secrettype.Alibaba,
+	secrettype.Amazon,
+	secrettype.Azure,
+	secrettype.Google,
+}
+
+// Policy describes when and how the key in a secret is rotated.
+type Policy struct {
+	OrganizationID uint
+	SecretID       string
+
+	// Provider is the name of the key provider used for the rotation.
+	Provider string
+
+	// Interval is the time between two rotations.
+	Interval time.Duration
+
+	LastRotatedAt  *time.Time
+	NextRotationAt time.Time
+	LastError      string
+}
+
+// SetPolicyRequest contains the details of a rotation policy.
+type SetPolicyRequest struct {
+	// Provider defaults to the type of the secret.
+	Provider string
+	Interval time.Duration
+}
+
+//go:generate mga gen kit endpoint --outdir secretrotationdriver --with-oc Service
+//go:generate mockery -name Service -inpkg
+type Service interface {
+	// GetPolicy returns the rotation policy of a secret.
+	GetPolicy(ctx context.Context, secretID string) (Policy, error)
+
+	// SetPolicy creates or updates the rotation policy of a secret.
+	SetPolicy(ctx context.Context, secretID string, req SetPolicyRequest) (Policy, error)
+
+	// DeletePolicy deletes the rotation policy of a secret.
+	DeletePolicy(ctx context.Context, secretID string) error
+
+	// RotateSecret starts rotating a secret immediately.
+	RotateSecret(ctx context.Context, secretID string) error
+}
+
+// NewService returns a new Service.
+func NewService(
+	orgIDExtractor OrgIDContextExtractor,
+	policies PolicyStore,
+	secrets SecretStore,
+	rotations RotationStarter,
+	providers []string,
+) Service {
+	return service{
+		orgIDExtractor: orgIDExtractor,
+		policies:       policies,
+		secrets:        secrets,
+		rotations:      rotations,
+		providers:      providers,
+	}
+}
+
+type service struct {
+	orgIDExtractor OrgIDContextExtractor
+	policies       PolicyStore
+	secrets        SecretStore
+	rotations      RotationStarter
+	providers      []string
+}
+
+// OrgIDContextExtractor extracts an organization ID from a context (if there is any).
+type OrgIDContextExtractor interface {
+	// GetOrganizationID extracts an organization ID from a context (if there is any).
+	GetOrganizationID(ctx context.Context) (uint, bool)
+}
+
+// PolicyStore persists rotation policies.
+type PolicyStore interface {
+	// Get returns the rotation policy of a secret.
+	// It returns a NotFoundError if the secret has no rotation policy.
+	Get(ctx context.Context, organizationID uint, secretID string) (Policy, error)
+
+	// Save creates or updates a rotation policy.
+	Save(ctx context.Context, policy Policy) error
+
+	// Delete deletes the rotation policy of a secret.
+	Delete(ctx context.Context, organizationID uint, secretID string) error
+
+	// FindDue returns every policy that should have been rotated by a given time.
+	FindDue(ctx context.Context, now time.Time) ([]Policy, error)
+}
+
+// Secret is a secret holding the key of a cloud provider account.
+type Secret struct {
+	ID      string
+	Name    string
+	Type    string
+	Values  map[string]string
+	Tags    []string
+	Version int
+}
+
+// SecretStore provides access to the secrets being rotated.
+type SecretStore interface {
+	// Get returns the latest version of a secret.
+	// It returns a SecretNotFoundError if the secret cannot be found.
+	Get(ctx context.Context, organizationID uint, secretID string) (Secret, error)
+
+	// GetVersion returns a specific version of a secret.
+	GetVersion(ctx context.Context, organizationID uint, secretID string, version int) (Secret, error)
+
+	// Update stores the values of a secret as a new version.
+	// It fails if the secret changed since it was read.
+	Update(ctx context.Context, organizationID uint, secret Secret) error
+
+	// Rollback restores a previous version of a secret as a new version.
+	Rollback(ctx context.Context, organizationID uint, secretID string, version int) error
+}
+
+// RotationStarter starts secret rotations in the background.
+type RotationStarter interface {
+	// StartRotation starts rotating a secret.
+	// It returns an AlreadyRotatingError if the secret is being rotated already.
+	StartRotation(ctx context.Context, organizationID uint, secretID string, provider string) error
+}
+
+func (s service) GetPolicy(ctx context.Context, secretID string) (Policy, error) {
+	orgID, ok := s.orgIDExtractor.GetOrganizationID(ctx)
+	if !ok {
+		return Policy{}, errors.New("organization ID not found in the context")
+	}
+
+	return s.policies.Get(ctx, orgID, secretID)
+}
+
+func (s service) SetPolicy(ctx context.Context, secretID string, req SetPolicyRequest) (Policy, error) {
+	orgID, ok := s.orgIDExtractor.GetOrganizationID(ctx)
+	if !ok {
+		return Policy{}, errors.New("organization ID not found in the context")
+	}
+
+	if req.Interval < MinInterval {
+		return Policy{}, ValidationError{Message: fmt.Sprintf("rotation interval must be at least %s", MinInterval)}
+	}
+
+	provider, err := s.getProvider(ctx, orgID, secretID, req.Provider)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	policy, err := s.policies.Get(ctx, orgID, secretID)
+	if errors.As(err, &NotFoundError{}) {
+		policy = Policy{
+			OrganizationID: orgID,
+			SecretID:       secretID,
+		}
+	} else if err != nil {
+		return Policy{}, err
+	}
+
+	policy.Provider = provider
+	policy.Interval = req.Interval
+
+	if policy.LastRotatedAt != nil {
+		policy.NextRotationAt = policy.LastRotatedAt.Add(policy.Interval)
+	} else {
+		policy.NextRotationAt = time.Now().Add(policy.Interval)
+	}
+
+	if err := s.policies.Save(ctx, policy); err != nil {
+		return Policy{}, err
+	}
+
+	return policy, nil
+}
+
+func (s service) DeletePolicy(ctx context.Context, secretID string) error {
+	orgID, ok := s.orgIDExtractor.GetOrganizationID(ctx)
+	if !ok {
+		return errors.New("organization ID not found in the context")
+	}
+
+	return s.policies.Delete(ctx, orgID, secretID)
+}
+
+func (s service) RotateSecret(ctx context.Context, secretID string) error {
+	orgID, ok := s.orgIDExtractor.GetOrganizationID(ctx)
+	if !ok {
+		return errors.New("organization ID not found in the context")
+	}
+
+	var provider string
+
+	policy, err := s.policies.Get(ctx, orgID, secretID)
+	if err == nil {
+		provider = policy.Provider
+	} else if !errors.As(err, &NotFoundError{}) {
+		return err
+	}
+
+	provider, err = s.getProvider(ctx, orgID, secretID, provider)
+	if err != nil {
+		return err
+	}
+
+	return s.rotations.StartRotation(ctx, orgID, secretID, provider)
+}
+
+// getProvider returns the requested (or the default) key provider of a secret.
+func (s service) getProvider(ctx context.Context, organizationID uint, secretID string, provider string) (string, error) {
+	item, err := s.secrets.Get(ctx, organizationID, secretID)
+	if err != nil {
+		return "", err
+	}
+
+	if provider == "" {
+		provider = item.Type
+	}
+
+	for _, p := range s.providers {
+		if p == provider {
+			return provider, nil
+		}
+	}
+
+	return "", ValidationError{Message: fmt.Sprintf("secrets of type %q cannot be rotated by provider %q", item.Type, provider)}
+}
+
+// NotFoundError is returned if the rotation policy of a secret cannot be found.
+type NotFoundError struct {
+	SecretID string
+}
+
+// Error implements the error interface.
+func (NotFoundError) Error() string {
+	return "rotation policy not found"
+}
+
+// Details returns error details.
+func (e NotFoundError) Details() []interface{} {
+	return []interface{}{"secretId", e.SecretID}
+}
+
+// IsBusinessError tells the transport layer to return this error to the client.
+func (NotFoundError) IsBusinessError() bool {
+	return true
+}
+
+// SecretNotFoundError is returned if a secret cannot be found.
+type SecretNotFoundError struct {
+	SecretID string
+}
+
+// Error implements the error interface.
+func (SecretNotFoundError) Error() string {
+	return "secret not found"
+}
+
+// Details returns error details.
+func (e SecretNotFoundError) Details() []interface{} {
+	return []interface{}{"secretId", e.SecretID}
+}
+
+// IsBusinessError tells the transport layer to return this error to the client.
+func (SecretNotFoundError) IsBusinessError() bool {
+	return true
+}
+
+// ValidationError is returned when a request is invalid.
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface.
+func (e ValidationError) Error() string {
+	return e.Message
+}
+
+// IsBusinessError tells the transport layer to return this error to the client.
+func (ValidationError) IsBusinessError() bool {
+	return true
+}
+
+// AlreadyRotatingError is returned when a secret is being rotated already.
+type AlreadyRotatingError struct {
+	SecretID string
+}
+
+// Error implements the error interface.
+func (AlreadyRotatingError) Error() string {
+	return "secret is being rotated already"
+}
+
+// Details returns error details.
+func (e AlreadyRotatingError) Details() []interface{} {
+	return []interface{}{"secretId", e.SecretID}
+}
+
+// IsBusinessError tells the transport layer to return this error to the client.
+func (AlreadyRotatingError) IsBusinessError() bool {
+	return true
+}
@@ -0,0 +1,156 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockery -name PolicyStore -inpkg -testonly
+//go:generate mockery -name SecretStore -inpkg -testonly
+//go:generate mockery -name RotationStarter -inpkg -testonly
+
+type orgIDExtractorStub struct {
+	orgID uint
+}
+
+func (e orgIDExtractorStub) GetOrganizationID(ctx context.Context) (uint, bool) {
+	return e.orgID, e.orgID != 0
+}
+
+func TestService_SetPolicy(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+
+	secrets := new(MockSecretStore)
+	secrets.On("Get", ctx, orgID, "secret").Return(Secret{ID: "secret", Type: "amazon"}, nil)
+
+	policies := new(MockPolicyStore)
+	policies.On("Get", ctx, orgID, "secret").Return(Policy{}, NotFoundError{SecretID: "secret"})
+	policies.On("Save", ctx, mock.MatchedBy(func(policy Policy) bool {
+		return policy.OrganizationID == orgID &&
+			policy.SecretID == "secret" &&
+			policy.Provider == "amazon" &&
+			policy.Interval == 24*time.Hour &&
+			policy.NextRotationAt.After(time.Now().Add(23*time.Hour))
+	})).Return(nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, policies, secrets, nil, CloudProviders)
+
+	policy, err := service.SetPolicy(ctx, "secret", SetPolicyRequest{Interval: 24 * time.Hour})
+	require.NoError(t, err)
+
+	assert.Equal(t, "amazon", policy.Provider)
+
+	secrets.AssertExpectations(t)
+	policies.AssertExpectations(t)
+}
+
+func TestService_SetPolicy_Update(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+	lastRotatedAt := time.Date(2019, time.November, 10, 10, 0, 0, 0, time.UTC)
+
+	secrets := new(MockSecretStore)
+	secrets.On("Get", ctx, orgID, "secret").Return(Secret{ID: "secret", Type: "generic"}, nil)
+
+	existingPolicy := Policy{
+		OrganizationID: orgID,
+		SecretID:       "secret",
+		Provider:       "fake",
+		Interval:       time.Hour,
+		LastRotatedAt:  &lastRotatedAt,
+	}
+
+	expectedPolicy := existingPolicy
+	expectedPolicy.Interval = 48 * time.Hour
+	expectedPolicy.NextRotationAt = lastRotatedAt.Add(48 * time.Hour)
+
+	policies := new(MockPolicyStore)
+	policies.On("Get", ctx, orgID, "secret").Return(existingPolicy, nil)
+	policies.On("Save", ctx, expectedPolicy).Return(nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, policies, secrets, nil, []string{"fake"})
+
+	policy, err := service.SetPolicy(ctx, "secret", SetPolicyRequest{Provider: "fake", Interval: 48 * time.Hour})
+	require.NoError(t, err)
+
+	assert.Equal(t, expectedPolicy, policy)
+
+	secrets.AssertExpectations(t)
+	policies.AssertExpectations(t)
+}
+
+func TestService_SetPolicy_Invalid(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+
+	secrets := new(MockSecretStore)
+	secrets.On("Get", ctx, orgID, "secret").Return(Secret{ID: "secret", Type: "generic"}, nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, new(MockPolicyStore), secrets, nil, CloudProviders)
+
+	_, err := service.SetPolicy(ctx, "secret", SetPolicyRequest{Interval: time.Minute})
+	assert.True(t, errors.As(err, &ValidationError{}))
+
+	_, err = service.SetPolicy(ctx, "secret", SetPolicyRequest{Interval: time.Hour})
+	assert.True(t, errors.As(err, &ValidationError{}))
+}
+
+func TestService_RotateSecret(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+
+	secrets := new(MockSecretStore)
+	secrets.On("Get", ctx, orgID, "secret").Return(Secret{ID: "secret", Type: "google"}, nil)
+
+	policies := new(MockPolicyStore)
+	policies.On("Get", ctx, orgID, "secret").Return(Policy{}, NotFoundError{SecretID: "secret"})
+
+	rotations := new(MockRotationStarter)
+	rotations.On("StartRotation", ctx, orgID, "secret", "google").Return(nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, policies, secrets, rotations, CloudProviders)
+
+	err := service.RotateSecret(ctx, "secret")
+	require.NoError(t, err)
+
+	secrets.AssertExpectations(t)
+	policies.AssertExpectations(t)
+	rotations.AssertExpectations(t)
+}
+
+func TestService_RotateSecret_SecretNotFound(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+
+	secrets := new(MockSecretStore)
+	secrets.On("Get", ctx, orgID, "secret").Return(Secret{}, SecretNotFoundError{SecretID: "secret"})
+
+	policies := new(MockPolicyStore)
+	policies.On("Get", ctx, orgID, "secret").Return(Policy{}, NotFoundError{SecretID: "secret"})
+
+	service := NewService(orgIDExtractorStub{orgID}, policies, secrets, nil, CloudProviders)
+
+	err := service.RotateSecret(ctx, "secret")
+	assert.True(t, errors.As(err, &SecretNotFoundError{}))
+}
@@ -0,0 +1,96 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	"github.com/aliyun/alibaba-cloud-sdk-go/services/ram"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	"github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/secret/verify"
+)
+
+const defaultAlibabaRegion = "cn-beijing"
+
+// AlibabaKeyProvider rotates the access keys of RAM users.
+type AlibabaKeyProvider struct{}
+
+// NewAlibabaKeyProvider returns a new AlibabaKeyProvider.
+func NewAlibabaKeyProvider() AlibabaKeyProvider {
+	return AlibabaKeyProvider{}
+}
+
+// CreateKey creates a new access key for the RAM user the credentials belong to.
+func (AlibabaKeyProvider) CreateKey(ctx context.Context, values map[string]string) (map[string]string, error) {
+	client, err := newRAMClient(values)
+	if err != nil {
+		return nil, err
+	}
+
+	// When no user name is specified, RAM uses the user who signed the request
+	req := ram.CreateCreateAccessKeyRequest()
+	req.SetScheme(requests.HTTPS)
+
+	resp, err := client.CreateAccessKey(req)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create access key")
+	}
+
+	newValues := copyValues(values)
+	newValues[secrettype.AlibabaAccessKeyId] = resp.AccessKey.AccessKeyId
+	newValues[secrettype.AlibabaSecretAccessKey] = resp.AccessKey.AccessKeySecret
+
+	return newValues, nil
+}
+
+// VerifyKey verifies the credentials.
+func (AlibabaKeyProvider) VerifyKey(ctx context.Context, values map[string]string) error {
+	return verify.NewVerifier(cluster.Alibaba, values).VerifySecret()
+}
+
+// RevokeKey deletes the access key of the revoked credentials.
+func (AlibabaKeyProvider) RevokeKey(ctx context.Context, active map[string]string, revoked map[string]string) error {
+	client, err := newRAMClient(active)
+	if err != nil {
+		return err
+	}
+
+	req := ram.CreateDeleteAccessKeyRequest()
+	req.SetScheme(requests.HTTPS)
+	req.UserAccessKeyId = revoked[secrettype.AlibabaAccessKeyId]
+
+	if _, err := client.DeleteAccessKey(req); err != nil {
+		return errors.WrapIf(err, "failed to delete access key")
+	}
+
+	return nil
+}
+
+func newRAMClient(values map[string]string) (*ram.Client, error) {
+	client, err := ram.NewClientWithAccessKey(
+		defaultAlibabaRegion,
+		values[secrettype.AlibabaAccessKeyId],
+		values[secrettype.AlibabaSecretAccessKey],
+	)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create RAM client")
+	}
+
+	return client, nil
+}
@@ -0,0 +1,100 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	"github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/secret/verify"
+)
+
+// AmazonKeyProvider rotates the access keys of IAM users.
+type AmazonKeyProvider struct{}
+
+// NewAmazonKeyProvider returns a new AmazonKeyProvider.
+func NewAmazonKeyProvider() AmazonKeyProvider {
+	return AmazonKeyProvider{}
+}
+
+// CreateKey creates a new access key for the IAM user the credentials belong to.
+func (AmazonKeyProvider) CreateKey(ctx context.Context, values map[string]string) (map[string]string, error) {
+	client, err := newIAMClient(values)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.CreateAccessKeyWithContext(ctx, &iam.CreateAccessKeyInput{})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create access key")
+	}
+
+	newValues := copyValues(values)
+	newValues[secrettype.AwsAccessKeyId] = aws.StringValue(output.AccessKey.AccessKeyId)
+	newValues[secrettype.AwsSecretAccessKey] = aws.StringValue(output.AccessKey.SecretAccessKey)
+
+	return newValues, nil
+}
+
+// VerifyKey verifies the credentials.
+func (AmazonKeyProvider) VerifyKey(ctx context.Context, values map[string]string) error {
+	return verify.NewVerifier(cluster.Amazon, values).VerifySecret()
+}
+
+// RevokeKey deletes the access key of the revoked credentials.
+func (AmazonKeyProvider) RevokeKey(ctx context.Context, active map[string]string, revoked map[string]string) error {
+	client, err := newIAMClient(active)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteAccessKeyWithContext(ctx, &iam.DeleteAccessKeyInput{
+		AccessKeyId: aws.String(revoked[secrettype.AwsAccessKeyId]),
+	})
+	if err != nil {
+		return errors.WrapIf(err, "failed to delete access key")
+	}
+
+	return nil
+}
+
+// newIAMClient returns an IAM client acting as the IAM user the credentials belong to.
+// When no user name is specified, IAM uses the user who signed the request.
+func newIAMClient(values map[string]string) (*iam.IAM, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: verify.CreateAWSCredentials(values),
+		Region:      aws.String(verify.DefaultRegion),
+	})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create AWS session")
+	}
+
+	return iam.New(sess), nil
+}
+
+func copyValues(values map[string]string) map[string]string {
+	newValues := make(map[string]string, len(values))
+	for k, v := range values {
+		newValues[k] = v
+	}
+
+	return newValues
+}
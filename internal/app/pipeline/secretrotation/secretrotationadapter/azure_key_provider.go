@@ -0,0 +1,209 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/gofrs/uuid"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	"github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/secret/verify"
+)
+
+// azurePasswordLifetime is the lifetime of the client secrets created during rotations.
+const azurePasswordLifetime = 2 * 365 * 24 * time.Hour
+
+// AzureKeyProvider rotates the client secrets of Azure AD applications.
+//
+// Client secrets cannot be read back once created, so each client secret created by the provider is tagged
+// with a hash of its value to be able to revoke it later.
+type AzureKeyProvider struct{}
+
+// NewAzureKeyProvider returns a new AzureKeyProvider.
+func NewAzureKeyProvider() AzureKeyProvider {
+	return AzureKeyProvider{}
+}
+
+// CreateKey creates a new client secret for the application the credentials belong to.
+func (AzureKeyProvider) CreateKey(ctx context.Context, values map[string]string) (map[string]string, error) {
+	client, objectID, err := newAzureApplicationsClient(ctx, values)
+	if err != nil {
+		return nil, err
+	}
+
+	passwords, err := client.ListPasswordCredentials(ctx, objectID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list client secrets")
+	}
+
+	rawPassword := make([]byte, 32)
+	if _, err := rand.Read(rawPassword); err != nil {
+		return nil, errors.WrapIf(err, "failed to generate client secret")
+	}
+
+	password := base64.RawURLEncoding.EncodeToString(rawPassword)
+	keyID := uuid.Must(uuid.NewV4()).String()
+	identifier := azurePasswordIdentifier(password)
+	now := time.Now()
+
+	credentials := append(passwordCredentials(passwords), graphrbac.PasswordCredential{
+		KeyID:               &keyID,
+		Value:               &password,
+		CustomKeyIdentifier: &identifier,
+		StartDate:           &date.Time{Time: now},
+		EndDate:             &date.Time{Time: now.Add(azurePasswordLifetime)},
+	})
+
+	_, err = client.UpdatePasswordCredentials(ctx, objectID, graphrbac.PasswordCredentialsUpdateParameters{Value: &credentials})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create client secret")
+	}
+
+	newValues := copyValues(values)
+	newValues[secrettype.AzureClientSecret] = password
+
+	return newValues, nil
+}
+
+// VerifyKey verifies the credentials.
+func (AzureKeyProvider) VerifyKey(ctx context.Context, values map[string]string) error {
+	return verify.NewVerifier(cluster.Azure, values).VerifySecret()
+}
+
+// RevokeKey deletes the client secret of the revoked credentials.
+//
+// Client secrets which were not created by the provider cannot be identified,
+// so every untagged client secret older than the active one is deleted in their case.
+func (AzureKeyProvider) RevokeKey(ctx context.Context, active map[string]string, revoked map[string]string) error {
+	client, objectID, err := newAzureApplicationsClient(ctx, active)
+	if err != nil {
+		return err
+	}
+
+	passwords, err := client.ListPasswordCredentials(ctx, objectID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to list client secrets")
+	}
+
+	credentials := passwordCredentials(passwords)
+	kept := removeRevokedPasswords(
+		credentials,
+		azurePasswordIdentifier(active[secrettype.AzureClientSecret]),
+		azurePasswordIdentifier(revoked[secrettype.AzureClientSecret]),
+	)
+
+	if len(kept) == len(credentials) {
+		return nil
+	}
+
+	_, err = client.UpdatePasswordCredentials(ctx, objectID, graphrbac.PasswordCredentialsUpdateParameters{Value: &kept})
+	if err != nil {
+		return errors.WrapIf(err, "failed to delete client secret")
+	}
+
+	return nil
+}
+
+// newAzureApplicationsClient returns an Azure AD application client and the object ID of the application
+// the credentials belong to.
+func newAzureApplicationsClient(ctx context.Context, values map[string]string) (graphrbac.ApplicationsClient, string, error) {
+	config := auth.NewClientCredentialsConfig(
+		values[secrettype.AzureClientID],
+		values[secrettype.AzureClientSecret],
+		values[secrettype.AzureTenantID],
+	)
+	config.Resource = azure.PublicCloud.GraphEndpoint
+
+	authorizer, err := config.Authorizer()
+	if err != nil {
+		return graphrbac.ApplicationsClient{}, "", errors.WrapIf(err, "failed to create Azure authorizer")
+	}
+
+	client := graphrbac.NewApplicationsClient(values[secrettype.AzureTenantID])
+	client.Authorizer = authorizer
+
+	applications, err := client.List(ctx, fmt.Sprintf("appId eq '%s'", values[secrettype.AzureClientID]))
+	if err != nil {
+		return graphrbac.ApplicationsClient{}, "", errors.WrapIf(err, "failed to find application")
+	}
+
+	if len(applications.Values()) == 0 || applications.Values()[0].ObjectID == nil {
+		return graphrbac.ApplicationsClient{}, "", errors.NewWithDetails("application not found", "clientId", values[secrettype.AzureClientID])
+	}
+
+	return client, *applications.Values()[0].ObjectID, nil
+}
+
+func passwordCredentials(result graphrbac.PasswordCredentialListResult) []graphrbac.PasswordCredential {
+	if result.Value == nil {
+		return nil
+	}
+
+	return *result.Value
+}
+
+// removeRevokedPasswords returns the client secrets which are kept after revoking a client secret.
+func removeRevokedPasswords(
+	credentials []graphrbac.PasswordCredential,
+	activeIdentifier []byte,
+	revokedIdentifier []byte,
+) []graphrbac.PasswordCredential {
+	var activeCredential *graphrbac.PasswordCredential
+	for i, credential := range credentials {
+		if hasIdentifier(credential, activeIdentifier) {
+			activeCredential = &credentials[i]
+		}
+	}
+
+	var kept []graphrbac.PasswordCredential
+	for _, credential := range credentials {
+		switch {
+		case hasIdentifier(credential, revokedIdentifier):
+			continue
+
+		case credential.CustomKeyIdentifier == nil && activeCredential != nil &&
+			credential.StartDate != nil && activeCredential.StartDate != nil &&
+			credential.StartDate.Before(activeCredential.StartDate.Time):
+			continue
+		}
+
+		kept = append(kept, credential)
+	}
+
+	return kept
+}
+
+func azurePasswordIdentifier(password string) []byte {
+	sum := sha256.Sum256([]byte(password))
+
+	return sum[:16]
+}
+
+func hasIdentifier(credential graphrbac.PasswordCredential, identifier []byte) bool {
+	return credential.CustomKeyIdentifier != nil && bytes.Equal(*credential.CustomKeyIdentifier, identifier)
+}
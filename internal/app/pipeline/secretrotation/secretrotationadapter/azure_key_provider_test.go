@@ -0,0 +1,87 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveRevokedPasswords(t *testing.T) {
+	now := time.Date(2019, time.November, 10, 10, 0, 0, 0, time.UTC)
+
+	newCredential := func(keyID string, password string, startDate time.Time) graphrbac.PasswordCredential {
+		credential := graphrbac.PasswordCredential{
+			KeyID:     &keyID,
+			StartDate: &date.Time{Time: startDate},
+		}
+
+		if password != "" {
+			identifier := azurePasswordIdentifier(password)
+			credential.CustomKeyIdentifier = &identifier
+		}
+
+		return credential
+	}
+
+	initial := newCredential("initial", "", now.Add(-48*time.Hour))
+	first := newCredential("first", "first", now.Add(-24*time.Hour))
+	second := newCredential("second", "second", now)
+	unrelated := newCredential("unrelated", "", now.Add(time.Hour))
+
+	tests := map[string]struct {
+		credentials []graphrbac.PasswordCredential
+		active      string
+		revoked     string
+		kept        []graphrbac.PasswordCredential
+	}{
+		"tagged": {
+			credentials: []graphrbac.PasswordCredential{first, second},
+			active:      "second",
+			revoked:     "first",
+			kept:        []graphrbac.PasswordCredential{second},
+		},
+		"rollback": {
+			credentials: []graphrbac.PasswordCredential{initial, first},
+			active:      "initial",
+			revoked:     "first",
+			kept:        []graphrbac.PasswordCredential{initial},
+		},
+		"untagged": {
+			credentials: []graphrbac.PasswordCredential{initial, first, unrelated},
+			active:      "first",
+			revoked:     "initial",
+			kept:        []graphrbac.PasswordCredential{first, unrelated},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			kept := removeRevokedPasswords(
+				test.credentials,
+				azurePasswordIdentifier(test.active),
+				azurePasswordIdentifier(test.revoked),
+			)
+
+			assert.Equal(t, test.kept, kept)
+		})
+	}
+}
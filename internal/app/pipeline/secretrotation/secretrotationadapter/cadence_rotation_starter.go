@@ -0,0 +1,67 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"go.uber.org/cadence/.gen/go/shared"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+)
+
+// CadenceRotationStarter starts secret rotations using Uber Cadence.
+type CadenceRotationStarter struct {
+	cadenceClient client.Client
+}
+
+// NewCadenceRotationStarter returns a new CadenceRotationStarter.
+func NewCadenceRotationStarter(cadenceClient client.Client) CadenceRotationStarter {
+	return CadenceRotationStarter{
+		cadenceClient: cadenceClient,
+	}
+}
+
+// StartRotation starts rotating a secret.
+func (s CadenceRotationStarter) StartRotation(ctx context.Context, organizationID uint, secretID string, provider string) error {
+	// The workflow ID makes sure that a secret is never rotated concurrently
+	workflowID := fmt.Sprintf("%s-%d-%s", secretrotation.WorkflowName, organizationID, secretID)
+
+	options := client.StartWorkflowOptions{
+		ID:                           workflowID,
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: 2 * time.Hour,
+		WorkflowIDReusePolicy:        client.WorkflowIDReusePolicyAllowDuplicate,
+	}
+
+	input := secretrotation.WorkflowInput{
+		OrganizationID: organizationID,
+		SecretID:       secretID,
+		Provider:       provider,
+	}
+
+	_, err := s.cadenceClient.StartWorkflow(ctx, options, secretrotation.WorkflowName, input)
+	if _, ok := err.(*shared.WorkflowExecutionAlreadyStartedError); ok {
+		return secretrotation.AlreadyRotatingError{SecretID: secretID}
+	} else if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to start secret rotation", "workflowId", workflowID)
+	}
+
+	return nil
+}
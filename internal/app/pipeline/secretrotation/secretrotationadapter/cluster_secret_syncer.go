@@ -0,0 +1,130 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// ClusterManager lists the clusters of an organization.
+type ClusterManager interface {
+	GetClusters(ctx context.Context, organizationID uint) ([]cluster.CommonCluster, error)
+}
+
+// ClusterSecretSyncer reinstalls secrets to the clusters they were installed to.
+type ClusterSecretSyncer struct {
+	clusters ClusterManager
+	secrets  InternalSecretStore
+}
+
+// NewClusterSecretSyncer returns a new ClusterSecretSyncer.
+func NewClusterSecretSyncer(clusters ClusterManager, secrets InternalSecretStore) ClusterSecretSyncer {
+	return ClusterSecretSyncer{
+		clusters: clusters,
+		secrets:  secrets,
+	}
+}
+
+// SyncSecret reinstalls a secret to every namespace of every running cluster of an organization it was installed to.
+func (s ClusterSecretSyncer) SyncSecret(ctx context.Context, organizationID uint, secretID string) error {
+	item, err := s.secrets.Get(organizationID, secretID)
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get secret", "secretId", secretID)
+	}
+
+	clusters, err := s.clusters.GetClusters(ctx, organizationID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to list clusters")
+	}
+
+	var errs []error
+
+	for _, c := range clusters {
+		status, err := c.GetStatus()
+		if err != nil || status.Status != pkgCluster.Running {
+			continue
+		}
+
+		if err := syncClusterSecret(c, item); err != nil {
+			errs = append(errs, errors.WithDetails(err, "clusterId", c.GetID()))
+		}
+	}
+
+	return errors.Combine(errs...)
+}
+
+func syncClusterSecret(c cluster.CommonCluster, item *secret.SecretItemResponse) error {
+	kubeConfig, err := c.GetK8sConfig()
+	if err != nil {
+		return errors.WrapIf(err, "failed to get kubeconfig")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create kubernetes client")
+	}
+
+	return syncInstalledSecrets(client, item)
+}
+
+// syncInstalledSecrets updates the Kubernetes secrets installed from a secret.
+// Secrets that were not installed by Pipeline from this secret are left untouched, even if their names match.
+func syncInstalledSecrets(client kubernetes.Interface, item *secret.SecretItemResponse) error {
+	installed, err := client.CoreV1().Secrets(metav1.NamespaceAll).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", intSecret.SourceLabel, intSecret.SourceLabelValue),
+	})
+	if err != nil {
+		return errors.WrapIf(err, "failed to list installed secrets")
+	}
+
+	for _, installedSecret := range installed.Items {
+		if !intSecret.IsKubeSecretSource(installedSecret, item.ID) {
+			continue
+		}
+
+		kubeSecret, err := intSecret.CreateKubeSecret(intSecret.KubeSecretRequest{
+			Name:      installedSecret.Name,
+			Namespace: installedSecret.Namespace,
+			Type:      item.Type,
+			Values:    item.Values,
+			SourceID:  item.ID,
+		})
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to create kubernetes secret", "namespace", installedSecret.Namespace)
+		}
+
+		installedSecret := installedSecret
+		installedSecret.Data = nil // Clear data so that it is created from string data again
+		installedSecret.StringData = kubeSecret.StringData
+
+		_, err = client.CoreV1().Secrets(installedSecret.Namespace).Update(&installedSecret)
+		if err != nil {
+			return errors.WrapIfWithDetails(err, "failed to update installed secret", "namespace", installedSecret.Namespace)
+		}
+	}
+
+	return nil
+}
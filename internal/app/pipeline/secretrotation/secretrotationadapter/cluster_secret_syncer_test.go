@@ -0,0 +1,89 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	intSecret "github.com/banzaicloud/pipeline/internal/secret"
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+func TestSyncInstalledSecrets(t *testing.T) {
+	item := &secret.SecretItemResponse{
+		ID:     "secret-id",
+		Name:   "my-secret",
+		Type:   secrettype.PasswordSecretType,
+		Values: map[string]string{secrettype.Username: "user", secrettype.Password: "rotated"},
+	}
+
+	installed := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-secret",
+			Namespace:   "installed",
+			Labels:      map[string]string{intSecret.SourceLabel: intSecret.SourceLabelValue},
+			Annotations: map[string]string{intSecret.SourceIDAnnotation: item.ID},
+		},
+		Data: map[string][]byte{secrettype.Password: []byte("old")},
+	}
+
+	// installed by Pipeline from another secret
+	other := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-secret",
+			Namespace:   "other",
+			Labels:      map[string]string{intSecret.SourceLabel: intSecret.SourceLabelValue},
+			Annotations: map[string]string{intSecret.SourceIDAnnotation: "other-id"},
+		},
+		Data: map[string][]byte{secrettype.Password: []byte("other")},
+	}
+
+	// not installed by Pipeline
+	foreign := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-secret",
+			Namespace: "foreign",
+		},
+		Data: map[string][]byte{secrettype.Password: []byte("foreign")},
+	}
+
+	client := fake.NewSimpleClientset(&installed, &other, &foreign)
+
+	err := syncInstalledSecrets(client, item)
+	require.NoError(t, err)
+
+	synced, err := client.CoreV1().Secrets("installed").Get("my-secret", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "rotated", synced.StringData[secrettype.Password])
+	assert.True(t, intSecret.IsKubeSecretSource(*synced, item.ID))
+
+	otherSecret, err := client.CoreV1().Secrets("other").Get("my-secret", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, other, *otherSecret)
+
+	foreignSecret, err := client.CoreV1().Secrets("foreign").Get("my-secret", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, foreign, *foreignSecret)
+}
@@ -0,0 +1,109 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"emperror.dev/errors"
+	"google.golang.org/api/iam/v1"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	"github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/secret/verify"
+)
+
+// GoogleKeyProvider rotates the keys of service accounts.
+type GoogleKeyProvider struct{}
+
+// NewGoogleKeyProvider returns a new GoogleKeyProvider.
+func NewGoogleKeyProvider() GoogleKeyProvider {
+	return GoogleKeyProvider{}
+}
+
+// CreateKey creates a new key for the service account the credentials belong to.
+func (GoogleKeyProvider) CreateKey(ctx context.Context, values map[string]string) (map[string]string, error) {
+	service, err := newIAMService(values)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("projects/-/serviceAccounts/%s", values[secrettype.ClientEmail])
+
+	key, err := service.Projects.ServiceAccounts.Keys.Create(name, &iam.CreateServiceAccountKeyRequest{}).Context(ctx).Do()
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create service account key")
+	}
+
+	// The key data is a service account key file
+	keyFile, err := base64.StdEncoding.DecodeString(key.PrivateKeyData)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to decode service account key")
+	}
+
+	var keyValues map[string]string
+	if err := json.Unmarshal(keyFile, &keyValues); err != nil {
+		return nil, errors.WrapIf(err, "failed to parse service account key")
+	}
+
+	newValues := copyValues(values)
+	for k, v := range keyValues {
+		newValues[k] = v
+	}
+
+	return newValues, nil
+}
+
+// VerifyKey verifies the credentials.
+func (GoogleKeyProvider) VerifyKey(ctx context.Context, values map[string]string) error {
+	return verify.NewVerifier(cluster.Google, values).VerifySecret()
+}
+
+// RevokeKey deletes the service account key of the revoked credentials.
+func (GoogleKeyProvider) RevokeKey(ctx context.Context, active map[string]string, revoked map[string]string) error {
+	service, err := newIAMService(active)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf(
+		"projects/-/serviceAccounts/%s/keys/%s",
+		revoked[secrettype.ClientEmail],
+		revoked[secrettype.PrivateKeyId],
+	)
+
+	if _, err := service.Projects.ServiceAccounts.Keys.Delete(name).Context(ctx).Do(); err != nil {
+		return errors.WrapIf(err, "failed to delete service account key")
+	}
+
+	return nil
+}
+
+func newIAMService(values map[string]string) (*iam.Service, error) {
+	client, err := verify.CreateOath2Client(verify.CreateServiceAccount(values), iam.CloudPlatformScope)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create OAuth2 client")
+	}
+
+	service, err := iam.New(client)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create IAM client")
+	}
+
+	return service, nil
+}
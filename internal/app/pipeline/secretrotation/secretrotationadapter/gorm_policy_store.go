@@ -0,0 +1,140 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+)
+
+// TableName constants
+const (
+	policyTableName = "secret_rotation_policies"
+)
+
+type policyModel struct {
+	ID             uint `gorm:"primary_key"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrganizationID uint   `gorm:"unique_index:idx_secret_rotation_policies_org_secret;not null"`
+	SecretID       string `gorm:"unique_index:idx_secret_rotation_policies_org_secret;not null"`
+	Provider       string
+	Interval       int64 // seconds
+	LastRotatedAt  *time.Time
+	NextRotationAt time.Time `gorm:"index"`
+	LastError      string    `sql:"type:text"`
+}
+
+// TableName changes the default table name.
+func (policyModel) TableName() string {
+	return policyTableName
+}
+
+// GormPolicyStore stores rotation policies using Gorm for data persistence.
+type GormPolicyStore struct {
+	db *gorm.DB
+}
+
+// NewGormPolicyStore returns a new GormPolicyStore.
+func NewGormPolicyStore(db *gorm.DB) GormPolicyStore {
+	return GormPolicyStore{
+		db: db,
+	}
+}
+
+// Get returns the rotation policy of a secret.
+func (s GormPolicyStore) Get(ctx context.Context, organizationID uint, secretID string) (secretrotation.Policy, error) {
+	var model policyModel
+
+	err := s.db.Where(policyModel{OrganizationID: organizationID, SecretID: secretID}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return secretrotation.Policy{}, secretrotation.NotFoundError{SecretID: secretID}
+	}
+	if err != nil {
+		return secretrotation.Policy{}, errors.WrapIfWithDetails(err, "failed to find rotation policy", "secretId", secretID)
+	}
+
+	return toPolicy(model), nil
+}
+
+// Save creates or updates a rotation policy.
+func (s GormPolicyStore) Save(ctx context.Context, policy secretrotation.Policy) error {
+	model := policyModel{
+		OrganizationID: policy.OrganizationID,
+		SecretID:       policy.SecretID,
+	}
+
+	err := s.db.Where(model).FirstOrInit(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to find rotation policy", "secretId", policy.SecretID)
+	}
+
+	model.Provider = policy.Provider
+	model.Interval = int64(policy.Interval / time.Second)
+	model.LastRotatedAt = policy.LastRotatedAt
+	model.NextRotationAt = policy.NextRotationAt
+	model.LastError = policy.LastError
+
+	err = s.db.Save(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to save rotation policy", "secretId", policy.SecretID)
+	}
+
+	return nil
+}
+
+// Delete deletes the rotation policy of a secret.
+func (s GormPolicyStore) Delete(ctx context.Context, organizationID uint, secretID string) error {
+	err := s.db.Where(policyModel{OrganizationID: organizationID, SecretID: secretID}).Delete(policyModel{}).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete rotation policy", "secretId", secretID)
+	}
+
+	return nil
+}
+
+// FindDue returns every policy that should have been rotated by a given time.
+func (s GormPolicyStore) FindDue(ctx context.Context, now time.Time) ([]secretrotation.Policy, error) {
+	var models []policyModel
+
+	err := s.db.Where("next_rotation_at <= ?", now).Order("next_rotation_at").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to find due rotation policies")
+	}
+
+	policies := make([]secretrotation.Policy, 0, len(models))
+	for _, model := range models {
+		policies = append(policies, toPolicy(model))
+	}
+
+	return policies, nil
+}
+
+func toPolicy(model policyModel) secretrotation.Policy {
+	return secretrotation.Policy{
+		OrganizationID: model.OrganizationID,
+		SecretID:       model.SecretID,
+		Provider:       model.Provider,
+		Interval:       time.Duration(model.Interval) * time.Second,
+		LastRotatedAt:  model.LastRotatedAt,
+		NextRotationAt: model.NextRotationAt,
+		LastError:      model.LastError,
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+)
+
+func testGormPolicyStore(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = Migrate(db, commonadapter.NewNoopLogger())
+	require.NoError(t, err)
+
+	store := NewGormPolicyStore(db)
+
+	now := time.Date(2019, time.November, 10, 10, 0, 0, 0, time.UTC)
+
+	policy := secretrotation.Policy{
+		OrganizationID: 1,
+		SecretID:       "secret",
+		Provider:       "amazon",
+		Interval:       24 * time.Hour,
+		NextRotationAt: now.Add(24 * time.Hour),
+	}
+
+	err = store.Save(ctx, policy)
+	require.NoError(t, err)
+
+	err = store.Save(ctx, secretrotation.Policy{
+		OrganizationID: 2,
+		SecretID:       "secret",
+		Provider:       "google",
+		Interval:       time.Hour,
+		NextRotationAt: now.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+
+	foundPolicy, err := store.Get(ctx, 1, "secret")
+	require.NoError(t, err)
+
+	assert.Equal(t, policy.Provider, foundPolicy.Provider)
+	assert.Equal(t, policy.Interval, foundPolicy.Interval)
+	assert.True(t, policy.NextRotationAt.Equal(foundPolicy.NextRotationAt))
+	assert.Nil(t, foundPolicy.LastRotatedAt)
+
+	due, err := store.FindDue(ctx, now)
+	require.NoError(t, err)
+
+	require.Len(t, due, 1)
+	assert.Equal(t, uint(2), due[0].OrganizationID)
+
+	policy.LastRotatedAt = &now
+	policy.NextRotationAt = now
+	policy.LastError = "failed"
+
+	err = store.Save(ctx, policy)
+	require.NoError(t, err)
+
+	foundPolicy, err = store.Get(ctx, 1, "secret")
+	require.NoError(t, err)
+
+	require.NotNil(t, foundPolicy.LastRotatedAt)
+	assert.True(t, now.Equal(*foundPolicy.LastRotatedAt))
+	assert.Equal(t, "failed", foundPolicy.LastError)
+
+	due, err = store.FindDue(ctx, now)
+	require.NoError(t, err)
+
+	assert.Len(t, due, 2)
+
+	err = store.Delete(ctx, 1, "secret")
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, 1, "secret")
+	assert.True(t, errors.As(err, &secretrotation.NotFoundError{}))
+}
@@ -0,0 +1,105 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// InternalSecretStore is an interface for the internal secret store.
+type InternalSecretStore interface {
+	// Get retrieves the latest version of a secret.
+	Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error)
+
+	// GetVersion retrieves a specific version of a secret.
+	GetVersion(organizationID uint, secretID string, version int) (*secret.SecretItemResponse, error)
+
+	// Update updates a secret.
+	Update(organizationID uint, secretID string, request *secret.CreateSecretRequest) error
+
+	// Rollback creates a new version of a secret with the content of a previous version.
+	Rollback(organizationID uint, secretID string, version int, updatedBy string) error
+}
+
+// SecretStore is a wrapper for the internal secret store.
+type SecretStore struct {
+	secrets InternalSecretStore
+}
+
+// NewSecretStore returns a wrapper for the internal secret store.
+func NewSecretStore(secrets InternalSecretStore) SecretStore {
+	return SecretStore{
+		secrets: secrets,
+	}
+}
+
+// Get returns the latest version of a secret.
+func (s SecretStore) Get(ctx context.Context, organizationID uint, secretID string) (secretrotation.Secret, error) {
+	item, err := s.secrets.Get(organizationID, secretID)
+	if err == secret.ErrSecretNotExists {
+		return secretrotation.Secret{}, secretrotation.SecretNotFoundError{SecretID: secretID}
+	} else if err != nil {
+		return secretrotation.Secret{}, errors.WrapIfWithDetails(err, "failed to get secret", "secretId", secretID)
+	}
+
+	return toSecret(item), nil
+}
+
+// GetVersion returns a specific version of a secret.
+func (s SecretStore) GetVersion(ctx context.Context, organizationID uint, secretID string, version int) (secretrotation.Secret, error) {
+	item, err := s.secrets.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		return secretrotation.Secret{}, errors.WrapIfWithDetails(err, "failed to get secret version", "secretId", secretID, "version", version)
+	}
+
+	return toSecret(item), nil
+}
+
+// Update stores the values of a secret as a new version.
+func (s SecretStore) Update(ctx context.Context, organizationID uint, sec secretrotation.Secret) error {
+	version := sec.Version
+
+	request := secret.CreateSecretRequest{
+		Name:      sec.Name,
+		Type:      sec.Type,
+		Values:    sec.Values,
+		Tags:      sec.Tags,
+		Version:   &version,
+		UpdatedBy: secretrotation.UpdatedBy,
+	}
+
+	return s.secrets.Update(organizationID, sec.ID, &request)
+}
+
+// Rollback restores a previous version of a secret as a new version.
+func (s SecretStore) Rollback(ctx context.Context, organizationID uint, secretID string, version int) error {
+	return s.secrets.Rollback(organizationID, secretID, version, secretrotation.UpdatedBy)
+}
+
+func toSecret(item *secret.SecretItemResponse) secretrotation.Secret {
+	return secretrotation.Secret{
+		ID:      item.ID,
+		Name:    item.Name,
+		Type:    item.Type,
+		Values:  item.Values,
+		Tags:    item.Tags,
+		Version: item.Version,
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/banzaicloud/bank-vaults/pkg/sdk/tls"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+// TLSKeyProvider renews TLS certificate chains generated by Pipeline.
+type TLSKeyProvider struct {
+	defaultValidity string
+}
+
+// NewTLSKeyProvider returns a new TLSKeyProvider.
+// Certificates of secrets without a validity are renewed with the default validity.
+func NewTLSKeyProvider(defaultValidity string) TLSKeyProvider {
+	return TLSKeyProvider{
+		defaultValidity: defaultValidity,
+	}
+}
+
+// caValidityFactor is the validity of a re-issued CA relative to the validity of the certificates it signs.
+const caValidityFactor = 2
+
+// CreateKey renews the server, client and peer certificates for the same hosts.
+//
+// The certificates are signed by the existing CA key, so that clients trusting it keep working.
+// When the CA would expire before the renewed certificates, it is re-issued with the same key and subject,
+// otherwise the certificates could never outlive the CA and every renewal would yield the same expiry.
+// A new CA is generated only if the secret has no CA or it has already expired.
+func (p TLSKeyProvider) CreateKey(ctx context.Context, values map[string]string) (map[string]string, error) {
+	validity := values[secrettype.TLSValidity]
+	if validity == "" {
+		validity = p.defaultValidity
+	}
+
+	validityDuration, err := time.ParseDuration(validity)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "invalid certificate validity", "validity", validity)
+	}
+
+	var cc *tls.CertificateChain
+
+	caCert, caKey, err := parseCA(values)
+	if err != nil {
+		return nil, err
+	}
+
+	if caCert != nil && time.Now().Before(caCert.NotAfter) {
+		caCertPEM := values[secrettype.CACert]
+
+		if time.Until(caCert.NotAfter) < validityDuration {
+			caCert, caCertPEM, err = reissueCA(caCert, caKey, caValidityFactor*validityDuration)
+			if err != nil {
+				return nil, errors.WrapIf(err, "failed to re-issue CA certificate")
+			}
+		}
+
+		cc, err = renewTLS(values[secrettype.TLSHosts], validityDuration, caCert, caKey)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to renew certificates")
+		}
+
+		cc.CACert = caCertPEM
+		cc.CAKey = values[secrettype.CAKey]
+
+		if values[secrettype.PeerCert] == "" {
+			cc.PeerCert = ""
+			cc.PeerKey = ""
+		}
+	} else {
+		cc, err = tls.GenerateTLS(values[secrettype.TLSHosts], validity)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to generate certificates")
+		}
+	}
+
+	newValues := copyValues(values)
+
+	err = mapstructure.Decode(cc, &newValues)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to decode certificates")
+	}
+
+	// keep the peer certificate out of secrets that never had one
+	if cc.PeerCert == "" {
+		delete(newValues, secrettype.PeerCert)
+		delete(newValues, secrettype.PeerKey)
+	}
+
+	return newValues, nil
+}
+
+// reissueCA issues a new self-signed certificate for an existing CA with the same key and subject.
+// Certificates signed by the CA key are verified by both the previous and the re-issued CA certificate.
+func reissueCA(caCert *x509.Certificate, caKey crypto.Signer, validity time.Duration) (*x509.Certificate, string, error) {
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, "", errors.WrapIf(err, "failed to generate serial number")
+	}
+
+	notBefore := time.Now()
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               caCert.Subject,
+		SubjectKeyId:          caCert.SubjectKeyId,
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(validity),
+		KeyUsage:              caCert.KeyUsage,
+		ExtKeyUsage:           caCert.ExtKeyUsage,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, caKey.Public(), caKey)
+	if err != nil {
+		return nil, "", errors.WrapIf(err, "failed to create certificate")
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, "", errors.WrapIf(err, "failed to parse certificate")
+	}
+
+	return cert, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
+
+// renewTLS generates server, client and peer certificates signed by an existing CA.
+func renewTLS(hosts string, validity time.Duration, caCert *x509.Certificate, caKey crypto.Signer) (*tls.CertificateChain, error) {
+	sHosts := tls.NewSeparatedCertHosts(hosts)
+
+	var dnsNames []string
+	commonName := "Banzai Cloud Generated Server Cert"
+	if len(sHosts.WildCardHosts) != 0 {
+		commonName = sHosts.WildCardHosts[0]
+		dnsNames = append(dnsNames, sHosts.WildCardHosts...)
+	}
+	dnsNames = append(dnsNames, sHosts.Hosts...)
+
+	serverCert, err := tls.GenerateServerCertificate(tls.ServerCertificateRequest{
+		Subject: pkix.Name{
+			Organization: []string{"Banzai Cloud"},
+			CommonName:   commonName,
+		},
+		Validity:    validity,
+		DNSNames:    dnsNames,
+		IPAddresses: sHosts.IPs,
+	}, caCert, caKey)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to generate server certificate")
+	}
+
+	clientCert, err := tls.GenerateClientCertificate(tls.ClientCertificateRequest{
+		Subject: pkix.Name{
+			Organization: []string{"Banzai Cloud"},
+			CommonName:   "Banzai Cloud Generated Client Cert",
+		},
+		Validity: validity,
+	}, caCert, caKey)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to generate client certificate")
+	}
+
+	peerCommonName := "Banzai Cloud Generated Peer Cert"
+	if len(sHosts.WildCardHosts) != 0 {
+		peerCommonName = sHosts.WildCardHosts[0]
+	}
+
+	peerCert, err := tls.GeneratePeerCertificate(tls.PeerCertificateRequest{
+		Subject: pkix.Name{
+			Organization: []string{"Banzai Cloud"},
+			CommonName:   peerCommonName,
+		},
+		Validity:    validity,
+		DNSNames:    dnsNames,
+		IPAddresses: sHosts.IPs,
+	}, caCert, caKey)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to generate peer certificate")
+	}
+
+	return &tls.CertificateChain{
+		ServerKey:  string(serverCert.Key),
+		ServerCert: string(serverCert.Certificate),
+		ClientKey:  string(clientCert.Key),
+		ClientCert: string(clientCert.Certificate),
+		PeerKey:    string(peerCert.Key),
+		PeerCert:   string(peerCert.Certificate),
+	}, nil
+}
+
+// parseCA parses the CA certificate and key of a secret.
+// It returns nil if the secret has no CA.
+func parseCA(values map[string]string) (*x509.Certificate, crypto.Signer, error) {
+	if values[secrettype.CACert] == "" || values[secrettype.CAKey] == "" {
+		return nil, nil, nil
+	}
+
+	caCert, err := parseCertificate(values[secrettype.CACert])
+	if err != nil {
+		return nil, nil, errors.WithDetails(err, "value", secrettype.CACert)
+	}
+
+	caKey, err := parsePrivateKey(values[secrettype.CAKey])
+	if err != nil {
+		return nil, nil, errors.WithDetails(err, "value", secrettype.CAKey)
+	}
+
+	return caCert, caKey, nil
+}
+
+// VerifyKey verifies that the server certificate is currently valid and signed by the CA.
+func (TLSKeyProvider) VerifyKey(ctx context.Context, values map[string]string) error {
+	ca, err := parseCertificate(values[secrettype.CACert])
+	if err != nil {
+		return errors.WithDetails(err, "value", secrettype.CACert)
+	}
+
+	server, err := parseCertificate(values[secrettype.ServerCert])
+	if err != nil {
+		return errors.WithDetails(err, "value", secrettype.ServerCert)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	_, err = server.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return errors.WrapIf(err, "failed to verify server certificate")
+	}
+
+	return nil
+}
+
+// RevokeKey does nothing: the previous certificates simply expire.
+func (TLSKeyProvider) RevokeKey(ctx context.Context, active map[string]string, revoked map[string]string) error {
+	return nil
+}
+
+func parseCertificate(value string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to parse certificate")
+	}
+
+	return cert, nil
+}
+
+func parsePrivateKey(value string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to parse private key")
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+
+	return signer, nil
+}
@@ -0,0 +1,137 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationadapter
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+func TestTLSKeyProvider(t *testing.T) {
+	provider := NewTLSKeyProvider("24h")
+
+	values := map[string]string{
+		secrettype.TLSHosts: "example.org",
+	}
+
+	renewed, err := provider.CreateKey(context.Background(), values)
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.org", renewed[secrettype.TLSHosts])
+	assert.NotEmpty(t, renewed[secrettype.CACert])
+	assert.NotEmpty(t, renewed[secrettype.ServerCert])
+	assert.NotContains(t, values, secrettype.CACert, "the original values should not be modified")
+
+	err = provider.VerifyKey(context.Background(), renewed)
+	require.NoError(t, err)
+
+	other, err := provider.CreateKey(context.Background(), values)
+	require.NoError(t, err)
+
+	// A server certificate signed by a different CA
+	renewed[secrettype.ServerCert] = other[secrettype.ServerCert]
+
+	err = provider.VerifyKey(context.Background(), renewed)
+	assert.Error(t, err)
+}
+
+func TestTLSKeyProvider_KeepsCA(t *testing.T) {
+	provider := NewTLSKeyProvider("24h")
+
+	original, err := provider.CreateKey(context.Background(), map[string]string{
+		secrettype.TLSHosts: "example.org,10.0.0.1",
+	})
+	require.NoError(t, err)
+
+	renewed, err := provider.CreateKey(context.Background(), original)
+	require.NoError(t, err)
+
+	assert.Equal(t, original[secrettype.CAKey], renewed[secrettype.CAKey])
+	assert.NotEqual(t, original[secrettype.ServerCert], renewed[secrettype.ServerCert])
+	assert.NotEqual(t, original[secrettype.ServerKey], renewed[secrettype.ServerKey])
+
+	ca, err := parseCertificate(original[secrettype.CACert])
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	server, err := parseCertificate(renewed[secrettype.ServerCert])
+	require.NoError(t, err)
+
+	_, err = server.Verify(x509.VerifyOptions{
+		DNSName:   "example.org",
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "10.0.0.1", server.IPAddresses[0].String())
+
+	client, err := parseCertificate(renewed[secrettype.ClientCert])
+	require.NoError(t, err)
+
+	_, err = client.Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	require.NoError(t, err)
+
+	renewedCA, err := parseCertificate(renewed[secrettype.CACert])
+	require.NoError(t, err)
+
+	assert.Equal(t, ca.Subject, renewedCA.Subject)
+	assert.False(t, server.NotAfter.After(renewedCA.NotAfter), "the certificate must not outlive the CA")
+}
+
+func TestTLSKeyProvider_RenewalExtendsExpiry(t *testing.T) {
+	provider := NewTLSKeyProvider("24h")
+
+	// The generated CA expires together with the certificates it signs
+	original, err := provider.CreateKey(context.Background(), map[string]string{
+		secrettype.TLSHosts: "example.org",
+	})
+	require.NoError(t, err)
+
+	originalExpiresAt, err := secret.CertificateExpiry(secrettype.TLSSecretType, original)
+	require.NoError(t, err)
+
+	time.Sleep(time.Second)
+
+	renewed, err := provider.CreateKey(context.Background(), original)
+	require.NoError(t, err)
+
+	renewedExpiresAt, err := secret.CertificateExpiry(secrettype.TLSSecretType, renewed)
+	require.NoError(t, err)
+
+	assert.True(t, renewedExpiresAt.After(*originalExpiresAt), "renewal should push the expiry forward")
+
+	err = provider.VerifyKey(context.Background(), renewed)
+	require.NoError(t, err)
+
+	// A CA that outlives the renewed certificates is kept as is
+	again, err := provider.CreateKey(context.Background(), renewed)
+	require.NoError(t, err)
+
+	assert.Equal(t, renewed[secrettype.CACert], again[secrettype.CACert])
+}
@@ -0,0 +1,73 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationdriver
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+)
+
+type getPolicyRequest struct {
+	SecretID string
+}
+
+func MakeGetPolicyEndpoint(service secretrotation.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(getPolicyRequest)
+
+		return service.GetPolicy(ctx, r.SecretID)
+	})
+}
+
+type setPolicyRequest struct {
+	SecretID      string
+	PolicyRequest secretrotation.SetPolicyRequest
+}
+
+func MakeSetPolicyEndpoint(service secretrotation.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(setPolicyRequest)
+
+		return service.SetPolicy(ctx, r.SecretID, r.PolicyRequest)
+	})
+}
+
+type deletePolicyRequest struct {
+	SecretID string
+}
+
+func MakeDeletePolicyEndpoint(service secretrotation.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(deletePolicyRequest)
+
+		return nil, service.DeletePolicy(ctx, r.SecretID)
+	})
+}
+
+type rotateSecretRequest struct {
+	SecretID string
+}
+
+func MakeRotateSecretEndpoint(service secretrotation.Service) endpoint.Endpoint {
+	return kitxendpoint.BusinessErrorMiddleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+		r := req.(rotateSecretRequest)
+
+		return nil, service.RotateSecret(ctx, r.SecretID)
+	})
+}
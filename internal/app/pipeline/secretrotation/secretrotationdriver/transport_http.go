@@ -0,0 +1,188 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationdriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"emperror.dev/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+	"github.com/banzaicloud/pipeline/pkg/problems"
+)
+
+// RegisterHTTPHandlers mounts all of the service endpoints into a router.
+func RegisterHTTPHandlers(endpoints Endpoints, router *mux.Router, options ...kithttp.ServerOption) {
+	// Request bodies are validated while decoding requests
+	options = append(options, kithttp.ServerErrorEncoder(encodeHTTPError))
+
+	router.Methods(http.MethodGet).Path("").Handler(kithttp.NewServer(
+		endpoints.GetPolicy,
+		decodeGetPolicyHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodePolicyHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPut).Path("").Handler(kithttp.NewServer(
+		endpoints.SetPolicy,
+		decodeSetPolicyHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodePolicyHTTPResponse, errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodDelete).Path("").Handler(kithttp.NewServer(
+		endpoints.DeletePolicy,
+		decodeDeletePolicyHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusNoContent), errorEncoder),
+		options...,
+	))
+
+	router.Methods(http.MethodPost).Path("/rotate").Handler(kithttp.NewServer(
+		endpoints.RotateSecret,
+		decodeRotateSecretHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(kitxhttp.StatusCodeResponseEncoder(http.StatusAccepted), errorEncoder),
+		options...,
+	))
+}
+
+// policyRequest is the JSON representation of a rotation policy request.
+type policyRequest struct {
+	Provider string `json:"provider,omitempty"`
+	Interval string `json:"interval"`
+}
+
+// policyResponse is the JSON representation of a rotation policy.
+type policyResponse struct {
+	SecretID       string     `json:"secretId"`
+	Provider       string     `json:"provider"`
+	Interval       string     `json:"interval"`
+	LastRotatedAt  *time.Time `json:"lastRotatedAt,omitempty"`
+	NextRotationAt time.Time  `json:"nextRotationAt"`
+	LastError      string     `json:"lastError,omitempty"`
+}
+
+func decodeGetPolicyHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	secretID, err := getSecretID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return getPolicyRequest{SecretID: secretID}, nil
+}
+
+func decodeSetPolicyHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	secretID, err := getSecretID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var req policyRequest
+
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode request")
+	}
+
+	interval, err := time.ParseDuration(req.Interval)
+	if err != nil {
+		return nil, secretrotation.ValidationError{Message: "invalid interval (expected a duration, eg. 720h): " + req.Interval}
+	}
+
+	return setPolicyRequest{
+		SecretID: secretID,
+		PolicyRequest: secretrotation.SetPolicyRequest{
+			Provider: req.Provider,
+			Interval: interval,
+		},
+	}, nil
+}
+
+func decodeDeletePolicyHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	secretID, err := getSecretID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return deletePolicyRequest{SecretID: secretID}, nil
+}
+
+func decodeRotateSecretHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	secretID, err := getSecretID(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return rotateSecretRequest{SecretID: secretID}, nil
+}
+
+func encodePolicyHTTPResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	policy := resp.(secretrotation.Policy)
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, policyResponse{
+		SecretID:       policy.SecretID,
+		Provider:       policy.Provider,
+		Interval:       policy.Interval.String(),
+		LastRotatedAt:  policy.LastRotatedAt,
+		NextRotationAt: policy.NextRotationAt,
+		LastError:      policy.LastError,
+	})
+}
+
+func getSecretID(r *http.Request) (string, error) {
+	secretID, ok := mux.Vars(r)["secretId"]
+	if !ok || secretID == "" {
+		return "", errors.NewWithDetails("missing parameter from the URL", "param", "secretId")
+	}
+
+	return secretID, nil
+}
+
+func encodeHTTPError(ctx context.Context, err error, w http.ResponseWriter) {
+	_ = errorEncoder(ctx, w, err)
+}
+
+func errorEncoder(_ context.Context, w http.ResponseWriter, e error) error {
+	var problem problems.StatusProblem
+
+	switch {
+	case errors.As(e, &secretrotation.NotFoundError{}), errors.As(e, &secretrotation.SecretNotFoundError{}):
+		problem = problems.NewDetailedProblem(http.StatusNotFound, e.Error())
+
+	case errors.As(e, &secretrotation.AlreadyRotatingError{}):
+		problem = problems.NewDetailedProblem(http.StatusConflict, e.Error())
+
+	case errors.As(e, &secretrotation.ValidationError{}):
+		problem = problems.NewDetailedProblem(http.StatusUnprocessableEntity, e.Error())
+
+	default:
+		problem = problems.NewDetailedProblem(http.StatusInternalServerError, "something went wrong")
+	}
+
+	w.Header().Set("Content-Type", problems.ProblemMediaType)
+	w.WriteHeader(problem.ProblemStatus())
+
+	err := json.NewEncoder(w).Encode(problem)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode error response")
+	}
+
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotationdriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sagikazarmark/kitx/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretrotation"
+)
+
+func TestRegisterHTTPHandlers_SetPolicy(t *testing.T) {
+	nextRotationAt := time.Date(2019, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			SetPolicy: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				assert.Equal(
+					t,
+					setPolicyRequest{
+						SecretID: "secret",
+						PolicyRequest: secretrotation.SetPolicyRequest{
+							Provider: "amazon",
+							Interval: 720 * time.Hour,
+						},
+					},
+					request,
+				)
+
+				return secretrotation.Policy{
+					OrganizationID: 1,
+					SecretID:       "secret",
+					Provider:       "amazon",
+					Interval:       720 * time.Hour,
+					NextRotationAt: nextRotationAt,
+				}, nil
+			},
+		},
+		handler.PathPrefix("/secrets/{secretId}/rotation").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, err := http.NewRequest(
+		http.MethodPut,
+		ts.URL+"/secrets/secret/rotation",
+		strings.NewReader(`{"provider": "amazon", "interval": "720h"}`),
+	)
+	require.NoError(t, err)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var policy policyResponse
+
+	err = json.NewDecoder(resp.Body).Decode(&policy)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		policyResponse{
+			SecretID:       "secret",
+			Provider:       "amazon",
+			Interval:       "720h0m0s",
+			NextRotationAt: nextRotationAt,
+		},
+		policy,
+	)
+}
+
+func TestRegisterHTTPHandlers_SetPolicy_InvalidInterval(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{},
+		handler.PathPrefix("/secrets/{secretId}/rotation").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	req, err := http.NewRequest(
+		http.MethodPut,
+		ts.URL+"/secrets/secret/rotation",
+		strings.NewReader(`{"interval": "monthly"}`),
+	)
+	require.NoError(t, err)
+
+	resp, err := ts.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+}
+
+func TestRegisterHTTPHandlers_GetPolicy_NotFound(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			GetPolicy: endpoint.BusinessErrorMiddleware(func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return nil, secretrotation.NotFoundError{SecretID: request.(getPolicyRequest).SecretID}
+			}),
+		},
+		handler.PathPrefix("/secrets/{secretId}/rotation").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/secrets/secret/rotation")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRegisterHTTPHandlers_RotateSecret(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			RotateSecret: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				assert.Equal(t, rotateSecretRequest{SecretID: "secret"}, request)
+
+				return nil, nil
+			},
+		},
+		handler.PathPrefix("/secrets/{secretId}/rotation").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := ts.Client().Post(ts.URL+"/secrets/secret/rotation/rotate", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+}
+
+func TestRegisterHTTPHandlers_RotateSecret_AlreadyRotating(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			RotateSecret: endpoint.BusinessErrorMiddleware(func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				return nil, secretrotation.AlreadyRotatingError{SecretID: "secret"}
+			}),
+		},
+		handler.PathPrefix("/secrets/{secretId}/rotation").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := ts.Client().Post(ts.URL+"/secrets/secret/rotation/rotate", "application/json", nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+}
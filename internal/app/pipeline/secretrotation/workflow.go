@@ -0,0 +1,164 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+	"go.uber.org/zap"
+)
+
+// WorkflowName can be used to reference the secret rotation workflow.
+const WorkflowName = "secret-rotation"
+
+// WorkflowInput is the input for a secret rotation workflow.
+type WorkflowInput struct {
+	OrganizationID uint
+	SecretID       string
+	Provider       string
+}
+
+// Workflow rotates the key stored in a secret.
+//
+// The new key is stored as a new version of the secret right away,
+// so that key material never becomes part of the workflow history.
+// If the new key cannot be verified, the previous version is restored.
+func Workflow(ctx workflow.Context, input WorkflowInput) error {
+	activityOptions := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+		WaitForCancellation:    true,
+	}
+
+	// Creating a key is not idempotent, so it is never retried
+	createCtx := workflow.WithActivityOptions(ctx, activityOptions)
+
+	// Default timeouts and retries
+	activityOptions.RetryPolicy = &cadence.RetryPolicy{
+		InitialInterval:    5 * time.Second,
+		BackoffCoefficient: 1.5,
+		MaximumInterval:    time.Minute,
+		MaximumAttempts:    10,
+	}
+	ctx = workflow.WithActivityOptions(ctx, activityOptions)
+
+	var createOutput CreateKeyActivityOutput
+	{
+		activityInput := CreateKeyActivityInput{
+			OrganizationID: input.OrganizationID,
+			SecretID:       input.SecretID,
+			Provider:       input.Provider,
+		}
+
+		err := workflow.ExecuteActivity(createCtx, CreateKeyActivityName, activityInput).Get(ctx, &createOutput)
+		if err != nil {
+			return recordRotation(ctx, input, err)
+		}
+	}
+
+	{
+		activityInput := VerifyKeyActivityInput{
+			OrganizationID: input.OrganizationID,
+			SecretID:       input.SecretID,
+			Provider:       input.Provider,
+			Version:        createOutput.Version,
+		}
+
+		err := workflow.ExecuteActivity(ctx, VerifyKeyActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			if err := rollbackKey(ctx, input, createOutput); err != nil {
+				workflow.GetLogger(ctx).Error("failed to roll back secret", zap.Error(err))
+			}
+
+			return recordRotation(ctx, input, err)
+		}
+	}
+
+	{
+		activityInput := SyncClustersActivityInput{
+			OrganizationID: input.OrganizationID,
+			SecretID:       input.SecretID,
+		}
+
+		err := workflow.ExecuteActivity(ctx, SyncClustersActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			// The previous key is kept, so that clusters which could not be updated keep working
+			return recordRotation(ctx, input, err)
+		}
+	}
+
+	{
+		activityInput := RevokeKeyActivityInput{
+			OrganizationID: input.OrganizationID,
+			SecretID:       input.SecretID,
+			Provider:       input.Provider,
+			ActiveVersion:  createOutput.Version,
+			RevokedVersion: createOutput.PreviousVersion,
+		}
+
+		err := workflow.ExecuteActivity(ctx, RevokeKeyActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			return recordRotation(ctx, input, err)
+		}
+	}
+
+	return recordRotation(ctx, input, nil)
+}
+
+// rollbackKey restores the previous version of a secret and revokes the new key.
+func rollbackKey(ctx workflow.Context, input WorkflowInput, createOutput CreateKeyActivityOutput) error {
+	rollbackInput := RollbackSecretActivityInput{
+		OrganizationID: input.OrganizationID,
+		SecretID:       input.SecretID,
+		Version:        createOutput.PreviousVersion,
+	}
+
+	err := workflow.ExecuteActivity(ctx, RollbackSecretActivityName, rollbackInput).Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	revokeInput := RevokeKeyActivityInput{
+		OrganizationID: input.OrganizationID,
+		SecretID:       input.SecretID,
+		Provider:       input.Provider,
+		ActiveVersion:  createOutput.PreviousVersion,
+		RevokedVersion: createOutput.Version,
+	}
+
+	return workflow.ExecuteActivity(ctx, RevokeKeyActivityName, revokeInput).Get(ctx, nil)
+}
+
+// recordRotation records the outcome of a rotation in the rotation policy and returns the rotation error.
+func recordRotation(ctx workflow.Context, input WorkflowInput, rotationErr error) error {
+	activityInput := RecordRotationActivityInput{
+		OrganizationID: input.OrganizationID,
+		SecretID:       input.SecretID,
+		RotatedAt:      workflow.Now(ctx),
+	}
+
+	if rotationErr != nil {
+		activityInput.Error = rotationErr.Error()
+	}
+
+	err := workflow.ExecuteActivity(ctx, RecordRotationActivityName, activityInput).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("failed to record secret rotation", zap.Error(err))
+	}
+
+	return rotationErr
+}
@@ -0,0 +1,154 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretrotation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/testsuite"
+	"go.uber.org/cadence/workflow"
+)
+
+// nolint: gochecknoglobals
+var testWorkflowInput = WorkflowInput{
+	OrganizationID: 1,
+	SecretID:       "secret",
+	Provider:       "amazon",
+}
+
+type WorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env *testsuite.TestWorkflowEnvironment
+}
+
+func TestWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowTestSuite))
+}
+
+func (s *WorkflowTestSuite) SetupSuite() {
+	workflow.RegisterWithOptions(Workflow, workflow.RegisterOptions{Name: WorkflowName})
+
+	activity.RegisterWithOptions(CreateKeyActivity{}.Execute, activity.RegisterOptions{Name: CreateKeyActivityName})
+	activity.RegisterWithOptions(VerifyKeyActivity{}.Execute, activity.RegisterOptions{Name: VerifyKeyActivityName})
+	activity.RegisterWithOptions(RollbackSecretActivity{}.Execute, activity.RegisterOptions{Name: RollbackSecretActivityName})
+	activity.RegisterWithOptions(SyncClustersActivity{}.Execute, activity.RegisterOptions{Name: SyncClustersActivityName})
+	activity.RegisterWithOptions(RevokeKeyActivity{}.Execute, activity.RegisterOptions{Name: RevokeKeyActivityName})
+	activity.RegisterWithOptions(RecordRotationActivity{}.Execute, activity.RegisterOptions{Name: RecordRotationActivityName})
+}
+
+func (s *WorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+
+	s.env.OnActivity(
+		CreateKeyActivityName,
+		mock.Anything,
+		CreateKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "amazon"},
+	).Return(CreateKeyActivityOutput{PreviousVersion: 2, Version: 3}, nil)
+}
+
+func (s *WorkflowTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *WorkflowTestSuite) onRecordRotation(rotationErr string) {
+	s.env.OnActivity(
+		RecordRotationActivityName,
+		mock.Anything,
+		mock.MatchedBy(func(input RecordRotationActivityInput) bool {
+			return input.OrganizationID == 1 && input.SecretID == "secret" && input.Error == rotationErr
+		}),
+	).Return(nil)
+}
+
+func (s *WorkflowTestSuite) Test_Success() {
+	s.env.OnActivity(
+		VerifyKeyActivityName,
+		mock.Anything,
+		VerifyKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "amazon", Version: 3},
+	).Return(nil)
+
+	s.env.OnActivity(
+		SyncClustersActivityName,
+		mock.Anything,
+		SyncClustersActivityInput{OrganizationID: 1, SecretID: "secret"},
+	).Return(nil)
+
+	s.env.OnActivity(
+		RevokeKeyActivityName,
+		mock.Anything,
+		RevokeKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "amazon", ActiveVersion: 3, RevokedVersion: 2},
+	).Return(nil)
+
+	s.onRecordRotation("")
+
+	s.env.ExecuteWorkflow(WorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *WorkflowTestSuite) Test_VerificationFailed() {
+	s.env.OnActivity(
+		VerifyKeyActivityName,
+		mock.Anything,
+		VerifyKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "amazon", Version: 3},
+	).Return(errors.New("invalid key"))
+
+	s.env.OnActivity(
+		RollbackSecretActivityName,
+		mock.Anything,
+		RollbackSecretActivityInput{OrganizationID: 1, SecretID: "secret", Version: 2},
+	).Return(nil)
+
+	s.env.OnActivity(
+		RevokeKeyActivityName,
+		mock.Anything,
+		RevokeKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "amazon", ActiveVersion: 2, RevokedVersion: 3},
+	).Return(nil)
+
+	s.onRecordRotation("invalid key")
+
+	s.env.ExecuteWorkflow(WorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.EqualError(s.env.GetWorkflowError(), "invalid key")
+}
+
+func (s *WorkflowTestSuite) Test_SyncFailed() {
+	s.env.OnActivity(
+		VerifyKeyActivityName,
+		mock.Anything,
+		VerifyKeyActivityInput{OrganizationID: 1, SecretID: "secret", Provider: "amazon", Version: 3},
+	).Return(nil)
+
+	s.env.OnActivity(
+		SyncClustersActivityName,
+		mock.Anything,
+		SyncClustersActivityInput{OrganizationID: 1, SecretID: "secret"},
+	).Return(errors.New("cluster unreachable"))
+
+	s.onRecordRotation("cluster unreachable")
+
+	s.env.ExecuteWorkflow(WorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.EqualError(s.env.GetWorkflowError(), "cluster unreachable")
+}
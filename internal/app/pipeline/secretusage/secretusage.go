@@ -0,0 +1,108 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusage
+
+import (
+	"context"
+	"sort"
+
+	"emperror.dev/errors"
+)
+
+// Usage kinds
+const (
+	ClusterKind          = "cluster"
+	BucketKind           = "bucket"
+	BackupBucketKind     = "backupBucket"
+	BackupDeploymentKind = "backupDeployment"
+	ClusterFeatureKind   = "clusterFeature"
+	SpotguideKind        = "spotguide"
+	HelmRepositoryKind   = "helmRepository"
+)
+
+// Usage is an object referencing a secret.
+type Usage struct {
+	// Kind is the type of the referencing object.
+	Kind string
+
+	// ID identifies the referencing object among the objects of the same kind.
+	ID string
+
+	// Name is the human readable name of the referencing object.
+	Name string
+
+	// ClusterID is set when the referencing object belongs to a cluster.
+	ClusterID uint
+}
+
+//go:generate mga gen kit endpoint --outdir secretusagedriver --with-oc Service
+//go:generate mockery -name Service -inpkg
+type Service interface {
+	// ListUsages returns every object referencing a secret.
+	ListUsages(ctx context.Context, secretID string) ([]Usage, error)
+}
+
+// NewService returns a new Service.
+func NewService(orgIDExtractor OrgIDContextExtractor, finders ...UsageFinder) Service {
+	return service{
+		orgIDExtractor: orgIDExtractor,
+		finders:        finders,
+	}
+}
+
+type service struct {
+	orgIDExtractor OrgIDContextExtractor
+	finders        []UsageFinder
+}
+
+// OrgIDContextExtractor extracts an organization ID from a context (if there is any).
+type OrgIDContextExtractor interface {
+	// GetOrganizationID extracts an organization ID from a context (if there is any).
+	GetOrganizationID(ctx context.Context) (uint, bool)
+}
+
+// UsageFinder finds the objects of a kind referencing a secret.
+type UsageFinder interface {
+	// FindUsages returns the objects referencing a secret in an organization.
+	FindUsages(ctx context.Context, organizationID uint, secretID string) ([]Usage, error)
+}
+
+func (s service) ListUsages(ctx context.Context, secretID string) ([]Usage, error) {
+	orgID, ok := s.orgIDExtractor.GetOrganizationID(ctx)
+	if !ok {
+		return nil, errors.New("organization ID not found in the context")
+	}
+
+	usages := make([]Usage, 0)
+
+	for _, finder := range s.finders {
+		found, err := finder.FindUsages(ctx, orgID, secretID)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to find secret usages", "secretId", secretID)
+		}
+
+		usages = append(usages, found...)
+	}
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		if usages[i].Kind != usages[j].Kind {
+			return usages[i].Kind < usages[j].Kind
+		}
+
+		return usages[i].Name < usages[j].Name
+	})
+
+	return usages, nil
+}
@@ -0,0 +1,102 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusage
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate mockery -name UsageFinder -inpkg -testonly
+
+type orgIDExtractorStub struct {
+	orgID uint
+}
+
+func (e orgIDExtractorStub) GetOrganizationID(ctx context.Context) (uint, bool) {
+	return e.orgID, e.orgID != 0
+}
+
+func TestService_ListUsages(t *testing.T) {
+	ctx := context.Background()
+	orgID := uint(1)
+
+	clusters := new(MockUsageFinder)
+	clusters.On("FindUsages", ctx, orgID, "secret").Return([]Usage{
+		{Kind: ClusterKind, ID: "2", Name: "my-second-cluster", ClusterID: 2},
+		{Kind: ClusterKind, ID: "1", Name: "my-cluster", ClusterID: 1},
+	}, nil)
+
+	buckets := new(MockUsageFinder)
+	buckets.On("FindUsages", ctx, orgID, "secret").Return([]Usage{
+		{Kind: BucketKind, ID: "amazon/my-bucket", Name: "my-bucket"},
+	}, nil)
+
+	features := new(MockUsageFinder)
+	features.On("FindUsages", ctx, orgID, "secret").Return(nil, nil)
+
+	service := NewService(orgIDExtractorStub{orgID}, clusters, buckets, features)
+
+	usages, err := service.ListUsages(ctx, "secret")
+	require.NoError(t, err)
+
+	assert.Equal(t, []Usage{
+		{Kind: BucketKind, ID: "amazon/my-bucket", Name: "my-bucket"},
+		{Kind: ClusterKind, ID: "1", Name: "my-cluster", ClusterID: 1},
+		{Kind: ClusterKind, ID: "2", Name: "my-second-cluster", ClusterID: 2},
+	}, usages)
+
+	clusters.AssertExpectations(t)
+	buckets.AssertExpectations(t)
+	features.AssertExpectations(t)
+}
+
+func TestService_ListUsages_NotUsed(t *testing.T) {
+	ctx := context.Background()
+
+	clusters := new(MockUsageFinder)
+	clusters.On("FindUsages", ctx, uint(1), "secret").Return(nil, nil)
+
+	service := NewService(orgIDExtractorStub{1}, clusters)
+
+	usages, err := service.ListUsages(ctx, "secret")
+	require.NoError(t, err)
+
+	assert.NotNil(t, usages)
+	assert.Empty(t, usages)
+}
+
+func TestService_ListUsages_FinderError(t *testing.T) {
+	ctx := context.Background()
+
+	clusters := new(MockUsageFinder)
+	clusters.On("FindUsages", ctx, uint(1), "secret").Return(nil, errors.New("database is down"))
+
+	service := NewService(orgIDExtractorStub{1}, clusters)
+
+	_, err := service.ListUsages(ctx, "secret")
+	require.Error(t, err)
+}
+
+func TestService_ListUsages_NoOrganization(t *testing.T) {
+	service := NewService(orgIDExtractorStub{})
+
+	_, err := service.ListUsages(context.Background(), "secret")
+	require.Error(t, err)
+}
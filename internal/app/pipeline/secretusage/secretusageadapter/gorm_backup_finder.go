@@ -0,0 +1,97 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusageadapter
+
+import (
+	"context"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+)
+
+// GormBackupFinder finds the Ark backup buckets referencing a secret
+// and the Ark deployments using those buckets.
+type GormBackupFinder struct {
+	db *gorm.DB
+}
+
+// NewGormBackupFinder returns a new GormBackupFinder.
+func NewGormBackupFinder(db *gorm.DB) GormBackupFinder {
+	return GormBackupFinder{
+		db: db,
+	}
+}
+
+// FindUsages implements the secretusage.UsageFinder interface.
+func (f GormBackupFinder) FindUsages(ctx context.Context, organizationID uint, secretID string) ([]secretusage.Usage, error) {
+	var buckets []struct {
+		ID         uint
+		Cloud      string
+		BucketName string
+	}
+
+	err := f.db.Table("ark_backup_buckets").
+		Select("id, cloud, bucket_name").
+		Where("organization_id = ? AND secret_id = ? AND deleted_at IS NULL", organizationID, secretID).
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to find backup buckets")
+	}
+
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+
+	usages := make([]secretusage.Usage, 0, len(buckets))
+	bucketIDs := make([]uint, 0, len(buckets))
+
+	for _, bucket := range buckets {
+		usages = append(usages, secretusage.Usage{
+			Kind: secretusage.BackupBucketKind,
+			ID:   strconv.FormatUint(uint64(bucket.ID), 10),
+			Name: bucket.BucketName,
+		})
+
+		bucketIDs = append(bucketIDs, bucket.ID)
+	}
+
+	var deployments []struct {
+		ID        uint
+		Name      string
+		ClusterID uint
+	}
+
+	err = f.db.Table("ark_deployments").
+		Select("id, name, cluster_id").
+		Where("organization_id = ? AND bucket_id IN (?) AND deleted_at IS NULL", organizationID, bucketIDs).
+		Scan(&deployments).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to find backup deployments")
+	}
+
+	for _, deployment := range deployments {
+		usages = append(usages, secretusage.Usage{
+			Kind:      secretusage.BackupDeploymentKind,
+			ID:        strconv.FormatUint(uint64(deployment.ID), 10),
+			Name:      deployment.Name,
+			ClusterID: deployment.ClusterID,
+		})
+	}
+
+	return usages, nil
+}
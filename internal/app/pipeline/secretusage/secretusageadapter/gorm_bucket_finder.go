@@ -0,0 +1,88 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusageadapter
+
+import (
+	"context"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+	"github.com/banzaicloud/pipeline/pkg/providers"
+)
+
+type bucketTable struct {
+	cloud             string
+	table             string
+	organizationIDCol string
+	secretCols        []string
+}
+
+// nolint: gochecknoglobals
+var bucketTables = []bucketTable{
+	{cloud: providers.Alibaba, table: "alibaba_buckets", organizationIDCol: "org_id", secretCols: []string{"secret_ref"}},
+	{cloud: providers.Amazon, table: "amazon_buckets", organizationIDCol: "organization_id", secretCols: []string{"secret_ref"}},
+	{cloud: providers.Azure, table: "azure_buckets", organizationIDCol: "organization_id", secretCols: []string{"secret_ref", "access_secret_ref"}},
+	{cloud: providers.Google, table: "google_buckets", organizationIDCol: "organization_id", secretCols: []string{"secret_ref"}},
+	{cloud: providers.Oracle, table: "oracle_buckets", organizationIDCol: "org_id", secretCols: []string{"secret_ref"}},
+}
+
+// GormBucketFinder finds the object store buckets referencing a secret.
+type GormBucketFinder struct {
+	db *gorm.DB
+}
+
+// NewGormBucketFinder returns a new GormBucketFinder.
+func NewGormBucketFinder(db *gorm.DB) GormBucketFinder {
+	return GormBucketFinder{
+		db: db,
+	}
+}
+
+// FindUsages implements the secretusage.UsageFinder interface.
+func (f GormBucketFinder) FindUsages(ctx context.Context, organizationID uint, secretID string) ([]secretusage.Usage, error) {
+	usages := make([]secretusage.Usage, 0)
+
+	for _, bt := range bucketTables {
+		conditions := make([]string, 0, len(bt.secretCols))
+		values := make([]interface{}, 0, len(bt.secretCols))
+		for _, col := range bt.secretCols {
+			conditions = append(conditions, col+" = ?")
+			values = append(values, secretID)
+		}
+
+		var names []string
+
+		err := f.db.Table(bt.table).
+			Where(bt.organizationIDCol+" = ?", organizationID).
+			Where(strings.Join(conditions, " OR "), values...).
+			Pluck("name", &names).Error
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to find buckets", "cloud", bt.cloud)
+		}
+
+		for _, name := range names {
+			usages = append(usages, secretusage.Usage{
+				Kind: secretusage.BucketKind,
+				ID:   bt.cloud + "/" + name,
+				Name: name,
+			})
+		}
+	}
+
+	return usages, nil
+}
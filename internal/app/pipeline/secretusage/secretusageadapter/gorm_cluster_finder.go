@@ -0,0 +1,67 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusageadapter
+
+import (
+	"context"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+)
+
+// GormClusterFinder finds the clusters referencing a secret.
+// A cluster references its cloud, config (kubeconfig) and SSH secrets.
+type GormClusterFinder struct {
+	db *gorm.DB
+}
+
+// NewGormClusterFinder returns a new GormClusterFinder.
+func NewGormClusterFinder(db *gorm.DB) GormClusterFinder {
+	return GormClusterFinder{
+		db: db,
+	}
+}
+
+// FindUsages implements the secretusage.UsageFinder interface.
+func (f GormClusterFinder) FindUsages(ctx context.Context, organizationID uint, secretID string) ([]secretusage.Usage, error) {
+	var clusters []struct {
+		ID   uint
+		Name string
+	}
+
+	err := f.db.Table("clusters").
+		Select("id, name").
+		Where("organization_id = ? AND deleted_at IS NULL", organizationID).
+		Where("secret_id = ? OR config_secret_id = ? OR ssh_secret_id = ?", secretID, secretID, secretID).
+		Scan(&clusters).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to find clusters")
+	}
+
+	usages := make([]secretusage.Usage, 0, len(clusters))
+	for _, cluster := range clusters {
+		usages = append(usages, secretusage.Usage{
+			Kind:      secretusage.ClusterKind,
+			ID:        strconv.FormatUint(uint64(cluster.ID), 10),
+			Name:      cluster.Name,
+			ClusterID: cluster.ID,
+		})
+	}
+
+	return usages, nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusageadapter
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+)
+
+// GormFeatureFinder finds the cluster features referencing a secret in their specs.
+type GormFeatureFinder struct {
+	db *gorm.DB
+}
+
+// NewGormFeatureFinder returns a new GormFeatureFinder.
+func NewGormFeatureFinder(db *gorm.DB) GormFeatureFinder {
+	return GormFeatureFinder{
+		db: db,
+	}
+}
+
+// FindUsages implements the secretusage.UsageFinder interface.
+func (f GormFeatureFinder) FindUsages(ctx context.Context, organizationID uint, secretID string) ([]secretusage.Usage, error) {
+	var features []struct {
+		ID        uint
+		Name      string
+		ClusterID uint
+		Spec      string
+	}
+
+	err := f.db.Table("cluster_features").
+		Select("cluster_features.id, cluster_features.name, cluster_features.cluster_id, cluster_features.spec").
+		Joins("JOIN clusters ON clusters.id = cluster_features.cluster_id").
+		Where("clusters.organization_id = ? AND clusters.deleted_at IS NULL", organizationID).
+		Scan(&features).Error
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list cluster features")
+	}
+
+	usages := make([]secretusage.Usage, 0)
+
+	for _, feature := range features {
+		var spec interface{}
+
+		err := json.Unmarshal([]byte(feature.Spec), &spec)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(
+				err, "failed to decode cluster feature spec",
+				"clusterId", feature.ClusterID,
+				"feature", feature.Name,
+			)
+		}
+
+		if !referencesSecret(spec, organizationID, secretID) {
+			continue
+		}
+
+		usages = append(usages, secretusage.Usage{
+			Kind:      secretusage.ClusterFeatureKind,
+			ID:        strconv.FormatUint(uint64(feature.ID), 10),
+			Name:      feature.Name,
+			ClusterID: feature.ClusterID,
+		})
+	}
+
+	return usages, nil
+}
+
+// referencesSecret walks a decoded feature spec looking for a secret ID.
+// Some features (eg. DNS) replace secret IDs with secret BRNs when preparing their specs,
+// so these are accepted as well.
+func referencesSecret(spec interface{}, organizationID uint, secretID string) bool {
+	switch v := spec.(type) {
+	case string:
+		if v == secretID {
+			return true
+		}
+
+		if !brn.IsBRN(v) {
+			return false
+		}
+
+		rn, err := brn.ParseAs(v, brn.SecretResourceType)
+		if err != nil {
+			return false
+		}
+
+		return rn.ResourceID == secretID && (rn.OrganizationID == 0 || rn.OrganizationID == organizationID)
+
+	case map[string]interface{}:
+		for _, value := range v {
+			if referencesSecret(value, organizationID, secretID) {
+				return true
+			}
+		}
+
+	case []interface{}:
+		for _, value := range v {
+			if referencesSecret(value, organizationID, secretID) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
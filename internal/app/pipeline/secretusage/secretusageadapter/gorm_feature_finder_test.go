@@ -0,0 +1,65 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusageadapter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferencesSecret(t *testing.T) {
+	tests := map[string]struct {
+		spec     interface{}
+		expected bool
+	}{
+		"secret ID": {
+			spec:     map[string]interface{}{"grafana": map[string]interface{}{"secretId": "secret"}},
+			expected: true,
+		},
+		"secret BRN": {
+			spec:     map[string]interface{}{"provider": map[string]interface{}{"secretId": "brn:1:secret:secret"}},
+			expected: true,
+		},
+		"secret BRN without organization": {
+			spec:     []interface{}{"brn::secret:secret"},
+			expected: true,
+		},
+		"secret BRN of another organization": {
+			spec:     map[string]interface{}{"secretId": "brn:2:secret:secret"},
+			expected: false,
+		},
+		"BRN of another resource type": {
+			spec:     map[string]interface{}{"clusterId": "brn:1:cluster:secret"},
+			expected: false,
+		},
+		"other secret": {
+			spec:     map[string]interface{}{"secretId": "other", "enabled": true, "replicas": float64(1)},
+			expected: false,
+		},
+		"empty spec": {
+			spec:     nil,
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, referencesSecret(test.spec, 1, "secret"))
+		})
+	}
+}
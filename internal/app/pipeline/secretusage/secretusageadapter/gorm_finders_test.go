@@ -0,0 +1,188 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusageadapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+)
+
+// The following models mirror the columns of the tables owned by other modules.
+
+type testClusterModel struct {
+	ID             uint `gorm:"primary_key"`
+	Name           string
+	OrganizationID uint
+	SecretID       string
+	ConfigSecretID string
+	SSHSecretID    string `gorm:"column:ssh_secret_id"`
+	DeletedAt      *time.Time
+}
+
+func (testClusterModel) TableName() string { return "clusters" }
+
+// testBucketModel has the columns of every cloud specific bucket table.
+type testBucketModel struct {
+	ID              uint `gorm:"primary_key"`
+	OrganizationID  uint
+	OrgID           uint
+	Name            string
+	SecretRef       string
+	AccessSecretRef string
+}
+
+type testBackupBucketModel struct {
+	ID             uint `gorm:"primary_key"`
+	Cloud          string
+	SecretID       string
+	BucketName     string
+	OrganizationID uint
+	DeletedAt      *time.Time
+}
+
+func (testBackupBucketModel) TableName() string { return "ark_backup_buckets" }
+
+type testBackupDeploymentModel struct {
+	ID             uint `gorm:"primary_key"`
+	Name           string
+	BucketID       uint
+	OrganizationID uint
+	ClusterID      uint
+	DeletedAt      *time.Time
+}
+
+func (testBackupDeploymentModel) TableName() string { return "ark_deployments" }
+
+type testFeatureModel struct {
+	ID        uint `gorm:"primary_key"`
+	Name      string
+	ClusterID uint
+	Spec      string `gorm:"type:text"`
+}
+
+func (testFeatureModel) TableName() string { return "cluster_features" }
+
+func testGormFinders(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(
+		&testClusterModel{},
+		&testBackupBucketModel{},
+		&testBackupDeploymentModel{},
+		&testFeatureModel{},
+	).Error
+	require.NoError(t, err)
+
+	for _, bt := range bucketTables {
+		err := db.Table(bt.table).CreateTable(&testBucketModel{}).Error
+		require.NoError(t, err)
+	}
+
+	buckets := map[string][]testBucketModel{
+		"amazon_buckets": {
+			{OrganizationID: 1, Name: "my-bucket", SecretRef: "secret"},
+			{OrganizationID: 1, Name: "other-bucket", SecretRef: "other"},
+		},
+		"azure_buckets": {
+			{OrganizationID: 1, Name: "my-blob", SecretRef: "other", AccessSecretRef: "secret"},
+		},
+		"oracle_buckets": {
+			{OrgID: 1, Name: "my-oci-bucket", SecretRef: "secret"},
+		},
+		"alibaba_buckets": {
+			{OrgID: 2, Name: "other-org-bucket", SecretRef: "secret"},
+		},
+	}
+
+	for table, models := range buckets {
+		for _, m := range models {
+			m := m
+			require.NoError(t, db.Table(table).Create(&m).Error)
+		}
+	}
+
+	deletedAt := time.Now()
+
+	for _, m := range []interface{}{
+		&testClusterModel{ID: 1, Name: "my-cluster", OrganizationID: 1, SecretID: "secret"},
+		&testClusterModel{ID: 2, Name: "my-pke-cluster", OrganizationID: 1, SecretID: "other", SSHSecretID: "secret"},
+		&testClusterModel{ID: 3, Name: "deleted-cluster", OrganizationID: 1, SecretID: "secret", DeletedAt: &deletedAt},
+		&testClusterModel{ID: 4, Name: "other-org-cluster", OrganizationID: 2, SecretID: "secret"},
+		&testBackupBucketModel{ID: 1, Cloud: "amazon", SecretID: "secret", BucketName: "my-backups", OrganizationID: 1},
+		&testBackupDeploymentModel{ID: 1, Name: "ark", BucketID: 1, OrganizationID: 1, ClusterID: 1},
+		&testFeatureModel{ID: 1, Name: "monitoring", ClusterID: 1, Spec: `{"grafana": {"enabled": true, "secretId": "secret"}}`},
+		&testFeatureModel{ID: 2, Name: "dns", ClusterID: 2, Spec: `{"externalDns": {"provider": {"secretId": "brn:1:secret:secret"}}}`},
+		&testFeatureModel{ID: 3, Name: "vault", ClusterID: 2, Spec: `{"customVault": {"enabled": false}}`},
+		&testFeatureModel{ID: 4, Name: "monitoring", ClusterID: 4, Spec: `{"grafana": {"secretId": "secret"}}`},
+	} {
+		require.NoError(t, db.Create(m).Error)
+	}
+
+	ctx := context.Background()
+
+	t.Run("Clusters", func(t *testing.T) {
+		usages, err := NewGormClusterFinder(db).FindUsages(ctx, 1, "secret")
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []secretusage.Usage{
+			{Kind: secretusage.ClusterKind, ID: "1", Name: "my-cluster", ClusterID: 1},
+			{Kind: secretusage.ClusterKind, ID: "2", Name: "my-pke-cluster", ClusterID: 2},
+		}, usages)
+	})
+
+	t.Run("Buckets", func(t *testing.T) {
+		usages, err := NewGormBucketFinder(db).FindUsages(ctx, 1, "secret")
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []secretusage.Usage{
+			{Kind: secretusage.BucketKind, ID: "amazon/my-bucket", Name: "my-bucket"},
+			{Kind: secretusage.BucketKind, ID: "azure/my-blob", Name: "my-blob"},
+			{Kind: secretusage.BucketKind, ID: "oracle/my-oci-bucket", Name: "my-oci-bucket"},
+		}, usages)
+	})
+
+	t.Run("Backups", func(t *testing.T) {
+		usages, err := NewGormBackupFinder(db).FindUsages(ctx, 1, "secret")
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []secretusage.Usage{
+			{Kind: secretusage.BackupBucketKind, ID: "1", Name: "my-backups"},
+			{Kind: secretusage.BackupDeploymentKind, ID: "1", Name: "ark", ClusterID: 1},
+		}, usages)
+
+		usages, err = NewGormBackupFinder(db).FindUsages(ctx, 1, "other")
+		require.NoError(t, err)
+		assert.Empty(t, usages)
+	})
+
+	t.Run("Features", func(t *testing.T) {
+		usages, err := NewGormFeatureFinder(db).FindUsages(ctx, 1, "secret")
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []secretusage.Usage{
+			{Kind: secretusage.ClusterFeatureKind, ID: "1", Name: "monitoring", ClusterID: 1},
+			{Kind: secretusage.ClusterFeatureKind, ID: "2", Name: "dns", ClusterID: 2},
+		}, usages)
+	})
+}
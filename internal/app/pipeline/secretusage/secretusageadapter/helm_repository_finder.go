@@ -0,0 +1,79 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusageadapter
+
+import (
+	"context"
+	"sort"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+)
+
+// HelmRepositoryFinder finds the Helm chart repositories whose credentials are read from a secret.
+// The secret references of the repositories are stored in the Helm home of the organization.
+type HelmRepositoryFinder struct {
+	db *gorm.DB
+}
+
+// NewHelmRepositoryFinder returns a new HelmRepositoryFinder.
+func NewHelmRepositoryFinder(db *gorm.DB) HelmRepositoryFinder {
+	return HelmRepositoryFinder{
+		db: db,
+	}
+}
+
+// FindUsages implements the secretusage.UsageFinder interface.
+func (f HelmRepositoryFinder) FindUsages(ctx context.Context, organizationID uint, secretID string) ([]secretusage.Usage, error) {
+	var organization struct {
+		Name string
+	}
+
+	err := f.db.Table("organizations").
+		Select("name").
+		Where("id = ?", organizationID).
+		Scan(&organization).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to find organization")
+	}
+
+	secretIDs, err := helm.ListRepositorySecretIDs(organization.Name)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list helm repository secrets")
+	}
+
+	var usages []secretusage.Usage
+
+	for repoName, repoSecretID := range secretIDs {
+		if repoSecretID != secretID {
+			continue
+		}
+
+		usages = append(usages, secretusage.Usage{
+			Kind: secretusage.HelmRepositoryKind,
+			ID:   repoName,
+			Name: repoName,
+		})
+	}
+
+	sort.Slice(usages, func(i, j int) bool { return usages[i].Name < usages[j].Name })
+
+	return usages, nil
+}
@@ -0,0 +1,80 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusageadapter
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+	phelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+type testOrganizationModel struct {
+	ID   uint `gorm:"primary_key"`
+	Name string
+}
+
+func (testOrganizationModel) TableName() string { return "organizations" }
+
+func TestHelmRepositoryFinder(t *testing.T) {
+	helmPath, err := ioutil.TempDir("", "helm")
+	require.NoError(t, err)
+	defer os.RemoveAll(helmPath)
+
+	viper.Set("helm.path", helmPath)
+	defer viper.Set("helm.path", nil)
+
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(&testOrganizationModel{}).Error)
+	require.NoError(t, db.Create(&testOrganizationModel{ID: 1, Name: "my-org"}).Error)
+
+	env := helm.CreateEnvSettings(fmt.Sprintf("%s/%s", config.GetHelmPath("my-org"), phelm.HelmPostFix))
+	require.NoError(t, os.MkdirAll(env.Home.Path("repository"), 0700))
+
+	require.NoError(t, helm.SetRepositorySecret(env, "private", 1, "secret"))
+	require.NoError(t, helm.SetRepositorySecret(env, "registry", 1, "secret"))
+	require.NoError(t, helm.SetRepositorySecret(env, "other", 1, "other"))
+
+	finder := NewHelmRepositoryFinder(db)
+
+	usages, err := finder.FindUsages(context.Background(), 1, "secret")
+	require.NoError(t, err)
+
+	assert.Equal(t, []secretusage.Usage{
+		{Kind: secretusage.HelmRepositoryKind, ID: "private", Name: "private"},
+		{Kind: secretusage.HelmRepositoryKind, ID: "registry", Name: "registry"},
+	}, usages)
+
+	// organizations without a Helm home have no repositories
+	require.NoError(t, db.Create(&testOrganizationModel{ID: 2, Name: "other-org"}).Error)
+
+	usages, err = finder.FindUsages(context.Background(), 2, "secret")
+	require.NoError(t, err)
+	assert.Empty(t, usages)
+}
@@ -0,0 +1,76 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusageadapter
+
+import (
+	"context"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// spotguideRepoTagPrefix is the prefix of the tag added to the secrets created for spotguide CI/CD configs.
+const spotguideRepoTagPrefix = "repo:"
+
+// InternalSecretStore returns secrets.
+type InternalSecretStore interface {
+	// Get returns a secret of an organization.
+	Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error)
+}
+
+// SpotguideFinder finds the spotguide repositories whose CI/CD config references a secret.
+// Spotguide secrets are referenced by name in the CI/CD config of the repository
+// and are tagged with the repository name when the spotguide is launched.
+type SpotguideFinder struct {
+	secrets InternalSecretStore
+}
+
+// NewSpotguideFinder returns a new SpotguideFinder.
+func NewSpotguideFinder(secrets InternalSecretStore) SpotguideFinder {
+	return SpotguideFinder{
+		secrets: secrets,
+	}
+}
+
+// FindUsages implements the secretusage.UsageFinder interface.
+func (f SpotguideFinder) FindUsages(ctx context.Context, organizationID uint, secretID string) ([]secretusage.Usage, error) {
+	item, err := f.secrets.Get(organizationID, secretID)
+	if err == secret.ErrSecretNotExists {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WrapIf(err, "failed to get secret")
+	}
+
+	var usages []secretusage.Usage
+
+	for _, tag := range item.Tags {
+		if !strings.HasPrefix(tag, spotguideRepoTagPrefix) {
+			continue
+		}
+
+		repo := strings.TrimPrefix(tag, spotguideRepoTagPrefix)
+
+		usages = append(usages, secretusage.Usage{
+			Kind: secretusage.SpotguideKind,
+			ID:   repo,
+			Name: repo,
+		})
+	}
+
+	return usages, nil
+}
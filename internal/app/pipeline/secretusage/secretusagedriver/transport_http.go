@@ -0,0 +1,86 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusagedriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"emperror.dev/errors"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/gorilla/mux"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+	"github.com/banzaicloud/pipeline/pkg/problems"
+)
+
+// RegisterHTTPHandlers mounts all of the service endpoints into a router.
+func RegisterHTTPHandlers(endpoints Endpoints, router *mux.Router, options ...kithttp.ServerOption) {
+	router.Methods(http.MethodGet).Path("").Handler(kithttp.NewServer(
+		endpoints.ListUsages,
+		decodeListUsagesHTTPRequest,
+		kitxhttp.ErrorResponseEncoder(encodeListUsagesHTTPResponse, errorEncoder),
+		options...,
+	))
+}
+
+// usageResponse is the JSON representation of a secret usage.
+type usageResponse struct {
+	Kind      string `json:"kind"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ClusterID uint   `json:"clusterId,omitempty"`
+}
+
+func decodeListUsagesHTTPRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	secretID, ok := mux.Vars(r)["secretId"]
+	if !ok || secretID == "" {
+		return nil, errors.NewWithDetails("missing parameter from the URL", "param", "secretId")
+	}
+
+	return listUsagesRequest{SecretID: secretID}, nil
+}
+
+func encodeListUsagesHTTPResponse(ctx context.Context, w http.ResponseWriter, resp interface{}) error {
+	usages := resp.([]secretusage.Usage)
+
+	items := make([]usageResponse, 0, len(usages))
+	for _, usage := range usages {
+		items = append(items, usageResponse{
+			Kind:      usage.Kind,
+			ID:        usage.ID,
+			Name:      usage.Name,
+			ClusterID: usage.ClusterID,
+		})
+	}
+
+	return kitxhttp.JSONResponseEncoder(ctx, w, items)
+}
+
+func errorEncoder(_ context.Context, w http.ResponseWriter, e error) error {
+	problem := problems.NewDetailedProblem(http.StatusInternalServerError, "something went wrong")
+
+	w.Header().Set("Content-Type", problems.ProblemMediaType)
+	w.WriteHeader(problem.ProblemStatus())
+
+	err := json.NewEncoder(w).Encode(problem)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode error response")
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secretusagedriver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/secretusage"
+)
+
+func TestRegisterHTTPHandlers_ListUsages(t *testing.T) {
+	handler := mux.NewRouter()
+	RegisterHTTPHandlers(
+		Endpoints{
+			ListUsages: func(ctx context.Context, request interface{}) (response interface{}, err error) {
+				assert.Equal(t, listUsagesRequest{SecretID: "secret"}, request)
+
+				return []secretusage.Usage{
+					{Kind: secretusage.BucketKind, ID: "amazon/my-bucket", Name: "my-bucket"},
+					{Kind: secretusage.ClusterKind, ID: "1", Name: "my-cluster", ClusterID: 1},
+				}, nil
+			},
+		},
+		handler.PathPrefix("/secrets/{secretId}/usages").Subrouter(),
+	)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/secrets/secret/usages")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var usages []map[string]interface{}
+
+	err = json.NewDecoder(resp.Body).Decode(&usages)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]map[string]interface{}{
+			{"kind": "bucket", "id": "amazon/my-bucket", "name": "my-bucket"},
+			{"kind": "cluster", "id": "1", "name": "my-cluster", "clusterId": float64(1)},
+		},
+		usages,
+	)
+}
@@ -69,6 +69,28 @@ type Backup struct {
 	ClusterID       uint    `json:"clusterId,omitempty"`
 	ActiveClusterID uint    `json:"activeClusterId,omitempty"`
 	Bucket          *Bucket `json:"-"`
+
+	Verification *BackupVerification `json:"verification,omitempty"`
+}
+
+// BackupContents describes the objects stored in an ARK backup
+type BackupContents struct {
+	Namespaces       []BackupNamespaceContents `json:"namespaces"`
+	ClusterResources []BackupResourceContents  `json:"clusterResources"`
+}
+
+// BackupNamespaceContents describes the namespaced objects of a backup
+type BackupNamespaceContents struct {
+	Name      string                   `json:"name"`
+	Resources []BackupResourceContents `json:"resources"`
+}
+
+// BackupResourceContents describes the objects of a resource type within a backup
+type BackupResourceContents struct {
+	// Resource is the resource name with the API group, e.g. deployments.apps
+	Resource string   `json:"resource"`
+	Kind     string   `json:"kind"`
+	Names    []string `json:"names"`
 }
 
 // DeleteBackupResponse describes a delete backup response
@@ -43,4 +43,5 @@ type Cluster interface {
 // ClusterManager interface for getting clusters
 type ClusterManager interface {
 	GetClusters(context.Context, uint) ([]Cluster, error)
+	GetClusterLabels(context.Context, uint) (map[string]string, error)
 }
@@ -0,0 +1,127 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+)
+
+// Migration statuses
+const (
+	MigrationStatusPending   = "Pending"
+	MigrationStatusRunning   = "Running"
+	MigrationStatusCompleted = "Completed"
+	MigrationStatusFailed    = "Failed"
+)
+
+// Migration steps
+const (
+	MigrationStepBackup        = "Backup"
+	MigrationStepPrepareTarget = "PrepareTarget"
+	MigrationStepRestore       = "Restore"
+	MigrationStepCleanup       = "Cleanup"
+)
+
+// Migration result scopes and severities
+const (
+	MigrationResultScopeMigration = "migration"
+	MigrationResultScopeArk       = "ark"
+	MigrationResultScopeCluster   = "cluster"
+	MigrationResultScopeNamespace = "namespace"
+
+	MigrationResultSeverityError   = "error"
+	MigrationResultSeverityWarning = "warning"
+)
+
+// CreateMigrationRequest describes a request for migrating workloads from one cluster to another through a backup
+type CreateMigrationRequest struct {
+	SourceClusterID uint `json:"sourceClusterId" binding:"required"`
+	TargetClusterID uint `json:"targetClusterId" binding:"required"`
+
+	// BackupName selects an existing backup of the source cluster,
+	// a new backup is taken when it is empty.
+	BackupName string `json:"backupName,omitempty"`
+
+	Options MigrationOptions `json:"options,omitempty"`
+}
+
+// MigrationOptions defines which parts of the backup are restored and where
+type MigrationOptions struct {
+	// IncludedNamespaces is a slice of namespace names to migrate.
+	// If empty, all namespaces are migrated.
+	IncludedNamespaces []string `json:"includedNamespaces,omitempty"`
+
+	// NamespaceMapping is a map of source namespace names
+	// to target namespace names to restore into.
+	NamespaceMapping map[string]string `json:"namespaceMapping,omitempty"`
+
+	// RestorePVs specifies whether to restore volumes from snapshots.
+	// Snapshots can only be restored within the same cloud and location,
+	// so it defaults to false for migrations across them.
+	RestorePVs *bool `json:"restorePVs,omitempty"`
+}
+
+// PersistMigrationStatusRequest describes the progress of a migration to be persisted
+type PersistMigrationStatusRequest struct {
+	Status        string
+	Step          string
+	StatusMessage string
+
+	BucketID    uint
+	BackupName  string
+	RestoreName string
+
+	Results *MigrationResults
+}
+
+// Migration describes a cross-cluster backup and restore
+type Migration struct {
+	ID              uint             `json:"id"`
+	SourceClusterID uint             `json:"sourceClusterId"`
+	TargetClusterID uint             `json:"targetClusterId"`
+	BucketID        uint             `json:"bucketId,omitempty"`
+	BackupName      string           `json:"backupName,omitempty"`
+	RestoreName     string           `json:"restoreName,omitempty"`
+	Options         MigrationOptions `json:"options,omitempty"`
+	Status          string           `json:"status"`
+	Step            string           `json:"step,omitempty"`
+	StatusMessage   string           `json:"statusMessage,omitempty"`
+
+	Results *MigrationResults `json:"results,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// MigrationResults describes the outcome of a migration
+type MigrationResults struct {
+	Errors    uint                      `json:"errors"`
+	Warnings  uint                      `json:"warnings"`
+	Resources []MigrationResourceResult `json:"resources"`
+}
+
+// MigrationResourceResult describes an error or a warning reported for a resource during a migration
+type MigrationResourceResult struct {
+	Scope     string `json:"scope"`
+	Namespace string `json:"namespace,omitempty"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+}
+
+// CreateMigrationResponse describes a create migration response
+type CreateMigrationResponse struct {
+	Migration *Migration `json:"migration"`
+	Status    int        `json:"status"`
+}
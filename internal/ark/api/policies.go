@@ -0,0 +1,168 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// LabelKeyBackupPolicy label key used for marking schedules created by a backup policy
+	LabelKeyBackupPolicy = "pipeline-backup-policy"
+
+	policyScheduleNamePrefix = "policy-"
+	maxPolicyNameLength      = 40
+)
+
+var policyNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// PolicyClusterSelector selects the clusters of an organization a backup policy applies to.
+// A cluster matches the selector if its cloud and distribution are one of the listed values
+// (empty lists match every cluster) and it has every listed label with the same value.
+type PolicyClusterSelector struct {
+	Cloud        []string          `json:"cloud,omitempty"`
+	Distribution []string          `json:"distribution,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+}
+
+// Matches returns true if the cluster with the given labels is selected
+func (s PolicyClusterSelector) Matches(cluster Cluster, labels map[string]string) bool {
+	if !matchesAny(s.Cloud, cluster.GetCloud()) || !matchesAny(s.Distribution, cluster.GetDistribution()) {
+		return false
+	}
+
+	for name, value := range s.Labels {
+		if v, ok := labels[name]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PolicyBucket describes where the backups of the clusters matching a policy are stored.
+// ARK needs a dedicated bucket for every cluster, so the bucket name is used as a prefix
+// and the bucket of a cluster is created on demand.
+type PolicyBucket struct {
+	Cloud      string `json:"cloud" binding:"required"`
+	BucketName string `json:"bucketName" binding:"required"`
+	SecretID   string `json:"secretId" binding:"required"`
+	Location   string `json:"location"`
+
+	AzureBucketProperties `json:"azure"`
+}
+
+// ClusterBucketName returns the name of the bucket used for a cluster
+func (b PolicyBucket) ClusterBucketName(clusterName string) string {
+	return fmt.Sprintf("%s-%s", b.BucketName, clusterName)
+}
+
+// CreateBackupPolicyRequest describes a create backup policy request
+type CreateBackupPolicyRequest struct {
+	Name     string                `json:"name" binding:"required"`
+	Selector PolicyClusterSelector `json:"selector"`
+	Schedule string                `json:"schedule" binding:"required"`
+	TTL      string                `json:"ttl" binding:"required"`
+	// RPO is the maximum age of the last successful backup of a compliant cluster
+	RPO     string        `json:"rpo" binding:"required"`
+	Bucket  PolicyBucket  `json:"bucket"`
+	Options BackupOptions `json:"options,omitempty"`
+}
+
+// Validate validates a CreateBackupPolicyRequest
+func (req CreateBackupPolicyRequest) Validate() error {
+	if len(req.Name) > maxPolicyNameLength || !policyNameRegexp.MatchString(req.Name) {
+		return errors.Errorf("policy name must consist of lower case alphanumeric characters or '-' and must be at most %d characters long", maxPolicyNameLength)
+	}
+
+	if _, err := time.ParseDuration(req.TTL); err != nil {
+		return errors.Wrap(err, "invalid ttl")
+	}
+
+	rpo, err := time.ParseDuration(req.RPO)
+	if err != nil {
+		return errors.Wrap(err, "invalid rpo")
+	}
+	if rpo <= 0 {
+		return errors.New("rpo must be positive")
+	}
+
+	return nil
+}
+
+// BackupPolicy describes an organization level backup policy
+type BackupPolicy struct {
+	ID        uint                  `json:"id"`
+	Name      string                `json:"name"`
+	Selector  PolicyClusterSelector `json:"selector"`
+	Schedule  string                `json:"schedule"`
+	TTL       string                `json:"ttl"`
+	RPO       string                `json:"rpo"`
+	Bucket    PolicyBucket          `json:"bucket"`
+	Options   BackupOptions         `json:"options,omitempty"`
+	CreatedAt time.Time             `json:"createdAt"`
+}
+
+// ScheduleName returns the name of the ARK schedule created by the policy
+func (p *BackupPolicy) ScheduleName() string {
+	return policyScheduleNamePrefix + p.Name
+}
+
+// CreateBackupPolicyResponse describes a create backup policy response
+type CreateBackupPolicyResponse struct {
+	Policy *BackupPolicy `json:"policy"`
+	Status int           `json:"status"`
+}
+
+// DeleteBackupPolicyResponse describes a delete backup policy response
+type DeleteBackupPolicyResponse struct {
+	ID     uint `json:"id"`
+	Status int  `json:"status"`
+}
+
+// BackupPolicyComplianceReport describes the clusters which are out of compliance with the backup policies
+type BackupPolicyComplianceReport struct {
+	GeneratedAt          time.Time                    `json:"generatedAt"`
+	CheckedClusters      int                          `json:"checkedClusters"`
+	NonCompliantClusters []ClusterComplianceViolation `json:"nonCompliantClusters"`
+}
+
+// ClusterComplianceViolation describes why a cluster is out of compliance with a backup policy
+type ClusterComplianceViolation struct {
+	PolicyID             uint       `json:"policyId"`
+	PolicyName           string     `json:"policyName"`
+	ClusterID            uint       `json:"clusterId"`
+	ClusterName          string     `json:"clusterName"`
+	LastSuccessfulBackup *time.Time `json:"lastSuccessfulBackup,omitempty"`
+	Reasons              []string   `json:"reasons"`
+}
@@ -0,0 +1,125 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+type fakeCluster struct {
+	cloud        string
+	distribution string
+}
+
+func (c fakeCluster) GetID() uint                   { return 1 }
+func (c fakeCluster) GetName() string               { return "test" }
+func (c fakeCluster) GetOrganizationId() uint       { return 1 }
+func (c fakeCluster) GetCloud() string              { return c.cloud }
+func (c fakeCluster) GetDistribution() string       { return c.distribution }
+func (c fakeCluster) GetK8sConfig() ([]byte, error) { return nil, nil }
+func (c fakeCluster) GetLocation() string           { return "" }
+func (c fakeCluster) RbacEnabled() bool             { return true }
+func (c fakeCluster) GetSecretWithValidation() (*secret.SecretItemResponse, error) {
+	return nil, nil
+}
+func (c fakeCluster) GetStatus() (*pkgCluster.GetClusterStatusResponse, error) {
+	return nil, nil
+}
+
+func TestPolicyClusterSelector_Matches(t *testing.T) {
+	eks := fakeCluster{cloud: "amazon", distribution: "eks"}
+	gke := fakeCluster{cloud: "google", distribution: "gke"}
+	production := map[string]string{"environment": "production", "team": "shop"}
+	staging := map[string]string{"environment": "staging"}
+
+	tests := []struct {
+		name     string
+		selector PolicyClusterSelector
+		cluster  Cluster
+		labels   map[string]string
+		matches  bool
+	}{
+		{name: "empty selector", cluster: gke, labels: staging, matches: true},
+		{name: "cloud", selector: PolicyClusterSelector{Cloud: []string{"amazon", "azure"}}, cluster: eks, matches: true},
+		{name: "other cloud", selector: PolicyClusterSelector{Cloud: []string{"amazon"}}, cluster: gke, matches: false},
+		{name: "distribution", selector: PolicyClusterSelector{Distribution: []string{"gke"}}, cluster: gke, matches: true},
+		{
+			name:     "labels",
+			selector: PolicyClusterSelector{Labels: map[string]string{"environment": "production"}},
+			cluster:  eks,
+			labels:   production,
+			matches:  true,
+		},
+		{
+			name:     "other label value",
+			selector: PolicyClusterSelector{Labels: map[string]string{"environment": "production"}},
+			cluster:  eks,
+			labels:   staging,
+			matches:  false,
+		},
+		{
+			name:     "cloud and labels",
+			selector: PolicyClusterSelector{Cloud: []string{"google"}, Labels: map[string]string{"environment": "production"}},
+			cluster:  eks,
+			labels:   production,
+			matches:  false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.matches, test.selector.Matches(test.cluster, test.labels))
+		})
+	}
+}
+
+func TestCreateBackupPolicyRequest_Validate(t *testing.T) {
+	valid := CreateBackupPolicyRequest{
+		Name:     "production",
+		Schedule: "0 */6 * * *",
+		TTL:      "720h",
+		RPO:      "12h",
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalidName := valid
+	invalidName.Name = "Production_Clusters"
+	assert.Error(t, invalidName.Validate())
+
+	invalidTTL := valid
+	invalidTTL.TTL = "30 days"
+	assert.Error(t, invalidTTL.Validate())
+
+	invalidRPO := valid
+	invalidRPO.RPO = "0s"
+	assert.Error(t, invalidRPO.Validate())
+}
+
+func TestBackupPolicy_Names(t *testing.T) {
+	policy := BackupPolicy{
+		Name:   "production",
+		Bucket: PolicyBucket{BucketName: "prod-backups"},
+	}
+
+	assert.Equal(t, "policy-production", policy.ScheduleName())
+	assert.Equal(t, "prod-backups-shop-eu", policy.Bucket.ClusterBucketName("shop-eu"))
+}
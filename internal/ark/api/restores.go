@@ -15,11 +15,31 @@
 package api
 
 import (
+	"strings"
+
 	arkAPI "github.com/heptio/ark/pkg/apis/ark/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
+const (
+	// LabelKeyRestoreType label key used for marking restores which were not executed by ARK
+	LabelKeyRestoreType = "pipeline-restore-type"
+	// RestoreTypeObjects is the restore type of object level restores executed by Pipeline
+	RestoreTypeObjects = "objects"
+	// AnnotationKeyObjectSelectors annotation key used for storing the object selectors of a restore
+	AnnotationKeyObjectSelectors = "pipeline-object-selectors"
+)
+
+// RestorePhaseFailed means an object level restore could not be executed
+const RestorePhaseFailed arkAPI.RestorePhase = "Failed"
+
+// ObjectSelectors describes the object selectors of a restore
+type ObjectSelectors struct {
+	IncludedObjects []ObjectSelector `json:"includedObjects,omitempty"`
+	ExcludedObjects []ObjectSelector `json:"excludedObjects,omitempty"`
+}
+
 // PersistRestoreRequest describes a persist restore request
 type PersistRestoreRequest struct {
 	BucketID  uint
@@ -88,6 +108,113 @@ type RestoreOptions struct {
 	// should be included for consideration in the restore. If null, defaults
 	// to true.
 	IncludeClusterResources *bool `json:"includeClusterResources,omitempty"`
+
+	// IncludedObjects is a slice of object selectors to include in the
+	// restore. If set, only the matching objects are restored.
+	IncludedObjects []ObjectSelector `json:"includedObjects,omitempty"`
+
+	// ExcludedObjects is a slice of object selectors that are not
+	// included in the restore.
+	ExcludedObjects []ObjectSelector `json:"excludedObjects,omitempty"`
+}
+
+// ObjectSelector selects objects of a backup, empty fields match everything
+type ObjectSelector struct {
+	// Namespace of the object, must be empty for cluster scoped objects
+	Namespace string `json:"namespace,omitempty"`
+
+	// Resource name of the object, with or without the API group (e.g. deployments or deployments.apps)
+	Resource string `json:"resource,omitempty"`
+
+	// Name of the object
+	Name string `json:"name,omitempty"`
+}
+
+// Matches returns true if the object identified by namespace, resource and name is selected
+func (s ObjectSelector) Matches(namespace, resource, name string) bool {
+	if s.Namespace != "" && s.Namespace != namespace {
+		return false
+	}
+
+	if s.Name != "" && s.Name != name {
+		return false
+	}
+
+	return s.Resource == "" || resourceMatches(s.Resource, resource)
+}
+
+// HasObjectSelectors returns true if the restore should be done at object granularity
+func (o RestoreOptions) HasObjectSelectors() bool {
+	return len(o.IncludedObjects) > 0 || len(o.ExcludedObjects) > 0
+}
+
+// SelectsObject returns true if the object identified by namespace, resource and name
+// should be restored according to the options, cluster scoped objects have an empty namespace
+func (o RestoreOptions) SelectsObject(namespace, resource, name string) bool {
+	if namespace == "" {
+		if o.IncludeClusterResources != nil && !*o.IncludeClusterResources {
+			return false
+		}
+	} else if !listSelects(o.IncludedNamespaces, o.ExcludedNamespaces, func(item string) bool {
+		return item == namespace
+	}) {
+		return false
+	}
+
+	if !listSelects(o.IncludedResources, o.ExcludedResources, func(item string) bool {
+		return resourceMatches(item, resource)
+	}) {
+		return false
+	}
+
+	for _, selector := range o.ExcludedObjects {
+		if selector.Matches(namespace, resource, name) {
+			return false
+		}
+	}
+
+	if len(o.IncludedObjects) == 0 {
+		return true
+	}
+
+	for _, selector := range o.IncludedObjects {
+		if selector.Matches(namespace, resource, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listSelects evaluates ARK style include/exclude lists where "*" and an empty include list mean everything
+func listSelects(included, excluded []string, matches func(item string) bool) bool {
+	for _, item := range excluded {
+		if item == "*" || matches(item) {
+			return false
+		}
+	}
+
+	if len(included) == 0 {
+		return true
+	}
+
+	for _, item := range included {
+		if item == "*" || matches(item) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resourceMatches compares a resource name given with or without the API group to a fully qualified one
+func resourceMatches(selector, resource string) bool {
+	selector = strings.ToLower(selector)
+	if selector == resource {
+		return true
+	}
+
+	return !strings.Contains(selector, ".") && strings.SplitN(resource, ".", 2)[0] == selector
 }
 
 // CreateRestoreRequest describes a create restore request
@@ -0,0 +1,116 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestoreOptions_SelectsObject(t *testing.T) {
+	noClusterResources := false
+
+	type object struct {
+		namespace string
+		resource  string
+		name      string
+	}
+
+	tests := []struct {
+		name     string
+		options  RestoreOptions
+		selected []object
+		skipped  []object
+	}{
+		{
+			name:    "empty options",
+			options: RestoreOptions{},
+			selected: []object{
+				{"default", "configmaps", "config"},
+				{"", "clusterroles.rbac.authorization.k8s.io", "admin"},
+			},
+		},
+		{
+			name: "single configmap",
+			options: RestoreOptions{
+				IncludedObjects: []ObjectSelector{{Namespace: "default", Resource: "configmaps", Name: "config"}},
+			},
+			selected: []object{
+				{"default", "configmaps", "config"},
+			},
+			skipped: []object{
+				{"default", "configmaps", "other"},
+				{"kube-system", "configmaps", "config"},
+				{"default", "secrets", "config"},
+			},
+		},
+		{
+			name: "resource without group",
+			options: RestoreOptions{
+				IncludedObjects: []ObjectSelector{{Resource: "deployments", Name: "frontend"}},
+			},
+			selected: []object{
+				{"web", "deployments.apps", "frontend"},
+				{"shop", "deployments.extensions", "frontend"},
+			},
+			skipped: []object{
+				{"web", "deployments.apps", "backend"},
+				{"web", "deploymentconfigs.apps.openshift.io", "frontend"},
+			},
+		},
+		{
+			name: "excluded objects and namespaces",
+			options: RestoreOptions{
+				ExcludedNamespaces: []string{"kube-system"},
+				ExcludedObjects:    []ObjectSelector{{Resource: "secrets", Name: "token"}},
+			},
+			selected: []object{
+				{"default", "configmaps", "config"},
+				{"default", "secrets", "other"},
+			},
+			skipped: []object{
+				{"kube-system", "configmaps", "config"},
+				{"default", "secrets", "token"},
+			},
+		},
+		{
+			name: "no cluster resources",
+			options: RestoreOptions{
+				IncludedResources:       []string{"*"},
+				IncludeClusterResources: &noClusterResources,
+			},
+			selected: []object{
+				{"default", "configmaps", "config"},
+			},
+			skipped: []object{
+				{"", "clusterroles.rbac.authorization.k8s.io", "admin"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			for _, o := range test.selected {
+				assert.True(t, test.options.SelectsObject(o.namespace, o.resource, o.name), "%+v should be selected", o)
+			}
+			for _, o := range test.skipped {
+				assert.False(t, test.options.SelectsObject(o.namespace, o.resource, o.name), "%+v should be skipped", o)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Backup verification statuses
+const (
+	BackupVerificationStatusRunning = "Running"
+	BackupVerificationStatusPassed  = "Passed"
+	BackupVerificationStatusFailed  = "Failed"
+)
+
+// DefaultVerificationReadinessTimeout is the time restored workloads are given to become ready
+const DefaultVerificationReadinessTimeout = 10 * time.Minute
+
+// VerifyBackupRequest describes a request for verifying a backup by restoring it
+type VerifyBackupRequest struct {
+	// TargetClusterID selects a short-lived cluster (one with a TTL) to restore the backup into,
+	// the cluster is not created by the verification, so it has to be created beforehand.
+	// The backup is restored into scratch namespaces of the backed up cluster when it is empty,
+	// without ingresses, jobs and cron jobs.
+	TargetClusterID uint `json:"targetClusterId,omitempty"`
+
+	// ReadinessTimeout is the time restored workloads are given to become ready, defaults to 10m
+	ReadinessTimeout string `json:"readinessTimeout,omitempty"`
+}
+
+// GetReadinessTimeout returns the parsed readiness timeout or the default one
+func (req VerifyBackupRequest) GetReadinessTimeout() (time.Duration, error) {
+	if req.ReadinessTimeout == "" {
+		return DefaultVerificationReadinessTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(req.ReadinessTimeout)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid readiness timeout")
+	}
+	if timeout <= 0 {
+		return 0, errors.New("readiness timeout must be positive")
+	}
+
+	return timeout, nil
+}
+
+// BackupVerification describes the outcome of the latest test restore of a backup
+type BackupVerification struct {
+	Status          string     `json:"status"`
+	Message         string     `json:"message,omitempty"`
+	Problems        []string   `json:"problems,omitempty"`
+	TargetClusterID uint       `json:"targetClusterId,omitempty"`
+	StartedAt       *time.Time `json:"startedAt,omitempty"`
+	FinishedAt      *time.Time `json:"finishedAt,omitempty"`
+}
+
+// VerifyBackupResponse describes a verify backup response
+type VerifyBackupResponse struct {
+	BackupID     uint                `json:"backupId"`
+	Verification *BackupVerification `json:"verification"`
+	Status       int                 `json:"status"`
+}
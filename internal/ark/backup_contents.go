@@ -0,0 +1,242 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"emperror.dev/emperror"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+const (
+	backupResourcesDir     = "resources"
+	backupNamespacedDir    = "namespaces"
+	backupClusterScopedDir = "cluster"
+	backupObjectFileSuffix = ".json"
+)
+
+// backupObject is a single object stored in a backup tarball
+type backupObject struct {
+	Namespace string
+	Resource  string
+	Name      string
+	Data      []byte
+}
+
+// parseBackupContentsPath parses an ARK backup tarball path into namespace, resource and object name,
+// paths are either resources/<resource>/namespaces/<namespace>/<name>.json or resources/<resource>/cluster/<name>.json
+func parseBackupContentsPath(path string) (namespace, resource, name string, ok bool) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) < 4 || parts[0] != backupResourcesDir || !strings.HasSuffix(parts[len(parts)-1], backupObjectFileSuffix) {
+		return
+	}
+
+	resource = parts[1]
+	name = strings.TrimSuffix(parts[len(parts)-1], backupObjectFileSuffix)
+
+	switch {
+	case len(parts) == 5 && parts[2] == backupNamespacedDir:
+		namespace = parts[3]
+	case len(parts) == 4 && parts[2] == backupClusterScopedDir:
+	default:
+		return "", "", "", false
+	}
+
+	return namespace, resource, name, resource != "" && name != ""
+}
+
+// walkBackupContents calls fn for every object stored in the given backup tarball,
+// the data of the object can be read from the given reader until fn returns
+func walkBackupContents(r io.Reader, fn func(object backupObject, data io.Reader) error) error {
+
+	gzf, err := gzip.NewReader(r)
+	if err != nil {
+		return emperror.Wrap(err, "could not read backup contents")
+	}
+
+	tarReader := tar.NewReader(gzf)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return emperror.Wrap(err, "could not read backup contents")
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		namespace, resource, name, ok := parseBackupContentsPath(header.Name)
+		if !ok {
+			continue
+		}
+
+		err = fn(backupObject{
+			Namespace: namespace,
+			Resource:  resource,
+			Name:      name,
+		}, tarReader)
+		if err != nil {
+			return emperror.WrapWith(err, "could not read backup object", "path", header.Name)
+		}
+	}
+}
+
+// readBackupObjectKind reads the kind of a backup object from its data
+func readBackupObjectKind(data io.Reader) string {
+
+	var typeMeta struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.NewDecoder(data).Decode(&typeMeta); err != nil {
+		return ""
+	}
+
+	return typeMeta.Kind
+}
+
+// newBackupContents builds the namespace and resource listing of backup objects,
+// kinds are the kinds of the resources
+func newBackupContents(objects []backupObject, kinds map[string]string) *api.BackupContents {
+
+	resources := make(map[string]map[string]*api.BackupResourceContents)
+
+	for _, object := range objects {
+		if resources[object.Namespace] == nil {
+			resources[object.Namespace] = make(map[string]*api.BackupResourceContents)
+		}
+
+		item := resources[object.Namespace][object.Resource]
+		if item == nil {
+			item = &api.BackupResourceContents{
+				Resource: object.Resource,
+				Kind:     kinds[object.Resource],
+			}
+			resources[object.Namespace][object.Resource] = item
+		}
+
+		item.Names = append(item.Names, object.Name)
+	}
+
+	contents := &api.BackupContents{
+		Namespaces:       make([]api.BackupNamespaceContents, 0),
+		ClusterResources: sortedBackupResourceContents(resources[""]),
+	}
+
+	for namespace, items := range resources {
+		if namespace == "" {
+			continue
+		}
+
+		contents.Namespaces = append(contents.Namespaces, api.BackupNamespaceContents{
+			Name:      namespace,
+			Resources: sortedBackupResourceContents(items),
+		})
+	}
+
+	sort.Slice(contents.Namespaces, func(i, j int) bool {
+		return contents.Namespaces[i].Name < contents.Namespaces[j].Name
+	})
+
+	return contents
+}
+
+func sortedBackupResourceContents(items map[string]*api.BackupResourceContents) []api.BackupResourceContents {
+
+	resources := make([]api.BackupResourceContents, 0, len(items))
+	for _, item := range items {
+		sort.Strings(item.Names)
+		resources = append(resources, *item)
+	}
+
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Resource < resources[j].Resource
+	})
+
+	return resources
+}
+
+// walkBackupContentsFromObjectStore streams a backup from an object store bucket into walkBackupContents
+func (s *BucketsService) walkBackupContentsFromObjectStore(bucket *api.Bucket, backupName string, fn func(object backupObject, data io.Reader) error) error {
+
+	pr, pw := io.Pipe()
+	go func() {
+		err := s.StreamBackupContentsFromObjectStore(bucket, backupName, pw)
+		pw.CloseWithError(emperror.Wrap(err, "could not get backup contents from object store"))
+	}()
+
+	// closing the reader stops the download if the walk returns before the end of the stream
+	defer pr.Close()
+
+	return walkBackupContents(pr, fn)
+}
+
+// getBackupObjects gets the objects with their data from a backup in an object store bucket
+// which are selected by the given function
+func (s *BucketsService) getBackupObjects(bucket *api.Bucket, backupName string, selects func(namespace, resource, name string) bool) (
+	[]backupObject, error) {
+
+	objects := make([]backupObject, 0)
+	err := s.walkBackupContentsFromObjectStore(bucket, backupName, func(object backupObject, data io.Reader) error {
+		if selects != nil && !selects(object.Namespace, object.Resource, object.Name) {
+			return nil
+		}
+
+		var err error
+		object.Data, err = ioutil.ReadAll(data)
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, object)
+
+		return nil
+	})
+
+	return objects, err
+}
+
+// GetBackupContents lists the namespaces, resources and object names of a backup in an object store bucket,
+// only the first object of every resource is read to get the kind of the resource
+func (s *BucketsService) GetBackupContents(bucket *api.Bucket, backupName string) (*api.BackupContents, error) {
+
+	objects := make([]backupObject, 0)
+	kinds := make(map[string]string)
+	err := s.walkBackupContentsFromObjectStore(bucket, backupName, func(object backupObject, data io.Reader) error {
+		if _, ok := kinds[object.Resource]; !ok {
+			kinds[object.Resource] = readBackupObjectKind(data)
+		}
+
+		objects = append(objects, object)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newBackupContents(objects, kinds), nil
+}
@@ -0,0 +1,129 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+func TestParseBackupContentsPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		namespace string
+		resource  string
+		name      string
+		ok        bool
+	}{
+		{path: "resources/deployments.apps/namespaces/default/frontend.json", namespace: "default", resource: "deployments.apps", name: "frontend", ok: true},
+		{path: "resources/nodes/cluster/node-1.example.com.json", resource: "nodes", name: "node-1.example.com", ok: true},
+		{path: "metadata/version"},
+		{path: "resources/configmaps/namespaces/default.json"},
+		{path: "resources/configmaps/cluster/default/config.json"},
+		{path: "resources/configmaps/namespaces/default/config.yaml"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.path, func(t *testing.T) {
+			namespace, resource, name, ok := parseBackupContentsPath(test.path)
+
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.namespace, namespace)
+			assert.Equal(t, test.resource, resource)
+			assert.Equal(t, test.name, name)
+		})
+	}
+}
+
+func backupTarball(t *testing.T, files map[string]string) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(content)),
+		})
+		require.NoError(t, err)
+
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+
+	return buf
+}
+
+func TestNewBackupContents(t *testing.T) {
+	tarball := backupTarball(t, map[string]string{
+		"metadata/version": "1",
+		"resources/deployments.apps/namespaces/web/frontend.json":             `{"kind":"Deployment"}`,
+		"resources/deployments.apps/namespaces/web/backend.json":              `{"kind":"Deployment"}`,
+		"resources/configmaps/namespaces/web/config.json":                     `{"kind":"ConfigMap"}`,
+		"resources/configmaps/namespaces/db/config.json":                      `{"kind":"ConfigMap"}`,
+		"resources/clusterroles.rbac.authorization.k8s.io/cluster/admin.json": `{"kind":"ClusterRole"}`,
+	})
+
+	objects := make([]backupObject, 0)
+	kinds := make(map[string]string)
+	err := walkBackupContents(tarball, func(object backupObject, data io.Reader) error {
+		if _, ok := kinds[object.Resource]; !ok {
+			kinds[object.Resource] = readBackupObjectKind(data)
+		}
+
+		objects = append(objects, object)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, objects, 5)
+
+	expected := &api.BackupContents{
+		Namespaces: []api.BackupNamespaceContents{
+			{
+				Name: "db",
+				Resources: []api.BackupResourceContents{
+					{Resource: "configmaps", Kind: "ConfigMap", Names: []string{"config"}},
+				},
+			},
+			{
+				Name: "web",
+				Resources: []api.BackupResourceContents{
+					{Resource: "configmaps", Kind: "ConfigMap", Names: []string{"config"}},
+					{Resource: "deployments.apps", Kind: "Deployment", Names: []string{"backend", "frontend"}},
+				},
+			},
+		},
+		ClusterResources: []api.BackupResourceContents{
+			{Resource: "clusterroles.rbac.authorization.k8s.io", Kind: "ClusterRole", Names: []string{"admin"}},
+		},
+	}
+
+	assert.Equal(t, expected, newBackupContents(objects, kinds))
+}
@@ -47,6 +47,13 @@ type ClusterBackupsModel struct {
 	Status        string
 	StatusMessage string `sql:"type:text"`
 
+	VerificationStatus          string
+	VerificationMessage         string `sql:"type:text"`
+	VerificationProblems        []byte `sql:"type:json"`
+	VerificationTargetClusterID uint
+	VerificationStartedAt       *time.Time
+	VerificationFinishedAt      *time.Time
+
 	Organization   auth.Organization             `gorm:"foreignkey:OrganizationID"`
 	OrganizationID uint                          `gorm:"index;not null"`
 	Cluster        model.ClusterModel            `gorm:"foreignkey:ClusterID"`
@@ -100,9 +107,32 @@ func (backup *ClusterBackupsModel) ConvertModelToEntity() *api.Backup {
 		item.Bucket = backup.Bucket.ConvertModelToEntity()
 	}
 
+	item.Verification = backup.GetVerification()
+
 	return item
 }
 
+// GetVerification returns the outcome of the latest verification of the backup, or nil if it has never been verified
+func (backup *ClusterBackupsModel) GetVerification() *api.BackupVerification {
+	if backup.VerificationStatus == "" {
+		return nil
+	}
+
+	verification := &api.BackupVerification{
+		Status:          backup.VerificationStatus,
+		Message:         backup.VerificationMessage,
+		TargetClusterID: backup.VerificationTargetClusterID,
+		StartedAt:       backup.VerificationStartedAt,
+		FinishedAt:      backup.VerificationFinishedAt,
+	}
+
+	if len(backup.VerificationProblems) > 0 {
+		_ = json.Unmarshal(backup.VerificationProblems, &verification.Problems)
+	}
+
+	return verification
+}
+
 // GetStateObject gives back ark Backup from saved json
 func (backup *ClusterBackupsModel) GetStateObject() *arkAPI.Backup {
 
@@ -15,6 +15,10 @@
 package ark
 
 import (
+	"encoding/json"
+
+	"emperror.dev/emperror"
+	arkAPI "github.com/heptio/ark/pkg/apis/ark/v1"
 	"github.com/jinzhu/gorm"
 	"github.com/sirupsen/logrus"
 
@@ -79,6 +83,21 @@ func (r *BackupsRepository) FindOneByID(id uint) (*ClusterBackupsModel, error) {
 	return &backup, err
 }
 
+// FindCompletedByClusterID returns the completed ClusterBackupsModels of a cluster, the last completed first
+func (r *BackupsRepository) FindCompletedByClusterID(clusterID uint) ([]*ClusterBackupsModel, error) {
+	var backups []*ClusterBackupsModel
+
+	query := &ClusterBackupsModel{
+		OrganizationID: r.org.ID,
+		ClusterID:      clusterID,
+		Status:         string(arkAPI.BackupPhaseCompleted),
+	}
+
+	err := r.db.Where(&query).Order("completed_at desc").Find(&backups).Error
+
+	return backups, err
+}
+
 // FindByPersistRequest returns a ClusterBackupsModel by PersistBackupRequest
 func (r *BackupsRepository) FindByPersistRequest(req *api.PersistBackupRequest) (
 	*ClusterBackupsModel, error) {
@@ -150,3 +169,22 @@ func (r *BackupsRepository) UpdateStatus(backup *ClusterBackupsModel, status, me
 
 	return r.db.Save(&backup).Error
 }
+
+// UpdateVerification updates the verification fields of ClusterBackupsModel only,
+// so that it does not interfere with the backup being synced at the same time
+func (r *BackupsRepository) UpdateVerification(backup *ClusterBackupsModel, verification api.BackupVerification) error {
+
+	problems, err := json.Marshal(verification.Problems)
+	if err != nil {
+		return emperror.Wrap(err, "error converting verification problems to json")
+	}
+
+	return r.db.Model(backup).Updates(map[string]interface{}{
+		"verification_status":            verification.Status,
+		"verification_message":           verification.Message,
+		"verification_problems":          problems,
+		"verification_target_cluster_id": verification.TargetClusterID,
+		"verification_started_at":        verification.StartedAt,
+		"verification_finished_at":       verification.FinishedAt,
+	}).Error
+}
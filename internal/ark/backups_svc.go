@@ -142,3 +142,14 @@ func (s *BackupsService) DeleteBackupsWithoutBucket() error {
 
 	return s.repository.DeleteBackupsWithoutBucket()
 }
+
+// UpdateVerification records the outcome of a backup verification
+func (s *BackupsService) UpdateVerification(id uint, verification api.BackupVerification) error {
+
+	backup, err := s.GetModelByID(id)
+	if err != nil {
+		return err
+	}
+
+	return s.repository.UpdateVerification(backup, verification)
+}
@@ -43,6 +43,7 @@ func (c *Client) CreateRestore(req api.CreateRestoreRequest) (*arkAPI.Restore, e
 			ExcludedResources:       req.Options.ExcludedResources,
 			IncludeClusterResources: req.Options.IncludeClusterResources,
 			LabelSelector:           req.Options.LabelSelector,
+			NamespaceMapping:        req.Options.NamespaceMapping,
 			RestorePVs:              req.Options.RestorePVs,
 		},
 	}
@@ -45,3 +45,13 @@ func (cm *ClusterManager) GetClusters(ctx context.Context, organizationID uint)
 
 	return apiClusters, nil
 }
+
+// GetClusterByID returns a cluster of an organization by ID
+func (cm *ClusterManager) GetClusterByID(ctx context.Context, organizationID uint, clusterID uint) (api.Cluster, error) {
+	return cm.clusterManager.GetClusterByID(ctx, organizationID, clusterID)
+}
+
+// GetClusterLabels returns the user-defined labels of a cluster
+func (cm *ClusterManager) GetClusterLabels(ctx context.Context, clusterID uint) (map[string]string, error) {
+	return cm.clusterManager.GetClusterLabels(ctx, clusterID)
+}
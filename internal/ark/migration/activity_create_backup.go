@@ -0,0 +1,121 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/emperror"
+	arkAPI "github.com/heptio/ark/pkg/apis/ark/v1"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+const CreateBackupActivityName = "ark-migration-create-backup"
+
+// backupTTL is the retention of the backups taken for migrations
+const backupTTL = 30 * 24 * time.Hour
+
+type CreateBackupActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+	MigrationID    uint
+
+	BackupName         string
+	IncludedNamespaces []string
+}
+
+// CreateBackupActivity takes a backup of the source cluster and waits for it to complete.
+type CreateBackupActivity struct {
+	clusters Clusters
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+// NewCreateBackupActivity returns a new CreateBackupActivity.
+func NewCreateBackupActivity(clusters Clusters, db *gorm.DB, logger logrus.FieldLogger) CreateBackupActivity {
+	return CreateBackupActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a CreateBackupActivity) Execute(ctx context.Context, input CreateBackupActivityInput) error {
+	logger := a.logger.WithFields(logrus.Fields{
+		"clusterId": input.ClusterID,
+		"backup":    input.BackupName,
+	})
+
+	svc, err := getARKService(ctx, a.clusters, a.db, logger, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	client, err := svc.GetDeploymentsService().GetClient()
+	if err != nil {
+		return emperror.Wrap(err, "backup service is not enabled on the source cluster")
+	}
+
+	// the backup might already exist if the activity is retried
+	_, err = client.GetBackupByName(input.BackupName)
+	if k8serrors.IsNotFound(err) {
+		logger.Info("creating backup")
+
+		err = svc.GetClusterBackupsService().Create(api.CreateBackupRequest{
+			Name: input.BackupName,
+			TTL:  metav1.Duration{Duration: backupTTL},
+			Labels: labels.Set{
+				migrationIDLabelKey: fmt.Sprint(input.MigrationID),
+			},
+			Options: api.BackupOptions{
+				IncludedNamespaces: input.IncludedNamespaces,
+			},
+		})
+	}
+	if err != nil {
+		return emperror.Wrap(err, "could not create backup")
+	}
+
+	return waitFor(ctx, func() (bool, error) {
+		backup, err := client.GetBackupByName(input.BackupName)
+		if err != nil {
+			return false, emperror.Wrap(err, "could not get backup")
+		}
+
+		switch backup.Status.Phase {
+		case arkAPI.BackupPhaseCompleted:
+			return true, nil
+		case arkAPI.BackupPhaseFailed, arkAPI.BackupPhaseFailedValidation:
+			return false, emperror.With(
+				errors.Errorf("backup %s", backup.Status.Phase),
+				"backup", input.BackupName,
+				"validationErrors", backup.Status.ValidationErrors,
+			)
+		}
+
+		logger.WithField("phase", backup.Status.Phase).Debug("backup in progress")
+
+		return false, nil
+	})
+}
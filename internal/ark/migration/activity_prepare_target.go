@@ -0,0 +1,147 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
+)
+
+const PrepareTargetActivityName = "ark-migration-prepare-target"
+
+type PrepareTargetActivityInput struct {
+	OrganizationID  uint
+	SourceClusterID uint
+	TargetClusterID uint
+
+	BackupName string
+	RestorePVs *bool
+}
+
+type PrepareTargetActivityOutput struct {
+	BucketID uint
+
+	// ArkDeployed is true if ARK was deployed to the target cluster for the migration
+	ArkDeployed bool
+
+	RestorePVs *bool
+	Warnings   []string
+}
+
+// PrepareTargetActivity makes sure the backup bucket is readable and ARK is deployed to the target cluster in restore mode.
+type PrepareTargetActivity struct {
+	clusters Clusters
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+// NewPrepareTargetActivity returns a new PrepareTargetActivity.
+func NewPrepareTargetActivity(clusters Clusters, db *gorm.DB, logger logrus.FieldLogger) PrepareTargetActivity {
+	return PrepareTargetActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a PrepareTargetActivity) Execute(ctx context.Context, input PrepareTargetActivityInput) (*PrepareTargetActivityOutput, error) {
+	logger := a.logger.WithFields(logrus.Fields{
+		"clusterId": input.TargetClusterID,
+		"backup":    input.BackupName,
+	})
+
+	org, err := auth.GetOrganizationById(input.OrganizationID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get organization", "organizationId", input.OrganizationID)
+	}
+
+	source, err := a.clusters.GetClusterByID(ctx, input.OrganizationID, input.SourceClusterID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get source cluster", "clusterId", input.SourceClusterID)
+	}
+
+	target, err := a.clusters.GetClusterByID(ctx, input.OrganizationID, input.TargetClusterID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get target cluster", "clusterId", input.TargetClusterID)
+	}
+
+	backup, err := ark.BackupsServiceFactory(org, a.db, logger).GetModelByName(input.BackupName)
+	if err != nil {
+		return nil, err
+	}
+
+	// the target cluster reads the bucket with the credentials of the bucket secret,
+	// so the backup has to be accessible with them regardless of the cloud of the target
+	backups, err := ark.BucketsServiceFactory(org, a.db, logger).GetBackupsFromObjectStore(backup.Bucket.ConvertModelToEntity())
+	if err != nil {
+		return nil, emperror.WrapWith(err, "backup bucket is not readable", "bucket", backup.Bucket.BucketName)
+	}
+
+	found := false
+	for _, b := range backups {
+		if b.Name == input.BackupName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, emperror.With(errors.New("backup is not found in the bucket"), "backup", input.BackupName, "bucket", backup.Bucket.BucketName)
+	}
+
+	output := &PrepareTargetActivityOutput{
+		BucketID: backup.BucketID,
+		Warnings: make([]string, 0),
+	}
+
+	var warning string
+	output.RestorePVs, warning = restorePVs(source, target, input.RestorePVs)
+	if warning != "" {
+		output.Warnings = append(output.Warnings, warning)
+	}
+
+	deployments := ark.NewARKService(org, target, a.db, logger).GetDeploymentsService()
+
+	deployment, err := deployments.GetActiveDeployment()
+	if err == nil {
+		if deployment.BucketID != backup.BucketID {
+			return nil, emperror.With(
+				errors.New("backup service of the target cluster uses a different bucket"),
+				"bucketId", deployment.BucketID,
+			)
+		}
+
+		return output, nil
+	}
+	if !gorm.IsRecordNotFoundError(err) {
+		return nil, emperror.Wrap(err, "could not get active deployment")
+	}
+
+	logger.Info("deploying ARK in restore mode")
+
+	err = deployments.Deploy(&backup.Bucket, true)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not deploy ARK to the target cluster")
+	}
+	output.ArkDeployed = true
+
+	return output, nil
+}
@@ -0,0 +1,59 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+const RecordStatusActivityName = "ark-migration-record-status"
+
+type RecordStatusActivityInput struct {
+	OrganizationID uint
+	MigrationID    uint
+
+	Status api.PersistMigrationStatusRequest
+}
+
+// RecordStatusActivity persists the progress of a migration.
+type RecordStatusActivity struct {
+	db     *gorm.DB
+	logger logrus.FieldLogger
+}
+
+// NewRecordStatusActivity returns a new RecordStatusActivity.
+func NewRecordStatusActivity(db *gorm.DB, logger logrus.FieldLogger) RecordStatusActivity {
+	return RecordStatusActivity{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a RecordStatusActivity) Execute(ctx context.Context, input RecordStatusActivityInput) error {
+	org, err := auth.GetOrganizationById(input.OrganizationID)
+	if err != nil {
+		return emperror.WrapWith(err, "could not get organization", "organizationId", input.OrganizationID)
+	}
+
+	return ark.MigrationsServiceFactory(org, a.db, a.logger).UpdateStatus(input.MigrationID, input.Status)
+}
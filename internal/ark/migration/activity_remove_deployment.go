@@ -0,0 +1,64 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+const RemoveDeploymentActivityName = "ark-migration-remove-deployment"
+
+type RemoveDeploymentActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+}
+
+// RemoveDeploymentActivity removes the ARK deployment installed for the migration from the target cluster.
+type RemoveDeploymentActivity struct {
+	clusters Clusters
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+// NewRemoveDeploymentActivity returns a new RemoveDeploymentActivity.
+func NewRemoveDeploymentActivity(clusters Clusters, db *gorm.DB, logger logrus.FieldLogger) RemoveDeploymentActivity {
+	return RemoveDeploymentActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a RemoveDeploymentActivity) Execute(ctx context.Context, input RemoveDeploymentActivityInput) error {
+	logger := a.logger.WithField("clusterId", input.ClusterID)
+
+	svc, err := getARKService(ctx, a.clusters, a.db, logger, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("removing ARK deployment")
+
+	err = svc.GetDeploymentsService().Remove()
+	if err != nil {
+		return emperror.Wrap(err, "could not remove ARK deployment")
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	arkAPI "github.com/heptio/ark/pkg/apis/ark/v1"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/internal/ark/sync"
+)
+
+const RestoreActivityName = "ark-migration-restore"
+
+type RestoreActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+
+	BackupName string
+	Options    api.MigrationOptions
+
+	// Labels identify the restore, so that it is not created again when the activity is retried
+	Labels map[string]string
+
+	// ExcludedResources are the resources of the backup which are not restored
+	ExcludedResources []string
+
+	// IncludeClusterResources specifies whether cluster scoped resources are restored, defaults to true
+	IncludeClusterResources *bool
+}
+
+type RestoreActivityOutput struct {
+	RestoreName string
+	Results     *api.MigrationResults
+}
+
+// RestoreActivity restores the backup into the target cluster and collects the results.
+type RestoreActivity struct {
+	clusters Clusters
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+// NewRestoreActivity returns a new RestoreActivity.
+func NewRestoreActivity(clusters Clusters, db *gorm.DB, logger logrus.FieldLogger) RestoreActivity {
+	return RestoreActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a RestoreActivity) Execute(ctx context.Context, input RestoreActivityInput) (*RestoreActivityOutput, error) {
+	logger := a.logger.WithFields(logrus.Fields{
+		"clusterId": input.ClusterID,
+		"backup":    input.BackupName,
+	})
+
+	svc, err := getARKService(ctx, a.clusters, a.db, logger, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := svc.GetDeploymentsService().GetClient()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get ARK client")
+	}
+
+	// ARK on the target cluster has to sync the backup from the bucket first
+	err = waitFor(ctx, func() (bool, error) {
+		_, err := client.GetBackupByName(input.BackupName)
+		if k8serrors.IsNotFound(err) {
+			logger.Debug("waiting for backup to be synced")
+			return false, nil
+		}
+
+		return err == nil, emperror.Wrap(err, "could not get backup")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	restoreLabels := labels.Set{}
+	for key, value := range input.Labels {
+		restoreLabels[key] = value
+	}
+
+	// the restore might already exist if the activity is retried
+	restores, err := client.ListRestores(metav1.ListOptions{LabelSelector: restoreLabels.String()})
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not list restores")
+	}
+
+	var restoreName string
+	if len(restores.Items) > 0 {
+		restoreName = restores.Items[0].Name
+	} else {
+		logger.Info("creating restore")
+
+		restoreLabels[restoredByLabelKey] = restoredByLabelValue
+
+		restore, err := svc.GetRestoresService().Create(api.CreateRestoreRequest{
+			BackupName: input.BackupName,
+			Labels:     restoreLabels,
+			Options: api.RestoreOptions{
+				IncludedNamespaces: input.Options.IncludedNamespaces,
+				ExcludedNamespaces: nonRestorableNamespaces,
+				NamespaceMapping:   input.Options.NamespaceMapping,
+				RestorePVs:         input.Options.RestorePVs,
+				ExcludedResources:  input.ExcludedResources,
+
+				IncludeClusterResources: input.IncludeClusterResources,
+			},
+		})
+		if err != nil {
+			return nil, emperror.Wrap(err, "could not create restore")
+		}
+
+		restoreName = restore.Name
+	}
+
+	err = waitFor(ctx, func() (bool, error) {
+		restore, err := client.GetRestoreByName(restoreName)
+		if err != nil {
+			return false, emperror.Wrap(err, "could not get restore")
+		}
+
+		switch restore.Status.Phase {
+		case arkAPI.RestorePhaseCompleted:
+			return true, nil
+		case arkAPI.RestorePhaseFailedValidation:
+			return false, emperror.With(
+				errors.Errorf("restore %s", restore.Status.Phase),
+				"restore", restoreName,
+				"validationErrors", restore.Status.ValidationErrors,
+			)
+		}
+
+		logger.WithField("phase", restore.Status.Phase).Debug("restore in progress")
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// syncing persists the restore results read from the bucket
+	err = sync.NewRestoresSyncService(svc.GetOrganization(), a.db, logger).SyncRestoresForCluster(svc.GetCluster())
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not sync restores")
+	}
+
+	restore, err := svc.GetRestoresService().GetByName(restoreName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RestoreActivityOutput{
+		RestoreName: restoreName,
+		Results:     NewMigrationResults(restore.Results),
+	}, nil
+}
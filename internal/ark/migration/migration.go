@@ -0,0 +1,103 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence/activity"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+const (
+	pollInterval = 15 * time.Second
+
+	migrationIDLabelKey  = "pipeline-migration-id"
+	restoredByLabelKey   = "restored-by"
+	restoredByLabelValue = "pipeline"
+)
+
+// nolint: gochecknoglobals
+var (
+	nonRestorableNamespaces = []string{
+		"kube-system",
+	}
+)
+
+// Clusters is the interface for getting the clusters of an organization
+type Clusters interface {
+	GetClusterByID(ctx context.Context, organizationID uint, clusterID uint) (api.Cluster, error)
+}
+
+// CanRestoreVolumes returns true if the volume snapshots taken on the source cluster can be restored into the target cluster
+func CanRestoreVolumes(source api.Cluster, target api.Cluster) bool {
+	return source.GetCloud() == target.GetCloud() && source.GetLocation() == target.GetLocation()
+}
+
+// restorePVs decides whether volumes are restored from snapshots, and returns a warning if they are skipped implicitly
+func restorePVs(source api.Cluster, target api.Cluster, requested *bool) (*bool, string) {
+	if requested != nil || CanRestoreVolumes(source, target) {
+		return requested, ""
+	}
+
+	disabled := false
+
+	return &disabled, "volume snapshots are not restored, because the source and target clusters are in different clouds or locations"
+}
+
+func getARKService(ctx context.Context, clusters Clusters, db *gorm.DB, logger logrus.FieldLogger, organizationID uint, clusterID uint) (*ark.Service, error) {
+	org, err := auth.GetOrganizationById(organizationID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get organization", "organizationId", organizationID)
+	}
+
+	cluster, err := clusters.GetClusterByID(ctx, organizationID, clusterID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get cluster", "clusterId", clusterID)
+	}
+
+	return ark.NewARKService(org, cluster, db, logger), nil
+}
+
+// waitFor polls the condition until it is met, an error occurs or the activity context is done
+func waitFor(ctx context.Context, condition func() (bool, error)) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		done, err := condition()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		activity.RecordHeartbeat(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
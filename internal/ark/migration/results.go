@@ -0,0 +1,86 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"sort"
+
+	arkAPI "github.com/heptio/ark/pkg/apis/ark/v1"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// NewMigrationResults converts the results of an ARK restore to per resource migration results
+func NewMigrationResults(results *api.RestoreResults) *api.MigrationResults {
+	migrationResults := &api.MigrationResults{
+		Resources: make([]api.MigrationResourceResult, 0),
+	}
+
+	if results == nil {
+		return migrationResults
+	}
+
+	addRestoreResult(migrationResults, results.Errors, api.MigrationResultSeverityError)
+	addRestoreResult(migrationResults, results.Warnings, api.MigrationResultSeverityWarning)
+
+	return migrationResults
+}
+
+// addWarning adds a migration level warning to the results
+func addWarning(results *api.MigrationResults, message string) {
+	results.Resources = append(results.Resources, api.MigrationResourceResult{
+		Scope:    api.MigrationResultScopeMigration,
+		Severity: api.MigrationResultSeverityWarning,
+		Message:  message,
+	})
+	results.Warnings++
+}
+
+func addRestoreResult(results *api.MigrationResults, result arkAPI.RestoreResult, severity string) {
+	add := func(scope string, namespace string, message string) {
+		results.Resources = append(results.Resources, api.MigrationResourceResult{
+			Scope:     scope,
+			Namespace: namespace,
+			Severity:  severity,
+			Message:   message,
+		})
+
+		if severity == api.MigrationResultSeverityError {
+			results.Errors++
+		} else {
+			results.Warnings++
+		}
+	}
+
+	for _, message := range result.Ark {
+		add(api.MigrationResultScopeArk, "", message)
+	}
+
+	for _, message := range result.Cluster {
+		add(api.MigrationResultScopeCluster, "", message)
+	}
+
+	namespaces := make([]string, 0, len(result.Namespaces))
+	for namespace := range result.Namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	for _, namespace := range namespaces {
+		for _, message := range result.Namespaces[namespace] {
+			add(api.MigrationResultScopeNamespace, namespace, message)
+		}
+	}
+}
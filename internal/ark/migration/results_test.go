@@ -0,0 +1,62 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"testing"
+
+	arkAPI "github.com/heptio/ark/pkg/apis/ark/v1"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+func arkRestoreResult(cluster []string, namespaces map[string][]string) arkAPI.RestoreResult {
+	return arkAPI.RestoreResult{
+		Cluster:    cluster,
+		Namespaces: namespaces,
+	}
+}
+
+func TestNewMigrationResults(t *testing.T) {
+	results := NewMigrationResults(&api.RestoreResults{
+		Errors: arkRestoreResult(
+			[]string{"error restoring clusterroles/admin"},
+			map[string][]string{
+				"web": {"error restoring deployments/web/frontend"},
+				"db":  {"error restoring statefulsets/db/mysql"},
+			},
+		),
+		Warnings: arkRestoreResult(nil, map[string][]string{
+			"web": {"could not restore, services \"frontend\" already exists"},
+		}),
+	})
+
+	assert.Equal(t, uint(3), results.Errors)
+	assert.Equal(t, uint(1), results.Warnings)
+	assert.Equal(t, []api.MigrationResourceResult{
+		{Scope: "cluster", Severity: "error", Message: "error restoring clusterroles/admin"},
+		{Scope: "namespace", Namespace: "db", Severity: "error", Message: "error restoring statefulsets/db/mysql"},
+		{Scope: "namespace", Namespace: "web", Severity: "error", Message: "error restoring deployments/web/frontend"},
+		{Scope: "namespace", Namespace: "web", Severity: "warning", Message: "could not restore, services \"frontend\" already exists"},
+	}, results.Resources)
+}
+
+func TestNewMigrationResults_Empty(t *testing.T) {
+	results := NewMigrationResults(nil)
+
+	assert.Equal(t, uint(0), results.Errors)
+	assert.Empty(t, results.Resources)
+}
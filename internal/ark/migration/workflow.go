@@ -0,0 +1,184 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// MigrationWorkflowName can be used to reference the migration workflow.
+const MigrationWorkflowName = "ark-migration"
+
+// MigrationWorkflowInput is the input for a migration workflow.
+type MigrationWorkflowInput struct {
+	OrganizationID  uint
+	MigrationID     uint
+	SourceClusterID uint
+	TargetClusterID uint
+
+	// BackupName is the name of the backup to restore
+	BackupName string
+
+	// CreateBackup specifies whether the backup has to be taken from the source cluster first
+	CreateBackup bool
+
+	Options api.MigrationOptions
+}
+
+// BackupName returns the name of the backup taken for a migration.
+func BackupName(migrationID uint, now time.Time) string {
+	return fmt.Sprintf("migration-%d-%s", migrationID, now.Format("20060102150405"))
+}
+
+// MigrationWorkflow takes a backup of the source cluster and restores it into the target cluster.
+func MigrationWorkflow(ctx workflow.Context, input MigrationWorkflowInput) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+	})
+
+	// backups and restores are polled until they finish
+	waitCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    2 * time.Hour,
+		HeartbeatTimeout:       4 * pollInterval,
+	})
+
+	status := api.PersistMigrationStatusRequest{
+		Status:     api.MigrationStatusRunning,
+		BackupName: input.BackupName,
+	}
+
+	recordStatus := func() error {
+		activityInput := RecordStatusActivityInput{
+			OrganizationID: input.OrganizationID,
+			MigrationID:    input.MigrationID,
+			Status:         status,
+		}
+
+		return workflow.ExecuteActivity(ctx, RecordStatusActivityName, activityInput).Get(ctx, nil)
+	}
+
+	fail := func(err error) error {
+		status.Status = api.MigrationStatusFailed
+		status.StatusMessage = err.Error()
+
+		_ = recordStatus()
+
+		return err
+	}
+
+	if input.CreateBackup {
+		status.Step = api.MigrationStepBackup
+		if err := recordStatus(); err != nil {
+			return err
+		}
+
+		activityInput := CreateBackupActivityInput{
+			OrganizationID:     input.OrganizationID,
+			ClusterID:          input.SourceClusterID,
+			MigrationID:        input.MigrationID,
+			BackupName:         input.BackupName,
+			IncludedNamespaces: input.Options.IncludedNamespaces,
+		}
+
+		err := workflow.ExecuteActivity(waitCtx, CreateBackupActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	status.Step = api.MigrationStepPrepareTarget
+	if err := recordStatus(); err != nil {
+		return err
+	}
+
+	var prepared PrepareTargetActivityOutput
+	{
+		activityInput := PrepareTargetActivityInput{
+			OrganizationID:  input.OrganizationID,
+			SourceClusterID: input.SourceClusterID,
+			TargetClusterID: input.TargetClusterID,
+			BackupName:      input.BackupName,
+			RestorePVs:      input.Options.RestorePVs,
+		}
+
+		err := workflow.ExecuteActivity(ctx, PrepareTargetActivityName, activityInput).Get(ctx, &prepared)
+		if err != nil {
+			return fail(err)
+		}
+	}
+
+	status.BucketID = prepared.BucketID
+	status.Step = api.MigrationStepRestore
+	if err := recordStatus(); err != nil {
+		return err
+	}
+
+	var restored RestoreActivityOutput
+	options := input.Options
+	options.RestorePVs = prepared.RestorePVs
+
+	restoreErr := workflow.ExecuteActivity(waitCtx, RestoreActivityName, RestoreActivityInput{
+		OrganizationID: input.OrganizationID,
+		ClusterID:      input.TargetClusterID,
+		BackupName:     input.BackupName,
+		Options:        options,
+		Labels:         map[string]string{migrationIDLabelKey: fmt.Sprint(input.MigrationID)},
+	}).Get(ctx, &restored)
+
+	results := restored.Results
+	if results == nil {
+		results = NewMigrationResults(nil)
+	}
+	for _, warning := range prepared.Warnings {
+		addWarning(results, warning)
+	}
+
+	// ARK is only removed if it was deployed for the migration
+	if prepared.ArkDeployed {
+		status.Step = api.MigrationStepCleanup
+		_ = recordStatus()
+
+		activityInput := RemoveDeploymentActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterID:      input.TargetClusterID,
+		}
+
+		err := workflow.ExecuteActivity(ctx, RemoveDeploymentActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			addWarning(results, "could not remove ARK from the target cluster: "+err.Error())
+		}
+	}
+
+	status.RestoreName = restored.RestoreName
+	status.Results = results
+
+	if restoreErr != nil {
+		return fail(restoreErr)
+	}
+
+	status.Status = api.MigrationStatusCompleted
+	if results.Errors > 0 {
+		status.StatusMessage = fmt.Sprintf("restore completed with %d errors", results.Errors)
+	}
+
+	return recordStatus()
+}
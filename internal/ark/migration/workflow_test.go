@@ -0,0 +1,221 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/testsuite"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// nolint: gochecknoglobals
+var testWorkflowInput = MigrationWorkflowInput{
+	OrganizationID:  1,
+	MigrationID:     2,
+	SourceClusterID: 3,
+	TargetClusterID: 4,
+	BackupName:      "migration-2",
+	CreateBackup:    true,
+	Options: api.MigrationOptions{
+		IncludedNamespaces: []string{"shop"},
+		NamespaceMapping:   map[string]string{"shop": "shop-migrated"},
+	},
+}
+
+type WorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env      *testsuite.TestWorkflowEnvironment
+	statuses []api.PersistMigrationStatusRequest
+}
+
+func TestWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowTestSuite))
+}
+
+func (s *WorkflowTestSuite) SetupSuite() {
+	workflow.RegisterWithOptions(MigrationWorkflow, workflow.RegisterOptions{Name: MigrationWorkflowName})
+
+	activity.RegisterWithOptions(CreateBackupActivity{}.Execute, activity.RegisterOptions{Name: CreateBackupActivityName})
+	activity.RegisterWithOptions(PrepareTargetActivity{}.Execute, activity.RegisterOptions{Name: PrepareTargetActivityName})
+	activity.RegisterWithOptions(RestoreActivity{}.Execute, activity.RegisterOptions{Name: RestoreActivityName})
+	activity.RegisterWithOptions(RemoveDeploymentActivity{}.Execute, activity.RegisterOptions{Name: RemoveDeploymentActivityName})
+	activity.RegisterWithOptions(RecordStatusActivity{}.Execute, activity.RegisterOptions{Name: RecordStatusActivityName})
+}
+
+func (s *WorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+	s.statuses = nil
+
+	s.env.OnActivity(
+		RecordStatusActivityName,
+		mock.Anything,
+		mock.MatchedBy(func(input RecordStatusActivityInput) bool {
+			s.statuses = append(s.statuses, input.Status)
+
+			return input.OrganizationID == 1 && input.MigrationID == 2
+		}),
+	).Return(nil)
+}
+
+func (s *WorkflowTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *WorkflowTestSuite) onCreateBackup() {
+	s.env.OnActivity(
+		CreateBackupActivityName,
+		mock.Anything,
+		CreateBackupActivityInput{
+			OrganizationID:     1,
+			ClusterID:          3,
+			MigrationID:        2,
+			BackupName:         "migration-2",
+			IncludedNamespaces: []string{"shop"},
+		},
+	).Return(nil).Once()
+}
+
+func (s *WorkflowTestSuite) onPrepareTarget(output PrepareTargetActivityOutput) {
+	s.env.OnActivity(
+		PrepareTargetActivityName,
+		mock.Anything,
+		PrepareTargetActivityInput{
+			OrganizationID:  1,
+			SourceClusterID: 3,
+			TargetClusterID: 4,
+			BackupName:      "migration-2",
+		},
+	).Return(&output, nil).Once()
+}
+
+func (s *WorkflowTestSuite) onRestore(restorePVs *bool, output *RestoreActivityOutput, err error) {
+	s.env.OnActivity(
+		RestoreActivityName,
+		mock.Anything,
+		RestoreActivityInput{
+			OrganizationID: 1,
+			ClusterID:      4,
+			BackupName:     "migration-2",
+			Options: api.MigrationOptions{
+				IncludedNamespaces: []string{"shop"},
+				NamespaceMapping:   map[string]string{"shop": "shop-migrated"},
+				RestorePVs:         restorePVs,
+			},
+			Labels: map[string]string{"pipeline-migration-id": "2"},
+		},
+	).Return(output, err).Once()
+}
+
+func (s *WorkflowTestSuite) onRemoveDeployment() {
+	s.env.OnActivity(
+		RemoveDeploymentActivityName,
+		mock.Anything,
+		RemoveDeploymentActivityInput{OrganizationID: 1, ClusterID: 4},
+	).Return(nil).Once()
+}
+
+func (s *WorkflowTestSuite) steps() []string {
+	steps := make([]string, 0, len(s.statuses))
+	for _, status := range s.statuses {
+		steps = append(steps, status.Step+"/"+status.Status)
+	}
+
+	return steps
+}
+
+func (s *WorkflowTestSuite) Test_Success() {
+	restorePVs := false
+
+	s.onCreateBackup()
+	s.onPrepareTarget(PrepareTargetActivityOutput{
+		BucketID:    5,
+		ArkDeployed: true,
+		RestorePVs:  &restorePVs,
+		Warnings:    []string{"volumes are not restored"},
+	})
+	s.onRestore(&restorePVs, &RestoreActivityOutput{
+		RestoreName: "migration-2-20191118120000",
+		Results: NewMigrationResults(&api.RestoreResults{
+			Errors: arkRestoreResult(nil, map[string][]string{"shop-migrated": {"error restoring services/shop-migrated/db"}}),
+		}),
+	}, nil)
+	s.onRemoveDeployment()
+
+	s.env.ExecuteWorkflow(MigrationWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	s.Equal([]string{
+		"Backup/Running",
+		"PrepareTarget/Running",
+		"Restore/Running",
+		"Cleanup/Running",
+		"Cleanup/Completed",
+	}, s.steps())
+
+	final := s.statuses[len(s.statuses)-1]
+	s.Equal(uint(5), final.BucketID)
+	s.Equal("migration-2-20191118120000", final.RestoreName)
+	s.Equal("restore completed with 1 errors", final.StatusMessage)
+	s.Equal(uint(1), final.Results.Errors)
+	s.Equal(uint(1), final.Results.Warnings)
+	s.Len(final.Results.Resources, 2)
+}
+
+func (s *WorkflowTestSuite) Test_ExistingBackup() {
+	input := testWorkflowInput
+	input.CreateBackup = false
+
+	s.onPrepareTarget(PrepareTargetActivityOutput{BucketID: 5})
+	s.onRestore(nil, &RestoreActivityOutput{RestoreName: "migration-2-20191118120000"}, nil)
+
+	s.env.ExecuteWorkflow(MigrationWorkflowName, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	s.Equal([]string{
+		"PrepareTarget/Running",
+		"Restore/Running",
+		"Restore/Completed",
+	}, s.steps())
+}
+
+func (s *WorkflowTestSuite) Test_RestoreFailed_RemovesDeployment() {
+	s.onCreateBackup()
+	s.onPrepareTarget(PrepareTargetActivityOutput{BucketID: 5, ArkDeployed: true})
+	s.onRestore(nil, nil, errors.New("restore FailedValidation"))
+	s.onRemoveDeployment()
+
+	s.env.ExecuteWorkflow(MigrationWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+
+	final := s.statuses[len(s.statuses)-1]
+	s.Equal(api.MigrationStatusFailed, final.Status)
+	s.Equal(api.MigrationStepCleanup, final.Step)
+	s.Contains(final.StatusMessage, "restore FailedValidation")
+}
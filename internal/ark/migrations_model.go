@@ -0,0 +1,140 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"encoding/json"
+	"time"
+
+	"emperror.dev/emperror"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// ClusterBackupMigrationsModel describes a cross-cluster backup and restore
+type ClusterBackupMigrationsModel struct {
+	ID uint `gorm:"primary_key"`
+
+	SourceClusterID uint `gorm:"index;not null"`
+	TargetClusterID uint `gorm:"index;not null"`
+	BucketID        uint
+	BackupName      string
+	RestoreName     string
+
+	Options []byte `sql:"type:json"`
+	Results []byte `sql:"type:json"`
+
+	Status        string
+	Step          string
+	StatusMessage string `sql:"type:text;"`
+
+	Organization   auth.Organization `gorm:"foreignkey:OrganizationID"`
+	OrganizationID uint              `gorm:"index;not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name
+func (ClusterBackupMigrationsModel) TableName() string {
+	return clusterBackupMigrationsTableName
+}
+
+// SetValuesFromRequest set values from a CreateMigrationRequest to the migration object
+func (migration *ClusterBackupMigrationsModel) SetValuesFromRequest(req api.CreateMigrationRequest) error {
+
+	optionsJSON, err := json.Marshal(req.Options)
+	if err != nil {
+		return emperror.Wrap(err, "error converting options to json")
+	}
+
+	migration.SourceClusterID = req.SourceClusterID
+	migration.TargetClusterID = req.TargetClusterID
+	migration.BackupName = req.BackupName
+	migration.Options = optionsJSON
+	migration.Status = api.MigrationStatusPending
+
+	return nil
+}
+
+// SetStatusFromRequest set values from a PersistMigrationStatusRequest to the migration object
+func (migration *ClusterBackupMigrationsModel) SetStatusFromRequest(req api.PersistMigrationStatusRequest) error {
+
+	if req.Results != nil {
+		resultsJSON, err := json.Marshal(req.Results)
+		if err != nil {
+			return emperror.Wrap(err, "error converting results to json")
+		}
+		migration.Results = resultsJSON
+	}
+
+	if req.BucketID > 0 {
+		migration.BucketID = req.BucketID
+	}
+	if req.BackupName != "" {
+		migration.BackupName = req.BackupName
+	}
+	if req.RestoreName != "" {
+		migration.RestoreName = req.RestoreName
+	}
+
+	migration.Status = req.Status
+	migration.Step = req.Step
+	migration.StatusMessage = req.StatusMessage
+
+	return nil
+}
+
+// ConvertModelToEntity converts ClusterBackupMigrationsModel to api.Migration
+func (migration *ClusterBackupMigrationsModel) ConvertModelToEntity() *api.Migration {
+
+	return &api.Migration{
+		ID:              migration.ID,
+		SourceClusterID: migration.SourceClusterID,
+		TargetClusterID: migration.TargetClusterID,
+		BucketID:        migration.BucketID,
+		BackupName:      migration.BackupName,
+		RestoreName:     migration.RestoreName,
+		Options:         migration.GetOptions(),
+		Status:          migration.Status,
+		Step:            migration.Step,
+		StatusMessage:   migration.StatusMessage,
+		Results:         migration.GetResults(),
+		CreatedAt:       migration.CreatedAt,
+		UpdatedAt:       migration.UpdatedAt,
+	}
+}
+
+// GetOptions unmarshals the stored options JSON into api.MigrationOptions
+func (migration *ClusterBackupMigrationsModel) GetOptions() api.MigrationOptions {
+
+	var options api.MigrationOptions
+	_ = json.Unmarshal(migration.Options, &options)
+
+	return options
+}
+
+// GetResults unmarshals a stored result JSON into api.MigrationResults
+func (migration *ClusterBackupMigrationsModel) GetResults() *api.MigrationResults {
+
+	var results *api.MigrationResults
+	err := json.Unmarshal(migration.Results, &results)
+	if err != nil {
+		return nil
+	}
+
+	return results
+}
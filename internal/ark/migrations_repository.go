@@ -0,0 +1,95 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// MigrationsRepository is a repository for managing ARK migration models
+type MigrationsRepository struct {
+	org    *auth.Organization
+	db     *gorm.DB
+	logger logrus.FieldLogger
+}
+
+// NewMigrationsRepository creates and returns a MigrationsRepository instance
+func NewMigrationsRepository(org *auth.Organization, db *gorm.DB, logger logrus.FieldLogger) *MigrationsRepository {
+
+	return &MigrationsRepository{
+		org:    org,
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Find finds all ClusterBackupMigrationsModel
+func (r *MigrationsRepository) Find() ([]*ClusterBackupMigrationsModel, error) {
+	var migrations []*ClusterBackupMigrationsModel
+
+	query := ClusterBackupMigrationsModel{
+		OrganizationID: r.org.ID,
+	}
+
+	err := r.db.Where(&query).Order("id desc").Find(&migrations).Error
+
+	return migrations, err
+}
+
+// FindOneByID finds one ClusterBackupMigrationsModel by ID
+func (r *MigrationsRepository) FindOneByID(id uint) (*ClusterBackupMigrationsModel, error) {
+	var migration ClusterBackupMigrationsModel
+
+	query := ClusterBackupMigrationsModel{
+		ID:             id,
+		OrganizationID: r.org.ID,
+	}
+
+	err := r.db.Where(&query).First(&migration).Error
+
+	return &migration, err
+}
+
+// Create persists a new ClusterBackupMigrationsModel by a CreateMigrationRequest
+func (r *MigrationsRepository) Create(req api.CreateMigrationRequest) (*ClusterBackupMigrationsModel, error) {
+
+	migration := &ClusterBackupMigrationsModel{
+		OrganizationID: r.org.ID,
+	}
+
+	err := migration.SetValuesFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Create(migration).Error
+
+	return migration, err
+}
+
+// UpdateStatus updates the progress of a ClusterBackupMigrationsModel
+func (r *MigrationsRepository) UpdateStatus(migration *ClusterBackupMigrationsModel, req api.PersistMigrationStatusRequest) error {
+
+	err := migration.SetStatusFromRequest(req)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Save(migration).Error
+}
@@ -0,0 +1,117 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// MigrationsService is for managing cross-cluster backup and restore records
+type MigrationsService struct {
+	org        *auth.Organization
+	logger     logrus.FieldLogger
+	repository *MigrationsRepository
+}
+
+// MigrationsServiceFactory creates and returns an initialized MigrationsService instance
+func MigrationsServiceFactory(org *auth.Organization, db *gorm.DB, logger logrus.FieldLogger) *MigrationsService {
+
+	return NewMigrationsService(org, NewMigrationsRepository(org, db, logger), logger)
+}
+
+// NewMigrationsService creates and returns an initialized MigrationsService instance
+func NewMigrationsService(
+	org *auth.Organization,
+	repository *MigrationsRepository,
+	logger logrus.FieldLogger,
+) *MigrationsService {
+
+	return &MigrationsService{
+		org:        org,
+		logger:     logger,
+		repository: repository,
+	}
+}
+
+// GetModelByID gets a ClusterBackupMigrationsModel by ID
+func (s *MigrationsService) GetModelByID(id uint) (*ClusterBackupMigrationsModel, error) {
+
+	model, err := s.repository.FindOneByID(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get migration from database")
+	}
+
+	return model, nil
+}
+
+// GetByID gets a Migration by ID
+func (s *MigrationsService) GetByID(id uint) (*api.Migration, error) {
+
+	model, err := s.GetModelByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.ConvertModelToEntity(), nil
+}
+
+// List gets all migrations of the organization
+func (s *MigrationsService) List() ([]*api.Migration, error) {
+
+	migrations := make([]*api.Migration, 0)
+
+	items, err := s.repository.Find()
+	if err != nil {
+		return migrations, err
+	}
+
+	for _, item := range items {
+		migrations = append(migrations, item.ConvertModelToEntity())
+	}
+
+	return migrations, nil
+}
+
+// Create persists a pending migration by a CreateMigrationRequest
+func (s *MigrationsService) Create(req api.CreateMigrationRequest) (*api.Migration, error) {
+
+	model, err := s.repository.Create(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not persist migration")
+	}
+
+	return model.ConvertModelToEntity(), nil
+}
+
+// UpdateStatus persists the progress of a migration
+func (s *MigrationsService) UpdateStatus(id uint, req api.PersistMigrationStatusRequest) error {
+
+	model, err := s.GetModelByID(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repository.UpdateStatus(model, req)
+	if err != nil {
+		return errors.Wrap(err, "could not update migration status")
+	}
+
+	return nil
+}
@@ -28,6 +28,8 @@ const (
 	clusterBackupBucketsTableName     = "ark_backup_buckets"
 	clusterBackupDeploymentsTableName = "ark_deployments"
 	clusterBackupsTableName           = "ark_backups"
+	clusterBackupMigrationsTableName  = "ark_migrations"
+	clusterBackupPoliciesTableName    = "ark_backup_policies"
 )
 
 // Migrate executes the table migrations for Ark.
@@ -37,6 +39,8 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 		&ClusterBackupBucketsModel{},
 		&ClusterBackupRestoresModel{},
 		&ClusterBackupDeploymentsModel{},
+		&ClusterBackupMigrationsModel{},
+		&ClusterBackupPoliciesModel{},
 	}
 
 	var tableNames string
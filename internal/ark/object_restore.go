@@ -0,0 +1,521 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/gofrs/uuid"
+	arkAPI "github.com/heptio/ark/pkg/apis/ark/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+// nonRestorableResources are never restored by object level restores
+var nonRestorableResources = map[string]bool{
+	"nodes":                true,
+	"events":               true,
+	"events.events.k8s.io": true,
+	"persistentvolumes":    true,
+}
+
+var namespacesResource = schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+
+var servicesResource = schema.GroupVersionResource{Version: "v1", Resource: "services"}
+
+// createObjectRestore checks that the object selectors of the request match objects of the backup and
+// persists an in progress restore, the objects themselves are restored by RestoreObjects
+func (s *RestoresService) createObjectRestore(req api.CreateRestoreRequest, deployment *ClusterBackupDeploymentsModel) (
+	*api.Restore, error) {
+
+	bucket, err := s.buckets.GetByID(deployment.BucketID)
+	if err != nil {
+		return nil, emperror.Wrap(err, "error getting bucket")
+	}
+
+	objects, err := s.buckets.getBackupObjects(bucket, req.BackupName, req.Options.SelectsObject)
+	if err != nil {
+		return nil, emperror.Wrap(err, "error getting objects from backup")
+	}
+
+	if len(objects) == 0 {
+		return nil, errors.New("object selectors do not match any object in the backup")
+	}
+
+	restore, err := newObjectRestore(req)
+	if err != nil {
+		return nil, err
+	}
+
+	restoreItem, err := s.Persist(&api.PersistRestoreRequest{
+		BucketID:  deployment.BucketID,
+		ClusterID: s.deployments.GetCluster().GetID(),
+
+		Restore: restore,
+	})
+	if err != nil {
+		return nil, emperror.Wrap(err, "error persisting restore")
+	}
+
+	return restoreItem, nil
+}
+
+// RestoreObjects restores the objects selected by an in progress object level restore from the backup
+// contents stored in its bucket and persists the outcome
+func (s *RestoresService) RestoreObjects(id uint) error {
+
+	model, err := s.GetModelByID(id)
+	if err != nil {
+		return err
+	}
+
+	state := model.GetState()
+	if !isObjectRestore(state) {
+		return errors.Errorf("restore %q is not an object level restore", model.Name)
+	}
+
+	if state.Status.Phase != arkAPI.RestorePhaseInProgress {
+		s.logger.WithField("restore", model.Name).Debug("object level restore already finished")
+		return nil
+	}
+
+	results, err := s.restoreObjects(model.BucketID, model.ConvertModelToEntity())
+	if err != nil {
+		if persistErr := s.failObjectRestore(model, err); persistErr != nil {
+			s.logger.Error(persistErr.Error())
+		}
+
+		return err
+	}
+
+	state.Status.Phase = arkAPI.RestorePhaseCompleted
+	state.Status.Warnings = countRestoreResult(results.Warnings)
+	state.Status.Errors = countRestoreResult(results.Errors)
+
+	_, err = s.Persist(&api.PersistRestoreRequest{
+		BucketID:  model.BucketID,
+		ClusterID: model.ClusterID,
+
+		Restore: state,
+		Results: results,
+	})
+	if err != nil {
+		return emperror.Wrap(err, "error persisting restore")
+	}
+
+	return nil
+}
+
+// FailObjectRestore marks an in progress object level restore as failed
+func (s *RestoresService) FailObjectRestore(id uint, reason error) error {
+
+	model, err := s.GetModelByID(id)
+	if err != nil {
+		return err
+	}
+
+	if state := model.GetState(); !isObjectRestore(state) || state.Status.Phase != arkAPI.RestorePhaseInProgress {
+		return nil
+	}
+
+	return s.failObjectRestore(model, reason)
+}
+
+// failObjectRestore persists the failed phase of an object level restore with the reason of the failure
+func (s *RestoresService) failObjectRestore(model *ClusterBackupRestoresModel, reason error) error {
+
+	state := model.GetState()
+	state.Status.Phase = api.RestorePhaseFailed
+	state.Status.Errors = 1
+
+	_, err := s.Persist(&api.PersistRestoreRequest{
+		BucketID:  model.BucketID,
+		ClusterID: model.ClusterID,
+
+		Restore: state,
+		Results: &api.RestoreResults{
+			Errors: arkAPI.RestoreResult{
+				Ark: []string{reason.Error()},
+			},
+		},
+	})
+	if err != nil {
+		return emperror.Wrap(err, "error persisting restore")
+	}
+
+	return nil
+}
+
+func (s *RestoresService) restoreObjects(bucketID uint, restore *api.Restore) (*api.RestoreResults, error) {
+
+	bucket, err := s.buckets.GetByID(bucketID)
+	if err != nil {
+		return nil, emperror.Wrap(err, "error getting bucket")
+	}
+
+	objects, err := s.buckets.getBackupObjects(bucket, restore.BackupName, restore.Options.SelectsObject)
+	if err != nil {
+		return nil, emperror.Wrap(err, "error getting objects from backup")
+	}
+
+	restorer, err := s.getObjectRestorer(restore.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	return restorer.restore(objects), nil
+}
+
+// RestoreServicesAsClusterIP restores the services of a backup selected by the options as ClusterIP services,
+// so that the restored services do not allocate node ports or load balancers and do not receive external traffic
+func (s *RestoresService) RestoreServicesAsClusterIP(bucket *api.Bucket, backupName string, options api.RestoreOptions) (
+	*api.RestoreResults, error) {
+
+	objects, err := s.buckets.getBackupObjects(bucket, backupName, func(namespace, resource, name string) bool {
+		return resource == servicesResource.Resource && options.SelectsObject(namespace, resource, name)
+	})
+	if err != nil {
+		return nil, emperror.Wrap(err, "error getting services from backup")
+	}
+
+	restorer, err := s.getObjectRestorer(options)
+	if err != nil {
+		return nil, err
+	}
+	restorer.clusterIPServices = true
+
+	return restorer.restore(objects), nil
+}
+
+// getObjectRestorer returns an object restorer for the cluster of the service
+func (s *RestoresService) getObjectRestorer(options api.RestoreOptions) (*objectRestorer, error) {
+
+	kubeConfig, err := s.deployments.GetCluster().GetK8sConfig()
+	if err != nil {
+		return nil, emperror.Wrap(err, "error getting k8s config")
+	}
+
+	config, err := k8sclient.NewClientConfig(kubeConfig)
+	if err != nil {
+		return nil, emperror.Wrap(err, "error creating k8s client config")
+	}
+
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, emperror.Wrap(err, "error creating k8s client")
+	}
+
+	return newObjectRestorer(client, options, s.logger), nil
+}
+
+// newObjectRestore creates an in progress ARK restore state for an object level restore
+func newObjectRestore(req api.CreateRestoreRequest) (*arkAPI.Restore, error) {
+
+	selectors, err := json.Marshal(api.ObjectSelectors{
+		IncludedObjects: req.Options.IncludedObjects,
+		ExcludedObjects: req.Options.ExcludedObjects,
+	})
+	if err != nil {
+		return nil, emperror.Wrap(err, "error converting object selectors to json")
+	}
+
+	restoreLabels := labels.Merge(req.Labels, labels.Set{
+		api.LabelKeyRestoreType: api.RestoreTypeObjects,
+	})
+
+	return &arkAPI.Restore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("%s-%s", req.BackupName, time.Now().Format("20060102150405")),
+			UID:               types.UID(uuid.Must(uuid.NewV4()).String()),
+			Labels:            restoreLabels,
+			CreationTimestamp: metav1.Now(),
+			Annotations: map[string]string{
+				api.AnnotationKeyObjectSelectors: string(selectors),
+			},
+		},
+		Spec: arkAPI.RestoreSpec{
+			BackupName:              req.BackupName,
+			IncludedNamespaces:      req.Options.IncludedNamespaces,
+			IncludedResources:       req.Options.IncludedResources,
+			ExcludedNamespaces:      req.Options.ExcludedNamespaces,
+			ExcludedResources:       req.Options.ExcludedResources,
+			IncludeClusterResources: req.Options.IncludeClusterResources,
+			LabelSelector:           req.Options.LabelSelector,
+			NamespaceMapping:        req.Options.NamespaceMapping,
+			RestorePVs:              req.Options.RestorePVs,
+		},
+		Status: arkAPI.RestoreStatus{
+			Phase: arkAPI.RestorePhaseInProgress,
+		},
+	}, nil
+}
+
+// isObjectRestore returns true if the restore was executed by Pipeline instead of ARK
+func isObjectRestore(restore *arkAPI.Restore) bool {
+	return restore != nil && restore.Labels[api.LabelKeyRestoreType] == api.RestoreTypeObjects
+}
+
+// getObjectSelectors gets the object selectors stored on an object level restore
+func getObjectSelectors(restore *arkAPI.Restore) (selectors api.ObjectSelectors) {
+	if !isObjectRestore(restore) {
+		return
+	}
+
+	_ = json.Unmarshal([]byte(restore.Annotations[api.AnnotationKeyObjectSelectors]), &selectors)
+
+	return
+}
+
+func countRestoreResult(result arkAPI.RestoreResult) int {
+	count := len(result.Ark) + len(result.Cluster)
+	for _, messages := range result.Namespaces {
+		count += len(messages)
+	}
+
+	return count
+}
+
+// objectRestorer creates backed up objects in a cluster
+type objectRestorer struct {
+	client  dynamic.Interface
+	options api.RestoreOptions
+	logger  logrus.FieldLogger
+
+	// clusterIPServices specifies whether services are restored as ClusterIP services
+	clusterIPServices bool
+
+	namespaces map[string]bool
+	results    *api.RestoreResults
+}
+
+func newObjectRestorer(client dynamic.Interface, options api.RestoreOptions, logger logrus.FieldLogger) *objectRestorer {
+	return &objectRestorer{
+		client:     client,
+		options:    options,
+		logger:     logger,
+		namespaces: make(map[string]bool),
+		results: &api.RestoreResults{
+			Errors: arkAPI.RestoreResult{
+				Namespaces: make(map[string][]string),
+			},
+			Warnings: arkAPI.RestoreResult{
+				Namespaces: make(map[string][]string),
+			},
+		},
+	}
+}
+
+// restore restores the given objects and collects the errors and warnings
+func (r *objectRestorer) restore(objects []backupObject) *api.RestoreResults {
+
+	for _, object := range objects {
+		namespace := r.targetNamespace(object.Namespace)
+		log := r.logger.WithFields(logrus.Fields{
+			"namespace": namespace,
+			"resource":  object.Resource,
+			"name":      object.Name,
+		})
+
+		restored, err := r.restoreObject(namespace, object)
+		if k8serrors.IsAlreadyExists(err) {
+			log.Debug("object already exists")
+			addRestoreResult(&r.results.Warnings, namespace, fmt.Sprintf("not restored: %s %q already exists", object.Resource, object.Name))
+			continue
+		}
+		if err != nil {
+			log.Warn(err.Error())
+			addRestoreResult(&r.results.Errors, namespace, fmt.Sprintf("error restoring %s %q: %s", object.Resource, object.Name, err.Error()))
+			continue
+		}
+		if !restored {
+			log.Debug("object skipped")
+			continue
+		}
+
+		log.Info("object restored")
+	}
+
+	return r.results
+}
+
+func (r *objectRestorer) restoreObject(namespace string, object backupObject) (bool, error) {
+
+	if nonRestorableResources[object.Resource] || strings.HasSuffix(object.Resource, ".ark.heptio.com") {
+		addRestoreResult(&r.results.Warnings, namespace, fmt.Sprintf("not restored: %s are not restorable at object level", object.Resource))
+		return false, nil
+	}
+
+	obj := new(unstructured.Unstructured)
+	err := obj.UnmarshalJSON(object.Data)
+	if err != nil {
+		return false, emperror.Wrap(err, "could not decode object")
+	}
+
+	if r.options.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(r.options.LabelSelector)
+		if err != nil {
+			return false, emperror.Wrap(err, "invalid label selector")
+		}
+		if !selector.Matches(labels.Set(obj.GetLabels())) {
+			return false, nil
+		}
+	}
+
+	gv, err := schema.ParseGroupVersion(obj.GetAPIVersion())
+	if err != nil {
+		return false, emperror.Wrap(err, "could not parse api version")
+	}
+	gvr := gv.WithResource(strings.SplitN(object.Resource, ".", 2)[0])
+
+	resetObject(obj, namespace)
+
+	if r.clusterIPServices && obj.GetKind() == "Service" {
+		resetServiceToClusterIP(obj)
+	}
+
+	if namespace == "" {
+		_, err = r.client.Resource(gvr).Create(obj, metav1.CreateOptions{})
+		return err == nil, err
+	}
+
+	err = r.ensureNamespace(namespace)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = r.client.Resource(gvr).Namespace(namespace).Create(obj, metav1.CreateOptions{})
+
+	return err == nil, err
+}
+
+// ensureNamespace creates the target namespace of the restored objects if it does not exist
+func (r *objectRestorer) ensureNamespace(name string) error {
+
+	if r.namespaces[name] {
+		return nil
+	}
+
+	namespace := new(unstructured.Unstructured)
+	namespace.SetAPIVersion("v1")
+	namespace.SetKind("Namespace")
+	namespace.SetName(name)
+
+	_, err := r.client.Resource(namespacesResource).Create(namespace, metav1.CreateOptions{})
+	if err != nil && !k8serrors.IsAlreadyExists(err) {
+		return emperror.WrapWith(err, "could not create namespace", "namespace", name)
+	}
+
+	r.namespaces[name] = true
+
+	return nil
+}
+
+func (r *objectRestorer) targetNamespace(namespace string) string {
+	if target, ok := r.options.NamespaceMapping[namespace]; ok && namespace != "" {
+		return target
+	}
+
+	return namespace
+}
+
+// resetObject removes the cluster specific fields of a backed up object the same way ARK does
+func resetObject(obj *unstructured.Unstructured, namespace string) {
+
+	metadata := metav1.ObjectMeta{
+		Name:        obj.GetName(),
+		Namespace:   namespace,
+		Labels:      obj.GetLabels(),
+		Annotations: obj.GetAnnotations(),
+	}
+
+	unstructured.RemoveNestedField(obj.Object, "metadata")
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	obj.SetName(metadata.Name)
+	obj.SetNamespace(metadata.Namespace)
+	obj.SetLabels(metadata.Labels)
+	obj.SetAnnotations(metadata.Annotations)
+
+	switch obj.GetKind() {
+	case "Service":
+		if clusterIP, _, _ := unstructured.NestedString(obj.Object, "spec", "clusterIP"); clusterIP != "None" {
+			unstructured.RemoveNestedField(obj.Object, "spec", "clusterIP")
+		}
+	case "PersistentVolumeClaim":
+		// volumes are not restored at object level, let the claim bind to a new volume
+		unstructured.RemoveNestedField(obj.Object, "spec", "volumeName")
+		annotations := obj.GetAnnotations()
+		delete(annotations, "pv.kubernetes.io/bind-completed")
+		delete(annotations, "pv.kubernetes.io/bound-by-controller")
+		obj.SetAnnotations(annotations)
+	}
+}
+
+// resetServiceToClusterIP turns a backed up service into a ClusterIP service,
+// external name services are kept as they do not expose anything
+func resetServiceToClusterIP(obj *unstructured.Unstructured) {
+
+	if serviceType, _, _ := unstructured.NestedString(obj.Object, "spec", "type"); serviceType == "ExternalName" {
+		return
+	}
+
+	_ = unstructured.SetNestedField(obj.Object, "ClusterIP", "spec", "type")
+
+	for _, field := range []string{
+		"externalIPs",
+		"externalTrafficPolicy",
+		"healthCheckNodePort",
+		"loadBalancerIP",
+		"loadBalancerSourceRanges",
+	} {
+		unstructured.RemoveNestedField(obj.Object, "spec", field)
+	}
+
+	ports, found, err := unstructured.NestedSlice(obj.Object, "spec", "ports")
+	if err != nil || !found {
+		return
+	}
+
+	for _, port := range ports {
+		if port, ok := port.(map[string]interface{}); ok {
+			delete(port, "nodePort")
+		}
+	}
+
+	_ = unstructured.SetNestedSlice(obj.Object, ports, "spec", "ports")
+}
+
+func addRestoreResult(result *arkAPI.RestoreResult, namespace, message string) {
+	if namespace == "" {
+		result.Cluster = append(result.Cluster, message)
+		return
+	}
+
+	result.Namespaces[namespace] = append(result.Namespaces[namespace], message)
+}
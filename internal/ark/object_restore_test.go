@@ -0,0 +1,76 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestResetServiceToClusterIP(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name": "web",
+			},
+			"spec": map[string]interface{}{
+				"type":                     "LoadBalancer",
+				"externalTrafficPolicy":    "Local",
+				"healthCheckNodePort":      int64(30001),
+				"loadBalancerIP":           "10.0.0.1",
+				"loadBalancerSourceRanges": []interface{}{"10.0.0.0/8"},
+				"selector":                 map[string]interface{}{"app": "web"},
+				"ports": []interface{}{
+					map[string]interface{}{"port": int64(80), "nodePort": int64(30080)},
+				},
+			},
+		},
+	}
+
+	resetServiceToClusterIP(obj)
+
+	assert.Equal(t, map[string]interface{}{
+		"type":     "ClusterIP",
+		"selector": map[string]interface{}{"app": "web"},
+		"ports": []interface{}{
+			map[string]interface{}{"port": int64(80)},
+		},
+	}, obj.Object["spec"])
+}
+
+func TestResetServiceToClusterIP_ExternalName(t *testing.T) {
+	spec := map[string]interface{}{
+		"type":         "ExternalName",
+		"externalName": "db.example.com",
+	}
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"spec":       spec,
+		},
+	}
+
+	resetServiceToClusterIP(obj)
+
+	assert.Equal(t, map[string]interface{}{
+		"type":         "ExternalName",
+		"externalName": "db.example.com",
+	}, obj.Object["spec"])
+}
@@ -0,0 +1,98 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"encoding/json"
+	"time"
+
+	"emperror.dev/emperror"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// ClusterBackupPoliciesModel describes an organization level backup policy
+type ClusterBackupPoliciesModel struct {
+	ID uint `gorm:"primary_key"`
+
+	Name     string `gorm:"unique_index:idx_ark_backup_policies_org_name"`
+	Schedule string
+	TTL      string
+	RPO      string
+
+	Selector []byte `sql:"type:json"`
+	Bucket   []byte `sql:"type:json"`
+	Options  []byte `sql:"type:json"`
+
+	Organization   auth.Organization `gorm:"foreignkey:OrganizationID"`
+	OrganizationID uint              `gorm:"unique_index:idx_ark_backup_policies_org_name;not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name
+func (ClusterBackupPoliciesModel) TableName() string {
+	return clusterBackupPoliciesTableName
+}
+
+// SetValuesFromRequest set values from a CreateBackupPolicyRequest to the policy object
+func (policy *ClusterBackupPoliciesModel) SetValuesFromRequest(req api.CreateBackupPolicyRequest) error {
+
+	selectorJSON, err := json.Marshal(req.Selector)
+	if err != nil {
+		return emperror.Wrap(err, "error converting selector to json")
+	}
+
+	bucketJSON, err := json.Marshal(req.Bucket)
+	if err != nil {
+		return emperror.Wrap(err, "error converting bucket to json")
+	}
+
+	optionsJSON, err := json.Marshal(req.Options)
+	if err != nil {
+		return emperror.Wrap(err, "error converting options to json")
+	}
+
+	policy.Name = req.Name
+	policy.Schedule = req.Schedule
+	policy.TTL = req.TTL
+	policy.RPO = req.RPO
+	policy.Selector = selectorJSON
+	policy.Bucket = bucketJSON
+	policy.Options = optionsJSON
+
+	return nil
+}
+
+// ConvertModelToEntity converts ClusterBackupPoliciesModel to api.BackupPolicy
+func (policy *ClusterBackupPoliciesModel) ConvertModelToEntity() *api.BackupPolicy {
+
+	item := &api.BackupPolicy{
+		ID:        policy.ID,
+		Name:      policy.Name,
+		Schedule:  policy.Schedule,
+		TTL:       policy.TTL,
+		RPO:       policy.RPO,
+		CreatedAt: policy.CreatedAt,
+	}
+
+	_ = json.Unmarshal(policy.Selector, &item.Selector)
+	_ = json.Unmarshal(policy.Bucket, &item.Bucket)
+	_ = json.Unmarshal(policy.Options, &item.Options)
+
+	return item
+}
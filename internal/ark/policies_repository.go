@@ -0,0 +1,104 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// PoliciesRepository is a repository for managing ARK backup policy models
+type PoliciesRepository struct {
+	org    *auth.Organization
+	db     *gorm.DB
+	logger logrus.FieldLogger
+}
+
+// NewPoliciesRepository creates and returns a PoliciesRepository instance
+func NewPoliciesRepository(org *auth.Organization, db *gorm.DB, logger logrus.FieldLogger) *PoliciesRepository {
+
+	return &PoliciesRepository{
+		org:    org,
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Find finds all ClusterBackupPoliciesModel
+func (r *PoliciesRepository) Find() ([]*ClusterBackupPoliciesModel, error) {
+	var policies []*ClusterBackupPoliciesModel
+
+	query := ClusterBackupPoliciesModel{
+		OrganizationID: r.org.ID,
+	}
+
+	err := r.db.Where(&query).Order("id").Find(&policies).Error
+
+	return policies, err
+}
+
+// FindOneByID finds one ClusterBackupPoliciesModel by ID
+func (r *PoliciesRepository) FindOneByID(id uint) (*ClusterBackupPoliciesModel, error) {
+	var policy ClusterBackupPoliciesModel
+
+	query := ClusterBackupPoliciesModel{
+		ID:             id,
+		OrganizationID: r.org.ID,
+	}
+
+	err := r.db.Where(&query).First(&policy).Error
+
+	return &policy, err
+}
+
+// FindOneByName finds one ClusterBackupPoliciesModel by name
+func (r *PoliciesRepository) FindOneByName(name string) (*ClusterBackupPoliciesModel, error) {
+	var policy ClusterBackupPoliciesModel
+
+	query := ClusterBackupPoliciesModel{
+		Name:           name,
+		OrganizationID: r.org.ID,
+	}
+
+	err := r.db.Where(&query).First(&policy).Error
+
+	return &policy, err
+}
+
+// Create persists a new ClusterBackupPoliciesModel by a CreateBackupPolicyRequest
+func (r *PoliciesRepository) Create(req api.CreateBackupPolicyRequest) (*ClusterBackupPoliciesModel, error) {
+
+	policy := &ClusterBackupPoliciesModel{
+		OrganizationID: r.org.ID,
+	}
+
+	err := policy.SetValuesFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Create(policy).Error
+
+	return policy, err
+}
+
+// Delete deletes a ClusterBackupPoliciesModel
+func (r *PoliciesRepository) Delete(policy *ClusterBackupPoliciesModel) error {
+
+	return r.db.Delete(policy).Error
+}
@@ -0,0 +1,527 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/internal/providers"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// arkScheduleLabelKey is the label ARK sets on the backups taken by a schedule
+const arkScheduleLabelKey = "ark-schedule"
+
+// PoliciesService is for managing organization level backup policies
+type PoliciesService struct {
+	org        *auth.Organization
+	db         *gorm.DB
+	repository *PoliciesRepository
+	backups    *BackupsRepository
+	buckets    *BucketsService
+	logger     logrus.FieldLogger
+}
+
+// PoliciesServiceFactory creates and returns an initialized PoliciesService instance
+func PoliciesServiceFactory(org *auth.Organization, db *gorm.DB, logger logrus.FieldLogger) *PoliciesService {
+
+	return NewPoliciesService(
+		org,
+		db,
+		NewPoliciesRepository(org, db, logger),
+		NewBackupsRepository(org, db, logger),
+		BucketsServiceFactory(org, db, logger),
+		logger,
+	)
+}
+
+// NewPoliciesService creates and returns an initialized PoliciesService instance
+func NewPoliciesService(
+	org *auth.Organization,
+	db *gorm.DB,
+	repository *PoliciesRepository,
+	backups *BackupsRepository,
+	buckets *BucketsService,
+	logger logrus.FieldLogger,
+) *PoliciesService {
+
+	return &PoliciesService{
+		org:        org,
+		db:         db,
+		repository: repository,
+		backups:    backups,
+		buckets:    buckets,
+		logger:     logger,
+	}
+}
+
+// GetByID gets a BackupPolicy by ID
+func (s *PoliciesService) GetByID(id uint) (*api.BackupPolicy, error) {
+
+	model, err := s.repository.FindOneByID(id)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get policy from database")
+	}
+
+	return model.ConvertModelToEntity(), nil
+}
+
+// List gets all backup policies of the organization
+func (s *PoliciesService) List() ([]*api.BackupPolicy, error) {
+
+	policies := make([]*api.BackupPolicy, 0)
+
+	items, err := s.repository.Find()
+	if err != nil {
+		return policies, err
+	}
+
+	for _, item := range items {
+		policies = append(policies, item.ConvertModelToEntity())
+	}
+
+	return policies, nil
+}
+
+// Create persists a backup policy by a CreateBackupPolicyRequest
+func (s *PoliciesService) Create(req api.CreateBackupPolicyRequest) (*api.BackupPolicy, error) {
+
+	_, err := s.repository.FindOneByName(req.Name)
+	if err == nil {
+		return nil, errors.New("policy already exists")
+	}
+	if !gorm.IsRecordNotFoundError(err) {
+		return nil, errors.Wrap(err, "could not get policy from database")
+	}
+
+	model, err := s.repository.Create(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not persist policy")
+	}
+
+	return model.ConvertModelToEntity(), nil
+}
+
+// DeleteByID deletes a backup policy by ID, the schedules of the policy are removed by the next reconciliation
+func (s *PoliciesService) DeleteByID(id uint) error {
+
+	model, err := s.repository.FindOneByID(id)
+	if err != nil {
+		return errors.Wrap(err, "could not get policy from database")
+	}
+
+	return s.repository.Delete(model)
+}
+
+// policyCluster is a cluster with the backup policies applying to it
+type policyCluster struct {
+	cluster  api.Cluster
+	policies []*api.BackupPolicy
+}
+
+// getPolicyClusters gets the running clusters of the organization with the backup policies applying to them
+func (s *PoliciesService) getPolicyClusters(ctx context.Context, clusterManager api.ClusterManager) (
+	[]policyCluster, error) {
+
+	policies, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := clusterManager.GetClusters(ctx, s.org.ID)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get clusters")
+	}
+
+	items := make([]policyCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		status, err := cluster.GetStatus()
+		if err != nil || status.Status != pkgCluster.Running {
+			continue
+		}
+
+		clusterLabels, err := clusterManager.GetClusterLabels(ctx, cluster.GetID())
+		if err != nil {
+			return nil, emperror.WrapWith(err, "could not get cluster labels", "clusterID", cluster.GetID())
+		}
+
+		item := policyCluster{
+			cluster: cluster,
+		}
+		for _, policy := range policies {
+			if policy.Selector.Matches(cluster, clusterLabels) {
+				item.policies = append(item.policies, policy)
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// Reconcile deploys the backup service and creates the missing schedules on the clusters matching a policy,
+// and removes the schedules of policies which do not apply to a cluster anymore
+func (s *PoliciesService) Reconcile(ctx context.Context, clusterManager api.ClusterManager) error {
+
+	items, err := s.getPolicyClusters(ctx, clusterManager)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		err = s.reconcileCluster(item.cluster, item.policies)
+		if err != nil {
+			s.logger.WithField("clusterID", item.cluster.GetID()).Error(emperror.Wrap(err, "could not reconcile backup policies"))
+		}
+	}
+
+	return nil
+}
+
+func (s *PoliciesService) reconcileCluster(cluster api.Cluster, policies []*api.BackupPolicy) error {
+
+	log := s.logger.WithField("clusterID", cluster.GetID())
+	deployments := DeploymentsServiceFactory(s.org, cluster, s.db, log)
+
+	// the backup service of a cluster stores every backup in a single bucket, so it is deployed
+	// with the bucket of the first policy, the schedules of the policies with other buckets are not created
+	var bucket *api.Bucket
+
+	deployment, err := deployments.GetActiveDeployment()
+	if gorm.IsRecordNotFoundError(err) {
+		if len(policies) == 0 {
+			return nil
+		}
+
+		log.WithField("policy", policies[0].Name).Info("deploying backup service required by policy")
+
+		bucketModel, err := s.ensureClusterBucket(policies[0], cluster)
+		if err != nil {
+			return err
+		}
+
+		err = deployments.Deploy(bucketModel, false)
+		if err != nil {
+			return emperror.Wrap(err, "could not deploy backup service")
+		}
+
+		bucket = bucketModel.ConvertModelToEntity()
+	} else if err != nil {
+		return emperror.Wrap(err, "could not get active deployment")
+	} else if deployment.RestoreMode {
+		return nil
+	} else {
+		bucket, err = s.buckets.GetByID(deployment.BucketID)
+		if err != nil {
+			return emperror.Wrap(err, "could not get bucket of backup service")
+		}
+	}
+
+	schedulesSvc := SchedulesServiceFactory(deployments, log)
+	schedules, err := schedulesSvc.List()
+	if err != nil {
+		return emperror.Wrap(err, "could not list schedules")
+	}
+
+	existing := make(map[string]bool, len(schedules))
+	for _, schedule := range schedules {
+		existing[schedule.Name] = true
+	}
+
+	expected := make(map[string]bool, len(policies))
+	for _, policy := range policies {
+		if !usesPolicyBucket(policy, cluster.GetName(), bucket) {
+			log.WithFields(logrus.Fields{
+				"policy": policy.Name,
+				"bucket": bucket.Name,
+			}).Warn("backup service does not use the bucket of the policy, skip creating schedule")
+			continue
+		}
+
+		name := policy.ScheduleName()
+		expected[name] = true
+		if existing[name] {
+			continue
+		}
+
+		log.WithField("policy", policy.Name).Info("creating schedule required by policy")
+
+		err = schedulesSvc.Create(newPolicyBackupRequest(policy, cluster), policy.Schedule)
+		if err != nil {
+			return emperror.WrapWith(err, "could not create schedule", "policy", policy.Name)
+		}
+	}
+
+	for _, schedule := range schedules {
+		if schedule.Labels[api.LabelKeyBackupPolicy] == "" || expected[schedule.Name] {
+			continue
+		}
+
+		log.WithField("schedule", schedule.Name).Info("removing schedule of a policy which does not apply anymore")
+
+		err = schedulesSvc.DeleteByName(schedule.Name)
+		if err != nil {
+			return emperror.WrapWith(err, "could not delete schedule", "schedule", schedule.Name)
+		}
+	}
+
+	return nil
+}
+
+// usesPolicyBucket returns true if the backups stored in a bucket comply with the bucket of a policy
+func usesPolicyBucket(policy *api.BackupPolicy, clusterName string, bucket *api.Bucket) bool {
+	return bucket.Cloud == policy.Bucket.Cloud && bucket.Name == policy.Bucket.ClusterBucketName(clusterName)
+}
+
+// isPolicyBackup returns true if a backup was taken by the schedule of a policy or labeled with the policy
+func isPolicyBackup(policy *api.BackupPolicy, backupLabels map[string]string) bool {
+	return backupLabels[api.LabelKeyBackupPolicy] == policy.Name ||
+		backupLabels[arkScheduleLabelKey] == policy.ScheduleName()
+}
+
+// newPolicyBackupRequest creates the backup request of the schedule created by a policy
+func newPolicyBackupRequest(policy *api.BackupPolicy, cluster api.Cluster) *api.CreateBackupRequest {
+
+	// the TTL is validated when the policy is created
+	ttl, _ := time.ParseDuration(policy.TTL)
+
+	return &api.CreateBackupRequest{
+		Name: policy.ScheduleName(),
+		TTL: metav1.Duration{
+			Duration: ttl,
+		},
+		Labels: labels.Set{
+			api.LabelKeyBackupPolicy: policy.Name,
+			api.LabelKeyCloud:        cluster.GetCloud(),
+			api.LabelKeyDistribution: cluster.GetDistribution(),
+		},
+		Options: policy.Options,
+	}
+}
+
+// ensureClusterBucket creates the dedicated bucket of a cluster matching a policy if it does not exist yet
+func (s *PoliciesService) ensureClusterBucket(policy *api.BackupPolicy, cluster api.Cluster) (
+	*ClusterBackupBucketsModel, error) {
+
+	req := &api.CreateBucketRequest{
+		Cloud:                 policy.Bucket.Cloud,
+		BucketName:            policy.Bucket.ClusterBucketName(cluster.GetName()),
+		SecretID:              policy.Bucket.SecretID,
+		Location:              policy.Bucket.Location,
+		AzureBucketProperties: policy.Bucket.AzureBucketProperties,
+	}
+
+	secret, err := GetSecretWithValidation(req.SecretID, s.org.ID, req.Cloud)
+	if err != nil {
+		return nil, err
+	}
+
+	objectStore, err := providers.NewObjectStore(&providers.ObjectStoreContext{
+		Provider:       req.Cloud,
+		Secret:         secret,
+		Organization:   s.org,
+		Location:       req.Location,
+		ResourceGroup:  req.ResourceGroup,
+		StorageAccount: req.StorageAccount,
+	}, s.logger)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not create object store client")
+	}
+
+	if objectStore.CheckBucket(req.BucketName) != nil {
+		err = objectStore.CreateBucket(req.BucketName)
+		if err != nil {
+			return nil, emperror.WrapWith(err, "could not create bucket", "bucket", req.BucketName)
+		}
+	}
+
+	bucket, err := s.buckets.FindOrCreateBucket(req)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not persist bucket", "bucket", req.BucketName)
+	}
+
+	err = s.buckets.IsBucketInUse(bucket)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not use bucket", "bucket", req.BucketName)
+	}
+
+	return bucket, nil
+}
+
+// clusterBackupState describes the backup related state of a cluster checked by the compliance report
+type clusterBackupState struct {
+	clusterName  string
+	deployed     bool
+	bucket       *api.Bucket
+	schedules    map[string]*api.Schedule
+	schedulesErr error
+
+	// lastSuccessfulBackups are the completion times of the last successful backups by policy name
+	lastSuccessfulBackups map[string]time.Time
+}
+
+// lastSuccessfulBackup returns the completion time of the last successful backup of a policy
+func (s clusterBackupState) lastSuccessfulBackup(policy *api.BackupPolicy) *time.Time {
+	completedAt, ok := s.lastSuccessfulBackups[policy.Name]
+	if !ok {
+		return nil
+	}
+
+	return &completedAt
+}
+
+// GetComplianceReport checks every cluster matching a policy and lists the ones which are out of compliance
+func (s *PoliciesService) GetComplianceReport(ctx context.Context, clusterManager api.ClusterManager) (
+	*api.BackupPolicyComplianceReport, error) {
+
+	items, err := s.getPolicyClusters(ctx, clusterManager)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &api.BackupPolicyComplianceReport{
+		GeneratedAt:          time.Now(),
+		NonCompliantClusters: make([]api.ClusterComplianceViolation, 0),
+	}
+
+	for _, item := range items {
+		if len(item.policies) == 0 {
+			continue
+		}
+
+		report.CheckedClusters++
+
+		state, err := s.getClusterBackupState(item.cluster, item.policies)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, policy := range item.policies {
+			reasons := checkPolicyCompliance(policy, state, report.GeneratedAt)
+			if len(reasons) == 0 {
+				continue
+			}
+
+			report.NonCompliantClusters = append(report.NonCompliantClusters, api.ClusterComplianceViolation{
+				PolicyID:             policy.ID,
+				PolicyName:           policy.Name,
+				ClusterID:            item.cluster.GetID(),
+				ClusterName:          item.cluster.GetName(),
+				LastSuccessfulBackup: state.lastSuccessfulBackup(policy),
+				Reasons:              reasons,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+func (s *PoliciesService) getClusterBackupState(cluster api.Cluster, policies []*api.BackupPolicy) (clusterBackupState, error) {
+
+	state := clusterBackupState{
+		clusterName:           cluster.GetName(),
+		lastSuccessfulBackups: make(map[string]time.Time, len(policies)),
+	}
+
+	backups, err := s.backups.FindCompletedByClusterID(cluster.GetID())
+	if err != nil {
+		return state, emperror.WrapWith(err, "could not get completed backups", "clusterID", cluster.GetID())
+	}
+
+	// the backups are ordered by completion time, so the first backup of a policy is the last one
+	for _, backup := range backups {
+		backupState := backup.GetStateObject()
+		if backupState == nil || backup.CompletedAt == nil {
+			continue
+		}
+
+		for _, policy := range policies {
+			if _, ok := state.lastSuccessfulBackups[policy.Name]; !ok && isPolicyBackup(policy, backupState.Labels) {
+				state.lastSuccessfulBackups[policy.Name] = *backup.CompletedAt
+			}
+		}
+	}
+
+	deployments := DeploymentsServiceFactory(s.org, cluster, s.db, s.logger)
+	deployment, err := deployments.GetActiveDeployment()
+	if gorm.IsRecordNotFoundError(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, emperror.WrapWith(err, "could not get active deployment", "clusterID", cluster.GetID())
+	}
+
+	state.deployed = true
+
+	state.bucket, err = s.buckets.GetByID(deployment.BucketID)
+	if err != nil {
+		return state, emperror.WrapWith(err, "could not get bucket of backup service", "clusterID", cluster.GetID())
+	}
+
+	schedules, err := SchedulesServiceFactory(deployments, s.logger).List()
+	if err != nil {
+		state.schedulesErr = err
+		return state, nil
+	}
+
+	state.schedules = make(map[string]*api.Schedule, len(schedules))
+	for _, schedule := range schedules {
+		state.schedules[schedule.Name] = schedule
+	}
+
+	return state, nil
+}
+
+// checkPolicyCompliance returns the reasons why a cluster in the given state is out of compliance with a policy
+func checkPolicyCompliance(policy *api.BackupPolicy, state clusterBackupState, now time.Time) []string {
+
+	var reasons []string
+
+	if !state.deployed {
+		reasons = append(reasons, "backup service is not deployed")
+	} else if !usesPolicyBucket(policy, state.clusterName, state.bucket) {
+		reasons = append(reasons, fmt.Sprintf("backup service uses bucket %s instead of %s",
+			state.bucket.Name, policy.Bucket.ClusterBucketName(state.clusterName)))
+	} else if state.schedulesErr != nil {
+		reasons = append(reasons, fmt.Sprintf("could not check schedule: %s", state.schedulesErr.Error()))
+	} else if schedule, ok := state.schedules[policy.ScheduleName()]; !ok {
+		reasons = append(reasons, fmt.Sprintf("schedule %s does not exist", policy.ScheduleName()))
+	} else if len(schedule.ValidationErrors) > 0 {
+		reasons = append(reasons, fmt.Sprintf("schedule %s is invalid: %s", schedule.Name, strings.Join(schedule.ValidationErrors, ", ")))
+	}
+
+	// the RPO is validated when the policy is created
+	rpo, _ := time.ParseDuration(policy.RPO)
+	if lastSuccessfulBackup := state.lastSuccessfulBackup(policy); lastSuccessfulBackup == nil {
+		reasons = append(reasons, "no successful backup")
+	} else if now.Sub(*lastSuccessfulBackup) > rpo {
+		reasons = append(reasons, fmt.Sprintf("no successful backup within the RPO of %s", policy.RPO))
+	}
+
+	return reasons
+}
@@ -0,0 +1,154 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ark
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+func TestCheckPolicyCompliance(t *testing.T) {
+	now := time.Date(2019, 11, 20, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-2 * time.Hour)
+	old := now.Add(-48 * time.Hour)
+
+	policy := &api.BackupPolicy{
+		Name: "production",
+		RPO:  "12h",
+		Bucket: api.PolicyBucket{
+			Cloud:      "amazon",
+			BucketName: "backups",
+		},
+	}
+
+	bucket := &api.Bucket{Cloud: "amazon", Name: "backups-shop"}
+	recentBackups := map[string]time.Time{"production": recent}
+
+	schedules := map[string]*api.Schedule{
+		"policy-production": {Name: "policy-production"},
+	}
+
+	tests := []struct {
+		name    string
+		state   clusterBackupState
+		reasons []string
+	}{
+		{
+			name: "compliant",
+			state: clusterBackupState{
+				clusterName:           "shop",
+				deployed:              true,
+				bucket:                bucket,
+				schedules:             schedules,
+				lastSuccessfulBackups: recentBackups,
+			},
+		},
+		{
+			name:    "not deployed",
+			state:   clusterBackupState{},
+			reasons: []string{"backup service is not deployed", "no successful backup"},
+		},
+		{
+			name: "missing schedule",
+			state: clusterBackupState{
+				clusterName:           "shop",
+				deployed:              true,
+				bucket:                bucket,
+				schedules:             map[string]*api.Schedule{},
+				lastSuccessfulBackups: recentBackups,
+			},
+			reasons: []string{"schedule policy-production does not exist"},
+		},
+		{
+			name: "invalid schedule",
+			state: clusterBackupState{
+				clusterName: "shop",
+				deployed:    true,
+				bucket:      bucket,
+				schedules: map[string]*api.Schedule{
+					"policy-production": {Name: "policy-production", ValidationErrors: []string{"invalid schedule"}},
+				},
+				lastSuccessfulBackups: recentBackups,
+			},
+			reasons: []string{"schedule policy-production is invalid: invalid schedule"},
+		},
+		{
+			name: "schedules unavailable",
+			state: clusterBackupState{
+				clusterName:           "shop",
+				deployed:              true,
+				bucket:                bucket,
+				schedulesErr:          errors.New("connection refused"),
+				lastSuccessfulBackups: recentBackups,
+			},
+			reasons: []string{"could not check schedule: connection refused"},
+		},
+		{
+			name: "outside of RPO",
+			state: clusterBackupState{
+				clusterName:           "shop",
+				deployed:              true,
+				bucket:                bucket,
+				schedules:             schedules,
+				lastSuccessfulBackups: map[string]time.Time{"production": old},
+			},
+			reasons: []string{"no successful backup within the RPO of 12h"},
+		},
+		{
+			name: "backup of another policy",
+			state: clusterBackupState{
+				clusterName:           "shop",
+				deployed:              true,
+				bucket:                bucket,
+				schedules:             schedules,
+				lastSuccessfulBackups: map[string]time.Time{"staging": recent},
+			},
+			reasons: []string{"no successful backup"},
+		},
+		{
+			name: "bucket mismatch",
+			state: clusterBackupState{
+				clusterName:           "shop",
+				deployed:              true,
+				bucket:                &api.Bucket{Cloud: "amazon", Name: "shop-backups"},
+				lastSuccessfulBackups: recentBackups,
+			},
+			reasons: []string{"backup service uses bucket shop-backups instead of backups-shop"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.reasons, checkPolicyCompliance(policy, test.state, now))
+		})
+	}
+}
+
+func TestIsPolicyBackup(t *testing.T) {
+	policy := &api.BackupPolicy{Name: "production"}
+
+	assert.True(t, isPolicyBackup(policy, map[string]string{"ark-schedule": "policy-production"}))
+	assert.True(t, isPolicyBackup(policy, map[string]string{api.LabelKeyBackupPolicy: "production"}))
+	assert.False(t, isPolicyBackup(policy, map[string]string{"ark-schedule": "policy-staging"}))
+	assert.False(t, isPolicyBackup(policy, map[string]string{"ark-schedule": "daily"}))
+	assert.False(t, isPolicyBackup(policy, nil))
+}
@@ -0,0 +1,63 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const FailRestoreActivityName = "ark-object-restore-fail-restore"
+
+type FailRestoreActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+	RestoreID      uint
+
+	Reason string
+}
+
+// FailRestoreActivity marks an object level restore failed.
+type FailRestoreActivity struct {
+	clusters Clusters
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+// NewFailRestoreActivity returns a new FailRestoreActivity.
+func NewFailRestoreActivity(clusters Clusters, db *gorm.DB, logger logrus.FieldLogger) FailRestoreActivity {
+	return FailRestoreActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a FailRestoreActivity) Execute(ctx context.Context, input FailRestoreActivityInput) error {
+	logger := a.logger.WithFields(logrus.Fields{
+		"clusterId": input.ClusterID,
+		"restoreId": input.RestoreID,
+	})
+
+	svc, err := getRestoresService(ctx, a.clusters, a.db, logger, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	return svc.FailObjectRestore(input.RestoreID, errors.New(input.Reason))
+}
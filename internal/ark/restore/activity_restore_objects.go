@@ -0,0 +1,62 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+)
+
+const RestoreObjectsActivityName = "ark-object-restore-restore-objects"
+
+type RestoreObjectsActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+	RestoreID      uint
+}
+
+// RestoreObjectsActivity restores the objects selected by an object level restore.
+type RestoreObjectsActivity struct {
+	clusters Clusters
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+// NewRestoreObjectsActivity returns a new RestoreObjectsActivity.
+func NewRestoreObjectsActivity(clusters Clusters, db *gorm.DB, logger logrus.FieldLogger) RestoreObjectsActivity {
+	return RestoreObjectsActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a RestoreObjectsActivity) Execute(ctx context.Context, input RestoreObjectsActivityInput) error {
+	logger := a.logger.WithFields(logrus.Fields{
+		"clusterId": input.ClusterID,
+		"restoreId": input.RestoreID,
+	})
+
+	svc, err := getRestoresService(ctx, a.clusters, a.db, logger, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("restoring objects")
+
+	return svc.RestoreObjects(input.RestoreID)
+}
@@ -0,0 +1,46 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+// Clusters is the interface for getting the clusters of an organization
+type Clusters interface {
+	GetClusterByID(ctx context.Context, organizationID uint, clusterID uint) (api.Cluster, error)
+}
+
+func getRestoresService(ctx context.Context, clusters Clusters, db *gorm.DB, logger logrus.FieldLogger, organizationID uint, clusterID uint) (*ark.RestoresService, error) {
+	org, err := auth.GetOrganizationById(organizationID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get organization", "organizationId", organizationID)
+	}
+
+	cluster, err := clusters.GetClusterByID(ctx, organizationID, clusterID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get cluster", "clusterId", clusterID)
+	}
+
+	return ark.NewARKService(org, cluster, db, logger).GetRestoresService(), nil
+}
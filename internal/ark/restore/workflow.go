@@ -0,0 +1,67 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"time"
+
+	"go.uber.org/cadence/workflow"
+)
+
+// ObjectRestoreWorkflowName can be used to reference the object restore workflow.
+const ObjectRestoreWorkflowName = "ark-object-restore"
+
+// ObjectRestoreWorkflowInput is the input for an object restore workflow.
+type ObjectRestoreWorkflowInput struct {
+	OrganizationID uint
+	ClusterID      uint
+	RestoreID      uint
+}
+
+// ObjectRestoreWorkflow restores the objects selected by an object level restore,
+// and marks the restore failed if the objects could not be restored.
+func ObjectRestoreWorkflow(ctx workflow.Context, input ObjectRestoreWorkflowInput) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+	})
+
+	restoreCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    time.Hour,
+	})
+
+	restoreInput := RestoreObjectsActivityInput{
+		OrganizationID: input.OrganizationID,
+		ClusterID:      input.ClusterID,
+		RestoreID:      input.RestoreID,
+	}
+
+	err := workflow.ExecuteActivity(restoreCtx, RestoreObjectsActivityName, restoreInput).Get(ctx, nil)
+	if err != nil {
+		failInput := FailRestoreActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterID:      input.ClusterID,
+			RestoreID:      input.RestoreID,
+			Reason:         err.Error(),
+		}
+
+		_ = workflow.ExecuteActivity(ctx, FailRestoreActivityName, failInput).Get(ctx, nil)
+
+		return err
+	}
+
+	return nil
+}
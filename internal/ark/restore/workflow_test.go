@@ -0,0 +1,93 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restore
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/testsuite"
+	"go.uber.org/cadence/workflow"
+)
+
+// nolint: gochecknoglobals
+var testWorkflowInput = ObjectRestoreWorkflowInput{
+	OrganizationID: 1,
+	ClusterID:      2,
+	RestoreID:      3,
+}
+
+type WorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env *testsuite.TestWorkflowEnvironment
+}
+
+func TestWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowTestSuite))
+}
+
+func (s *WorkflowTestSuite) SetupSuite() {
+	workflow.RegisterWithOptions(ObjectRestoreWorkflow, workflow.RegisterOptions{Name: ObjectRestoreWorkflowName})
+
+	activity.RegisterWithOptions(RestoreObjectsActivity{}.Execute, activity.RegisterOptions{Name: RestoreObjectsActivityName})
+	activity.RegisterWithOptions(FailRestoreActivity{}.Execute, activity.RegisterOptions{Name: FailRestoreActivityName})
+}
+
+func (s *WorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+}
+
+func (s *WorkflowTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *WorkflowTestSuite) onRestoreObjects(err error) {
+	s.env.OnActivity(
+		RestoreObjectsActivityName,
+		mock.Anything,
+		RestoreObjectsActivityInput{OrganizationID: 1, ClusterID: 2, RestoreID: 3},
+	).Return(err).Once()
+}
+
+func (s *WorkflowTestSuite) Test_Success() {
+	s.onRestoreObjects(nil)
+
+	s.env.ExecuteWorkflow(ObjectRestoreWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *WorkflowTestSuite) Test_RestoreFailed() {
+	s.onRestoreObjects(errors.New("could not get backup contents"))
+	s.env.OnActivity(
+		FailRestoreActivityName,
+		mock.Anything,
+		mock.MatchedBy(func(input FailRestoreActivityInput) bool {
+			return input.OrganizationID == 1 && input.ClusterID == 2 && input.RestoreID == 3 &&
+				input.Reason == "could not get backup contents"
+		}),
+	).Return(nil).Once()
+
+	s.env.ExecuteWorkflow(ObjectRestoreWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+}
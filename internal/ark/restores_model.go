@@ -89,6 +89,7 @@ func (restore *ClusterBackupRestoresModel) ConvertModelToEntity() *api.Restore {
 
 	state := restore.GetState()
 	results := restore.GetResults()
+	selectors := getObjectSelectors(state)
 
 	item := &api.Restore{
 		ID:               restore.ID,
@@ -109,6 +110,8 @@ func (restore *ClusterBackupRestoresModel) ConvertModelToEntity() *api.Restore {
 			LabelSelector:           state.Spec.LabelSelector,
 			NamespaceMapping:        state.Spec.NamespaceMapping,
 			RestorePVs:              state.Spec.RestorePVs,
+			IncludedObjects:         selectors.IncludedObjects,
+			ExcludedObjects:         selectors.ExcludedObjects,
 		},
 	}
 
@@ -30,6 +30,7 @@ import (
 // RestoresService is for managing ARK restores
 type RestoresService struct {
 	deployments *DeploymentsService
+	buckets     *BucketsService
 	repository  *RestoresRepository
 
 	org    *auth.Organization
@@ -44,19 +45,27 @@ func RestoresServiceFactory(
 	logger logrus.FieldLogger,
 ) *RestoresService {
 
-	return NewRestoresService(org, deployments, NewRestoresRepository(org, deployments.GetCluster(), db, logger), logger)
+	return NewRestoresService(
+		org,
+		deployments,
+		BucketsServiceFactory(org, db, logger),
+		NewRestoresRepository(org, deployments.GetCluster(), db, logger),
+		logger,
+	)
 }
 
 // NewRestoresService creates and returns an initialized RestoresService instance
 func NewRestoresService(
 	org *auth.Organization,
 	deployments *DeploymentsService,
+	buckets *BucketsService,
 	repository *RestoresRepository,
 	logger logrus.FieldLogger,
 ) *RestoresService {
 	return &RestoresService{
 		org:         org,
 		deployments: deployments,
+		buckets:     buckets,
 		repository:  repository,
 		logger:      logger,
 	}
@@ -109,22 +118,24 @@ func (s *RestoresService) GetByID(id uint) (*api.Restore, error) {
 // DeleteByName deletes a Restore by name
 func (s *RestoresService) DeleteByName(name string) error {
 
-	client, err := s.deployments.GetClient()
-	if err != nil {
-		return emperror.Wrap(err, "error getting ark client")
-	}
-
-	err = client.DeleteRestoreByName(name)
-	if k8serrors.IsNotFound(err) {
-		err = nil
-	}
+	restore, err := s.GetModelByName(name)
 	if err != nil {
 		return emperror.Wrap(err, "error during deleting restore")
 	}
 
-	restore, err := s.GetModelByName(name)
-	if err != nil {
-		return emperror.Wrap(err, "error during deleting restore")
+	if !isObjectRestore(restore.GetState()) {
+		client, err := s.deployments.GetClient()
+		if err != nil {
+			return emperror.Wrap(err, "error getting ark client")
+		}
+
+		err = client.DeleteRestoreByName(name)
+		if k8serrors.IsNotFound(err) {
+			err = nil
+		}
+		if err != nil {
+			return emperror.Wrap(err, "error during deleting restore")
+		}
 	}
 
 	err = s.repository.Delete(restore)
@@ -143,17 +154,19 @@ func (s *RestoresService) DeleteByID(id uint) error {
 		return emperror.Wrap(err, "could not get restore from database")
 	}
 
-	client, err := s.deployments.GetClient()
-	if err != nil {
-		return emperror.Wrap(err, "could not get ARK client")
-	}
-
-	err = client.DeleteRestoreByName(restore.Name)
-	if k8serrors.IsNotFound(err) {
-		err = nil
-	}
-	if err != nil {
-		return emperror.Wrap(err, "could not delete restore through ARK")
+	if !isObjectRestore(restore.GetState()) {
+		client, err := s.deployments.GetClient()
+		if err != nil {
+			return emperror.Wrap(err, "could not get ARK client")
+		}
+
+		err = client.DeleteRestoreByName(restore.Name)
+		if k8serrors.IsNotFound(err) {
+			err = nil
+		}
+		if err != nil {
+			return emperror.Wrap(err, "could not delete restore through ARK")
+		}
 	}
 
 	err = s.repository.Delete(restore)
@@ -207,6 +220,11 @@ func (s *RestoresService) Create(req api.CreateRestoreRequest) (*api.Restore, er
 		return nil, emperror.Wrap(err, "error getting active deployment")
 	}
 
+	// ARK can not filter by object names, so object level restores are done by Pipeline
+	if req.Options.HasObjectSelectors() {
+		return s.createObjectRestore(req, deployment)
+	}
+
 	client, err := s.deployments.GetClient()
 	if err != nil {
 		return nil, emperror.Wrap(err, "error getting ark client")
@@ -32,7 +32,7 @@ func RunSyncServices(
 	clusterManager api.ClusterManager,
 	logger logrus.FieldLogger,
 	errorHandler emperror.Handler,
-	bucketSyncInterval, restoreSyncInterval, backupSyncInterval time.Duration,
+	bucketSyncInterval, restoreSyncInterval, backupSyncInterval, policySyncInterval time.Duration,
 ) {
 	if bucketSyncInterval.Seconds() < 1 {
 		logger.WithField("interval", bucketSyncInterval.Seconds()).Error("invalid bucket sync interval")
@@ -46,11 +46,16 @@ func RunSyncServices(
 		logger.WithField("interval", backupSyncInterval.Seconds()).Error("invalid backup sync interval")
 		return
 	}
+	if policySyncInterval.Seconds() < 1 {
+		logger.WithField("interval", policySyncInterval.Seconds()).Error("invalid policy sync interval")
+		return
+	}
 
 	logger.WithFields(logrus.Fields{
 		"bucket-sync-interval":  bucketSyncInterval,
 		"restore-sync-interval": restoreSyncInterval,
 		"backup-sync-interval":  backupSyncInterval,
+		"policy-sync-interval":  policySyncInterval,
 	}).Info("ARK synchronisation starting")
 
 	svc := NewSyncService(
@@ -58,6 +63,7 @@ func RunSyncServices(
 		bucketSyncInterval,
 		restoreSyncInterval,
 		backupSyncInterval,
+		policySyncInterval,
 	)
 
 	svc.Run(context, db, logger)
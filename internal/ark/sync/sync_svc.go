@@ -23,6 +23,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
 	"github.com/banzaicloud/pipeline/internal/ark/api"
 )
 
@@ -32,6 +33,7 @@ type Service struct {
 	bucketSyncInterval  time.Duration
 	restoreSyncInterval time.Duration
 	backupSyncInterval  time.Duration
+	policySyncInterval  time.Duration
 }
 
 // NewSyncService creates and initializes a Service
@@ -40,6 +42,7 @@ func NewSyncService(
 	BucketSyncInterval time.Duration,
 	RestoreSyncInterval time.Duration,
 	BackupSyncInterval time.Duration,
+	PolicySyncInterval time.Duration,
 ) *Service {
 
 	return &Service{
@@ -47,6 +50,7 @@ func NewSyncService(
 		bucketSyncInterval:  BucketSyncInterval,
 		restoreSyncInterval: RestoreSyncInterval,
 		backupSyncInterval:  BackupSyncInterval,
+		policySyncInterval:  PolicySyncInterval,
 	}
 }
 
@@ -76,6 +80,13 @@ func (s *Service) Run(context context.Context, db *gorm.DB, logger logrus.FieldL
 		s.syncBackupsLoop(context, db, logger, s.backupSyncInterval)
 	}()
 
+	// backup policies
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.syncPoliciesLoop(context, db, logger, s.policySyncInterval)
+	}()
+
 	wg.Wait()
 }
 
@@ -216,3 +227,48 @@ func (s *Service) syncBackups(db *gorm.DB, logger logrus.FieldLogger) error {
 
 	return nil
 }
+
+func (s *Service) syncPoliciesLoop(
+	ctx context.Context,
+	db *gorm.DB,
+	logger logrus.FieldLogger,
+	interval time.Duration,
+) {
+
+	logger.WithField("interval", interval.String()).Debug("reconciling backup policies")
+	go s.syncPolicies(db, logger) // nolint: errcheck
+	ticker := time.NewTicker(interval)
+	func() {
+		for {
+			select {
+			case <-ticker.C:
+				logger.WithField("interval", interval.String()).Debug("reconciling backup policies")
+				s.syncPolicies(db, logger) // nolint: errcheck
+			case <-ctx.Done():
+				logger.Debug("closing ticker")
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+}
+
+func (s *Service) syncPolicies(db *gorm.DB, logger logrus.FieldLogger) error {
+
+	var orgs []*auth.Organization
+	err := db.Find(&orgs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, org := range orgs {
+		log := logger.WithField("orgID", org.ID).WithField("orgName", org.Name)
+		log.Debug("reconciling backup policies")
+		err := ark.PoliciesServiceFactory(org, db, log).Reconcile(context.Background(), s.clusterManager)
+		if err != nil {
+			log.Error(err)
+		}
+	}
+
+	return nil
+}
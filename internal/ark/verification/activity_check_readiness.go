@@ -0,0 +1,134 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence/activity"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/internal/ark/migration"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+const CheckReadinessActivityName = "ark-verification-check-readiness"
+
+type CheckReadinessActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+
+	Namespaces []string
+	Timeout    time.Duration
+
+	// SkipVolumes specifies whether workloads using persistent volume claims are skipped, because the volumes are not restored
+	SkipVolumes bool
+}
+
+// CheckReadinessActivity waits for the restored workloads to become ready, and reports the ones which did not within the timeout.
+type CheckReadinessActivity struct {
+	clusters migration.Clusters
+	logger   logrus.FieldLogger
+}
+
+// NewCheckReadinessActivity returns a new CheckReadinessActivity.
+func NewCheckReadinessActivity(clusters migration.Clusters, logger logrus.FieldLogger) CheckReadinessActivity {
+	return CheckReadinessActivity{
+		clusters: clusters,
+		logger:   logger,
+	}
+}
+
+func (a CheckReadinessActivity) Execute(ctx context.Context, input CheckReadinessActivityInput) (*Readiness, error) {
+	logger := a.logger.WithField("clusterId", input.ClusterID)
+
+	cluster, err := a.clusters.GetClusterByID(ctx, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get cluster", "clusterId", input.ClusterID)
+	}
+
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get k8s config")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not create k8s client")
+	}
+
+	deadline := time.NewTimer(input.Timeout)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		readiness, err := checkReadiness(client, input.Namespaces, input.SkipVolumes)
+		if err != nil {
+			return nil, err
+		}
+		if readiness.Ready() {
+			return readiness, nil
+		}
+
+		logger.WithField("unready", len(readiness.Unready)).Debug("waiting for workloads to become ready")
+
+		activity.RecordHeartbeat(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline.C:
+			return readiness, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func checkReadiness(client kubernetes.Interface, namespaces []string, skipVolumes bool) (*Readiness, error) {
+	var deployments []appsv1.Deployment
+	var statefulSets []appsv1.StatefulSet
+	var daemonSets []appsv1.DaemonSet
+
+	for _, namespace := range namespaces {
+		deploymentList, err := client.AppsV1().Deployments(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, emperror.WrapWith(err, "could not list deployments", "namespace", namespace)
+		}
+		deployments = append(deployments, deploymentList.Items...)
+
+		statefulSetList, err := client.AppsV1().StatefulSets(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, emperror.WrapWith(err, "could not list statefulsets", "namespace", namespace)
+		}
+		statefulSets = append(statefulSets, statefulSetList.Items...)
+
+		daemonSetList, err := client.AppsV1().DaemonSets(namespace).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, emperror.WrapWith(err, "could not list daemonsets", "namespace", namespace)
+		}
+		daemonSets = append(daemonSets, daemonSetList.Items...)
+	}
+
+	readiness := NewReadiness(deployments, statefulSets, daemonSets, skipVolumes)
+
+	return &readiness, nil
+}
@@ -0,0 +1,119 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/banzaicloud/pipeline/internal/ark/migration"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+const CleanupActivityName = "ark-verification-cleanup"
+
+type CleanupActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+
+	// VerificationID selects the restores created for the verification
+	VerificationID string
+
+	// Namespaces are the scratch namespaces to delete
+	Namespaces []string
+}
+
+// CleanupActivity deletes the restores and the scratch namespaces created for the verification.
+type CleanupActivity struct {
+	clusters migration.Clusters
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+// NewCleanupActivity returns a new CleanupActivity.
+func NewCleanupActivity(clusters migration.Clusters, db *gorm.DB, logger logrus.FieldLogger) CleanupActivity {
+	return CleanupActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a CleanupActivity) Execute(ctx context.Context, input CleanupActivityInput) error {
+	logger := a.logger.WithField("clusterId", input.ClusterID)
+
+	svc, err := getARKService(ctx, a.clusters, a.db, logger, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	if len(input.Namespaces) > 0 {
+		kubeConfig, err := svc.GetCluster().GetK8sConfig()
+		if err != nil {
+			return emperror.Wrap(err, "could not get k8s config")
+		}
+
+		client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+		if err != nil {
+			return emperror.Wrap(err, "could not create k8s client")
+		}
+
+		for _, namespace := range input.Namespaces {
+			logger.WithField("namespace", namespace).Info("deleting scratch namespace")
+
+			err := client.CoreV1().Namespaces().Delete(namespace, &metav1.DeleteOptions{})
+			if err != nil && !k8serrors.IsNotFound(err) {
+				return emperror.WrapWith(err, "could not delete namespace", "namespace", namespace)
+			}
+		}
+	}
+
+	client, err := svc.GetDeploymentsService().GetClient()
+	if err != nil {
+		return emperror.Wrap(err, "could not get ARK client")
+	}
+
+	restores, err := client.ListRestores(metav1.ListOptions{
+		LabelSelector: labels.Set{verificationIDLabelKey: input.VerificationID}.String(),
+	})
+	if err != nil {
+		return emperror.Wrap(err, "could not list restores")
+	}
+
+	for _, restore := range restores.Items {
+		logger.WithField("restore", restore.Name).Info("deleting restore")
+
+		err := svc.GetRestoresService().DeleteByName(restore.Name)
+		if gorm.IsRecordNotFoundError(errors.Cause(err)) {
+			// the restore might not be synced yet if the verification failed early
+			err = client.DeleteRestoreByName(restore.Name)
+			if k8serrors.IsNotFound(err) {
+				err = nil
+			}
+		}
+		if err != nil {
+			return emperror.WrapWith(err, "could not delete restore", "restore", restore.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"sort"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+const PlanActivityName = "ark-verification-plan"
+
+type PlanActivityInput struct {
+	OrganizationID uint
+	BackupID       uint
+
+	// InPlace specifies whether the backup is restored into the backed up cluster
+	InPlace bool
+}
+
+type PlanActivityOutput struct {
+	IncludedNamespaces []string
+	NamespaceMapping   map[string]string
+
+	// Namespaces are the namespaces of the target cluster the workloads are restored into
+	Namespaces []string
+}
+
+// PlanActivity decides which namespaces of the backup are restored for the verification and where.
+type PlanActivity struct {
+	db     *gorm.DB
+	logger logrus.FieldLogger
+}
+
+// NewPlanActivity returns a new PlanActivity.
+func NewPlanActivity(db *gorm.DB, logger logrus.FieldLogger) PlanActivity {
+	return PlanActivity{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a PlanActivity) Execute(ctx context.Context, input PlanActivityInput) (*PlanActivityOutput, error) {
+	logger := a.logger.WithField("backupId", input.BackupID)
+
+	org, err := auth.GetOrganizationById(input.OrganizationID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get organization", "organizationId", input.OrganizationID)
+	}
+
+	backup, err := ark.BackupsServiceFactory(org, a.db, logger).GetModelByID(input.BackupID)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := ark.BucketsServiceFactory(org, a.db, logger).GetBackupContents(backup.Bucket.ConvertModelToEntity(), backup.Name)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not read backup contents")
+	}
+
+	// namespaces of the infrastructure are managed by Pipeline on every cluster
+	excluded := []string{
+		"kube-system",
+		viper.GetString(config.ARKNamespace),
+		viper.GetString(config.PipelineSystemNamespace),
+	}
+
+	return plan(contents, input.BackupID, input.InPlace, excluded), nil
+}
+
+// plan selects the namespaces of the backup to restore, and maps them to scratch namespaces for in place verifications
+func plan(contents *api.BackupContents, backupID uint, inPlace bool, excluded []string) *PlanActivityOutput {
+	output := &PlanActivityOutput{
+		IncludedNamespaces: make([]string, 0),
+		Namespaces:         make([]string, 0),
+	}
+	if inPlace {
+		output.NamespaceMapping = make(map[string]string)
+	}
+
+	skip := make(map[string]bool, len(excluded))
+	for _, namespace := range excluded {
+		skip[namespace] = true
+	}
+
+	for _, namespace := range contents.Namespaces {
+		if skip[namespace.Name] {
+			continue
+		}
+
+		target := namespace.Name
+		if inPlace {
+			target = ScratchNamespace(backupID, namespace.Name)
+			output.NamespaceMapping[namespace.Name] = target
+		}
+
+		output.IncludedNamespaces = append(output.IncludedNamespaces, namespace.Name)
+		output.Namespaces = append(output.Namespaces, target)
+	}
+
+	sort.Strings(output.IncludedNamespaces)
+	sort.Strings(output.Namespaces)
+
+	return output
+}
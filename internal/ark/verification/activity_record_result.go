@@ -0,0 +1,59 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+const RecordResultActivityName = "ark-verification-record-result"
+
+type RecordResultActivityInput struct {
+	OrganizationID uint
+	BackupID       uint
+
+	Verification api.BackupVerification
+}
+
+// RecordResultActivity persists the verdict of a verification on the backup.
+type RecordResultActivity struct {
+	db     *gorm.DB
+	logger logrus.FieldLogger
+}
+
+// NewRecordResultActivity returns a new RecordResultActivity.
+func NewRecordResultActivity(db *gorm.DB, logger logrus.FieldLogger) RecordResultActivity {
+	return RecordResultActivity{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (a RecordResultActivity) Execute(ctx context.Context, input RecordResultActivityInput) error {
+	org, err := auth.GetOrganizationById(input.OrganizationID)
+	if err != nil {
+		return emperror.WrapWith(err, "could not get organization", "organizationId", input.OrganizationID)
+	}
+
+	return ark.BackupsServiceFactory(org, a.db, a.logger).UpdateVerification(input.BackupID, input.Verification)
+}
@@ -0,0 +1,92 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/internal/ark/migration"
+)
+
+const RestoreServicesActivityName = "ark-verification-restore-services"
+
+type RestoreServicesActivityInput struct {
+	OrganizationID uint
+	ClusterID      uint
+	BackupID       uint
+
+	IncludedNamespaces []string
+	NamespaceMapping   map[string]string
+}
+
+type RestoreServicesActivityOutput struct {
+	Results *api.MigrationResults
+}
+
+// RestoreServicesActivity restores the services of a backup into the scratch namespaces of the backed up cluster
+// as ClusterIP services, so that they do not allocate node ports or load balancers.
+type RestoreServicesActivity struct {
+	clusters migration.Clusters
+	db       *gorm.DB
+	logger   logrus.FieldLogger
+}
+
+// NewRestoreServicesActivity returns a new RestoreServicesActivity.
+func NewRestoreServicesActivity(clusters migration.Clusters, db *gorm.DB, logger logrus.FieldLogger) RestoreServicesActivity {
+	return RestoreServicesActivity{
+		clusters: clusters,
+		db:       db,
+		logger:   logger,
+	}
+}
+
+func (a RestoreServicesActivity) Execute(ctx context.Context, input RestoreServicesActivityInput) (*RestoreServicesActivityOutput, error) {
+	logger := a.logger.WithFields(logrus.Fields{
+		"clusterId": input.ClusterID,
+		"backupId":  input.BackupID,
+	})
+
+	svc, err := getARKService(ctx, a.clusters, a.db, logger, input.OrganizationID, input.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := ark.BackupsServiceFactory(svc.GetOrganization(), a.db, logger).GetModelByID(input.BackupID)
+	if err != nil {
+		return nil, err
+	}
+
+	disabled := false
+
+	logger.Info("restoring services")
+
+	results, err := svc.GetRestoresService().RestoreServicesAsClusterIP(backup.Bucket.ConvertModelToEntity(), backup.Name, api.RestoreOptions{
+		IncludedNamespaces:      input.IncludedNamespaces,
+		NamespaceMapping:        input.NamespaceMapping,
+		IncludeClusterResources: &disabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RestoreServicesActivityOutput{
+		Results: migration.NewMigrationResults(results),
+	}, nil
+}
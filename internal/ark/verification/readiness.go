@@ -0,0 +1,116 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Readiness describes the state of the restored workloads
+type Readiness struct {
+	Workloads int
+
+	// Unready lists the workloads which are not ready
+	Unready []string
+
+	// Skipped lists the workloads which cannot be verified
+	Skipped []string
+}
+
+// NewReadiness checks the restored workloads, and skips the ones using persistent volume claims when the volumes are not restored.
+func NewReadiness(
+	deployments []appsv1.Deployment,
+	statefulSets []appsv1.StatefulSet,
+	daemonSets []appsv1.DaemonSet,
+	skipVolumes bool,
+) Readiness {
+	readiness := Readiness{
+		Unready: make([]string, 0),
+		Skipped: make([]string, 0),
+	}
+
+	check := func(kind string, namespace string, name string, claimsVolumes bool, observed bool, ready int32, desired int32) {
+		workload := fmt.Sprintf("%s %s/%s", kind, namespace, name)
+
+		if skipVolumes && claimsVolumes {
+			readiness.Skipped = append(readiness.Skipped, workload+": uses persistent volume claims")
+			return
+		}
+
+		readiness.Workloads++
+
+		if !observed || ready < desired {
+			readiness.Unready = append(readiness.Unready, fmt.Sprintf("%s: %d of %d ready", workload, ready, desired))
+		}
+	}
+
+	for i := range deployments {
+		d := &deployments[i]
+		check(
+			"deployment", d.Namespace, d.Name,
+			claimsVolumes(d.Spec.Template.Spec),
+			d.Status.ObservedGeneration >= d.Generation,
+			d.Status.ReadyReplicas, replicas(d.Spec.Replicas),
+		)
+	}
+
+	for i := range statefulSets {
+		s := &statefulSets[i]
+		check(
+			"statefulset", s.Namespace, s.Name,
+			len(s.Spec.VolumeClaimTemplates) > 0 || claimsVolumes(s.Spec.Template.Spec),
+			s.Status.ObservedGeneration >= s.Generation,
+			s.Status.ReadyReplicas, replicas(s.Spec.Replicas),
+		)
+	}
+
+	for i := range daemonSets {
+		d := &daemonSets[i]
+		check(
+			"daemonset", d.Namespace, d.Name,
+			claimsVolumes(d.Spec.Template.Spec),
+			d.Status.ObservedGeneration >= d.Generation,
+			d.Status.NumberReady, d.Status.DesiredNumberScheduled,
+		)
+	}
+
+	return readiness
+}
+
+// Ready returns true if every verified workload is ready
+func (r Readiness) Ready() bool {
+	return len(r.Unready) == 0
+}
+
+func replicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+
+	return *replicas
+}
+
+func claimsVolumes(spec corev1.PodSpec) bool {
+	for _, volume := range spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			return true
+		}
+	}
+
+	return false
+}
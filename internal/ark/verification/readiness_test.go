@@ -0,0 +1,106 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
+func TestNewReadiness(t *testing.T) {
+	claimedVolumes := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"},
+					},
+				},
+			},
+		},
+	}
+
+	deployments := []appsv1.Deployment{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "web", Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Replicas: int32Ptr(2)},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 1, ReadyReplicas: 2},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "worker", Generation: 1},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 1},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "uploads", Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Template: claimedVolumes},
+		},
+	}
+
+	statefulSets := []appsv1.StatefulSet{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "db", Generation: 1},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:             int32Ptr(1),
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{}},
+			},
+		},
+	}
+
+	daemonSets := []appsv1.DaemonSet{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "agent", Generation: 2},
+			Status:     appsv1.DaemonSetStatus{ObservedGeneration: 1, DesiredNumberScheduled: 0},
+		},
+	}
+
+	t.Run("in place", func(t *testing.T) {
+		readiness := NewReadiness(deployments, statefulSets, daemonSets, true)
+
+		assert.Equal(t, 3, readiness.Workloads)
+		assert.Equal(t, []string{
+			"deployment shop/worker: 0 of 1 ready",
+			"daemonset shop/agent: 0 of 0 ready",
+		}, readiness.Unready)
+		assert.Equal(t, []string{
+			"deployment shop/uploads: uses persistent volume claims",
+			"statefulset shop/db: uses persistent volume claims",
+		}, readiness.Skipped)
+		assert.False(t, readiness.Ready())
+	})
+
+	t.Run("target cluster", func(t *testing.T) {
+		readiness := NewReadiness(deployments, statefulSets, daemonSets, false)
+
+		assert.Equal(t, 5, readiness.Workloads)
+		assert.Len(t, readiness.Unready, 4)
+		assert.Empty(t, readiness.Skipped)
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		readiness := NewReadiness(deployments[:1], nil, nil, false)
+
+		assert.Equal(t, 1, readiness.Workloads)
+		assert.True(t, readiness.Ready())
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"emperror.dev/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/ark/migration"
+)
+
+const (
+	pollInterval = 15 * time.Second
+
+	verificationIDLabelKey = "pipeline-verification-id"
+
+	maxNamespaceLength = 63
+
+	scratchNamespaceHashLength = 8
+)
+
+// ID returns the identifier of a verification of a backup started at the given time.
+func ID(backupID uint, startedAt time.Time) string {
+	return fmt.Sprintf("%d-%d", backupID, startedAt.Unix())
+}
+
+// ScratchNamespace returns the namespace a backed up namespace is restored into
+// when the backup is verified within the backed up cluster.
+// Names longer than a namespace name can be are truncated and suffixed with a hash of the full name,
+// so that namespaces with a common prefix do not end up in the same scratch namespace.
+func ScratchNamespace(backupID uint, namespace string) string {
+	name := fmt.Sprintf("verify-%d-%s", backupID, namespace)
+	if len(name) <= maxNamespaceLength {
+		return name
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:scratchNamespaceHashLength]
+
+	return strings.TrimRight(name[:maxNamespaceLength-scratchNamespaceHashLength-1], "-") + "-" + hash
+}
+
+func getARKService(ctx context.Context, clusters migration.Clusters, db *gorm.DB, logger logrus.FieldLogger, organizationID uint, clusterID uint) (*ark.Service, error) {
+	org, err := auth.GetOrganizationById(organizationID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get organization", "organizationId", organizationID)
+	}
+
+	cluster, err := clusters.GetClusterByID(ctx, organizationID, clusterID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get cluster", "clusterId", clusterID)
+	}
+
+	return ark.NewARKService(org, cluster, db, logger), nil
+}
@@ -0,0 +1,68 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+)
+
+func TestScratchNamespace(t *testing.T) {
+	assert.Equal(t, "verify-12-shop", ScratchNamespace(12, "shop"))
+
+	name := ScratchNamespace(12, strings.Repeat("a", 52)+"-"+strings.Repeat("b", 10))
+	assert.Len(t, name, 63)
+	assert.True(t, strings.HasPrefix(name, "verify-12-"+strings.Repeat("a", 44)+"-"))
+
+	other := ScratchNamespace(12, strings.Repeat("a", 52)+"-"+strings.Repeat("c", 10))
+	assert.Len(t, other, 63)
+	assert.NotEqual(t, name, other)
+
+	name = ScratchNamespace(12, strings.Repeat("a", 43)+"-"+strings.Repeat("b", 20))
+	assert.True(t, strings.HasPrefix(name, "verify-12-"+strings.Repeat("a", 43)+"-"))
+	assert.NotContains(t, name, "--")
+}
+
+func TestPlan(t *testing.T) {
+	contents := &api.BackupContents{
+		Namespaces: []api.BackupNamespaceContents{
+			{Name: "shop"},
+			{Name: "kube-system"},
+			{Name: "default"},
+			{Name: "pipeline-system"},
+		},
+	}
+	excluded := []string{"kube-system", "pipeline-system"}
+
+	t.Run("in place", func(t *testing.T) {
+		planned := plan(contents, 2, true, excluded)
+
+		assert.Equal(t, []string{"default", "shop"}, planned.IncludedNamespaces)
+		assert.Equal(t, map[string]string{"default": "verify-2-default", "shop": "verify-2-shop"}, planned.NamespaceMapping)
+		assert.Equal(t, []string{"verify-2-default", "verify-2-shop"}, planned.Namespaces)
+	})
+
+	t.Run("target cluster", func(t *testing.T) {
+		planned := plan(contents, 2, false, excluded)
+
+		assert.Equal(t, []string{"default", "shop"}, planned.IncludedNamespaces)
+		assert.Nil(t, planned.NamespaceMapping)
+		assert.Equal(t, []string{"default", "shop"}, planned.Namespaces)
+	})
+}
@@ -0,0 +1,312 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/internal/ark/migration"
+)
+
+// VerificationWorkflowName can be used to reference the backup verification workflow.
+const VerificationWorkflowName = "ark-backup-verification"
+
+// nolint: gochecknoglobals
+var (
+	// inPlaceExcludedResources are not restored by ARK into the scratch namespaces of the backed up cluster:
+	// ingresses would compete with the original ones for the same hosts,
+	// jobs and cron jobs would run their side effects (migrations, reports, cleanups) a second time,
+	// services would allocate node ports and load balancers (they are restored as ClusterIP services instead)
+	inPlaceExcludedResources = []string{
+		"ingresses",
+		"jobs",
+		"cronjobs",
+		"services",
+	}
+)
+
+// VerificationWorkflowInput is the input for a backup verification workflow.
+type VerificationWorkflowInput struct {
+	OrganizationID uint
+	VerificationID string
+
+	BackupID   uint
+	BackupName string
+
+	// ClusterID is the backed up cluster
+	ClusterID uint
+
+	// TargetClusterID is the cluster the backup is restored into, an existing short-lived cluster
+	// or the backed up cluster itself, in which case the backup is restored into scratch namespaces
+	TargetClusterID uint
+
+	ReadinessTimeout time.Duration
+	StartedAt        time.Time
+}
+
+// VerificationWorkflow restores a backup, checks whether the restored workloads become ready,
+// records the verdict on the backup and removes everything created for the verification.
+func VerificationWorkflow(ctx workflow.Context, input VerificationWorkflowInput) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+	})
+
+	// restores are polled until they finish
+	restoreCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    2 * time.Hour,
+		HeartbeatTimeout:       4 * pollInterval,
+	})
+
+	readinessCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    input.ReadinessTimeout + 10*time.Minute,
+		HeartbeatTimeout:       4 * pollInterval,
+	})
+
+	inPlace := input.TargetClusterID == input.ClusterID
+	startedAt := input.StartedAt
+	notes := make([]string, 0)
+
+	verification := api.BackupVerification{
+		Status:          api.BackupVerificationStatusRunning,
+		TargetClusterID: input.TargetClusterID,
+		StartedAt:       &startedAt,
+	}
+
+	record := func() error {
+		finishedAt := workflow.Now(ctx)
+		verification.FinishedAt = &finishedAt
+
+		activityInput := RecordResultActivityInput{
+			OrganizationID: input.OrganizationID,
+			BackupID:       input.BackupID,
+			Verification:   verification,
+		}
+
+		return workflow.ExecuteActivity(ctx, RecordResultActivityName, activityInput).Get(ctx, nil)
+	}
+
+	fail := func(err error) error {
+		verification.Status = api.BackupVerificationStatusFailed
+		verification.Message = strings.Join(append([]string{err.Error()}, notes...), "; ")
+
+		_ = record()
+
+		return err
+	}
+
+	var prepared migration.PrepareTargetActivityOutput
+	if !inPlace {
+		activityInput := migration.PrepareTargetActivityInput{
+			OrganizationID:  input.OrganizationID,
+			SourceClusterID: input.ClusterID,
+			TargetClusterID: input.TargetClusterID,
+			BackupName:      input.BackupName,
+		}
+
+		err := workflow.ExecuteActivity(ctx, migration.PrepareTargetActivityName, activityInput).Get(ctx, &prepared)
+		if err != nil {
+			return fail(err)
+		}
+
+		notes = append(notes, prepared.Warnings...)
+	}
+
+	var scratchNamespaces []string
+
+	teardown := func() {
+		activityInput := CleanupActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterID:      input.TargetClusterID,
+			VerificationID: input.VerificationID,
+			Namespaces:     scratchNamespaces,
+		}
+
+		err := workflow.ExecuteActivity(ctx, CleanupActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			notes = append(notes, "could not clean up the restored workloads: "+err.Error())
+		}
+
+		// ARK is only removed if it was deployed for the verification
+		if prepared.ArkDeployed {
+			activityInput := migration.RemoveDeploymentActivityInput{
+				OrganizationID: input.OrganizationID,
+				ClusterID:      input.TargetClusterID,
+			}
+
+			err := workflow.ExecuteActivity(ctx, migration.RemoveDeploymentActivityName, activityInput).Get(ctx, nil)
+			if err != nil {
+				notes = append(notes, "could not remove ARK from the target cluster: "+err.Error())
+			}
+		}
+	}
+
+	var planned PlanActivityOutput
+	{
+		activityInput := PlanActivityInput{
+			OrganizationID: input.OrganizationID,
+			BackupID:       input.BackupID,
+			InPlace:        inPlace,
+		}
+
+		err := workflow.ExecuteActivity(ctx, PlanActivityName, activityInput).Get(ctx, &planned)
+		if err == nil && len(planned.IncludedNamespaces) == 0 {
+			err = errors.New("backup does not contain any namespace to verify")
+		}
+		if err != nil {
+			teardown()
+
+			return fail(err)
+		}
+	}
+
+	restoreInput := migration.RestoreActivityInput{
+		OrganizationID: input.OrganizationID,
+		ClusterID:      input.TargetClusterID,
+		BackupName:     input.BackupName,
+		Options: api.MigrationOptions{
+			IncludedNamespaces: planned.IncludedNamespaces,
+			NamespaceMapping:   planned.NamespaceMapping,
+			RestorePVs:         prepared.RestorePVs,
+		},
+		Labels: map[string]string{verificationIDLabelKey: input.VerificationID},
+	}
+
+	// restoring in place must not touch anything outside of the scratch namespaces
+	if inPlace {
+		disabled := false
+
+		restoreInput.Options.RestorePVs = &disabled
+		restoreInput.IncludeClusterResources = &disabled
+		restoreInput.ExcludedResources = inPlaceExcludedResources
+
+		scratchNamespaces = planned.Namespaces
+		notes = append(notes, "ingresses, jobs and cron jobs are not restored into the backed up cluster, services are restored as ClusterIP services")
+	}
+
+	var restored migration.RestoreActivityOutput
+	err := workflow.ExecuteActivity(restoreCtx, migration.RestoreActivityName, restoreInput).Get(ctx, &restored)
+	if err != nil {
+		teardown()
+
+		return fail(err)
+	}
+
+	if inPlace {
+		activityInput := RestoreServicesActivityInput{
+			OrganizationID:     input.OrganizationID,
+			ClusterID:          input.TargetClusterID,
+			BackupID:           input.BackupID,
+			IncludedNamespaces: planned.IncludedNamespaces,
+			NamespaceMapping:   planned.NamespaceMapping,
+		}
+
+		var servicesRestored RestoreServicesActivityOutput
+		err := workflow.ExecuteActivity(ctx, RestoreServicesActivityName, activityInput).Get(ctx, &servicesRestored)
+		if err != nil {
+			teardown()
+
+			return fail(err)
+		}
+
+		restored.Results = mergeResults(restored.Results, servicesRestored.Results)
+	}
+
+	var readiness Readiness
+	{
+		activityInput := CheckReadinessActivityInput{
+			OrganizationID: input.OrganizationID,
+			ClusterID:      input.TargetClusterID,
+			Namespaces:     planned.Namespaces,
+			Timeout:        input.ReadinessTimeout,
+			SkipVolumes:    inPlace,
+		}
+
+		err = workflow.ExecuteActivity(readinessCtx, CheckReadinessActivityName, activityInput).Get(ctx, &readiness)
+	}
+
+	teardown()
+
+	if err != nil {
+		return fail(err)
+	}
+
+	verification.Status, verification.Message, verification.Problems = verdict(restored.Results, readiness, notes)
+
+	return record()
+}
+
+// mergeResults adds the resource results of other to results
+func mergeResults(results *api.MigrationResults, other *api.MigrationResults) *api.MigrationResults {
+	if results == nil {
+		return other
+	}
+
+	if other != nil {
+		results.Errors += other.Errors
+		results.Warnings += other.Warnings
+		results.Resources = append(results.Resources, other.Resources...)
+	}
+
+	return results
+}
+
+// verdict decides whether the verification passed based on the restore results and the readiness of the restored workloads
+func verdict(results *api.MigrationResults, readiness Readiness, notes []string) (string, string, []string) {
+	problems := make([]string, 0)
+
+	if results != nil {
+		for _, result := range results.Resources {
+			if result.Severity != api.MigrationResultSeverityError {
+				continue
+			}
+
+			source := result.Namespace
+			if source == "" {
+				source = result.Scope
+			}
+
+			problems = append(problems, fmt.Sprintf("restore error in %s: %s", source, result.Message))
+		}
+	}
+
+	problems = append(problems, readiness.Unready...)
+
+	messages := []string{
+		fmt.Sprintf("%d of %d restored workloads became ready", readiness.Workloads-len(readiness.Unready), readiness.Workloads),
+	}
+	if results != nil && results.Errors > 0 {
+		messages = append(messages, fmt.Sprintf("restore completed with %d errors", results.Errors))
+	}
+	if len(readiness.Skipped) > 0 {
+		messages = append(messages, fmt.Sprintf("%d workloads using persistent volumes were not verified", len(readiness.Skipped)))
+	}
+	messages = append(messages, notes...)
+
+	status := api.BackupVerificationStatusPassed
+	if len(problems) > 0 {
+		status = api.BackupVerificationStatusFailed
+	}
+
+	return status, strings.Join(messages, "; "), problems
+}
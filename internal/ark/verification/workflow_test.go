@@ -0,0 +1,315 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verification
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	arkAPI "github.com/heptio/ark/pkg/apis/ark/v1"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/testsuite"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/internal/ark/api"
+	"github.com/banzaicloud/pipeline/internal/ark/migration"
+)
+
+// nolint: gochecknoglobals
+var testWorkflowInput = VerificationWorkflowInput{
+	OrganizationID:   1,
+	VerificationID:   "2-1574150400",
+	BackupID:         2,
+	BackupName:       "daily-20191119",
+	ClusterID:        3,
+	TargetClusterID:  3,
+	ReadinessTimeout: 10 * time.Minute,
+	StartedAt:        time.Date(2019, 11, 19, 8, 0, 0, 0, time.UTC),
+}
+
+type WorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env     *testsuite.TestWorkflowEnvironment
+	results []api.BackupVerification
+}
+
+func TestWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkflowTestSuite))
+}
+
+func (s *WorkflowTestSuite) SetupSuite() {
+	workflow.RegisterWithOptions(VerificationWorkflow, workflow.RegisterOptions{Name: VerificationWorkflowName})
+
+	activity.RegisterWithOptions(migration.PrepareTargetActivity{}.Execute, activity.RegisterOptions{Name: migration.PrepareTargetActivityName})
+	activity.RegisterWithOptions(migration.RestoreActivity{}.Execute, activity.RegisterOptions{Name: migration.RestoreActivityName})
+	activity.RegisterWithOptions(migration.RemoveDeploymentActivity{}.Execute, activity.RegisterOptions{Name: migration.RemoveDeploymentActivityName})
+	activity.RegisterWithOptions(PlanActivity{}.Execute, activity.RegisterOptions{Name: PlanActivityName})
+	activity.RegisterWithOptions(RestoreServicesActivity{}.Execute, activity.RegisterOptions{Name: RestoreServicesActivityName})
+	activity.RegisterWithOptions(CheckReadinessActivity{}.Execute, activity.RegisterOptions{Name: CheckReadinessActivityName})
+	activity.RegisterWithOptions(CleanupActivity{}.Execute, activity.RegisterOptions{Name: CleanupActivityName})
+	activity.RegisterWithOptions(RecordResultActivity{}.Execute, activity.RegisterOptions{Name: RecordResultActivityName})
+}
+
+func (s *WorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+	s.results = nil
+
+	s.env.OnActivity(
+		RecordResultActivityName,
+		mock.Anything,
+		mock.MatchedBy(func(input RecordResultActivityInput) bool {
+			s.results = append(s.results, input.Verification)
+
+			return input.OrganizationID == 1 && input.BackupID == 2
+		}),
+	).Return(nil)
+}
+
+func (s *WorkflowTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *WorkflowTestSuite) onPlan(inPlace bool, output PlanActivityOutput) {
+	s.env.OnActivity(
+		PlanActivityName,
+		mock.Anything,
+		PlanActivityInput{OrganizationID: 1, BackupID: 2, InPlace: inPlace},
+	).Return(&output, nil).Once()
+}
+
+func (s *WorkflowTestSuite) onRestore(input migration.RestoreActivityInput, output *migration.RestoreActivityOutput, err error) {
+	s.env.OnActivity(migration.RestoreActivityName, mock.Anything, input).Return(output, err).Once()
+}
+
+func (s *WorkflowTestSuite) onCheckReadiness(clusterID uint, namespaces []string, skipVolumes bool, output Readiness) {
+	s.env.OnActivity(
+		CheckReadinessActivityName,
+		mock.Anything,
+		CheckReadinessActivityInput{
+			OrganizationID: 1,
+			ClusterID:      clusterID,
+			Namespaces:     namespaces,
+			Timeout:        10 * time.Minute,
+			SkipVolumes:    skipVolumes,
+		},
+	).Return(&output, nil).Once()
+}
+
+func (s *WorkflowTestSuite) onCleanup(clusterID uint, namespaces []string) {
+	s.env.OnActivity(
+		CleanupActivityName,
+		mock.Anything,
+		CleanupActivityInput{
+			OrganizationID: 1,
+			ClusterID:      clusterID,
+			VerificationID: "2-1574150400",
+			Namespaces:     namespaces,
+		},
+	).Return(nil).Once()
+}
+
+func (s *WorkflowTestSuite) final() api.BackupVerification {
+	s.Require().NotEmpty(s.results)
+
+	return s.results[len(s.results)-1]
+}
+
+func (s *WorkflowTestSuite) Test_InPlace_Passed() {
+	disabled := false
+	scratch := []string{"verify-2-shop"}
+
+	s.onPlan(true, PlanActivityOutput{
+		IncludedNamespaces: []string{"shop"},
+		NamespaceMapping:   map[string]string{"shop": "verify-2-shop"},
+		Namespaces:         scratch,
+	})
+	s.onRestore(migration.RestoreActivityInput{
+		OrganizationID: 1,
+		ClusterID:      3,
+		BackupName:     "daily-20191119",
+		Options: api.MigrationOptions{
+			IncludedNamespaces: []string{"shop"},
+			NamespaceMapping:   map[string]string{"shop": "verify-2-shop"},
+			RestorePVs:         &disabled,
+		},
+		Labels:                  map[string]string{"pipeline-verification-id": "2-1574150400"},
+		ExcludedResources:       []string{"ingresses", "jobs", "cronjobs", "services"},
+		IncludeClusterResources: &disabled,
+	}, &migration.RestoreActivityOutput{RestoreName: "daily-20191119-20191119080000"}, nil)
+	s.env.OnActivity(
+		RestoreServicesActivityName,
+		mock.Anything,
+		RestoreServicesActivityInput{
+			OrganizationID:     1,
+			ClusterID:          3,
+			BackupID:           2,
+			IncludedNamespaces: []string{"shop"},
+			NamespaceMapping:   map[string]string{"shop": "verify-2-shop"},
+		},
+	).Return(&RestoreServicesActivityOutput{
+		Results: migration.NewMigrationResults(&api.RestoreResults{
+			Warnings: arkAPI.RestoreResult{
+				Namespaces: map[string][]string{"verify-2-shop": {`not restored: services "web" already exists`}},
+			},
+		}),
+	}, nil).Once()
+	s.onCheckReadiness(3, scratch, true, Readiness{
+		Workloads: 2,
+		Unready:   []string{},
+		Skipped:   []string{"statefulset verify-2-shop/db: uses persistent volume claims"},
+	})
+	s.onCleanup(3, scratch)
+
+	s.env.ExecuteWorkflow(VerificationWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	s.Len(s.results, 1)
+
+	final := s.final()
+	s.Equal(api.BackupVerificationStatusPassed, final.Status)
+	s.Equal("2 of 2 restored workloads became ready; 1 workloads using persistent volumes were not verified; "+
+		"ingresses, jobs and cron jobs are not restored into the backed up cluster, services are restored as ClusterIP services", final.Message)
+	s.Empty(final.Problems)
+	s.Equal(uint(3), final.TargetClusterID)
+	s.NotNil(final.FinishedAt)
+}
+
+func (s *WorkflowTestSuite) Test_TargetCluster_Failed() {
+	input := testWorkflowInput
+	input.TargetClusterID = 4
+
+	restorePVs := false
+
+	s.env.OnActivity(
+		migration.PrepareTargetActivityName,
+		mock.Anything,
+		migration.PrepareTargetActivityInput{
+			OrganizationID:  1,
+			SourceClusterID: 3,
+			TargetClusterID: 4,
+			BackupName:      "daily-20191119",
+		},
+	).Return(&migration.PrepareTargetActivityOutput{
+		BucketID:    5,
+		ArkDeployed: true,
+		RestorePVs:  &restorePVs,
+		Warnings:    []string{"volumes are not restored"},
+	}, nil).Once()
+	s.onPlan(false, PlanActivityOutput{
+		IncludedNamespaces: []string{"shop"},
+		Namespaces:         []string{"shop"},
+	})
+	s.onRestore(migration.RestoreActivityInput{
+		OrganizationID: 1,
+		ClusterID:      4,
+		BackupName:     "daily-20191119",
+		Options: api.MigrationOptions{
+			IncludedNamespaces: []string{"shop"},
+			RestorePVs:         &restorePVs,
+		},
+		Labels: map[string]string{"pipeline-verification-id": "2-1574150400"},
+	}, &migration.RestoreActivityOutput{
+		RestoreName: "daily-20191119-20191119080000",
+		Results: migration.NewMigrationResults(&api.RestoreResults{
+			Errors: arkAPI.RestoreResult{
+				Namespaces: map[string][]string{"shop": {"error restoring services/shop/db"}},
+			},
+		}),
+	}, nil)
+	s.onCheckReadiness(4, []string{"shop"}, false, Readiness{
+		Workloads: 2,
+		Unready:   []string{"deployment shop/web: 0 of 2 ready"},
+		Skipped:   []string{},
+	})
+	s.onCleanup(4, nil)
+	s.env.OnActivity(
+		migration.RemoveDeploymentActivityName,
+		mock.Anything,
+		migration.RemoveDeploymentActivityInput{OrganizationID: 1, ClusterID: 4},
+	).Return(nil).Once()
+
+	s.env.ExecuteWorkflow(VerificationWorkflowName, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	final := s.final()
+	s.Equal(api.BackupVerificationStatusFailed, final.Status)
+	s.Equal("1 of 2 restored workloads became ready; restore completed with 1 errors; volumes are not restored", final.Message)
+	s.Equal([]string{
+		"restore error in shop: error restoring services/shop/db",
+		"deployment shop/web: 0 of 2 ready",
+	}, final.Problems)
+	s.Equal(uint(4), final.TargetClusterID)
+}
+
+func (s *WorkflowTestSuite) Test_RestoreFailed_TearsDown() {
+	disabled := false
+	scratch := []string{"verify-2-shop"}
+
+	s.onPlan(true, PlanActivityOutput{
+		IncludedNamespaces: []string{"shop"},
+		NamespaceMapping:   map[string]string{"shop": "verify-2-shop"},
+		Namespaces:         scratch,
+	})
+	s.onRestore(migration.RestoreActivityInput{
+		OrganizationID: 1,
+		ClusterID:      3,
+		BackupName:     "daily-20191119",
+		Options: api.MigrationOptions{
+			IncludedNamespaces: []string{"shop"},
+			NamespaceMapping:   map[string]string{"shop": "verify-2-shop"},
+			RestorePVs:         &disabled,
+		},
+		Labels:                  map[string]string{"pipeline-verification-id": "2-1574150400"},
+		ExcludedResources:       []string{"ingresses", "jobs", "cronjobs", "services"},
+		IncludeClusterResources: &disabled,
+	}, nil, errors.New("restore FailedValidation"))
+	s.onCleanup(3, scratch)
+
+	s.env.ExecuteWorkflow(VerificationWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+
+	final := s.final()
+	s.Equal(api.BackupVerificationStatusFailed, final.Status)
+	s.Contains(final.Message, "restore FailedValidation")
+}
+
+func (s *WorkflowTestSuite) Test_NothingToVerify() {
+	s.onPlan(true, PlanActivityOutput{
+		IncludedNamespaces: []string{},
+		NamespaceMapping:   map[string]string{},
+		Namespaces:         []string{},
+	})
+	s.onCleanup(3, nil)
+
+	s.env.ExecuteWorkflow(VerificationWorkflowName, testWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+
+	final := s.final()
+	s.Equal(api.BackupVerificationStatusFailed, final.Status)
+	s.Equal("backup does not contain any namespace to verify", final.Message)
+}
@@ -162,6 +162,44 @@ func (c *Clusters) FindBySecret(organizationID uint, secretID string) ([]*model.
 	return clusters, nil
 }
 
+// GetLabels returns the user-defined labels of a cluster.
+func (c *Clusters) GetLabels(clusterID uint) (map[string]string, error) {
+	var labelModels []model.ClusterLabelModel
+
+	err := c.db.Find(&labelModels, map[string]interface{}{"cluster_id": clusterID}).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch cluster labels", "clusterID", clusterID)
+	}
+
+	labels := make(map[string]string, len(labelModels))
+	for _, label := range labelModels {
+		labels[label.Name] = label.Value
+	}
+
+	return labels, nil
+}
+
+// SetLabels replaces the user-defined labels of a cluster.
+func (c *Clusters) SetLabels(clusterID uint, labels map[string]string) error {
+	tx := c.db.Begin()
+
+	err := tx.Delete(model.ClusterLabelModel{}, map[string]interface{}{"cluster_id": clusterID}).Error
+	if err != nil {
+		tx.Rollback()
+		return emperror.WrapWith(err, "could not delete cluster labels", "clusterID", clusterID)
+	}
+
+	for name, value := range labels {
+		err := tx.Create(&model.ClusterLabelModel{ClusterID: clusterID, Name: name, Value: value}).Error
+		if err != nil {
+			tx.Rollback()
+			return emperror.WrapWith(err, "could not save cluster label", "clusterID", clusterID, "label", name)
+		}
+	}
+
+	return emperror.WrapWith(tx.Commit().Error, "could not save cluster labels", "clusterID", clusterID)
+}
+
 // GetConfigSecretIDByClusterID returns the kubeconfig's secretID stored in DB
 func (c *Clusters) GetConfigSecretIDByClusterID(organizationID uint, clusterID uint) (string, error) {
 	cluster := model.ClusterModel{ID: clusterID}
@@ -0,0 +1,75 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+)
+
+// ClusterDomainGetter returns the domain of clusters with an active DNS feature
+type ClusterDomainGetter struct {
+	featureRepository clusterfeature.FeatureRepository
+	featureManager    FeatureManager
+}
+
+// NewClusterDomainGetter returns a new ClusterDomainGetter
+func NewClusterDomainGetter(featureRepository clusterfeature.FeatureRepository, featureManager FeatureManager) ClusterDomainGetter {
+	return ClusterDomainGetter{
+		featureRepository: featureRepository,
+		featureManager:    featureManager,
+	}
+}
+
+// GetClusterDomain returns the domain of a cluster, or an empty string if the DNS feature is not active on the cluster
+func (g ClusterDomainGetter) GetClusterDomain(ctx context.Context, clusterID uint) (string, error) {
+	feature, err := g.featureRepository.GetFeature(ctx, clusterID, FeatureName)
+	if err != nil {
+		if clusterfeature.IsFeatureNotFoundError(err) {
+			return "", nil
+		}
+
+		return "", errors.WrapIf(err, "failed to get DNS feature")
+	}
+
+	if feature.Status != clusterfeature.FeatureStatusActive {
+		return "", nil
+	}
+
+	spec, err := bindFeatureSpec(feature.Spec)
+	if err != nil {
+		return "", err
+	}
+
+	if spec.CustomDNS.Enabled {
+		return spec.CustomDNS.ClusterDomain, nil
+	}
+
+	output, err := g.featureManager.GetOutput(ctx, clusterID, feature.Spec)
+	if err != nil {
+		return "", err
+	}
+
+	if autoDNS, ok := output["autoDns"].(map[string]interface{}); ok {
+		if clusterDomain, ok := autoDNS["clusterDomain"].(string); ok {
+			return clusterDomain, nil
+		}
+	}
+
+	return "", nil
+}
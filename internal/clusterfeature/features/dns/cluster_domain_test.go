@@ -0,0 +1,90 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+)
+
+func TestClusterDomainGetter_GetClusterDomain(t *testing.T) {
+	clusterGetter := dummyClusterGetter{
+		Clusters: map[uint]dummyCluster{
+			1: {Name: "auto"},
+			2: {Name: "custom"},
+			3: {Name: "pending"},
+			4: {Name: "none"},
+		},
+	}
+	orgDomainService := dummyOrgDomainService{
+		Domain: "the.domain",
+		OrgID:  13,
+	}
+
+	featureRepository := clusterfeature.NewInMemoryFeatureRepository(map[uint][]clusterfeature.Feature{
+		1: {
+			{
+				Name: FeatureName,
+				Spec: clusterfeature.FeatureSpec{
+					"autoDns": map[string]interface{}{"enabled": true},
+				},
+				Status: clusterfeature.FeatureStatusActive,
+			},
+		},
+		2: {
+			{
+				Name: FeatureName,
+				Spec: clusterfeature.FeatureSpec{
+					"customDns": map[string]interface{}{
+						"enabled":       true,
+						"domainFilters": []string{"example.com"},
+						"clusterDomain": "custom.example.com",
+					},
+				},
+				Status: clusterfeature.FeatureStatusActive,
+			},
+		},
+		3: {
+			{
+				Name: FeatureName,
+				Spec: clusterfeature.FeatureSpec{
+					"autoDns": map[string]interface{}{"enabled": true},
+				},
+				Status: clusterfeature.FeatureStatusPending,
+			},
+		},
+	})
+
+	getter := NewClusterDomainGetter(featureRepository, MakeFeatureManager(clusterGetter, nil, orgDomainService))
+
+	tests := map[uint]string{
+		1: "auto.the.domain",
+		2: "custom.example.com",
+		3: "",
+		4: "",
+	}
+
+	for clusterID, expected := range tests {
+		domain, err := getter.GetClusterDomain(context.Background(), clusterID)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, domain)
+	}
+}
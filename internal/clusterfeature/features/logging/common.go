@@ -0,0 +1,69 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+)
+
+const (
+	featureName                = "logging"
+	loggingOperatorReleaseName = "logging-operator"
+	loggingReleaseName         = "logging-operator-logging"
+	fluentdSecretName          = "logging-fluentd-tls"
+	fluentbitSecretName        = "logging-fluentbit-tls"
+	outputsSecretName          = "logging-outputs"
+
+	outputTypeS3            = "s3"
+	outputTypeGCS           = "gcs"
+	outputTypeAzure         = "azure"
+	outputTypeElasticsearch = "elasticsearch"
+	outputTypeLoki          = "loki"
+	outputTypeHTTP          = "http"
+
+	// legacyFluentReleaseName is only installed by the logging post hook (cluster.InstallLogging),
+	// it marks the clusters where the post hook installed the logging operator under loggingOperatorReleaseName
+	legacyFluentReleaseName = "logging-operator-fluent"
+)
+
+// legacyOutputReleaseNames are the output releases installed by the logging post hook
+// nolint: gochecknoglobals
+var legacyOutputReleaseNames = []string{
+	"pipeline-s3-output",
+	"pipeline-gcs-output",
+	"pipeline-oss-output",
+	"pipeline-azure-output",
+}
+
+func getClusterNameSecretTag(clusterName string) string {
+	return fmt.Sprintf("cluster:%s", clusterName)
+}
+
+func getClusterUIDSecretTag(clusterUID string) string {
+	return fmt.Sprintf("clusterUID:%s", clusterUID)
+}
+
+func getReleaseSecretTag() string {
+	return fmt.Sprintf("release:%s", loggingOperatorReleaseName)
+}
+
+func getTLSSecretName(clusterID uint) string {
+	return fmt.Sprintf("logging-tls-%d", clusterID)
+}
+
+// getOutputSecretKey returns the key under which a credential of an output is stored in the outputs secret
+func getOutputSecretKey(outputName string, key string) string {
+	return fmt.Sprintf("%s.%s", outputName, key)
+}
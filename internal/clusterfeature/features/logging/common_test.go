@@ -0,0 +1,112 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	legacyHelm "github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
+	"github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+type obj = map[string]interface{}
+
+type dummyClusterGetter struct {
+	Clusters map[uint]dummyCluster
+}
+
+func (d dummyClusterGetter) GetClusterByIDOnly(ctx context.Context, clusterID uint) (clusterfeatureadapter.Cluster, error) {
+	return d.Clusters[clusterID], nil
+}
+
+func (d dummyClusterGetter) GetClusterStatus(ctx context.Context, clusterID uint) (string, error) {
+	if c, ok := d.Clusters[clusterID]; ok {
+		return c.Status, nil
+	}
+	return "", errors.New("cluster not found")
+}
+
+type dummyCluster struct {
+	K8sConfig []byte
+	Name      string
+	OrgID     uint
+	ID        uint
+	UID       string
+	NodePools map[string]bool
+	Rbac      bool
+	Status    string
+}
+
+func (d dummyCluster) GetK8sConfig() ([]byte, error) {
+	return d.K8sConfig, nil
+}
+
+func (d dummyCluster) GetName() string {
+	return d.Name
+}
+
+func (d dummyCluster) GetOrganizationId() uint {
+	return d.OrgID
+}
+
+func (d dummyCluster) GetUID() string {
+	return d.UID
+}
+
+func (d dummyCluster) GetID() uint {
+	return d.ID
+}
+
+func (d dummyCluster) NodePoolExists(nodePoolName string) bool {
+	return d.NodePools[nodePoolName]
+}
+
+func (d dummyCluster) RbacEnabled() bool {
+	return d.Rbac
+}
+
+type dummyHelmService struct {
+	Deployments map[string]*helm.GetDeploymentResponse
+	Deleted     map[string]bool
+}
+
+func (d dummyHelmService) ApplyDeployment(
+	ctx context.Context,
+	clusterID uint,
+	namespace string,
+	deploymentName string,
+	releaseName string,
+	values []byte,
+	chartVersion string,
+) error {
+	return nil
+}
+
+func (d dummyHelmService) DeleteDeployment(ctx context.Context, clusterID uint, releaseName string) error {
+	if d.Deleted != nil {
+		d.Deleted[releaseName] = true
+	}
+	return nil
+}
+
+func (d dummyHelmService) GetDeployment(ctx context.Context, clusterID uint, releaseName string) (*helm.GetDeploymentResponse, error) {
+	if deployment, ok := d.Deployments[releaseName]; ok {
+		return deployment, nil
+	}
+	return nil, &legacyHelm.DeploymentNotFoundError{HelmError: errors.New("deployment not found")}
+}
@@ -0,0 +1,144 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/features"
+	"github.com/banzaicloud/pipeline/internal/common"
+)
+
+// defaultFlowName is the name of the flow routing every log to every output when the spec contains no flows
+const defaultFlowName = "all"
+
+// FeatureManager implements the Logging feature manager
+type FeatureManager struct {
+	helmService features.HelmService
+	config      Configuration
+	logger      common.Logger
+}
+
+func MakeFeatureManager(
+	helmService features.HelmService,
+	config Configuration,
+	logger common.Logger,
+) FeatureManager {
+	return FeatureManager{
+		helmService: helmService,
+		config:      config,
+		logger:      logger,
+	}
+}
+
+// Name returns the feature's name
+func (FeatureManager) Name() string {
+	return featureName
+}
+
+// GetOutput returns the Logging feature's output
+func (m FeatureManager) GetOutput(ctx context.Context, clusterID uint, spec clusterfeature.FeatureSpec) (clusterfeature.FeatureOutput, error) {
+	operatorDeployment, err := m.helmService.GetDeployment(ctx, clusterID, loggingOperatorReleaseName)
+	if err != nil {
+		m.logger.Warn(fmt.Sprintf("failed to get deployment details: %s", err.Error()))
+	}
+
+	loggingDeployment, err := m.helmService.GetDeployment(ctx, clusterID, loggingReleaseName)
+	if err != nil {
+		m.logger.Warn(fmt.Sprintf("failed to get logging deployment details: %s", err.Error()))
+	}
+
+	chartVersion := m.config.operator.chartVersion
+	if operatorDeployment != nil && operatorDeployment.ChartVersion != "" {
+		chartVersion = operatorDeployment.ChartVersion
+	}
+
+	var loggingValues map[string]interface{}
+	if loggingDeployment != nil {
+		loggingValues = loggingDeployment.Values
+	}
+
+	loggingOutput, err := getLoggingOutput(loggingValues)
+	if err != nil {
+		m.logger.Warn(fmt.Sprintf("failed to get active outputs and flows: %s", err.Error()))
+		loggingOutput = map[string]interface{}{}
+	}
+
+	out := clusterfeature.FeatureOutput{
+		"loggingOperator": map[string]interface{}{
+			versionKey: chartVersion,
+		},
+		outputsKey: loggingOutput[outputsKey],
+		flowsKey:   loggingOutput[flowsKey],
+	}
+
+	return out, nil
+}
+
+// ValidateSpec validates a Logging feature specification
+func (FeatureManager) ValidateSpec(ctx context.Context, spec clusterfeature.FeatureSpec) error {
+	boundSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+		return clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     err.Error(),
+		}
+	}
+
+	if err := boundSpec.Validate(); err != nil {
+		return clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// PrepareSpec makes certain preparations to the spec before it's sent to be applied
+func (FeatureManager) PrepareSpec(ctx context.Context, spec clusterfeature.FeatureSpec) (clusterfeature.FeatureSpec, error) {
+	boundSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+		return nil, clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     err.Error(),
+		}
+	}
+
+	if len(boundSpec.Flows) > 0 {
+		return spec, nil
+	}
+
+	// route every log to every output if no flows are specified
+	outputNames := make([]interface{}, 0, len(boundSpec.Outputs))
+	for _, output := range boundSpec.Outputs {
+		outputNames = append(outputNames, output.Name)
+	}
+
+	preparedSpec := make(clusterfeature.FeatureSpec, len(spec)+1)
+	for key, value := range spec {
+		preparedSpec[key] = value
+	}
+	preparedSpec[flowsKey] = []interface{}{
+		map[string]interface{}{
+			nameKey:    defaultFlowName,
+			outputsKey: outputNames,
+		},
+	}
+
+	return preparedSpec, nil
+}
@@ -0,0 +1,253 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+	"github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+func TestFeatureManager_Name(t *testing.T) {
+	mng := MakeFeatureManager(nil, NewFeatureConfiguration(), nil)
+
+	assert.Equal(t, "logging", mng.Name())
+}
+
+func TestFeatureManager_GetOutput(t *testing.T) {
+	clusterID := uint(42)
+
+	helmService := dummyHelmService{
+		Deployments: map[string]*helm.GetDeploymentResponse{
+			loggingOperatorReleaseName: {
+				ReleaseName:  loggingOperatorReleaseName,
+				ChartVersion: "3.0.0",
+			},
+			loggingReleaseName: {
+				ReleaseName: loggingReleaseName,
+				Values: obj{
+					"controlNamespace": "pipeline-system",
+					"clusterOutputs": []interface{}{
+						obj{"name": "archive", "spec": obj{"s3": obj{"s3_bucket": "logs", "s3_region": "eu-west-1"}}},
+						obj{"name": "search", "spec": obj{"elasticsearch": obj{"host": "es.example.com"}}},
+					},
+					"clusterFlows": []interface{}{
+						obj{"name": "all", "spec": obj{"globalOutputRefs": []interface{}{"archive"}}},
+						obj{"name": "apps", "spec": obj{
+							"globalOutputRefs": []interface{}{"search"},
+							"match": []interface{}{
+								obj{"select": obj{"namespaces": []interface{}{"default"}, "labels": obj{"app": "web"}}},
+							},
+						}},
+					},
+				},
+			},
+		},
+	}
+	mng := MakeFeatureManager(helmService, Configuration{}, commonadapter.NewNoopLogger())
+
+	output, err := mng.GetOutput(context.Background(), clusterID, clusterfeature.FeatureSpec{})
+	assert.NoError(t, err)
+	assert.Equal(t, clusterfeature.FeatureOutput{
+		"loggingOperator": obj{
+			"version": "3.0.0",
+		},
+		"outputs": []map[string]interface{}{
+			{"name": "archive", "type": "s3"},
+			{"name": "search", "type": "elasticsearch"},
+		},
+		"flows": []map[string]interface{}{
+			{"name": "all", "outputs": []string{"archive"}, "namespaces": []string(nil), "labels": map[string]string(nil)},
+			{"name": "apps", "outputs": []string{"search"}, "namespaces": []string{"default"}, "labels": map[string]string{"app": "web"}},
+		},
+	}, output)
+}
+
+func TestFeatureManager_GetOutput_NotDeployed(t *testing.T) {
+	config := Configuration{}
+	config.operator.chartVersion = "3.0.0"
+	mng := MakeFeatureManager(dummyHelmService{}, config, commonadapter.NewNoopLogger())
+
+	output, err := mng.GetOutput(context.Background(), 42, clusterfeature.FeatureSpec{})
+	assert.NoError(t, err)
+	assert.Equal(t, clusterfeature.FeatureOutput{
+		"loggingOperator": obj{
+			"version": "3.0.0",
+		},
+		"outputs": []map[string]interface{}{},
+		"flows":   []map[string]interface{}{},
+	}, output)
+}
+
+func TestFeatureManager_ValidateSpec(t *testing.T) {
+	mng := MakeFeatureManager(nil, Configuration{}, nil)
+
+	cases := map[string]struct {
+		Spec  clusterfeature.FeatureSpec
+		Error bool
+	}{
+		"valid spec": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "archive", "s3": obj{"secretId": "s3secret", "bucket": "logs", "region": "eu-west-1"}},
+					obj{"name": "gcs", "gcs": obj{"secretId": "gsecret", "bucket": "logs"}},
+					obj{"name": "blob", "azure": obj{"secretId": "asecret", "resourceGroup": "rg", "storageAccount": "sa", "container": "logs"}},
+					obj{"name": "search", "elasticsearch": obj{"host": "es.example.com", "port": 9200, "scheme": "https"}},
+					obj{"name": "loki", "loki": obj{"url": "http://loki:3100"}},
+					obj{"name": "webhook", "http": obj{"endpoint": "https://logs.example.com"}},
+				},
+				"flows": []interface{}{
+					obj{"name": "apps", "outputs": []interface{}{"search", "loki"}, "namespaces": []interface{}{"default"}, "labels": obj{"app.kubernetes.io/name": "web"}},
+				},
+			},
+		},
+		"no outputs": {
+			Spec:  clusterfeature.FeatureSpec{},
+			Error: true,
+		},
+		"output without type": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "archive"},
+				},
+			},
+			Error: true,
+		},
+		"output with multiple types": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "archive", "loki": obj{"url": "http://loki:3100"}, "http": obj{"endpoint": "https://logs.example.com"}},
+				},
+			},
+			Error: true,
+		},
+		"invalid output name": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "Archive_1", "loki": obj{"url": "http://loki:3100"}},
+				},
+			},
+			Error: true,
+		},
+		"duplicate output name": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "loki", "loki": obj{"url": "http://loki:3100"}},
+					obj{"name": "loki", "loki": obj{"url": "http://loki2:3100"}},
+				},
+			},
+			Error: true,
+		},
+		"missing bucket": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "archive", "s3": obj{"secretId": "s3secret", "region": "eu-west-1"}},
+				},
+			},
+			Error: true,
+		},
+		"invalid elasticsearch scheme": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "search", "elasticsearch": obj{"host": "es.example.com", "scheme": "ftp"}},
+				},
+			},
+			Error: true,
+		},
+		"flow with undefined output": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "loki", "loki": obj{"url": "http://loki:3100"}},
+				},
+				"flows": []interface{}{
+					obj{"name": "apps", "outputs": []interface{}{"search"}},
+				},
+			},
+			Error: true,
+		},
+		"flow without outputs": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "loki", "loki": obj{"url": "http://loki:3100"}},
+				},
+				"flows": []interface{}{
+					obj{"name": "apps"},
+				},
+			},
+			Error: true,
+		},
+		"flow with invalid label": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": []interface{}{
+					obj{"name": "loki", "loki": obj{"url": "http://loki:3100"}},
+				},
+				"flows": []interface{}{
+					obj{"name": "apps", "outputs": []interface{}{"loki"}, "labels": obj{"app": "not a valid value"}},
+				},
+			},
+			Error: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := mng.ValidateSpec(context.Background(), tc.Spec)
+			if tc.Error {
+				assert.Error(t, err)
+				assert.IsType(t, clusterfeature.InvalidFeatureSpecError{}, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFeatureManager_PrepareSpec(t *testing.T) {
+	mng := MakeFeatureManager(nil, Configuration{}, nil)
+
+	outputs := []interface{}{
+		obj{"name": "archive", "s3": obj{"secretId": "s3secret", "bucket": "logs", "region": "eu-west-1"}},
+		obj{"name": "loki", "loki": obj{"url": "http://loki:3100"}},
+	}
+
+	t.Run("default flow", func(t *testing.T) {
+		spec, err := mng.PrepareSpec(context.Background(), clusterfeature.FeatureSpec{"outputs": outputs})
+		assert.NoError(t, err)
+		assert.Equal(t, clusterfeature.FeatureSpec{
+			"outputs": outputs,
+			"flows": []interface{}{
+				obj{"name": "all", "outputs": []interface{}{"archive", "loki"}},
+			},
+		}, spec)
+	})
+
+	t.Run("explicit flows", func(t *testing.T) {
+		original := clusterfeature.FeatureSpec{
+			"outputs": outputs,
+			"flows": []interface{}{
+				obj{"name": "apps", "outputs": []interface{}{"loki"}},
+			},
+		}
+
+		spec, err := mng.PrepareSpec(context.Background(), original)
+		assert.NoError(t, err)
+		assert.Equal(t, original, spec)
+	})
+}
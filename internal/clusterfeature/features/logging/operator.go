@@ -0,0 +1,644 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"emperror.dev/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/banzaicloud/pipeline/auth"
+	pkgCluster "github.com/banzaicloud/pipeline/cluster"
+	legacyHelm "github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/features"
+	"github.com/banzaicloud/pipeline/internal/common"
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/pkg/providers/azure"
+	azureObjectstore "github.com/banzaicloud/pipeline/pkg/providers/azure/objectstore"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// keys of the credentials stored in the outputs secret
+const (
+	awsAccessKeyIDKey     = "awsAccessKeyId"
+	awsSecretAccessKeyKey = "awsSecretAccessKey"
+	gcsCredentialsKey     = "credentials.json"
+	azureStorageAccount   = "storageAccount"
+	azureStorageKey       = "storageAccountKey"
+	usernameKey           = "username"
+	passwordKey           = "password"
+)
+
+// FeatureOperator implements the Logging feature operator
+type FeatureOperator struct {
+	clusterGetter     clusterfeatureadapter.ClusterGetter
+	clusterService    clusterfeature.ClusterService
+	helmService       features.HelmService
+	kubernetesService features.KubernetesService
+	config            Configuration
+	logger            common.Logger
+	secretStore       features.SecretStore
+}
+
+// MakeFeatureOperator returns a Logging feature operator
+func MakeFeatureOperator(
+	clusterGetter clusterfeatureadapter.ClusterGetter,
+	clusterService clusterfeature.ClusterService,
+	helmService features.HelmService,
+	kubernetesService features.KubernetesService,
+	config Configuration,
+	logger common.Logger,
+	secretStore features.SecretStore,
+) FeatureOperator {
+	return FeatureOperator{
+		clusterGetter:     clusterGetter,
+		clusterService:    clusterService,
+		helmService:       helmService,
+		kubernetesService: kubernetesService,
+		config:            config,
+		logger:            logger,
+		secretStore:       secretStore,
+	}
+}
+
+// Name returns the name of the Logging feature
+func (FeatureOperator) Name() string {
+	return featureName
+}
+
+// Apply applies the provided specification to the cluster feature
+func (op FeatureOperator) Apply(ctx context.Context, clusterID uint, spec clusterfeature.FeatureSpec) error {
+	if err := op.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := op.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	logger := op.logger.WithContext(ctx).WithFields(map[string]interface{}{"cluster": clusterID, "feature": featureName})
+
+	boundSpec, err := bindFeatureSpec(spec)
+	if err != nil {
+		return clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     err.Error(),
+		}
+	}
+
+	cluster, err := op.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return errors.WrapIf(err, "failed to get cluster")
+	}
+
+	// generate and install the TLS secrets used between Fluent Bit and Fluentd
+	if err := op.installTLSSecrets(ctx, cluster, logger); err != nil {
+		return errors.WrapIf(err, "failed to install TLS secrets")
+	}
+
+	// the post hook installs an incompatible operator under the same release name
+	if err := op.removeLegacyLogging(ctx, clusterID, logger); err != nil {
+		return errors.WrapIf(err, "failed to remove logging installed by the post hook")
+	}
+
+	// install Logging Operator
+	if err := op.installLoggingOperator(ctx, cluster, logger); err != nil {
+		return errors.WrapIf(err, "failed to install Logging operator")
+	}
+
+	// collect output credentials and install them as a single secret
+	outputs, secretValues, err := op.generateOutputs(ctx, boundSpec)
+	if err != nil {
+		return errors.WrapIf(err, "failed to generate outputs")
+	}
+
+	if err := op.installOutputsSecret(ctx, cluster, secretValues); err != nil {
+		return errors.WrapIf(err, "failed to install outputs secret")
+	}
+
+	// install Logging resources with the outputs and flows
+	if err := op.installLogging(ctx, cluster, logger, outputs, generateFlows(boundSpec)); err != nil {
+		return errors.WrapIf(err, "failed to install Logging resources")
+	}
+
+	return nil
+}
+
+// Deactivate deactivates the cluster feature
+func (op FeatureOperator) Deactivate(ctx context.Context, clusterID uint, spec clusterfeature.FeatureSpec) error {
+	if err := op.clusterService.CheckClusterReady(ctx, clusterID); err != nil {
+		return err
+	}
+
+	ctx, err := op.ensureOrgIDInContext(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	// delete Logging resources first, so that the operator can clean up after them
+	if err := op.helmService.DeleteDeployment(ctx, clusterID, loggingReleaseName); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete deployment", "release", loggingReleaseName)
+	}
+
+	if err := op.helmService.DeleteDeployment(ctx, clusterID, loggingOperatorReleaseName); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete deployment", "release", loggingOperatorReleaseName)
+	}
+
+	for _, secretName := range []string{outputsSecretName, fluentdSecretName, fluentbitSecretName} {
+		kubeSecret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: op.config.pipelineSystemNamespace}}
+		if err := op.kubernetesService.DeleteObject(ctx, clusterID, kubeSecret); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to delete kubernetes secret", "secret", secretName)
+		}
+	}
+
+	if err := op.deleteTLSSecret(ctx, clusterID); err != nil && !isSecretNotFoundError(err) {
+		return errors.WrapIf(err, "failed to delete TLS secret")
+	}
+
+	return nil
+}
+
+func (op FeatureOperator) installTLSSecrets(ctx context.Context, cluster clusterfeatureadapter.Cluster, logger common.Logger) error {
+	tlsSecretName := getTLSSecretName(cluster.GetID())
+	pipelineSystemNamespace := op.config.pipelineSystemNamespace
+
+	tlsSecretRequest := &secret.CreateSecretRequest{
+		Name: tlsSecretName,
+		Type: secrettype.TLSSecretType,
+		Values: map[string]string{
+			secrettype.TLSHosts: fmt.Sprintf("fluentd.%s.svc.cluster.local", pipelineSystemNamespace),
+		},
+		Tags: []string{
+			getClusterNameSecretTag(cluster.GetName()),
+			getClusterUIDSecretTag(cluster.GetUID()),
+			secret.TagBanzaiReadonly,
+			getReleaseSecretTag(),
+		},
+	}
+
+	// reuse the already generated certificates on reconfiguration
+	if _, err := secret.Store.GetOrCreate(cluster.GetOrganizationId(), tlsSecretRequest); err != nil {
+		return errors.WrapIf(err, "failed to generate TLS secret")
+	}
+	logger.Debug("TLS secret stored")
+
+	fluentdSecretRequest := pkgCluster.InstallSecretRequest{
+		SourceSecretName: tlsSecretName,
+		Namespace:        pipelineSystemNamespace,
+		Spec: map[string]pkgCluster.InstallSecretRequestSpecItem{
+			"ca.crt":  {Source: secrettype.CACert},
+			"tls.crt": {Source: secrettype.ServerCert},
+			"tls.key": {Source: secrettype.ServerKey},
+		},
+		Update: true,
+	}
+	if _, err := op.installSecret(ctx, cluster.GetID(), fluentdSecretName, fluentdSecretRequest); err != nil {
+		return errors.WrapIf(err, "failed to install Fluentd TLS secret")
+	}
+
+	fluentbitSecretRequest := pkgCluster.InstallSecretRequest{
+		SourceSecretName: tlsSecretName,
+		Namespace:        pipelineSystemNamespace,
+		Spec: map[string]pkgCluster.InstallSecretRequestSpecItem{
+			"ca.crt":  {Source: secrettype.CACert},
+			"tls.crt": {Source: secrettype.ClientCert},
+			"tls.key": {Source: secrettype.ClientKey},
+		},
+		Update: true,
+	}
+	if _, err := op.installSecret(ctx, cluster.GetID(), fluentbitSecretName, fluentbitSecretRequest); err != nil {
+		return errors.WrapIf(err, "failed to install Fluent Bit TLS secret")
+	}
+
+	return nil
+}
+
+// removeLegacyLogging deletes the releases installed by the logging post hook, so that the operator
+// release of the feature is installed from scratch instead of upgrading the one of the post hook
+func (op FeatureOperator) removeLegacyLogging(ctx context.Context, clusterID uint, logger common.Logger) error {
+	_, err := op.helmService.GetDeployment(ctx, clusterID, legacyFluentReleaseName)
+	if errors.As(err, new(*legacyHelm.DeploymentNotFoundError)) {
+		return nil
+	}
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to get deployment", "release", legacyFluentReleaseName)
+	}
+
+	logger.Info("removing logging installed by the post hook")
+
+	releaseNames := append([]string{legacyFluentReleaseName, loggingOperatorReleaseName}, legacyOutputReleaseNames...)
+	for _, releaseName := range releaseNames {
+		if err := op.helmService.DeleteDeployment(ctx, clusterID, releaseName); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to delete deployment", "release", releaseName)
+		}
+	}
+
+	return nil
+}
+
+func (op FeatureOperator) installLoggingOperator(ctx context.Context, cluster clusterfeatureadapter.Cluster, logger common.Logger) error {
+	var chartValues = &loggingOperatorValues{
+		Affinity:    GetHeadNodeAffinity(cluster, op.config),
+		Tolerations: GetHeadNodeTolerations(op.config),
+	}
+
+	valuesBytes, err := json.Marshal(chartValues)
+	if err != nil {
+		logger.Debug("failed to marshal chartValues")
+		return errors.WrapIf(err, "failed to decode chartValues")
+	}
+
+	return op.helmService.ApplyDeployment(
+		ctx,
+		cluster.GetID(),
+		op.config.pipelineSystemNamespace,
+		op.config.operator.chartName,
+		loggingOperatorReleaseName,
+		valuesBytes,
+		op.config.operator.chartVersion,
+	)
+}
+
+func (op FeatureOperator) installLogging(
+	ctx context.Context,
+	cluster clusterfeatureadapter.Cluster,
+	logger common.Logger,
+	outputs []clusterOutputValues,
+	flows []clusterFlowValues,
+) error {
+	var chartValues = &loggingValues{
+		ControlNamespace: op.config.pipelineSystemNamespace,
+		TLS: tlsValues{
+			Enabled:             true,
+			FluentdSecretName:   fluentdSecretName,
+			FluentbitSecretName: fluentbitSecretName,
+		},
+		ClusterOutputs: outputs,
+		ClusterFlows:   flows,
+	}
+
+	valuesBytes, err := json.Marshal(chartValues)
+	if err != nil {
+		logger.Debug("failed to marshal chartValues")
+		return errors.WrapIf(err, "failed to decode chartValues")
+	}
+
+	return op.helmService.ApplyDeployment(
+		ctx,
+		cluster.GetID(),
+		op.config.pipelineSystemNamespace,
+		op.config.logging.chartName,
+		loggingReleaseName,
+		valuesBytes,
+		op.config.logging.chartVersion,
+	)
+}
+
+// installOutputsSecret installs the credentials of all outputs to the cluster, replacing the ones of removed outputs
+func (op FeatureOperator) installOutputsSecret(ctx context.Context, cluster clusterfeatureadapter.Cluster, values map[string]string) error {
+	spec := make(map[string]pkgCluster.InstallSecretRequestSpecItem, len(values))
+	for key, value := range values {
+		spec[key] = pkgCluster.InstallSecretRequestSpecItem{Value: value}
+	}
+
+	installSecretRequest := pkgCluster.InstallSecretRequest{
+		Namespace: op.config.pipelineSystemNamespace,
+		Spec:      spec,
+		Update:    true,
+	}
+
+	if _, err := op.installSecret(ctx, cluster.GetID(), outputsSecretName, installSecretRequest); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to install outputs secret to cluster", "clusterID", cluster.GetID())
+	}
+
+	return nil
+}
+
+// generateOutputs returns the cluster output chart values and the credentials they refer to
+func (op FeatureOperator) generateOutputs(ctx context.Context, spec featureSpec) ([]clusterOutputValues, map[string]string, error) {
+	outputs := make([]clusterOutputValues, 0, len(spec.Outputs))
+	secretValues := make(map[string]string)
+
+	for _, output := range spec.Outputs {
+		values, credentials, err := op.generateOutput(ctx, output)
+		if err != nil {
+			return nil, nil, errors.WrapIfWithDetails(err, "failed to generate output", "output", output.Name)
+		}
+
+		for key, value := range credentials {
+			secretValues[getOutputSecretKey(output.Name, key)] = value
+		}
+
+		outputs = append(outputs, clusterOutputValues{
+			Name: output.Name,
+			Spec: values,
+		})
+	}
+
+	return outputs, secretValues, nil
+}
+
+func (op FeatureOperator) generateOutput(ctx context.Context, output outputSpec) (outputSpecValues, map[string]string, error) {
+	ref := func(key string) *secretValue {
+		return newSecretValue(getOutputSecretKey(output.Name, key))
+	}
+
+	switch output.Type() {
+	case outputTypeS3:
+		values, err := op.secretStore.GetSecretValues(ctx, output.S3.SecretID)
+		if err != nil {
+			return outputSpecValues{}, nil, errors.WrapIf(err, "failed to get S3 secret")
+		}
+
+		return outputSpecValues{
+			S3: &s3OutputValues{
+				AwsKeyID:  ref(awsAccessKeyIDKey),
+				AwsSecKey: ref(awsSecretAccessKeyKey),
+				Bucket:    output.S3.Bucket,
+				Region:    output.S3.Region,
+				Path:      output.S3.Path,
+			},
+		}, map[string]string{
+			awsAccessKeyIDKey:     values[secrettype.AwsAccessKeyId],
+			awsSecretAccessKeyKey: values[secrettype.AwsSecretAccessKey],
+		}, nil
+
+	case outputTypeGCS:
+		values, err := op.secretStore.GetSecretValues(ctx, output.GCS.SecretID)
+		if err != nil {
+			return outputSpecValues{}, nil, errors.WrapIf(err, "failed to get GCS secret")
+		}
+
+		// the Google secret values are the fields of a service account key file
+		credentials, err := json.Marshal(values)
+		if err != nil {
+			return outputSpecValues{}, nil, errors.WrapIf(err, "failed to marshal GCS credentials")
+		}
+
+		return outputSpecValues{
+			GCS: &gcsOutputValues{
+				Project:         values[secrettype.ProjectId],
+				CredentialsJSON: ref(gcsCredentialsKey),
+				Bucket:          output.GCS.Bucket,
+				Path:            output.GCS.Path,
+			},
+		}, map[string]string{
+			gcsCredentialsKey: string(credentials),
+		}, nil
+
+	case outputTypeAzure:
+		values, err := op.secretStore.GetSecretValues(ctx, output.Azure.SecretID)
+		if err != nil {
+			return outputSpecValues{}, nil, errors.WrapIf(err, "failed to get Azure secret")
+		}
+
+		storageAccountClient, err := azureObjectstore.NewAuthorizedStorageAccountClientFromSecret(*azure.NewCredentials(values))
+		if err != nil {
+			return outputSpecValues{}, nil, errors.WrapIf(err, "failed to create storage account client")
+		}
+
+		storageAccountKey, err := storageAccountClient.GetStorageAccountKey(output.Azure.ResourceGroup, output.Azure.StorageAccount)
+		if err != nil {
+			return outputSpecValues{}, nil, errors.WrapIf(err, "failed to get storage account key")
+		}
+
+		return outputSpecValues{
+			AzureStorage: &azureStorageOutputValues{
+				StorageAccount: ref(azureStorageAccount),
+				AccessKey:      ref(azureStorageKey),
+				Container:      output.Azure.Container,
+				Path:           output.Azure.Path,
+			},
+		}, map[string]string{
+			azureStorageAccount: output.Azure.StorageAccount,
+			azureStorageKey:     storageAccountKey,
+		}, nil
+
+	case outputTypeElasticsearch:
+		values := outputSpecValues{
+			Elasticsearch: &elasticsearchOutputValues{
+				Host:      output.Elasticsearch.Host,
+				Port:      output.Elasticsearch.Port,
+				Scheme:    output.Elasticsearch.Scheme,
+				IndexName: output.Elasticsearch.Index,
+			},
+		}
+		if output.Elasticsearch.SecretID == "" {
+			return values, nil, nil
+		}
+
+		credentials, err := op.getBasicAuthCredentials(ctx, output.Elasticsearch.SecretID)
+		if err != nil {
+			return outputSpecValues{}, nil, err
+		}
+
+		values.Elasticsearch.User = credentials[usernameKey]
+		values.Elasticsearch.Password = ref(passwordKey)
+
+		return values, map[string]string{passwordKey: credentials[passwordKey]}, nil
+
+	case outputTypeLoki:
+		values := outputSpecValues{
+			Loki: &lokiOutputValues{
+				URL:                       output.Loki.URL,
+				ConfigureKubernetesLabels: true,
+			},
+		}
+		if output.Loki.SecretID == "" {
+			return values, nil, nil
+		}
+
+		credentials, err := op.getBasicAuthCredentials(ctx, output.Loki.SecretID)
+		if err != nil {
+			return outputSpecValues{}, nil, err
+		}
+
+		values.Loki.Username = ref(usernameKey)
+		values.Loki.Password = ref(passwordKey)
+
+		return values, credentials, nil
+
+	case outputTypeHTTP:
+		values := outputSpecValues{
+			HTTP: &httpOutputValues{
+				Endpoint: output.HTTP.Endpoint,
+			},
+		}
+		if output.HTTP.SecretID == "" {
+			return values, nil, nil
+		}
+
+		credentials, err := op.getBasicAuthCredentials(ctx, output.HTTP.SecretID)
+		if err != nil {
+			return outputSpecValues{}, nil, err
+		}
+
+		values.HTTP.Auth = &httpAuthValues{
+			Username: ref(usernameKey),
+			Password: ref(passwordKey),
+		}
+
+		return values, credentials, nil
+	}
+
+	return outputSpecValues{}, nil, errors.Errorf("unsupported output type for output %q", output.Name)
+}
+
+func (op FeatureOperator) getBasicAuthCredentials(ctx context.Context, secretID string) (map[string]string, error) {
+	values, err := op.secretStore.GetSecretValues(ctx, secretID)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get basic auth secret")
+	}
+
+	return map[string]string{
+		usernameKey: values[secrettype.Username],
+		passwordKey: values[secrettype.Password],
+	}, nil
+}
+
+// generateFlows returns the cluster flow chart values of the spec
+func generateFlows(spec featureSpec) []clusterFlowValues {
+	flows := make([]clusterFlowValues, 0, len(spec.Flows))
+	for _, flow := range spec.Flows {
+		values := clusterFlowValues{
+			Name: flow.Name,
+			Spec: clusterFlowSpecValues{
+				OutputRefs: flow.Outputs,
+			},
+		}
+
+		// an empty match selects the logs of every namespace
+		if len(flow.Namespaces) > 0 || len(flow.Labels) > 0 {
+			values.Spec.Match = []matchValues{
+				{
+					Select: &selectValues{
+						Namespaces: flow.Namespaces,
+						Labels:     flow.Labels,
+					},
+				},
+			}
+		}
+
+		flows = append(flows, values)
+	}
+
+	return flows
+}
+
+func newSecretValue(key string) *secretValue {
+	return &secretValue{
+		ValueFrom: valueFromValues{
+			SecretKeyRef: secretKeyRefValues{
+				Name: outputsSecretName,
+				Key:  key,
+			},
+		},
+	}
+}
+
+func (op FeatureOperator) deleteTLSSecret(ctx context.Context, clusterID uint) error {
+	secretID, err := op.secretStore.GetIDByName(ctx, getTLSSecretName(clusterID))
+	if err != nil {
+		return errors.WrapIf(err, "failed to get TLS secret")
+	}
+	return op.secretStore.Delete(ctx, secretID)
+}
+
+func (op FeatureOperator) installSecret(ctx context.Context, clusterID uint, secretName string, secretRequest pkgCluster.InstallSecretRequest) (*secret.K8SSourceMeta, error) {
+	cl, err := op.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to get cluster", "clusterID", clusterID)
+	}
+
+	k8sSec, err := pkgCluster.InstallSecret(cl, secretName, secretRequest)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to install secret to the cluster", "clusterID", clusterID)
+	}
+
+	return k8sSec, nil
+}
+
+func (op FeatureOperator) ensureOrgIDInContext(ctx context.Context, clusterID uint) (context.Context, error) {
+	if _, ok := auth.GetCurrentOrganizationID(ctx); !ok {
+		cluster, err := op.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+		if err != nil {
+			return ctx, errors.WrapIf(err, "failed to get cluster by ID")
+		}
+		ctx = auth.SetCurrentOrganizationID(ctx, cluster.GetOrganizationId())
+	}
+	return ctx, nil
+}
+
+func isSecretNotFoundError(err error) bool {
+	errCause := errors.Cause(err)
+	if errCause == secret.ErrSecretNotExists {
+		return true
+	}
+	return false
+}
+
+func GetHeadNodeAffinity(cluster interface {
+	NodePoolExists(nodePoolName string) bool
+}, config Configuration) v1.Affinity {
+	headNodePoolName := config.headNodepoolName
+	if headNodePoolName == "" {
+		return v1.Affinity{}
+	}
+	if !cluster.NodePoolExists(headNodePoolName) {
+		return v1.Affinity{}
+	}
+	return v1.Affinity{
+		NodeAffinity: &v1.NodeAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []v1.PreferredSchedulingTerm{
+				{
+					Weight: 100,
+					Preference: v1.NodeSelectorTerm{
+						MatchExpressions: []v1.NodeSelectorRequirement{
+							{
+								Key:      pkgCommon.LabelKey,
+								Operator: v1.NodeSelectorOpIn,
+								Values: []string{
+									headNodePoolName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func GetHeadNodeTolerations(config Configuration) []v1.Toleration {
+	headNodePoolName := config.headNodepoolName
+	if headNodePoolName == "" {
+		return []v1.Toleration{}
+	}
+	return []v1.Toleration{
+		{
+			Key:      pkgCommon.NodePoolNameTaintKey,
+			Operator: v1.TolerationOpEqual,
+			Value:    headNodePoolName,
+		},
+	}
+}
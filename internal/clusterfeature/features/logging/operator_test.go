@@ -0,0 +1,197 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+	"github.com/banzaicloud/pipeline/internal/clusterfeature/clusterfeatureadapter"
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+func TestFeatureOperator_Name(t *testing.T) {
+	op := MakeFeatureOperator(nil, nil, nil, nil, NewFeatureConfiguration(), nil, nil)
+
+	assert.Equal(t, "logging", op.Name())
+}
+
+func TestFeatureOperator_Apply(t *testing.T) {
+	clusterID := uint(42)
+	orgID := uint(13)
+
+	clusterGetter := dummyClusterGetter{
+		Clusters: map[uint]dummyCluster{},
+	}
+	clusterService := clusterfeatureadapter.NewClusterService(clusterGetter)
+	logger := commonadapter.NewNoopLogger()
+	op := MakeFeatureOperator(clusterGetter, clusterService, dummyHelmService{}, nil, Configuration{}, logger, nil)
+
+	cases := map[string]struct {
+		Spec    clusterfeature.FeatureSpec
+		Cluster dummyCluster
+		Error   interface{}
+	}{
+		"cluster not ready": {
+			Spec: clusterfeature.FeatureSpec{},
+			Cluster: dummyCluster{
+				OrgID:  orgID,
+				Status: pkgCluster.Creating,
+				ID:     clusterID,
+			},
+			Error: clusterfeature.ClusterIsNotReadyError{
+				ClusterID: clusterID,
+			},
+		},
+		"invalid spec": {
+			Spec: clusterfeature.FeatureSpec{
+				"outputs": "archive",
+			},
+			Cluster: dummyCluster{
+				OrgID:  orgID,
+				Status: pkgCluster.Running,
+				ID:     clusterID,
+			},
+			Error: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			clusterGetter.Clusters[clusterID] = tc.Cluster
+
+			ctx := auth.SetCurrentOrganizationID(context.Background(), orgID)
+
+			err := op.Apply(ctx, clusterID, tc.Spec)
+			switch tc.Error {
+			case nil, false:
+				assert.NoError(t, err)
+			case true:
+				assert.Error(t, err)
+			default:
+				assert.Equal(t, tc.Error, err)
+			}
+		})
+	}
+}
+
+func TestFeatureOperator_RemoveLegacyLogging(t *testing.T) {
+	logger := commonadapter.NewNoopLogger()
+
+	t.Run("installed by the post hook", func(t *testing.T) {
+		helmService := dummyHelmService{
+			Deployments: map[string]*helm.GetDeploymentResponse{
+				"logging-operator":        {ReleaseName: "logging-operator"},
+				"logging-operator-fluent": {ReleaseName: "logging-operator-fluent"},
+				"pipeline-s3-output":      {ReleaseName: "pipeline-s3-output"},
+			},
+			Deleted: map[string]bool{},
+		}
+		op := MakeFeatureOperator(nil, nil, helmService, nil, Configuration{}, logger, nil)
+
+		assert.NoError(t, op.removeLegacyLogging(context.Background(), 42, logger))
+		assert.True(t, helmService.Deleted["logging-operator"])
+		assert.True(t, helmService.Deleted["logging-operator-fluent"])
+		assert.True(t, helmService.Deleted["pipeline-s3-output"])
+	})
+
+	t.Run("installed by the feature", func(t *testing.T) {
+		helmService := dummyHelmService{
+			Deployments: map[string]*helm.GetDeploymentResponse{
+				"logging-operator":         {ReleaseName: "logging-operator"},
+				"logging-operator-logging": {ReleaseName: "logging-operator-logging"},
+			},
+			Deleted: map[string]bool{},
+		}
+		op := MakeFeatureOperator(nil, nil, helmService, nil, Configuration{}, logger, nil)
+
+		assert.NoError(t, op.removeLegacyLogging(context.Background(), 42, logger))
+		assert.Empty(t, helmService.Deleted)
+	})
+}
+
+func TestGenerateFlows(t *testing.T) {
+	spec := featureSpec{
+		Flows: []flowSpec{
+			{
+				Name:    "all",
+				Outputs: []string{"archive", "loki"},
+			},
+			{
+				Name:       "apps",
+				Outputs:    []string{"loki"},
+				Namespaces: []string{"default", "web"},
+				Labels:     map[string]string{"app": "web"},
+			},
+		},
+	}
+
+	assert.Equal(t, []clusterFlowValues{
+		{
+			Name: "all",
+			Spec: clusterFlowSpecValues{
+				OutputRefs: []string{"archive", "loki"},
+			},
+		},
+		{
+			Name: "apps",
+			Spec: clusterFlowSpecValues{
+				Match: []matchValues{
+					{
+						Select: &selectValues{
+							Namespaces: []string{"default", "web"},
+							Labels:     map[string]string{"app": "web"},
+						},
+					},
+				},
+				OutputRefs: []string{"loki"},
+			},
+		},
+	}, generateFlows(spec))
+}
+
+func TestFeatureOperator_GenerateOutputs(t *testing.T) {
+	op := MakeFeatureOperator(nil, nil, nil, nil, Configuration{}, commonadapter.NewNoopLogger(), nil)
+
+	spec := featureSpec{
+		Outputs: []outputSpec{
+			{Name: "search", Elasticsearch: &elasticsearchOutputSpec{Host: "es.example.com", Port: 9200, Index: "logs"}},
+			{Name: "webhook", HTTP: &httpOutputSpec{Endpoint: "https://logs.example.com"}},
+		},
+	}
+
+	outputs, secretValues, err := op.generateOutputs(context.Background(), spec)
+	assert.NoError(t, err)
+	assert.Empty(t, secretValues)
+	assert.Equal(t, []clusterOutputValues{
+		{
+			Name: "search",
+			Spec: outputSpecValues{
+				Elasticsearch: &elasticsearchOutputValues{Host: "es.example.com", Port: 9200, IndexName: "logs"},
+			},
+		},
+		{
+			Name: "webhook",
+			Spec: outputSpecValues{
+				HTTP: &httpOutputValues{Endpoint: "https://logs.example.com"},
+			},
+		},
+	}, outputs)
+}
@@ -0,0 +1,115 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"encoding/json"
+
+	"emperror.dev/errors"
+)
+
+const (
+	nameKey       = "name"
+	typeKey       = "type"
+	outputsKey    = "outputs"
+	flowsKey      = "flows"
+	namespacesKey = "namespaces"
+	labelsKey     = "labels"
+	versionKey    = "version"
+)
+
+// getLoggingOutput returns the outputs and flows active on the cluster based on the deployed Logging resources
+func getLoggingOutput(deploymentValues map[string]interface{}) (map[string]interface{}, error) {
+	outputs := make([]map[string]interface{}, 0)
+	flows := make([]map[string]interface{}, 0)
+
+	if deploymentValues != nil {
+		var values loggingValues
+
+		valuesBytes, err := json.Marshal(deploymentValues)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to marshal deployment values")
+		}
+
+		if err := json.Unmarshal(valuesBytes, &values); err != nil {
+			return nil, errors.WrapIf(err, "failed to unmarshal deployment values")
+		}
+
+		for _, output := range values.ClusterOutputs {
+			outputs = append(outputs, map[string]interface{}{
+				nameKey: output.Name,
+				typeKey: output.Spec.getOutputType(),
+			})
+		}
+
+		for _, flow := range values.ClusterFlows {
+			var namespaces []string
+			var labels map[string]string
+			for _, match := range flow.Spec.Match {
+				if match.Select != nil {
+					namespaces = append(namespaces, match.Select.Namespaces...)
+					labels = mergeLabels(labels, match.Select.Labels)
+				}
+			}
+
+			flows = append(flows, map[string]interface{}{
+				nameKey:       flow.Name,
+				outputsKey:    flow.Spec.OutputRefs,
+				namespacesKey: namespaces,
+				labelsKey:     labels,
+			})
+		}
+	}
+
+	return map[string]interface{}{
+		outputsKey: outputs,
+		flowsKey:   flows,
+	}, nil
+}
+
+func (v outputSpecValues) getOutputType() string {
+	switch {
+	case v.S3 != nil:
+		return outputTypeS3
+	case v.GCS != nil:
+		return outputTypeGCS
+	case v.AzureStorage != nil:
+		return outputTypeAzure
+	case v.Elasticsearch != nil:
+		return outputTypeElasticsearch
+	case v.Loki != nil:
+		return outputTypeLoki
+	case v.HTTP != nil:
+		return outputTypeHTTP
+	}
+
+	return ""
+}
+
+func mergeLabels(labels map[string]string, other map[string]string) map[string]string {
+	if len(other) == 0 {
+		return labels
+	}
+
+	if labels == nil {
+		labels = make(map[string]string, len(other))
+	}
+
+	for name, value := range other {
+		labels[name] = value
+	}
+
+	return labels
+}
@@ -0,0 +1,307 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/mitchellh/mapstructure"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/banzaicloud/pipeline/internal/clusterfeature"
+)
+
+type featureSpec struct {
+	Outputs []outputSpec `json:"outputs" mapstructure:"outputs"`
+	Flows   []flowSpec   `json:"flows" mapstructure:"flows"`
+}
+
+type outputSpec struct {
+	Name          string                   `json:"name" mapstructure:"name"`
+	S3            *s3OutputSpec            `json:"s3,omitempty" mapstructure:"s3"`
+	GCS           *gcsOutputSpec           `json:"gcs,omitempty" mapstructure:"gcs"`
+	Azure         *azureOutputSpec         `json:"azure,omitempty" mapstructure:"azure"`
+	Elasticsearch *elasticsearchOutputSpec `json:"elasticsearch,omitempty" mapstructure:"elasticsearch"`
+	Loki          *lokiOutputSpec          `json:"loki,omitempty" mapstructure:"loki"`
+	HTTP          *httpOutputSpec          `json:"http,omitempty" mapstructure:"http"`
+}
+
+type s3OutputSpec struct {
+	SecretID string `json:"secretId" mapstructure:"secretId"`
+	Bucket   string `json:"bucket" mapstructure:"bucket"`
+	Region   string `json:"region" mapstructure:"region"`
+	Path     string `json:"path" mapstructure:"path"`
+}
+
+type gcsOutputSpec struct {
+	SecretID string `json:"secretId" mapstructure:"secretId"`
+	Bucket   string `json:"bucket" mapstructure:"bucket"`
+	Path     string `json:"path" mapstructure:"path"`
+}
+
+type azureOutputSpec struct {
+	SecretID       string `json:"secretId" mapstructure:"secretId"`
+	ResourceGroup  string `json:"resourceGroup" mapstructure:"resourceGroup"`
+	StorageAccount string `json:"storageAccount" mapstructure:"storageAccount"`
+	Container      string `json:"container" mapstructure:"container"`
+	Path           string `json:"path" mapstructure:"path"`
+}
+
+type elasticsearchOutputSpec struct {
+	Host     string `json:"host" mapstructure:"host"`
+	Port     int    `json:"port" mapstructure:"port"`
+	Scheme   string `json:"scheme" mapstructure:"scheme"`
+	Index    string `json:"index" mapstructure:"index"`
+	SecretID string `json:"secretId" mapstructure:"secretId"`
+}
+
+type lokiOutputSpec struct {
+	URL      string `json:"url" mapstructure:"url"`
+	SecretID string `json:"secretId" mapstructure:"secretId"`
+}
+
+type httpOutputSpec struct {
+	Endpoint string `json:"endpoint" mapstructure:"endpoint"`
+	SecretID string `json:"secretId" mapstructure:"secretId"`
+}
+
+type flowSpec struct {
+	Name       string            `json:"name" mapstructure:"name"`
+	Outputs    []string          `json:"outputs" mapstructure:"outputs"`
+	Namespaces []string          `json:"namespaces" mapstructure:"namespaces"`
+	Labels     map[string]string `json:"labels" mapstructure:"labels"`
+}
+
+type requiredFieldError struct {
+	fieldName string
+}
+
+func (e requiredFieldError) Error() string {
+	return fmt.Sprintf("%q cannot be empty", e.fieldName)
+}
+
+func (s featureSpec) Validate() error {
+	if len(s.Outputs) == 0 {
+		return requiredFieldError{fieldName: "outputs"}
+	}
+
+	outputNames := make(map[string]bool, len(s.Outputs))
+	for _, output := range s.Outputs {
+		if err := output.Validate(); err != nil {
+			return err
+		}
+
+		if outputNames[output.Name] {
+			return errors.Errorf("duplicate output name %q", output.Name)
+		}
+		outputNames[output.Name] = true
+	}
+
+	flowNames := make(map[string]bool, len(s.Flows))
+	for _, flow := range s.Flows {
+		if err := flow.Validate(outputNames); err != nil {
+			return err
+		}
+
+		if flowNames[flow.Name] {
+			return errors.Errorf("duplicate flow name %q", flow.Name)
+		}
+		flowNames[flow.Name] = true
+	}
+
+	return nil
+}
+
+// Type returns the type of the output, or an empty string if none or more than one is set
+func (s outputSpec) Type() string {
+	var types []string
+	if s.S3 != nil {
+		types = append(types, outputTypeS3)
+	}
+	if s.GCS != nil {
+		types = append(types, outputTypeGCS)
+	}
+	if s.Azure != nil {
+		types = append(types, outputTypeAzure)
+	}
+	if s.Elasticsearch != nil {
+		types = append(types, outputTypeElasticsearch)
+	}
+	if s.Loki != nil {
+		types = append(types, outputTypeLoki)
+	}
+	if s.HTTP != nil {
+		types = append(types, outputTypeHTTP)
+	}
+
+	if len(types) != 1 {
+		return ""
+	}
+
+	return types[0]
+}
+
+func (s outputSpec) Validate() error {
+	if err := validateName("output", s.Name); err != nil {
+		return err
+	}
+
+	switch s.Type() {
+	case outputTypeS3:
+		return s.S3.Validate(s.Name)
+	case outputTypeGCS:
+		return s.GCS.Validate(s.Name)
+	case outputTypeAzure:
+		return s.Azure.Validate(s.Name)
+	case outputTypeElasticsearch:
+		return s.Elasticsearch.Validate(s.Name)
+	case outputTypeLoki:
+		return s.Loki.Validate(s.Name)
+	case outputTypeHTTP:
+		return s.HTTP.Validate(s.Name)
+	default:
+		return errors.Errorf("output %q must have exactly one of s3, gcs, azure, elasticsearch, loki or http set", s.Name)
+	}
+}
+
+func (s s3OutputSpec) Validate(outputName string) error {
+	if s.SecretID == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "s3.secretId")}
+	}
+	if s.Bucket == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "s3.bucket")}
+	}
+	if s.Region == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "s3.region")}
+	}
+	return nil
+}
+
+func (s gcsOutputSpec) Validate(outputName string) error {
+	if s.SecretID == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "gcs.secretId")}
+	}
+	if s.Bucket == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "gcs.bucket")}
+	}
+	return nil
+}
+
+func (s azureOutputSpec) Validate(outputName string) error {
+	if s.SecretID == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "azure.secretId")}
+	}
+	if s.ResourceGroup == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "azure.resourceGroup")}
+	}
+	if s.StorageAccount == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "azure.storageAccount")}
+	}
+	if s.Container == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "azure.container")}
+	}
+	return nil
+}
+
+func (s elasticsearchOutputSpec) Validate(outputName string) error {
+	if s.Host == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "elasticsearch.host")}
+	}
+	if s.Port < 0 || s.Port > 65535 {
+		return errors.Errorf("output %q has an invalid elasticsearch port: %d", outputName, s.Port)
+	}
+	switch s.Scheme {
+	case "", "http", "https":
+	default:
+		return errors.Errorf("output %q has an invalid elasticsearch scheme: %q", outputName, s.Scheme)
+	}
+	return nil
+}
+
+func (s lokiOutputSpec) Validate(outputName string) error {
+	if s.URL == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "loki.url")}
+	}
+	return nil
+}
+
+func (s httpOutputSpec) Validate(outputName string) error {
+	if s.Endpoint == "" {
+		return requiredFieldError{fieldName: outputFieldName(outputName, "http.endpoint")}
+	}
+	return nil
+}
+
+func (s flowSpec) Validate(outputNames map[string]bool) error {
+	if err := validateName("flow", s.Name); err != nil {
+		return err
+	}
+
+	if len(s.Outputs) == 0 {
+		return requiredFieldError{fieldName: fmt.Sprintf("flows[%s].outputs", s.Name)}
+	}
+
+	for _, output := range s.Outputs {
+		if !outputNames[output] {
+			return errors.Errorf("flow %q refers to an undefined output %q", s.Name, output)
+		}
+	}
+
+	for _, namespace := range s.Namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return errors.Errorf("flow %q has an invalid namespace %q: %s", s.Name, namespace, strings.Join(errs, ", "))
+		}
+	}
+
+	for name, value := range s.Labels {
+		if errs := validation.IsQualifiedName(name); len(errs) > 0 {
+			return errors.Errorf("flow %q has an invalid label name %q: %s", s.Name, name, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return errors.Errorf("flow %q has an invalid label value %q: %s", s.Name, value, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+func validateName(kind string, name string) error {
+	if name == "" {
+		return requiredFieldError{fieldName: fmt.Sprintf("%s name", kind)}
+	}
+
+	if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+		return errors.Errorf("invalid %s name %q: %s", kind, name, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+func outputFieldName(outputName string, field string) string {
+	return fmt.Sprintf("outputs[%s].%s", outputName, field)
+}
+
+func bindFeatureSpec(spec clusterfeature.FeatureSpec) (featureSpec, error) {
+	var boundSpec featureSpec
+	if err := mapstructure.Decode(spec, &boundSpec); err != nil {
+		return boundSpec, clusterfeature.InvalidFeatureSpecError{
+			FeatureName: featureName,
+			Problem:     errors.WrapIf(err, "failed to bind feature spec").Error(),
+		}
+	}
+	return boundSpec, nil
+}
@@ -0,0 +1,131 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+type loggingOperatorValues struct {
+	Affinity    v1.Affinity     `json:"affinity"`
+	Tolerations []v1.Toleration `json:"tolerations"`
+}
+
+type loggingValues struct {
+	ControlNamespace string                `json:"controlNamespace"`
+	TLS              tlsValues             `json:"tls"`
+	ClusterOutputs   []clusterOutputValues `json:"clusterOutputs"`
+	ClusterFlows     []clusterFlowValues   `json:"clusterFlows"`
+}
+
+type tlsValues struct {
+	Enabled             bool   `json:"enabled"`
+	FluentdSecretName   string `json:"fluentdSecretName"`
+	FluentbitSecretName string `json:"fluentbitSecretName"`
+}
+
+type clusterOutputValues struct {
+	Name string           `json:"name"`
+	Spec outputSpecValues `json:"spec"`
+}
+
+type outputSpecValues struct {
+	S3            *s3OutputValues            `json:"s3,omitempty"`
+	GCS           *gcsOutputValues           `json:"gcs,omitempty"`
+	AzureStorage  *azureStorageOutputValues  `json:"azurestorage,omitempty"`
+	Elasticsearch *elasticsearchOutputValues `json:"elasticsearch,omitempty"`
+	Loki          *lokiOutputValues          `json:"loki,omitempty"`
+	HTTP          *httpOutputValues          `json:"http,omitempty"`
+}
+
+type secretValue struct {
+	ValueFrom valueFromValues `json:"valueFrom"`
+}
+
+type valueFromValues struct {
+	SecretKeyRef secretKeyRefValues `json:"secretKeyRef"`
+}
+
+type secretKeyRefValues struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+type s3OutputValues struct {
+	AwsKeyID  *secretValue `json:"aws_key_id"`
+	AwsSecKey *secretValue `json:"aws_sec_key"`
+	Bucket    string       `json:"s3_bucket"`
+	Region    string       `json:"s3_region"`
+	Path      string       `json:"path,omitempty"`
+}
+
+type gcsOutputValues struct {
+	Project         string       `json:"project"`
+	CredentialsJSON *secretValue `json:"credentials_json"`
+	Bucket          string       `json:"bucket"`
+	Path            string       `json:"path,omitempty"`
+}
+
+type azureStorageOutputValues struct {
+	StorageAccount *secretValue `json:"azure_storage_account"`
+	AccessKey      *secretValue `json:"azure_storage_access_key"`
+	Container      string       `json:"azure_container"`
+	Path           string       `json:"path,omitempty"`
+}
+
+type elasticsearchOutputValues struct {
+	Host      string       `json:"host"`
+	Port      int          `json:"port,omitempty"`
+	Scheme    string       `json:"scheme,omitempty"`
+	IndexName string       `json:"index_name,omitempty"`
+	User      string       `json:"user,omitempty"`
+	Password  *secretValue `json:"password,omitempty"`
+}
+
+type lokiOutputValues struct {
+	URL                       string       `json:"url"`
+	Username                  *secretValue `json:"username,omitempty"`
+	Password                  *secretValue `json:"password,omitempty"`
+	ConfigureKubernetesLabels bool         `json:"configure_kubernetes_labels"`
+}
+
+type httpOutputValues struct {
+	Endpoint string          `json:"endpoint"`
+	Auth     *httpAuthValues `json:"auth,omitempty"`
+}
+
+type httpAuthValues struct {
+	Username *secretValue `json:"username"`
+	Password *secretValue `json:"password"`
+}
+
+type clusterFlowValues struct {
+	Name string                `json:"name"`
+	Spec clusterFlowSpecValues `json:"spec"`
+}
+
+type clusterFlowSpecValues struct {
+	Match      []matchValues `json:"match,omitempty"`
+	OutputRefs []string      `json:"globalOutputRefs"`
+}
+
+type matchValues struct {
+	Select *selectValues `json:"select,omitempty"`
+}
+
+type selectValues struct {
+	Namespaces []string          `json:"namespaces,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
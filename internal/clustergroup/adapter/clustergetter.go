@@ -75,3 +75,28 @@ func (m *clusterGetter) GetClusterByIDOnly(ctx context.Context, clusterID uint)
 
 	return nil, errors.New("could not assert to Cluster")
 }
+
+// GetClusters returns the cluster instances of an organization.
+func (m *clusterGetter) GetClusters(ctx context.Context, organizationID uint) ([]api.Cluster, error) {
+	commonClusters, err := m.clusterManager.GetClusters(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters := make([]api.Cluster, 0, len(commonClusters))
+	for _, c := range commonClusters {
+		cluster, ok := c.(api.Cluster)
+		if !ok {
+			return nil, errors.New("could not assert to Cluster")
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, nil
+}
+
+// GetClusterLabels returns the user-defined labels of a cluster.
+func (m *clusterGetter) GetClusterLabels(ctx context.Context, clusterID uint) (map[string]string, error) {
+	return m.clusterManager.GetClusterLabels(ctx, clusterID)
+}
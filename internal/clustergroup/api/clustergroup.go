@@ -20,8 +20,9 @@ import (
 
 // CreateRequest describes fields of a create cluster group request
 type CreateRequest struct {
-	Name    string `json:"name" yaml:"name" example:"cluster_group_name"`
-	Members []uint `json:"members" yaml:"members"`
+	Name     string           `json:"name" yaml:"name" example:"cluster_group_name"`
+	Members  []uint           `json:"members" yaml:"members"`
+	Selector *ClusterSelector `json:"selector,omitempty" yaml:"selector,omitempty"`
 }
 
 // Validate validates CreateRequest
@@ -30,10 +31,7 @@ func (g *CreateRequest) Validate() error {
 		return errors.New("cluster group name is empty")
 	}
 
-	if len(g.Members) == 0 {
-		return errors.New("there should be at least one cluster member")
-	}
-	return nil
+	return validateMembership(g.Members, g.Selector)
 }
 
 // CreateResponse describes fields of a create cluster group response
@@ -44,8 +42,9 @@ type CreateResponse struct {
 
 // UpdateRequest describes fields of a update cluster group request
 type UpdateRequest struct {
-	Name    string `json:"name" yaml:"name" example:"cluster_group_name"`
-	Members []uint `json:"members,omitempty" yaml:"members"`
+	Name     string           `json:"name" yaml:"name" example:"cluster_group_name"`
+	Members  []uint           `json:"members,omitempty" yaml:"members"`
+	Selector *ClusterSelector `json:"selector,omitempty" yaml:"selector,omitempty"`
 }
 
 // Validate validates UpdateRequest
@@ -54,7 +53,19 @@ func (g *UpdateRequest) Validate() error {
 		return errors.New("cluster group name is empty")
 	}
 
-	if len(g.Members) == 0 {
+	return validateMembership(g.Members, g.Selector)
+}
+
+func validateMembership(members []uint, selector *ClusterSelector) error {
+	if selector != nil {
+		if len(members) > 0 {
+			return errors.New("either members or selector should be specified, not both")
+		}
+
+		return selector.Validate()
+	}
+
+	if len(members) == 0 {
 		return errors.New("there should be at least one cluster member")
 	}
 	return nil
@@ -82,6 +93,7 @@ type ClusterGroup struct {
 	Name            string           `json:"name" yaml:"name"`
 	OrganizationID  uint             `json:"organizationId" yaml:"organizationId"`
 	Members         []Member         `json:"members,omitempty" yaml:"members"`
+	Selector        *ClusterSelector `json:"selector,omitempty" yaml:"selector,omitempty"`
 	EnabledFeatures []string         `json:"enabledFeatures,omitempty" yaml:"enabledFeatures"`
 	Clusters        map[uint]Cluster `json:"-" yaml:"-"`
 }
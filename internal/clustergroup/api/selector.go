@@ -0,0 +1,71 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ClusterSelector selects the members of a cluster group by cluster attributes.
+// A cluster matches the selector if its cloud, distribution and location are one of the listed values
+// (empty lists match every cluster) and it has every listed label with the same value.
+type ClusterSelector struct {
+	Cloud        []string          `json:"cloud,omitempty" yaml:"cloud,omitempty" example:"amazon"`
+	Distribution []string          `json:"distribution,omitempty" yaml:"distribution,omitempty" example:"eks"`
+	Location     []string          `json:"location,omitempty" yaml:"location,omitempty" example:"eu-west-1"`
+	Labels       map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+// Validate validates ClusterSelector
+func (s *ClusterSelector) Validate() error {
+	if len(s.Cloud) == 0 && len(s.Distribution) == 0 && len(s.Location) == 0 && len(s.Labels) == 0 {
+		return errors.New("cluster selector should have at least one criteria")
+	}
+
+	return nil
+}
+
+// Matches returns true if the cluster with the given attributes and labels is selected.
+func (s *ClusterSelector) Matches(cluster Cluster, labels map[string]string) bool {
+	if !matchesAny(s.Cloud, cluster.GetCloud()) ||
+		!matchesAny(s.Distribution, cluster.GetDistribution()) ||
+		!matchesAny(s.Location, cluster.GetLocation()) {
+		return false
+	}
+
+	for name, value := range s.Labels {
+		if v, ok := labels[name]; !ok || v != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type selectorTestCluster struct {
+	cloud        string
+	distribution string
+	location     string
+}
+
+func (c selectorTestCluster) GetID() uint                   { return 1 }
+func (c selectorTestCluster) GetOrganizationId() uint       { return 1 }
+func (c selectorTestCluster) GetCloud() string              { return c.cloud }
+func (c selectorTestCluster) GetDistribution() string       { return c.distribution }
+func (c selectorTestCluster) GetLocation() string           { return c.location }
+func (c selectorTestCluster) GetName() string               { return "test" }
+func (c selectorTestCluster) GetK8sConfig() ([]byte, error) { return nil, nil }
+func (c selectorTestCluster) IsReady() (bool, error)        { return true, nil }
+func (c selectorTestCluster) GetStatus() (*cluster.GetClusterStatusResponse, error) {
+	return nil, nil
+}
+
+func TestClusterSelector_Matches(t *testing.T) {
+	eks := selectorTestCluster{cloud: "amazon", distribution: "eks", location: "eu-west-1"}
+	labels := map[string]string{"env": "prod", "team": "core"}
+
+	tests := []struct {
+		name     string
+		selector ClusterSelector
+		expected bool
+	}{
+		{
+			name:     "cloud",
+			selector: ClusterSelector{Cloud: []string{"google", "amazon"}},
+			expected: true,
+		},
+		{
+			name:     "other cloud",
+			selector: ClusterSelector{Cloud: []string{"google"}},
+			expected: false,
+		},
+		{
+			name:     "distribution and location",
+			selector: ClusterSelector{Distribution: []string{"EKS"}, Location: []string{"eu-west-1"}},
+			expected: true,
+		},
+		{
+			name:     "other location",
+			selector: ClusterSelector{Distribution: []string{"eks"}, Location: []string{"us-east-1"}},
+			expected: false,
+		},
+		{
+			name:     "labels",
+			selector: ClusterSelector{Labels: map[string]string{"env": "prod"}},
+			expected: true,
+		},
+		{
+			name:     "other label value",
+			selector: ClusterSelector{Labels: map[string]string{"env": "dev"}},
+			expected: false,
+		},
+		{
+			name:     "missing label",
+			selector: ClusterSelector{Cloud: []string{"amazon"}, Labels: map[string]string{"region": "eu"}},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.selector.Matches(eks, labels))
+		})
+	}
+}
+
+func TestClusterSelector_Validate(t *testing.T) {
+	assert.Error(t, (&ClusterSelector{}).Validate())
+	assert.NoError(t, (&ClusterSelector{Labels: map[string]string{"env": "prod"}}).Validate())
+}
+
+func TestCreateRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&CreateRequest{Name: "group", Members: []uint{1}}).Validate())
+	assert.NoError(t, (&CreateRequest{Name: "group", Selector: &ClusterSelector{Cloud: []string{"amazon"}}}).Validate())
+	assert.Error(t, (&CreateRequest{Name: "group"}).Validate())
+	assert.Error(t, (&CreateRequest{Name: "group", Members: []uint{1}, Selector: &ClusterSelector{Cloud: []string{"amazon"}}}).Validate())
+}
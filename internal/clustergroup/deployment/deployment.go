@@ -36,6 +36,31 @@ type ClusterGroupDeployment struct {
 	ValueOverrides map[string]map[string]interface{} `json:"valueOverrides,omitempty" yaml:"valueOverrides,omitempty"`
 	RollingMode    bool                              `json:"rollingMode,omitempty" yaml:"rollingMode,omitempty"`
 	Atomic         bool                              `json:"atomic,omitempty" yaml:"atomic,omitempty"`
+	Rollout        *RolloutStrategy                  `json:"rollout,omitempty" yaml:"rollout,omitempty"`
+}
+
+// RolloutStrategy describes a progressive rollout of a cluster group deployment.
+// The deployment is rolled out to the canary clusters first, then to the rest of the clusters in waves.
+// The rollout halts when the release is not healthy on a cluster of a wave.
+type RolloutStrategy struct {
+	// CanaryClusters are the names of the clusters the deployment is rolled out to first.
+	CanaryClusters []string `json:"canaryClusters,omitempty" yaml:"canaryClusters,omitempty"`
+
+	// WaveSize is the number of clusters the deployment is rolled out to at once after the canary clusters.
+	// All the remaining clusters are deployed in one wave if it is not set.
+	WaveSize int `json:"waveSize,omitempty" yaml:"waveSize,omitempty"`
+
+	// HealthTimeout is the time in seconds to wait for the release to become healthy on the clusters of a wave.
+	HealthTimeout int64 `json:"healthTimeout,omitempty" yaml:"healthTimeout,omitempty"`
+}
+
+// RolloutInfo describes the progress of the rollout of a cluster group deployment.
+type RolloutInfo struct {
+	Status      string          `json:"status"`
+	CurrentWave int             `json:"currentWave"`
+	Waves       int             `json:"waves"`
+	Error       string          `json:"error,omitempty"`
+	Strategy    RolloutStrategy `json:"strategy"`
 }
 
 // DeploymentInfo describes the details of a helm deployment
@@ -53,9 +78,10 @@ type DeploymentInfo struct {
 	ValueOverrides       map[string]map[string]interface{} `json:"valueOverrides,omitempty" yaml:"valueOverrides,omitempty"`
 	TargetClusters       map[uint]bool                     `json:"-" yaml:"-"`
 	TargetClustersStatus []TargetClusterStatus             `json:"targetClusters"`
+	Rollout              *RolloutInfo                      `json:"rollout,omitempty"`
 }
 
-func (c *DeploymentInfo) GetValuesForCluster(clusterName string) ([]byte, error) {
+func (c *DeploymentInfo) GetValuesForCluster(cluster ClusterTemplateData) ([]byte, error) {
 	// copy c.values into a new map before merging
 	values := make(map[string]interface{})
 	if c.Values != nil {
@@ -69,11 +95,17 @@ func (c *DeploymentInfo) GetValuesForCluster(clusterName string) ([]byte, error)
 		}
 	}
 
-	clusterSpecificOverrides, exists := c.ValueOverrides[clusterName]
+	clusterSpecificOverrides, exists := c.ValueOverrides[cluster.Name]
 	// merge values with overrides for cluster if any
 	if exists {
 		values = helm.MergeValues(values, clusterSpecificOverrides)
 	}
+
+	values, err := renderValues(values, cluster, c.ReleaseName, c.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	marshalledValues, err := yaml.Marshal(values)
 	if err != nil {
 		return nil, err
@@ -97,6 +129,14 @@ type TargetClusterStatus struct {
 	Stale        bool   `json:"stale"`
 	Version      string `json:"version,omitempty"`
 	Error        string `json:"error,omitempty"`
+
+	// ValuesDrift is true if the values of the installed release differ from the values rendered for the cluster.
+	ValuesDrift bool `json:"valuesDrift,omitempty"`
+
+	// Wave is the rollout wave of the cluster if the deployment is rolled out progressively.
+	Wave          int    `json:"wave,omitempty"`
+	Canary        bool   `json:"canary,omitempty"`
+	RolloutStatus string `json:"rolloutStatus,omitempty"`
 }
 
 // TargetOperationStatus describes a status of a deployment operation (install/upgrade/delete) on a target cluster
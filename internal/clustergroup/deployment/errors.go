@@ -90,3 +90,63 @@ func IsDeploymentAlreadyExistsError(err error) bool {
 
 	return ok
 }
+
+type invalidRolloutStrategyError struct {
+	message string
+}
+
+func (e *invalidRolloutStrategyError) Error() string {
+	return "invalid rollout strategy: " + e.message
+}
+
+// IsInvalidRolloutStrategyError returns true if the passed in error designates an invalid rollout strategy
+func IsInvalidRolloutStrategyError(err error) bool {
+	_, ok := errors.Cause(err).(*invalidRolloutStrategyError)
+
+	return ok
+}
+
+type rolloutNotHaltedError struct {
+	releaseName   string
+	rolloutStatus string
+}
+
+func (e *rolloutNotHaltedError) Error() string {
+	return "only halted rollouts can be rolled back"
+}
+
+func (e *rolloutNotHaltedError) Context() []interface{} {
+	return []interface{}{
+		"releaseName", e.releaseName,
+		"rolloutStatus", e.rolloutStatus,
+	}
+}
+
+// IsRolloutNotHaltedError returns true if the passed in error designates a rollback of a rollout which is not halted
+func IsRolloutNotHaltedError(err error) bool {
+	_, ok := errors.Cause(err).(*rolloutNotHaltedError)
+
+	return ok
+}
+
+type invalidValuesError struct {
+	clusterName string
+	message     string
+}
+
+func (e *invalidValuesError) Error() string {
+	return fmt.Sprintf("invalid values for cluster %s: %s", e.clusterName, e.message)
+}
+
+func (e *invalidValuesError) Context() []interface{} {
+	return []interface{}{
+		"clusterName", e.clusterName,
+	}
+}
+
+// IsInvalidValuesError returns true if the passed in error designates values which can not be rendered for a cluster
+func IsInvalidValuesError(err error) bool {
+	_, ok := errors.Cause(err).(*invalidValuesError)
+
+	return ok
+}
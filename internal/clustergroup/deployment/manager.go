@@ -18,30 +18,39 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"emperror.dev/emperror"
+	"github.com/ghodss/yaml"
 	"github.com/jinzhu/gorm"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/log"
 	"github.com/sirupsen/logrus"
 	"github.com/technosophos/moniker"
-	k8sHelm "k8s.io/helm/pkg/helm"
-	helm_env "k8s.io/helm/pkg/helm/environment"
+	"go.uber.org/cadence/client"
 	"k8s.io/helm/pkg/proto/hapi/chart"
-	hapi_release5 "k8s.io/helm/pkg/proto/hapi/release"
 
 	"github.com/banzaicloud/pipeline/helm"
 	"github.com/banzaicloud/pipeline/internal/clustergroup/api"
-	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+	internalHelm "github.com/banzaicloud/pipeline/internal/helm"
 )
 
+// ClusterDomainGetter returns the DNS domain of a cluster.
+type ClusterDomainGetter interface {
+	// GetClusterDomain returns the domain of the cluster, or an empty string if the cluster has no DNS domain.
+	GetClusterDomain(ctx context.Context, clusterID uint) (string, error)
+}
+
 // CGDeploymentManager
 type CGDeploymentManager struct {
-	clusterGetter api.ClusterGetter
-	repository    *CGDeploymentRepository
-	logger        logrus.FieldLogger
-	errorHandler  emperror.Handler
+	clusterGetter       api.ClusterGetter
+	clusterDomainGetter ClusterDomainGetter
+	helmBackends        internalHelm.Backends
+	repository          *CGDeploymentRepository
+	workflowClient      client.Client
+	logger              logrus.FieldLogger
+	errorHandler        emperror.Handler
 }
 
 const OperationSucceededStatus = "SUCCEEDED"
@@ -59,6 +68,9 @@ const FeatureName = "deployment"
 func NewCGDeploymentManager(
 	db *gorm.DB,
 	clusterGetter api.ClusterGetter,
+	clusterDomainGetter ClusterDomainGetter,
+	helmBackends internalHelm.Backends,
+	workflowClient client.Client,
 	logger logrus.FieldLogger,
 	errorHandler emperror.Handler,
 ) *CGDeploymentManager {
@@ -67,9 +79,12 @@ func NewCGDeploymentManager(
 			db:     db,
 			logger: logger,
 		},
-		clusterGetter: clusterGetter,
-		logger:        logger,
-		errorHandler:  errorHandler,
+		clusterGetter:       clusterGetter,
+		clusterDomainGetter: clusterDomainGetter,
+		helmBackends:        helmBackends,
+		workflowClient:      workflowClient,
+		logger:              logger,
+		errorHandler:        errorHandler,
 	}
 }
 
@@ -112,38 +127,52 @@ func (m *CGDeploymentManager) GetMembersStatus(featureState api.Feature) (map[ui
 	return statusMap, nil
 }
 
-func (m CGDeploymentManager) installDeploymentOnCluster(log *logrus.Entry, apiCluster api.Cluster, orgName string, env helm_env.EnvSettings, depInfo *DeploymentInfo, requestedChart *chart.Chart, dryRun bool) error {
-	log.Info("install cluster group deployment")
-
+// getHelmBackend returns the Helm backend managing the releases of a member cluster.
+// The organization name is only used to resolve charts, so it can be empty for the other operations.
+func (m CGDeploymentManager) getHelmBackend(ctx context.Context, apiCluster api.Cluster, orgName string) (*internalHelm.Cluster, internalHelm.Backend, error) {
 	k8sConfig, err := apiCluster.GetK8sConfig()
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	cluster := &internalHelm.Cluster{
+		OrganizationName: orgName,
+		KubeConfig:       k8sConfig,
+	}
+
+	backend, err := m.helmBackends.ForCluster(ctx, cluster)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	values, err := depInfo.GetValuesForCluster(apiCluster.GetName())
+	return cluster, backend, nil
+}
+
+func (m CGDeploymentManager) installDeploymentOnCluster(log *logrus.Entry, apiCluster api.Cluster, orgName string, depInfo *DeploymentInfo, chartPackage []byte, dryRun bool, healthTimeout int64) error {
+	log.Info("install cluster group deployment")
+
+	ctx := context.Background()
+	cluster, backend, err := m.getHelmBackend(ctx, apiCluster, orgName)
 	if err != nil {
 		return err
 	}
 
-	hClient, err := pkgHelm.NewClient(k8sConfig, m.logger)
+	values, err := m.getValuesForCluster(apiCluster, depInfo)
 	if err != nil {
 		return err
 	}
-	defer hClient.Close()
 
-	options := []k8sHelm.InstallOption{
-		k8sHelm.ReleaseName(depInfo.ReleaseName),
-		k8sHelm.InstallDryRun(dryRun),
-		k8sHelm.InstallWait(false),
-		k8sHelm.ValueOverrides(values),
+	options := internalHelm.ReleaseOptions{
+		DryRun:       dryRun,
+		ChartPackage: chartPackage,
+	}
+	// wait for the resources of the release to become ready
+	if healthTimeout > 0 {
+		options.Wait = true
+		options.Timeout = healthTimeout
 	}
-	installOptions := append(helm.DefaultInstallOptions, options...)
 
-	_, err = hClient.InstallReleaseFromChart(
-		requestedChart,
-		depInfo.Namespace,
-		installOptions...,
-	)
+	_, err = backend.InstallRelease(ctx, cluster, depInfo.Namespace, depInfo.Chart, depInfo.ChartVersion, depInfo.ReleaseName, values, options)
 	if err != nil {
 		return fmt.Errorf("error deploying chart: %v", err)
 	}
@@ -152,33 +181,31 @@ func (m CGDeploymentManager) installDeploymentOnCluster(log *logrus.Entry, apiCl
 	return nil
 }
 
-func (m CGDeploymentManager) upgradeDeploymentOnCluster(log *logrus.Entry, apiCluster api.Cluster, orgName string, env helm_env.EnvSettings, depInfo *DeploymentInfo, requestedChart *chart.Chart, dryRun bool) error {
+func (m CGDeploymentManager) upgradeDeploymentOnCluster(log *logrus.Entry, apiCluster api.Cluster, orgName string, depInfo *DeploymentInfo, chartPackage []byte, dryRun bool, healthTimeout int64) error {
 	log.Info("upgrade cluster group deployment")
 
-	k8sConfig, err := apiCluster.GetK8sConfig()
+	ctx := context.Background()
+	cluster, backend, err := m.getHelmBackend(ctx, apiCluster, orgName)
 	if err != nil {
 		return err
 	}
 
-	values, err := depInfo.GetValuesForCluster(apiCluster.GetName())
+	values, err := m.getValuesForCluster(apiCluster, depInfo)
 	if err != nil {
 		return err
 	}
 
-	hClient, err := pkgHelm.NewClient(k8sConfig, m.logger)
-	if err != nil {
-		return err
+	options := internalHelm.ReleaseOptions{
+		DryRun:       dryRun,
+		ChartPackage: chartPackage,
+	}
+	// wait for the resources of the release to become ready
+	if healthTimeout > 0 {
+		options.Wait = true
+		options.Timeout = healthTimeout
 	}
-	defer hClient.Close()
 
-	_, err = hClient.UpdateReleaseFromChart(
-		depInfo.ReleaseName,
-		requestedChart,
-		k8sHelm.UpdateValueOverrides(values),
-		k8sHelm.UpgradeDryRun(dryRun),
-		// helm.ResetValues(u.resetValues),
-		k8sHelm.ReuseValues(false),
-	)
+	_, err = backend.UpgradeRelease(ctx, cluster, depInfo.Chart, depInfo.ChartVersion, depInfo.ReleaseName, values, options)
 	if err != nil {
 		return fmt.Errorf("error deploying chart: %v", err)
 	}
@@ -187,53 +214,50 @@ func (m CGDeploymentManager) upgradeDeploymentOnCluster(log *logrus.Entry, apiCl
 	return nil
 }
 
-func (m CGDeploymentManager) upgradeOrInstallDeploymentOnCluster(apiCluster api.Cluster, orgName string, env helm_env.EnvSettings, depInfo *DeploymentInfo, requestedChart *chart.Chart, dryRun bool) error {
+// upgradeOrInstallDeploymentOnCluster installs or upgrades a deployment on a member cluster.
+// If healthTimeout is set, it waits for the resources of the release to become ready.
+// The status of the deployment on the cluster before the operation is returned.
+func (m CGDeploymentManager) upgradeOrInstallDeploymentOnCluster(apiCluster api.Cluster, orgName string, depInfo *DeploymentInfo, chartPackage []byte, dryRun bool, healthTimeout int64) (TargetClusterStatus, error) {
 	log := m.logger.WithFields(logrus.Fields{"deploymentName": depInfo.Chart, "releaseName": depInfo.ReleaseName, "clusterName": apiCluster.GetName(), "clusterId": apiCluster.GetID()})
 
 	status, err := m.getClusterDeploymentStatus(apiCluster, depInfo.ReleaseName, depInfo)
 	if err != nil {
-		return err
+		return status, err
 	}
 	if status.Status == NotInstalledStatus {
-		err := m.installDeploymentOnCluster(log, apiCluster, orgName, env, depInfo, requestedChart, dryRun)
+		err := m.installDeploymentOnCluster(log, apiCluster, orgName, depInfo, chartPackage, dryRun, healthTimeout)
 		if err != nil {
-			return err
+			return status, err
 		}
 	}
 
 	if status.Stale {
-		err := m.upgradeDeploymentOnCluster(log, apiCluster, orgName, env, depInfo, requestedChart, dryRun)
+		if status.ValuesDrift {
+			log.Info("installed values drifted from the rendered values")
+		}
+
+		err := m.upgradeDeploymentOnCluster(log, apiCluster, orgName, depInfo, chartPackage, dryRun, healthTimeout)
 		if err != nil {
-			return err
+			return status, err
 		}
+	} else if healthTimeout > 0 && status.Status != internalHelm.ReleaseStatusDeployed {
+		return status, fmt.Errorf("release is not healthy, status: %s", status.Status)
 	} else {
 		log.Info("nothing to do deployment is up to date")
 	}
 
-	return nil
+	return status, nil
 }
 
-func (m CGDeploymentManager) findRelease(apiCluster api.Cluster, name string) (*hapi_release5.Release, error) {
-	k8sConfig, err := apiCluster.GetK8sConfig()
-	if err != nil {
-		return nil, err
-	}
-
-	hClient, err := pkgHelm.NewClient(k8sConfig, m.logger)
+// findRelease returns the latest revision of a release on a member cluster, or nil if the release is not found.
+func (m CGDeploymentManager) findRelease(apiCluster api.Cluster, name string) (*internalHelm.Release, error) {
+	ctx := context.Background()
+	cluster, backend, err := m.getHelmBackend(ctx, apiCluster, "")
 	if err != nil {
-
 		return nil, err
 	}
-	defer hClient.Close()
 
-	resp, err := hClient.ReleaseContent(name)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return nil, nil
-		}
-		return nil, err
-	}
-	return resp.Release, nil
+	return backend.GetRelease(ctx, cluster, name)
 }
 
 func (m CGDeploymentManager) getClusterDeploymentStatus(apiCluster api.Cluster, name string, depInfo *DeploymentInfo) (TargetClusterStatus, error) {
@@ -252,9 +276,16 @@ func (m CGDeploymentManager) getClusterDeploymentStatus(apiCluster api.Cluster,
 		return deploymentStatus, err
 	}
 	if release != nil {
-		deploymentStatus.Version = release.Chart.Metadata.Version
-		deploymentStatus.Status = release.Info.Status.Code.String()
-		deploymentStatus.Stale = m.isStaleDeployment(release, depInfo, apiCluster)
+		deploymentStatus.Version = release.ChartVersion
+		deploymentStatus.Status = release.Status
+
+		values, err := m.getValuesForCluster(apiCluster, depInfo)
+		if err != nil {
+			deploymentStatus.Error = err.Error()
+			return deploymentStatus, err
+		}
+		deploymentStatus.ValuesDrift = m.isValuesDrift(release, values, apiCluster)
+		deploymentStatus.Stale = deploymentStatus.ValuesDrift || m.isStaleChart(release, depInfo)
 		if deploymentStatus.Stale {
 			deploymentStatus.Status = StaleStatus
 		}
@@ -266,23 +297,90 @@ func (m CGDeploymentManager) getClusterDeploymentStatus(apiCluster api.Cluster,
 	return deploymentStatus, nil
 }
 
-func (m CGDeploymentManager) isStaleDeployment(release *hapi_release5.Release, depInfo *DeploymentInfo, apiCluster api.Cluster) bool {
-	if release.Chart.Metadata.Name != depInfo.ChartName {
+func (m CGDeploymentManager) isStaleChart(release *internalHelm.Release, depInfo *DeploymentInfo) bool {
+	if release.ChartName != depInfo.ChartName {
 		return true
 	}
-	if release.Chart.Metadata.Version != depInfo.ChartVersion {
+	if release.ChartVersion != depInfo.ChartVersion {
 		return true
 	}
-	values, err := depInfo.GetValuesForCluster(apiCluster.GetName())
-	if err != nil {
+	return false
+}
+
+// isValuesDrift returns true if the values of the installed release differ from the values rendered for the cluster
+func (m CGDeploymentManager) isValuesDrift(release *internalHelm.Release, values []byte, apiCluster api.Cluster) bool {
+	m.logger.Debugf("%s release values: \n%v \nuser values:\n%s ", apiCluster.GetName(), release.Config, string(values))
+
+	// the values are compared decoded, as the backends don't store them the same way
+	var renderedValues map[string]interface{}
+	if err := yaml.Unmarshal(values, &renderedValues); err != nil {
+		return true
+	}
+
+	if len(release.Config) == 0 && len(renderedValues) == 0 {
 		return false
 	}
-	m.logger.Debugf("%s release values: \n%s \nuser values:\n%s ", apiCluster.GetName(), release.Config.Raw, string(values))
 
-	if len(release.Config.Raw) != len(string(values)) || release.Config.Raw != string(values) {
-		return true
+	return !reflect.DeepEqual(release.Config, renderedValues)
+}
+
+// getValuesForCluster returns the values of a deployment rendered for a member cluster
+func (m CGDeploymentManager) getValuesForCluster(apiCluster api.Cluster, depInfo *DeploymentInfo) ([]byte, error) {
+	cluster, err := m.getClusterTemplateData(apiCluster)
+	if err != nil {
+		return nil, err
 	}
-	return false
+
+	return depInfo.GetValuesForCluster(cluster)
+}
+
+func (m CGDeploymentManager) getClusterTemplateData(apiCluster api.Cluster) (ClusterTemplateData, error) {
+	ctx := context.Background()
+
+	cluster := ClusterTemplateData{
+		ID:           apiCluster.GetID(),
+		Name:         apiCluster.GetName(),
+		Cloud:        apiCluster.GetCloud(),
+		Distribution: apiCluster.GetDistribution(),
+		Location:     apiCluster.GetLocation(),
+	}
+
+	labels, err := m.clusterGetter.GetClusterLabels(ctx, apiCluster.GetID())
+	if err != nil {
+		return cluster, emperror.WrapWith(err, "could not get cluster labels", "clusterName", apiCluster.GetName())
+	}
+	cluster.Labels = labels
+
+	if m.clusterDomainGetter != nil {
+		domain, err := m.clusterDomainGetter.GetClusterDomain(ctx, apiCluster.GetID())
+		if err != nil {
+			return cluster, emperror.WrapWith(err, "could not get cluster domain", "clusterName", apiCluster.GetName())
+		}
+		cluster.Domain = domain
+	}
+
+	return cluster, nil
+}
+
+// validateValues checks that the values of a deployment can be rendered for every target cluster
+func (m CGDeploymentManager) validateValues(clusterGroup *api.ClusterGroup, deploymentModel *ClusterGroupDeploymentModel) error {
+	depInfo, err := m.getDeploymentFromModel(deploymentModel)
+	if err != nil {
+		return err
+	}
+
+	for _, apiCluster := range clusterGroup.Clusters {
+		if _, ok := depInfo.TargetClusters[apiCluster.GetID()]; !ok {
+			continue
+		}
+
+		_, err := m.getValuesForCluster(apiCluster, depInfo)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (m CGDeploymentManager) createDeploymentModel(clusterGroup *api.ClusterGroup, orgName string, cgDeployment *ClusterGroupDeployment, requestedChart *chart.Chart) (*ClusterGroupDeploymentModel, error) {
@@ -428,6 +526,22 @@ func (m CGDeploymentManager) getDeploymentFromModel(deploymentModel *ClusterGrou
 			deployment.ValueOverrides[targetCluster.ClusterName] = unmarshalledValues
 		}
 	}
+
+	if deploymentModel.RolloutStatus != "" {
+		deployment.Rollout = &RolloutInfo{
+			Status:      deploymentModel.RolloutStatus,
+			CurrentWave: deploymentModel.RolloutWave,
+			Waves:       deploymentModel.RolloutWaves,
+			Error:       deploymentModel.RolloutError,
+		}
+		if len(deploymentModel.RolloutStrategy) > 0 {
+			err = json.Unmarshal(deploymentModel.RolloutStrategy, &deployment.Rollout.Strategy)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return deployment, nil
 }
 
@@ -465,6 +579,25 @@ func (m CGDeploymentManager) GetDeployment(clusterGroup *api.ClusterGroup, deplo
 
 	targetClusterStatus = append(targetClusterStatus, m.addStaleClusterStatuses(clusterGroup.Clusters, deploymentModel.TargetClusters)...)
 
+	// add the rollout progress of each target cluster
+	if depInfo.Rollout != nil {
+		targets := make(map[uint]*TargetCluster, len(deploymentModel.TargetClusters))
+		for _, target := range deploymentModel.TargetClusters {
+			targets[target.ClusterID] = target
+		}
+
+		for i, status := range targetClusterStatus {
+			if target, ok := targets[status.ClusterId]; ok {
+				targetClusterStatus[i].Wave = target.Wave
+				targetClusterStatus[i].Canary = target.Canary
+				targetClusterStatus[i].RolloutStatus = target.RolloutStatus
+				if status.Error == "" {
+					targetClusterStatus[i].Error = target.RolloutError
+				}
+			}
+		}
+	}
+
 	depInfo.TargetClustersStatus = targetClusterStatus
 	return depInfo, nil
 }
@@ -551,12 +684,12 @@ func (m CGDeploymentManager) deleteDeploymentFromCluster(clusterId uint, apiClus
 	apiCluster = cluster
 
 	log.Info("deleting cluster group deployment from cluster")
-	k8sConfig, err := apiCluster.GetK8sConfig()
+	helmCluster, backend, err := m.getHelmBackend(ctx, apiCluster, "")
 	if err != nil {
 		return err
 	}
 
-	err = helm.DeleteDeployment(releaseName, k8sConfig)
+	err = backend.DeleteRelease(ctx, helmCluster, releaseName)
 	if err != nil {
 		// deployment not found error is ok in this case
 		if !strings.Contains(err.Error(), "not found") {
@@ -599,12 +732,13 @@ func (m CGDeploymentManager) SyncDeployment(clusterGroup *api.ClusterGroup, orgN
 	// get deployment status for each cluster group member
 	response := make([]TargetClusterStatus, 0)
 
+	// the chart is loaded before touching the clusters, so that a missing chart fails the whole sync
 	env := helm.GenerateHelmRepoEnv(orgName)
-	requestedChart, err := helm.GetRequestedChart(depInfo.ReleaseName, depInfo.Chart, depInfo.ChartVersion, deploymentModel.DeploymentPackage, env)
+	_, err = helm.GetRequestedChart(depInfo.ReleaseName, depInfo.Chart, depInfo.ChartVersion, deploymentModel.DeploymentPackage, env)
 	if err != nil {
 		return nil, fmt.Errorf("error loading chart: %v", err)
 	}
-	targetClustersStatus := m.upgradeOrInstallDeploymentToTargetClusters(clusterGroup, orgName, env, depInfo, requestedChart, false)
+	targetClustersStatus := m.upgradeOrInstallDeploymentToTargetClusters(clusterGroup, orgName, depInfo, deploymentModel.DeploymentPackage, false)
 	response = append(response, targetClustersStatus...)
 
 	targetClustersStatus, err = m.deleteDeploymentFromTargetClusters(clusterGroup, releaseName, deploymentModel, false, false)
@@ -675,7 +809,7 @@ func (m CGDeploymentManager) deleteDeploymentFromTargetClusters(clusterGroup *ap
 	return targetClustersStatus, nil
 }
 
-func (m CGDeploymentManager) upgradeOrInstallDeploymentToTargetClusters(clusterGroup *api.ClusterGroup, orgName string, env helm_env.EnvSettings, depInfo *DeploymentInfo, requestedChart *chart.Chart, dryRun bool) []TargetClusterStatus {
+func (m CGDeploymentManager) upgradeOrInstallDeploymentToTargetClusters(clusterGroup *api.ClusterGroup, orgName string, depInfo *DeploymentInfo, chartPackage []byte, dryRun bool) []TargetClusterStatus {
 	targetClusterStatus := make([]TargetClusterStatus, 0)
 	deploymentCount := 0
 	statusChan := make(chan TargetClusterStatus)
@@ -694,7 +828,8 @@ func (m CGDeploymentManager) upgradeOrInstallDeploymentToTargetClusters(clusterG
 					Distribution: apiCluster.GetDistribution(),
 					Status:       OperationSucceededStatus,
 				}
-				clerr := m.upgradeOrInstallDeploymentOnCluster(apiCluster, orgName, env, depInfo, requestedChart, dryRun)
+				status, clerr := m.upgradeOrInstallDeploymentOnCluster(apiCluster, orgName, depInfo, chartPackage, dryRun, 0)
+				opStatus.ValuesDrift = status.ValuesDrift
 				if clerr != nil {
 					opStatus.Status = OperationFailedStatus
 					opStatus.Error = clerr.Error()
@@ -719,6 +854,11 @@ func (m CGDeploymentManager) CreateDeployment(clusterGroup *api.ClusterGroup, or
 		return nil, errors.Errorf("release name is mandatory")
 	}
 
+	err := validateRollout(clusterGroup, cgDeployment)
+	if err != nil {
+		return nil, err
+	}
+
 	deploymentModel, err := m.repository.FindByName(clusterGroup.Id, cgDeployment.ReleaseName)
 	if err != nil && !IsDeploymentNotFoundError(err) {
 		return nil, err
@@ -750,6 +890,10 @@ func (m CGDeploymentManager) CreateDeployment(clusterGroup *api.ClusterGroup, or
 	if err != nil {
 		return nil, emperror.Wrap(err, "Error creating deployment model")
 	}
+	err = m.validateValues(clusterGroup, deploymentModel)
+	if err != nil {
+		return nil, err
+	}
 	if !cgDeployment.DryRun {
 		err = m.repository.Save(deploymentModel)
 		if err != nil {
@@ -762,13 +906,21 @@ func (m CGDeploymentManager) CreateDeployment(clusterGroup *api.ClusterGroup, or
 		return nil, err
 	}
 
-	targetClusterStatus := m.upgradeOrInstallDeploymentToTargetClusters(clusterGroup, orgName, env, depInfo, requestedChart, cgDeployment.DryRun)
+	if cgDeployment.Rollout != nil && !cgDeployment.DryRun {
+		return m.startRollout(clusterGroup, deploymentModel, depInfo, *cgDeployment.Rollout)
+	}
+
+	targetClusterStatus := m.upgradeOrInstallDeploymentToTargetClusters(clusterGroup, orgName, depInfo, cgDeployment.Package, cgDeployment.DryRun)
 	return targetClusterStatus, nil
 }
 
 // UpdateDeployment upgrades deployment using provided values or using already provided values if ReUseValues = true.
 // The deployment is installed on a member cluster in case it's was not installed previously.
 func (m CGDeploymentManager) UpdateDeployment(clusterGroup *api.ClusterGroup, orgName string, cgDeployment *ClusterGroupDeployment) ([]TargetClusterStatus, error) {
+	err := validateRollout(clusterGroup, cgDeployment)
+	if err != nil {
+		return nil, err
+	}
 
 	env := helm.GenerateHelmRepoEnv(orgName)
 	requestedChart, err := helm.GetRequestedChart(cgDeployment.ReleaseName, cgDeployment.Name, cgDeployment.Version, cgDeployment.Package, env)
@@ -796,7 +948,14 @@ func (m CGDeploymentManager) UpdateDeployment(clusterGroup *api.ClusterGroup, or
 	if err != nil {
 		return nil, emperror.Wrap(err, "Error updating deployment model")
 	}
+	err = m.validateValues(clusterGroup, deploymentModel)
+	if err != nil {
+		return nil, err
+	}
 	if !cgDeployment.DryRun {
+		// the update supersedes the previous rollout
+		resetRollout(deploymentModel)
+
 		err = m.repository.Save(deploymentModel)
 		if err != nil {
 			return nil, emperror.Wrap(err, "Error saving deployment model")
@@ -808,7 +967,11 @@ func (m CGDeploymentManager) UpdateDeployment(clusterGroup *api.ClusterGroup, or
 		return nil, err
 	}
 
-	targetClusterStatus := m.upgradeOrInstallDeploymentToTargetClusters(clusterGroup, orgName, env, depInfo, requestedChart, cgDeployment.DryRun)
+	if cgDeployment.Rollout != nil && !cgDeployment.DryRun {
+		return m.startRollout(clusterGroup, deploymentModel, depInfo, *cgDeployment.Rollout)
+	}
+
+	targetClusterStatus := m.upgradeOrInstallDeploymentToTargetClusters(clusterGroup, orgName, depInfo, cgDeployment.Package, cgDeployment.DryRun)
 	return targetClusterStatus, nil
 }
 
@@ -822,7 +985,7 @@ func (m *CGDeploymentManager) IsReleaseNameAvailable(clusterGroup *api.ClusterGr
 		count++
 		go func(apiCluster api.Cluster, name string) {
 			status, _ := m.findRelease(apiCluster, name)
-			if status != nil && status.Info.Deleted == nil {
+			if status != nil {
 				statusChan <- true
 			} else {
 				statusChan <- false
@@ -52,6 +52,13 @@ type ClusterGroupDeploymentModel struct {
 	OrganizationName      string
 	Values                []byte           `sql:"type:text;"`
 	TargetClusters        []*TargetCluster `gorm:"foreignkey:ClusterGroupDeploymentID"`
+
+	RolloutStrategy   []byte `sql:"type:text;"`
+	RolloutStatus     string
+	RolloutWave       int
+	RolloutWaves      int
+	RolloutError      string `sql:"type:text;"`
+	RolloutGeneration uint
 }
 
 // TargetCluster describes cluster specific values for a cluster group deployment
@@ -63,6 +70,12 @@ type TargetCluster struct {
 	CreatedAt                time.Time
 	UpdatedAt                *time.Time
 	Values                   []byte `sql:"type:text;"`
+
+	Wave             int
+	Canary           bool
+	RolloutStatus    string
+	RolloutError     string `sql:"type:text;"`
+	PreviousRevision int32
 }
 
 // Migrate executes the table migrations for the cluster module.
@@ -75,6 +75,50 @@ func (g *CGDeploymentRepository) Save(model *ClusterGroupDeploymentModel) error
 	return g.db.Save(model).Error
 }
 
+// SaveRolloutState saves the rollout state of a cluster group deployment and its target clusters.
+func (g *CGDeploymentRepository) SaveRolloutState(model *ClusterGroupDeploymentModel) error {
+	err := g.db.Model(model).UpdateColumns(map[string]interface{}{
+		"rollout_strategy":   model.RolloutStrategy,
+		"rollout_status":     model.RolloutStatus,
+		"rollout_wave":       model.RolloutWave,
+		"rollout_waves":      model.RolloutWaves,
+		"rollout_error":      model.RolloutError,
+		"rollout_generation": model.RolloutGeneration,
+	}).Error
+	if err != nil {
+		return errors.Wrap(err, "could not save rollout state of cluster group deployment")
+	}
+
+	for _, target := range model.TargetClusters {
+		err := g.db.Model(target).UpdateColumns(map[string]interface{}{
+			"wave":              target.Wave,
+			"canary":            target.Canary,
+			"rollout_status":    target.RolloutStatus,
+			"rollout_error":     target.RolloutError,
+			"previous_revision": target.PreviousRevision,
+		}).Error
+		if err != nil {
+			return errors.Wrap(err, "could not save rollout state of target cluster")
+		}
+	}
+
+	return nil
+}
+
+// SaveTargetRolloutState saves the rollout state of a single target cluster of a cluster group deployment.
+func (g *CGDeploymentRepository) SaveTargetRolloutState(target *TargetCluster) error {
+	err := g.db.Model(target).UpdateColumns(map[string]interface{}{
+		"rollout_status":    target.RolloutStatus,
+		"rollout_error":     target.RolloutError,
+		"previous_revision": target.PreviousRevision,
+	}).Error
+	if err != nil {
+		return errors.Wrap(err, "could not save rollout state of target cluster")
+	}
+
+	return nil
+}
+
 // Delete deletes a target cluster from deployment
 func (g *CGDeploymentRepository) DeleteTargetCluster(model *TargetCluster) error {
 	err := g.db.Delete(model).Error
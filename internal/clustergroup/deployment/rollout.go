@@ -0,0 +1,550 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/internal/clustergroup/api"
+)
+
+// rollout statuses of cluster group deployments
+const RolloutRunningStatus = "RUNNING"
+const RolloutSucceededStatus = "SUCCEEDED"
+const RolloutHaltedStatus = "HALTED"
+const RolloutRolledBackStatus = "ROLLED BACK"
+
+// rollout statuses of target clusters
+const RolloutPendingStatus = "PENDING"
+const RolloutDeployingStatus = "DEPLOYING"
+const RolloutHealthyStatus = "HEALTHY"
+const RolloutFailedStatus = "FAILED"
+
+const defaultRolloutHealthTimeout = 300
+
+// planRolloutWaves splits the target clusters of a deployment into rollout waves.
+// The first wave consists of the canary clusters (if any), the remaining clusters are deployed
+// in waves of the configured size ordered by their names.
+func planRolloutWaves(clusterNames []string, strategy RolloutStrategy) ([][]string, error) {
+	if strategy.WaveSize < 0 {
+		return nil, &invalidRolloutStrategyError{message: "wave size must not be negative"}
+	}
+
+	if strategy.HealthTimeout < 0 {
+		return nil, &invalidRolloutStrategyError{message: "health timeout must not be negative"}
+	}
+
+	targets := make(map[string]bool, len(clusterNames))
+	for _, name := range clusterNames {
+		targets[name] = true
+	}
+
+	var waves [][]string
+
+	canaries := make(map[string]bool, len(strategy.CanaryClusters))
+	if len(strategy.CanaryClusters) > 0 {
+		var canaryWave []string
+		for _, name := range strategy.CanaryClusters {
+			if !targets[name] {
+				return nil, &invalidRolloutStrategyError{message: fmt.Sprintf("canary cluster %q is not a member of the cluster group", name)}
+			}
+
+			if !canaries[name] {
+				canaries[name] = true
+				canaryWave = append(canaryWave, name)
+			}
+		}
+
+		sort.Strings(canaryWave)
+		waves = append(waves, canaryWave)
+	}
+
+	var remaining []string
+	for _, name := range clusterNames {
+		if !canaries[name] {
+			remaining = append(remaining, name)
+		}
+	}
+	sort.Strings(remaining)
+
+	waveSize := strategy.WaveSize
+	if waveSize == 0 {
+		waveSize = len(remaining)
+	}
+
+	for len(remaining) > 0 {
+		size := waveSize
+		if size > len(remaining) {
+			size = len(remaining)
+		}
+
+		waves = append(waves, remaining[:size])
+		remaining = remaining[size:]
+	}
+
+	return waves, nil
+}
+
+// validateRollout checks whether the rollout strategy of a deployment request is applicable to the cluster group.
+func validateRollout(clusterGroup *api.ClusterGroup, cgDeployment *ClusterGroupDeployment) error {
+	if cgDeployment.Rollout == nil {
+		return nil
+	}
+
+	clusterNames := make([]string, 0, len(clusterGroup.Clusters))
+	for _, apiCluster := range clusterGroup.Clusters {
+		clusterNames = append(clusterNames, apiCluster.GetName())
+	}
+
+	_, err := planRolloutWaves(clusterNames, *cgDeployment.Rollout)
+
+	return err
+}
+
+// resetRollout clears the rollout state of a deployment, stopping the rollout in progress (if any).
+func resetRollout(deploymentModel *ClusterGroupDeploymentModel) {
+	deploymentModel.RolloutGeneration++
+	deploymentModel.RolloutStrategy = nil
+	deploymentModel.RolloutStatus = ""
+	deploymentModel.RolloutWave = 0
+	deploymentModel.RolloutWaves = 0
+	deploymentModel.RolloutError = ""
+
+	for _, target := range deploymentModel.TargetClusters {
+		target.Wave = 0
+		target.Canary = false
+		target.RolloutStatus = ""
+		target.RolloutError = ""
+		target.PreviousRevision = 0
+	}
+}
+
+// startRollout plans the rollout waves of a deployment and starts rolling it out with a workflow.
+// The rollout state is saved, so the progress of the rollout is visible in the deployment details.
+func (m CGDeploymentManager) startRollout(
+	clusterGroup *api.ClusterGroup,
+	deploymentModel *ClusterGroupDeploymentModel,
+	depInfo *DeploymentInfo,
+	strategy RolloutStrategy,
+) ([]TargetClusterStatus, error) {
+	var clusterNames []string
+	for _, apiCluster := range clusterGroup.Clusters {
+		if _, ok := depInfo.TargetClusters[apiCluster.GetID()]; ok {
+			clusterNames = append(clusterNames, apiCluster.GetName())
+		}
+	}
+
+	waves, err := planRolloutWaves(clusterNames, strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	if strategy.HealthTimeout == 0 {
+		strategy.HealthTimeout = defaultRolloutHealthTimeout
+	}
+
+	rawStrategy, err := json.Marshal(strategy)
+	if err != nil {
+		return nil, err
+	}
+
+	resetRollout(deploymentModel)
+	deploymentModel.RolloutStrategy = rawStrategy
+	deploymentModel.RolloutStatus = RolloutRunningStatus
+	deploymentModel.RolloutWaves = len(waves)
+
+	clusterWaves := make(map[string]int, len(clusterNames))
+	for i, wave := range waves {
+		for _, name := range wave {
+			clusterWaves[name] = i + 1
+		}
+	}
+
+	targetClusterStatus := make([]TargetClusterStatus, 0, len(clusterNames))
+	for _, target := range deploymentModel.TargetClusters {
+		apiCluster, ok := clusterGroup.Clusters[target.ClusterID]
+		if !ok {
+			continue
+		}
+
+		target.Wave = clusterWaves[target.ClusterName]
+		target.Canary = target.Wave == 1 && len(strategy.CanaryClusters) > 0
+		target.RolloutStatus = RolloutPendingStatus
+
+		targetClusterStatus = append(targetClusterStatus, TargetClusterStatus{
+			ClusterId:     apiCluster.GetID(),
+			ClusterName:   apiCluster.GetName(),
+			Cloud:         apiCluster.GetCloud(),
+			Distribution:  apiCluster.GetDistribution(),
+			Status:        RolloutPendingStatus,
+			Wave:          target.Wave,
+			Canary:        target.Canary,
+			RolloutStatus: target.RolloutStatus,
+		})
+	}
+
+	err = m.repository.SaveRolloutState(deploymentModel)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.startRolloutWorkflow(deploymentModel, strategy.HealthTimeout)
+	if err != nil {
+		deploymentModel.RolloutStatus = RolloutHaltedStatus
+		deploymentModel.RolloutError = err.Error()
+		m.saveRolloutState(m.logger, deploymentModel)
+
+		return nil, err
+	}
+
+	return targetClusterStatus, nil
+}
+
+// startRolloutWorkflow starts the workflow rolling out a deployment wave by wave.
+// Every rollout generation has its own workflow: a superseded rollout stops at its next step.
+func (m CGDeploymentManager) startRolloutWorkflow(deploymentModel *ClusterGroupDeploymentModel, healthTimeout int64) error {
+	workflowID := fmt.Sprintf(
+		"clustergroup-deployment-rollout-%d-%s-%d",
+		deploymentModel.ClusterGroupID,
+		deploymentModel.DeploymentReleaseName,
+		deploymentModel.RolloutGeneration,
+	)
+
+	options := client.StartWorkflowOptions{
+		ID:                           workflowID,
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: time.Duration(deploymentModel.RolloutWaves) * (time.Duration(healthTimeout)*time.Second + time.Hour),
+	}
+
+	input := RolloutWorkflowInput{
+		ClusterGroupID: deploymentModel.ClusterGroupID,
+		ReleaseName:    deploymentModel.DeploymentReleaseName,
+		Generation:     deploymentModel.RolloutGeneration,
+		HealthTimeout:  healthTimeout,
+	}
+
+	_, err := m.workflowClient.StartWorkflow(context.Background(), options, RolloutWorkflowName, input)
+	if err != nil {
+		return errors.Wrap(err, "failed to start rollout workflow")
+	}
+
+	return nil
+}
+
+// startRolloutWave marks the target clusters of the next wave of a rollout as deploying.
+// The rollout succeeds when there are no waves left.
+func (m CGDeploymentManager) startRolloutWave(input StartRolloutWaveActivityInput) (StartRolloutWaveActivityOutput, error) {
+	var output StartRolloutWaveActivityOutput
+
+	log := m.logger.WithFields(logrus.Fields{"clusterGroupId": input.ClusterGroupID, "releaseName": input.ReleaseName, "wave": input.Wave})
+
+	deploymentModel, err := m.repository.FindByName(input.ClusterGroupID, input.ReleaseName)
+	if IsDeploymentNotFoundError(err) {
+		log.Info("rollout is superseded by the deletion of the deployment")
+		output.Superseded = true
+
+		return output, nil
+	} else if err != nil {
+		return output, err
+	}
+
+	if deploymentModel.RolloutGeneration != input.Generation {
+		log.Info("rollout is superseded by an other update of the deployment")
+		output.Superseded = true
+
+		return output, nil
+	}
+
+	if input.Wave > deploymentModel.RolloutWaves {
+		deploymentModel.RolloutStatus = RolloutSucceededStatus
+		output.Finished = true
+
+		log.Info("rollout of cluster group deployment succeeded")
+
+		return output, m.repository.SaveRolloutState(deploymentModel)
+	}
+
+	log.Info("rolling out cluster group deployment")
+
+	deploymentModel.RolloutWave = input.Wave
+
+	for _, target := range deploymentModel.TargetClusters {
+		if target.Wave == input.Wave {
+			target.RolloutStatus = RolloutDeployingStatus
+			target.RolloutError = ""
+			output.ClusterIDs = append(output.ClusterIDs, target.ClusterID)
+		}
+	}
+
+	return output, m.repository.SaveRolloutState(deploymentModel)
+}
+
+// deployRolloutTarget deploys the current version of a deployment to a target cluster of a rollout
+// and records whether the release became healthy.
+func (m CGDeploymentManager) deployRolloutTarget(ctx context.Context, input DeployRolloutTargetActivityInput) error {
+	deploymentModel, err := m.repository.FindByName(input.ClusterGroupID, input.ReleaseName)
+	if IsDeploymentNotFoundError(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if deploymentModel.RolloutGeneration != input.Generation {
+		return nil
+	}
+
+	var target *TargetCluster
+	for _, t := range deploymentModel.TargetClusters {
+		if t.ClusterID == input.ClusterID {
+			target = t
+		}
+	}
+
+	if target == nil {
+		return errors.New("cluster is not a target of the deployment anymore")
+	}
+
+	target.RolloutStatus = RolloutHealthyStatus
+	target.RolloutError = ""
+
+	previousRevision, err := m.deployToRolloutTarget(ctx, deploymentModel, input)
+	target.PreviousRevision = previousRevision
+	if err != nil {
+		target.RolloutStatus = RolloutFailedStatus
+		target.RolloutError = err.Error()
+	}
+
+	return m.repository.SaveTargetRolloutState(target)
+}
+
+// deployToRolloutTarget upgrades or installs a deployment on a target cluster.
+// It returns the revision of the release before the deployment (if there was any), even if the deployment fails.
+func (m CGDeploymentManager) deployToRolloutTarget(ctx context.Context, deploymentModel *ClusterGroupDeploymentModel, input DeployRolloutTargetActivityInput) (int32, error) {
+	apiCluster, err := m.clusterGetter.GetClusterByIDOnly(ctx, input.ClusterID)
+	if err != nil {
+		return 0, err
+	}
+
+	depInfo, err := m.getDeploymentFromModel(deploymentModel)
+	if err != nil {
+		return 0, err
+	}
+
+	var previousRevision int32
+
+	release, err := m.findRelease(apiCluster, deploymentModel.DeploymentReleaseName)
+	if err != nil {
+		return 0, err
+	}
+	if release != nil {
+		previousRevision = int32(release.Version)
+	}
+
+	_, err = m.upgradeOrInstallDeploymentOnCluster(apiCluster, deploymentModel.OrganizationName, depInfo, deploymentModel.DeploymentPackage, false, input.HealthTimeout)
+
+	return previousRevision, err
+}
+
+// finishRolloutWave halts the rollout if the release is not healthy on every cluster of a wave.
+// Target clusters the deployment was interrupted on (eg. by a timeout) are marked as failed.
+func (m CGDeploymentManager) finishRolloutWave(input FinishRolloutWaveActivityInput) (FinishRolloutWaveActivityOutput, error) {
+	var output FinishRolloutWaveActivityOutput
+
+	deploymentModel, err := m.repository.FindByName(input.ClusterGroupID, input.ReleaseName)
+	if IsDeploymentNotFoundError(err) {
+		output.Superseded = true
+
+		return output, nil
+	} else if err != nil {
+		return output, err
+	}
+
+	if deploymentModel.RolloutGeneration != input.Generation {
+		output.Superseded = true
+
+		return output, nil
+	}
+
+	failed := 0
+	for _, target := range deploymentModel.TargetClusters {
+		if target.Wave != input.Wave {
+			continue
+		}
+
+		if target.RolloutStatus == RolloutDeployingStatus {
+			target.RolloutStatus = RolloutFailedStatus
+			target.RolloutError = "deployment was interrupted"
+			if message, ok := input.Errors[target.ClusterID]; ok {
+				target.RolloutError = message
+			}
+		}
+
+		if target.RolloutStatus == RolloutFailedStatus {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		deploymentModel.RolloutStatus = RolloutHaltedStatus
+		deploymentModel.RolloutError = fmt.Sprintf("release is not healthy on %d cluster(s) of wave %d", failed, input.Wave)
+		output.Halted = true
+
+		m.logger.WithFields(logrus.Fields{
+			"clusterGroupId": input.ClusterGroupID,
+			"releaseName":    input.ReleaseName,
+			"wave":           input.Wave,
+		}).Warn("rollout of cluster group deployment halted")
+	}
+
+	return output, m.repository.SaveRolloutState(deploymentModel)
+}
+
+func (m CGDeploymentManager) saveRolloutState(log logrus.FieldLogger, deploymentModel *ClusterGroupDeploymentModel) bool {
+	err := m.repository.SaveRolloutState(deploymentModel)
+	if err != nil {
+		log.Error(err.Error())
+		m.errorHandler.Handle(err)
+
+		return false
+	}
+
+	return true
+}
+
+// RollbackDeployment rolls back a halted rollout of a deployment.
+// Clusters already reached by the rollout are rolled back to their previous release revision,
+// releases installed by the rollout are deleted.
+func (m CGDeploymentManager) RollbackDeployment(clusterGroup *api.ClusterGroup, releaseName string) ([]TargetClusterStatus, error) {
+	deploymentModel, err := m.repository.FindByName(clusterGroup.Id, releaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	if deploymentModel.RolloutStatus != RolloutHaltedStatus {
+		return nil, &rolloutNotHaltedError{
+			releaseName:   releaseName,
+			rolloutStatus: deploymentModel.RolloutStatus,
+		}
+	}
+
+	targetClusterStatus := make([]TargetClusterStatus, 0)
+	statusChan := make(chan TargetClusterStatus)
+	defer close(statusChan)
+
+	count := 0
+	targets := make(map[uint]*TargetCluster)
+	for _, target := range deploymentModel.TargetClusters {
+		switch target.RolloutStatus {
+		case RolloutHealthyStatus, RolloutFailedStatus, RolloutDeployingStatus:
+		default:
+			continue
+		}
+
+		count++
+		targets[target.ClusterID] = target
+		go func(target TargetCluster) {
+			opStatus := TargetClusterStatus{
+				ClusterId:   target.ClusterID,
+				ClusterName: target.ClusterName,
+				Status:      OperationSucceededStatus,
+				Wave:        target.Wave,
+				Canary:      target.Canary,
+			}
+
+			err := m.rollbackDeploymentOnCluster(clusterGroup, target, releaseName)
+			if err != nil {
+				opStatus.Status = OperationFailedStatus
+				opStatus.Error = err.Error()
+			}
+
+			statusChan <- opStatus
+		}(*target)
+	}
+
+	// wait for goroutines to finish
+	failed := 0
+	for i := 0; i < count; i++ {
+		status := <-statusChan
+
+		target := targets[status.ClusterId]
+		if status.Error == "" {
+			target.RolloutStatus = RolloutRolledBackStatus
+			target.RolloutError = ""
+		} else {
+			failed++
+			target.RolloutError = status.Error
+		}
+		status.RolloutStatus = target.RolloutStatus
+
+		targetClusterStatus = append(targetClusterStatus, status)
+	}
+
+	// a rollback which failed on some of the clusters can be retried
+	if failed == 0 {
+		deploymentModel.RolloutStatus = RolloutRolledBackStatus
+		deploymentModel.RolloutError = ""
+	} else {
+		deploymentModel.RolloutError = fmt.Sprintf("rollback failed on %d cluster(s)", failed)
+	}
+
+	err = m.repository.SaveRolloutState(deploymentModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return targetClusterStatus, nil
+}
+
+func (m CGDeploymentManager) rollbackDeploymentOnCluster(clusterGroup *api.ClusterGroup, target TargetCluster, releaseName string) error {
+	apiCluster, ok := clusterGroup.Clusters[target.ClusterID]
+	if !ok {
+		return fmt.Errorf("cluster is not member of the cluster group anymore")
+	}
+
+	log := m.logger.WithFields(logrus.Fields{"releaseName": releaseName, "clusterName": apiCluster.GetName(), "clusterId": apiCluster.GetID()})
+
+	ctx := context.Background()
+	cluster, backend, err := m.getHelmBackend(ctx, apiCluster, "")
+	if err != nil {
+		return err
+	}
+
+	if target.PreviousRevision > 0 {
+		log.WithField("revision", target.PreviousRevision).Info("rolling back cluster group deployment")
+
+		_, err := backend.RollbackRelease(ctx, cluster, releaseName, int(target.PreviousRevision))
+
+		return err
+	}
+
+	log.Info("deleting cluster group deployment installed by the rollout")
+
+	err = backend.DeleteRelease(ctx, cluster, releaseName)
+	// deployment not found error is ok in this case
+	if err != nil && !strings.Contains(err.Error(), "not found") {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"context"
+)
+
+const StartRolloutWaveActivityName = "clustergroup-deployment-rollout-start-wave"
+
+type StartRolloutWaveActivityInput struct {
+	ClusterGroupID uint
+	ReleaseName    string
+	Generation     uint
+	Wave           int
+}
+
+type StartRolloutWaveActivityOutput struct {
+	// ClusterIDs are the target clusters of the wave
+	ClusterIDs []uint
+
+	// Finished is true when there are no waves left
+	Finished bool
+
+	// Superseded is true when the deployment was updated or deleted since the rollout started
+	Superseded bool
+}
+
+// StartRolloutWaveActivity marks the target clusters of the next wave of a rollout as deploying.
+type StartRolloutWaveActivity struct {
+	manager *CGDeploymentManager
+}
+
+// NewStartRolloutWaveActivity returns a new StartRolloutWaveActivity.
+func NewStartRolloutWaveActivity(manager *CGDeploymentManager) StartRolloutWaveActivity {
+	return StartRolloutWaveActivity{
+		manager: manager,
+	}
+}
+
+func (a StartRolloutWaveActivity) Execute(ctx context.Context, input StartRolloutWaveActivityInput) (StartRolloutWaveActivityOutput, error) {
+	return a.manager.startRolloutWave(input)
+}
+
+const DeployRolloutTargetActivityName = "clustergroup-deployment-rollout-deploy-target"
+
+type DeployRolloutTargetActivityInput struct {
+	ClusterGroupID uint
+	ReleaseName    string
+	Generation     uint
+	ClusterID      uint
+	HealthTimeout  int64
+}
+
+// DeployRolloutTargetActivity deploys a release to a target cluster of a rollout.
+type DeployRolloutTargetActivity struct {
+	manager *CGDeploymentManager
+}
+
+// NewDeployRolloutTargetActivity returns a new DeployRolloutTargetActivity.
+func NewDeployRolloutTargetActivity(manager *CGDeploymentManager) DeployRolloutTargetActivity {
+	return DeployRolloutTargetActivity{
+		manager: manager,
+	}
+}
+
+func (a DeployRolloutTargetActivity) Execute(ctx context.Context, input DeployRolloutTargetActivityInput) error {
+	return a.manager.deployRolloutTarget(ctx, input)
+}
+
+const FinishRolloutWaveActivityName = "clustergroup-deployment-rollout-finish-wave"
+
+type FinishRolloutWaveActivityInput struct {
+	ClusterGroupID uint
+	ReleaseName    string
+	Generation     uint
+	Wave           int
+
+	// Errors are the errors of the failed deployment activities by cluster ID
+	Errors map[uint]string
+}
+
+type FinishRolloutWaveActivityOutput struct {
+	// Halted is true when the release is not healthy on every cluster of the wave
+	Halted bool
+
+	// Superseded is true when the deployment was updated or deleted since the rollout started
+	Superseded bool
+}
+
+// FinishRolloutWaveActivity halts the rollout if a wave failed.
+type FinishRolloutWaveActivity struct {
+	manager *CGDeploymentManager
+}
+
+// NewFinishRolloutWaveActivity returns a new FinishRolloutWaveActivity.
+func NewFinishRolloutWaveActivity(manager *CGDeploymentManager) FinishRolloutWaveActivity {
+	return FinishRolloutWaveActivity{
+		manager: manager,
+	}
+}
+
+func (a FinishRolloutWaveActivity) Execute(ctx context.Context, input FinishRolloutWaveActivityInput) (FinishRolloutWaveActivityOutput, error) {
+	return a.manager.finishRolloutWave(input)
+}
@@ -0,0 +1,90 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanRolloutWaves(t *testing.T) {
+	clusters := []string{"c5", "c1", "c4", "c2", "c3"}
+
+	tests := []struct {
+		name     string
+		strategy RolloutStrategy
+		expected [][]string
+	}{
+		{
+			name:     "single wave",
+			strategy: RolloutStrategy{},
+			expected: [][]string{{"c1", "c2", "c3", "c4", "c5"}},
+		},
+		{
+			name:     "waves",
+			strategy: RolloutStrategy{WaveSize: 2},
+			expected: [][]string{{"c1", "c2"}, {"c3", "c4"}, {"c5"}},
+		},
+		{
+			name:     "canary",
+			strategy: RolloutStrategy{CanaryClusters: []string{"c4"}},
+			expected: [][]string{{"c4"}, {"c1", "c2", "c3", "c5"}},
+		},
+		{
+			name:     "canaries and waves",
+			strategy: RolloutStrategy{CanaryClusters: []string{"c5", "c2", "c5"}, WaveSize: 2},
+			expected: [][]string{{"c2", "c5"}, {"c1", "c3"}, {"c4"}},
+		},
+		{
+			name:     "only canaries",
+			strategy: RolloutStrategy{CanaryClusters: []string{"c1", "c2", "c3", "c4", "c5"}, WaveSize: 2},
+			expected: [][]string{{"c1", "c2", "c3", "c4", "c5"}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			waves, err := planRolloutWaves(clusters, test.strategy)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, waves)
+		})
+	}
+}
+
+func TestPlanRolloutWaves_Invalid(t *testing.T) {
+	clusters := []string{"c1", "c2"}
+
+	tests := map[string]RolloutStrategy{
+		"unknown canary":   {CanaryClusters: []string{"c3"}},
+		"negative wave":    {WaveSize: -1},
+		"negative timeout": {HealthTimeout: -1},
+	}
+
+	for name, strategy := range tests {
+		strategy := strategy
+
+		t.Run(name, func(t *testing.T) {
+			_, err := planRolloutWaves(clusters, strategy)
+			require.Error(t, err)
+
+			assert.True(t, IsInvalidRolloutStrategyError(err))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"time"
+
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/workflow"
+)
+
+// RolloutWorkflowName can be used to reference the rollout workflow.
+const RolloutWorkflowName = "clustergroup-deployment-rollout"
+
+// RolloutWorkflowInput is the input for a rollout workflow.
+type RolloutWorkflowInput struct {
+	ClusterGroupID uint
+	ReleaseName    string
+
+	// Generation is the rollout generation of the deployment the workflow rolls out
+	Generation uint
+
+	// HealthTimeout is the time (in seconds) a release has to become healthy on a target cluster
+	HealthTimeout int64
+}
+
+// RolloutWorkflow rolls out a cluster group deployment wave by wave.
+// It stops when a wave fails or when the rollout is superseded by an other update of the deployment.
+// The progress of the rollout is persisted by the activities, so it survives a restart of the workers.
+func RolloutWorkflow(ctx workflow.Context, input RolloutWorkflowInput) error {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+		WaitForCancellation:    true,
+		RetryPolicy: &cadence.RetryPolicy{
+			InitialInterval:    5 * time.Second,
+			BackoffCoefficient: 1.5,
+			MaximumInterval:    time.Minute,
+			MaximumAttempts:    10,
+		},
+	})
+
+	// deployments are not retried: a failing release halts the rollout
+	deployCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    time.Duration(input.HealthTimeout)*time.Second + 5*time.Minute,
+		WaitForCancellation:    true,
+	})
+
+	for wave := 1; ; wave++ {
+		var started StartRolloutWaveActivityOutput
+		{
+			activityInput := StartRolloutWaveActivityInput{
+				ClusterGroupID: input.ClusterGroupID,
+				ReleaseName:    input.ReleaseName,
+				Generation:     input.Generation,
+				Wave:           wave,
+			}
+
+			err := workflow.ExecuteActivity(ctx, StartRolloutWaveActivityName, activityInput).Get(ctx, &started)
+			if err != nil {
+				return err
+			}
+		}
+
+		if started.Superseded || started.Finished {
+			return nil
+		}
+
+		futures := make(map[uint]workflow.Future, len(started.ClusterIDs))
+		for _, clusterID := range started.ClusterIDs {
+			activityInput := DeployRolloutTargetActivityInput{
+				ClusterGroupID: input.ClusterGroupID,
+				ReleaseName:    input.ReleaseName,
+				Generation:     input.Generation,
+				ClusterID:      clusterID,
+				HealthTimeout:  input.HealthTimeout,
+			}
+
+			futures[clusterID] = workflow.ExecuteActivity(deployCtx, DeployRolloutTargetActivityName, activityInput)
+		}
+
+		errs := make(map[uint]string)
+		for _, clusterID := range started.ClusterIDs {
+			if err := futures[clusterID].Get(ctx, nil); err != nil {
+				errs[clusterID] = err.Error()
+			}
+		}
+
+		var finished FinishRolloutWaveActivityOutput
+		{
+			activityInput := FinishRolloutWaveActivityInput{
+				ClusterGroupID: input.ClusterGroupID,
+				ReleaseName:    input.ReleaseName,
+				Generation:     input.Generation,
+				Wave:           wave,
+				Errors:         errs,
+			}
+
+			err := workflow.ExecuteActivity(ctx, FinishRolloutWaveActivityName, activityInput).Get(ctx, &finished)
+			if err != nil {
+				return err
+			}
+		}
+
+		if finished.Superseded || finished.Halted {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/testsuite"
+	"go.uber.org/cadence/workflow"
+)
+
+// nolint: gochecknoglobals
+var testRolloutWorkflowInput = RolloutWorkflowInput{
+	ClusterGroupID: 1,
+	ReleaseName:    "my-release",
+	Generation:     3,
+	HealthTimeout:  60,
+}
+
+type RolloutWorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env *testsuite.TestWorkflowEnvironment
+}
+
+func TestRolloutWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(RolloutWorkflowTestSuite))
+}
+
+func (s *RolloutWorkflowTestSuite) SetupSuite() {
+	workflow.RegisterWithOptions(RolloutWorkflow, workflow.RegisterOptions{Name: RolloutWorkflowName})
+
+	activity.RegisterWithOptions(StartRolloutWaveActivity{}.Execute, activity.RegisterOptions{Name: StartRolloutWaveActivityName})
+	activity.RegisterWithOptions(DeployRolloutTargetActivity{}.Execute, activity.RegisterOptions{Name: DeployRolloutTargetActivityName})
+	activity.RegisterWithOptions(FinishRolloutWaveActivity{}.Execute, activity.RegisterOptions{Name: FinishRolloutWaveActivityName})
+}
+
+func (s *RolloutWorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+}
+
+func (s *RolloutWorkflowTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *RolloutWorkflowTestSuite) onStartWave(wave int, output StartRolloutWaveActivityOutput) {
+	s.env.OnActivity(
+		StartRolloutWaveActivityName,
+		mock.Anything,
+		StartRolloutWaveActivityInput{ClusterGroupID: 1, ReleaseName: "my-release", Generation: 3, Wave: wave},
+	).Return(output, nil).Once()
+}
+
+func (s *RolloutWorkflowTestSuite) onDeploy(clusterID uint, err error) {
+	s.env.OnActivity(
+		DeployRolloutTargetActivityName,
+		mock.Anything,
+		DeployRolloutTargetActivityInput{ClusterGroupID: 1, ReleaseName: "my-release", Generation: 3, ClusterID: clusterID, HealthTimeout: 60},
+	).Return(err).Once()
+}
+
+func (s *RolloutWorkflowTestSuite) onFinishWave(wave int, errs map[uint]string, output FinishRolloutWaveActivityOutput) {
+	s.env.OnActivity(
+		FinishRolloutWaveActivityName,
+		mock.Anything,
+		FinishRolloutWaveActivityInput{ClusterGroupID: 1, ReleaseName: "my-release", Generation: 3, Wave: wave, Errors: errs},
+	).Return(output, nil).Once()
+}
+
+func (s *RolloutWorkflowTestSuite) Test_Success() {
+	s.onStartWave(1, StartRolloutWaveActivityOutput{ClusterIDs: []uint{10}})
+	s.onDeploy(10, nil)
+	s.onFinishWave(1, map[uint]string{}, FinishRolloutWaveActivityOutput{})
+
+	s.onStartWave(2, StartRolloutWaveActivityOutput{ClusterIDs: []uint{11, 12}})
+	s.onDeploy(11, nil)
+	s.onDeploy(12, nil)
+	s.onFinishWave(2, map[uint]string{}, FinishRolloutWaveActivityOutput{})
+
+	s.onStartWave(3, StartRolloutWaveActivityOutput{Finished: true})
+
+	s.env.ExecuteWorkflow(RolloutWorkflowName, testRolloutWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *RolloutWorkflowTestSuite) Test_HaltsOnFailedWave() {
+	s.onStartWave(1, StartRolloutWaveActivityOutput{ClusterIDs: []uint{10, 11}})
+	s.onDeploy(10, nil)
+	s.onDeploy(11, errors.New("release is not healthy"))
+	s.onFinishWave(1, map[uint]string{11: "release is not healthy"}, FinishRolloutWaveActivityOutput{Halted: true})
+
+	s.env.ExecuteWorkflow(RolloutWorkflowName, testRolloutWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *RolloutWorkflowTestSuite) Test_Superseded() {
+	s.onStartWave(1, StartRolloutWaveActivityOutput{Superseded: true})
+
+	s.env.ExecuteWorkflow(RolloutWorkflowName, testRolloutWorkflowInput)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"github.com/banzaicloud/pipeline/internal/helm/valuetemplate"
+)
+
+// ClusterTemplateData describes the attributes of a member cluster the values of a deployment can refer to.
+type ClusterTemplateData struct {
+	ID           uint
+	Name         string
+	Cloud        string
+	Distribution string
+	Location     string
+	Labels       map[string]string
+
+	// Domain is the DNS domain of the cluster if the DNS feature is active on the cluster.
+	Domain string
+}
+
+// renderValues evaluates the templates in the string values of a deployment for a member cluster.
+//
+// Templates can refer to the attributes of the cluster ({{ .Cluster.Location }}, {{ .Cluster.Labels.env }},
+// {{ .Cluster.Domain }}) and the release ({{ .Release.Name }}, {{ .Release.Namespace }}).
+// Other templates are left untouched, so that the chart can evaluate them (eg. with tpl).
+func renderValues(values map[string]interface{}, cluster ClusterTemplateData, releaseName string, namespace string) (map[string]interface{}, error) {
+	if cluster.Labels == nil {
+		cluster.Labels = map[string]string{}
+	}
+
+	data := map[string]interface{}{
+		"Cluster": cluster,
+		"Release": map[string]interface{}{
+			"Name":      releaseName,
+			"Namespace": namespace,
+		},
+	}
+
+	rendered, err := valuetemplate.RenderKnown(values, data)
+	if err != nil {
+		return nil, &invalidValuesError{
+			clusterName: cluster.Name,
+			message:     err.Error(),
+		}
+	}
+
+	return rendered, nil
+}
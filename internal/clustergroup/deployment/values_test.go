@@ -0,0 +1,111 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentInfo_GetValuesForCluster(t *testing.T) {
+	depInfo := DeploymentInfo{
+		ReleaseName: "app",
+		Namespace:   "apps",
+		Values: map[string]interface{}{
+			"endpoint": "https://api.{{ .Cluster.Location }}.example.com",
+			"ingress": map[string]interface{}{
+				"hosts": []interface{}{"app.{{ .Cluster.Domain }}"},
+			},
+			"env":      "{{ .Cluster.Labels.env }}",
+			"replicas": 2,
+		},
+		ValueOverrides: map[string]map[string]interface{}{
+			"eks": {
+				"provider": "{{ .Cluster.Cloud }}/{{ .Cluster.Distribution }}",
+				"release":  "{{ .Release.Namespace }}/{{ .Release.Name }}",
+			},
+		},
+	}
+
+	cluster := ClusterTemplateData{
+		Name:         "eks",
+		Cloud:        "amazon",
+		Distribution: "eks",
+		Location:     "eu-west-1",
+		Domain:       "eks.org.example.com",
+		Labels:       map[string]string{"env": "prod"},
+	}
+
+	values, err := depInfo.GetValuesForCluster(cluster)
+	require.NoError(t, err)
+
+	expected := `endpoint: https://api.eu-west-1.example.com
+env: prod
+ingress:
+  hosts:
+  - app.eks.org.example.com
+provider: amazon/eks
+release: apps/app
+replicas: 2
+`
+	assert.Equal(t, expected, string(values))
+}
+
+func TestDeploymentInfo_GetValuesForCluster_InvalidTemplate(t *testing.T) {
+	depInfo := DeploymentInfo{
+		Values: map[string]interface{}{
+			"env": "{{ .Cluster.Labels.env }}",
+		},
+	}
+
+	_, err := depInfo.GetValuesForCluster(ClusterTemplateData{Name: "unlabeled"})
+	require.Error(t, err)
+
+	assert.True(t, IsInvalidValuesError(err))
+}
+
+func TestDeploymentInfo_GetValuesForCluster_Environment(t *testing.T) {
+	depInfo := DeploymentInfo{
+		Values: map[string]interface{}{
+			"home": `{{ .Cluster.Name }}: {{ env "HOME" }}`,
+		},
+	}
+
+	_, err := depInfo.GetValuesForCluster(ClusterTemplateData{Name: "eks"})
+	require.Error(t, err)
+
+	assert.True(t, IsInvalidValuesError(err))
+}
+
+func TestDeploymentInfo_GetValuesForCluster_ChartTemplates(t *testing.T) {
+	depInfo := DeploymentInfo{
+		Values: map[string]interface{}{
+			"config":   "{{ .Values.image.tag }}",
+			"fullname": `{{ include "app.fullname" . }}`,
+			"home":     `{{ env "HOME" }}`,
+		},
+	}
+
+	values, err := depInfo.GetValuesForCluster(ClusterTemplateData{Name: "eks"})
+	require.NoError(t, err)
+
+	expected := `config: '{{ .Values.image.tag }}'
+fullname: '{{ include "app.fullname" . }}'
+home: '{{ env "HOME" }}'
+`
+	assert.Equal(t, expected, string(values))
+}
@@ -16,7 +16,9 @@ package clustergroup
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"sync"
 
 	"emperror.dev/emperror"
 	"github.com/pkg/errors"
@@ -34,6 +36,9 @@ type Manager struct {
 	logger            logrus.FieldLogger
 	errorHandler      emperror.Handler
 	featureHandlerMap map[string]api.FeatureHandler
+
+	// membershipMu serializes the re-evaluation of selector based memberships
+	membershipMu sync.Mutex
 }
 
 // NewManager returns a new Manager instance.
@@ -63,7 +68,7 @@ func isValidClusterStatus(clusterStatus *cluster.GetClusterStatusResponse) bool
 }
 
 // CreateClusterGroup creates a cluster group
-func (g *Manager) CreateClusterGroup(ctx context.Context, name string, orgID uint, members []uint) (*uint, error) {
+func (g *Manager) CreateClusterGroup(ctx context.Context, name string, orgID uint, members []uint, selector *api.ClusterSelector) (*uint, error) {
 	cgModel, err := g.cgRepo.FindOne(ClusterGroupModel{
 		OrganizationID: orgID,
 		Name:           name,
@@ -80,6 +85,28 @@ func (g *Manager) CreateClusterGroup(ctx context.Context, name string, orgID uin
 	}
 
 	memberClusterModels := make([]MemberClusterModel, 0)
+
+	var selectorJSON []byte
+	if selector != nil {
+		g.membershipMu.Lock()
+		defer g.membershipMu.Unlock()
+
+		selectorJSON, err = json.Marshal(selector)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		selectedClusters, err := g.selectMembers(ctx, orgID, 0, selector)
+		if err != nil {
+			return nil, err
+		}
+		for clusterID := range selectedClusters {
+			memberClusterModels = append(memberClusterModels, MemberClusterModel{
+				ClusterID: clusterID,
+			})
+		}
+	}
+
 	for _, clusterID := range members {
 		var cluster api.Cluster
 		cluster, err := g.clusterGetter.GetClusterByID(ctx, orgID, clusterID)
@@ -119,7 +146,7 @@ func (g *Manager) CreateClusterGroup(ctx context.Context, name string, orgID uin
 		}
 	}
 
-	cgId, err := g.cgRepo.Create(name, orgID, memberClusterModels)
+	cgId, err := g.cgRepo.Create(name, orgID, selectorJSON, memberClusterModels)
 	if err != nil {
 		return nil, err
 	}
@@ -139,7 +166,10 @@ func (g *Manager) CreateClusterGroup(ctx context.Context, name string, orgID uin
 }
 
 // UpdateClusterGroup updates a cluster group
-func (g *Manager) UpdateClusterGroup(ctx context.Context, clusterGroupID uint, orgID uint, name string, members []uint) error {
+func (g *Manager) UpdateClusterGroup(ctx context.Context, clusterGroupID uint, orgID uint, name string, members []uint, selector *api.ClusterSelector) error {
+	g.membershipMu.Lock()
+	defer g.membershipMu.Unlock()
+
 	cgModel, err := g.cgRepo.FindOne(ClusterGroupModel{
 		ID:             clusterGroupID,
 		OrganizationID: orgID,
@@ -151,6 +181,20 @@ func (g *Manager) UpdateClusterGroup(ctx context.Context, clusterGroupID uint, o
 	existingClusterGroup := g.GetClusterGroupFromModel(ctx, cgModel, false)
 	newMembers := make(map[uint]api.Cluster, 0)
 
+	var selectorJSON []byte
+	if selector != nil {
+		selectorJSON, err = json.Marshal(selector)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		newMembers, err = g.selectMembers(ctx, orgID, existingClusterGroup.Id, selector)
+		if err != nil {
+			return err
+		}
+	}
+	existingClusterGroup.Selector = selector
+
 	for _, clusterID := range members {
 		var cluster api.Cluster
 		cluster, err = g.clusterGetter.GetClusterByID(ctx, orgID, clusterID)
@@ -195,6 +239,11 @@ func (g *Manager) UpdateClusterGroup(ctx context.Context, clusterGroupID uint, o
 		return emperror.Wrap(err, "updating cluster group is not allowed")
 	}
 
+	err = g.cgRepo.UpdateSelector(existingClusterGroup.Id, selectorJSON)
+	if err != nil {
+		return err
+	}
+
 	err = g.cgRepo.UpdateMembers(existingClusterGroup, newMembers)
 	if err != nil {
 		return err
@@ -236,6 +285,9 @@ func (g *Manager) DeleteClusterGroupByID(ctx context.Context, orgID uint, cluste
 
 // RemoveClusterFromGroup removes a cluster from group
 func (g *Manager) RemoveClusterFromGroup(ctx context.Context, clusterID uint) error {
+	g.membershipMu.Lock()
+	defer g.membershipMu.Unlock()
+
 	clusterGroupID, err := g.getClusterGroupForCluster(clusterID)
 	if err != nil {
 		return err
@@ -294,6 +346,15 @@ func (g *Manager) GetClusterGroupFromModel(ctx context.Context, cg *ClusterGroup
 	clusterGroup.Members = make([]api.Member, 0)
 	clusterGroup.Clusters = make(map[uint]api.Cluster, 0)
 
+	if len(cg.Selector) > 0 {
+		var selector api.ClusterSelector
+		if err := json.Unmarshal(cg.Selector, &selector); err != nil {
+			g.errorHandler.Handle(emperror.WrapWith(err, "could not unmarshal cluster group selector", "clusterGroupID", cg.ID))
+		} else {
+			clusterGroup.Selector = &selector
+		}
+	}
+
 	enabledFeatures := make([]string, 0)
 	clusterGroup.EnabledFeatures = enabledFeatures
 	for _, feature := range cg.FeatureParams {
@@ -419,7 +480,8 @@ func (g *Manager) GetClusterGroupNameForCluster(clusterID uint, orgID uint) (*st
 func (g *Manager) validateBeforeClusterGroupUpdate(clusterGroup api.ClusterGroup, newClusters map[uint]api.Cluster) error {
 	g.logger.WithField("clusterGroupName", clusterGroup.Name).Debug("validate group members before update")
 
-	if len(newClusters) == 0 {
+	// groups with a selector may have no matching clusters at the moment
+	if len(newClusters) == 0 && clusterGroup.Selector == nil {
 		return &clusterGroupUpdateRejectedError{
 			err: errors.New("there must be at least 1 cluster member in a group"),
 		}
@@ -0,0 +1,163 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clustergroup
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/clustergroup/api"
+)
+
+// RegisterClusterEvents subscribes to cluster events to keep the members of selector based cluster groups up to date.
+func (g *Manager) RegisterClusterEvents(events clusterEvents) {
+	events.NotifyClusterCreated(g.clusterChanged)
+	events.NotifyClusterUpdated(g.clusterChanged)
+	events.NotifyClusterDeleted(g.clusterDeleted)
+}
+
+func (g *Manager) clusterChanged(clusterID uint) {
+	ctx := context.Background()
+
+	cluster, err := g.clusterGetter.GetClusterByIDOnly(ctx, clusterID)
+	if err != nil {
+		g.errorHandler.Handle(emperror.WrapWith(err, "could not get cluster", "clusterID", clusterID))
+		return
+	}
+
+	g.ReconcileSelectorMembership(ctx, cluster.GetOrganizationId())
+}
+
+func (g *Manager) clusterDeleted(orgID uint, clusterName string) {
+	g.ReconcileSelectorMembership(context.Background(), orgID)
+}
+
+// ReconcileSelectorMembership re-evaluates the members of the selector based cluster groups of an organization
+// and reconciles the enabled features of the groups whose members changed.
+func (g *Manager) ReconcileSelectorMembership(ctx context.Context, orgID uint) {
+	g.membershipMu.Lock()
+	defer g.membershipMu.Unlock()
+
+	cgModels, err := g.cgRepo.FindAll(orgID)
+	if err != nil {
+		g.errorHandler.Handle(err)
+		return
+	}
+
+	for _, cgModel := range cgModels {
+		clusterGroup := g.GetClusterGroupFromModel(ctx, cgModel, false)
+		if clusterGroup.Selector == nil {
+			continue
+		}
+
+		err := g.reconcileSelectorMembers(ctx, clusterGroup)
+		if err != nil {
+			g.errorHandler.Handle(emperror.WrapWith(err, "could not reconcile cluster group members", "clusterGroupID", clusterGroup.Id))
+		}
+	}
+}
+
+func (g *Manager) reconcileSelectorMembers(ctx context.Context, existingClusterGroup *api.ClusterGroup) error {
+	newMembers, err := g.selectMembers(ctx, existingClusterGroup.OrganizationID, existingClusterGroup.Id, existingClusterGroup.Selector)
+	if err != nil {
+		return err
+	}
+
+	if sameMembers(existingClusterGroup.Clusters, newMembers) {
+		return nil
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"clusterGroupName": existingClusterGroup.Name,
+		"members":          len(newMembers),
+	}).Info("cluster group members changed")
+
+	err = g.validateBeforeClusterGroupUpdate(*existingClusterGroup, newMembers)
+	if err != nil {
+		return emperror.Wrap(err, "updating cluster group is not allowed")
+	}
+
+	err = g.cgRepo.UpdateMembers(existingClusterGroup, newMembers)
+	if err != nil {
+		return err
+	}
+
+	clusterGroup, err := g.GetClusterGroupByID(ctx, existingClusterGroup.Id, existingClusterGroup.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	// call feature handlers on members update
+	return g.ReconcileFeatures(*clusterGroup, true)
+}
+
+// selectMembers returns the clusters of an organization matching a selector,
+// leaving out the clusters which are members of another group or are not in a joinable state.
+func (g *Manager) selectMembers(ctx context.Context, orgID uint, clusterGroupID uint, selector *api.ClusterSelector) (map[uint]api.Cluster, error) {
+	clusters, err := g.clusterGetter.GetClusters(ctx, orgID)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not get clusters", "orgID", orgID)
+	}
+
+	members := make(map[uint]api.Cluster, 0)
+	for _, cluster := range clusters {
+		logger := g.logger.WithFields(logrus.Fields{
+			"clusterName":    cluster.GetName(),
+			"clusterGroupID": clusterGroupID,
+		})
+
+		labels, err := g.clusterGetter.GetClusterLabels(ctx, cluster.GetID())
+		if err != nil {
+			return nil, err
+		}
+
+		if !selector.Matches(cluster, labels) {
+			continue
+		}
+
+		if ok, err := g.isClusterMemberOfAClusterGroup(cluster.GetID(), clusterGroupID); ok {
+			logger.Debug("cluster is selected, but it is a member of another group")
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		clusterStatus, err := cluster.GetStatus()
+		if err != nil || !isValidClusterStatus(clusterStatus) {
+			logger.Debug("cluster is selected, but it is not able to join")
+			continue
+		}
+
+		members[cluster.GetID()] = cluster
+	}
+
+	return members, nil
+}
+
+func sameMembers(current map[uint]api.Cluster, selected map[uint]api.Cluster) bool {
+	if len(current) != len(selected) {
+		return false
+	}
+
+	for id := range selected {
+		if _, ok := current[id]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
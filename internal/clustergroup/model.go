@@ -41,6 +41,7 @@ type ClusterGroupModel struct {
 	CreatedBy      uint
 	Name           string                     `gorm:"unique_index:idx_unique_id"`
 	OrganizationID uint                       `gorm:"unique_index:idx_unique_id"`
+	Selector       []byte                     `sql:"type:text"`
 	Members        []MemberClusterModel       `gorm:"foreignkey:ClusterGroupID"`
 	FeatureParams  []ClusterGroupFeatureModel `gorm:"foreignkey:ClusterGroupID"`
 }
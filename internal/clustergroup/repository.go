@@ -75,10 +75,11 @@ func (g *ClusterGroupRepository) FindAll(orgID uint) ([]*ClusterGroupModel, erro
 }
 
 // Create persists a cluster group
-func (g *ClusterGroupRepository) Create(name string, orgID uint, memberClusterModels []MemberClusterModel) (*uint, error) {
+func (g *ClusterGroupRepository) Create(name string, orgID uint, selector []byte, memberClusterModels []MemberClusterModel) (*uint, error) {
 	clusterGroupModel := &ClusterGroupModel{
 		Name:           name,
 		OrganizationID: orgID,
+		Selector:       selector,
 		Members:        memberClusterModels,
 	}
 
@@ -89,6 +90,15 @@ func (g *ClusterGroupRepository) Create(name string, orgID uint, memberClusterMo
 	return &clusterGroupModel.ID, nil
 }
 
+// UpdateSelector updates the member cluster selector of a cluster group
+func (g *ClusterGroupRepository) UpdateSelector(clusterGroupID uint, selector []byte) error {
+	err := g.db.Model(&ClusterGroupModel{ID: clusterGroupID}).UpdateColumn("selector", selector).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "could not update cluster group selector", "clusterGroupID", clusterGroupID)
+	}
+	return nil
+}
+
 // UpdateMembers updates cluster group members
 func (g *ClusterGroupRepository) UpdateMembers(cgroup *api.ClusterGroup, newMembers map[uint]api.Cluster) error {
 	cgModel, err := g.FindOne(ClusterGroupModel{
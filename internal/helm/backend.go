@@ -0,0 +1,124 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"time"
+)
+
+// Release statuses (the Helm 2 names are used for every backend).
+const (
+	ReleaseStatusDeployed = "DEPLOYED"
+	ReleaseStatusFailed   = "FAILED"
+)
+
+// Release is a revision of a release managed by a Helm backend.
+type Release struct {
+	Name         string
+	Namespace    string
+	Version      int
+	Status       string
+	Description  string
+	ChartName    string
+	ChartVersion string
+	AppVersion   string
+
+	FirstDeployed time.Time
+	LastDeployed  time.Time
+
+	Notes string
+
+	// Values are the chart values coalesced with the values of the release
+	Values map[string]interface{}
+
+	// Config are the values supplied for the release
+	Config map[string]interface{}
+
+	Manifest string
+}
+
+// ReleaseOptions are the options of installing or upgrading a release.
+type ReleaseOptions struct {
+	// Wait waits until the resources of the release are ready
+	Wait bool
+
+	// DryRun renders the release without applying it
+	DryRun bool
+
+	// Timeout is the time to wait for Kubernetes operations in seconds (the backend default if not set)
+	Timeout int64
+
+	// ReuseValues merges the values with the values of the latest revision (upgrade only)
+	ReuseValues bool
+
+	// ChartPackage is the chart archive to release instead of downloading the chart from a repository
+	ChartPackage []byte
+
+	// OnDemandPercentages are the on-demand percentages of the resources of the release on spot clusters (install only)
+	OnDemandPercentages map[string]int
+}
+
+// Backend manages the releases of a cluster.
+type Backend interface {
+	// ListReleases returns the latest revision of the releases of a cluster
+	// (except the deleted and superseded ones), the last deployed first.
+	ListReleases(ctx context.Context, cluster *Cluster) ([]Release, error)
+
+	// GetRelease returns the latest revision of a release, or nil if the release is not found.
+	GetRelease(ctx context.Context, cluster *Cluster, releaseName string) (*Release, error)
+
+	// GetReleaseRevision returns a revision of a release, or nil if the revision is not found.
+	GetReleaseRevision(ctx context.Context, cluster *Cluster, releaseName string, version int) (*Release, error)
+
+	// GetReleaseHistory returns the revisions of a release (latest first).
+	// It returns an empty list if the release is not found.
+	GetReleaseHistory(ctx context.Context, cluster *Cluster, releaseName string) ([]Release, error)
+
+	// InstallRelease installs a new release.
+	InstallRelease(
+		ctx context.Context,
+		cluster *Cluster,
+		namespace string,
+		chartName string,
+		chartVersion string,
+		releaseName string,
+		values []byte,
+		options ReleaseOptions,
+	) (*Release, error)
+
+	// UpgradeRelease upgrades an existing release.
+	UpgradeRelease(
+		ctx context.Context,
+		cluster *Cluster,
+		chartName string,
+		chartVersion string,
+		releaseName string,
+		values []byte,
+		options ReleaseOptions,
+	) (*Release, error)
+
+	// RollbackRelease rolls back a release to a previous revision, and returns the new revision.
+	RollbackRelease(ctx context.Context, cluster *Cluster, releaseName string, version int) (*Release, error)
+
+	// DeleteRelease deletes a release with all of its revisions.
+	DeleteRelease(ctx context.Context, cluster *Cluster, releaseName string) error
+}
+
+// Backends selects the Helm backend of a cluster.
+type Backends interface {
+	// ForCluster returns the Helm backend managing the releases of a cluster.
+	ForCluster(ctx context.Context, cluster *Cluster) (Backend, error)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ChartSchema is a values schema registered in Pipeline for a chart.
+type ChartSchema struct {
+	// Chart is the chart reference used in deployment requests (eg. stable/mysql).
+	Chart string
+
+	// Schema is the JSON schema of the values of the chart.
+	Schema []byte
+
+	UpdatedAt time.Time
+}
+
+// ChartSchemaStore stores the values schemas registered for charts by organizations.
+type ChartSchemaStore interface {
+	// List returns the schemas registered by an organization.
+	List(ctx context.Context, organizationID uint) ([]ChartSchema, error)
+
+	// Get returns the schema registered by an organization for a chart.
+	// It returns a ChartSchemaNotFoundError if no schema is registered.
+	Get(ctx context.Context, organizationID uint, chart string) (ChartSchema, error)
+
+	// Set registers the schema of a chart, replacing the previous one.
+	Set(ctx context.Context, organizationID uint, chart string, schema []byte) error
+
+	// Delete removes the schema registered for a chart.
+	Delete(ctx context.Context, organizationID uint, chart string) error
+}
+
+// ChartSchemaNotFoundError is returned when no values schema is registered for a chart.
+type ChartSchemaNotFoundError struct {
+	Chart string
+}
+
+// Error implements the error interface.
+func (e ChartSchemaNotFoundError) Error() string {
+	return fmt.Sprintf("no values schema is registered for chart %q", e.Chart)
+}
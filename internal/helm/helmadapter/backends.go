@@ -0,0 +1,56 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helmadapter
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	legacyHelm "github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/helm"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+// Backends selects the Helm backend of a cluster based on the backend recorded on the cluster (see pkg/helm.GetBackend).
+type Backends struct {
+	tiller helm.Backend
+	helm3  helm.Backend
+}
+
+// NewBackends returns a new Backends instance.
+func NewBackends() *Backends {
+	return &Backends{
+		tiller: NewTillerBackend(),
+		helm3:  NewHelm3Backend(),
+	}
+}
+
+// ForCluster returns the Helm backend managing the releases of a cluster.
+func (b *Backends) ForCluster(ctx context.Context, cluster *helm.Cluster) (helm.Backend, error) {
+	backend, err := legacyHelm.GetBackend(cluster.KubeConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to get helm backend")
+	}
+
+	switch backend {
+	case pkgHelm.Helm3Backend:
+		return b.helm3, nil
+
+	default:
+		// the tiller client connects to the embedded tiller as well
+		return b.tiller, nil
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helmadapter
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/internal/helm"
+)
+
+// TableName constants
+const (
+	chartSchemaTableName = "helm_chart_schemas"
+)
+
+type chartSchemaModel struct {
+	ID             uint `gorm:"primary_key"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	OrganizationID uint   `gorm:"unique_index:idx_helm_chart_schemas_org_chart;not null"`
+	Chart          string `gorm:"unique_index:idx_helm_chart_schemas_org_chart;not null"`
+	Schema         string `sql:"type:text"`
+}
+
+// TableName changes the default table name.
+func (chartSchemaModel) TableName() string {
+	return chartSchemaTableName
+}
+
+// GormChartSchemaStore stores chart values schemas using Gorm for data persistence.
+type GormChartSchemaStore struct {
+	db *gorm.DB
+}
+
+// NewGormChartSchemaStore returns a new GormChartSchemaStore.
+func NewGormChartSchemaStore(db *gorm.DB) GormChartSchemaStore {
+	return GormChartSchemaStore{
+		db: db,
+	}
+}
+
+// List returns the schemas registered by an organization.
+func (s GormChartSchemaStore) List(ctx context.Context, organizationID uint) ([]helm.ChartSchema, error) {
+	var models []chartSchemaModel
+
+	err := s.db.Where(chartSchemaModel{OrganizationID: organizationID}).Order("chart").Find(&models).Error
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to find chart schemas", "organizationId", organizationID)
+	}
+
+	schemas := make([]helm.ChartSchema, 0, len(models))
+	for _, model := range models {
+		schemas = append(schemas, toChartSchema(model))
+	}
+
+	return schemas, nil
+}
+
+// Get returns the schema registered by an organization for a chart.
+func (s GormChartSchemaStore) Get(ctx context.Context, organizationID uint, chart string) (helm.ChartSchema, error) {
+	var model chartSchemaModel
+
+	err := s.db.Where(chartSchemaModel{OrganizationID: organizationID, Chart: chart}).First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return helm.ChartSchema{}, helm.ChartSchemaNotFoundError{Chart: chart}
+	}
+	if err != nil {
+		return helm.ChartSchema{}, errors.WrapIfWithDetails(err, "failed to find chart schema", "chart", chart)
+	}
+
+	return toChartSchema(model), nil
+}
+
+// Set registers the schema of a chart, replacing the previous one.
+func (s GormChartSchemaStore) Set(ctx context.Context, organizationID uint, chart string, schema []byte) error {
+	model := chartSchemaModel{
+		OrganizationID: organizationID,
+		Chart:          chart,
+	}
+
+	err := s.db.Where(model).FirstOrInit(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to find chart schema", "chart", chart)
+	}
+
+	model.Schema = string(schema)
+
+	err = s.db.Save(&model).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to save chart schema", "chart", chart)
+	}
+
+	return nil
+}
+
+// Delete removes the schema registered for a chart.
+func (s GormChartSchemaStore) Delete(ctx context.Context, organizationID uint, chart string) error {
+	err := s.db.Where(chartSchemaModel{OrganizationID: organizationID, Chart: chart}).Delete(chartSchemaModel{}).Error
+	if err != nil {
+		return errors.WrapIfWithDetails(err, "failed to delete chart schema", "chart", chart)
+	}
+
+	return nil
+}
+
+func toChartSchema(model chartSchemaModel) helm.ChartSchema {
+	return helm.ChartSchema{
+		Chart:     model.Chart,
+		Schema:    []byte(model.Schema),
+		UpdatedAt: model.UpdatedAt,
+	}
+}
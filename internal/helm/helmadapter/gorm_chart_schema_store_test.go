@@ -0,0 +1,69 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helmadapter
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite" // SQLite driver used for integration test
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+	"github.com/banzaicloud/pipeline/internal/helm"
+)
+
+func testGormChartSchemaStore(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+
+	err = Migrate(db, commonadapter.NewNoopLogger())
+	require.NoError(t, err)
+
+	store := NewGormChartSchemaStore(db)
+
+	err = store.Set(ctx, 1, "stable/mysql", []byte(`{"type": "object"}`))
+	require.NoError(t, err)
+
+	err = store.Set(ctx, 1, "stable/mysql", []byte(`{"type": "object", "required": ["mysqlUser"]}`))
+	require.NoError(t, err)
+
+	err = store.Set(ctx, 2, "stable/mysql", []byte(`{}`))
+	require.NoError(t, err)
+
+	schema, err := store.Get(ctx, 1, "stable/mysql")
+	require.NoError(t, err)
+
+	assert.Equal(t, "stable/mysql", schema.Chart)
+	assert.Equal(t, `{"type": "object", "required": ["mysqlUser"]}`, string(schema.Schema))
+
+	schemas, err := store.List(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, schemas, 1)
+
+	err = store.Delete(ctx, 1, "stable/mysql")
+	require.NoError(t, err)
+
+	_, err = store.Get(ctx, 1, "stable/mysql")
+	assert.True(t, errors.As(err, &helm.ChartSchemaNotFoundError{}))
+
+	_, err = store.Get(ctx, 2, "stable/mysql")
+	require.NoError(t, err)
+}
@@ -0,0 +1,206 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helmadapter
+
+import (
+	"context"
+
+	legacyHelm "github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/helm"
+	pkgHelm "github.com/banzaicloud/pipeline/pkg/helm"
+)
+
+// Helm3Backend manages releases with the Helm 3 client.
+// Releases are stored by the client as secrets in the namespace of the release.
+type Helm3Backend struct{}
+
+// NewHelm3Backend returns a new Helm3Backend.
+func NewHelm3Backend() Helm3Backend {
+	return Helm3Backend{}
+}
+
+// ListReleases returns the latest revision of the releases of a cluster
+// (except the deleted and superseded ones), the last deployed first.
+func (Helm3Backend) ListReleases(ctx context.Context, cluster *helm.Cluster) ([]helm.Release, error) {
+	releases, err := legacyHelm.Helm3ListDeployments(cluster.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertHelm3Releases(releases), nil
+}
+
+// GetRelease returns the latest revision of a release, or nil if the release is not found.
+func (Helm3Backend) GetRelease(ctx context.Context, cluster *helm.Cluster, releaseName string) (*helm.Release, error) {
+	rls, err := legacyHelm.Helm3GetDeployment(releaseName, cluster.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if rls == nil {
+		return nil, nil
+	}
+
+	return convertHelm3Release(rls), nil
+}
+
+// GetReleaseRevision returns a revision of a release, or nil if the revision is not found.
+func (Helm3Backend) GetReleaseRevision(ctx context.Context, cluster *helm.Cluster, releaseName string, version int) (*helm.Release, error) {
+	rls, err := legacyHelm.Helm3GetDeploymentRevision(releaseName, version, cluster.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if rls == nil {
+		return nil, nil
+	}
+
+	return convertHelm3Release(rls), nil
+}
+
+// GetReleaseHistory returns the revisions of a release (latest first).
+// It returns an empty list if the release is not found.
+func (Helm3Backend) GetReleaseHistory(ctx context.Context, cluster *helm.Cluster, releaseName string) ([]helm.Release, error) {
+	history, err := legacyHelm.Helm3GetDeploymentHistory(releaseName, cluster.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertHelm3Releases(history), nil
+}
+
+// InstallRelease installs a new release.
+func (Helm3Backend) InstallRelease(
+	ctx context.Context,
+	cluster *helm.Cluster,
+	namespace string,
+	chartName string,
+	chartVersion string,
+	releaseName string,
+	values []byte,
+	options helm.ReleaseOptions,
+) (*helm.Release, error) {
+	rls, err := legacyHelm.Helm3InstallDeployment(
+		chartName,
+		chartVersion,
+		options.ChartPackage,
+		namespace,
+		releaseName,
+		values,
+		legacyHelm.Helm3Options{
+			Wait:                options.Wait,
+			DryRun:              options.DryRun,
+			Timeout:             options.Timeout,
+			OnDemandPercentages: options.OnDemandPercentages,
+		},
+		cluster.KubeConfig,
+		legacyHelm.GenerateHelmRepoEnv(cluster.OrganizationName),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertHelm3Release(rls), nil
+}
+
+// UpgradeRelease upgrades an existing release.
+func (Helm3Backend) UpgradeRelease(
+	ctx context.Context,
+	cluster *helm.Cluster,
+	chartName string,
+	chartVersion string,
+	releaseName string,
+	values []byte,
+	options helm.ReleaseOptions,
+) (*helm.Release, error) {
+	rls, err := legacyHelm.Helm3UpgradeDeployment(
+		releaseName,
+		chartName,
+		chartVersion,
+		options.ChartPackage,
+		values,
+		legacyHelm.Helm3Options{
+			Wait:        options.Wait,
+			DryRun:      options.DryRun,
+			Timeout:     options.Timeout,
+			ReuseValues: options.ReuseValues,
+		},
+		cluster.KubeConfig,
+		legacyHelm.GenerateHelmRepoEnv(cluster.OrganizationName),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertHelm3Release(rls), nil
+}
+
+// RollbackRelease rolls back a release to a previous revision, and returns the new revision.
+func (Helm3Backend) RollbackRelease(ctx context.Context, cluster *helm.Cluster, releaseName string, version int) (*helm.Release, error) {
+	rls, err := legacyHelm.Helm3RollbackDeployment(releaseName, version, cluster.KubeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertHelm3Release(rls), nil
+}
+
+// DeleteRelease deletes a release with all of its revisions.
+func (Helm3Backend) DeleteRelease(ctx context.Context, cluster *helm.Cluster, releaseName string) error {
+	return legacyHelm.Helm3DeleteDeployment(releaseName, cluster.KubeConfig)
+}
+
+func convertHelm3Releases(releases []*pkgHelm.Helm3Release) []helm.Release {
+	result := make([]helm.Release, 0, len(releases))
+
+	for _, rls := range releases {
+		result = append(result, *convertHelm3Release(rls))
+	}
+
+	return result
+}
+
+func convertHelm3Release(rls *pkgHelm.Helm3Release) *helm.Release {
+	result := &helm.Release{
+		Name:      rls.Name,
+		Namespace: rls.Namespace,
+		Version:   rls.Version,
+		Manifest:  rls.Manifest,
+		Config:    rls.Config,
+	}
+
+	if rls.Info != nil {
+		result.Status = pkgHelm.Helm2Status(rls.Info.Status)
+		result.Description = rls.Info.Description
+		result.FirstDeployed = rls.Info.FirstDeployed
+		result.LastDeployed = rls.Info.LastDeployed
+		result.Notes = rls.Info.Notes
+	}
+
+	// Helm 3 stores the values of the chart and the ones of the release separately, like Helm 2
+	values := make(map[string]interface{})
+	if rls.Chart != nil {
+		if rls.Chart.Metadata != nil {
+			result.ChartName = rls.Chart.Metadata.Name
+			result.ChartVersion = rls.Chart.Metadata.Version
+			result.AppVersion = rls.Chart.Metadata.AppVersion
+		}
+
+		values = legacyHelm.MergeValues(values, rls.Chart.Values)
+	}
+	result.Values = legacyHelm.MergeValues(values, rls.Config)
+
+	return result
+}
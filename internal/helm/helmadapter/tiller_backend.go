@@ -0,0 +1,224 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helmadapter
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"k8s.io/helm/pkg/chartutil"
+	k8sHelm "k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+
+	legacyHelm "github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/helm"
+)
+
+// TillerBackend manages releases with the Helm 2 client,
+// connected either to Tiller or to the embedded tiller of Pipeline.
+type TillerBackend struct{}
+
+// NewTillerBackend returns a new TillerBackend.
+func NewTillerBackend() TillerBackend {
+	return TillerBackend{}
+}
+
+// ListReleases returns the latest revision of the releases of a cluster
+// (except the deleted and superseded ones), the last deployed first.
+func (TillerBackend) ListReleases(ctx context.Context, cluster *helm.Cluster) ([]helm.Release, error) {
+	deployments, err := legacyHelm.ListDeployments(nil, "", cluster.KubeConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to fetch deployments")
+	}
+
+	return convertTillerReleases(deployments.GetReleases())
+}
+
+// GetRelease returns the latest revision of a release, or nil if the release is not found.
+func (TillerBackend) GetRelease(ctx context.Context, cluster *helm.Cluster, releaseName string) (*helm.Release, error) {
+	deployments, err := legacyHelm.ListDeployments(&releaseName, "", cluster.KubeConfig)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to fetch deployments", "release", releaseName)
+	}
+
+	if deployments != nil {
+		for _, rls := range deployments.Releases {
+			if rls.Name == releaseName {
+				return convertTillerRelease(rls)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// GetReleaseRevision returns a revision of a release, or nil if the revision is not found.
+func (TillerBackend) GetReleaseRevision(ctx context.Context, cluster *helm.Cluster, releaseName string, version int) (*helm.Release, error) {
+	rls, err := legacyHelm.GetReleaseRevision(releaseName, cluster.KubeConfig, int32(version))
+	if _, ok := err.(*legacyHelm.DeploymentNotFoundError); ok {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to fetch deployment revision", "release", releaseName, "version", version)
+	}
+
+	return convertTillerRelease(rls)
+}
+
+// GetReleaseHistory returns the revisions of a release (latest first).
+// It returns an empty list if the release is not found.
+func (TillerBackend) GetReleaseHistory(ctx context.Context, cluster *helm.Cluster, releaseName string) ([]helm.Release, error) {
+	history, err := legacyHelm.GetReleaseHistory(releaseName, cluster.KubeConfig)
+	if _, ok := err.(*legacyHelm.DeploymentNotFoundError); ok {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to fetch deployment history", "release", releaseName)
+	}
+
+	return convertTillerReleases(history)
+}
+
+// InstallRelease installs a new release.
+func (TillerBackend) InstallRelease(
+	ctx context.Context,
+	cluster *helm.Cluster,
+	namespace string,
+	chartName string,
+	chartVersion string,
+	releaseName string,
+	values []byte,
+	options helm.ReleaseOptions,
+) (*helm.Release, error) {
+	installOptions := []k8sHelm.InstallOption{
+		k8sHelm.InstallWait(options.Wait),
+		k8sHelm.ValueOverrides(values),
+	}
+	if options.Timeout > 0 {
+		installOptions = append(installOptions, k8sHelm.InstallTimeout(options.Timeout))
+	}
+
+	res, err := legacyHelm.CreateDeployment(
+		chartName,
+		chartVersion,
+		options.ChartPackage,
+		namespace,
+		releaseName,
+		options.DryRun,
+		options.OnDemandPercentages,
+		cluster.KubeConfig,
+		legacyHelm.GenerateHelmRepoEnv(cluster.OrganizationName), // TODO: refactor!!!!!!
+		installOptions...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTillerRelease(res.GetRelease())
+}
+
+// UpgradeRelease upgrades an existing release.
+func (TillerBackend) UpgradeRelease(
+	ctx context.Context,
+	cluster *helm.Cluster,
+	chartName string,
+	chartVersion string,
+	releaseName string,
+	values []byte,
+	options helm.ReleaseOptions,
+) (*helm.Release, error) {
+	upgradeOptions := []k8sHelm.UpdateOption{
+		k8sHelm.UpgradeWait(options.Wait),
+		k8sHelm.UpgradeDryRun(options.DryRun),
+	}
+	if options.Timeout > 0 {
+		upgradeOptions = append(upgradeOptions, k8sHelm.UpgradeTimeout(options.Timeout))
+	}
+
+	res, err := legacyHelm.UpgradeDeployment(
+		releaseName,
+		chartName,
+		chartVersion,
+		options.ChartPackage,
+		values,
+		options.ReuseValues,
+		cluster.KubeConfig,
+		legacyHelm.GenerateHelmRepoEnv(cluster.OrganizationName), // TODO: refactor!!!!!!
+		upgradeOptions...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTillerRelease(res.GetRelease())
+}
+
+// RollbackRelease rolls back a release to a previous revision, and returns the new revision.
+func (TillerBackend) RollbackRelease(ctx context.Context, cluster *helm.Cluster, releaseName string, version int) (*helm.Release, error) {
+	rls, err := legacyHelm.RollbackDeployment(releaseName, cluster.KubeConfig, int32(version))
+	if err != nil {
+		return nil, err
+	}
+
+	return convertTillerRelease(rls)
+}
+
+// DeleteRelease deletes a release with all of its revisions.
+func (TillerBackend) DeleteRelease(ctx context.Context, cluster *helm.Cluster, releaseName string) error {
+	return legacyHelm.DeleteDeployment(releaseName, cluster.KubeConfig)
+}
+
+func convertTillerReleases(releases []*release.Release) ([]helm.Release, error) {
+	result := make([]helm.Release, 0, len(releases))
+
+	for _, rls := range releases {
+		converted, err := convertTillerRelease(rls)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *converted)
+	}
+
+	return result, nil
+}
+
+func convertTillerRelease(rls *release.Release) (*helm.Release, error) {
+	values, err := chartutil.CoalesceValues(rls.GetChart(), rls.GetConfig())
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to coalesce release values", "release", rls.GetName())
+	}
+
+	config, err := chartutil.ReadValues([]byte(rls.GetConfig().GetRaw()))
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to parse release values", "release", rls.GetName())
+	}
+
+	return &helm.Release{
+		Name:          rls.GetName(),
+		Namespace:     rls.GetNamespace(),
+		Version:       int(rls.GetVersion()),
+		Status:        rls.GetInfo().GetStatus().GetCode().String(),
+		Description:   rls.GetInfo().GetDescription(),
+		ChartName:     rls.GetChart().GetMetadata().GetName(),
+		ChartVersion:  rls.GetChart().GetMetadata().GetVersion(),
+		AppVersion:    rls.GetChart().GetMetadata().GetAppVersion(),
+		FirstDeployed: time.Unix(rls.GetInfo().GetFirstDeployed().GetSeconds(), 0),
+		LastDeployed:  time.Unix(rls.GetInfo().GetLastDeployed().GetSeconds(), 0),
+		Notes:         rls.GetInfo().GetStatus().GetNotes(),
+		Values:        values.AsMap(),
+		Config:        config.AsMap(),
+		Manifest:      rls.GetManifest(),
+	}, nil
+}
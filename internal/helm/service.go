@@ -16,10 +16,9 @@ package helm
 
 import (
 	"context"
+	"encoding/base64"
 
 	"emperror.dev/errors"
-	k8sHelm "k8s.io/helm/pkg/helm"
-	"k8s.io/helm/pkg/proto/hapi/release"
 
 	"github.com/banzaicloud/pipeline/helm"
 	"github.com/banzaicloud/pipeline/internal/common"
@@ -39,16 +38,19 @@ type Cluster struct {
 }
 
 // HelmService provides an interface for using Helm on a specific cluster.
+// Releases are managed by the Helm backend of the cluster (see pkg/helm.Backend).
 type HelmService struct {
 	clusters ClusterService
+	backends Backends
 
 	logger common.Logger
 }
 
 // NewHelmService returns a new HelmService.
-func NewHelmService(clusters ClusterService, logger common.Logger) *HelmService {
+func NewHelmService(clusters ClusterService, backends Backends, logger common.Logger) *HelmService {
 	return &HelmService{
 		clusters: clusters,
+		backends: backends,
 
 		logger: logger.WithFields(map[string]interface{}{"component": "helm"}),
 	}
@@ -70,24 +72,24 @@ func (s *HelmService) InstallDeployment(
 	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"chart": chartName, "release": releaseName})
 	logger.Info("installing deployment")
 
-	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	cluster, backend, err := s.getBackend(ctx, clusterID)
 	if err != nil {
 		return err
 	}
 
-	foundRelease, err := findRelease(releaseName, cluster.KubeConfig)
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
 	if err != nil {
 		return errors.WithDetails(err, "chart", chartName)
 	}
 
 	if foundRelease != nil {
-		switch foundRelease.GetInfo().GetStatus().GetCode() {
-		case release.Status_DEPLOYED:
+		switch foundRelease.Status {
+		case ReleaseStatusDeployed:
 			logger.Info("deployment is already installed")
 
 			return nil
-		case release.Status_FAILED:
-			err := helm.DeleteDeployment(releaseName, cluster.KubeConfig)
+		case ReleaseStatusFailed:
+			err := backend.DeleteRelease(ctx, cluster, releaseName)
 			if err != nil {
 				return errors.WrapIfWithDetails(
 					err, "failed to delete deployment",
@@ -98,22 +100,7 @@ func (s *HelmService) InstallDeployment(
 		}
 	}
 
-	options := []k8sHelm.InstallOption{
-		k8sHelm.InstallWait(wait),
-		k8sHelm.ValueOverrides(values),
-	}
-	_, err = helm.CreateDeployment(
-		chartName,
-		chartVersion,
-		nil,
-		namespace,
-		releaseName,
-		false,
-		nil,
-		cluster.KubeConfig,
-		helm.GenerateHelmRepoEnv(cluster.OrganizationName), // TODO: refactor!!!!!!
-		options...,
-	)
+	_, err = backend.InstallRelease(ctx, cluster, namespace, chartName, chartVersion, releaseName, values, ReleaseOptions{Wait: wait})
 	if err != nil {
 		return errors.WrapIfWithDetails(
 			err, "failed to install deployment",
@@ -141,36 +128,24 @@ func (s *HelmService) UpdateDeployment(
 	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"chart": chartName, "release": releaseName})
 	logger.Info("updating deployment")
 
-	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	cluster, backend, err := s.getBackend(ctx, clusterID)
 	if err != nil {
 		return err
 	}
 
-	foundRelease, err := findRelease(releaseName, cluster.KubeConfig)
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
 	if err != nil {
 		return errors.WithDetails(err, "chart", chartName)
 	}
 
-	if foundRelease != nil {
-		switch foundRelease.GetInfo().GetStatus().GetCode() {
-		case release.Status_DEPLOYED:
-			_, err = helm.UpgradeDeployment(
-				releaseName,
-				chartName,
-				chartVersion,
-				nil,
-				values,
-				false,
-				cluster.KubeConfig,
-				helm.GenerateHelmRepoEnv(cluster.OrganizationName), // TODO: refactor!!!!!!
+	if foundRelease != nil && foundRelease.Status == ReleaseStatusDeployed {
+		_, err = backend.UpgradeRelease(ctx, cluster, chartName, chartVersion, releaseName, values, ReleaseOptions{})
+		if err != nil {
+			return errors.WrapIfWithDetails(
+				err, "failed to update deployment",
+				"chart", chartName,
+				"release", releaseName,
 			)
-			if err != nil {
-				return errors.WrapIfWithDetails(
-					err, "failed to update deployment",
-					"chart", chartName,
-					"release", releaseName,
-				)
-			}
 		}
 	}
 
@@ -191,29 +166,20 @@ func (s *HelmService) ApplyDeployment(
 	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"chart": chartName, "release": releaseName})
 	logger.Info("applying deployment")
 
-	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	cluster, backend, err := s.getBackend(ctx, clusterID)
 	if err != nil {
 		return err
 	}
 
-	foundRelease, err := findRelease(releaseName, cluster.KubeConfig)
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
 	if err != nil {
 		return errors.WithDetails(err, "chart", chartName)
 	}
 
 	if foundRelease != nil {
-		switch foundRelease.GetInfo().GetStatus().GetCode() {
-		case release.Status_DEPLOYED:
-			_, err := helm.UpgradeDeployment(
-				releaseName,
-				chartName,
-				chartVersion,
-				nil,
-				values,
-				false,
-				cluster.KubeConfig,
-				helm.GenerateHelmRepoEnv(cluster.OrganizationName), // TODO: refactor!!!!!!
-			)
+		switch foundRelease.Status {
+		case ReleaseStatusDeployed:
+			_, err := backend.UpgradeRelease(ctx, cluster, chartName, chartVersion, releaseName, values, ReleaseOptions{})
 			if err != nil {
 				return errors.WrapIfWithDetails(
 					err, "failed to upgrade deployment",
@@ -222,8 +188,12 @@ func (s *HelmService) ApplyDeployment(
 				)
 			}
 
-		case release.Status_FAILED:
-			if err := helm.DeleteDeployment(releaseName, cluster.KubeConfig); err != nil {
+			logger.Info("deployment applied successfully")
+
+			return nil
+
+		case ReleaseStatusFailed:
+			if err := backend.DeleteRelease(ctx, cluster, releaseName); err != nil {
 				return errors.WrapIfWithDetails(
 					err, "failed to delete deployment",
 					"chart", chartName,
@@ -231,59 +201,95 @@ func (s *HelmService) ApplyDeployment(
 				)
 			}
 
-			options := []k8sHelm.InstallOption{
-				// k8sHelm.InstallWait(wait),
-				k8sHelm.ValueOverrides(values),
-			}
-			_, err = helm.CreateDeployment(
-				chartName,
-				chartVersion,
-				nil,
-				namespace,
-				releaseName,
-				false,
-				nil,
-				cluster.KubeConfig,
-				helm.GenerateHelmRepoEnv(cluster.OrganizationName), // TODO: refactor!!!!!!
-				options...,
-			)
-			if err != nil {
-				return errors.WrapIfWithDetails(
-					err, "failed to install deployment",
-					"chart", chartName,
-					"release", releaseName,
-				)
-			}
-		}
-	} else {
-		options := []k8sHelm.InstallOption{
-			// k8sHelm.InstallWait(wait),
-			k8sHelm.ValueOverrides(values),
+		default:
+			logger.Info("deployment applied successfully")
+
+			return nil
 		}
-		_, err = helm.CreateDeployment(
-			chartName,
-			chartVersion,
-			nil,
-			namespace,
-			releaseName,
-			false,
-			nil,
-			cluster.KubeConfig,
-			helm.GenerateHelmRepoEnv(cluster.OrganizationName), // TODO: refactor!!!!!!
-			options...,
+	}
+
+	_, err = backend.InstallRelease(ctx, cluster, namespace, chartName, chartVersion, releaseName, values, ReleaseOptions{})
+	if err != nil {
+		return errors.WrapIfWithDetails(
+			err, "failed to install deployment",
+			"chart", chartName,
+			"release", releaseName,
 		)
+	}
+
+	logger.Info("deployment applied successfully")
+
+	return nil
+}
+
+// PreviewDeployment is the preview mode of ApplyDeployment:
+// it renders the deployment without applying it and returns the resources that would be added, changed or removed.
+func (s *HelmService) PreviewDeployment(
+	ctx context.Context,
+	clusterID uint,
+	namespace string,
+	chartName string,
+	releaseName string,
+	values []byte,
+	chartVersion string,
+) (*pkgHelm.DeploymentPreviewResponse, error) {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"chart": chartName, "release": releaseName})
+	logger.Info("previewing deployment")
+
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
+	if err != nil {
+		return nil, errors.WithDetails(err, "chart", chartName)
+	}
+
+	if foundRelease != nil && foundRelease.Status == ReleaseStatusDeployed {
+		rendered, err := backend.UpgradeRelease(ctx, cluster, chartName, chartVersion, releaseName, values, ReleaseOptions{DryRun: true})
 		if err != nil {
-			return errors.WrapIfWithDetails(
-				err, "failed to install deployment",
+			return nil, errors.WrapIfWithDetails(
+				err, "failed to preview deployment upgrade",
 				"chart", chartName,
 				"release", releaseName,
 			)
 		}
+
+		return helm.DiffDeploymentManifests(releaseName, foundRelease.Manifest, rendered.Manifest, previewSensitiveValues(foundRelease, rendered))
 	}
 
-	logger.Info("deployment applied successfully")
+	// A failed release would be deleted and installed again by ApplyDeployment,
+	// so its resources are compared with a fresh install.
+	var liveManifest string
+	if foundRelease != nil {
+		liveManifest = foundRelease.Manifest
+	}
 
-	return nil
+	rendered, err := backend.InstallRelease(ctx, cluster, namespace, chartName, chartVersion, releaseName, values, ReleaseOptions{DryRun: true})
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(
+			err, "failed to preview deployment install",
+			"chart", chartName,
+			"release", releaseName,
+		)
+	}
+
+	return helm.DiffDeploymentManifests(releaseName, liveManifest, rendered.Manifest, previewSensitiveValues(foundRelease, rendered))
+}
+
+// previewSensitiveValues returns the resolved secret values of the live and the rendered release,
+// so that they are redacted from the preview.
+func previewSensitiveValues(releases ...*Release) []string {
+	var values []string
+
+	for _, release := range releases {
+		if release != nil {
+			values = append(values, helm.ResolvedSecretValues(release.Values)...)
+		}
+	}
+
+	return values
 }
 
 // DeleteDeployment deletes a deployment from a specific cluster.
@@ -291,18 +297,18 @@ func (s *HelmService) DeleteDeployment(ctx context.Context, clusterID uint, rele
 	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"release": releaseName})
 	logger.Info("deleting deployment")
 
-	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	cluster, backend, err := s.getBackend(ctx, clusterID)
 	if err != nil {
 		return err
 	}
 
-	foundRelease, err := findRelease(releaseName, cluster.KubeConfig)
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
 	if err != nil {
 		return err
 	}
 
 	if foundRelease != nil {
-		err = helm.DeleteDeployment(releaseName, cluster.KubeConfig)
+		err = backend.DeleteRelease(ctx, cluster, releaseName)
 		if err != nil {
 			return errors.WrapIfWithDetails(
 				err, "failed to delete deployment",
@@ -317,34 +323,393 @@ func (s *HelmService) DeleteDeployment(ctx context.Context, clusterID uint, rele
 
 }
 
+// GetDeployment returns the details of the latest revision of a deployment.
 func (s *HelmService) GetDeployment(ctx context.Context, clusterID uint, releaseName string) (*pkgHelm.GetDeploymentResponse, error) {
 	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"release": releaseName})
 	logger.Info("getting deployment")
 
-	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
 	if err != nil {
 		return nil, err
 	}
 
-	return helm.GetDeployment(releaseName, cluster.KubeConfig)
+	if foundRelease == nil {
+		return nil, deploymentNotFoundError(releaseName)
+	}
+
+	return getDeploymentResponse(foundRelease), nil
 }
 
-func findRelease(releaseName string, k8sConfig []byte) (*release.Release, error) {
-	deployments, err := helm.ListDeployments(&releaseName, "", k8sConfig)
+// GetTaggedDeployment returns the details of the latest revision of a deployment tagged with a tag
+// (in the banzaicloud.tags value).
+func (s *HelmService) GetTaggedDeployment(ctx context.Context, clusterID uint, releaseName string, tag string) (*pkgHelm.GetDeploymentResponse, error) {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"release": releaseName, "tag": tag})
+	logger.Info("getting tagged deployment")
+
+	cluster, backend, err := s.getBackend(ctx, clusterID)
 	if err != nil {
-		return nil, errors.WrapIfWithDetails(err, "failed to fetch deployments", "release", releaseName)
+		return nil, err
 	}
 
-	var foundRelease *release.Release
+	history, err := backend.GetReleaseHistory(ctx, cluster, releaseName)
+	if err != nil {
+		return nil, err
+	}
 
-	if deployments != nil {
-		for _, rel := range deployments.Releases {
-			if rel.Name == releaseName {
-				foundRelease = rel
-				break
-			}
+	if len(history) == 0 {
+		return nil, deploymentNotFoundError(releaseName)
+	}
+
+	for i := range history {
+		if helm.ValuesHaveTag(history[i].Values, tag) {
+			return getDeploymentResponse(&history[i]), nil
 		}
 	}
 
-	return foundRelease, nil
+	return nil, &helm.DeploymentNotFoundError{HelmError: errors.New("tag not found")}
+}
+
+func getDeploymentResponse(release *Release) *pkgHelm.GetDeploymentResponse {
+	return &pkgHelm.GetDeploymentResponse{
+		ReleaseName:  release.Name,
+		Namespace:    release.Namespace,
+		Version:      int32(release.Version),
+		Description:  release.Description,
+		Status:       release.Status,
+		Notes:        base64.StdEncoding.EncodeToString([]byte(release.Notes)),
+		CreatedAt:    release.FirstDeployed,
+		Updated:      release.LastDeployed,
+		Chart:        helm.GetVersionedChartName(release.ChartName, release.ChartVersion),
+		ChartName:    release.ChartName,
+		ChartVersion: release.ChartVersion,
+
+		// the resolved secret values are replaced with the references they were resolved from
+		Values: helm.MaskSecretReferences(release.Values),
+	}
+}
+
+// ListDeployments returns the latest revision of the deployments of a cluster, the last deployed first.
+// If a tag is given, only the deployments tagged with it (in the banzaicloud.tags value) are returned.
+func (s *HelmService) ListDeployments(ctx context.Context, clusterID uint, tag string) ([]Release, error) {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"tag": tag})
+	logger.Info("listing deployments")
+
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := backend.ListReleases(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	if tag == "" {
+		return releases, nil
+	}
+
+	tagged := make([]Release, 0, len(releases))
+	for _, release := range releases {
+		if helm.ValuesHaveTag(release.Values, tag) {
+			tagged = append(tagged, release)
+		}
+	}
+
+	return tagged, nil
+}
+
+// GetDeploymentStatus returns the status of the latest revision of a deployment.
+func (s *HelmService) GetDeploymentStatus(ctx context.Context, clusterID uint, releaseName string) (string, error) {
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return "", err
+	}
+
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
+	if err != nil {
+		return "", err
+	}
+
+	if foundRelease == nil {
+		return "", deploymentNotFoundError(releaseName)
+	}
+
+	return foundRelease.Status, nil
+}
+
+// GetDeploymentResources returns the resources of the latest revision of a deployment
+// (all of them if no resource types are given).
+func (s *HelmService) GetDeploymentResources(ctx context.Context, clusterID uint, releaseName string, resourceTypes []string) ([]pkgHelm.DeploymentResource, error) {
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	if foundRelease == nil {
+		return nil, deploymentNotFoundError(releaseName)
+	}
+
+	return helm.ParseReleaseManifest(foundRelease.Manifest, resourceTypes)
+}
+
+// CreateDeployment installs a new deployment on a specific cluster and returns the installed release.
+// Unlike InstallDeployment, it does not check whether the deployment is installed already.
+func (s *HelmService) CreateDeployment(
+	ctx context.Context,
+	clusterID uint,
+	namespace string,
+	chartName string,
+	chartVersion string,
+	releaseName string,
+	values []byte,
+	options ReleaseOptions,
+) (*Release, error) {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"chart": chartName, "release": releaseName})
+	logger.Info("creating deployment")
+
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := backend.InstallRelease(ctx, cluster, namespace, chartName, chartVersion, releaseName, values, options)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(
+			err, "failed to create deployment",
+			"chart", chartName,
+			"release", releaseName,
+		)
+	}
+
+	logger.Info("deployment created successfully")
+
+	return release, nil
+}
+
+// UpgradeDeployment upgrades an existing deployment on a specific cluster and returns the new revision.
+func (s *HelmService) UpgradeDeployment(
+	ctx context.Context,
+	clusterID uint,
+	chartName string,
+	chartVersion string,
+	releaseName string,
+	values []byte,
+	options ReleaseOptions,
+) (*Release, error) {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"chart": chartName, "release": releaseName})
+	logger.Info("upgrading deployment")
+
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
+	if err != nil {
+		return nil, errors.WithDetails(err, "chart", chartName)
+	}
+
+	if foundRelease == nil {
+		return nil, deploymentNotFoundError(releaseName)
+	}
+
+	release, err := backend.UpgradeRelease(ctx, cluster, chartName, chartVersion, releaseName, values, options)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(
+			err, "failed to upgrade deployment",
+			"chart", chartName,
+			"release", releaseName,
+		)
+	}
+
+	logger.Info("deployment upgraded successfully")
+
+	return release, nil
+}
+
+// PreviewDeploymentUpgrade is the preview mode of UpgradeDeployment:
+// it renders the upgrade without applying it and returns the resources that would be added, changed or removed.
+func (s *HelmService) PreviewDeploymentUpgrade(
+	ctx context.Context,
+	clusterID uint,
+	chartName string,
+	chartVersion string,
+	releaseName string,
+	values []byte,
+	options ReleaseOptions,
+) (*pkgHelm.DeploymentPreviewResponse, error) {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"chart": chartName, "release": releaseName})
+	logger.Info("previewing deployment upgrade")
+
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
+	if err != nil {
+		return nil, errors.WithDetails(err, "chart", chartName)
+	}
+
+	if foundRelease == nil {
+		return nil, deploymentNotFoundError(releaseName)
+	}
+
+	options.DryRun = true
+
+	rendered, err := backend.UpgradeRelease(ctx, cluster, chartName, chartVersion, releaseName, values, options)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(
+			err, "failed to preview deployment upgrade",
+			"chart", chartName,
+			"release", releaseName,
+		)
+	}
+
+	return helm.DiffDeploymentManifests(releaseName, foundRelease.Manifest, rendered.Manifest, previewSensitiveValues(foundRelease, rendered))
+}
+
+// GetDeploymentHistory returns the revisions of a deployment (latest first).
+func (s *HelmService) GetDeploymentHistory(ctx context.Context, clusterID uint, releaseName string) ([]pkgHelm.DeploymentRevision, error) {
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := backend.GetReleaseHistory(ctx, cluster, releaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		return nil, deploymentNotFoundError(releaseName)
+	}
+
+	revisions := make([]pkgHelm.DeploymentRevision, 0, len(history))
+	for _, release := range history {
+		revisions = append(revisions, pkgHelm.DeploymentRevision{
+			Version:      int32(release.Version),
+			Status:       release.Status,
+			Chart:        helm.GetVersionedChartName(release.ChartName, release.ChartVersion),
+			ChartName:    release.ChartName,
+			ChartVersion: release.ChartVersion,
+			AppVersion:   release.AppVersion,
+			Description:  release.Description,
+			UpdatedAt:    release.LastDeployed,
+		})
+	}
+
+	return revisions, nil
+}
+
+// GetDeploymentDiff returns the manifest and values differences between two revisions of a deployment.
+// The versions default to the previous (fromVersion) and the latest (toVersion) revision if they are 0.
+func (s *HelmService) GetDeploymentDiff(ctx context.Context, clusterID uint, releaseName string, fromVersion int, toVersion int) (*pkgHelm.GetDeploymentDiffResponse, error) {
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if toVersion == 0 {
+		foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
+		if err != nil {
+			return nil, err
+		}
+
+		if foundRelease == nil {
+			return nil, deploymentNotFoundError(releaseName)
+		}
+
+		toVersion = foundRelease.Version
+	}
+
+	if fromVersion == 0 {
+		fromVersion = toVersion - 1
+	}
+
+	if fromVersion < 1 {
+		return nil, &helm.DeploymentNotFoundError{HelmError: errors.New("deployment has no previous version")}
+	}
+
+	revisions := make([]helm.DeploymentRevisionContent, 0, 2)
+	for _, version := range []int{fromVersion, toVersion} {
+		release, err := backend.GetReleaseRevision(ctx, cluster, releaseName, version)
+		if err != nil {
+			return nil, err
+		}
+
+		if release == nil {
+			return nil, &helm.DeploymentNotFoundError{HelmError: errors.Errorf("release: %q version %d not found", releaseName, version)}
+		}
+
+		revisions = append(revisions, helm.DeploymentRevisionContent{
+			Version:  int32(release.Version),
+			Manifest: release.Manifest,
+			Values:   release.Config,
+		})
+	}
+
+	return helm.DiffDeploymentRevisions(releaseName, revisions[0], revisions[1])
+}
+
+// RollbackDeployment rolls back a deployment to a previous revision and returns the new revision.
+func (s *HelmService) RollbackDeployment(ctx context.Context, clusterID uint, releaseName string, version int) (*Release, error) {
+	logger := s.logger.WithContext(ctx).WithFields(map[string]interface{}{"release": releaseName, "version": version})
+	logger.Info("rolling back deployment")
+
+	cluster, backend, err := s.getBackend(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	foundRelease, err := backend.GetRelease(ctx, cluster, releaseName)
+	if err != nil {
+		return nil, err
+	}
+
+	if foundRelease == nil {
+		return nil, deploymentNotFoundError(releaseName)
+	}
+
+	release, err := backend.RollbackRelease(ctx, cluster, releaseName, version)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(
+			err, "failed to roll back deployment",
+			"release", releaseName,
+			"version", version,
+		)
+	}
+
+	logger.Info("deployment rolled back successfully")
+
+	return release, nil
+}
+
+func deploymentNotFoundError(releaseName string) error {
+	return &helm.DeploymentNotFoundError{HelmError: errors.Errorf("release: %q not found", releaseName)}
+}
+
+// getBackend returns a cluster and the Helm backend managing its releases.
+func (s *HelmService) getBackend(ctx context.Context, clusterID uint) (*Cluster, Backend, error) {
+	cluster, err := s.clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backend, err := s.backends.ForCluster(ctx, cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cluster, backend, nil
 }
@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package helm
+package helm_test
 
 import (
 	"context"
@@ -23,19 +23,22 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 
 	"github.com/banzaicloud/pipeline/internal/common/commonadapter"
+	"github.com/banzaicloud/pipeline/internal/helm"
+	"github.com/banzaicloud/pipeline/internal/helm/helmadapter"
 )
 
 const organizationName = "banzaicloud"
 
 type clusterServiceStub struct {
-	cluster Cluster
+	cluster helm.Cluster
 }
 
-func (s *clusterServiceStub) GetCluster(ctx context.Context, clusterID uint) (*Cluster, error) {
+func (s *clusterServiceStub) GetCluster(ctx context.Context, clusterID uint) (*helm.Cluster, error) {
 	return &s.cluster, nil
 }
 
@@ -53,12 +56,12 @@ func TestIntegration(t *testing.T) {
 	require.NoError(t, err)
 
 	clusterService := &clusterServiceStub{
-		cluster: Cluster{
+		cluster: helm.Cluster{
 			OrganizationName: organizationName,
 			KubeConfig:       kubeConfigBytes,
 		},
 	}
-	service := NewHelmService(clusterService, commonadapter.NewNoopLogger())
+	service := helm.NewHelmService(clusterService, helmadapter.NewBackends(), commonadapter.NewNoopLogger())
 
 	err = service.InstallDeployment(
 		context.Background(),
@@ -79,6 +82,21 @@ func TestIntegration(t *testing.T) {
 	valuesBytes, err := yaml.Marshal(values)
 	require.NoError(t, err)
 
+	preview, err := service.PreviewDeployment(
+		context.Background(),
+		1,
+		"default",
+		"banzaicloud-stable/banzaicloud-docs",
+		"helm-service-test",
+		valuesBytes,
+		"0.1.1",
+	)
+	require.NoError(t, err)
+	require.Len(t, preview.Changed, 1)
+	assert.Equal(t, "Deployment", preview.Changed[0].Kind)
+	assert.Empty(t, preview.Added)
+	assert.Empty(t, preview.Removed)
+
 	err = service.UpdateDeployment(
 		context.Background(),
 		1,
@@ -100,3 +118,102 @@ func TestIntegration(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+type backendStub struct {
+	helm.Backend
+
+	releases []helm.Release
+}
+
+func (b *backendStub) ListReleases(ctx context.Context, cluster *helm.Cluster) ([]helm.Release, error) {
+	return b.releases, nil
+}
+
+func (b *backendStub) GetRelease(ctx context.Context, cluster *helm.Cluster, releaseName string) (*helm.Release, error) {
+	for i := range b.releases {
+		if b.releases[i].Name == releaseName {
+			return &b.releases[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (b *backendStub) GetReleaseRevision(ctx context.Context, cluster *helm.Cluster, releaseName string, version int) (*helm.Release, error) {
+	for i := range b.releases {
+		if b.releases[i].Name == releaseName && b.releases[i].Version == version {
+			return &b.releases[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+type backendsStub struct {
+	backend helm.Backend
+}
+
+func (b *backendsStub) ForCluster(ctx context.Context, cluster *helm.Cluster) (helm.Backend, error) {
+	return b.backend, nil
+}
+
+func TestHelmService_ListDeployments(t *testing.T) {
+	backend := &backendStub{
+		releases: []helm.Release{
+			{
+				Name: "tagged",
+				Values: map[string]interface{}{
+					"banzaicloud": map[string]interface{}{"tags": []interface{}{"my-tag"}},
+				},
+			},
+			{Name: "untagged"},
+		},
+	}
+	service := helm.NewHelmService(&clusterServiceStub{}, &backendsStub{backend: backend}, commonadapter.NewNoopLogger())
+
+	releases, err := service.ListDeployments(context.Background(), 1, "")
+	require.NoError(t, err)
+	assert.Len(t, releases, 2)
+
+	releases, err = service.ListDeployments(context.Background(), 1, "my-tag")
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "tagged", releases[0].Name)
+}
+
+func TestHelmService_GetDeploymentDiff(t *testing.T) {
+	backend := &backendStub{
+		releases: []helm.Release{
+			{
+				Name:     "my-release",
+				Version:  2,
+				Config:   map[string]interface{}{"replicaCount": 2},
+				Manifest: "---\n# Source: chart/templates/cm.yaml\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: my-config\ndata:\n  key: new\n",
+			},
+			{
+				Name:     "my-release",
+				Version:  1,
+				Config:   map[string]interface{}{"replicaCount": 1},
+				Manifest: "---\n# Source: chart/templates/cm.yaml\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: my-config\ndata:\n  key: old\n",
+			},
+			{Name: "first-release", Version: 1},
+		},
+	}
+	service := helm.NewHelmService(&clusterServiceStub{}, &backendsStub{backend: backend}, commonadapter.NewNoopLogger())
+
+	diff, err := service.GetDeploymentDiff(context.Background(), 1, "my-release", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), diff.FromVersion)
+	assert.Equal(t, int32(2), diff.ToVersion)
+	assert.Contains(t, diff.Values, "replicaCount")
+	assert.Contains(t, diff.Manifest, "my-config")
+
+	_, err = service.GetDeploymentDiff(context.Background(), 1, "first-release", 0, 0)
+	require.Error(t, err)
+
+	_, err = service.GetDeploymentDiff(context.Background(), 1, "my-release", 1, 3)
+	require.Error(t, err)
+
+	_, err = service.GetDeploymentDiff(context.Background(), 1, "missing-release", 0, 0)
+	require.Error(t, err)
+}
@@ -0,0 +1,146 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package valuetemplate evaluates templates in the string values of Helm release values.
+package valuetemplate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+)
+
+// actionPattern matches the actions of a template.
+// nolint: gochecknoglobals
+var actionPattern = regexp.MustCompile(`(?s)\{\{(.*?)\}\}`)
+
+// rootFieldPattern matches the references to the top level fields of the template data in an action
+// (eg. .Cluster in {{ .Cluster.Name }} or $.Release in {{ $.Release.Name }}).
+// nolint: gochecknoglobals
+var rootFieldPattern = regexp.MustCompile(`(?:^|[^\w.)\]"'$])\$?\.([A-Za-z_]\w*)`)
+
+// RenderKnown evaluates the templates in the string values of a values map referring to the given template data.
+//
+// Every string containing a template is evaluated separately, other values are returned as is.
+// Strings are only evaluated if they refer to at least one top level field of the data and nothing else,
+// other templates (eg. {{ .Chart.Name }} or {{ include "name" . }} evaluated by the chart with tpl) are left untouched.
+// Errors refer to the path of the invalid value (eg. ingress.hosts[0]).
+func RenderKnown(values map[string]interface{}, data map[string]interface{}) (map[string]interface{}, error) {
+	rendered, err := renderValue(data, values, "", func(value string) bool {
+		roots := referencedRoots(value)
+		if len(roots) == 0 {
+			return false
+		}
+
+		for _, root := range roots {
+			if _, ok := data[root]; !ok {
+				return false
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rendered == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	return rendered.(map[string]interface{}), nil
+}
+
+// referencedRoots returns the top level fields of the template data referenced by a template.
+func referencedRoots(value string) []string {
+	var roots []string
+
+	for _, action := range actionPattern.FindAllStringSubmatch(value, -1) {
+		for _, field := range rootFieldPattern.FindAllStringSubmatch(action[1], -1) {
+			roots = append(roots, field[1])
+		}
+	}
+
+	return roots
+}
+
+func renderValue(data interface{}, value interface{}, path string, shouldRender func(value string) bool) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return nil, nil
+		}
+
+		rendered := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			r, err := renderValue(data, item, path+"."+key, shouldRender)
+			if err != nil {
+				return nil, err
+			}
+
+			rendered[key] = r
+		}
+
+		return rendered, nil
+
+	case []interface{}:
+		rendered := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := renderValue(data, item, fmt.Sprintf("%s[%d]", path, i), shouldRender)
+			if err != nil {
+				return nil, err
+			}
+
+			rendered[i] = r
+		}
+
+		return rendered, nil
+
+	case string:
+		if !strings.Contains(v, "{{") || !shouldRender(v) {
+			return v, nil
+		}
+
+		tmpl, err := template.New("values").Funcs(funcMap()).Option("missingkey=error").Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", strings.TrimPrefix(path, "."), err.Error())
+		}
+
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("%s: %s", strings.TrimPrefix(path, "."), err.Error())
+		}
+
+		return buf.String(), nil
+
+	default:
+		return v, nil
+	}
+}
+
+// funcMap returns the template functions available in values.
+//
+// Functions reading the environment of Pipeline are removed (the same way Helm does),
+// otherwise users could read configuration and credentials through release values.
+func funcMap() template.FuncMap {
+	f := sprig.TxtFuncMap()
+	delete(f, "env")
+	delete(f, "expandenv")
+
+	return f
+}
@@ -0,0 +1,135 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package valuetemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderKnown(t *testing.T) {
+	data := map[string]interface{}{
+		"Release": map[string]interface{}{
+			"Name": "frontend",
+		},
+	}
+
+	values := map[string]interface{}{
+		"replicaCount": 2,
+		"ingress": map[string]interface{}{
+			"hosts": []interface{}{"{{ .Release.Name }}.example.com"},
+		},
+		"title": "{{ .Release.Name | upper }}",
+	}
+
+	rendered, err := RenderKnown(values, data)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"replicaCount": 2,
+			"ingress": map[string]interface{}{
+				"hosts": []interface{}{"frontend.example.com"},
+			},
+			"title": "FRONTEND",
+		},
+		rendered,
+	)
+}
+
+func TestRenderKnown_Path(t *testing.T) {
+	values := map[string]interface{}{
+		"ingress": map[string]interface{}{
+			"hosts": []interface{}{"{{ .Release.Domain }}"},
+		},
+	}
+
+	_, err := RenderKnown(values, map[string]interface{}{"Release": map[string]interface{}{}})
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "ingress.hosts[0]: ")
+}
+
+func TestRenderKnown_Environment(t *testing.T) {
+	data := map[string]interface{}{"Release": map[string]interface{}{"Name": "frontend"}}
+
+	for _, value := range []string{`{{ .Release.Name }}: {{ env "HOME" }}`, `{{ .Release.Name }}: {{ expandenv "$HOME" }}`} {
+		_, err := RenderKnown(map[string]interface{}{"home": value}, data)
+
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRenderKnown_Empty(t *testing.T) {
+	rendered, err := RenderKnown(nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{}, rendered)
+}
+
+func TestRenderKnown_Foreign(t *testing.T) {
+	data := map[string]interface{}{
+		"Cluster": map[string]interface{}{
+			"Name":   "eks",
+			"Labels": map[string]string{},
+		},
+	}
+
+	values := map[string]interface{}{
+		"name":     "{{ .Cluster.Name }}",
+		"root":     "{{ $.Cluster.Name | upper }}",
+		"chart":    "{{ .Values.image }}",
+		"include":  `{{ include "app.fullname" . }}`,
+		"mixed":    "{{ .Cluster.Name }}-{{ .Release.Name }}",
+		"function": `{{ tpl .Values.config $ }}`,
+		"env":      `{{ env "HOME" }}`,
+		"variable": "{{ $name := .Cluster.Name }}{{ $name }}",
+	}
+
+	rendered, err := RenderKnown(values, data)
+	require.NoError(t, err)
+
+	assert.Equal(
+		t,
+		map[string]interface{}{
+			"name":     "eks",
+			"root":     "EKS",
+			"chart":    "{{ .Values.image }}",
+			"include":  `{{ include "app.fullname" . }}`,
+			"mixed":    "{{ .Cluster.Name }}-{{ .Release.Name }}",
+			"function": `{{ tpl .Values.config $ }}`,
+			"env":      `{{ env "HOME" }}`,
+			"variable": "eks",
+		},
+		rendered,
+	)
+}
+
+func TestRenderKnown_Error(t *testing.T) {
+	data := map[string]interface{}{
+		"Cluster": map[string]interface{}{
+			"Labels": map[string]string{},
+		},
+	}
+
+	for _, value := range []string{"{{ .Cluster.Labels.env }}", `{{ .Cluster.Labels.env }}{{ env "HOME" }}`} {
+		_, err := RenderKnown(map[string]interface{}{"env": value}, data)
+
+		assert.Error(t, err, value)
+	}
+}
@@ -76,6 +76,7 @@ type MeshReconciler struct {
 	Remotes       []cluster.CommonCluster
 
 	clusterGetter api.ClusterGetter
+	clients       clientFactory
 	logger        logrus.FieldLogger
 	errorHandler  emperror.Handler
 }
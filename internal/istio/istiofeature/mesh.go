@@ -34,6 +34,7 @@ func NewMeshReconciler(config Config, clusterGetter api.ClusterGetter, logger lo
 		Configuration: config,
 
 		clusterGetter: clusterGetter,
+		clients:       kubeConfigClientFactory{},
 		logger:        logger,
 		errorHandler:  errorHandler,
 	}
@@ -77,11 +78,37 @@ func (m *MeshReconciler) getRemoteClusters() []cluster.CommonCluster {
 	return remotes
 }
 
-func (m *MeshReconciler) getMasterK8sClient() (*kubernetes.Clientset, error) {
+func (m *MeshReconciler) getMasterK8sClient() (kubernetes.Interface, error) {
 	return m.getK8sClient(m.Master)
 }
 
-func (m *MeshReconciler) getK8sClient(c cluster.CommonCluster) (*kubernetes.Clientset, error) {
+func (m *MeshReconciler) getK8sClient(c cluster.CommonCluster) (kubernetes.Interface, error) {
+	return m.clients.NewK8sClient(c)
+}
+
+func (m *MeshReconciler) getMasterIstioOperatorK8sClient() (istiooperatorclientset.Interface, error) {
+	return m.getIstioOperatorK8sClient(m.Master)
+}
+
+func (m *MeshReconciler) getIstioOperatorK8sClient(c cluster.CommonCluster) (istiooperatorclientset.Interface, error) {
+	return m.clients.NewIstioOperatorClient(c)
+}
+
+func (m *MeshReconciler) getApiExtensionK8sClient(c cluster.CommonCluster) (apiextensionsclient.Interface, error) {
+	return m.clients.NewAPIExtensionClient(c)
+}
+
+// clientFactory creates the Kubernetes clients of the clusters in the mesh
+type clientFactory interface {
+	NewK8sClient(c cluster.CommonCluster) (kubernetes.Interface, error)
+	NewIstioOperatorClient(c cluster.CommonCluster) (istiooperatorclientset.Interface, error)
+	NewAPIExtensionClient(c cluster.CommonCluster) (apiextensionsclient.Interface, error)
+}
+
+// kubeConfigClientFactory creates clients from the kubeconfig of the clusters
+type kubeConfigClientFactory struct{}
+
+func (kubeConfigClientFactory) NewK8sClient(c cluster.CommonCluster) (kubernetes.Interface, error) {
 	kubeConfig, err := c.GetK8sConfig()
 	if err != nil {
 		return nil, emperror.Wrap(err, "could not get k8s config")
@@ -95,12 +122,8 @@ func (m *MeshReconciler) getK8sClient(c cluster.CommonCluster) (*kubernetes.Clie
 	return client, nil
 }
 
-func (m *MeshReconciler) getMasterIstioOperatorK8sClient() (*istiooperatorclientset.Clientset, error) {
-	return m.getIstioOperatorK8sClient(m.Master)
-}
-
-func (m *MeshReconciler) getIstioOperatorK8sClient(c cluster.CommonCluster) (*istiooperatorclientset.Clientset, error) {
-	kubeConfig, err := m.Master.GetK8sConfig()
+func (kubeConfigClientFactory) NewIstioOperatorClient(c cluster.CommonCluster) (istiooperatorclientset.Interface, error) {
+	kubeConfig, err := c.GetK8sConfig()
 	if err != nil {
 		return nil, emperror.Wrap(err, "could not get k8s config")
 	}
@@ -118,8 +141,8 @@ func (m *MeshReconciler) getIstioOperatorK8sClient(c cluster.CommonCluster) (*is
 	return client, nil
 }
 
-func (m *MeshReconciler) getApiExtensionK8sClient(c cluster.CommonCluster) (*apiextensionsclient.Clientset, error) {
-	kubeConfig, err := m.Master.GetK8sConfig()
+func (kubeConfigClientFactory) NewAPIExtensionClient(c cluster.CommonCluster) (apiextensionsclient.Interface, error) {
+	kubeConfig, err := c.GetK8sConfig()
 	if err != nil {
 		return nil, emperror.Wrap(err, "could not get k8s config")
 	}
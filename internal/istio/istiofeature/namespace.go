@@ -26,7 +26,7 @@ import (
 )
 
 // waitForNamespaceBeDeleted wait for a k8s namespace to be deleted
-func (m *MeshReconciler) waitForNamespaceBeDeleted(client *kubernetes.Clientset, namespace string) error {
+func (m *MeshReconciler) waitForNamespaceBeDeleted(client kubernetes.Interface, namespace string) error {
 	var backoffConfig = backoff.ConstantBackoffConfig{
 		Delay:      time.Duration(backoffDelaySeconds) * time.Second,
 		MaxRetries: backoffMaxretries,
@@ -77,7 +77,7 @@ func (m *MeshReconciler) ReconcileBackyards(desiredState DesiredState) error {
 }
 
 // waitForSidecarInjectorPod waits for Sidecar Injector Pods to be running
-func (m *MeshReconciler) waitForSidecarInjectorPod(client *kubernetes.Clientset) error {
+func (m *MeshReconciler) waitForSidecarInjectorPod(client kubernetes.Interface) error {
 	m.logger.Debug("waiting for sidecar injector pod")
 
 	var backoffConfig = backoff.ConstantBackoffConfig{
@@ -107,7 +107,7 @@ func (m *MeshReconciler) waitForSidecarInjectorPod(client *kubernetes.Clientset)
 }
 
 // waitForMetricCRD waits for Metric CRD to be present in the cluster
-func (m *MeshReconciler) waitForMetricCRD(name string, client *apiextensionsclient.Clientset) error {
+func (m *MeshReconciler) waitForMetricCRD(name string, client apiextensionsclient.Interface) error {
 	m.logger.WithField("name", name).Debug("waiting for metric CRD")
 
 	var backoffConfig = backoff.ConstantBackoffConfig{
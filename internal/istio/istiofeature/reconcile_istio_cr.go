@@ -81,7 +81,7 @@ func (m *MeshReconciler) ReconcileIstio(desiredState DesiredState) error {
 }
 
 // waitForIstioCRToBeDeleted wait for Istio CR to be deleted
-func (m *MeshReconciler) waitForIstioCRToBeDeleted(client *istiooperatorclientset.Clientset) error {
+func (m *MeshReconciler) waitForIstioCRToBeDeleted(client istiooperatorclientset.Interface) error {
 	m.logger.Debug("waiting for Istio CR to be deleted")
 
 	var backoffConfig = backoff.ConstantBackoffConfig{
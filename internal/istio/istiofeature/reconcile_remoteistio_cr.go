@@ -56,22 +56,32 @@ func (m *MeshReconciler) ReconcileRemoteIstio(desiredState DesiredState, c clust
 			return emperror.Wrap(err, "could not create Remote Istio CR")
 		}
 	} else {
-		err := client.IstioV1beta1().RemoteIstios(istioOperatorNamespace).Delete(c.GetName(), &metav1.DeleteOptions{})
-		if err != nil && !k8serrors.IsNotFound(err) {
-			return emperror.Wrap(err, "could not remove Remote Istio CR")
-		}
-
-		err = m.waitForRemoteIstioCRToBeDeleted(c.GetName(), client)
+		err := m.deleteRemoteIstioCR(c.GetName(), client)
 		if err != nil {
-			return emperror.Wrap(err, "timeout during waiting for Remote Istio CR to be deleted")
+			return err
 		}
 	}
 
 	return nil
 }
 
+// deleteRemoteIstioCR deletes the Remote Istio CR by name and waits for its removal
+func (m *MeshReconciler) deleteRemoteIstioCR(name string, client istiooperatorclientset.Interface) error {
+	err := client.IstioV1beta1().RemoteIstios(istioOperatorNamespace).Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return emperror.Wrap(err, "could not remove Remote Istio CR")
+	}
+
+	err = m.waitForRemoteIstioCRToBeDeleted(name, client)
+	if err != nil {
+		return emperror.Wrap(err, "timeout during waiting for Remote Istio CR to be deleted")
+	}
+
+	return nil
+}
+
 // waitForRemoteIstioCRToBeDeleted wait for Remote Istio CR to be deleted
-func (m *MeshReconciler) waitForRemoteIstioCRToBeDeleted(name string, client *istiooperatorclientset.Clientset) error {
+func (m *MeshReconciler) waitForRemoteIstioCRToBeDeleted(name string, client istiooperatorclientset.Interface) error {
 	m.logger.WithField("name", name).Debug("waiting for Remote Istio CR to be deleted")
 
 	var backoffConfig = backoff.ConstantBackoffConfig{
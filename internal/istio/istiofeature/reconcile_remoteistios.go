@@ -15,18 +15,23 @@
 package istiofeature
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"strconv"
+	"time"
 
 	"emperror.dev/emperror"
-	"github.com/pkg/errors"
+	"emperror.dev/errors"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/banzaicloud/pipeline/cluster"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/pkg/backoff"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 	"github.com/banzaicloud/pipeline/pkg/k8sutil"
 )
@@ -35,20 +40,22 @@ func (m *MeshReconciler) ReconcileRemoteIstios(desiredState DesiredState) error
 	m.logger.Debug("reconciling Remote Istios")
 	defer m.logger.Debug("Remote Istios reconciled")
 
+	var errs []error
+
 	remoteClusterIDs := make(map[uint]bool)
 	if len(m.Remotes) > 0 {
 		for _, remoteCluster := range m.Remotes {
 			remoteClusterIDs[remoteCluster.GetID()] = true
 			err := m.reconcileRemoteIstio(desiredState, remoteCluster)
 			if err != nil {
-				return err
+				errs = append(errs, emperror.WrapWith(err, "could not reconcile remote cluster", "clusterID", remoteCluster.GetID()))
 			}
 		}
 	}
 
-	clustersByRemoteIstios, err := m.getRemoteClustersByExistingRemoteIstioCRs()
+	clustersByRemoteIstios, orphanedRemoteIstios, err := m.getRemoteClustersByExistingRemoteIstioCRs()
 	if err != nil {
-		return err
+		return errors.Combine(append(errs, err)...)
 	}
 
 	for _, remoteCluster := range clustersByRemoteIstios {
@@ -58,11 +65,19 @@ func (m *MeshReconciler) ReconcileRemoteIstios(desiredState DesiredState) error
 
 		err := m.reconcileRemoteIstio(DesiredStateAbsent, remoteCluster)
 		if err != nil {
-			return err
+			errs = append(errs, emperror.WrapWith(err, "could not remove remote cluster from mesh", "clusterID", remoteCluster.GetID()))
 		}
 	}
 
-	return nil
+	// the clusters of these Remote Istio CRs no longer exist, so only the resources on the master can be cleaned up
+	for _, name := range orphanedRemoteIstios {
+		err := m.removeOrphanedRemoteIstio(name)
+		if err != nil {
+			errs = append(errs, emperror.WrapWith(err, "could not remove orphaned Remote Istio", "name", name))
+		}
+	}
+
+	return errors.Combine(errs...)
 }
 
 func (m *MeshReconciler) reconcileRemoteIstio(desiredState DesiredState, c cluster.CommonCluster) error {
@@ -106,20 +121,15 @@ func (m *MeshReconciler) reconcileRemoteIstio(desiredState DesiredState, c clust
 func (m *MeshReconciler) reconcileRemoteIstioSecret(desiredState DesiredState, c cluster.CommonCluster) error {
 	secretName := c.GetName()
 
+	if desiredState == DesiredStateAbsent {
+		return m.deleteRemoteIstioSecret(secretName)
+	}
+
 	client, err := m.getK8sClient(m.Master)
 	if err != nil {
 		return err
 	}
 
-	if desiredState == DesiredStateAbsent {
-		err := client.CoreV1().Secrets(istioOperatorNamespace).Delete(secretName, &metav1.DeleteOptions{})
-		if err != nil && !k8serrors.IsNotFound(err) {
-			return err
-		}
-
-		return nil
-	}
-
 	kubeconfig, err := m.generateKubeconfig(c)
 	if err != nil {
 		return err
@@ -135,11 +145,22 @@ func (m *MeshReconciler) reconcileRemoteIstioSecret(desiredState DesiredState, c
 		},
 	}
 
-	_, err = client.CoreV1().Secrets(istioOperatorNamespace).Get(secretName, metav1.GetOptions{})
+	existing, err := client.CoreV1().Secrets(istioOperatorNamespace).Get(secretName, metav1.GetOptions{})
 	if err != nil && !k8serrors.IsNotFound(err) {
 		return err
 	}
 	if err == nil {
+		// the token or the API server endpoint of the remote cluster might have changed since the secret was created
+		if bytes.Equal(existing.Data[secretName], kubeconfig) {
+			return nil
+		}
+
+		existing.Data = resource.Data
+		_, err = client.CoreV1().Secrets(istioOperatorNamespace).Update(existing)
+		if err != nil {
+			return emperror.Wrap(err, "could not update remote kubeconfig secret")
+		}
+
 		return nil
 	}
 	_, err = client.CoreV1().Secrets(istioOperatorNamespace).Create(resource)
@@ -150,34 +171,54 @@ func (m *MeshReconciler) reconcileRemoteIstioSecret(desiredState DesiredState, c
 	return nil
 }
 
-func (m *MeshReconciler) generateKubeconfig(c cluster.CommonCluster) ([]byte, error) {
-	kubeConfig, err := c.GetK8sConfig()
+func (m *MeshReconciler) deleteRemoteIstioSecret(secretName string) error {
+	client, err := m.getK8sClient(m.Master)
 	if err != nil {
-		return nil, emperror.Wrap(err, "could not get k8s config")
+		return err
 	}
 
-	config, err := k8sclient.NewClientConfig(kubeConfig)
+	err = client.CoreV1().Secrets(istioOperatorNamespace).Delete(secretName, &metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// removeOrphanedRemoteIstio removes the Remote Istio CR and the kubeconfig secret of a cluster that no longer exists
+func (m *MeshReconciler) removeOrphanedRemoteIstio(name string) error {
+	m.logger.WithField("name", name).Debug("removing orphaned Remote Istio")
+
+	client, err := m.getMasterIstioOperatorK8sClient()
 	if err != nil {
-		return nil, emperror.Wrap(err, "could not create rest config from kubeconfig")
+		return err
 	}
 
-	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	err = m.deleteRemoteIstioCR(name, client)
 	if err != nil {
-		return nil, emperror.Wrap(err, "cloud not create client from kubeconfig")
+		return err
 	}
 
-	sa, err := client.CoreV1().ServiceAccounts(istioOperatorNamespace).Get("istio-operator", metav1.GetOptions{})
+	return m.deleteRemoteIstioSecret(name)
+}
+
+func (m *MeshReconciler) generateKubeconfig(c cluster.CommonCluster) ([]byte, error) {
+	kubeConfig, err := c.GetK8sConfig()
 	if err != nil {
-		return nil, err
+		return nil, emperror.Wrap(err, "could not get k8s config")
 	}
 
-	if len(sa.Secrets) == 0 {
-		return nil, nil
+	config, err := k8sclient.NewClientConfig(kubeConfig)
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not create rest config from kubeconfig")
 	}
 
-	secretName := sa.Secrets[0].Name
+	client, err := m.getK8sClient(c)
+	if err != nil {
+		return nil, err
+	}
 
-	secret, err := client.CoreV1().Secrets(istioOperatorNamespace).Get(secretName, metav1.GetOptions{})
+	secret, err := m.waitForServiceAccountTokenSecret(client)
 	if err != nil {
 		return nil, err
 	}
@@ -207,6 +248,43 @@ users:
 	return []byte(yml), nil
 }
 
+// waitForServiceAccountTokenSecret waits for the token controller to populate the secret of the istio-operator service account
+func (m *MeshReconciler) waitForServiceAccountTokenSecret(client kubernetes.Interface) (*corev1.Secret, error) {
+	var backoffConfig = backoff.ConstantBackoffConfig{
+		Delay:      time.Duration(backoffDelaySeconds) * time.Second,
+		MaxRetries: backoffMaxretries,
+	}
+	var backoffPolicy = backoff.NewConstantBackoffPolicy(backoffConfig)
+
+	var secret *corev1.Secret
+	err := backoff.Retry(func() error {
+		sa, err := client.CoreV1().ServiceAccounts(istioOperatorNamespace).Get("istio-operator", metav1.GetOptions{})
+		if err != nil {
+			return emperror.Wrap(err, "could not get service account")
+		}
+
+		if len(sa.Secrets) == 0 {
+			return errors.New("service account token secret is not created yet")
+		}
+
+		secret, err = client.CoreV1().Secrets(istioOperatorNamespace).Get(sa.Secrets[0].Name, metav1.GetOptions{})
+		if err != nil {
+			return emperror.WrapWith(err, "could not get service account token secret", "name", sa.Secrets[0].Name)
+		}
+
+		if len(secret.Data["token"]) == 0 {
+			return emperror.With(errors.New("service account token secret is not populated yet"), "name", secret.Name)
+		}
+
+		return nil
+	}, backoffPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
 func (m *MeshReconciler) reconcileRemoteIstioNamespace(desiredState DesiredState, c cluster.CommonCluster) error {
 	client, err := m.getK8sClient(c)
 	if err != nil {
@@ -278,8 +356,7 @@ func (m *MeshReconciler) reconcileRemoteIstioClusterRole(desiredState DesiredSta
 
 	resource := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "istio-operator",
-			Namespace: istioOperatorNamespace,
+			Name: "istio-operator",
 		},
 		Rules: []rbacv1.PolicyRule{
 			{
@@ -324,8 +401,7 @@ func (m *MeshReconciler) reconcileRemoteIstioClusterRoleBinding(desiredState Des
 
 	resource := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "istio-operator",
-			Namespace: istioOperatorNamespace,
+			Name: "istio-operator",
 		},
 		RoleRef: rbacv1.RoleRef{
 			Kind:     "ClusterRole",
@@ -363,17 +439,20 @@ func (m *MeshReconciler) reconcileRemoteIstioClusterRoleBinding(desiredState Des
 	return nil
 }
 
-func (m *MeshReconciler) getRemoteClustersByExistingRemoteIstioCRs() (map[uint]cluster.CommonCluster, error) {
+// getRemoteClustersByExistingRemoteIstioCRs returns the clusters of the existing Remote Istio CRs, and the names of
+// the CRs whose cluster no longer exists
+func (m *MeshReconciler) getRemoteClustersByExistingRemoteIstioCRs() (map[uint]cluster.CommonCluster, []string, error) {
 	clusters := make(map[uint]cluster.CommonCluster, 0)
+	orphans := make([]string, 0)
 
 	client, err := m.getMasterIstioOperatorK8sClient()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	remoteistios, err := client.IstioV1beta1().RemoteIstios(istioOperatorNamespace).List(metav1.ListOptions{})
 	if err != nil && !k8serrors.IsNotFound(err) {
-		return nil, emperror.Wrap(err, "could not get remote istios")
+		return nil, nil, emperror.Wrap(err, "could not get remote istios")
 	}
 
 	for _, remoteistio := range remoteistios.Items {
@@ -393,6 +472,10 @@ func (m *MeshReconciler) getRemoteClustersByExistingRemoteIstioCRs() (map[uint]c
 		}
 
 		c, err := m.clusterGetter.GetClusterByID(context.Background(), m.Master.GetOrganizationId(), uint(clusterID))
+		if intCluster.IsClusterNotFoundError(err) {
+			orphans = append(orphans, remoteistio.Name)
+			continue
+		}
 		if err != nil {
 			m.errorHandler.Handle(errors.WithStack(err))
 			continue
@@ -401,5 +484,5 @@ func (m *MeshReconciler) getRemoteClustersByExistingRemoteIstioCRs() (map[uint]c
 		clusters[c.GetID()] = c.(cluster.CommonCluster)
 	}
 
-	return clusters, nil
+	return clusters, orphans, nil
 }
@@ -0,0 +1,324 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package istiofeature
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/banzaicloud/istio-operator/pkg/apis/istio/v1beta1"
+	istiooperatorclientset "github.com/banzaicloud/istio-operator/pkg/client/clientset/versioned"
+	istiov1beta1 "github.com/banzaicloud/istio-operator/pkg/client/clientset/versioned/typed/istio/v1beta1"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	"github.com/banzaicloud/pipeline/internal/clustergroup/api"
+)
+
+const testOrganizationID = 1
+
+// testCluster is a cluster of the mesh, only the methods used by the mesh reconciler are implemented
+type testCluster struct {
+	cluster.CommonCluster
+
+	id   uint
+	name string
+	host string
+}
+
+func (c *testCluster) GetID() uint             { return c.id }
+func (c *testCluster) GetName() string         { return c.name }
+func (c *testCluster) GetOrganizationId() uint { return testOrganizationID }
+func (c *testCluster) GetCloud() string        { return "amazon" }
+func (c *testCluster) GetDistribution() string { return "pke" }
+func (c *testCluster) GetK8sConfig() ([]byte, error) {
+	return []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: %s
+  name: %s
+contexts:
+- context:
+    cluster: %s
+    user: %s
+  name: %s
+current-context: %s
+users:
+- name: %s
+  user:
+    token: admin
+`, c.host, c.name, c.name, c.name, c.name, c.name, c.name)), nil
+}
+
+type testClusterGetter struct {
+	api.ClusterGetter
+
+	clusters map[uint]api.Cluster
+}
+
+func (g *testClusterGetter) GetClusterByID(ctx context.Context, organizationID uint, clusterID uint) (api.Cluster, error) {
+	c, ok := g.clusters[clusterID]
+	if !ok {
+		return nil, errors.New("cluster not found")
+	}
+
+	return c, nil
+}
+
+// testIstioOperatorClient keeps the Remote Istio CRs of a cluster in memory
+// (the generated fake clientset of the istio operator does not build with the client-go version used by Pipeline)
+type testIstioOperatorClient struct {
+	istiooperatorclientset.Interface
+	istiov1beta1.IstioV1beta1Interface
+
+	remoteIstios map[string]v1beta1.RemoteIstio
+}
+
+func newTestIstioOperatorClient() *testIstioOperatorClient {
+	return &testIstioOperatorClient{remoteIstios: make(map[string]v1beta1.RemoteIstio)}
+}
+
+func (c *testIstioOperatorClient) IstioV1beta1() istiov1beta1.IstioV1beta1Interface {
+	return c
+}
+
+func (c *testIstioOperatorClient) RemoteIstios(namespace string) istiov1beta1.RemoteIstioInterface {
+	return &testRemoteIstios{client: c}
+}
+
+type testRemoteIstios struct {
+	istiov1beta1.RemoteIstioInterface
+
+	client *testIstioOperatorClient
+}
+
+// nolint: gochecknoglobals
+var remoteIstioResource = schema.GroupResource{Group: "istio.banzaicloud.io", Resource: "remoteistios"}
+
+func (r *testRemoteIstios) Create(remoteIstio *v1beta1.RemoteIstio) (*v1beta1.RemoteIstio, error) {
+	if _, ok := r.client.remoteIstios[remoteIstio.Name]; ok {
+		return nil, k8serrors.NewAlreadyExists(remoteIstioResource, remoteIstio.Name)
+	}
+
+	r.client.remoteIstios[remoteIstio.Name] = *remoteIstio
+
+	return remoteIstio, nil
+}
+
+func (r *testRemoteIstios) Get(name string, options metav1.GetOptions) (*v1beta1.RemoteIstio, error) {
+	remoteIstio, ok := r.client.remoteIstios[name]
+	if !ok {
+		return nil, k8serrors.NewNotFound(remoteIstioResource, name)
+	}
+
+	return &remoteIstio, nil
+}
+
+func (r *testRemoteIstios) Delete(name string, options *metav1.DeleteOptions) error {
+	if _, ok := r.client.remoteIstios[name]; !ok {
+		return k8serrors.NewNotFound(remoteIstioResource, name)
+	}
+
+	delete(r.client.remoteIstios, name)
+
+	return nil
+}
+
+func (r *testRemoteIstios) List(opts metav1.ListOptions) (*v1beta1.RemoteIstioList, error) {
+	list := &v1beta1.RemoteIstioList{}
+	for _, remoteIstio := range r.client.remoteIstios {
+		list.Items = append(list.Items, remoteIstio)
+	}
+
+	return list, nil
+}
+
+// testClientFactory serves fake clients by cluster ID
+type testClientFactory struct {
+	k8sClients           map[uint]*fake.Clientset
+	istioOperatorClients map[uint]*testIstioOperatorClient
+}
+
+func (f *testClientFactory) NewK8sClient(c cluster.CommonCluster) (kubernetes.Interface, error) {
+	return f.k8sClients[c.GetID()], nil
+}
+
+func (f *testClientFactory) NewIstioOperatorClient(c cluster.CommonCluster) (istiooperatorclientset.Interface, error) {
+	return f.istioOperatorClients[c.GetID()], nil
+}
+
+func (f *testClientFactory) NewAPIExtensionClient(c cluster.CommonCluster) (apiextensionsclient.Interface, error) {
+	return apiextensionsfake.NewSimpleClientset(), nil
+}
+
+type meshTest struct {
+	master  *testCluster
+	remotes []*testCluster
+	clients *testClientFactory
+	getter  *testClusterGetter
+}
+
+func newMeshTest() *meshTest {
+	t := &meshTest{
+		master: &testCluster{id: 1, name: "master", host: "https://master.example.com"},
+		remotes: []*testCluster{
+			{id: 2, name: "remote-1", host: "https://remote-1.example.com"},
+			{id: 3, name: "remote-2", host: "https://remote-2.example.com"},
+		},
+		clients: &testClientFactory{
+			k8sClients:           make(map[uint]*fake.Clientset),
+			istioOperatorClients: make(map[uint]*testIstioOperatorClient),
+		},
+		getter: &testClusterGetter{clusters: make(map[uint]api.Cluster)},
+	}
+
+	for _, c := range append([]*testCluster{t.master}, t.remotes...) {
+		t.getter.clusters[c.id] = c
+		t.clients.istioOperatorClients[c.id] = newTestIstioOperatorClient()
+		t.clients.k8sClients[c.id] = fake.NewSimpleClientset(
+			// the service account token is populated by the token controller of the cluster
+			&corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: "istio-operator", Namespace: istioOperatorNamespace},
+				Secrets:    []corev1.ObjectReference{{Name: "istio-operator-token"}},
+			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "istio-operator-token", Namespace: istioOperatorNamespace},
+				Data:       map[string][]byte{"token": []byte(c.name + "-token"), "ca.crt": []byte(c.name + "-ca")},
+			},
+		)
+	}
+
+	return t
+}
+
+// reconciler returns a mesh reconciler for a cluster group with the master and the given remotes
+func (t *meshTest) reconciler(remotes ...*testCluster) *MeshReconciler {
+	clusters := map[uint]api.Cluster{t.master.id: t.master}
+	for _, c := range remotes {
+		clusters[c.id] = c
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	config := Config{MasterClusterID: t.master.id}
+	config.clusterGroup = api.ClusterGroup{OrganizationID: testOrganizationID, Clusters: clusters}
+
+	m := NewMeshReconciler(config, t.getter, logger, emperror.NewNoopHandler())
+	m.clients = t.clients
+
+	return m
+}
+
+func (t *meshTest) masterSecret(name string) (*corev1.Secret, error) {
+	return t.clients.k8sClients[t.master.id].CoreV1().Secrets(istioOperatorNamespace).Get(name, metav1.GetOptions{})
+}
+
+func (t *meshTest) remoteIstioNames(test *testing.T) []string {
+	list, err := t.clients.istioOperatorClients[t.master.id].IstioV1beta1().RemoteIstios(istioOperatorNamespace).List(metav1.ListOptions{})
+	require.NoError(test, err)
+
+	names := make([]string, 0, len(list.Items))
+	for _, item := range list.Items {
+		names = append(names, item.Name)
+	}
+
+	return names
+}
+
+func TestReconcileRemoteIstios_MemberJoins(t *testing.T) {
+	mesh := newMeshTest()
+
+	require.NoError(t, mesh.reconciler(mesh.remotes[0]).ReconcileRemoteIstios(DesiredStatePresent))
+	assert.ElementsMatch(t, []string{"remote-1"}, mesh.remoteIstioNames(t))
+
+	// remote-2 joins the group
+	require.NoError(t, mesh.reconciler(mesh.remotes...).ReconcileRemoteIstios(DesiredStatePresent))
+	assert.ElementsMatch(t, []string{"remote-1", "remote-2"}, mesh.remoteIstioNames(t))
+
+	remoteIstio, err := mesh.clients.istioOperatorClients[mesh.master.id].IstioV1beta1().RemoteIstios(istioOperatorNamespace).Get("remote-2", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "3", remoteIstio.Labels[clusterIDLabel])
+
+	secret, err := mesh.masterSecret("remote-2")
+	require.NoError(t, err)
+	kubeconfig := string(secret.Data["remote-2"])
+	assert.Contains(t, kubeconfig, "server: https://remote-2.example.com")
+	assert.Contains(t, kubeconfig, "token: remote-2-token")
+
+	// the RBAC resources of the operator are created on the remote cluster
+	_, err = mesh.clients.k8sClients[3].RbacV1().ClusterRoleBindings().Get("istio-operator", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestReconcileRemoteIstios_MemberLeaves(t *testing.T) {
+	mesh := newMeshTest()
+
+	require.NoError(t, mesh.reconciler(mesh.remotes...).ReconcileRemoteIstios(DesiredStatePresent))
+
+	// remote-2 leaves the group
+	require.NoError(t, mesh.reconciler(mesh.remotes[0]).ReconcileRemoteIstios(DesiredStatePresent))
+	assert.ElementsMatch(t, []string{"remote-1"}, mesh.remoteIstioNames(t))
+
+	_, err := mesh.masterSecret("remote-2")
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	_, err = mesh.masterSecret("remote-1")
+	assert.NoError(t, err)
+
+	_, err = mesh.clients.k8sClients[3].RbacV1().ClusterRoleBindings().Get("istio-operator", metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err))
+
+	_, err = mesh.clients.k8sClients[2].RbacV1().ClusterRoleBindings().Get("istio-operator", metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestReconcileRemoteIstios_RemoteSecretUpdated(t *testing.T) {
+	mesh := newMeshTest()
+
+	require.NoError(t, mesh.reconciler(mesh.remotes[0]).ReconcileRemoteIstios(DesiredStatePresent))
+
+	// the service account token of the remote cluster is rotated
+	tokens := mesh.clients.k8sClients[2].CoreV1().Secrets(istioOperatorNamespace)
+	token, err := tokens.Get("istio-operator-token", metav1.GetOptions{})
+	require.NoError(t, err)
+	token.Data["token"] = []byte("rotated-token")
+	_, err = tokens.Update(token)
+	require.NoError(t, err)
+
+	require.NoError(t, mesh.reconciler(mesh.remotes[0]).ReconcileRemoteIstios(DesiredStatePresent))
+
+	secret, err := mesh.masterSecret("remote-1")
+	require.NoError(t, err)
+	kubeconfig := string(secret.Data["remote-1"])
+	assert.Contains(t, kubeconfig, "token: rotated-token")
+	assert.False(t, strings.Contains(kubeconfig, "remote-1-token"))
+}
@@ -0,0 +1,54 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginauth
+
+import (
+	"context"
+	"net/http"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+)
+
+// ClusterEnforcer checks if the current user has access to a cluster.
+type ClusterEnforcer interface {
+	EnforceCluster(ctx context.Context, user *auth.User, clusterID uint) (bool, error)
+}
+
+// NewClusterMiddleware returns a new gin middleware that checks access to the cluster a request was resolved to.
+// It has to be installed after the middleware resolving the cluster (by ID or name) of the request.
+func NewClusterMiddleware(e ClusterEnforcer, errorHandler emperror.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clusterID, ok := ctxutil.ClusterID(c.Request.Context())
+		if !ok {
+			c.AbortWithStatus(http.StatusForbidden)
+
+			return
+		}
+
+		granted, err := e.EnforceCluster(c.Request.Context(), auth.GetCurrentUser(c.Request), clusterID)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to check cluster permissions for request")
+			errorHandler.Handle(err)
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+		} else if !granted {
+			c.AbortWithStatus(http.StatusForbidden)
+		}
+	}
+}
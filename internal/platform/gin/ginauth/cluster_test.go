@@ -0,0 +1,97 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+	qorauth "github.com/qor/auth"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+)
+
+type clusterEnforcerStub struct {
+	granted map[uint]bool
+}
+
+func (e clusterEnforcerStub) EnforceCluster(_ context.Context, _ *auth.User, clusterID uint) (bool, error) {
+	return e.granted[clusterID], nil
+}
+
+func TestClusterMiddleware(t *testing.T) {
+	e := clusterEnforcerStub{
+		granted: map[uint]bool{5: true},
+	}
+
+	// resolves clusters by name like the cluster check middleware does
+	clusterIDs := map[string]uint{
+		"my-cluster":    5,
+		"other-cluster": 6,
+	}
+
+	tests := map[string]struct {
+		path         string
+		expectedCode int
+	}{
+		"cluster": {
+			path:         "/clusters/my-cluster",
+			expectedCode: http.StatusOK,
+		},
+		"cluster forbidden": {
+			path:         "/clusters/other-cluster",
+			expectedCode: http.StatusForbidden,
+		},
+		"unresolved cluster": {
+			path:         "/clusters/unknown",
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.New()
+
+			clusters := router.Group("/clusters/:name")
+			clusters.Use(func(c *gin.Context) {
+				if clusterID, ok := clusterIDs[c.Param("name")]; ok {
+					c.Request = c.Request.WithContext(ctxutil.WithClusterID(c.Request.Context(), clusterID))
+				}
+			})
+			clusters.Use(NewClusterMiddleware(e, emperror.NewNoopHandler()))
+			clusters.GET("", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, test.path, nil)
+
+			ctx := context.WithValue(context.Background(), qorauth.CurrentUser, &auth.User{ID: 1})
+
+			router.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, w.Code)
+		})
+	}
+}
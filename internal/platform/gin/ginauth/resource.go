@@ -0,0 +1,183 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginauth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+)
+
+// ResourceEnforcer checks if a user (and the token it authenticated with) has access to a single resource.
+type ResourceEnforcer interface {
+	Enforce(ctx context.Context, organizationID uint, userID uint, tokenID string, resource brn.ResourceName, verb string) (bool, error)
+}
+
+// ResourceResolver returns the resource a request refers to and the verb requested on it.
+// The last return value is false when the request does not refer to a single resource.
+type ResourceResolver func(c *gin.Context, organizationID uint) (brn.ResourceName, string, bool)
+
+// NewResourceMiddleware returns a new gin middleware that checks access to the resource a request refers to.
+func NewResourceMiddleware(e ResourceEnforcer, resolver ResourceResolver, errorHandler emperror.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		org := auth.GetCurrentOrganization(c.Request)
+		user := auth.GetCurrentUser(c.Request)
+
+		if org == nil || user == nil {
+			c.AbortWithStatus(http.StatusForbidden)
+
+			return
+		}
+
+		resource, verb, ok := resolver(c, org.ID)
+		if !ok {
+			return
+		}
+
+		granted, err := e.Enforce(c.Request.Context(), org.ID, user.ID, user.TokenID, resource, verb)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to check resource permissions for request")
+			errorHandler.Handle(err)
+			_ = c.AbortWithError(http.StatusInternalServerError, err)
+		} else if !granted {
+			c.AbortWithStatus(http.StatusForbidden)
+		}
+	}
+}
+
+// ParamResolver resolves a resource from path parameters.
+// Multiple parameters are joined with a slash to form the resource ID.
+func ParamResolver(resourceType string, params ...string) ResourceResolver {
+	return func(c *gin.Context, organizationID uint) (brn.ResourceName, string, bool) {
+		ids := make([]string, 0, len(params))
+		for _, param := range params {
+			id := c.Param(param)
+			if id == "" {
+				return brn.ResourceName{}, "", false
+			}
+
+			ids = append(ids, id)
+		}
+
+		verb := auth.VerbFromMethod(c.Request.Method)
+		if verb == "" {
+			return brn.ResourceName{}, "", false
+		}
+
+		return brn.New(organizationID, resourceType, strings.Join(ids, "/")), verb, true
+	}
+}
+
+// NestedResolver resolves a parent resource (eg. a cluster) from a path parameter.
+// Requests targeting the resource itself require the verb corresponding to the method.
+// Requests targeting nested resources require read access to the parent when they only read,
+// and update access otherwise, since changing a nested resource changes the parent as well.
+func NestedResolver(resourceType string, param string) ResourceResolver {
+	resolver := ParamResolver(resourceType, param)
+
+	return func(c *gin.Context, organizationID uint) (brn.ResourceName, string, bool) {
+		resource, verb, ok := resolver(c, organizationID)
+		if !ok {
+			return resource, verb, ok
+		}
+
+		if !strings.HasSuffix(strings.TrimSuffix(c.Request.URL.Path, "/"), "/"+c.Param(param)) && verb != role.VerbGet {
+			verb = role.VerbUpdate
+		}
+
+		return resource, verb, true
+	}
+}
+
+// ClusterResolver resolves a cluster from a path parameter with the semantics of NestedResolver.
+// The ID of the cluster resolved by a preceding middleware (eg. from a cluster name) takes precedence over the parameter.
+func ClusterResolver(param string) ResourceResolver {
+	resolver := NestedResolver(brn.ClusterResourceType, param)
+
+	return func(c *gin.Context, organizationID uint) (brn.ResourceName, string, bool) {
+		resource, verb, ok := resolver(c, organizationID)
+		if !ok {
+			return resource, verb, ok
+		}
+
+		if clusterID, found := ctxutil.ClusterID(c.Request.Context()); found {
+			resource.ResourceID = strconv.FormatUint(uint64(clusterID), 10)
+		}
+
+		return resource, verb, true
+	}
+}
+
+// ClusterChildResolver resolves a resource of a cluster (eg. a deployment) from the cluster and the resource path parameters.
+// The ID of the cluster resolved by a preceding middleware (eg. from a cluster name) takes precedence over the cluster parameter.
+func ClusterChildResolver(resourceType string, clusterParam string, param string) ResourceResolver {
+	resolver := ParamResolver(resourceType, clusterParam, param)
+
+	return func(c *gin.Context, organizationID uint) (brn.ResourceName, string, bool) {
+		resource, verb, ok := resolver(c, organizationID)
+		if !ok {
+			return resource, verb, ok
+		}
+
+		if clusterID, found := ctxutil.ClusterID(c.Request.Context()); found {
+			resource.ResourceID = strconv.FormatUint(uint64(clusterID), 10) + "/" + c.Param(param)
+		}
+
+		return resource, verb, true
+	}
+}
+
+// VerbResolver overrides the verb resolved by another resolver.
+// It can be used for routes where the method does not reflect the access required (eg. proxies).
+func VerbResolver(resolver ResourceResolver, verb string) ResourceResolver {
+	return func(c *gin.Context, organizationID uint) (brn.ResourceName, string, bool) {
+		resource, _, ok := resolver(c, organizationID)
+
+		return resource, verb, ok
+	}
+}
+
+// ResourceFilterer filters a list of resources to the ones a user (and the token it authenticated with) has access to.
+type ResourceFilterer interface {
+	Filter(ctx context.Context, organizationID uint, userID uint, tokenID string, resources []brn.ResourceName, verb string) ([]brn.ResourceName, error)
+}
+
+// NewResourceFilterMiddleware returns a new gin middleware that makes a resource filter available to listing handlers.
+func NewResourceFilterMiddleware(f ResourceFilterer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		org := auth.GetCurrentOrganization(c.Request)
+		user := auth.GetCurrentUser(c.Request)
+
+		if org == nil || user == nil {
+			return
+		}
+
+		filter := func(ctx context.Context, resources []brn.ResourceName) ([]brn.ResourceName, error) {
+			return f.Filter(ctx, org.ID, user.ID, user.TokenID, resources, role.VerbGet)
+		}
+
+		c.Request = c.Request.WithContext(auth.SetResourceFilter(c.Request.Context(), filter))
+	}
+}
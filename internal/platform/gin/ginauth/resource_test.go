@@ -0,0 +1,212 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"emperror.dev/emperror"
+	"github.com/gin-gonic/gin"
+	qorauth "github.com/qor/auth"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+	"github.com/banzaicloud/pipeline/pkg/ctxutil"
+)
+
+type resourceEnforcerStub struct {
+	granted map[string]bool
+}
+
+func (e resourceEnforcerStub) Enforce(_ context.Context, _ uint, _ uint, _ string, resource brn.ResourceName, verb string) (bool, error) {
+	return e.granted[resource.String()+" "+verb], nil
+}
+
+func TestResourceMiddleware(t *testing.T) {
+	e := resourceEnforcerStub{
+		granted: map[string]bool{
+			"brn:1:cluster:5 get":                  true,
+			"brn:1:cluster:5 update":               true,
+			"brn:1:cluster:6 delete":               true,
+			"brn:1:cluster:7 get":                  true,
+			"brn:1:deployment:5/my-release delete": true,
+		},
+	}
+
+	tests := map[string]struct {
+		method       string
+		path         string
+		expectedCode int
+	}{
+		"cluster": {
+			method:       http.MethodDelete,
+			path:         "/clusters/6",
+			expectedCode: http.StatusOK,
+		},
+		"cluster forbidden": {
+			method:       http.MethodDelete,
+			path:         "/clusters/5",
+			expectedCode: http.StatusForbidden,
+		},
+		"nested resource": {
+			method:       http.MethodDelete,
+			path:         "/clusters/5/deployments/my-release",
+			expectedCode: http.StatusOK,
+		},
+		"nested resource forbidden": {
+			method:       http.MethodDelete,
+			path:         "/clusters/5/deployments/other-release",
+			expectedCode: http.StatusForbidden,
+		},
+		"nested read on read-only cluster": {
+			method:       http.MethodGet,
+			path:         "/clusters/7/secrets",
+			expectedCode: http.StatusOK,
+		},
+		"nested write on read-only cluster": {
+			method:       http.MethodPost,
+			path:         "/clusters/7/secrets",
+			expectedCode: http.StatusForbidden,
+		},
+		"proxy": {
+			method:       http.MethodGet,
+			path:         "/clusters/5/proxy/api/v1/pods",
+			expectedCode: http.StatusOK,
+		},
+		"proxy on read-only cluster": {
+			method:       http.MethodGet,
+			path:         "/clusters/7/proxy/api/v1/pods",
+			expectedCode: http.StatusForbidden,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.New()
+
+			clusters := router.Group("/clusters/:id")
+			clusters.Use(NewResourceMiddleware(e, NestedResolver(brn.ClusterResourceType, "id"), emperror.NewNoopHandler()))
+
+			handler := func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			}
+
+			clusters.DELETE("", handler)
+			clusters.DELETE(
+				"/deployments/:name",
+				NewResourceMiddleware(e, ParamResolver(brn.DeploymentResourceType, "id", "name"), emperror.NewNoopHandler()),
+				handler,
+			)
+			clusters.GET("/secrets", handler)
+			clusters.POST("/secrets", handler)
+			clusters.Any(
+				"/proxy/*path",
+				NewResourceMiddleware(e, VerbResolver(ParamResolver(brn.ClusterResourceType, "id"), role.VerbUpdate), emperror.NewNoopHandler()),
+				handler,
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(test.method, test.path, nil)
+
+			ctx := context.WithValue(context.Background(), qorauth.CurrentUser, &auth.User{ID: 1})
+			ctx = context.WithValue(ctx, auth.CurrentOrganization, &auth.Organization{ID: 1})
+
+			router.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, w.Code)
+		})
+	}
+}
+
+func TestClusterResolver(t *testing.T) {
+	e := resourceEnforcerStub{
+		granted: map[string]bool{
+			"brn:1:cluster:5 get":                  true,
+			"brn:1:cluster:5 update":               true,
+			"brn:1:deployment:5/my-release delete": true,
+		},
+	}
+
+	tests := map[string]struct {
+		method       string
+		path         string
+		expectedCode int
+	}{
+		"cluster by name": {
+			method:       http.MethodGet,
+			path:         "/clusters/my-cluster?field=name",
+			expectedCode: http.StatusOK,
+		},
+		"cluster by name forbidden": {
+			method:       http.MethodGet,
+			path:         "/clusters/other-cluster?field=name",
+			expectedCode: http.StatusForbidden,
+		},
+		"deployment of cluster by name": {
+			method:       http.MethodDelete,
+			path:         "/clusters/my-cluster/deployments/my-release?field=name",
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	clusterIDs := map[string]uint{
+		"my-cluster":    5,
+		"other-cluster": 6,
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.New()
+
+			clusters := router.Group("/clusters/:id")
+			clusters.Use(func(c *gin.Context) {
+				c.Request = c.Request.WithContext(ctxutil.WithClusterID(c.Request.Context(), clusterIDs[c.Param("id")]))
+			})
+			clusters.Use(NewResourceMiddleware(e, ClusterResolver("id"), emperror.NewNoopHandler()))
+
+			handler := func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			}
+
+			clusters.GET("", handler)
+			clusters.DELETE(
+				"/deployments/:name",
+				NewResourceMiddleware(e, ClusterChildResolver(brn.DeploymentResourceType, "id", "name"), emperror.NewNoopHandler()),
+				handler,
+			)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(test.method, test.path, nil)
+
+			ctx := context.WithValue(context.Background(), qorauth.CurrentUser, &auth.User{ID: 1})
+			ctx = context.WithValue(ctx, auth.CurrentOrganization, &auth.Organization{ID: 1})
+
+			router.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, w.Code)
+		})
+	}
+}
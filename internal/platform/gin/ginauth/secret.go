@@ -0,0 +1,76 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginauth
+
+import (
+	"fmt"
+	"net/http"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/app/pipeline/auth/role"
+	"github.com/banzaicloud/pipeline/pkg/brn"
+)
+
+// SecretAuthorizer checks if the current user has read access to a secret referenced by a request
+// (eg. in a request body) instead of the request path.
+// It makes the same decision as the middlewares protecting the GET /api/v1/orgs/:orgid/secrets/:id route.
+type SecretAuthorizer struct {
+	resourceEnforcer ResourceEnforcer
+	enforcers        []Enforcer
+}
+
+// NewSecretAuthorizer returns a new SecretAuthorizer.
+// The enforcers (eg. roles and token scopes) are checked against the path of the secret.
+func NewSecretAuthorizer(resourceEnforcer ResourceEnforcer, enforcers ...Enforcer) SecretAuthorizer {
+	return SecretAuthorizer{
+		resourceEnforcer: resourceEnforcer,
+		enforcers:        enforcers,
+	}
+}
+
+// AuthorizeSecret returns true if the current user may read the secret.
+func (a SecretAuthorizer) AuthorizeSecret(r *http.Request, secretID string) (bool, error) {
+	org := auth.GetCurrentOrganization(r)
+	user := auth.GetCurrentUser(r)
+
+	if org == nil || user == nil {
+		return false, nil
+	}
+
+	path := fmt.Sprintf("/api/v1/orgs/%d/secrets/%s", org.ID, secretID)
+
+	for _, enforcer := range a.enforcers {
+		granted, err := enforcer.Enforce(org, user, path, http.MethodGet)
+		if err != nil || !granted {
+			return false, errors.WithMessage(err, "failed to check secret permissions")
+		}
+	}
+
+	granted, err := a.resourceEnforcer.Enforce(
+		r.Context(),
+		org.ID,
+		user.ID,
+		user.TokenID,
+		brn.New(org.ID, brn.SecretResourceType, secretID),
+		role.VerbGet,
+	)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to check secret resource permissions")
+	}
+
+	return granted, nil
+}
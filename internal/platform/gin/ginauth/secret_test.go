@@ -0,0 +1,67 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ginauth
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	qorauth "github.com/qor/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/auth"
+)
+
+type pathEnforcerStub map[string]bool
+
+func (e pathEnforcerStub) Enforce(_ *auth.Organization, _ *auth.User, path, method string) (bool, error) {
+	return e[method+" "+path], nil
+}
+
+func TestSecretAuthorizer(t *testing.T) {
+	roles := pathEnforcerStub{
+		"GET /api/v1/orgs/1/secrets/allowed":    true,
+		"GET /api/v1/orgs/1/secrets/restricted": true,
+	}
+	policies := resourceEnforcerStub{
+		granted: map[string]bool{
+			"brn:1:secret:allowed get":    true,
+			"brn:1:secret:no-role get":    true,
+			"brn:1:secret:restricted get": false,
+		},
+	}
+
+	authorizer := NewSecretAuthorizer(policies, roles)
+
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/orgs/1/clusters/5/deployments", nil)
+	ctx := context.WithValue(context.Background(), qorauth.CurrentUser, &auth.User{ID: 1})
+	ctx = context.WithValue(ctx, auth.CurrentOrganization, &auth.Organization{ID: 1})
+	req = req.WithContext(ctx)
+
+	tests := map[string]bool{
+		"allowed":    true,
+		"no-role":    false,
+		"restricted": false,
+	}
+
+	for secretID, expected := range tests {
+		granted, err := authorizer.AuthorizeSecret(req, secretID)
+		require.NoError(t, err)
+
+		assert.Equal(t, expected, granted, secretID)
+	}
+}
@@ -27,6 +27,20 @@ import (
 	"github.com/banzaicloud/pipeline/secret"
 )
 
+// Kubernetes secrets installed from a Pipeline secret as is are marked with the ID of their source,
+// so that they can be found (and updated) when the source secret changes.
+const (
+	// SourceLabel marks Kubernetes secrets installed from a Pipeline secret.
+	SourceLabel = "secret.banzaicloud.io/source"
+
+	// SourceLabelValue is the value of SourceLabel.
+	SourceLabelValue = "pipeline"
+
+	// SourceIDAnnotation contains the ID of the Pipeline secret a Kubernetes secret was installed from.
+	// Secret IDs are too long to be label values.
+	SourceIDAnnotation = "secret.banzaicloud.io/source-id"
+)
+
 // KubeSecretRequest contains details for a Kubernetes Secret creation from pipeline secrets.
 type KubeSecretRequest struct {
 	Name      string
@@ -34,6 +48,29 @@ type KubeSecretRequest struct {
 	Type      string
 	Values    map[string]string
 	Spec      KubeSecretSpec
+
+	// SourceID is the ID of the Pipeline secret the values come from.
+	// The Kubernetes secret is only marked with it if the values are installed as is (without a spec).
+	SourceID string
+}
+
+// MarkKubeSecretSource marks a Kubernetes secret as installed from a Pipeline secret.
+func MarkKubeSecretSource(kubeSecret *v1.Secret, sourceID string) {
+	if kubeSecret.Labels == nil {
+		kubeSecret.Labels = make(map[string]string)
+	}
+
+	if kubeSecret.Annotations == nil {
+		kubeSecret.Annotations = make(map[string]string)
+	}
+
+	kubeSecret.Labels[SourceLabel] = SourceLabelValue
+	kubeSecret.Annotations[SourceIDAnnotation] = sourceID
+}
+
+// IsKubeSecretSource checks whether a Kubernetes secret was installed from a Pipeline secret.
+func IsKubeSecretSource(kubeSecret v1.Secret, sourceID string) bool {
+	return kubeSecret.Labels[SourceLabel] == SourceLabelValue && kubeSecret.Annotations[SourceIDAnnotation] == sourceID
 }
 
 type KubeSecretSpec map[string]KubeSecretSpecItem
@@ -73,6 +110,14 @@ func CreateKubeSecret(req KubeSecretRequest) (v1.Secret, error) {
 
 			kubeSecret.StringData[key] = value
 		}
+
+		if err := renderKubeSecretType(&kubeSecret, req); err != nil {
+			return kubeSecret, err
+		}
+
+		if req.SourceID != "" {
+			MarkKubeSecretSource(&kubeSecret, req.SourceID)
+		}
 	} else {
 		for key, specItem := range req.Spec {
 			if specItem.Source != "" { // Map one secret
@@ -118,6 +163,78 @@ func CreateKubeSecret(req KubeSecretRequest) (v1.Secret, error) {
 	return kubeSecret, nil
 }
 
+// renderKubeSecretType converts the values of secret types with a native Kubernetes representation.
+func renderKubeSecretType(kubeSecret *v1.Secret, req KubeSecretRequest) error {
+	switch req.Type {
+	case secrettype.DockerRegistrySecretType:
+		dockerConfig, err := createDockerConfigJSON(req.Values)
+		if err != nil {
+			return err
+		}
+
+		kubeSecret.Type = v1.SecretTypeDockerConfigJson
+		kubeSecret.StringData = map[string]string{
+			v1.DockerConfigJsonKey: string(dockerConfig),
+		}
+
+	case secrettype.GitDeployKeySecretType:
+		kubeSecret.Type = v1.SecretTypeSSHAuth
+		kubeSecret.StringData = map[string]string{
+			v1.SSHAuthPrivateKey:     req.Values[secrettype.PrivateKeyData],
+			secrettype.GitRepository: req.Values[secrettype.GitRepository],
+		}
+
+		if knownHosts := req.Values[secrettype.GitKnownHosts]; knownHosts != "" {
+			kubeSecret.StringData[secrettype.GitKnownHosts] = knownHosts
+		}
+
+	case secrettype.DatabaseSecretType:
+		dsn, err := secrettype.BuildDatabaseDSN(req.Values)
+		if err != nil {
+			return errors.Wrap(err, "could not build database connection string")
+		}
+
+		kubeSecret.StringData[secrettype.DatabaseDSN] = dsn
+	}
+
+	return nil
+}
+
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email,omitempty"`
+	Auth     string `json:"auth"`
+}
+
+// createDockerConfigJSON creates a .dockerconfigjson file from the values of a Docker registry secret.
+func createDockerConfigJSON(values map[string]string) ([]byte, error) {
+	username := values[secrettype.Username]
+	password := values[secrettype.Password]
+
+	config := dockerConfigJSON{
+		Auths: map[string]dockerConfigEntry{
+			values[secrettype.DockerRegistryServer]: {
+				Username: username,
+				Password: password,
+				Email:    values[secrettype.DockerRegistryEmail],
+				Auth:     base64.StdEncoding.EncodeToString([]byte(username + ":" + password)),
+			},
+		},
+	}
+
+	rawConfig, err := json.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal docker config")
+	}
+
+	return rawConfig, nil
+}
+
 type KubeSecretStore struct {
 	secrets SecretStore
 }
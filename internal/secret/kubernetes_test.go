@@ -49,6 +49,28 @@ func TestCreateKubeSecret(t *testing.T) {
 				},
 			},
 		},
+		"secret with source": {
+			v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "secret",
+					Namespace:   "namespace",
+					Labels:      map[string]string{secret.SourceLabel: secret.SourceLabelValue},
+					Annotations: map[string]string{secret.SourceIDAnnotation: "secret-id"},
+				},
+				StringData: map[string]string{
+					"key": "value",
+				},
+			},
+			secret.KubeSecretRequest{
+				Name:      "secret",
+				Namespace: "namespace",
+				Type:      "generic",
+				Values: map[string]string{
+					"key": "value",
+				},
+				SourceID: "secret-id",
+			},
+		},
 		"secret with opaque fields": {
 			v1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
@@ -197,6 +219,81 @@ func TestCreateKubeSecret(t *testing.T) {
 				},
 			},
 		},
+		"docker registry secret": {
+			v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret",
+					Namespace: "namespace",
+				},
+				Type: v1.SecretTypeDockerConfigJson,
+				StringData: map[string]string{
+					".dockerconfigjson": "{\"auths\":{\"my.registry.io\":{\"username\":\"user\",\"password\":\"pass\",\"auth\":\"dXNlcjpwYXNz\"}}}",
+				},
+			},
+			secret.KubeSecretRequest{
+				Name:      "secret",
+				Namespace: "namespace",
+				Type:      "dockerregistry",
+				Values: map[string]string{
+					"server":   "my.registry.io",
+					"username": "user",
+					"password": "pass",
+				},
+			},
+		},
+		"git deploy key secret": {
+			v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret",
+					Namespace: "namespace",
+				},
+				Type: v1.SecretTypeSSHAuth,
+				StringData: map[string]string{
+					"ssh-privatekey": "privatekey",
+					"repository":     "git@github.com:org/repo.git",
+					"known_hosts":    "github.com ssh-rsa AAAA",
+				},
+			},
+			secret.KubeSecretRequest{
+				Name:      "secret",
+				Namespace: "namespace",
+				Type:      "gitdeploykey",
+				Values: map[string]string{
+					"repository":       "git@github.com:org/repo.git",
+					"private_key_data": "privatekey",
+					"public_key_data":  "publickey",
+					"known_hosts":      "github.com ssh-rsa AAAA",
+				},
+			},
+		},
+		"database secret": {
+			v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "secret",
+					Namespace: "namespace",
+				},
+				StringData: map[string]string{
+					"driver":   "postgres",
+					"host":     "db",
+					"database": "app",
+					"username": "user",
+					"password": "pass",
+					"dsn":      "postgres://user:pass@db:5432/app",
+				},
+			},
+			secret.KubeSecretRequest{
+				Name:      "secret",
+				Namespace: "namespace",
+				Type:      "database",
+				Values: map[string]string{
+					"driver":   "postgres",
+					"host":     "db",
+					"database": "app",
+					"username": "user",
+					"password": "pass",
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -0,0 +1,168 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrettype
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+// Database drivers
+const (
+	MySQLDriver    = "mysql"
+	PostgresDriver = "postgres"
+)
+
+// nolint: gochecknoglobals
+var defaultDatabasePorts = map[string]string{
+	MySQLDriver:    "3306",
+	PostgresDriver: "5432",
+}
+
+// allowedDatabaseParams are the connection parameters a database secret can set for each driver.
+// Everything else is rejected: the connection strings are also used by Pipeline itself (eg. when verifying secrets),
+// so parameters giving access to local files (eg. allowAllFiles or sslrootcert) or weakening
+// the authentication (eg. allowCleartextPasswords) cannot be set.
+// A nil list of values allows any value.
+// nolint: gochecknoglobals
+var allowedDatabaseParams = map[string]map[string][]string{
+	MySQLDriver: {
+		"charset":          nil,
+		"collation":        nil,
+		"loc":              nil,
+		"parseTime":        {"true", "false"},
+		"timeout":          nil,
+		"readTimeout":      nil,
+		"writeTimeout":     nil,
+		"tls":              {"true", "false", "skip-verify", "preferred"},
+		"maxAllowedPacket": nil,
+		"rejectReadOnly":   {"true", "false"},
+	},
+	PostgresDriver: {
+		"sslmode":                   {"disable", "allow", "prefer", "require", "verify-ca", "verify-full"},
+		"connect_timeout":           nil,
+		"application_name":          nil,
+		"fallback_application_name": nil,
+		"search_path":               nil,
+		"statement_timeout":         nil,
+	},
+}
+
+// validateDatabaseParams checks that the connection parameters are allowed for a database driver.
+func validateDatabaseParams(driver string, params url.Values) error {
+	allowed := allowedDatabaseParams[driver]
+
+	for name, values := range params {
+		allowedValues, ok := allowed[name]
+		if !ok {
+			return errors.NewWithDetails("database connection parameter is not allowed", "driver", driver, "param", name)
+		}
+
+		if allowedValues == nil {
+			continue
+		}
+
+		for _, value := range values {
+			if !isAllowedDatabaseParamValue(allowedValues, value) {
+				return errors.NewWithDetails(
+					"database connection parameter value is not allowed",
+					"driver", driver,
+					"param", name,
+					"value", value,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isAllowedDatabaseParamValue(allowedValues []string, value string) bool {
+	for _, allowedValue := range allowedValues {
+		if value == allowedValue {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BuildDatabaseDSN builds a connection string from the values of a database secret.
+// The format of the connection string depends on the database driver.
+// Only the connection parameters in allowedDatabaseParams can be set.
+func BuildDatabaseDSN(values map[string]string) (string, error) {
+	driver := values[DatabaseDriver]
+
+	port := values[DatabasePort]
+	if port == "" {
+		port = defaultDatabasePorts[driver]
+	}
+
+	host := net.JoinHostPort(values[DatabaseHost], port)
+
+	params, err := url.ParseQuery(values[DatabaseParams])
+	if err != nil {
+		return "", errors.WrapIf(err, "invalid database connection parameters")
+	}
+
+	if err := validateDatabaseParams(driver, params); err != nil {
+		return "", err
+	}
+
+	switch driver {
+	case MySQLDriver:
+		// the MySQL DSN is not escaped, so the address and the database name must not contain its separators
+		if strings.ContainsAny(host+values[DatabaseName], "/?()@") {
+			return "", errors.New("database host, port and name must not contain any of the / ? ( ) @ characters")
+		}
+
+		var dsn strings.Builder
+
+		dsn.WriteString(values[Username])
+		if password := values[Password]; password != "" {
+			dsn.WriteString(":" + password)
+		}
+
+		dsn.WriteString("@tcp(" + host + ")/" + values[DatabaseName])
+
+		if len(params) > 0 {
+			dsn.WriteString("?" + params.Encode())
+		}
+
+		return dsn.String(), nil
+
+	case PostgresDriver:
+		dsn := url.URL{
+			Scheme:   "postgres",
+			Host:     host,
+			Path:     "/" + values[DatabaseName],
+			RawQuery: params.Encode(),
+		}
+
+		if password, ok := values[Password]; ok && password != "" {
+			dsn.User = url.UserPassword(values[Username], password)
+		} else {
+			dsn.User = url.User(values[Username])
+		}
+
+		return dsn.String(), nil
+
+	default:
+		return "", errors.NewWithDetails("unsupported database driver", "driver", driver)
+	}
+}
@@ -177,6 +177,36 @@ const (
 	PagerDutyIntegrationKey = "integrationKey"
 )
 
+// Docker registry extra keys (+Password keys)
+const (
+	DockerRegistryServer = "server"
+	DockerRegistryEmail  = "email"
+)
+
+// Git deploy key extra keys (+Ssh private and public key)
+const (
+	GitRepository = "repository"
+	GitKnownHosts = "known_hosts"
+)
+
+// Database extra keys (+Password keys)
+const (
+	DatabaseDriver = "driver"
+	DatabaseHost   = "host"
+	DatabasePort   = "port"
+	DatabaseName   = "database"
+	DatabaseParams = "params"
+	DatabaseDSN    = "dsn"
+)
+
+// OAuth client keys
+const (
+	OAuthClientID     = "clientId"
+	OAuthClientSecret = "clientSecret"
+	OAuthTokenURL     = "tokenUrl"
+	OAuthScopes       = "scopes"
+)
+
 const (
 	// GenericSecret represents generic secret types, without schema
 	GenericSecret = "generic"
@@ -204,6 +234,14 @@ const (
 	SlackSecretType = "slack"
 	// PagerDutySecretType as marks secrets as of type "pagerduty"
 	PagerDutySecretType = "pagerduty"
+	// DockerRegistrySecretType marks secrets as of type "dockerregistry"
+	DockerRegistrySecretType = "dockerregistry"
+	// GitDeployKeySecretType marks secrets as of type "gitdeploykey"
+	GitDeployKeySecretType = "gitdeploykey"
+	// DatabaseSecretType marks secrets as of type "database"
+	DatabaseSecretType = "database"
+	// OAuthClientSecretType marks secrets as of type "oauthclient"
+	OAuthClientSecretType = "oauthclient"
 )
 
 // DefaultRules key matching for types
@@ -348,4 +386,39 @@ var DefaultRules = map[string]Meta{
 			{Name: PagerDutyIntegrationKey, Required: true, Opaque: true, Description: "The PagerDuty integration key"},
 		},
 	},
+	DockerRegistrySecretType: {
+		Fields: []FieldMeta{
+			{Name: DockerRegistryServer, Required: true, Description: "Registry server address (eg. https://index.docker.io/v1/ or my.registry.io)"},
+			{Name: Username, Required: true, Description: "Your registry username"},
+			{Name: Password, Required: true, Description: "Your registry password or access token"},
+			{Name: DockerRegistryEmail, Required: false, Description: "Your registry E-mail"},
+		},
+	},
+	GitDeployKeySecretType: {
+		Fields: []FieldMeta{
+			{Name: GitRepository, Required: true, Description: "Git repository the key has access to (eg. git@github.com:org/repo.git)"},
+			{Name: PrivateKeyData, Required: true, Description: "Private key of the deploy key in PEM format"},
+			{Name: PublicKeyData, Required: false, Description: "Public key of the deploy key in authorized_keys format"},
+			{Name: GitKnownHosts, Required: false, Description: "Known hosts entries of the Git server"},
+		},
+	},
+	DatabaseSecretType: {
+		Fields: []FieldMeta{
+			{Name: DatabaseDriver, Required: true, Description: "Database driver (mysql or postgres)"},
+			{Name: DatabaseHost, Required: true, Description: "Database host"},
+			{Name: DatabasePort, Required: false, Description: "Database port (defaults to the driver's default port)"},
+			{Name: DatabaseName, Required: true, Description: "Database name"},
+			{Name: Username, Required: true, Description: "Database user"},
+			{Name: Password, Required: false, Description: "Database password"},
+			{Name: DatabaseParams, Required: false, Description: "Additional connection parameters in URL query format (eg. sslmode=require), only connection and TLS settings are allowed"},
+		},
+	},
+	OAuthClientSecretType: {
+		Fields: []FieldMeta{
+			{Name: OAuthClientID, Required: true, Description: "OAuth client ID"},
+			{Name: OAuthClientSecret, Required: true, Description: "OAuth client secret"},
+			{Name: OAuthTokenURL, Required: false, Description: "Token endpoint of the OAuth provider, used for verifying the client"},
+			{Name: OAuthScopes, Required: false, Description: "Comma separated list of scopes"},
+		},
+	},
 }
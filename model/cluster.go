@@ -46,6 +46,7 @@ const (
 	tableNameKubernetesProperties = "kubernetes_clusters"
 	tableNameEKSSubnets           = "amazon_eks_subnets"
 	tableNameAmazonNodePoolLabels = "amazon_node_pool_labels"
+	tableNameClusterLabels        = "cluster_labels"
 )
 
 // ClusterModel describes the common cluster model
@@ -485,6 +486,21 @@ func (cs *ClusterModel) UpdateSshSecret(sshSecretId string) error {
 	return cs.Save()
 }
 
+// ClusterLabelModel stores the user-defined labels of clusters
+type ClusterLabelModel struct {
+	ID        uint   `gorm:"primary_key"`
+	ClusterID uint   `gorm:"unique_index:idx_cluster_labels_id_name"`
+	Name      string `gorm:"unique_index:idx_cluster_labels_id_name"`
+	Value     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (ClusterLabelModel) TableName() string {
+	return tableNameClusterLabels
+}
+
 // AmazonNodePoolLabelModel stores labels for node pools
 type AmazonNodePoolLabelModel struct {
 	ID         uint   `gorm:"primary_key"`
@@ -37,6 +37,7 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 		&DummyClusterModel{},
 		&KubernetesClusterModel{},
 		&AmazonNodePoolLabelModel{},
+		&ClusterLabelModel{},
 	}
 
 	var tableNames string
@@ -29,9 +29,18 @@ const SchemePrefix = Scheme + ":"
 
 // Resource type constants
 const (
-	SecretResourceType = "secret"
+	SecretResourceType       = "secret"
+	ClusterResourceType      = "cluster"
+	ClusterGroupResourceType = "clustergroup"
+	BucketResourceType       = "bucket"
+	DeploymentResourceType   = "deployment"
+	SpotguideResourceType    = "spotguide"
+	HelmRepoResourceType     = "helmrepo"
 )
 
+// Wildcard matches any value of a BRN component in patterns.
+const Wildcard = "*"
+
 // ErrInvalid is returned when a BRN fails validation checks.
 var ErrInvalid = errors.NewPlain("brn: invalid BRN")
 
@@ -132,3 +141,58 @@ func ParseAs(brn string, resourceType string) (ResourceName, error) {
 
 	return rn, err
 }
+
+// ValidatePattern checks if the supplied string is a valid BRN pattern.
+// A pattern is a BRN where the organization ID, resource type and resource ID components can be wildcards.
+// A resource ID ending with a wildcard matches every resource ID with the preceding prefix.
+func ValidatePattern(pattern string) error {
+	const rnLen = 4
+	components := strings.SplitN(pattern, ":", rnLen)
+
+	if len(components) < rnLen || components[0] != Scheme || components[2] == "" || components[3] == "" {
+		return errors.WithStack(ErrInvalid)
+	}
+
+	if components[1] != "" && components[1] != Wildcard {
+		if _, err := strconv.ParseUint(components[1], 10, 64); err != nil {
+			return errors.Wrap(err, "invalid organization ID")
+		}
+	}
+
+	return nil
+}
+
+// Match checks if a resource name matches a BRN pattern.
+// Invalid patterns never match.
+func Match(pattern string, n ResourceName) bool {
+	if ValidatePattern(pattern) != nil {
+		return false
+	}
+
+	components := strings.SplitN(pattern, ":", 4)
+
+	if orgID := components[1]; orgID != Wildcard && orgID != strconv.FormatUint(uint64(n.OrganizationID), 10) {
+		return false
+	}
+
+	if resourceType := components[2]; resourceType != Wildcard && resourceType != n.ResourceType {
+		return false
+	}
+
+	resourceID := components[3]
+	if strings.HasSuffix(resourceID, Wildcard) {
+		return strings.HasPrefix(n.ResourceID, strings.TrimSuffix(resourceID, Wildcard))
+	}
+
+	return resourceID == n.ResourceID
+}
+
+// PatternResourceType returns the resource type component of a BRN pattern.
+func PatternResourceType(pattern string) string {
+	components := strings.SplitN(pattern, ":", 4)
+	if len(components) < 3 {
+		return ""
+	}
+
+	return components[2]
+}
@@ -135,3 +135,43 @@ func ExampleParse() {
 	// Output:
 	// brn:1:secret:dc460da4ad72c482231e28e688e01f2778a88ce31a08826899d54ef7183998b5
 }
+
+func TestMatch(t *testing.T) {
+	cluster := New(12, ClusterResourceType, "5")
+	deployment := New(12, DeploymentResourceType, "5/my-release")
+
+	tests := []struct {
+		pattern  string
+		resource ResourceName
+		expected bool
+	}{
+		{"brn:12:cluster:5", cluster, true},
+		{"brn:12:cluster:*", cluster, true},
+		{"brn:*:cluster:5", cluster, true},
+		{"brn:12:*:*", cluster, true},
+		{"brn:12:cluster:6", cluster, false},
+		{"brn:13:cluster:*", cluster, false},
+		{"brn:12:secret:*", cluster, false},
+		{"brn:12:deployment:5/*", deployment, true},
+		{"brn:12:deployment:6/*", deployment, false},
+		{"brn:12:cluster", cluster, false},
+		{"brn:x:cluster:*", cluster, false},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.pattern, func(t *testing.T) {
+			assert.Equal(t, test.expected, Match(test.pattern, test.resource))
+		})
+	}
+}
+
+func TestValidatePattern(t *testing.T) {
+	assert.NoError(t, ValidatePattern("brn:12:cluster:*"))
+	assert.NoError(t, ValidatePattern("brn:*:*:*"))
+	assert.Error(t, ValidatePattern("brn:12:cluster"))
+	assert.Error(t, ValidatePattern("brn:12::5"))
+	assert.Error(t, ValidatePattern("arn:12:cluster:5"))
+	assert.Error(t, ValidatePattern("brn:org:cluster:5"))
+}
@@ -17,9 +17,13 @@ package cluster
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"time"
 
+	"emperror.dev/emperror"
+	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/banzaicloud/pipeline/pkg/cluster/ack"
 	"github.com/banzaicloud/pipeline/pkg/cluster/aks"
@@ -172,6 +176,12 @@ type LoggingParam struct {
 	GenTLSForLogging GenTLSForLogging `json:"tls" binding:"required"`
 }
 
+// HelmParam describes the helm posthook params
+type HelmParam struct {
+	// Backend is the Helm backend of the cluster: tiller, embedded-tiller or helm3 (defaults to the helm.backend configuration)
+	Backend string `json:"backend"`
+}
+
 // AnchoreParam describes the anchore posthook params
 type AnchoreParam struct {
 	AllowAll string `json:"allowAll"`
@@ -478,6 +488,33 @@ func (r *UpdateClusterRequest) preValidate() {
 	}
 }
 
+// ClusterLabelsRequest describes a cluster labels update request
+type ClusterLabelsRequest struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// Validate checks whether the cluster labels are valid Kubernetes style labels
+func (r *ClusterLabelsRequest) Validate() error {
+	for name, value := range r.Labels {
+		errs := validation.IsQualifiedName(name)
+		if len(errs) > 0 {
+			return emperror.WrapWith(errors.New(strings.Join(errs, "\n")), "invalid cluster label name", "labelName", name)
+		}
+
+		errs = validation.IsValidLabelValue(value)
+		if len(errs) > 0 {
+			return emperror.WrapWith(errors.New(strings.Join(errs, "\n")), "invalid cluster label value", "labelValue", value)
+		}
+	}
+
+	return nil
+}
+
+// ClusterLabelsResponse describes the user-defined labels of a cluster
+type ClusterLabelsResponse struct {
+	Labels map[string]string `json:"labels"`
+}
+
 // ClusterProfileResponse describes Pipeline's ClusterProfile API responses
 type ClusterProfileResponse struct {
 	Name       string                    `json:"name" binding:"required"`
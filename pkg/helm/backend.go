@@ -0,0 +1,203 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"fmt"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// Backend describes how Helm releases are managed on a cluster.
+type Backend string
+
+// Helm backends
+const (
+	// TillerBackend manages releases through the Tiller deployed to the cluster.
+	TillerBackend Backend = "tiller"
+
+	// EmbeddedTillerBackend runs the Tiller release engine in Pipeline and stores releases as secrets in the cluster.
+	//
+	// This is an interim backend for clusters that should not run Tiller:
+	// releases are still stored in the Helm 2 format, so the Helm 3 CLI can't read them.
+	// Clusters using it can be migrated to the Helm 3 backend.
+	EmbeddedTillerBackend Backend = "embedded-tiller"
+
+	// Helm3Backend manages releases with the Helm 3 client, which stores releases as secrets in their namespaces.
+	//
+	// Releases of these clusters are managed through the HelmService of Pipeline (cluster features and post hooks);
+	// operations built on the Helm 2 client fail with ErrHelm3Backend.
+	Helm3Backend Backend = "helm3"
+)
+
+const (
+	// BackendConfigMapName is the name of the config map recording the Helm backend of a cluster.
+	BackendConfigMapName = "helm-backend"
+
+	// ReleaseNamespace is the namespace storing the releases of the tiller and embedded tiller backends.
+	ReleaseNamespace = "kube-system"
+
+	backendKey = "backend"
+
+	tillerReleaseSelector = "OWNER=TILLER"
+)
+
+// Validate checks whether the backend is a known one.
+func (b Backend) Validate() error {
+	switch b {
+	case TillerBackend, EmbeddedTillerBackend, Helm3Backend:
+		return nil
+	default:
+		return errors.Errorf("unknown helm backend: %q", b)
+	}
+}
+
+// GetBackend returns the Helm backend of a cluster.
+// Clusters without a recorded backend use Tiller.
+func GetBackend(client kubernetes.Interface) (Backend, error) {
+	configMap, err := client.CoreV1().ConfigMaps(ReleaseNamespace).Get(BackendConfigMapName, metav1.GetOptions{})
+	if k8sapierrors.IsNotFound(err) {
+		return TillerBackend, nil
+	}
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to get helm backend")
+	}
+
+	backend := Backend(configMap.Data[backendKey])
+	if backend == "" {
+		return TillerBackend, nil
+	}
+
+	return backend, backend.Validate()
+}
+
+// SetBackend records the Helm backend of a cluster.
+func SetBackend(client kubernetes.Interface, backend Backend) error {
+	if err := backend.Validate(); err != nil {
+		return err
+	}
+
+	configMaps := client.CoreV1().ConfigMaps(ReleaseNamespace)
+
+	configMap, err := configMaps.Get(BackendConfigMapName, metav1.GetOptions{})
+	if k8sapierrors.IsNotFound(err) {
+		_, err = configMaps.Create(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: BackendConfigMapName,
+			},
+			Data: map[string]string{
+				backendKey: string(backend),
+			},
+		})
+
+		return errors.WrapIf(err, "failed to create helm backend config map")
+	}
+	if err != nil {
+		return errors.WrapIf(err, "failed to get helm backend config map")
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[backendKey] = string(backend)
+
+	_, err = configMaps.Update(configMap)
+
+	return errors.WrapIf(err, "failed to update helm backend config map")
+}
+
+// MigrateTillerReleases copies the releases stored by Tiller to the storage of the embedded tiller backend.
+// Releases already present in the embedded tiller storage are left untouched, so the migration can be repeated.
+// It returns the number of copied release revisions.
+func MigrateTillerReleases(client kubernetes.Interface) (int, error) {
+	releases, err := GetTillerReleases(client, TillerBackend)
+	if err != nil {
+		return 0, err
+	}
+
+	target := driver.NewSecrets(client.CoreV1().Secrets(ReleaseNamespace))
+
+	var migrated int
+
+	for _, release := range releases {
+		key := releaseKey(release.GetName(), release.GetVersion())
+
+		if _, err := target.Get(key); err == nil {
+			continue
+		}
+
+		if err := target.Create(key, release); err != nil {
+			return migrated, errors.WrapIfWithDetails(err, "failed to migrate release", "release", release.GetName(), "version", release.GetVersion())
+		}
+
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// GetTillerReleases returns every revision of the Helm 2 releases stored by the tiller or the embedded tiller backend.
+func GetTillerReleases(client kubernetes.Interface, backend Backend) ([]*release.Release, error) {
+	var storage driver.Driver
+	var count int
+
+	switch backend {
+	case TillerBackend:
+		configMaps := client.CoreV1().ConfigMaps(ReleaseNamespace)
+
+		list, err := configMaps.List(metav1.ListOptions{LabelSelector: tillerReleaseSelector})
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to list tiller releases")
+		}
+
+		storage, count = driver.NewConfigMaps(configMaps), len(list.Items)
+
+	case EmbeddedTillerBackend:
+		secrets := client.CoreV1().Secrets(ReleaseNamespace)
+
+		list, err := secrets.List(metav1.ListOptions{LabelSelector: tillerReleaseSelector})
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to list embedded tiller releases")
+		}
+
+		storage, count = driver.NewSecrets(secrets), len(list.Items)
+
+	default:
+		return nil, errors.Errorf("%s backend does not store helm 2 releases", backend)
+	}
+
+	// the drivers report an empty storage as an error
+	if count == 0 {
+		return nil, nil
+	}
+
+	releases, err := storage.Query(map[string]string{"OWNER": "TILLER"})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to read tiller releases")
+	}
+
+	return releases, nil
+}
+
+// releaseKey returns the storage key of a release revision (same as the one used by Tiller).
+func releaseKey(name string, version int32) string {
+	return fmt.Sprintf("%s.v%d", name, version)
+}
@@ -0,0 +1,85 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestGetBackend_Default(t *testing.T) {
+	backend, err := GetBackend(fake.NewSimpleClientset())
+	require.NoError(t, err)
+
+	assert.Equal(t, TillerBackend, backend)
+}
+
+func TestSetBackend(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	require.NoError(t, SetBackend(client, EmbeddedTillerBackend))
+
+	backend, err := GetBackend(client)
+	require.NoError(t, err)
+	assert.Equal(t, EmbeddedTillerBackend, backend)
+
+	require.NoError(t, SetBackend(client, TillerBackend))
+
+	backend, err = GetBackend(client)
+	require.NoError(t, err)
+	assert.Equal(t, TillerBackend, backend)
+
+	assert.Error(t, SetBackend(client, Backend("helm2")))
+}
+
+func TestMigrateTillerReleases(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	configMaps := driver.NewConfigMaps(client.CoreV1().ConfigMaps(ReleaseNamespace))
+	for _, rls := range []*release.Release{
+		{Name: "my-release", Version: 1, Info: &release.Info{Status: &release.Status{Code: release.Status_SUPERSEDED}}},
+		{Name: "my-release", Version: 2, Info: &release.Info{Status: &release.Status{Code: release.Status_DEPLOYED}}},
+		{Name: "other-release", Version: 1, Info: &release.Info{Status: &release.Status{Code: release.Status_DEPLOYED}}},
+	} {
+		require.NoError(t, configMaps.Create(releaseKey(rls.Name, rls.Version), rls))
+	}
+
+	migrated, err := MigrateTillerReleases(client)
+	require.NoError(t, err)
+	assert.Equal(t, 3, migrated)
+
+	secrets := driver.NewSecrets(client.CoreV1().Secrets(ReleaseNamespace))
+
+	rls, err := secrets.Get(releaseKey("my-release", 2))
+	require.NoError(t, err)
+	assert.Equal(t, release.Status_DEPLOYED, rls.GetInfo().GetStatus().GetCode())
+
+	// migrating again does not copy anything
+	migrated, err = MigrateTillerReleases(client)
+	require.NoError(t, err)
+	assert.Equal(t, 0, migrated)
+}
+
+func TestMigrateTillerReleases_NoReleases(t *testing.T) {
+	migrated, err := MigrateTillerReleases(fake.NewSimpleClientset())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, migrated)
+}
@@ -22,15 +22,24 @@ import (
 	"github.com/sirupsen/logrus"
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/helm/portforwarder"
-	"k8s.io/helm/pkg/kube"
 
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 )
 
-// Client encapsulates a Helm Client and a Tunnel for that client to interact with the Tiller pod
+// ErrHelm3Backend is returned when a Helm 2 client is requested for a cluster using the Helm 3 backend.
+var ErrHelm3Backend = errors.New("releases of the cluster are managed by the helm 3 backend, which does not serve helm 2 clients")
+
+// Client encapsulates a Helm Client and the connection of that client to the release server of the cluster:
+// a Tunnel to the Tiller pod or a release server running in the current process (see Backend).
 type Client struct {
-	*kube.Tunnel
 	*helm.Client
+
+	close func()
+}
+
+// Close closes the connection to the release server.
+func (c *Client) Close() {
+	c.close()
 }
 
 func NewClient(kubeConfig []byte, logger logrus.FieldLogger) (*Client, error) {
@@ -44,6 +53,21 @@ func NewClient(kubeConfig []byte, logger logrus.FieldLogger) (*Client, error) {
 		return nil, errors.WithMessage(err, "failed to create kubernetes client for helm client")
 	}
 
+	backend, err := GetBackend(client)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get helm backend")
+	}
+
+	if backend == Helm3Backend {
+		return nil, ErrHelm3Backend
+	}
+
+	if backend == EmbeddedTillerBackend {
+		logger.Debug("create embedded tiller helm client")
+
+		return newEmbeddedTillerClient(kubeConfig, config, client, logger)
+	}
+
 	logger.Debug("create kubernetes tunnel")
 	tillerTunnel, err := portforwarder.New("kube-system", client, config)
 	if err != nil {
@@ -55,5 +79,5 @@ func NewClient(kubeConfig []byte, logger logrus.FieldLogger) (*Client, error) {
 
 	hClient := helm.NewClient(helm.Host(tillerTunnelAddress))
 
-	return &Client{Tunnel: tillerTunnel, Client: hClient}, nil
+	return &Client{Client: hClient, close: tillerTunnel.Close}, nil
 }
@@ -0,0 +1,112 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"net"
+
+	"emperror.dev/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// newEmbeddedTillerClient returns a Helm client served by a release server running in the current process.
+// The release server stores releases as secrets in the cluster, so nothing has to be deployed to the cluster.
+// It is the Helm 2 release server (the same one Tiller runs), so releases keep the Helm 2 storage format.
+func newEmbeddedTillerClient(kubeConfig []byte, config *rest.Config, client kubernetes.Interface, logger logrus.FieldLogger) (*Client, error) {
+	clientConfig, err := clientcmd.NewClientConfigFromBytes(kubeConfig)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to load kubernetes config for helm client")
+	}
+
+	secrets := driver.NewSecrets(client.CoreV1().Secrets(ReleaseNamespace))
+	secrets.Log = logger.Debugf
+
+	kubeClient := kube.New(restClientGetter{config: config, clientConfig: clientConfig})
+	kubeClient.Log = logger.Debugf
+
+	env := environment.New()
+	env.Releases = storage.Init(secrets)
+	env.Releases.Log = logger.Debugf
+	env.KubeClient = kubeClient
+
+	releaseServer := tiller.NewReleaseServer(env, client, false)
+	releaseServer.Log = logger.Debugf
+
+	server := tiller.NewServer()
+	services.RegisterReleaseServiceServer(server, releaseServer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to listen for helm client")
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			logger.WithField("error", err.Error()).Warn("embedded tiller release server stopped")
+		}
+	}()
+
+	logger.WithField("address", listener.Addr().String()).Debug("started embedded tiller release server")
+
+	hClient := helm.NewClient(helm.Host(listener.Addr().String()))
+
+	return &Client{Client: hClient, close: server.Stop}, nil
+}
+
+// restClientGetter provides the Kubernetes clients of the release server from a kubeconfig.
+type restClientGetter struct {
+	config       *rest.Config
+	clientConfig clientcmd.ClientConfig
+}
+
+func (g restClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return g.config, nil
+}
+
+func (g restClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	client, err := discovery.NewDiscoveryClientForConfig(g.config)
+	if err != nil {
+		return nil, err
+	}
+
+	return cached.NewMemCacheClient(client), nil
+}
+
+func (g restClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	client, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(client), client), nil
+}
+
+func (g restClientGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return g.clientConfig
+}
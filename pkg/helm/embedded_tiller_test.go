@@ -0,0 +1,70 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: test
+contexts:
+- context:
+    cluster: test
+    user: test
+  name: test
+current-context: test
+users:
+- name: test
+  user:
+    token: test
+`
+
+func TestEmbeddedTillerClient(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	secrets := driver.NewSecrets(client.CoreV1().Secrets(ReleaseNamespace))
+	require.NoError(t, secrets.Create(releaseKey("my-release", 1), &release.Release{
+		Name:      "my-release",
+		Version:   1,
+		Namespace: "default",
+		Info:      &release.Info{Status: &release.Status{Code: release.Status_DEPLOYED}},
+	}))
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+
+	hClient, err := newEmbeddedTillerClient([]byte(testKubeConfig), &rest.Config{Host: "https://127.0.0.1:6443"}, client, logger)
+	require.NoError(t, err)
+	defer hClient.Close()
+
+	releases, err := hClient.ListReleases()
+	require.NoError(t, err)
+	require.Len(t, releases.GetReleases(), 1)
+
+	assert.Equal(t, "my-release", releases.GetReleases()[0].GetName())
+}
@@ -15,10 +15,12 @@
 package helm
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/technosophos/moniker"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/helm/pkg/repo"
 )
 
 // Stable repository constants
@@ -184,6 +186,83 @@ type GetDeploymentResponse struct {
 	Values       map[string]interface{} `json:"values"`
 }
 
+// DeploymentRevision describes a revision of a helm deployment
+type DeploymentRevision struct {
+	Version      int32     `json:"version"`
+	Status       string    `json:"status"`
+	Chart        string    `json:"chart"`
+	ChartName    string    `json:"chartName"`
+	ChartVersion string    `json:"chartVersion"`
+	AppVersion   string    `json:"appVersion,omitempty"`
+	Description  string    `json:"description"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// GetDeploymentDiffResponse describes the differences between two revisions of a helm deployment
+type GetDeploymentDiffResponse struct {
+	ReleaseName string `json:"releaseName"`
+	FromVersion int32  `json:"fromVersion"`
+	ToVersion   int32  `json:"toVersion"`
+	Manifest    string `json:"manifest"`
+	Values      string `json:"values"`
+}
+
+// RollbackDeploymentRequest describes a helm deployment rollback request
+type RollbackDeploymentRequest struct {
+	Version int32 `json:"version" binding:"required"`
+}
+
+// BackendRequest describes a helm backend change request
+type BackendRequest struct {
+	Backend Backend `json:"backend" binding:"required"`
+}
+
+// BackendResponse describes the helm backend of a cluster
+type BackendResponse struct {
+	Backend Backend `json:"backend"`
+}
+
+// RepositoryRequest describes a helm repository add or modify request
+// Charts in private repositories are accessed with the credentials of the referenced secret.
+type RepositoryRequest struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	SecretID string `json:"secretId,omitempty"`
+}
+
+// Repository describes a helm repository
+type Repository struct {
+	*repo.Entry
+	SecretID string `json:"secretId,omitempty"`
+}
+
+// ChartSchemaRequest describes a chart values schema registration request
+type ChartSchemaRequest struct {
+	Schema json.RawMessage `json:"schema" binding:"required"`
+}
+
+// ChartSchemaResponse describes a values schema registered for a chart
+type ChartSchemaResponse struct {
+	Chart     string          `json:"chart"`
+	Schema    json.RawMessage `json:"schema"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// DeploymentPreviewResponse describes the K8s resources a helm deployment install or upgrade would change
+type DeploymentPreviewResponse struct {
+	ReleaseName string                     `json:"releaseName"`
+	Added       []DeploymentResourceChange `json:"added"`
+	Changed     []DeploymentResourceChange `json:"changed"`
+	Removed     []DeploymentResourceChange `json:"removed"`
+}
+
+// DeploymentResourceChange describes a K8s resource changed by a helm deployment
+type DeploymentResourceChange struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+	Diff string `json:"diff"`
+}
+
 // GetDeploymentResourcesResponse lists the resources of a helm deployment
 type GetDeploymentResourcesResponse struct {
 	DeploymentResources []DeploymentResource `json:"resources"`
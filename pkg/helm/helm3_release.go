@@ -0,0 +1,477 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	corev1 "k8s.io/api/core/v1"
+	k8sapierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// The Helm 3 storage format of releases: every revision of a release is stored in a secret in the namespace of the release.
+// The release is encoded as gzipped and base64 encoded JSON, see helm.sh/helm/v3/pkg/storage/driver.
+const (
+	helm3ReleaseSecretType = "helm.sh/release.v1"
+	helm3ReleaseSecretKey  = "release"
+	helm3ReleaseOwner      = "helm"
+)
+
+// Helm3Release is a release in the Helm 3 storage format (see helm.sh/helm/v3/pkg/release.Release).
+type Helm3Release struct {
+	Name      string                 `json:"name,omitempty"`
+	Info      *Helm3ReleaseInfo      `json:"info,omitempty"`
+	Chart     *Helm3Chart            `json:"chart,omitempty"`
+	Config    map[string]interface{} `json:"config,omitempty"`
+	Manifest  string                 `json:"manifest,omitempty"`
+	Hooks     []*Helm3Hook           `json:"hooks,omitempty"`
+	Version   int                    `json:"version,omitempty"`
+	Namespace string                 `json:"namespace,omitempty"`
+}
+
+// Helm3ReleaseInfo describes a revision of a Helm 3 release.
+type Helm3ReleaseInfo struct {
+	FirstDeployed time.Time `json:"first_deployed,omitempty"`
+	LastDeployed  time.Time `json:"last_deployed,omitempty"`
+	Deleted       time.Time `json:"deleted"`
+	Description   string    `json:"description,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	Notes         string    `json:"notes,omitempty"`
+}
+
+// Helm3Chart is the chart of a Helm 3 release.
+// Helm 3 does not store the subcharts of the released chart.
+type Helm3Chart struct {
+	Metadata  *Helm3ChartMetadata    `json:"metadata"`
+	Templates []*Helm3ChartFile      `json:"templates"`
+	Values    map[string]interface{} `json:"values"`
+	Files     []*Helm3ChartFile      `json:"files"`
+}
+
+// Helm3ChartMetadata is the metadata of a Helm 3 chart.
+type Helm3ChartMetadata struct {
+	Name        string              `json:"name,omitempty"`
+	Home        string              `json:"home,omitempty"`
+	Sources     []string            `json:"sources,omitempty"`
+	Version     string              `json:"version,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Keywords    []string            `json:"keywords,omitempty"`
+	Maintainers []*chart.Maintainer `json:"maintainers,omitempty"`
+	Icon        string              `json:"icon,omitempty"`
+	APIVersion  string              `json:"apiVersion,omitempty"`
+	Condition   string              `json:"condition,omitempty"`
+	Tags        string              `json:"tags,omitempty"`
+	AppVersion  string              `json:"appVersion,omitempty"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+	Annotations map[string]string   `json:"annotations,omitempty"`
+	KubeVersion string              `json:"kubeVersion,omitempty"`
+}
+
+// Helm3ChartFile is a template or a file of a Helm 3 chart.
+type Helm3ChartFile struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+// Helm3Hook is a hook of a Helm 3 release.
+type Helm3Hook struct {
+	Name           string             `json:"name,omitempty"`
+	Kind           string             `json:"kind,omitempty"`
+	Path           string             `json:"path,omitempty"`
+	Manifest       string             `json:"manifest,omitempty"`
+	Events         []string           `json:"events,omitempty"`
+	LastRun        Helm3HookExecution `json:"last_run,omitempty"`
+	Weight         int                `json:"weight,omitempty"`
+	DeletePolicies []string           `json:"delete_policies,omitempty"`
+}
+
+// Helm3HookExecution records the last execution of a Helm 3 hook.
+type Helm3HookExecution struct {
+	StartedAt   time.Time `json:"started_at,omitempty"`
+	CompletedAt time.Time `json:"completed_at,omitempty"`
+	Phase       string    `json:"phase"`
+}
+
+// Helm 3 names of the Helm 2 release statuses
+// nolint: gochecknoglobals
+var helm3Statuses = map[release.Status_Code]string{
+	release.Status_UNKNOWN:          "unknown",
+	release.Status_DEPLOYED:         "deployed",
+	release.Status_DELETED:          "uninstalled",
+	release.Status_SUPERSEDED:       "superseded",
+	release.Status_FAILED:           "failed",
+	release.Status_DELETING:         "uninstalling",
+	release.Status_PENDING_INSTALL:  "pending-install",
+	release.Status_PENDING_UPGRADE:  "pending-upgrade",
+	release.Status_PENDING_ROLLBACK: "pending-rollback",
+}
+
+// Helm 3 names of the Helm 2 hook events (Helm 3 has no test failure and CRD install hooks)
+// nolint: gochecknoglobals
+var helm3HookEvents = map[release.Hook_Event]string{
+	release.Hook_PRE_INSTALL:          "pre-install",
+	release.Hook_POST_INSTALL:         "post-install",
+	release.Hook_PRE_DELETE:           "pre-delete",
+	release.Hook_POST_DELETE:          "post-delete",
+	release.Hook_PRE_UPGRADE:          "pre-upgrade",
+	release.Hook_POST_UPGRADE:         "post-upgrade",
+	release.Hook_PRE_ROLLBACK:         "pre-rollback",
+	release.Hook_POST_ROLLBACK:        "post-rollback",
+	release.Hook_RELEASE_TEST_SUCCESS: "test",
+}
+
+// Helm 3 names of the Helm 2 hook delete policies
+// nolint: gochecknoglobals
+var helm3HookDeletePolicies = map[release.Hook_DeletePolicy]string{
+	release.Hook_SUCCEEDED:            "hook-succeeded",
+	release.Hook_FAILED:               "hook-failed",
+	release.Hook_BEFORE_HOOK_CREATION: "before-hook-creation",
+}
+
+// Helm2Status returns the Helm 2 name of a Helm 3 release status, so that statuses of both backends can be compared.
+func Helm2Status(status string) string {
+	for code, name := range helm3Statuses {
+		if name == status {
+			return code.String()
+		}
+	}
+
+	return release.Status_UNKNOWN.String()
+}
+
+// ConvertToHelm3Release converts a Helm 2 release to the Helm 3 format.
+func ConvertToHelm3Release(rls *release.Release) (*Helm3Release, error) {
+	config, err := chartutil.ReadValues([]byte(rls.GetConfig().GetRaw()))
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to parse release values")
+	}
+
+	helm3Chart, err := convertToHelm3Chart(rls.GetChart())
+	if err != nil {
+		return nil, err
+	}
+
+	status := helm3Statuses[rls.GetInfo().GetStatus().GetCode()]
+
+	helm3Release := &Helm3Release{
+		Name: rls.GetName(),
+		Info: &Helm3ReleaseInfo{
+			FirstDeployed: timestampToTime(rls.GetInfo().GetFirstDeployed()),
+			LastDeployed:  timestampToTime(rls.GetInfo().GetLastDeployed()),
+			Deleted:       timestampToTime(rls.GetInfo().GetDeleted()),
+			Description:   rls.GetInfo().GetDescription(),
+			Status:        status,
+			Notes:         rls.GetInfo().GetStatus().GetNotes(),
+		},
+		Chart:     helm3Chart,
+		Config:    config.AsMap(),
+		Manifest:  rls.GetManifest(),
+		Version:   int(rls.GetVersion()),
+		Namespace: rls.GetNamespace(),
+	}
+
+	for _, hook := range rls.GetHooks() {
+		helm3Hook := &Helm3Hook{
+			Name:     hook.GetName(),
+			Kind:     hook.GetKind(),
+			Path:     hook.GetPath(),
+			Manifest: hook.GetManifest(),
+			Weight:   int(hook.GetWeight()),
+			LastRun: Helm3HookExecution{
+				StartedAt:   timestampToTime(hook.GetLastRun()),
+				CompletedAt: timestampToTime(hook.GetLastRun()),
+				Phase:       "Unknown",
+			},
+		}
+
+		for _, event := range hook.GetEvents() {
+			if name, ok := helm3HookEvents[event]; ok {
+				helm3Hook.Events = append(helm3Hook.Events, name)
+			}
+		}
+
+		for _, policy := range hook.GetDeletePolicies() {
+			helm3Hook.DeletePolicies = append(helm3Hook.DeletePolicies, helm3HookDeletePolicies[policy])
+		}
+
+		// hooks without Helm 3 events would never run again
+		if len(helm3Hook.Events) > 0 {
+			helm3Release.Hooks = append(helm3Release.Hooks, helm3Hook)
+		}
+	}
+
+	return helm3Release, nil
+}
+
+func convertToHelm3Chart(c *chart.Chart) (*Helm3Chart, error) {
+	values, err := chartutil.ReadValues([]byte(c.GetValues().GetRaw()))
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to parse chart values")
+	}
+
+	metadata := c.GetMetadata()
+
+	helm3Chart := &Helm3Chart{
+		Metadata: &Helm3ChartMetadata{
+			Name:        metadata.GetName(),
+			Home:        metadata.GetHome(),
+			Sources:     metadata.GetSources(),
+			Version:     metadata.GetVersion(),
+			Description: metadata.GetDescription(),
+			Keywords:    metadata.GetKeywords(),
+			Maintainers: metadata.GetMaintainers(),
+			Icon:        metadata.GetIcon(),
+			APIVersion:  metadata.GetApiVersion(),
+			Condition:   metadata.GetCondition(),
+			Tags:        metadata.GetTags(),
+			AppVersion:  metadata.GetAppVersion(),
+			Deprecated:  metadata.GetDeprecated(),
+			Annotations: metadata.GetAnnotations(),
+			KubeVersion: metadata.GetKubeVersion(),
+		},
+		Values: values.AsMap(),
+	}
+
+	// Helm 2 charts are Helm 3 charts of the v1 API version
+	if helm3Chart.Metadata.APIVersion == "" {
+		helm3Chart.Metadata.APIVersion = "v1"
+	}
+
+	for _, template := range c.GetTemplates() {
+		helm3Chart.Templates = append(helm3Chart.Templates, &Helm3ChartFile{Name: template.GetName(), Data: template.GetData()})
+	}
+
+	for _, file := range c.GetFiles() {
+		helm3Chart.Files = append(helm3Chart.Files, &Helm3ChartFile{Name: file.GetTypeUrl(), Data: file.GetValue()})
+	}
+
+	return helm3Chart, nil
+}
+
+func timestampToTime(ts *timestamp.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return time.Unix(ts.GetSeconds(), int64(ts.GetNanos())).UTC()
+}
+
+// Helm3ReleaseSecretName returns the name of the secret storing a revision of a Helm 3 release.
+func Helm3ReleaseSecretName(name string, version int) string {
+	return fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, version)
+}
+
+// NewHelm3ReleaseSecret returns the secret storing a revision of a Helm 3 release.
+func NewHelm3ReleaseSecret(rls *Helm3Release) (*corev1.Secret, error) {
+	data, err := json.Marshal(rls)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to encode release")
+	}
+
+	var buf bytes.Buffer
+
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to compress release")
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, errors.WrapIf(err, "failed to compress release")
+	}
+	if err := w.Close(); err != nil {
+		return nil, errors.WrapIf(err, "failed to compress release")
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Helm3ReleaseSecretName(rls.Name, rls.Version),
+			Namespace: rls.Namespace,
+			Labels: map[string]string{
+				"name":    rls.Name,
+				"owner":   helm3ReleaseOwner,
+				"status":  rls.Info.Status,
+				"version": strconv.Itoa(rls.Version),
+			},
+		},
+		Type: helm3ReleaseSecretType,
+		Data: map[string][]byte{
+			helm3ReleaseSecretKey: []byte(base64.StdEncoding.EncodeToString(buf.Bytes())),
+		},
+	}, nil
+}
+
+// DecodeHelm3ReleaseSecret returns the Helm 3 release revision stored in a secret.
+func DecodeHelm3ReleaseSecret(secret *corev1.Secret) (*Helm3Release, error) {
+	data, err := base64.StdEncoding.DecodeString(string(secret.Data[helm3ReleaseSecretKey]))
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to decode release", "secret", secret.Name)
+	}
+
+	// releases are gzipped by Helm 3, but uncompressed ones are accepted as well
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b, 0x08}) {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to decompress release", "secret", secret.Name)
+		}
+
+		data, err = ioutil.ReadAll(r)
+		if err != nil {
+			return nil, errors.WrapIfWithDetails(err, "failed to decompress release", "secret", secret.Name)
+		}
+	}
+
+	var rls Helm3Release
+	if err := json.Unmarshal(data, &rls); err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to decode release", "secret", secret.Name)
+	}
+
+	return &rls, nil
+}
+
+// GetHelm3Release returns the latest revision of a Helm 3 release stored in any namespace of a cluster.
+// It returns nil if the release is not found.
+func GetHelm3Release(client kubernetes.Interface, name string) (*Helm3Release, error) {
+	return getHelm3Release(client, fmt.Sprintf("owner=%s,name=%s", helm3ReleaseOwner, name), name)
+}
+
+// GetHelm3ReleaseRevision returns a revision of a Helm 3 release stored in any namespace of a cluster.
+// It returns nil if the revision is not found.
+func GetHelm3ReleaseRevision(client kubernetes.Interface, name string, version int) (*Helm3Release, error) {
+	return getHelm3Release(client, fmt.Sprintf("owner=%s,name=%s,version=%d", helm3ReleaseOwner, name, version), name)
+}
+
+func getHelm3Release(client kubernetes.Interface, selector string, name string) (*Helm3Release, error) {
+	secrets, err := listHelm3ReleaseSecrets(client, selector)
+	if err != nil {
+		return nil, errors.WithDetails(err, "release", name)
+	}
+
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+
+	return DecodeHelm3ReleaseSecret(&secrets[0])
+}
+
+// GetHelm3ReleaseHistory returns the revisions of a Helm 3 release stored in any namespace of a cluster (latest first).
+func GetHelm3ReleaseHistory(client kubernetes.Interface, name string) ([]*Helm3Release, error) {
+	secrets, err := listHelm3ReleaseSecrets(client, fmt.Sprintf("owner=%s,name=%s", helm3ReleaseOwner, name))
+	if err != nil {
+		return nil, errors.WithDetails(err, "release", name)
+	}
+
+	return decodeHelm3ReleaseSecrets(secrets)
+}
+
+// ListHelm3Releases returns the latest revision of every Helm 3 release stored in a cluster.
+func ListHelm3Releases(client kubernetes.Interface) ([]*Helm3Release, error) {
+	secrets, err := listHelm3ReleaseSecrets(client, fmt.Sprintf("owner=%s", helm3ReleaseOwner))
+	if err != nil {
+		return nil, err
+	}
+
+	// the secrets are sorted by version, so the first one of a release is the latest revision
+	latest := make([]corev1.Secret, 0, len(secrets))
+	seen := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		key := secret.Namespace + "/" + secret.Labels["name"]
+		if !seen[key] {
+			seen[key] = true
+			latest = append(latest, secret)
+		}
+	}
+
+	return decodeHelm3ReleaseSecrets(latest)
+}
+
+// listHelm3ReleaseSecrets returns the release secrets matching a label selector in every namespace of a cluster,
+// sorted by the version of the release (latest first).
+func listHelm3ReleaseSecrets(client kubernetes.Interface, selector string) ([]corev1.Secret, error) {
+	list, err := client.CoreV1().Secrets(metav1.NamespaceAll).List(metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to list helm 3 releases")
+	}
+
+	secrets := list.Items
+	sort.SliceStable(secrets, func(i, j int) bool {
+		vi, _ := strconv.Atoi(secrets[i].Labels["version"])
+		vj, _ := strconv.Atoi(secrets[j].Labels["version"])
+
+		return vi > vj
+	})
+
+	return secrets, nil
+}
+
+func decodeHelm3ReleaseSecrets(secrets []corev1.Secret) ([]*Helm3Release, error) {
+	releases := make([]*Helm3Release, 0, len(secrets))
+
+	for i := range secrets {
+		rls, err := DecodeHelm3ReleaseSecret(&secrets[i])
+		if err != nil {
+			return nil, err
+		}
+
+		releases = append(releases, rls)
+	}
+
+	return releases, nil
+}
+
+// MigrateToHelm3Releases converts the releases stored by Tiller (or the embedded tiller backend) to Helm 3 releases.
+// Helm 3 stores the revisions of a release in its namespace.
+// Revisions already present in the Helm 3 storage are left untouched, so the migration can be repeated.
+// It returns the number of converted release revisions.
+func MigrateToHelm3Releases(client kubernetes.Interface, releases []*release.Release) (int, error) {
+	var migrated int
+
+	for _, rls := range releases {
+		helm3Release, err := ConvertToHelm3Release(rls)
+		if err != nil {
+			return migrated, errors.WithDetails(err, "release", rls.GetName(), "version", rls.GetVersion())
+		}
+
+		secret, err := NewHelm3ReleaseSecret(helm3Release)
+		if err != nil {
+			return migrated, errors.WithDetails(err, "release", rls.GetName(), "version", rls.GetVersion())
+		}
+
+		_, err = client.CoreV1().Secrets(secret.Namespace).Create(secret)
+		if k8sapierrors.IsAlreadyExists(err) {
+			continue
+		}
+		if err != nil {
+			return migrated, errors.WrapIfWithDetails(err, "failed to store helm 3 release", "release", rls.GetName(), "version", rls.GetVersion())
+		}
+
+		migrated++
+	}
+
+	return migrated, nil
+}
@@ -0,0 +1,163 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func newTestHelm2Release(name string, version int32, status release.Status_Code) *release.Release {
+	return &release.Release{
+		Name:      name,
+		Namespace: "my-namespace",
+		Version:   version,
+		Info: &release.Info{
+			Status:        &release.Status{Code: status, Notes: "notes"},
+			FirstDeployed: &timestamp.Timestamp{Seconds: 1000},
+			LastDeployed:  &timestamp.Timestamp{Seconds: 2000},
+			Description:   "Install complete",
+		},
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "my-chart", Version: "1.0.0"},
+			Templates: []*chart.Template{{Name: "templates/service.yaml", Data: []byte("kind: Service")}},
+			Values:    &chart.Config{Raw: "replicaCount: 1\n"},
+		},
+		Config:   &chart.Config{Raw: "replicaCount: 2\n"},
+		Manifest: "kind: Service",
+		Hooks: []*release.Hook{
+			{
+				Name:           "my-hook",
+				Kind:           "Job",
+				Events:         []release.Hook_Event{release.Hook_PRE_INSTALL, release.Hook_RELEASE_TEST_FAILURE},
+				DeletePolicies: []release.Hook_DeletePolicy{release.Hook_SUCCEEDED},
+			},
+		},
+	}
+}
+
+func TestConvertToHelm3Release(t *testing.T) {
+	rls, err := ConvertToHelm3Release(newTestHelm2Release("my-release", 3, release.Status_DEPLOYED))
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-release", rls.Name)
+	assert.Equal(t, "my-namespace", rls.Namespace)
+	assert.Equal(t, 3, rls.Version)
+	assert.Equal(t, "deployed", rls.Info.Status)
+	assert.Equal(t, "notes", rls.Info.Notes)
+	assert.Equal(t, int64(2000), rls.Info.LastDeployed.Unix())
+	assert.Equal(t, "my-chart", rls.Chart.Metadata.Name)
+	assert.Equal(t, "v1", rls.Chart.Metadata.APIVersion)
+	assert.Equal(t, map[string]interface{}{"replicaCount": float64(1)}, rls.Chart.Values)
+	assert.Equal(t, map[string]interface{}{"replicaCount": float64(2)}, rls.Config)
+	require.Len(t, rls.Hooks, 1)
+	assert.Equal(t, []string{"pre-install"}, rls.Hooks[0].Events)
+	assert.Equal(t, []string{"hook-succeeded"}, rls.Hooks[0].DeletePolicies)
+}
+
+func TestHelm2Status(t *testing.T) {
+	assert.Equal(t, "DEPLOYED", Helm2Status("deployed"))
+	assert.Equal(t, "DELETED", Helm2Status("uninstalled"))
+	assert.Equal(t, "UNKNOWN", Helm2Status("something"))
+}
+
+func TestHelm3ReleaseSecret(t *testing.T) {
+	rls, err := ConvertToHelm3Release(newTestHelm2Release("my-release", 3, release.Status_DEPLOYED))
+	require.NoError(t, err)
+
+	secret, err := NewHelm3ReleaseSecret(rls)
+	require.NoError(t, err)
+
+	assert.Equal(t, "sh.helm.release.v1.my-release.v3", secret.Name)
+	assert.Equal(t, "my-namespace", secret.Namespace)
+	assert.Equal(t, map[string]string{"name": "my-release", "owner": "helm", "status": "deployed", "version": "3"}, secret.Labels)
+
+	decoded, err := DecodeHelm3ReleaseSecret(secret)
+	require.NoError(t, err)
+
+	assert.Equal(t, rls, decoded)
+}
+
+func TestMigrateToHelm3Releases(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	releases := []*release.Release{
+		newTestHelm2Release("my-release", 1, release.Status_SUPERSEDED),
+		newTestHelm2Release("my-release", 2, release.Status_DEPLOYED),
+		newTestHelm2Release("other-release", 1, release.Status_DEPLOYED),
+	}
+
+	migrated, err := MigrateToHelm3Releases(client, releases)
+	require.NoError(t, err)
+	assert.Equal(t, 3, migrated)
+
+	rls, err := GetHelm3Release(client, "my-release")
+	require.NoError(t, err)
+	require.NotNil(t, rls)
+	assert.Equal(t, 2, rls.Version)
+	assert.Equal(t, "deployed", rls.Info.Status)
+
+	// migrating again does not convert anything
+	migrated, err = MigrateToHelm3Releases(client, releases)
+	require.NoError(t, err)
+	assert.Equal(t, 0, migrated)
+}
+
+func TestGetHelm3Release_NotFound(t *testing.T) {
+	rls, err := GetHelm3Release(fake.NewSimpleClientset(), "my-release")
+	require.NoError(t, err)
+	assert.Nil(t, rls)
+}
+
+func TestListHelm3Releases(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	_, err := MigrateToHelm3Releases(client, []*release.Release{
+		newTestHelm2Release("my-release", 1, release.Status_SUPERSEDED),
+		newTestHelm2Release("my-release", 2, release.Status_DEPLOYED),
+		newTestHelm2Release("other-release", 1, release.Status_FAILED),
+	})
+	require.NoError(t, err)
+
+	releases, err := ListHelm3Releases(client)
+	require.NoError(t, err)
+
+	versions := make(map[string]int)
+	for _, rls := range releases {
+		versions[rls.Name] = rls.Version
+	}
+	assert.Equal(t, map[string]int{"my-release": 2, "other-release": 1}, versions)
+
+	history, err := GetHelm3ReleaseHistory(client, "my-release")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, 2, history[0].Version)
+	assert.Equal(t, 1, history[1].Version)
+
+	revision, err := GetHelm3ReleaseRevision(client, "my-release", 1)
+	require.NoError(t, err)
+	require.NotNil(t, revision)
+	assert.Equal(t, "superseded", revision.Info.Status)
+
+	revision, err = GetHelm3ReleaseRevision(client, "my-release", 3)
+	require.NoError(t, err)
+	assert.Nil(t, revision)
+}
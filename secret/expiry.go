@@ -0,0 +1,97 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+// generatedCACommonName is the common name of the CA of TLS certificate chains generated by Pipeline.
+const generatedCACommonName = "Banzai Cloud Generated Root CA"
+
+// certificateValues lists the values holding PEM encoded certificates for each secret type.
+// nolint: gochecknoglobals
+var certificateValues = map[string][]string{
+	secrettype.TLSSecretType: {
+		secrettype.CACert,
+		secrettype.ServerCert,
+		secrettype.ClientCert,
+		secrettype.PeerCert,
+	},
+	secrettype.PKESecretType: {
+		secrettype.CACert,
+		secrettype.KubernetesCACert,
+		secrettype.EtcdCACert,
+		secrettype.FrontProxyCACert,
+	},
+}
+
+// CertificateExpiry returns the earliest expiry time of the certificates stored in a secret.
+// It returns nil if the secret does not hold any certificates.
+func CertificateExpiry(secretType string, values map[string]string) (*time.Time, error) {
+	var expiresAt *time.Time
+
+	for _, key := range certificateValues[secretType] {
+		if values[key] == "" {
+			continue
+		}
+
+		cert, err := parseCertificate(values[key])
+		if err != nil {
+			return nil, errors.WithDetails(err, "value", key)
+		}
+
+		if expiresAt == nil || cert.NotAfter.Before(*expiresAt) {
+			notAfter := cert.NotAfter
+			expiresAt = &notAfter
+		}
+	}
+
+	return expiresAt, nil
+}
+
+// IsGeneratedCertificate tells whether a secret holds a TLS certificate chain generated by Pipeline.
+func IsGeneratedCertificate(secretType string, values map[string]string) bool {
+	if secretType != secrettype.TLSSecretType || values[secrettype.CACert] == "" {
+		return false
+	}
+
+	cert, err := parseCertificate(values[secrettype.CACert])
+	if err != nil {
+		return false
+	}
+
+	return cert.Subject.CommonName == generatedCACommonName
+}
+
+func parseCertificate(value string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(value))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse certificate")
+	}
+
+	return cert, nil
+}
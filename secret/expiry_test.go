@@ -0,0 +1,65 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"testing"
+	"time"
+
+	"github.com/banzaicloud/bank-vaults/pkg/sdk/tls"
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+func TestCertificateExpiry(t *testing.T) {
+	cc, err := tls.GenerateTLS("example.org", "24h")
+	require.NoError(t, err)
+
+	values := map[string]string{
+		secrettype.TLSHosts: "example.org",
+	}
+
+	err = mapstructure.Decode(cc, &values)
+	require.NoError(t, err)
+
+	expiresAt, err := CertificateExpiry(secrettype.TLSSecretType, values)
+	require.NoError(t, err)
+	require.NotNil(t, expiresAt)
+
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), *expiresAt, time.Minute)
+	assert.True(t, IsGeneratedCertificate(secrettype.TLSSecretType, values))
+}
+
+func TestCertificateExpiry_NoCertificates(t *testing.T) {
+	expiresAt, err := CertificateExpiry(secrettype.PasswordSecretType, map[string]string{
+		secrettype.Username: "user",
+		secrettype.Password: "pass",
+	})
+	require.NoError(t, err)
+
+	assert.Nil(t, expiresAt)
+	assert.False(t, IsGeneratedCertificate(secrettype.PasswordSecretType, nil))
+}
+
+func TestCertificateExpiry_InvalidCertificate(t *testing.T) {
+	_, err := CertificateExpiry(secrettype.TLSSecretType, map[string]string{
+		secrettype.CACert: "not a certificate",
+	})
+
+	assert.Error(t, err)
+}
@@ -20,4 +20,6 @@ type ListSecretsQuery struct {
 	IDs    []string `form:"ids" json:"ids"`
 	Tags   []string `form:"tags" json:"tags"`
 	Values bool     `form:"values" json:"values"`
+	// Versions pins secrets (by ID) to a specific version
+	Versions map[string]int `form:"-" json:"versions,omitempty"`
 }
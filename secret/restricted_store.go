@@ -57,6 +57,14 @@ func (s *restrictedSecretStore) Delete(organizationID uint, secretID string) err
 	return s.secretStore.Delete(organizationID, secretID)
 }
 
+func (s *restrictedSecretStore) Rollback(organizationID uint, secretID string, version int, updatedBy string) error {
+	if err := s.checkBlockingTags(organizationID, secretID); err != nil {
+		return err
+	}
+
+	return s.secretStore.Rollback(organizationID, secretID, version, updatedBy)
+}
+
 func (s *restrictedSecretStore) checkBlockingTags(organizationID uint, secretID string) error {
 
 	secretItem, err := s.secretStore.Get(organizationID, secretID)
@@ -102,6 +102,7 @@ type SecretItemResponse struct {
 	Version   int               `json:"version"`
 	UpdatedAt time.Time         `json:"updatedAt"`
 	UpdatedBy string            `json:"updatedBy,omitempty" mapstructure:"updatedBy"`
+	ExpiresAt *time.Time        `json:"expiresAt,omitempty"`
 }
 
 // K8SSourceMeta returns the meta information how to use this secret if installed to K8S
@@ -426,6 +427,13 @@ func parseSecret(secretID string, secret *vaultapi.Secret, values bool) (*Secret
 		return nil, err
 	}
 
+	// Expiry is calculated before the values are hidden
+	expiresAt, err := CertificateExpiry(response.Type, response.Values)
+	if err != nil {
+		log.Warnf("failed to parse certificates of secret %s: %s", secretID, err.Error())
+	}
+	response.ExpiresAt = expiresAt
+
 	if !values {
 		// Clear the values otherwise
 		for k := range response.Values {
@@ -511,7 +519,7 @@ func (ss *secretStore) List(orgid uint, query *ListSecretsQuery) ([]*SecretItemR
 
 	for _, secretID := range secretIDs {
 
-		if secret, err := ss.Logical.Read(secretDataPath(orgid, secretID)); err != nil {
+		if secret, err := ss.readSecret(orgid, secretID, query.Versions[secretID]); err != nil {
 
 			log.Errorf("Error listing secrets: %s", err.Error())
 			return nil, err
@@ -532,6 +540,27 @@ func (ss *secretStore) List(orgid uint, query *ListSecretsQuery) ([]*SecretItemR
 	return responseItems, nil
 }
 
+// readSecret reads the latest or (when version is not zero) a specific version of a secret
+func (ss *secretStore) readSecret(orgid uint, secretID string, version int) (*vaultapi.Secret, error) {
+	path := secretDataPath(orgid, secretID)
+
+	if version == 0 {
+		return ss.Logical.Read(path)
+	}
+
+	secret, err := ss.Logical.ReadWithData(path, map[string][]string{"version": {strconv.Itoa(version)}})
+	if err != nil {
+		return nil, err
+	}
+
+	if secret == nil || secret.Data["data"] == nil {
+		// Deleted and destroyed versions are returned without data
+		return nil, ErrSecretVersionNotExists
+	}
+
+	return secret, nil
+}
+
 func secretData(version int, request *CreateSecretRequest) (map[string]interface{}, error) {
 	valueData := map[string]interface{}{}
 
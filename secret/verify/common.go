@@ -15,6 +15,7 @@
 package verify
 
 import (
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 	oracle "github.com/banzaicloud/pipeline/pkg/providers/oracle/secret"
 )
@@ -40,6 +41,14 @@ func NewVerifier(cloudType string, values map[string]string) Verifier {
 		return oracle.CreateOCISecret(values)
 	case pkgCluster.Kubernetes:
 		return CreateKubeConfigSecretVerifier(values)
+	case secrettype.DockerRegistrySecretType:
+		return CreateDockerRegistrySecretVerifier(values)
+	case secrettype.GitDeployKeySecretType:
+		return CreateGitDeployKeySecretVerifier(values)
+	case secrettype.DatabaseSecretType:
+		return CreateDatabaseSecretVerifier(values)
+	case secrettype.OAuthClientSecretType:
+		return CreateOAuthClientSecretVerifier(values)
 	default:
 		return nil
 	}
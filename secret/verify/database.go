@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"emperror.dev/errors"
+	_ "github.com/go-sql-driver/mysql" // MySQL driver used for verifying database secrets
+	_ "github.com/lib/pq"              // PostgreSQL driver used for verifying database secrets
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+// databaseVerifier checks database credentials by connecting to the database.
+type databaseVerifier struct {
+	values  map[string]string
+	timeout time.Duration
+}
+
+// CreateDatabaseSecretVerifier creates a verifier that checks database credentials
+// by opening a connection to the database.
+func CreateDatabaseSecretVerifier(values map[string]string) *databaseVerifier {
+	return &databaseVerifier{
+		values:  values,
+		timeout: 10 * time.Second,
+	}
+}
+
+// VerifySecret validates database credentials
+func (v *databaseVerifier) VerifySecret() error {
+	dsn, err := secrettype.BuildDatabaseDSN(v.values)
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open(v.values[secrettype.DatabaseDriver], dsn)
+	if err != nil {
+		return errors.WrapIf(err, "failed to open database connection")
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.timeout)
+	defer cancel()
+
+	return errors.WrapIf(db.PingContext(ctx), "couldn't connect to database")
+}
@@ -0,0 +1,163 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+// dockerHubRegistry is the registry API endpoint of Docker Hub.
+const dockerHubRegistry = "https://registry-1.docker.io"
+
+// dockerRegistryVerifier checks Docker registry credentials by logging in to the registry.
+type dockerRegistryVerifier struct {
+	server   string
+	username string
+	password string
+	client   *http.Client
+}
+
+// CreateDockerRegistrySecretVerifier creates a verifier that checks Docker registry credentials
+// by authenticating against the registry API (following token authentication when required).
+func CreateDockerRegistrySecretVerifier(values map[string]string) *dockerRegistryVerifier {
+	return &dockerRegistryVerifier{
+		server:   values[secrettype.DockerRegistryServer],
+		username: values[secrettype.Username],
+		password: values[secrettype.Password],
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// VerifySecret validates Docker registry credentials
+func (v *dockerRegistryVerifier) VerifySecret() error {
+	endpoint, err := registryAPIEndpoint(v.server)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint+"/v2/", nil)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create registry request")
+	}
+	req.SetBasicAuth(v.username, v.password)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return errors.WrapIf(err, "failed to reach registry")
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+
+	case http.StatusUnauthorized:
+		challenge := resp.Header.Get("WWW-Authenticate")
+		if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+			return errors.New("invalid registry credentials")
+		}
+
+		return v.requestToken(challenge)
+
+	default:
+		return errors.NewWithDetails("unexpected registry response", "status", resp.StatusCode)
+	}
+}
+
+// requestToken requests a token from the authorization server of a registry using token authentication.
+func (v *dockerRegistryVerifier) requestToken(challenge string) error {
+	params := parseAuthChallenge(challenge)
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return errors.NewWithDetails("invalid registry authentication challenge", "challenge", challenge)
+	}
+
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create token request")
+	}
+	req.SetBasicAuth(v.username, v.password)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return errors.WrapIf(err, "failed to reach registry authorization server")
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("invalid registry credentials")
+	}
+
+	return nil
+}
+
+// registryAPIEndpoint returns the base URL of the registry API for a registry server address.
+func registryAPIEndpoint(server string) (string, error) {
+	if server == "" {
+		return "", errors.New("no registry server provided")
+	}
+
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+
+	u, err := url.Parse(server)
+	if err != nil {
+		return "", errors.WrapIf(err, "invalid registry server")
+	}
+
+	switch u.Host {
+	case "docker.io", "index.docker.io", "registry-1.docker.io":
+		return dockerHubRegistry, nil
+	}
+
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// parseAuthChallenge parses the parameters of a WWW-Authenticate header.
+// eg. Bearer realm="https://auth.docker.io/token",service="registry.docker.io"
+func parseAuthChallenge(challenge string) map[string]string {
+	params := make(map[string]string)
+
+	parts := strings.SplitN(challenge, " ", 2)
+	if len(parts) < 2 {
+		return params
+	}
+
+	for _, param := range strings.Split(parts[1], ",") {
+		kv := strings.SplitN(strings.TrimSpace(param), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		params[strings.ToLower(kv[0])] = strings.Trim(kv[1], `"`)
+	}
+
+	return params
+}
@@ -0,0 +1,96 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+func TestDockerRegistryVerifier_BasicAuth(t *testing.T) {
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}))
+	defer registry.Close()
+
+	verifier := CreateDockerRegistrySecretVerifier(map[string]string{
+		secrettype.DockerRegistryServer: registry.URL,
+		secrettype.Username:             "user",
+		secrettype.Password:             "pass",
+	})
+	require.NoError(t, verifier.VerifySecret())
+
+	verifier.password = "wrong"
+	require.Error(t, verifier.VerifySecret())
+}
+
+func TestDockerRegistryVerifier_TokenAuth(t *testing.T) {
+	auth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "registry.example.com", r.URL.Query().Get("service"))
+
+		if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"token": "token"}`))
+	}))
+	defer auth.Close()
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="`+auth.URL+`/token",service="registry.example.com"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer registry.Close()
+
+	verifier := CreateDockerRegistrySecretVerifier(map[string]string{
+		secrettype.DockerRegistryServer: registry.URL,
+		secrettype.Username:             "user",
+		secrettype.Password:             "pass",
+	})
+	require.NoError(t, verifier.VerifySecret())
+
+	verifier.password = "wrong"
+	require.Error(t, verifier.VerifySecret())
+}
+
+func TestRegistryAPIEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"https://index.docker.io/v1/": dockerHubRegistry,
+		"docker.io":                   dockerHubRegistry,
+		"my.registry.io":              "https://my.registry.io",
+		"http://localhost:5000/v2/":   "http://localhost:5000",
+	}
+
+	for server, expected := range tests {
+		server, expected := server, expected
+
+		t.Run(server, func(t *testing.T) {
+			endpoint, err := registryAPIEndpoint(server)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected, endpoint)
+		})
+	}
+}
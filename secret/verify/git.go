@@ -0,0 +1,62 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"bytes"
+
+	"emperror.dev/errors"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+// gitDeployKeyVerifier checks that a Git deploy key is a valid key pair.
+type gitDeployKeyVerifier struct {
+	privateKey string
+	publicKey  string
+}
+
+// CreateGitDeployKeySecretVerifier creates a verifier that checks if the private key of a Git deploy key
+// can be parsed and matches the public key (if there is any).
+func CreateGitDeployKeySecretVerifier(values map[string]string) *gitDeployKeyVerifier {
+	return &gitDeployKeyVerifier{
+		privateKey: values[secrettype.PrivateKeyData],
+		publicKey:  values[secrettype.PublicKeyData],
+	}
+}
+
+// VerifySecret validates a Git deploy key
+func (v *gitDeployKeyVerifier) VerifySecret() error {
+	signer, err := ssh.ParsePrivateKey([]byte(v.privateKey))
+	if err != nil {
+		return errors.WrapIf(err, "invalid private key")
+	}
+
+	if v.publicKey == "" {
+		return nil
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(v.publicKey))
+	if err != nil {
+		return errors.WrapIf(err, "invalid public key")
+	}
+
+	if !bytes.Equal(publicKey.Marshal(), signer.PublicKey().Marshal()) {
+		return errors.New("public key does not match the private key")
+	}
+
+	return nil
+}
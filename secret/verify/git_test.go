@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+func generateTestKeyPair(t *testing.T) (privateKey string, publicKey string) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	pub, err := ssh.NewPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	return string(privateKeyPEM), string(ssh.MarshalAuthorizedKey(pub))
+}
+
+func TestGitDeployKeyVerifier(t *testing.T) {
+	privateKey, publicKey := generateTestKeyPair(t)
+	_, otherPublicKey := generateTestKeyPair(t)
+
+	values := map[string]string{
+		secrettype.GitRepository:  "git@github.com:org/repo.git",
+		secrettype.PrivateKeyData: privateKey,
+		secrettype.PublicKeyData:  publicKey,
+	}
+	require.NoError(t, CreateGitDeployKeySecretVerifier(values).VerifySecret())
+
+	values[secrettype.PublicKeyData] = ""
+	require.NoError(t, CreateGitDeployKeySecretVerifier(values).VerifySecret())
+
+	values[secrettype.PublicKeyData] = otherPublicKey
+	require.Error(t, CreateGitDeployKeySecretVerifier(values).VerifySecret())
+
+	values[secrettype.PrivateKeyData] = "invalid"
+	require.Error(t, CreateGitDeployKeySecretVerifier(values).VerifySecret())
+}
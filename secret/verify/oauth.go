@@ -0,0 +1,68 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+// oauthClientVerifier checks OAuth client credentials using the client credentials grant.
+type oauthClientVerifier struct {
+	config  clientcredentials.Config
+	timeout time.Duration
+}
+
+// CreateOAuthClientSecretVerifier creates a verifier that checks OAuth client credentials
+// by requesting a token from the token endpoint of the provider.
+// Credentials without a token endpoint cannot be verified.
+func CreateOAuthClientSecretVerifier(values map[string]string) *oauthClientVerifier {
+	var scopes []string
+	for _, scope := range strings.Split(values[secrettype.OAuthScopes], ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return &oauthClientVerifier{
+		config: clientcredentials.Config{
+			ClientID:     values[secrettype.OAuthClientID],
+			ClientSecret: values[secrettype.OAuthClientSecret],
+			TokenURL:     values[secrettype.OAuthTokenURL],
+			Scopes:       scopes,
+		},
+		timeout: 30 * time.Second,
+	}
+}
+
+// VerifySecret validates OAuth client credentials
+func (v *oauthClientVerifier) VerifySecret() error {
+	if v.config.TokenURL == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.timeout)
+	defer cancel()
+
+	_, err := v.config.Token(ctx)
+
+	return errors.WrapIf(err, "couldn't get token with the OAuth client credentials")
+}
@@ -0,0 +1,58 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+func TestOAuthClientVerifier(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "read write", r.PostForm.Get("scope"))
+
+		if clientID, clientSecret, ok := r.BasicAuth(); !ok || clientID != "client" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "bearer"}`))
+	}))
+	defer provider.Close()
+
+	values := map[string]string{
+		secrettype.OAuthClientID:     "client",
+		secrettype.OAuthClientSecret: "secret",
+		secrettype.OAuthTokenURL:     provider.URL,
+		secrettype.OAuthScopes:       "read, write",
+	}
+	require.NoError(t, CreateOAuthClientSecretVerifier(values).VerifySecret())
+
+	values[secrettype.OAuthClientSecret] = "wrong"
+	require.Error(t, CreateOAuthClientSecretVerifier(values).VerifySecret())
+
+	// Credentials without a token endpoint cannot be verified
+	values[secrettype.OAuthTokenURL] = ""
+	require.NoError(t, CreateOAuthClientSecretVerifier(values).VerifySecret())
+}
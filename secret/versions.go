@@ -0,0 +1,157 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// ErrSecretVersionNotExists denotes 'Not Found' errors for secret versions
+// nolint: gochecknoglobals
+var ErrSecretVersionNotExists = fmt.Errorf("There's no secret version with this number")
+
+// SecretVersion describes a single version of a secret
+type SecretVersion struct {
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"createdAt"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+	Destroyed bool       `json:"destroyed"`
+	Current   bool       `json:"current"`
+}
+
+// ListVersions lists the versions of a secret kept by Vault (newest first)
+func (ss *secretStore) ListVersions(organizationID uint, secretID string) ([]SecretVersion, error) {
+	metadata, err := ss.Logical.Read(secretMetadataPath(organizationID, secretID))
+	if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret metadata")
+	}
+
+	if metadata == nil {
+		return nil, ErrSecretNotExists
+	}
+
+	versions, err := parseSecretVersions(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user who created a version is stored among the secret data
+	for i, version := range versions {
+		if version.DeletedAt != nil || version.Destroyed {
+			continue
+		}
+
+		secret, err := ss.GetVersion(organizationID, secretID, version.Version)
+		if err == ErrSecretVersionNotExists {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		versions[i].CreatedBy = secret.UpdatedBy
+	}
+
+	return versions, nil
+}
+
+// GetVersion retrieves a specific version of a secret
+func (ss *secretStore) GetVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error) {
+	secret, err := ss.readSecret(organizationID, secretID, version)
+	if err == ErrSecretVersionNotExists {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret version")
+	}
+
+	return parseSecret(secretID, secret, true)
+}
+
+// Rollback creates a new version of a secret with the content of a previous version
+func (ss *secretStore) Rollback(organizationID uint, secretID string, version int, updatedBy string) error {
+	current, err := ss.Get(organizationID, secretID)
+	if err != nil {
+		return err
+	}
+
+	previous, err := ss.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		return err
+	}
+
+	request := CreateSecretRequest{
+		Name:      previous.Name,
+		Type:      previous.Type,
+		Values:    previous.Values,
+		Tags:      previous.Tags,
+		Version:   &current.Version,
+		UpdatedBy: updatedBy,
+	}
+
+	return ss.Update(organizationID, secretID, &request)
+}
+
+func parseSecretVersions(metadata *vaultapi.Secret) ([]SecretVersion, error) {
+	currentVersion := cast.ToInt(cast.ToString(metadata.Data["current_version"]))
+
+	rawVersions := cast.ToStringMap(metadata.Data["versions"])
+
+	versions := make([]SecretVersion, 0, len(rawVersions))
+
+	for rawVersion, rawDetails := range rawVersions {
+		number, err := strconv.Atoi(rawVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid secret version: %s", rawVersion)
+		}
+
+		details := cast.ToStringMap(rawDetails)
+
+		createdAt, err := time.Parse(time.RFC3339, cast.ToString(details["created_time"]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid creation time of secret version: %s", rawVersion)
+		}
+
+		version := SecretVersion{
+			Version:   number,
+			CreatedAt: createdAt,
+			Destroyed: cast.ToBool(details["destroyed"]),
+			Current:   number == currentVersion,
+		}
+
+		if deletionTime := cast.ToString(details["deletion_time"]); deletionTime != "" {
+			deletedAt, err := time.Parse(time.RFC3339, deletionTime)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid deletion time of secret version: %s", rawVersion)
+			}
+
+			version.DeletedAt = &deletedAt
+		}
+
+		versions = append(versions, version)
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+
+	return versions, nil
+}
@@ -0,0 +1,236 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/secret/secrettype"
+)
+
+func TestParseSecretVersions(t *testing.T) {
+	createdAt := time.Date(2019, time.November, 8, 10, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2019, time.November, 9, 10, 0, 0, 0, time.UTC)
+
+	versionDetails := func(deletionTime string, destroyed bool) map[string]interface{} {
+		return map[string]interface{}{
+			"created_time":  createdAt.Format(time.RFC3339),
+			"deletion_time": deletionTime,
+			"destroyed":     destroyed,
+		}
+	}
+
+	tests := map[string]struct {
+		metadata map[string]interface{}
+		expected []SecretVersion
+		isError  bool
+	}{
+		"current version": {
+			metadata: map[string]interface{}{
+				"current_version": json.Number("2"),
+				"versions": map[string]interface{}{
+					"1": versionDetails("", false),
+					"2": versionDetails("", false),
+				},
+			},
+			expected: []SecretVersion{
+				{Version: 2, CreatedAt: createdAt, Current: true},
+				{Version: 1, CreatedAt: createdAt},
+			},
+		},
+		"deleted version": {
+			metadata: map[string]interface{}{
+				"current_version": json.Number("2"),
+				"versions": map[string]interface{}{
+					"1": versionDetails(deletedAt.Format(time.RFC3339), false),
+					"2": versionDetails("", false),
+				},
+			},
+			expected: []SecretVersion{
+				{Version: 2, CreatedAt: createdAt, Current: true},
+				{Version: 1, CreatedAt: createdAt, DeletedAt: &deletedAt},
+			},
+		},
+		"destroyed version": {
+			metadata: map[string]interface{}{
+				"current_version": json.Number("2"),
+				"versions": map[string]interface{}{
+					"1": versionDetails("", true),
+					"2": versionDetails("", false),
+				},
+			},
+			expected: []SecretVersion{
+				{Version: 2, CreatedAt: createdAt, Current: true},
+				{Version: 1, CreatedAt: createdAt, Destroyed: true},
+			},
+		},
+		"invalid version number": {
+			metadata: map[string]interface{}{
+				"current_version": json.Number("1"),
+				"versions": map[string]interface{}{
+					"first": versionDetails("", false),
+				},
+			},
+			isError: true,
+		},
+		"invalid creation time": {
+			metadata: map[string]interface{}{
+				"current_version": json.Number("1"),
+				"versions": map[string]interface{}{
+					"1": map[string]interface{}{"created_time": "yesterday"},
+				},
+			},
+			isError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			versions, err := parseSecretVersions(&vaultapi.Secret{Data: test.metadata})
+
+			if test.isError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, versions)
+		})
+	}
+}
+
+func TestSecretStore_Rollback(t *testing.T) {
+	const orgID = 1
+	secretID := GenerateSecretIDFromName("my-secret")
+
+	secretResponse := func(version int, password string) map[string]interface{} {
+		return map[string]interface{}{
+			"data": map[string]interface{}{
+				"data": map[string]interface{}{
+					"value": map[string]interface{}{
+						"name":   "my-secret",
+						"type":   secrettype.PasswordSecretType,
+						"values": map[string]interface{}{secrettype.Password: password},
+					},
+				},
+				"metadata": map[string]interface{}{
+					"version":      version,
+					"created_time": "2019-11-08T10:00:00Z",
+				},
+			},
+		}
+	}
+
+	// Deleted and destroyed versions are returned with metadata only
+	removedResponse := map[string]interface{}{
+		"data": map[string]interface{}{
+			"data": nil,
+			"metadata": map[string]interface{}{
+				"version":      1,
+				"created_time": "2019-11-08T10:00:00Z",
+				"destroyed":    true,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		version       int
+		expectedError error
+		expectedCAS   int
+	}{
+		"previous version": {
+			version:     2,
+			expectedCAS: 3,
+		},
+		"destroyed version": {
+			version:       1,
+			expectedError: ErrSecretVersionNotExists,
+		},
+		"unknown version": {
+			version:       7,
+			expectedError: ErrSecretVersionNotExists,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			var written map[string]interface{}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/"+secretDataPath(orgID, secretID) {
+					w.WriteHeader(http.StatusNotFound)
+
+					return
+				}
+
+				switch r.Method {
+				case http.MethodGet:
+					switch r.URL.Query().Get("version") {
+					case "":
+						_ = json.NewEncoder(w).Encode(secretResponse(3, "current"))
+					case "2":
+						_ = json.NewEncoder(w).Encode(secretResponse(2, "previous"))
+					case "1":
+						w.WriteHeader(http.StatusNotFound)
+						_ = json.NewEncoder(w).Encode(removedResponse)
+					default:
+						w.WriteHeader(http.StatusNotFound)
+					}
+
+				case http.MethodPut, http.MethodPost:
+					_ = json.NewDecoder(r.Body).Decode(&written)
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer server.Close()
+
+			client, err := vaultapi.NewClient(&vaultapi.Config{Address: server.URL})
+			require.NoError(t, err)
+
+			store := &secretStore{Logical: client.Logical()}
+
+			err = store.Rollback(orgID, secretID, test.version, "john")
+
+			if test.expectedError != nil {
+				assert.Equal(t, test.expectedError, err)
+				assert.Nil(t, written, "nothing should be written when the rollback fails")
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, written)
+
+			assert.Equal(t, map[string]interface{}{"cas": float64(test.expectedCAS)}, written["options"])
+
+			value := written["data"].(map[string]interface{})["value"].(map[string]interface{})
+			assert.Equal(t, map[string]interface{}{secrettype.Password: "previous"}, value["values"])
+			assert.Equal(t, "john", value["updatedBy"])
+		})
+	}
+}